@@ -189,6 +189,10 @@ type Config interface {
 	// available) when connecting to the state or API server.
 	PreferIPv6() bool
 
+	// IPv6Only returns whether only IPv6 addresses (and hostnames)
+	// should be used when connecting to the state or API server.
+	IPv6Only() bool
+
 	// Environment returns the tag for the environment that the agent belongs
 	// to.
 	Environment() names.EnvironTag
@@ -308,6 +312,7 @@ type configInternal struct {
 	servingInfo       *params.StateServingInfo
 	values            map[string]string
 	preferIPv6        bool
+	ipv6Only          bool
 }
 
 type AgentConfigParams struct {
@@ -324,6 +329,7 @@ type AgentConfigParams struct {
 	CACert            string
 	Values            map[string]string
 	PreferIPv6        bool
+	IPv6Only          bool
 }
 
 // NewAgentConfig returns a new config object suitable for use for a
@@ -373,6 +379,7 @@ func NewAgentConfig(configParams AgentConfigParams) (ConfigSetterWriter, error)
 		oldPassword:       configParams.Password,
 		values:            configParams.Values,
 		preferIPv6:        configParams.PreferIPv6,
+		ipv6Only:          configParams.IPv6Only,
 	}
 	if len(configParams.StateAddresses) > 0 {
 		config.stateDetails = &connectionDetails{
@@ -625,7 +632,11 @@ func (c *configInternal) Value(key string) string {
 }
 
 func (c *configInternal) PreferIPv6() bool {
-	return c.preferIPv6
+	return c.preferIPv6 || c.ipv6Only
+}
+
+func (c *configInternal) IPv6Only() bool {
+	return c.ipv6Only
 }
 
 func (c *configInternal) StateServingInfo() (params.StateServingInfo, bool) {
@@ -722,7 +733,7 @@ func (c *configInternal) APIInfo() *api.Info {
 	if isStateServer {
 		port := servingInfo.APIPort
 		localAPIAddr := net.JoinHostPort("localhost", strconv.Itoa(port))
-		if c.preferIPv6 {
+		if c.PreferIPv6() {
 			localAPIAddr = net.JoinHostPort("::1", strconv.Itoa(port))
 		}
 		addrInAddrs := false
@@ -752,7 +763,7 @@ func (c *configInternal) MongoInfo() (info *mongo.MongoInfo, ok bool) {
 		return nil, false
 	}
 	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(ssi.StatePort))
-	if c.preferIPv6 {
+	if c.PreferIPv6() {
 		addr = net.JoinHostPort("::1", strconv.Itoa(ssi.StatePort))
 	}
 	return &mongo.MongoInfo{
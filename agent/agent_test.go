@@ -241,6 +241,25 @@ var agentConfigTests = []struct {
 	},
 	inspectConfig: func(c *gc.C, cfg agent.Config) {
 		c.Check(cfg.PreferIPv6(), jc.IsFalse)
+		c.Check(cfg.IPv6Only(), jc.IsFalse)
+	},
+}, {
+	about: "ipv6-only implies prefer-ipv6",
+	params: agent.AgentConfigParams{
+		DataDir:           "/data/dir",
+		Tag:               names.NewMachineTag("1"),
+		Password:          "sekrit",
+		UpgradedToVersion: version.Current.Number,
+		CACert:            "ca cert",
+		Environment:       testing.EnvironmentTag,
+		StateAddresses:    []string{"[2001:db8::1]:1234"},
+		APIAddresses:      []string{"[2001:db8::1]:1235"},
+		Nonce:             "a nonce",
+		IPv6Only:          true,
+	},
+	inspectConfig: func(c *gc.C, cfg agent.Config) {
+		c.Check(cfg.IPv6Only(), jc.IsTrue)
+		c.Check(cfg.PreferIPv6(), jc.IsTrue)
 	},
 }}
 
@@ -596,6 +615,16 @@ func (*suite) TestMongoInfoHonorsPreferIPv6(c *gc.C) {
 	c.Check(mongoInfo.Info.Addrs, jc.DeepEquals, []string{"127.0.0.1:69"})
 }
 
+func (*suite) TestMongoInfoHonorsIPv6Only(c *gc.C) {
+	attrParams := attributeParams
+	attrParams.IPv6Only = true
+	conf, err := agent.NewStateMachineConfig(attrParams, stateServingInfo())
+	c.Assert(err, jc.ErrorIsNil)
+	mongoInfo, ok := conf.MongoInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(mongoInfo.Info.Addrs, jc.DeepEquals, []string{"[::1]:69"})
+}
+
 func (*suite) TestAPIInfoDoesntAddLocalhostWhenNoServingInfoPreferIPv6Off(c *gc.C) {
 	attrParams := attributeParams
 	attrParams.PreferIPv6 = false
@@ -47,6 +47,7 @@ type format_1_18Serialization struct {
 	Values      map[string]string
 
 	PreferIPv6 bool `yaml:"prefer-ipv6,omitempty"`
+	IPv6Only   bool `yaml:"ipv6-only,omitempty"`
 
 	// Only state server machines have these next items set.
 	StateServerCert string `yaml:",omitempty"`
@@ -101,6 +102,7 @@ func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
 		oldPassword:       format.OldPassword,
 		values:            format.Values,
 		preferIPv6:        format.PreferIPv6,
+		ipv6Only:          format.IPv6Only,
 	}
 	if config.logDir == "" {
 		config.logDir = DefaultLogDir
@@ -170,6 +172,7 @@ func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
 		OldPassword:       config.oldPassword,
 		Values:            config.values,
 		PreferIPv6:        config.preferIPv6,
+		IPv6Only:          config.ipv6Only,
 	}
 	if config.servingInfo != nil {
 		format.StateServerCert = config.servingInfo.Cert
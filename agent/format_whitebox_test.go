@@ -91,6 +91,17 @@ func (*formatSuite) TestRead(c *gc.C) {
 	assertWriteAndRead(c, config)
 }
 
+func (*formatSuite) TestReadWriteIPv6Only(c *gc.C) {
+	params := agentParams
+	params.DataDir = c.MkDir()
+	params.StateAddresses = []string{"[2001:db8::1]:1234"}
+	params.APIAddresses = []string{"[2001:db8::1]:1235"}
+	params.IPv6Only = true
+	config, err := NewAgentConfig(params)
+	c.Assert(err, jc.ErrorIsNil)
+	assertWriteAndRead(c, config.(*configInternal))
+}
+
 func (*formatSuite) TestReadWriteStateConfig(c *gc.C) {
 	servingInfo := params.StateServingInfo{
 		Cert:         "some special cert",
@@ -69,9 +69,10 @@ type State struct {
 	// closed is a channel that gets closed when State.Close is called.
 	closed chan struct{}
 
-	// tag and password hold the cached login credentials.
+	// tag, password and nonce hold the cached login credentials.
 	tag      string
 	password string
+	nonce    string
 
 	// serverRootAddress holds the cached API server address and port used
 	// to login.
@@ -115,6 +116,7 @@ func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce s
 		// state structure BEFORE login ?!?
 		tag:      toString(info.Tag),
 		password: info.Password,
+		nonce:    info.Nonce,
 		certPool: conn.Config().TlsConfig.RootCAs,
 	}
 	if info.Tag != nil || info.Password != "" {
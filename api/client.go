@@ -225,6 +225,17 @@ func (c *Client) ServiceSet(service string, options map[string]string) error {
 	return c.facade.FacadeCall("NewServiceSetForClientAPI", p, nil)
 }
 
+// ServiceSetConfigProfile sets the environment config profile whose
+// settings the service uses in place of the charm defaults. An empty
+// profile name causes the service to stop using a config profile.
+func (c *Client) ServiceSetConfigProfile(service, profile string) error {
+	p := params.ServiceSetConfigProfile{
+		ServiceName:   service,
+		ConfigProfile: profile,
+	}
+	return c.facade.FacadeCall("ServiceSetConfigProfile", p, nil)
+}
+
 // ServiceUnset resets configuration options on a service.
 func (c *Client) ServiceUnset(service string, options []string) error {
 	p := params.ServiceUnset{
@@ -705,6 +716,44 @@ func (c *Client) AbortCurrentUpgrade() error {
 	return c.facade.FacadeCall("AbortCurrentUpgrade", nil, nil)
 }
 
+// StartStagedUpgrade sets the agent version for the environment,
+// upgrading the state servers and the given canary machines first,
+// and then the remaining machines batchSize at a time.
+func (c *Client) StartStagedUpgrade(version version.Number, canaryMachines []string, batchSize int) error {
+	args := params.StartStagedUpgrade{
+		Version:        version,
+		CanaryMachines: canaryMachines,
+		BatchSize:      batchSize,
+	}
+	return c.facade.FacadeCall("StartStagedUpgrade", args, nil)
+}
+
+// AbortStagedUpgrade stops the current staged agent upgrade, leaving
+// the machines not yet upgraded at the previous version.
+func (c *Client) AbortStagedUpgrade() error {
+	return c.facade.FacadeCall("AbortStagedUpgrade", nil, nil)
+}
+
+// ResumeStagedUpgrade continues the current staged agent upgrade
+// after it was paused or aborted.
+func (c *Client) ResumeStagedUpgrade() error {
+	return c.facade.FacadeCall("ResumeStagedUpgrade", nil, nil)
+}
+
+// UpgradeCheck reports on the readiness of the environment for an
+// agent upgrade to the given version, without changing anything.
+func (c *Client) UpgradeCheck(version version.Number) (params.UpgradeCheckResult, error) {
+	var result params.UpgradeCheckResult
+	args := params.UpgradeCheck{Version: version}
+	if err := c.facade.FacadeCall("UpgradeCheck", args, &result); err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return result, errors.New("upgrade checks not supported by this version of Juju")
+		}
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
 // FindTools returns a List containing all tools matching the specified parameters.
 func (c *Client) FindTools(majorVersion, minorVersion int, series, arch string) (result params.FindToolsResult, err error) {
 	args := params.FindToolsParams{
@@ -557,6 +557,80 @@ func (s *clientSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	c.Assert(err, gc.Equals, someErr) // Confirms that the correct facade was called
 }
 
+func (s *clientSuite) TestStartStagedUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	someErr := errors.New("random")
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "StartStagedUpgrade")
+			c.Assert(args, jc.DeepEquals, params.StartStagedUpgrade{
+				Version:        version.MustParse("9.8.7"),
+				CanaryMachines: []string{"1", "2"},
+				BatchSize:      3,
+			})
+			c.Assert(response, gc.IsNil)
+			return someErr
+		},
+	)
+	defer cleanup()
+
+	err := client.StartStagedUpgrade(version.MustParse("9.8.7"), []string{"1", "2"}, 3)
+	c.Assert(err, gc.Equals, someErr)
+}
+
+func (s *clientSuite) TestAbortAndResumeStagedUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	var requests []string
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			requests = append(requests, request)
+			c.Assert(args, gc.IsNil)
+			c.Assert(response, gc.IsNil)
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.AbortStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ResumeStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requests, jc.DeepEquals, []string{"AbortStagedUpgrade", "ResumeStagedUpgrade"})
+}
+
+func (s *clientSuite) TestUpgradeCheck(c *gc.C) {
+	client := s.APIState.Client()
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "UpgradeCheck")
+			c.Assert(args, jc.DeepEquals, params.UpgradeCheck{
+				Version: version.MustParse("9.8.7"),
+			})
+			result := response.(*params.UpgradeCheckResult)
+			result.UpgradeSteps = []string{"step"}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := client.UpgradeCheck(version.MustParse("9.8.7"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.UpgradeSteps, jc.DeepEquals, []string{"step"})
+}
+
+func (s *clientSuite) TestUpgradeCheckNotImplemented(c *gc.C) {
+	client := s.APIState.Client()
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			return &params.Error{Code: params.CodeNotImplemented}
+		},
+	)
+	defer cleanup()
+
+	_, err := client.UpgradeCheck(version.MustParse("9.8.7"))
+	c.Assert(err, gc.ErrorMatches, "upgrade checks not supported by this version of Juju")
+}
+
 func (s *clientSuite) TestEnvironmentGet(c *gc.C) {
 	client := s.APIState.Client()
 	env, err := client.EnvironmentGet()
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package configprofiles contains the client for the ConfigProfiles
+// facade, used to manage the environment's charm config profiles.
+package configprofiles
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the configprofiles api.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new configprofiles client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ConfigProfiles")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddConfigProfile adds a config profile with the given name and
+// YAML-formatted settings to the environment.
+func (c *Client) AddConfigProfile(name, settingsYAML string) error {
+	p := params.AddConfigProfiles{
+		Profiles: []params.AddConfigProfile{{
+			Name:         name,
+			SettingsYAML: settingsYAML,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddConfigProfiles", p, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListConfigProfiles returns all of the environment's config profiles,
+// ordered by name.
+func (c *Client) ListConfigProfiles() ([]params.ConfigProfile, error) {
+	var results params.ListConfigProfilesResults
+	if err := c.facade.FacadeCall("ListConfigProfiles", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Profiles, nil
+}
+
+// ConfigProfile returns the config profile with the given name.
+func (c *Client) ConfigProfile(name string) (params.ConfigProfile, error) {
+	p := params.ConfigProfileNames{Names: []string{name}}
+	var results params.ConfigProfileResults
+	if err := c.facade.FacadeCall("ShowConfigProfiles", p, &results); err != nil {
+		return params.ConfigProfile{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ConfigProfile{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.ConfigProfile{}, err
+	}
+	return *results.Results[0].Result, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofiles_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/configprofiles"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type configProfilesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&configProfilesSuite{})
+
+func (s *configProfilesSuite) TestAddConfigProfile(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ConfigProfiles")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "AddConfigProfiles")
+		c.Check(arg, jc.DeepEquals, params.AddConfigProfiles{
+			Profiles: []params.AddConfigProfile{{
+				Name:         "production",
+				SettingsYAML: "title: Production\n",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		callCount++
+		return nil
+	})
+
+	client := configprofiles.NewClient(apiCaller)
+	err := client.AddConfigProfile("production", "title: Production\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callCount, gc.Equals, 1)
+}
+
+func (s *configProfilesSuite) TestAddConfigProfileError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	client := configprofiles.NewClient(apiCaller)
+	err := client.AddConfigProfile("production", "")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *configProfilesSuite) TestListConfigProfiles(c *gc.C) {
+	expected := []params.ConfigProfile{{
+		Name:     "production",
+		Settings: map[string]interface{}{"title": "Production"},
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ConfigProfiles")
+		c.Check(request, gc.Equals, "ListConfigProfiles")
+		c.Assert(result, gc.FitsTypeOf, &params.ListConfigProfilesResults{})
+		*(result.(*params.ListConfigProfilesResults)) = params.ListConfigProfilesResults{
+			Profiles: expected,
+		}
+		return nil
+	})
+	client := configprofiles.NewClient(apiCaller)
+	profiles, err := client.ListConfigProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, jc.DeepEquals, expected)
+}
+
+func (s *configProfilesSuite) TestConfigProfile(c *gc.C) {
+	expected := params.ConfigProfile{
+		Name:     "production",
+		Settings: map[string]interface{}{"title": "Production"},
+	}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ConfigProfiles")
+		c.Check(request, gc.Equals, "ShowConfigProfiles")
+		c.Check(arg, jc.DeepEquals, params.ConfigProfileNames{Names: []string{"production"}})
+		c.Assert(result, gc.FitsTypeOf, &params.ConfigProfileResults{})
+		*(result.(*params.ConfigProfileResults)) = params.ConfigProfileResults{
+			Results: []params.ConfigProfileResult{{Result: &expected}},
+		}
+		return nil
+	})
+	client := configprofiles.NewClient(apiCaller)
+	profile, err := client.ConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, jc.DeepEquals, expected)
+}
+
+func (s *configProfilesSuite) TestConfigProfileError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ConfigProfileResults)) = params.ConfigProfileResults{
+			Results: []params.ConfigProfileResult{{
+				Error: &params.Error{Message: `config profile "production" not found`, Code: params.CodeNotFound},
+			}},
+		}
+		return nil
+	})
+	client := configprofiles.NewClient(apiCaller)
+	_, err := client.ConfigProfile("production")
+	c.Assert(err, gc.ErrorMatches, `config profile "production" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package containermigrator provides the API used by host machine
+// agents to move containers between them.
+package containermigrator
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// httpClient represents the methods of api.State (see api/http.go)
+// needed to transfer container filesystems.
+type httpClient interface {
+	// NewHTTPRequest returns a new request to the API server.
+	NewHTTPRequest(method, path string) (*http.Request, error)
+	// NewHTTPClient returns a client for requests to the API server.
+	NewHTTPClient() *http.Client
+}
+
+// State provides access to a container migrator worker's view of the
+// state.
+type State struct {
+	facade base.FacadeCaller
+	http   httpClient
+}
+
+// NewState returns a version of the state that provides functionality
+// required by the container migrator worker.
+func NewState(caller base.APICaller, http httpClient) *State {
+	return &State{
+		facade: base.NewFacadeCaller(caller, "ContainerMigrator"),
+		http:   http,
+	}
+}
+
+// WatchContainerMigrations returns a NotifyWatcher that notifies of
+// changes to container migrations.
+func (st *State) WatchContainerMigrations() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	if err := st.facade.FacadeCall("WatchContainerMigrations", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
+// ContainerMigrations returns the active migrations of containers to
+// or from the authenticated host machine.
+func (st *State) ContainerMigrations() ([]params.ContainerMigration, error) {
+	var result params.ContainerMigrationsResult
+	if err := st.facade.FacadeCall("ContainerMigrations", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Migrations, nil
+}
+
+// SetStatus records the progress of the migration of the container
+// with the given tag.
+func (st *State) SetStatus(container names.MachineTag, status, message string) error {
+	var results params.ErrorResults
+	args := params.ContainerMigrationStatuses{
+		Migrations: []params.ContainerMigrationStatus{{
+			ContainerTag: container.String(),
+			Status:       status,
+			Message:      message,
+		}},
+	}
+	if err := st.facade.FacadeCall("SetContainerMigrationStatus", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+func rootfsPath(container names.MachineTag) string {
+	return fmt.Sprintf("containers/%s/rootfs", container)
+}
+
+// UploadRootfs sends the archive of the filesystem of the container
+// with the given tag, length bytes long, to the API server.
+func (st *State) UploadRootfs(container names.MachineTag, archive io.Reader, length int64) error {
+	req, err := st.http.NewHTTPRequest("PUT", rootfsPath(container))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/x-tar-gz")
+	req.Body = ioutil.NopCloser(archive)
+	req.ContentLength = length
+	resp, err := st.http.NewHTTPClient().Do(req)
+	if err != nil {
+		return errors.Annotate(err, "while sending HTTP request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Trace(extractError(resp))
+	}
+	return nil
+}
+
+// DownloadRootfs returns the archive of the filesystem of the
+// container with the given tag, and its length.
+func (st *State) DownloadRootfs(container names.MachineTag) (io.ReadCloser, int64, error) {
+	req, err := st.http.NewHTTPRequest("GET", rootfsPath(container))
+	if err != nil {
+		return nil, -1, errors.Trace(err)
+	}
+	resp, err := st.http.NewHTTPClient().Do(req)
+	if err != nil {
+		return nil, -1, errors.Annotate(err, "while sending HTTP request")
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, -1, errors.Trace(extractError(resp))
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+func extractError(resp *http.Response) error {
+	failure, err := apihttp.ExtractAPIError(resp)
+	if err != nil {
+		return errors.Annotate(err, "while extracting failure")
+	}
+	return failure
+}
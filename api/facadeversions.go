@@ -23,6 +23,8 @@ var facadeVersions = map[string]int{
 	"CharmRevisionUpdater":         0,
 	"Client":                       0,
 	"Cleaner":                      1,
+	"ConfigProfiles":               1,
+	"ContainerMigrator":            1,
 	"Deployer":                     0,
 	"DiskManager":                  1,
 	"EntityWatcher":                1,
@@ -30,9 +32,10 @@ var facadeVersions = map[string]int{
 	"EnvironmentManager":           1,
 	"FilesystemAttachmentsWatcher": 1,
 	"Firewaller":                   1,
-	"HighAvailability":             1,
+	"HighAvailability":             3,
 	"ImageManager":                 1,
 	"ImageMetadata":                1,
+	"IPAddresses":                  1,
 	"InstancePoller":               1,
 	"KeyManager":                   0,
 	"KeyUpdater":                   0,
@@ -40,6 +43,7 @@ var facadeVersions = map[string]int{
 	"Logger":                       0,
 	"MachineManager":               1,
 	"Machiner":                     0,
+	"MetricsDebug":                 1,
 	"MetricsManager":               0,
 	"Networker":                    0,
 	"NotifyWatcher":                0,
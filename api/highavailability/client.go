@@ -32,9 +32,13 @@ func NewClient(caller base.APICallCloser) *Client {
 	return &Client{ClientFacade: frontend, facade: backend, environTag: environTag}
 }
 
-// EnsureAvailability ensures the availability of Juju state servers.
+// EnsureAvailability ensures the availability of Juju state servers,
+// and records the given replica set preferences on them. If
+// strictZones is true, an error is returned if the state servers
+// cannot all be placed in distinct availability zones.
 func (c *Client) EnsureAvailability(
 	numStateServers int, cons constraints.Value, series string, placement []string,
+	prefs params.ReplicaSetPreferences, strictZones bool,
 ) (params.StateServersChanges, error) {
 
 	var results params.StateServersChangeResults
@@ -45,8 +49,17 @@ func (c *Client) EnsureAvailability(
 			Constraints:     cons,
 			Series:          series,
 			Placement:       placement,
+			Preferences:     prefs,
+			StrictZones:     strictZones,
 		}}}
 
+	hasPrefs := prefs.PreferredZone != "" || prefs.PreferredPrimary != "" || len(prefs.Hidden) > 0 || prefs.Reset
+	if hasPrefs && c.facade.BestAPIVersion() < 2 {
+		return params.StateServersChanges{}, errors.Errorf("replica set preferences not supported with this version of Juju")
+	}
+	if strictZones && c.facade.BestAPIVersion() < 3 {
+		return params.StateServersChanges{}, errors.Errorf("strict zone placement not supported with this version of Juju")
+	}
 	var err error
 	// We need to retain compatibility with older Juju deployments without the new HighAvailability facade.
 	if c.facade.BestAPIVersion() < 1 {
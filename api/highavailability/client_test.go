@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/api/highavailability"
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
@@ -57,7 +58,7 @@ func assertEnsureAvailability(c *gc.C, s *jujutesting.JujuConnSuite) {
 
 	emptyCons := constraints.Value{}
 	client := highavailability.NewClient(s.APIState)
-	result, err := client.EnsureAvailability(3, emptyCons, "", nil)
+	result, err := client.EnsureAvailability(3, emptyCons, "", nil, params.ReplicaSetPreferences{}, false)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(result.Maintained, gc.DeepEquals, []string{"machine-0"})
@@ -78,7 +79,28 @@ func (s *clientSuite) TestClientEnsureAvailability(c *gc.C) {
 
 func (s *clientSuite) TestClientEnsureAvailabilityVersion(c *gc.C) {
 	client := highavailability.NewClient(s.APIState)
-	c.Assert(client.BestAPIVersion(), gc.Equals, 1)
+	c.Assert(client.BestAPIVersion(), gc.Equals, 2)
+}
+
+func (s *clientSuite) TestClientEnsureAvailabilityPreferences(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	pinger := setAgentPresence(c, &s.JujuConnSuite, "0")
+	defer assertKill(c, pinger)
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := highavailability.NewClient(s.APIState)
+	result, err := client.EnsureAvailability(1, constraints.Value{}, "", nil, params.ReplicaSetPreferences{
+		Hidden: []string{m.Id()},
+	}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hidden, gc.DeepEquals, []string{m.Tag().String()})
+
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.IsManager(), jc.IsTrue)
+	c.Assert(m.ReplicaSetHidden(), jc.IsTrue)
 }
 
 type clientLegacySuite struct {
@@ -89,6 +111,8 @@ var _ = gc.Suite(&clientLegacySuite{})
 
 func (s *clientLegacySuite) SetUpTest(c *gc.C) {
 	common.Facades.Discard("HighAvailability", 1)
+	common.Facades.Discard("HighAvailability", 2)
+	common.Facades.Discard("HighAvailability", 3)
 	s.JujuConnSuite.SetUpTest(c)
 }
 
@@ -98,6 +122,20 @@ func (s *clientLegacySuite) TestEnsureAvailabilityLegacy(c *gc.C) {
 
 func (s *clientLegacySuite) TestEnsureAvailabilityLegacyRejectsPlacement(c *gc.C) {
 	client := highavailability.NewClient(s.APIState)
-	_, err := client.EnsureAvailability(3, constraints.Value{}, "", []string{"machine"})
+	_, err := client.EnsureAvailability(3, constraints.Value{}, "", []string{"machine"}, params.ReplicaSetPreferences{}, false)
 	c.Assert(err, gc.ErrorMatches, "placement directives not supported with this version of Juju")
 }
+
+func (s *clientLegacySuite) TestEnsureAvailabilityLegacyRejectsPreferences(c *gc.C) {
+	client := highavailability.NewClient(s.APIState)
+	_, err := client.EnsureAvailability(3, constraints.Value{}, "", nil, params.ReplicaSetPreferences{
+		PreferredPrimary: "0",
+	}, false)
+	c.Assert(err, gc.ErrorMatches, "replica set preferences not supported with this version of Juju")
+}
+
+func (s *clientLegacySuite) TestEnsureAvailabilityLegacyRejectsStrictZones(c *gc.C) {
+	client := highavailability.NewClient(s.APIState)
+	_, err := client.EnsureAvailability(3, constraints.Value{}, "", nil, params.ReplicaSetPreferences{}, true)
+	c.Assert(err, gc.ErrorMatches, "strict zone placement not supported with this version of Juju")
+}
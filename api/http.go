@@ -47,7 +47,14 @@ func (s *State) NewHTTPRequest(method, path string) (*http.Request, error) {
 	uuid := tag.Id()
 
 	req, err := apiserverhttp.NewRequest(method, baseURL, path, uuid, s.tag, s.password)
-	return req, errors.Trace(err)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s.nonce != "" {
+		// Machine agents must identify the instance they run on.
+		req.Header.Set("X-Juju-Nonce", s.nonce)
+	}
+	return req, nil
 }
 
 // SendHTTPRequest sends a GET request using the HTTP client derived from State.
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/charmrevisionupdater"
 	"github.com/juju/juju/api/cleaner"
+	"github.com/juju/juju/api/containermigrator"
 	"github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/api/diskmanager"
 	"github.com/juju/juju/api/environment"
@@ -155,6 +156,7 @@ type Connection interface {
 	Resumer() *resumer.API
 	Networker() networker.State
 	Provisioner() *provisioner.State
+	ContainerMigrator() *containermigrator.State
 	Uniter() (*uniter.State, error)
 	DiskManager() (*diskmanager.State, error)
 	StorageProvisioner(scope names.Tag) *storageprovisioner.State
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddresses
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const ipAddressesFacade = "IPAddresses"
+
+// API provides access to the IPAddresses API facade.
+type API struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewAPI creates a new client-side IPAddresses facade.
+func NewAPI(caller base.APICallCloser) *API {
+	if caller == nil {
+		panic("caller is nil")
+	}
+	clientFacade, facadeCaller := base.NewClientFacade(caller, ipAddressesFacade)
+	return &API{
+		ClientFacade: clientFacade,
+		facade:       facadeCaller,
+	}
+}
+
+// ListIPAddresses returns the IP addresses known to Juju. When
+// subnetCIDR or machineId are not empty, only addresses in that
+// subnet or held by that machine are returned.
+func (api *API) ListIPAddresses(subnetCIDR, machineId string) ([]params.IPAddressInfo, error) {
+	var args params.IPAddressFilter
+	if subnetCIDR != "" {
+		if !names.IsValidSubnet(subnetCIDR) {
+			return nil, errors.NotValidf("subnet %q", subnetCIDR)
+		}
+		args.SubnetTag = names.NewSubnetTag(subnetCIDR).String()
+	}
+	if machineId != "" {
+		if !names.IsValidMachine(machineId) {
+			return nil, errors.NotValidf("machine ID %q", machineId)
+		}
+		args.MachineTag = names.NewMachineTag(machineId).String()
+	}
+	var response params.IPAddressInfoResults
+	err := api.facade.FacadeCall("ListIPAddresses", args, &response)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return response.Results, nil
+}
+
+// ReserveIPAddress reserves the given address in the subnet with the
+// given CIDR, or picks a free one when address is empty, so it is never
+// allocated to a container. It returns the reserved address.
+func (api *API) ReserveIPAddress(subnetCIDR, address string) (string, error) {
+	if !names.IsValidSubnet(subnetCIDR) {
+		return "", errors.NotValidf("subnet %q", subnetCIDR)
+	}
+	args := params.ReserveIPAddressesParams{
+		Reservations: []params.ReserveIPAddressParams{{
+			SubnetTag: names.NewSubnetTag(subnetCIDR).String(),
+			Address:   address,
+		}},
+	}
+	var response params.StringResults
+	err := api.facade.FacadeCall("ReserveIPAddresses", args, &response)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(response.Results))
+	}
+	if err := response.Results[0].Error; err != nil {
+		return "", err
+	}
+	return response.Results[0].Result, nil
+}
+
+// ReleaseIPAddresses releases the given addresses with the provider
+// and removes them from Juju. Addresses held by live machines are only
+// released when force is true. One result is returned for each
+// address.
+func (api *API) ReleaseIPAddresses(addresses []string, force bool) ([]params.ErrorResult, error) {
+	args := params.ReleaseIPAddressesParams{
+		Addresses: addresses,
+		Force:     force,
+	}
+	var response params.ErrorResults
+	err := api.facade.FacadeCall("ReleaseIPAddresses", args, &response)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(response.Results) != len(addresses) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(addresses), len(response.Results))
+	}
+	return response.Results, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddresses_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/ipaddresses"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type IPAddressesSuite struct {
+	coretesting.BaseSuite
+
+	called    int
+	apiCaller base.APICallCloser
+	api       *ipaddresses.API
+}
+
+var _ = gc.Suite(&IPAddressesSuite{})
+
+func (s *IPAddressesSuite) init(c *gc.C, args *apitesting.CheckArgs, err error) {
+	s.called = 0
+	s.apiCaller = apitesting.CheckingAPICaller(c, args, &s.called, err)
+	s.api = ipaddresses.NewAPI(s.apiCaller)
+	c.Check(s.api, gc.NotNil)
+	c.Check(s.called, gc.Equals, 0)
+}
+
+func (s *IPAddressesSuite) TestNewAPIWithNilCaller(c *gc.C) {
+	panicFunc := func() { ipaddresses.NewAPI(nil) }
+	c.Assert(panicFunc, gc.PanicMatches, "caller is nil")
+}
+
+func (s *IPAddressesSuite) TestListIPAddresses(c *gc.C) {
+	expected := []params.IPAddressInfo{{
+		Value:      "10.0.0.11",
+		SubnetTag:  "subnet-10.0.0.0/24",
+		MachineTag: "machine-0-lxc-1",
+		State:      "allocated",
+		Life:       params.Alive,
+	}}
+	args := apitesting.CheckArgs{
+		Facade: "IPAddresses",
+		Method: "ListIPAddresses",
+		Args: params.IPAddressFilter{
+			SubnetTag:  "subnet-10.0.0.0/24",
+			MachineTag: "machine-0-lxc-1",
+		},
+		Results: params.IPAddressInfoResults{Results: expected},
+	}
+	s.init(c, &args, nil)
+	results, err := s.api.ListIPAddresses("10.0.0.0/24", "0/lxc/1")
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *IPAddressesSuite) TestListIPAddressesInvalidFilter(c *gc.C) {
+	s.init(c, nil, nil)
+	_, err := s.api.ListIPAddresses("foo", "")
+	c.Assert(err, gc.ErrorMatches, `subnet "foo" not valid`)
+	_, err = s.api.ListIPAddresses("", "bar")
+	c.Assert(err, gc.ErrorMatches, `machine ID "bar" not valid`)
+	c.Assert(s.called, gc.Equals, 0)
+}
+
+func (s *IPAddressesSuite) TestListIPAddressesServerError(c *gc.C) {
+	args := apitesting.CheckArgs{
+		Facade: "IPAddresses",
+		Method: "ListIPAddresses",
+		Args:   params.IPAddressFilter{},
+	}
+	s.init(c, &args, errors.New("boom"))
+	results, err := s.api.ListIPAddresses("", "")
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(results, gc.IsNil)
+}
+
+func (s *IPAddressesSuite) TestReserveIPAddress(c *gc.C) {
+	args := apitesting.CheckArgs{
+		Facade: "IPAddresses",
+		Method: "ReserveIPAddresses",
+		Args: params.ReserveIPAddressesParams{
+			Reservations: []params.ReserveIPAddressParams{{
+				SubnetTag: "subnet-10.0.0.0/24",
+			}},
+		},
+		Results: params.StringResults{
+			Results: []params.StringResult{{Result: "10.0.0.10"}},
+		},
+	}
+	s.init(c, &args, nil)
+	value, err := s.api.ReserveIPAddress("10.0.0.0/24", "")
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(value, gc.Equals, "10.0.0.10")
+}
+
+func (s *IPAddressesSuite) TestReserveIPAddressError(c *gc.C) {
+	args := apitesting.CheckArgs{
+		Facade: "IPAddresses",
+		Method: "ReserveIPAddresses",
+		Args: params.ReserveIPAddressesParams{
+			Reservations: []params.ReserveIPAddressParams{{
+				SubnetTag: "subnet-10.0.0.0/24",
+				Address:   "10.0.0.5",
+			}},
+		},
+		Results: params.StringResults{
+			Results: []params.StringResult{{
+				Error: &params.Error{Message: "nope"},
+			}},
+		},
+	}
+	s.init(c, &args, nil)
+	value, err := s.api.ReserveIPAddress("10.0.0.0/24", "10.0.0.5")
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, gc.ErrorMatches, "nope")
+	c.Assert(value, gc.Equals, "")
+}
+
+func (s *IPAddressesSuite) TestReleaseIPAddresses(c *gc.C) {
+	expected := []params.ErrorResult{
+		{Error: nil},
+		{Error: &params.Error{Message: "in use"}},
+	}
+	args := apitesting.CheckArgs{
+		Facade: "IPAddresses",
+		Method: "ReleaseIPAddresses",
+		Args: params.ReleaseIPAddressesParams{
+			Addresses: []string{"10.0.0.11", "10.0.0.12"},
+			Force:     true,
+		},
+		Results: params.ErrorResults{Results: expected},
+	}
+	s.init(c, &args, nil)
+	results, err := s.api.ReleaseIPAddresses([]string{"10.0.0.11", "10.0.0.12"}, true)
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *IPAddressesSuite) TestReleaseIPAddressesWrongResultCount(c *gc.C) {
+	args := apitesting.CheckArgs{
+		Facade: "IPAddresses",
+		Method: "ReleaseIPAddresses",
+		Args: params.ReleaseIPAddressesParams{
+			Addresses: []string{"10.0.0.11"},
+		},
+		Results: params.ErrorResults{},
+	}
+	s.init(c, &args, nil)
+	_, err := s.api.ReleaseIPAddresses([]string{"10.0.0.11"}, false)
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, gc.ErrorMatches, `expected 1 result\(s\), got 0`)
+}
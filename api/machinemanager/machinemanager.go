@@ -5,6 +5,7 @@ package machinemanager
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -36,3 +37,69 @@ func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]pa
 	}
 	return results.Machines, err
 }
+
+// MigrateContainer starts moving the container with the given tag to
+// the target host machine. Only LXC and KVM containers can be migrated.
+func (client *Client) MigrateContainer(container, targetHost names.MachineTag) error {
+	args := params.MigrateContainers{
+		Migrations: []params.MigrateContainer{{
+			ContainerTag:  container.String(),
+			TargetHostTag: targetHost.String(),
+		}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("MigrateContainers", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// CordonMachines cordons the given machines, so that no new units or
+// containers are placed on them.
+func (client *Client) CordonMachines(machines ...names.MachineTag) error {
+	return client.setMachinesCordoned("CordonMachines", machines)
+}
+
+// UncordonMachines uncordons the given machines, allowing new units
+// and containers to be placed on them again.
+func (client *Client) UncordonMachines(machines ...names.MachineTag) error {
+	return client.setMachinesCordoned("UncordonMachines", machines)
+}
+
+func (client *Client) setMachinesCordoned(method string, machines []names.MachineTag) error {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(machines)),
+	}
+	for i, machine := range machines {
+		args.Entities[i].Tag = machine.String()
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall(method, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != len(machines) {
+		return errors.Errorf("expected %d result(s), got %d", len(machines), len(results.Results))
+	}
+	return results.Combine()
+}
+
+// DrainMachine cordons the given machine and replaces its units with
+// new units elsewhere. The units drained are returned, even if the
+// drain failed part way through.
+func (client *Client) DrainMachine(machine names.MachineTag) ([]params.DrainedUnit, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: machine.String()}},
+	}
+	var results params.DrainMachinesResults
+	if err := client.facade.FacadeCall("DrainMachines", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Units, result.Error
+	}
+	return result.Units, nil
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -116,3 +117,91 @@ func (s *MachinemanagerSuite) TestAddMachinesResultCountInvalid(c *gc.C) {
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *MachinemanagerSuite) TestMigrateContainer(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "MigrateContainers")
+		c.Check(arg, jc.DeepEquals, params.MigrateContainers{
+			Migrations: []params.MigrateContainer{{
+				ContainerTag:  "machine-0-lxc-0",
+				TargetHostTag: "machine-1",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "MSG"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	err := st.MigrateContainer(names.NewMachineTag("0/lxc/0"), names.NewMachineTag("1"))
+	c.Assert(err, gc.ErrorMatches, "MSG")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestCordonMachines(c *gc.C) {
+	for _, method := range []string{"CordonMachines", "UncordonMachines"} {
+		var callCount int
+		apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "MachineManager")
+			c.Check(request, gc.Equals, method)
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {
+					Error: &params.Error{Message: "MSG"},
+				}},
+			}
+			callCount++
+			return nil
+		})
+		st := machinemanager.NewClient(apiCaller)
+		machines := []names.MachineTag{names.NewMachineTag("0"), names.NewMachineTag("1")}
+		var err error
+		if method == "CordonMachines" {
+			err = st.CordonMachines(machines...)
+		} else {
+			err = st.UncordonMachines(machines...)
+		}
+		c.Check(err, gc.ErrorMatches, "MSG")
+		c.Check(callCount, gc.Equals, 1)
+	}
+}
+
+func (s *MachinemanagerSuite) TestDrainMachine(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "DrainMachines")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-1"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.DrainMachinesResults{})
+		*(result.(*params.DrainMachinesResults)) = params.DrainMachinesResults{
+			Results: []params.DrainMachineResult{{
+				Units: []params.DrainedUnit{{
+					UnitTag:        "unit-mysql-0",
+					ReplacementTag: "unit-mysql-1",
+				}},
+				Error: &params.Error{Message: "MSG"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	units, err := st.DrainMachine(names.NewMachineTag("1"))
+	c.Assert(err, gc.ErrorMatches, "MSG")
+	c.Assert(units, jc.DeepEquals, []params.DrainedUnit{{
+		UnitTag:        "unit-mysql-0",
+		ReplacementTag: "unit-mysql-1",
+	}})
+	c.Check(callCount, gc.Equals, 1)
+}
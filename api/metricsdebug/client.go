@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package metricsdebug contains the client for the MetricsDebug facade,
+// used to query the metrics collected from units.
+package metricsdebug
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the metricsdebug api.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new metricsdebug client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "MetricsDebug")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// GetMetrics returns the metrics recorded by the specified unit, or by
+// all units of the specified service. If since is non-zero, only metrics
+// recorded after that time are returned.
+func (c *Client) GetMetrics(tag names.Tag, since time.Time) ([]params.MetricResult, error) {
+	p := params.MetricsQueries{
+		Queries: []params.MetricsQuery{{Tag: tag.String(), Since: since}},
+	}
+	var results params.MetricResults
+	if err := c.facade.FacadeCall("GetMetrics", p, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Metrics, nil
+}
+
+// MeterThresholds returns the meter thresholds of the specified service.
+func (c *Client) MeterThresholds(service string) ([]string, error) {
+	p := params.Entities{
+		Entities: []params.Entity{{names.NewServiceTag(service).String()}},
+	}
+	var results params.MeterThresholdsResults
+	if err := c.facade.FacadeCall("MeterThresholds", p, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Thresholds, nil
+}
+
+// SetMeterThresholds replaces the meter thresholds of the specified
+// service. Each threshold is of the form
+// "<metric> <operator> <value> [for <duration>] -> <status>".
+func (c *Client) SetMeterThresholds(service string, thresholds []string) error {
+	p := params.MeterThresholdsArgs{
+		Args: []params.MeterThresholds{{
+			Tag:        names.NewServiceTag(service).String(),
+			Thresholds: thresholds,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetMeterThresholds", p, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
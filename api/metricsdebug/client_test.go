@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/metricsdebug"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type metricsdebugSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&metricsdebugSuite{})
+
+func (s *metricsdebugSuite) TestGetMetrics(c *gc.C) {
+	since := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MetricsDebug")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "GetMetrics")
+		c.Check(arg, gc.DeepEquals, params.MetricsQueries{
+			Queries: []params.MetricsQuery{{Tag: "service-mysql", Since: since}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.MetricResults{})
+		*(result.(*params.MetricResults)) = params.MetricResults{
+			Results: []params.EntityMetrics{{
+				Metrics: []params.MetricResult{{
+					Unit: "mysql/0", Key: "pings", Value: "5", Time: since,
+				}},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	client := metricsdebug.NewClient(apiCaller)
+	metrics, err := client.GetMetrics(names.NewServiceTag("mysql"), since)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callCount, gc.Equals, 1)
+	c.Assert(metrics, jc.DeepEquals, []params.MetricResult{{
+		Unit: "mysql/0", Key: "pings", Value: "5", Time: since,
+	}})
+}
+
+func (s *metricsdebugSuite) TestGetMetricsError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.MetricResults)) = params.MetricResults{
+			Results: []params.EntityMetrics{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		return nil
+	})
+	client := metricsdebug.NewClient(apiCaller)
+	_, err := client.GetMetrics(names.NewUnitTag("mysql/0"), time.Time{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *metricsdebugSuite) TestMeterThresholds(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MetricsDebug")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "MeterThresholds")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{"service-mysql"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.MeterThresholdsResults{})
+		*(result.(*params.MeterThresholdsResults)) = params.MeterThresholdsResults{
+			Results: []params.MeterThresholdsResult{{
+				Thresholds: []string{"pings > 1000 -> AMBER"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+	client := metricsdebug.NewClient(apiCaller)
+	thresholds, err := client.MeterThresholds("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callCount, gc.Equals, 1)
+	c.Assert(thresholds, jc.DeepEquals, []string{"pings > 1000 -> AMBER"})
+}
+
+func (s *metricsdebugSuite) TestSetMeterThresholds(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MetricsDebug")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetMeterThresholds")
+		c.Check(arg, gc.DeepEquals, params.MeterThresholdsArgs{
+			Args: []params.MeterThresholds{{
+				Tag:        "service-mysql",
+				Thresholds: []string{"pings > 1000 -> AMBER"},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+	client := metricsdebug.NewClient(apiCaller)
+	err := client.SetMeterThresholds("mysql", []string{"pings > 1000 -> AMBER"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(callCount, gc.Equals, 1)
+}
@@ -24,6 +24,7 @@ type Client struct {
 type MetricsManagerClient interface {
 	CleanupOldMetrics() error
 	SendMetrics() error
+	EvaluateMeterThresholds() error
 }
 
 var _ MetricsManagerClient = (*Client)(nil)
@@ -68,3 +69,21 @@ func (c *Client) SendMetrics() error {
 	}
 	return results.OneError()
 }
+
+// EvaluateMeterThresholds evaluates the meter thresholds defined for
+// services in the environment, setting the meter status of their units.
+func (c *Client) EvaluateMeterThresholds() error {
+	envTag, err := c.st.EnvironTag()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p := params.Entities{Entities: []params.Entity{
+		{envTag.String()},
+	}}
+	results := new(params.ErrorResults)
+	err = c.facade.FacadeCall("EvaluateMeterThresholds", p, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
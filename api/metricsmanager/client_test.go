@@ -69,3 +69,17 @@ func (s *metricsManagerSuite) TestSendMetricsFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *metricsManagerSuite) TestEvaluateMeterThresholds(c *gc.C) {
+	var called bool
+	metricsmanager.PatchFacadeCall(s, s.manager, func(request string, args, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "EvaluateMeterThresholds")
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	})
+	err := s.manager.EvaluateMeterThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
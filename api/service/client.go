@@ -10,6 +10,7 @@ package service
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
@@ -63,7 +64,10 @@ func (c *Client) EnvironmentUUID() string {
 // requested networks that must be present on the machines where the
 // service is deployed. Another way to specify networks to include/exclude
 // is using constraints. Placement directives, if provided, specify the
-// machine on which the charm is deployed.
+// machine on which the charm is deployed. Endpoint bindings, if provided,
+// map the charm's relation endpoints to the spaces they should use. The
+// config profile, if provided, names the environment config profile whose
+// settings the service uses in place of the charm defaults.
 func (c *Client) ServiceDeploy(
 	charmURL string,
 	serviceName string,
@@ -74,6 +78,8 @@ func (c *Client) ServiceDeploy(
 	placement []*instance.Placement,
 	networks []string,
 	storage map[string]storage.Constraints,
+	endpointBindings map[string]string,
+	configProfile string,
 ) error {
 	args := params.ServicesDeploy{
 		Services: []params.ServiceDeploy{{
@@ -86,6 +92,9 @@ func (c *Client) ServiceDeploy(
 			Placement:     placement,
 			Networks:      networks,
 			Storage:       storage,
+
+			EndpointBindings: endpointBindings,
+			ConfigProfile:    configProfile,
 		}},
 	}
 	var results params.ErrorResults
@@ -106,3 +115,77 @@ func (c *Client) ServiceDeploy(
 	}
 	return results.OneError()
 }
+
+// SetCharmRolling starts a rolling upgrade of the service to the given
+// charm, upgrading batchSize units at a time.
+func (c *Client) SetCharmRolling(serviceName, charmURL string, force bool, batchSize int) error {
+	args := params.ServicesSetCharmRolling{
+		Services: []params.ServiceSetCharmRolling{{
+			ServiceName: serviceName,
+			CharmUrl:    charmURL,
+			Force:       force,
+			BatchSize:   batchSize,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetCharmsRolling", args, &results); err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return errors.New("rolling charm upgrades not supported by this version of Juju")
+		}
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ResumeRollingUpgrade resumes the paused rolling charm upgrade of
+// the service.
+func (c *Client) ResumeRollingUpgrade(serviceName string) error {
+	return c.updateRollingUpgrade("ResumeRollingUpgrades", serviceName)
+}
+
+// RollBackRollingUpgrade abandons the rolling charm upgrade of the
+// service, returning it to its previous charm.
+func (c *Client) RollBackRollingUpgrade(serviceName string) error {
+	return c.updateRollingUpgrade("RollBackRollingUpgrades", serviceName)
+}
+
+func (c *Client) updateRollingUpgrade(method, serviceName string) error {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(serviceName).String()}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return errors.New("rolling charm upgrades not supported by this version of Juju")
+		}
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RollBackCharm returns the service to a charm it used previously:
+// the one with the given revision, or the most recent one if revision
+// is negative. It returns the URL of the charm the service now uses.
+func (c *Client) RollBackCharm(serviceName string, revision int, force bool) (string, error) {
+	args := params.ServicesRollBackCharm{
+		Services: []params.ServiceRollBackCharm{{
+			ServiceName: serviceName,
+			Revision:    revision,
+			Force:       force,
+		}},
+	}
+	var results params.RollBackCharmResults
+	if err := c.facade.FacadeCall("RollBackCharms", args, &results); err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return "", errors.New("charm rollback not supported by this version of Juju")
+		}
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", err
+	}
+	return results.Results[0].CharmURL, nil
+}
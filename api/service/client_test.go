@@ -89,13 +89,105 @@ func (s *serviceSuite) TestSetServiceDeploy(c *gc.C) {
 		c.Assert(args.Services[0].ToMachineSpec, gc.Equals, "machineSpec")
 		c.Assert(args.Services[0].Networks, gc.DeepEquals, []string{"neta"})
 		c.Assert(args.Services[0].Storage, gc.DeepEquals, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}})
+		c.Assert(args.Services[0].EndpointBindings, gc.DeepEquals, map[string]string{"db": "internal"})
+		c.Assert(args.Services[0].ConfigProfile, gc.Equals, "production")
 
 		result := response.(*params.ErrorResults)
 		result.Results = make([]params.ErrorResult, 1)
 		return nil
 	})
 	err := s.client.ServiceDeploy("charmURL", "serviceA", 2, "configYAML", constraints.MustParse("mem=4G"),
-		"machineSpec", nil, []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}})
+		"machineSpec", nil, []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}},
+		map[string]string{"db": "internal"}, "production")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *serviceSuite) TestSetCharmRolling(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "SetCharmsRolling")
+		c.Assert(a, jc.DeepEquals, params.ServicesSetCharmRolling{
+			Services: []params.ServiceSetCharmRolling{{
+				ServiceName: "wordpress",
+				CharmUrl:    "cs:quantal/wordpress-4",
+				BatchSize:   2,
+			}},
+		})
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	})
+	err := s.client.SetCharmRolling("wordpress", "cs:quantal/wordpress-4", false, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestSetCharmRollingNotImplemented(c *gc.C) {
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		return &params.Error{Code: params.CodeNotImplemented}
+	})
+	err := s.client.SetCharmRolling("wordpress", "cs:quantal/wordpress-4", false, 2)
+	c.Assert(err, gc.ErrorMatches, "rolling charm upgrades not supported by this version of Juju")
+}
+
+func (s *serviceSuite) TestUpdateRollingUpgrade(c *gc.C) {
+	for _, method := range []string{"ResumeRollingUpgrades", "RollBackRollingUpgrades"} {
+		var called bool
+		service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, method)
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "service-wordpress"}},
+			})
+			result := response.(*params.ErrorResults)
+			result.Results = []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}}
+			return nil
+		})
+		var err error
+		if method == "ResumeRollingUpgrades" {
+			err = s.client.ResumeRollingUpgrade("wordpress")
+		} else {
+			err = s.client.RollBackRollingUpgrade("wordpress")
+		}
+		c.Assert(err, gc.ErrorMatches, "boom")
+		c.Assert(called, jc.IsTrue)
+	}
+}
+
+func (s *serviceSuite) TestRollBackCharm(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "RollBackCharms")
+		c.Assert(a, jc.DeepEquals, params.ServicesRollBackCharm{
+			Services: []params.ServiceRollBackCharm{{
+				ServiceName: "wordpress",
+				Revision:    3,
+				Force:       true,
+			}},
+		})
+		result := response.(*params.RollBackCharmResults)
+		result.Results = []params.RollBackCharmResult{{CharmURL: "cs:quantal/wordpress-3"}}
+		return nil
+	})
+	curl, err := s.client.RollBackCharm("wordpress", 3, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.Equals, "cs:quantal/wordpress-3")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestRollBackCharmError(c *gc.C) {
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		result := response.(*params.RollBackCharmResults)
+		result.Results = []params.RollBackCharmResult{{
+			Error: &params.Error{Message: "boom"},
+		}}
+		return nil
+	})
+	_, err := s.client.RollBackCharm("wordpress", -1, false)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
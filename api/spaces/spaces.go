@@ -69,3 +69,17 @@ func (api *API) ListSpaces() ([]params.Space, error) {
 	err := api.facade.FacadeCall("ListSpaces", nil, &response)
 	return response.Results, err
 }
+
+// DiscoverSpaces returns the subnets known to the provider but not
+// yet to Juju, grouped by their proposed spaces. If importSubnets is
+// true, the proposed subnets and spaces are also added to the
+// environment.
+func (api *API) DiscoverSpaces(importSubnets bool) ([]params.Space, error) {
+	var response params.DiscoverSpacesResults
+	args := params.DiscoverSpacesParams{Import: importSubnets}
+	err := api.facade.FacadeCall("DiscoverSpaces", args, &response)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return response.Results, nil
+}
@@ -171,3 +171,37 @@ func (s *SpacesSuite) TestListSpacesManyResults(c *gc.C) {
 func (s *SpacesSuite) TestListSpacesServerError(c *gc.C) {
 	s.testListSpaces(c, nil, errors.New("boom"), "boom")
 }
+
+func (s *SpacesSuite) TestDiscoverSpaces(c *gc.C) {
+	proposed := []params.Space{{
+		Name:    "",
+		Subnets: []params.Subnet{{CIDR: "10.30.0.0/24"}},
+	}, {
+		Name:    "internal",
+		Subnets: []params.Subnet{{CIDR: "10.20.0.0/24", SpaceTag: "space-internal"}},
+	}}
+	args := apitesting.CheckArgs{
+		Facade:  "Spaces",
+		Method:  "DiscoverSpaces",
+		Args:    params.DiscoverSpacesParams{Import: true},
+		Results: params.DiscoverSpacesResults{Results: proposed},
+	}
+	s.init(c, &args, nil)
+	results, err := s.api.DiscoverSpaces(true)
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, proposed)
+}
+
+func (s *SpacesSuite) TestDiscoverSpacesServerError(c *gc.C) {
+	args := apitesting.CheckArgs{
+		Facade: "Spaces",
+		Method: "DiscoverSpaces",
+		Args:   params.DiscoverSpacesParams{},
+	}
+	s.init(c, &args, errors.New("boom"))
+	results, err := s.api.DiscoverSpaces(false)
+	c.Assert(s.called, gc.Equals, 1)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(results, gc.IsNil)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/charmrevisionupdater"
 	"github.com/juju/juju/api/cleaner"
+	"github.com/juju/juju/api/containermigrator"
 	"github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/api/diskmanager"
 	"github.com/juju/juju/api/environment"
@@ -259,6 +260,12 @@ func (st *State) Provisioner() *provisioner.State {
 	return provisioner.NewState(st)
 }
 
+// ContainerMigrator returns a version of the state that provides
+// functionality required by the container migrator worker.
+func (st *State) ContainerMigrator() *containermigrator.State {
+	return containermigrator.NewState(st, st)
+}
+
 // Uniter returns a version of the state that provides functionality
 // required by the uniter worker.
 func (st *State) Uniter() (*uniter.State, error) {
@@ -112,3 +112,146 @@ func (c *Client) AddToUnit(storages []params.StorageAddParams) ([]params.ErrorRe
 	}
 	return out.Results, nil
 }
+
+// CreateVolumeSnapshots requests snapshots of the volumes with the
+// specified IDs.
+func (c *Client) CreateVolumeSnapshots(volumes []string) ([]params.VolumeSnapshotResult, error) {
+	args := params.Entities{Entities: make([]params.Entity, len(volumes))}
+	for i, one := range volumes {
+		args.Entities[i].Tag = names.NewVolumeTag(one).String()
+	}
+	out := params.VolumeSnapshotResults{}
+	if err := c.facade.FacadeCall("CreateVolumeSnapshots", args, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// ListVolumeSnapshots lists snapshots of the volumes with the specified
+// IDs. If no volumes are provided, a list of all snapshots is returned.
+func (c *Client) ListVolumeSnapshots(volumes []string) ([]params.VolumeSnapshotResult, error) {
+	tags := make([]string, len(volumes))
+	for i, one := range volumes {
+		tags[i] = names.NewVolumeTag(one).String()
+	}
+	args := params.VolumeSnapshotFilter{Volumes: tags}
+	out := params.VolumeSnapshotResults{}
+	if err := c.facade.FacadeCall("ListVolumeSnapshots", args, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// RestoreVolumeSnapshots creates new volumes from the specified volume
+// snapshots, returning the tags of the new volumes.
+func (c *Client) RestoreVolumeSnapshots(snapshots []params.RestoreVolumeSnapshot) ([]params.StringResult, error) {
+	args := params.RestoreVolumeSnapshots{Snapshots: snapshots}
+	out := params.StringResults{}
+	if err := c.facade.FacadeCall("RestoreVolumeSnapshots", args, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// Detach detaches the specified storage instances from the units they
+// are attached to, retaining them so they may be attached to other units.
+func (c *Client) Detach(storageIds []string) ([]params.ErrorResult, error) {
+	args := params.Entities{Entities: make([]params.Entity, len(storageIds))}
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		args.Entities[i].Tag = names.NewStorageTag(id).String()
+	}
+	out := params.ErrorResults{}
+	if err := c.facade.FacadeCall("Detach", args, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// Remove removes the specified storage instances from the units they are
+// attached to, using the specified release policy: "destroy" or "retain".
+// If the policy is empty, the release policy of each storage instance's
+// pool is used.
+func (c *Client) Remove(storageIds []string, releasePolicy string) ([]params.ErrorResult, error) {
+	args := params.StorageRemovals{Storage: make([]params.StorageRemoval, len(storageIds))}
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		args.Storage[i] = params.StorageRemoval{
+			StorageTag:    names.NewStorageTag(id).String(),
+			ReleasePolicy: releasePolicy,
+		}
+	}
+	out := params.ErrorResults{}
+	if err := c.facade.FacadeCall("Remove", args, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// Attach attaches the specified detached storage instance to a unit.
+func (c *Client) Attach(storageId, unitId string) error {
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	if !names.IsValidUnit(unitId) {
+		return errors.NotValidf("unit ID %q", unitId)
+	}
+	args := params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			UnitTag:    names.NewUnitTag(unitId).String(),
+		}},
+	}
+	out := params.ErrorResults{}
+	if err := c.facade.FacadeCall("Attach", args, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// Resize requests that the specified storage instance be grown to the
+// specified size, in MiB.
+func (c *Client) Resize(storageId string, size uint64) error {
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	args := params.StorageResizes{
+		Storage: []params.StorageResize{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Size:       size,
+		}},
+	}
+	out := params.ErrorResults{}
+	if err := c.facade.FacadeCall("Resize", args, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// Import imports the volume or filesystem with the specified provider ID,
+// from the specified pool, as storage with the specified name. The tag
+// of the new storage instance is returned.
+func (c *Client) Import(pool, providerId, storageName string) (names.StorageTag, error) {
+	args := params.StorageImports{
+		Storage: []params.StorageImport{{
+			Pool:        pool,
+			ProviderId:  providerId,
+			StorageName: storageName,
+		}},
+	}
+	out := params.StringResults{}
+	if err := c.facade.FacadeCall("Import", args, &out); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	if len(out.Results) != 1 {
+		return names.StorageTag{}, errors.Errorf("expected 1 result, got %d", len(out.Results))
+	}
+	if err := out.Results[0].Error; err != nil {
+		return names.StorageTag{}, err
+	}
+	return names.ParseStorageTag(out.Results[0].Result)
+}
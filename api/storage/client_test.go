@@ -474,3 +474,296 @@ func (s *storageMockSuite) TestAddToUnitFacadeCallError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 	c.Assert(found, gc.HasLen, 0)
 }
+
+func (s *storageMockSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CreateVolumeSnapshots")
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{"volume-0-1"}},
+			})
+			if results, ok := result.(*params.VolumeSnapshotResults); ok {
+				results.Results = []params.VolumeSnapshotResult{{
+					Result: &params.VolumeSnapshotInstance{Id: "0/0", VolumeTag: "volume-0-1"},
+				}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.CreateVolumeSnapshots([]string{"0/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 1)
+	c.Assert(found[0].Result.Id, gc.Equals, "0/0")
+}
+
+func (s *storageMockSuite) TestListVolumeSnapshots(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListVolumeSnapshots")
+			c.Assert(a, jc.DeepEquals, params.VolumeSnapshotFilter{
+				Volumes: []string{"volume-2"},
+			})
+			if results, ok := result.(*params.VolumeSnapshotResults); ok {
+				results.Results = []params.VolumeSnapshotResult{{
+					Result: &params.VolumeSnapshotInstance{Id: "1", VolumeTag: "volume-2"},
+				}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.ListVolumeSnapshots([]string{"2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 1)
+	c.Assert(found[0].Result.Id, gc.Equals, "1")
+}
+
+func (s *storageMockSuite) TestRestoreVolumeSnapshots(c *gc.C) {
+	snapshots := []params.RestoreVolumeSnapshot{{Id: "1", MachineTag: "machine-3"}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "RestoreVolumeSnapshots")
+			c.Assert(a, jc.DeepEquals, params.RestoreVolumeSnapshots{Snapshots: snapshots})
+			if results, ok := result.(*params.StringResults); ok {
+				results.Results = []params.StringResult{{Result: "volume-3"}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.RestoreVolumeSnapshots(snapshots)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.StringResult{{Result: "volume-3"}})
+}
+
+func (s *storageMockSuite) TestRestoreVolumeSnapshotsFacadeCallError(c *gc.C) {
+	msg := "facade failure"
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "RestoreVolumeSnapshots")
+			return errors.New(msg)
+		})
+	storageClient := storage.NewClient(apiCaller)
+	_, err := storageClient.RestoreVolumeSnapshots(nil)
+	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
+}
+
+func (s *storageMockSuite) TestDetach(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Detach")
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{"storage-data-0"}},
+			})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.Detach([]string{"data/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *storageMockSuite) TestDetachInvalidId(c *gc.C) {
+	storageClient := storage.NewClient(basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected facade call")
+			return nil
+		}))
+	_, err := storageClient.Detach([]string{"data"})
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+}
+
+func (s *storageMockSuite) TestRemove(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Remove")
+			c.Assert(a, jc.DeepEquals, params.StorageRemovals{
+				Storage: []params.StorageRemoval{{
+					StorageTag:    "storage-data-0",
+					ReleasePolicy: "retain",
+				}},
+			})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.Remove([]string{"data/0"}, "retain")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *storageMockSuite) TestRemoveInvalidId(c *gc.C) {
+	storageClient := storage.NewClient(basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected facade call")
+			return nil
+		}))
+	_, err := storageClient.Remove([]string{"data"}, "")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+}
+
+func (s *storageMockSuite) TestAttach(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Attach")
+			c.Assert(a, jc.DeepEquals, params.StorageAttachmentIds{
+				Ids: []params.StorageAttachmentId{{
+					StorageTag: "storage-data-0",
+					UnitTag:    "unit-mysql-1",
+				}},
+			})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{
+					Error: &params.Error{Message: "storage is not detached"},
+				}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Attach("data/0", "mysql/1")
+	c.Assert(err, gc.ErrorMatches, "storage is not detached")
+}
+
+func (s *storageMockSuite) TestAttachFacadeCallError(c *gc.C) {
+	msg := "facade failure"
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "Attach")
+			return errors.New(msg)
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Attach("data/0", "mysql/1")
+	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
+}
+
+func (s *storageMockSuite) TestResize(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Resize")
+			c.Assert(a, jc.DeepEquals, params.StorageResizes{
+				Storage: []params.StorageResize{{
+					StorageTag: "storage-data-0",
+					Size:       2048,
+				}},
+			})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Resize("data/0", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageMockSuite) TestResizeInvalidStorageId(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Resize("data", 2048)
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+}
+
+func (s *storageMockSuite) TestImport(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Import")
+			c.Assert(a, jc.DeepEquals, params.StorageImports{
+				Storage: []params.StorageImport{{
+					Pool:        "ebs",
+					ProviderId:  "vol-123",
+					StorageName: "data",
+				}},
+			})
+			if results, ok := result.(*params.StringResults); ok {
+				results.Results = []params.StringResult{{Result: "storage-data-1"}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	tag, err := storageClient.Import("ebs", "vol-123", "data")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, names.NewStorageTag("data/1"))
+}
+
+func (s *storageMockSuite) TestImportError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			if results, ok := result.(*params.StringResults); ok {
+				results.Results = []params.StringResult{{
+					Error: &params.Error{Message: "volume not found", Code: params.CodeNotFound},
+				}}
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	_, err := storageClient.Import("ebs", "vol-123", "data")
+	c.Assert(err, gc.ErrorMatches, "volume not found")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
@@ -93,6 +93,24 @@ func (st *State) WatchFilesystems() (watcher.StringsWatcher, error) {
 	return st.watchStorageEntities("WatchFilesystems")
 }
 
+// WatchVolumeSnapshots watches for lifecycle changes to snapshots of
+// volumes scoped to the entity with the tag passed to NewState.
+func (st *State) WatchVolumeSnapshots() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchVolumeSnapshots")
+}
+
+// WatchVolumeResizes watches for changes to pending resizes of volumes
+// scoped to the entity with the tag passed to NewState.
+func (st *State) WatchVolumeResizes() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchVolumeResizes")
+}
+
+// WatchFilesystemResizes watches for changes to pending resizes of
+// filesystems scoped to the entity with the tag passed to NewState.
+func (st *State) WatchFilesystemResizes() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchFilesystemResizes")
+}
+
 func (st *State) watchStorageEntities(method string) (watcher.StringsWatcher, error) {
 	var results params.StringsWatchResults
 	args := params.Entities{
@@ -249,6 +267,61 @@ func (st *State) VolumeParams(tags []names.VolumeTag) ([]params.VolumeParamsResu
 	return results.Results, nil
 }
 
+// VolumeSnapshotParams returns the parameters for creating the volume
+// snapshots with the specified IDs.
+func (st *State) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	args := params.VolumeSnapshotIds{Ids: ids}
+	var results params.VolumeSnapshotParamsResults
+	err := st.facade.FacadeCall("VolumeSnapshotParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags.
+func (st *State) VolumeResizeParams(tags []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.VolumeResizeParamsResults
+	err := st.facade.FacadeCall("VolumeResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// FilesystemResizeParams returns the parameters for resizing the
+// filesystems with the specified tags.
+func (st *State) FilesystemResizeParams(tags []names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.FilesystemResizeParamsResults
+	err := st.facade.FacadeCall("FilesystemResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // FilesystemParams returns the parameters for creating the filesystems
 // with the specified tags.
 func (st *State) FilesystemParams(tags []names.FilesystemTag) ([]params.FilesystemParamsResult, error) {
@@ -313,6 +386,49 @@ func (st *State) SetVolumeInfo(volumes []params.Volume) ([]params.ErrorResult, e
 	return results.Results, nil
 }
 
+// SetVolumeSnapshotInfo records the details of newly created volume
+// snapshots, or the reason they could not be created.
+func (st *State) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+	args := params.VolumeSnapshots{VolumeSnapshots: snapshots}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeSnapshotInfo", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeResizeResults records the outcome of resizing volumes.
+func (st *State) SetVolumeResizeResults(resizes []params.VolumeResize) ([]params.ErrorResult, error) {
+	args := params.VolumeResizes{VolumeResizes: resizes}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeResizeResults", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(resizes) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(resizes), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// SetFilesystemResizeResults records the outcome of resizing filesystems.
+func (st *State) SetFilesystemResizeResults(resizes []params.FilesystemResize) ([]params.ErrorResult, error) {
+	args := params.FilesystemResizes{FilesystemResizes: resizes}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetFilesystemResizeResults", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(resizes) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(resizes), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // SetFilesystemInfo records the details of newly provisioned filesystems.
 func (st *State) SetFilesystemInfo(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
 	args := params.Filesystems{Filesystems: filesystems}
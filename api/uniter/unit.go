@@ -412,6 +412,32 @@ func (u *Unit) PrivateAddress() (string, error) {
 	return result.Result, nil
 }
 
+// EndpointAddress returns the address the unit should advertise over
+// the named relation endpoint. If the endpoint is bound to a space,
+// the address is in that space; otherwise it is the unit's private
+// address.
+func (u *Unit) EndpointAddress(endpoint string) (string, error) {
+	if u.st.facade.BestAPIVersion() < 2 {
+		return "", errors.NotImplementedf("EndpointAddress() (need V2+)")
+	}
+	var results params.StringResults
+	args := params.UnitEndpoints{
+		UnitEndpoints: []params.UnitEndpoint{{Unit: u.tag.String(), Endpoint: endpoint}},
+	}
+	err := u.st.facade.FacadeCall("EndpointAddresses", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
 // AvailabilityZone returns the availability zone of the unit.
 func (u *Unit) AvailabilityZone() (string, error) {
 	var results params.StringResults
@@ -342,6 +342,28 @@ func (s *unitSuite) TestPrivateAddress(c *gc.C) {
 	c.Assert(address, gc.Equals, "1.2.3.4")
 }
 
+func (s *unitSuite) TestEndpointAddress(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressService.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.apiUnit.EndpointAddress("db")
+	c.Assert(err, gc.ErrorMatches, `"unit-wordpress-0" has no db endpoint address set`)
+
+	err = s.wordpressMachine.SetProviderAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopeCloudLocal),
+		network.NewScopedAddress("10.0.0.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	address, err := s.apiUnit.EndpointAddress("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(address, gc.Equals, "10.0.0.4")
+}
+
 func (s *unitSuite) TestAvailabilityZone(c *gc.C) {
 	uniter.PatchUnitResponse(s, s.apiUnit, "AvailabilityZone",
 		func(result interface{}) error {
@@ -356,6 +356,9 @@ func (srv *Server) run(lis net.Listener) {
 			stateServerEnvOnly: true,
 		}},
 	)
+	handleAll(mux, "/environment/:envuuid/containers/:tag/rootfs",
+		&containerRootfsHandler{httpHandler{statePool: srv.statePool}},
+	)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	handleAll(mux, "/environment/:envuuid/images/:kind/:series/:arch/:filename",
 		&imagesDownloadHandler{
@@ -105,6 +105,19 @@ func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
 	return newServiceSetSettingsStringsForClientAPI(svc, p.Options)
 }
 
+// ServiceSetConfigProfile implements the server side of
+// Client.ServiceSetConfigProfile.
+func (c *Client) ServiceSetConfigProfile(p params.ServiceSetConfigProfile) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	svc, err := c.api.state.Service(p.ServiceName)
+	if err != nil {
+		return err
+	}
+	return svc.SetConfigProfile(p.ConfigProfile)
+}
+
 // ServiceUnset implements the server side of Client.ServiceUnset.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceUnset(p params.ServiceUnset) error {
@@ -981,6 +994,35 @@ func (c *Client) AbortCurrentUpgrade() error {
 	return c.api.state.AbortCurrentUpgrade()
 }
 
+// StartStagedUpgrade sets the environment agent version, upgrading
+// the state servers and canary machines first and the remaining
+// machines in batches.
+func (c *Client) StartStagedUpgrade(args params.StartStagedUpgrade) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.StartStagedUpgrade(args.Version, args.CanaryMachines, args.BatchSize)
+}
+
+// AbortStagedUpgrade stops the current staged agent upgrade, leaving
+// the machines not yet upgraded at the previous version.
+func (c *Client) AbortStagedUpgrade() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	message := fmt.Sprintf("aborted by %s", c.api.auth.GetAuthTag().Id())
+	return c.api.state.AbortStagedUpgrade(message)
+}
+
+// ResumeStagedUpgrade continues the current staged agent upgrade
+// after it was paused or aborted.
+func (c *Client) ResumeStagedUpgrade() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.ResumeStagedUpgrade()
+}
+
 // FindTools returns a List containing all tools matching the given parameters.
 func (c *Client) FindTools(args params.FindToolsParams) (params.FindToolsResult, error) {
 	return c.api.toolsFinder.FindTools(args)
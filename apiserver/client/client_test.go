@@ -560,6 +560,55 @@ func (s *serverSuite) TestBlockChangesAbortCurrentUpgrade(c *gc.C) {
 	s.assertAbortCurrentUpgradeBlocked(c, "TestBlockChangesAbortCurrentUpgrade")
 }
 
+func (s *serverSuite) TestStagedUpgrade(c *gc.C) {
+	var machines []*state.Machine
+	for i := 0; i < 2; i++ {
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetAgentVersion(version.Current)
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+	err := s.client.StartStagedUpgrade(params.StartStagedUpgrade{
+		Version:        version.MustParse("9.8.7"),
+		CanaryMachines: []string{machines[1].Id()},
+		BatchSize:      1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envConfig.AllAttrs()["agent-version"], gc.Equals, "9.8.7")
+	u, err := s.State.StagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Held(), jc.DeepEquals, []string{machines[0].Id()})
+
+	err = s.client.AbortStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Status(), gc.Equals, state.StagedUpgradeAborted)
+	c.Assert(u.Message(), gc.Matches, "aborted by admin.*")
+
+	err = s.client.ResumeStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Status(), gc.Equals, state.StagedUpgradeRunning)
+}
+
+func (s *serverSuite) TestBlockChangesStagedUpgrade(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockChangesStagedUpgrade")
+	err := s.client.StartStagedUpgrade(params.StartStagedUpgrade{
+		Version:   version.MustParse("9.8.7"),
+		BatchSize: 1,
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesStagedUpgrade")
+	err = s.client.AbortStagedUpgrade()
+	s.AssertBlocked(c, err, "TestBlockChangesStagedUpgrade")
+	err = s.client.ResumeStagedUpgrade()
+	s.AssertBlocked(c, err, "TestBlockChangesStagedUpgrade")
+}
+
 type clientSuite struct {
 	baseSuite
 }
@@ -647,6 +696,27 @@ func (s *clientSuite) TestClientServiceSet(c *gc.C) {
 	})
 }
 
+func (s *clientSuite) TestClientServiceSetConfigProfile(c *gc.C) {
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{"title": "Production"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().ServiceSetConfigProfile("dummy", "production")
+	c.Assert(err, jc.ErrorIsNil)
+	profile, err := dummy.ConfigProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, gc.Equals, "production")
+
+	err = s.APIState.Client().ServiceSetConfigProfile("dummy", "")
+	c.Assert(err, jc.ErrorIsNil)
+	profile, err = dummy.ConfigProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, gc.Equals, "")
+
+	err = s.APIState.Client().ServiceSetConfigProfile("dummy", "staging")
+	c.Assert(err, gc.ErrorMatches, `cannot set config profile for service "dummy": config profile "staging" not found`)
+}
+
 func (s *serverSuite) assertServiceSetBlocked(c *gc.C, dummy *state.Service, msg string) {
 	err := s.client.ServiceSet(params.ServiceSet{
 		ServiceName: "dummy",
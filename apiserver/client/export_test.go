@@ -21,3 +21,9 @@ var (
 )
 
 type MachineAndContainers machineAndContainers
+
+// Upgrade check exports
+var (
+	DiskSpaceMaxAge  = &diskSpaceMaxAge
+	ReplicaSetStatus = &replicaSetStatus
+)
@@ -24,7 +24,15 @@ func (c *Client) ServiceGet(args params.ServiceGet) (params.ServiceGetResults, e
 	if err != nil {
 		return params.ServiceGetResults{}, err
 	}
-	configInfo := describe(settings, charm.Config())
+	profile, err := service.ConfigProfile()
+	if err != nil {
+		return params.ServiceGetResults{}, err
+	}
+	profileSettings, err := service.ConfigProfileSettings()
+	if err != nil {
+		return params.ServiceGetResults{}, err
+	}
+	configInfo := describe(settings, profileSettings, charm.Config())
 	var constraints constraints.Value
 	if service.IsPrincipal() {
 		constraints, err = service.Constraints()
@@ -33,14 +41,20 @@ func (c *Client) ServiceGet(args params.ServiceGet) (params.ServiceGetResults, e
 		}
 	}
 	return params.ServiceGetResults{
-		Service:     args.ServiceName,
-		Charm:       charm.Meta().Name,
-		Config:      configInfo,
-		Constraints: constraints,
+		Service:       args.ServiceName,
+		Charm:         charm.Meta().Name,
+		ConfigProfile: profile,
+		Config:        configInfo,
+		Constraints:   constraints,
 	}, nil
 }
 
-func describe(settings charm.Settings, config *charm.Config) map[string]interface{} {
+// describe returns the description of each of the charm's options,
+// with its value taken from the service's settings if set there, or
+// else from its config profile settings, or else the charm default.
+// Values from the profile are marked with "profile", and charm defaults
+// with "default".
+func describe(settings, profileSettings charm.Settings, config *charm.Config) map[string]interface{} {
 	results := make(map[string]interface{})
 	for name, option := range config.Options {
 		info := map[string]interface{}{
@@ -49,6 +63,9 @@ func describe(settings charm.Settings, config *charm.Config) map[string]interfac
 		}
 		if value := settings[name]; value != nil {
 			info["value"] = value
+		} else if value := profileSettings[name]; value != nil {
+			info["value"] = value
+			info["profile"] = true
 		} else {
 			if option.Default != nil {
 				info["value"] = option.Default
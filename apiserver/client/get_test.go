@@ -165,6 +165,47 @@ func (s *getSuite) TestServiceGet(c *gc.C) {
 	}
 }
 
+func (s *getSuite) TestServiceGetConfigProfile(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	svc := s.AddTestingService(c, "test-service", ch)
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{
+		"title":    "Production",
+		"username": "prod-admin",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.SetConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.UpdateConfigSettings(charm.Settings{"username": "foobie"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.APIState.Client().ServiceGet(svc.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.ConfigProfile, gc.Equals, "production")
+	c.Assert(got.Config, gc.DeepEquals, map[string]interface{}{
+		"title": map[string]interface{}{
+			"description": "A descriptive title used for the service.",
+			"type":        "string",
+			"value":       "Production",
+			"profile":     true,
+		},
+		"outlook": map[string]interface{}{
+			"description": "No default outlook.",
+			"type":        "string",
+			"default":     true,
+		},
+		"username": map[string]interface{}{
+			"description": "The name of the initial account (given admin permissions).",
+			"type":        "string",
+			"value":       "foobie",
+		},
+		"skill-level": map[string]interface{}{
+			"description": "A number indicating skill.",
+			"type":        "int",
+			"default":     true,
+		},
+	})
+}
+
 func (s *getSuite) TestServiceGetMaxResolutionInt(c *gc.C) {
 	// See the bug http://pad.lv/1217742
 	// ServiceGet ends up pushing a map[string]interface{} which containts
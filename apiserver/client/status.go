@@ -13,8 +13,10 @@ import (
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/hooks"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -118,6 +120,8 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		return noStatus, errors.Annotate(err, "could not fetch relations")
 	} else if context.networks, err = fetchNetworks(c.api.state); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch networks")
+	} else if context.leaders, err = c.api.state.LeadershipReader().Leaders(); err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch service leaders")
 	}
 
 	logger.Debugf("Services: %v", context.services)
@@ -193,12 +197,39 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		}
 	}
 
+	stagedUpgrade, err := processStagedUpgrade(c.api.state)
+	if err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch staged upgrade")
+	}
+
 	return params.FullStatus{
 		EnvironmentName: cfg.Name(),
 		Machines:        processMachines(context.machines),
 		Services:        context.processServices(),
 		Networks:        context.processNetworks(),
 		Relations:       context.processRelations(),
+		StagedUpgrade:   stagedUpgrade,
+	}, nil
+}
+
+// processStagedUpgrade returns the progress of the environment's
+// staged agent upgrade, or nil if there is none.
+func processStagedUpgrade(st *state.State) (*params.StagedUpgradeStatus, error) {
+	u, err := st.StagedUpgrade()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.StagedUpgradeStatus{
+		PreviousVersion: u.PreviousVersion().String(),
+		Version:         u.Version().String(),
+		CanaryMachines:  u.Canaries(),
+		BatchSize:       u.BatchSize(),
+		Batch:           u.Batch(),
+		Held:            u.Held(),
+		Status:          string(u.Status()),
+		Message:         u.Message(),
 	}, nil
 }
 
@@ -229,6 +260,8 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// leaders: service name -> current leader
+	leaders map[string]leadership.Leader
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -241,24 +274,41 @@ func fetchMachines(st *state.State, machineIds set.Strings) (map[string][]*state
 	if err != nil {
 		return nil, err
 	}
+	byId := make(map[string]*state.Machine)
+	var containers []*state.Machine
 	// AllMachines gives us machines sorted by id.
 	for _, m := range machines {
+		byId[m.Id()] = m
 		if machineIds != nil && !machineIds.Contains(m.Id()) {
 			continue
 		}
-		parentId, ok := m.ParentId()
-		if !ok {
-			// Only top level host machines go directly into the machine map.
-			v[m.Id()] = []*state.Machine{m}
-		} else {
-			topParentId := state.TopParentId(m.Id())
-			machines, ok := v[topParentId]
+		if _, ok := m.ParentId(); ok {
+			containers = append(containers, m)
+			continue
+		}
+		// Only top level host machines go directly into the machine map.
+		v[m.Id()] = []*state.Machine{m}
+	}
+	// Containers are listed under the top level machine hosting them,
+	// which differs from the one in their id if they have been migrated.
+	for _, m := range containers {
+		parentId, _ := m.ParentId()
+		topParentId := parentId
+		for {
+			host, ok := byId[topParentId]
 			if !ok {
 				panic(fmt.Errorf("unexpected machine id %q", parentId))
 			}
-			machines = append(machines, m)
-			v[topParentId] = machines
+			hostParentId, isContainer := host.ParentId()
+			if !isContainer {
+				break
+			}
+			topParentId = hostParentId
+		}
+		if _, ok := v[topParentId]; !ok {
+			v[topParentId] = []*state.Machine{byId[topParentId]}
 		}
+		v[topParentId] = append(v[topParentId], m)
 	}
 	return v, nil
 }
@@ -311,8 +361,8 @@ func fetchAllServicesAndUnits(
 }
 
 // fetchUnitMachineIds returns a set of IDs for machines that
-// the specified units reside on, and those machines' ancestors.
-func fetchUnitMachineIds(units map[string]map[string]*state.Unit) (set.Strings, error) {
+// the specified units reside on, and the machines hosting them.
+func fetchUnitMachineIds(st *state.State, units map[string]map[string]*state.Unit) (set.Strings, error) {
 	machineIds := make(set.Strings)
 	for _, svcUnitMap := range units {
 		for _, unit := range svcUnitMap {
@@ -325,7 +375,7 @@ func fetchUnitMachineIds(units map[string]map[string]*state.Unit) (set.Strings,
 			}
 			for mid != "" {
 				machineIds.Add(mid)
-				mid = state.ParentId(mid)
+				mid = common.ContainerHostId(st, mid)
 			}
 		}
 	}
@@ -390,15 +440,26 @@ func processMachines(idToMachines map[string][]*state.Machine) map[string]params
 		machinesMap[id] = hostStatus
 		cache[id] = hostStatus
 
-		for _, machine := range machines[1:] {
-			parent, ok := cache[state.ParentId(machine.Id())]
-			if !ok {
+		// Containers are sorted by id, but a migrated container may be
+		// hosted by one that sorts after it.
+		pending := machines[1:]
+		for len(pending) > 0 {
+			var deferred []*state.Machine
+			for _, machine := range pending {
+				parentId, _ := machine.ParentId()
+				parent, ok := cache[parentId]
+				if !ok {
+					deferred = append(deferred, machine)
+					continue
+				}
+				status := makeMachineStatus(machine)
+				parent.Containers[machine.Id()] = status
+				cache[machine.Id()] = status
+			}
+			if len(deferred) == len(pending) {
 				panic("We've broken an assumpution.")
 			}
-
-			status := makeMachineStatus(machine)
-			parent.Containers[machine.Id()] = status
-			cache[machine.Id()] = status
+			pending = deferred
 		}
 	}
 	return machinesMap
@@ -420,6 +481,7 @@ func makeMachineStatus(machine *state.Machine) (status params.MachineStatus) {
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
 	status.WantsVote = machine.WantsVote()
 	status.HasVote = machine.HasVote()
+	status.Cordoned = machine.Cordoned()
 	instid, err := machine.InstanceId()
 	if err == nil {
 		status.InstanceId = instid
@@ -447,6 +509,9 @@ func makeMachineStatus(machine *state.Machine) (status params.MachineStatus) {
 		}
 	} else {
 		status.Hardware = hc.String()
+		if hc.AvailabilityZone != nil {
+			status.AvailabilityZone = *hc.AvailabilityZone
+		}
 	}
 	status.Containers = make(map[string]params.MachineStatus)
 	return
@@ -553,6 +618,16 @@ func (context *statusContext) processService(service *state.Service) (status par
 	if ok && latestCharm != serviceCharmURL.String() {
 		status.CanUpgradeTo = latestCharm
 	}
+	if upgrade, ok := service.RollingUpgrade(); ok {
+		status.RollingUpgrade = &params.RollingUpgradeStatus{
+			PreviousCharm: upgrade.PreviousCharmURL.String(),
+			BatchSize:     upgrade.BatchSize,
+			Batch:         upgrade.Batch,
+			Held:          upgrade.Held,
+			Status:        string(upgrade.Status),
+			Message:       upgrade.Message,
+		}
+	}
 	var err error
 	status.Relations, status.SubordinateTo, err = context.processServiceRelations(service)
 	if err != nil {
@@ -645,6 +720,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		result.Charm = curl.String()
 	}
 	processUnitAndAgentStatus(unit, &result)
+	context.processUnitLeadership(unit, &result)
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		result.Subordinates = make(map[string]params.UnitStatus)
@@ -659,6 +735,18 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	return result
 }
 
+// processUnitLeadership marks the unit as leader, and records the lease
+// expiry time, if it currently holds leadership of its service.
+func (context *statusContext) processUnitLeadership(unit *state.Unit, status *params.UnitStatus) {
+	leader, ok := context.leaders[unit.ServiceName()]
+	if !ok || leader.UnitName != unit.Name() {
+		return
+	}
+	expiry := leader.Expiry
+	status.Leader = true
+	status.LeaderExpiry = &expiry
+}
+
 func (context *statusContext) unitByName(name string) *state.Unit {
 	serviceName := strings.Split(name, "/")[0]
 	return context.units[serviceName][name]
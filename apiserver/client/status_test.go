@@ -4,6 +4,8 @@
 package client_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -164,3 +166,27 @@ func (s *statusUnitTestSuite) TestMeterStatus(c *gc.C) {
 		}
 	}
 }
+
+func (s *statusUnitTestSuite) TestLeadership(c *gc.C) {
+	service := s.MakeService(c, nil)
+	leader := s.MakeUnit(c, &factory.UnitParams{Service: service})
+	follower := s.MakeUnit(c, &factory.UnitParams{Service: service})
+
+	err := s.State.LeadershipClaimer().ClaimLeadership(service.Name(), leader.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	serviceStatus, ok := status.Services[service.Name()]
+	c.Assert(ok, jc.IsTrue)
+
+	leaderStatus := serviceStatus.Units[leader.Name()]
+	c.Check(leaderStatus.Leader, jc.IsTrue)
+	c.Assert(leaderStatus.LeaderExpiry, gc.NotNil)
+	c.Check(leaderStatus.LeaderExpiry.After(time.Now()), jc.IsTrue)
+
+	followerStatus := serviceStatus.Units[follower.Name()]
+	c.Check(followerStatus.Leader, jc.IsFalse)
+	c.Check(followerStatus.LeaderExpiry, gc.IsNil)
+}
@@ -0,0 +1,244 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/toolstorage"
+	"github.com/juju/juju/upgrades"
+	"github.com/juju/juju/version"
+)
+
+var replicaSetStatus = replicaset.CurrentStatus
+
+// diskSpaceMaxAge is how old the free space recorded for a state
+// server may be before it is reported as a problem. State servers
+// record their free space every few minutes.
+var diskSpaceMaxAge = 15 * time.Minute
+
+// UpgradeCheck reports on the readiness of the environment for an
+// agent upgrade to the given version, without changing anything.
+func (c *Client) UpgradeCheck(args params.UpgradeCheck) (params.UpgradeCheckResult, error) {
+	st := c.api.state
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return params.UpgradeCheckResult{}, errors.Trace(err)
+	}
+	current, ok := cfg.AgentVersion()
+	if !ok {
+		return params.UpgradeCheckResult{}, errors.New("no agent version set in the environment")
+	}
+	result := params.UpgradeCheckResult{
+		CurrentVersion: current,
+		Version:        args.Version,
+		ServerVersion:  version.Current.Number,
+		UpgradeSteps:   upgrades.StepDescriptions(current, args.Version),
+	}
+	machines, err := st.AllMachines()
+	if err != nil {
+		return params.UpgradeCheckResult{}, errors.Trace(err)
+	}
+	if result.ProblemAgents, err = checkAgents(st, machines); err != nil {
+		return params.UpgradeCheckResult{}, errors.Trace(err)
+	}
+	if result.Tools, err = c.checkTools(machines, args.Version); err != nil {
+		return params.UpgradeCheckResult{}, errors.Trace(err)
+	}
+	result.ReplicaSet, result.ReplicaSetError = checkReplicaSet(st)
+	if result.DiskSpace, err = checkDiskSpace(st); err != nil {
+		return params.UpgradeCheckResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// checkAgents returns the machine and unit agents that are down or
+// in error.
+func checkAgents(st *state.State, machines []*state.Machine) ([]params.UpgradeCheckAgent, error) {
+	var problems []params.UpgradeCheckAgent
+	check := func(tag string, status state.StatusInfo, alive bool) {
+		// Agents that have not yet started are not expected to be
+		// alive.
+		started := status.Status != state.StatusPending && status.Status != state.StatusAllocating
+		down := started && !alive
+		if down || status.Status == state.StatusError {
+			problems = append(problems, params.UpgradeCheckAgent{
+				Tag:     tag,
+				Status:  params.Status(status.Status),
+				Message: status.Message,
+				Down:    down,
+			})
+		}
+	}
+	for _, m := range machines {
+		if m.Life() == state.Dead {
+			continue
+		}
+		status, err := m.Status()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		alive, err := m.AgentPresence()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		check(m.Tag().String(), status, alive)
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, svc := range services {
+		units, err := svc.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, u := range units {
+			if u.Life() == state.Dead {
+				continue
+			}
+			// Hook errors are recorded in the workload status.
+			status, err := u.Status()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if status.Status != state.StatusError {
+				if status, err = u.AgentStatus(); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+			alive, err := u.AgentPresence()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			check(u.Tag().String(), status, alive)
+		}
+	}
+	return problems, nil
+}
+
+// checkTools reports where tools for the given version can be found,
+// for each series and architecture of the environment's machines.
+func (c *Client) checkTools(machines []*state.Machine, vers version.Number) ([]params.UpgradeCheckTools, error) {
+	storage, err := c.api.state.ToolsStorage()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer storage.Close()
+
+	var results []params.UpgradeCheckTools
+	seen := make(map[version.Binary]bool)
+	for _, m := range machines {
+		arch := ""
+		if tools, err := m.AgentTools(); err == nil {
+			arch = tools.Version.Arch
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		} else if hc, err := m.HardwareCharacteristics(); err == nil && hc.Arch != nil {
+			arch = *hc.Arch
+		}
+		if arch == "" {
+			// The machine has not yet been provisioned.
+			continue
+		}
+		binary := version.Binary{Number: vers, Series: m.Series(), Arch: arch}
+		if seen[binary] {
+			continue
+		}
+		seen[binary] = true
+		results = append(results, c.checkToolsFor(storage, binary))
+	}
+	return results, nil
+}
+
+// checkToolsFor reports where tools for the given binary version can
+// be found: tool storage is checked first, then simplestreams.
+func (c *Client) checkToolsFor(storage toolstorage.Storage, binary version.Binary) params.UpgradeCheckTools {
+	result := params.UpgradeCheckTools{
+		Series: binary.Series,
+		Arch:   binary.Arch,
+	}
+	if _, err := storage.Metadata(binary); err == nil {
+		result.Source = "tool storage"
+		return result
+	} else if !errors.IsNotFound(err) {
+		result.Error = err.Error()
+		return result
+	}
+	found, err := c.api.toolsFinder.FindTools(params.FindToolsParams{
+		Number:       binary.Number,
+		MajorVersion: binary.Major,
+		MinorVersion: binary.Minor,
+		Series:       binary.Series,
+		Arch:         binary.Arch,
+	})
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case found.Error == nil:
+		result.Source = "simplestreams"
+	case !params.IsCodeNotFound(found.Error):
+		result.Error = found.Error.Error()
+	}
+	return result
+}
+
+// checkReplicaSet reports the health of the mongo replica set members,
+// or why it could not be queried.
+func checkReplicaSet(st *state.State) ([]params.UpgradeCheckMember, string) {
+	status, err := replicaSetStatus(st.MongoSession())
+	if err != nil {
+		return nil, err.Error()
+	}
+	members := make([]params.UpgradeCheckMember, len(status.Members))
+	for i, m := range status.Members {
+		members[i] = params.UpgradeCheckMember{
+			Address: m.Address,
+			State:   m.State.String(),
+			Healthy: m.Healthy,
+			Message: m.ErrMsg,
+		}
+	}
+	return members, ""
+}
+
+// checkDiskSpace reports the free space recorded for each state
+// server's data directory, where the backup taken during the upgrade
+// is written. Missing or out of date records are reported as errors.
+func checkDiskSpace(st *state.State) ([]params.UpgradeCheckDiskSpace, error) {
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	recorded, err := st.StateServerDiskSpace()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	byMachine := make(map[string]state.StateServerDiskSpace)
+	for _, space := range recorded {
+		byMachine[space.MachineId] = space
+	}
+	results := make([]params.UpgradeCheckDiskSpace, len(info.MachineIds))
+	for i, id := range info.MachineIds {
+		results[i].MachineId = id
+		space, ok := byMachine[id]
+		if !ok {
+			results[i].Error = "free space not recorded"
+			continue
+		}
+		results[i].Path = space.Path
+		results[i].Free = space.Free
+		results[i].Updated = space.Updated
+		if time.Since(space.Updated) > diskSpaceMaxAge {
+			results[i].Error = fmt.Sprintf("free space not recorded since %s", space.Updated.Format(time.RFC3339))
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/juju/replicaset"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/toolstorage"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+	"github.com/juju/juju/version"
+)
+
+type upgradeCheckSuite struct {
+	baseSuite
+	client *client.Client
+	next   version.Number
+}
+
+var _ = gc.Suite(&upgradeCheckSuite{})
+
+func (s *upgradeCheckSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	auth := testing.FakeAuthorizer{
+		Tag:            s.AdminUserTag(c),
+		EnvironManager: true,
+	}
+	var err error
+	s.client, err = client.NewClient(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.next = version.Current.Number
+	s.next.Patch++
+	s.PatchValue(client.ReplicaSetStatus, func(*mgo.Session) (*replicaset.Status, error) {
+		return &replicaset.Status{
+			Members: []replicaset.MemberStatus{{
+				Address: "10.0.0.1:37017",
+				State:   replicaset.PrimaryState,
+				Healthy: true,
+			}, {
+				Address: "10.0.0.2:37017",
+				State:   replicaset.UnknownState,
+				ErrMsg:  "no route to host",
+			}},
+		}, nil
+	})
+}
+
+// addStartedMachine adds a machine whose agent is running the current
+// version of the tools for the given architecture.
+func (s *upgradeCheckSuite) addStartedMachine(c *gc.C, arch string) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	vers := version.Current
+	vers.Series = "quantal"
+	vers.Arch = arch
+	err = m.SetAgentVersion(vers)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *upgradeCheckSuite) TestUpgradeCheck(c *gc.C) {
+	stateServers := s.recordDiskSpace(c)
+	started := s.addStartedMachine(c, "amd64")
+	pinger, err := started.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	defer pinger.Kill()
+	s.addStartedMachine(c, "arm64")
+	failed := s.addStartedMachine(c, "amd64")
+	err = failed.SetStatus(state.StatusError, "boom", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// Machines not yet started are neither down nor checked for tools.
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	storage, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	err = storage.AddTools(strings.NewReader("tools"), toolstorage.Metadata{
+		Version: version.Binary{Number: s.next, Series: "quantal", Arch: "amd64"},
+		Size:    5,
+		SHA256:  "fake",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.State.StartSync()
+	err = started.WaitAgentPresence(coretesting.LongWait)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.client.UpgradeCheck(params.UpgradeCheck{Version: s.next})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.CurrentVersion, gc.Equals, version.Current.Number)
+	c.Assert(result.Version, gc.Equals, s.next)
+	c.Assert(result.ProblemAgents, jc.DeepEquals, []params.UpgradeCheckAgent{{
+		Tag:    "machine-1",
+		Status: params.StatusStarted,
+		Down:   true,
+	}, {
+		Tag:     "machine-2",
+		Status:  params.StatusError,
+		Message: "boom",
+		Down:    true,
+	}})
+	c.Assert(result.Tools, jc.DeepEquals, []params.UpgradeCheckTools{{
+		Series: "quantal",
+		Arch:   "amd64",
+		Source: "tool storage",
+	}, {
+		Series: "quantal",
+		Arch:   "arm64",
+	}})
+	c.Assert(result.ReplicaSet, jc.DeepEquals, []params.UpgradeCheckMember{{
+		Address: "10.0.0.1:37017",
+		State:   "PRIMARY",
+		Healthy: true,
+	}, {
+		Address: "10.0.0.2:37017",
+		State:   "UNKNOWN",
+		Message: "no route to host",
+	}})
+	c.Assert(result.ReplicaSetError, gc.Equals, "")
+	c.Assert(result.ServerVersion, gc.Equals, version.Current.Number)
+	c.Assert(result.UpgradeSteps, jc.DeepEquals, upgrades.StepDescriptions(version.Current.Number, s.next))
+	c.Assert(result.DiskSpace, gc.HasLen, len(stateServers))
+	for i, space := range result.DiskSpace {
+		c.Check(space.MachineId, gc.Equals, stateServers[i])
+		c.Check(space.Path, gc.Equals, "/var/lib/juju")
+		c.Check(space.Free, gc.Equals, uint64(1234+i))
+		c.Check(space.Error, gc.Equals, "")
+	}
+}
+
+// recordDiskSpace records free space for each state server, and
+// returns their machine ids.
+func (s *upgradeCheckSuite) recordDiskSpace(c *gc.C) []string {
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.MachineIds, gc.Not(gc.HasLen), 0)
+	for i, id := range info.MachineIds {
+		err := s.State.SetStateServerDiskSpace(id, "/var/lib/juju", uint64(1234+i))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return info.MachineIds
+}
+
+func (s *upgradeCheckSuite) TestUpgradeCheckReportsErrors(c *gc.C) {
+	s.PatchValue(client.ReplicaSetStatus, func(*mgo.Session) (*replicaset.Status, error) {
+		return nil, errors.New("not running with replica set")
+	})
+	result, err := s.client.UpgradeCheck(params.UpgradeCheck{Version: s.next})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.ReplicaSet, gc.HasLen, 0)
+	c.Assert(result.ReplicaSetError, gc.Equals, "not running with replica set")
+	c.Assert(result.DiskSpace, gc.Not(gc.HasLen), 0)
+	for _, space := range result.DiskSpace {
+		c.Check(space.Error, gc.Equals, "free space not recorded")
+	}
+}
+
+func (s *upgradeCheckSuite) TestUpgradeCheckReportsStaleDiskSpace(c *gc.C) {
+	s.recordDiskSpace(c)
+	s.PatchValue(client.DiskSpaceMaxAge, -time.Hour)
+	result, err := s.client.UpgradeCheck(params.UpgradeCheck{Version: s.next})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.DiskSpace, gc.Not(gc.HasLen), 0)
+	for _, space := range result.DiskSpace {
+		c.Check(space.Error, gc.Matches, "free space not recorded since .*")
+		c.Check(space.Free, gc.Not(gc.Equals), uint64(0))
+	}
+}
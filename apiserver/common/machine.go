@@ -39,3 +39,21 @@ func machineJobFromParams(job multiwatcher.MachineJob) (state.MachineJob, error)
 		return -1, errors.Errorf("invalid machine job %q", job)
 	}
 }
+
+// ContainerHostId returns the id of the machine currently hosting the
+// machine with the given id, or "" if it is not a container. Containers
+// that have been migrated are hosted by a machine other than the parent
+// encoded in their id.
+func ContainerHostId(st *state.State, machineId string) string {
+	parentId := state.ParentId(machineId)
+	if parentId == "" {
+		return ""
+	}
+	m, err := st.Machine(machineId)
+	if err != nil {
+		// Let the caller report the machine as missing.
+		return parentId
+	}
+	hostId, _ := m.ParentId()
+	return hostId
+}
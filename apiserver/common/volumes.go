@@ -65,9 +65,81 @@ func VolumeParams(
 		cfg.Attrs(),
 		volumeTags,
 		nil, // attachment params set by the caller
+		"",  // snapshot ID set by the caller
 	}, nil
 }
 
+// VolumeSnapshotId returns the provider ID of the snapshot from which
+// the given volume is to be created, or "" if the volume is not to be
+// created from a snapshot.
+func VolumeSnapshotId(
+	v state.Volume,
+	getVolumeSnapshot func(string) (state.VolumeSnapshot, error),
+) (string, error) {
+	stateVolumeParams, ok := v.Params()
+	if !ok || stateVolumeParams.Snapshot == "" {
+		return "", nil
+	}
+	snapshot, err := getVolumeSnapshot(stateVolumeParams.Snapshot)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	info, err := snapshot.Info()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return info.SnapshotId, nil
+}
+
+// VolumeSnapshotParams returns the parameters for creating the given
+// volume snapshot.
+func VolumeSnapshotParams(
+	s state.VolumeSnapshot,
+	environConfig *config.Config,
+	poolManager poolmanager.PoolManager,
+) (params.VolumeSnapshotParams, error) {
+	snapshotTags, err := storageTags(nil, environConfig)
+	if err != nil {
+		return params.VolumeSnapshotParams{}, errors.Annotate(err, "computing storage tags")
+	}
+	providerType, cfg, err := StoragePoolConfig(s.Pool(), poolManager)
+	if err != nil {
+		return params.VolumeSnapshotParams{}, errors.Trace(err)
+	}
+	return params.VolumeSnapshotParams{
+		Id:         s.Id(),
+		VolumeTag:  s.Volume().String(),
+		VolumeId:   s.VolumeId(),
+		Provider:   string(providerType),
+		Attributes: cfg.Attrs(),
+		Tags:       snapshotTags,
+	}, nil
+}
+
+// VolumeSnapshotFromState converts a state.VolumeSnapshot to
+// params.VolumeSnapshotInstance.
+func VolumeSnapshotFromState(s state.VolumeSnapshot) params.VolumeSnapshotInstance {
+	result := params.VolumeSnapshotInstance{
+		Id:        s.Id(),
+		VolumeTag: s.Volume().String(),
+		Pool:      s.Pool(),
+		Size:      s.Size(),
+		Created:   s.Created(),
+		Status:    "pending",
+	}
+	if info, err := s.Info(); err == nil {
+		result.SnapshotId = info.SnapshotId
+		if info.Size != 0 {
+			result.Size = info.Size
+		}
+		result.Status = "created"
+	} else if message := s.Error(); message != "" {
+		result.Status = "error"
+		result.Message = message
+	}
+	return result
+}
+
 // StoragePoolConfig returns the storage provider type and
 // configuration for a named storage pool. If there is no
 // such pool with the specified name, but it identifies a
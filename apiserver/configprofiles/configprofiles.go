@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package configprofiles contains the implementation of an api endpoint
+// for managing the environment's charm config profiles.
+package configprofiles
+
+import (
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ConfigProfiles", 1, NewConfigProfilesAPI)
+}
+
+// ConfigProfiles defines the methods on the configprofiles API end point.
+type ConfigProfiles interface {
+	AddConfigProfiles(args params.AddConfigProfiles) (params.ErrorResults, error)
+	ListConfigProfiles() (params.ListConfigProfilesResults, error)
+	ShowConfigProfiles(args params.ConfigProfileNames) (params.ConfigProfileResults, error)
+}
+
+// ConfigProfilesAPI implements the ConfigProfiles interface and is the
+// concrete implementation of the api end point.
+type ConfigProfilesAPI struct {
+	state *state.State
+	check *common.BlockChecker
+}
+
+var _ ConfigProfiles = (*ConfigProfilesAPI)(nil)
+
+// NewConfigProfilesAPI creates a new API endpoint for managing config
+// profiles.
+func NewConfigProfilesAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*ConfigProfilesAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &ConfigProfilesAPI{
+		state: st,
+		check: common.NewBlockChecker(st),
+	}, nil
+}
+
+// AddConfigProfiles adds the specified config profiles to the
+// environment.
+func (api *ConfigProfilesAPI) AddConfigProfiles(args params.AddConfigProfiles) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Profiles)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Profiles {
+		err := api.addConfigProfile(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *ConfigProfilesAPI) addConfigProfile(arg params.AddConfigProfile) error {
+	var settings map[string]interface{}
+	if err := goyaml.Unmarshal([]byte(arg.SettingsYAML), &settings); err != nil {
+		return errors.Annotatef(err, "cannot parse settings for config profile %q", arg.Name)
+	}
+	_, err := api.state.AddConfigProfile(arg.Name, settings)
+	return errors.Trace(err)
+}
+
+// ListConfigProfiles returns all of the environment's config profiles,
+// ordered by name.
+func (api *ConfigProfilesAPI) ListConfigProfiles() (params.ListConfigProfilesResults, error) {
+	profiles, err := api.state.AllConfigProfiles()
+	if err != nil {
+		return params.ListConfigProfilesResults{}, errors.Trace(err)
+	}
+	results := params.ListConfigProfilesResults{
+		Profiles: make([]params.ConfigProfile, len(profiles)),
+	}
+	for i, profile := range profiles {
+		results.Profiles[i] = params.ConfigProfile{
+			Name:     profile.Name(),
+			Settings: profile.Settings(),
+		}
+	}
+	return results, nil
+}
+
+// ShowConfigProfiles returns the config profiles with the given names.
+func (api *ConfigProfilesAPI) ShowConfigProfiles(args params.ConfigProfileNames) (params.ConfigProfileResults, error) {
+	results := params.ConfigProfileResults{
+		Results: make([]params.ConfigProfileResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		profile, err := api.state.ConfigProfile(name)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = &params.ConfigProfile{
+			Name:     profile.Name(),
+			Settings: profile.Settings(),
+		}
+	}
+	return results, nil
+}
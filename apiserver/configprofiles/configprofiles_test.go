@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofiles_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/configprofiles"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type configProfilesSuite struct {
+	jujutesting.JujuConnSuite
+
+	api *configprofiles.ConfigProfilesAPI
+}
+
+var _ = gc.Suite(&configProfilesSuite{})
+
+func (s *configProfilesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	api, err := configprofiles.NewConfigProfilesAPI(s.State, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *configProfilesSuite) TestNewConfigProfilesAPIRefusesNonClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	}
+	_, err := configprofiles.NewConfigProfilesAPI(s.State, nil, authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *configProfilesSuite) TestAddConfigProfiles(c *gc.C) {
+	_, err := s.State.AddConfigProfile("staging", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.AddConfigProfiles(params.AddConfigProfiles{
+		Profiles: []params.AddConfigProfile{{
+			Name:         "production",
+			SettingsYAML: "title: Production\nskill-level: 9\n",
+		}, {
+			Name: "staging",
+		}, {
+			Name:         "broken",
+			SettingsYAML: "title: [a, b]",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add config profile "staging": config profile "staging" already exists`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `cannot add config profile "broken": value of "title" must be a string, number or boolean, got \[\]interface \{\}`)
+
+	profile, err := s.State.ConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile.Settings(), jc.DeepEquals, map[string]interface{}{
+		"title":       "Production",
+		"skill-level": 9,
+	})
+}
+
+func (s *configProfilesSuite) TestListConfigProfiles(c *gc.C) {
+	_, err := s.State.AddConfigProfile("staging", map[string]interface{}{"title": "Staging"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddConfigProfile("production", map[string]interface{}{"title": "Production"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListConfigProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ListConfigProfilesResults{
+		Profiles: []params.ConfigProfile{{
+			Name:     "production",
+			Settings: map[string]interface{}{"title": "Production"},
+		}, {
+			Name:     "staging",
+			Settings: map[string]interface{}{"title": "Staging"},
+		}},
+	})
+}
+
+func (s *configProfilesSuite) TestShowConfigProfiles(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{"title": "Production"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ShowConfigProfiles(params.ConfigProfileNames{
+		Names: []string{"production", "staging"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0], jc.DeepEquals, params.ConfigProfileResult{
+		Result: &params.ConfigProfile{
+			Name:     "production",
+			Settings: map[string]interface{}{"title": "Production"},
+		},
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `config profile "staging" not found`)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+}
@@ -0,0 +1,162 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package containermigrator implements the API used by host machine
+// agents to move containers between them.
+package containermigrator
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+	"github.com/juju/juju/state/watcher"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.containermigrator")
+
+func init() {
+	common.RegisterStandardFacade("ContainerMigrator", 1, NewContainerMigratorAPI)
+}
+
+// ContainerMigratorAPI provides access to the ContainerMigrator API
+// facade.
+type ContainerMigratorAPI struct {
+	st        *state.State
+	resources *common.Resources
+	hostId    string
+}
+
+// NewContainerMigratorAPI creates a new server-side ContainerMigrator
+// API facade.
+func NewContainerMigratorAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ContainerMigratorAPI, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	tag, ok := authorizer.GetAuthTag().(names.MachineTag)
+	if !ok {
+		return nil, errors.Errorf("expected names.MachineTag, got %T", authorizer.GetAuthTag())
+	}
+	return &ContainerMigratorAPI{
+		st:        st,
+		resources: resources,
+		hostId:    tag.Id(),
+	}, nil
+}
+
+// WatchContainerMigrations returns a NotifyWatcher that notifies of
+// changes to container migrations.
+func (api *ContainerMigratorAPI) WatchContainerMigrations() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	watch := api.st.WatchContainerMigrations()
+	// Consume the initial event. Technically, API
+	// calls to Watch 'transmit' the initial event
+	// in the Watch response. But NotifyWatchers
+	// have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		result.Error = common.ServerError(watcher.EnsureErr(watch))
+	}
+	return result, nil
+}
+
+// ContainerMigrations returns the active migrations of containers to or
+// from the authenticated host machine.
+func (api *ContainerMigratorAPI) ContainerMigrations() (params.ContainerMigrationsResult, error) {
+	var result params.ContainerMigrationsResult
+	migrations, err := api.st.HostContainerMigrations(api.hostId)
+	if err != nil {
+		result.Error = common.ServerError(err)
+		return result, nil
+	}
+	result.Migrations = make([]params.ContainerMigration, 0, len(migrations))
+	for _, migration := range migrations {
+		container, err := api.st.Machine(migration.ContainerId())
+		if err != nil {
+			result.Error = common.ServerError(err)
+			return params.ContainerMigrationsResult{Error: result.Error}, nil
+		}
+		instanceId, err := container.InstanceId()
+		if err != nil {
+			result.Error = common.ServerError(err)
+			return params.ContainerMigrationsResult{Error: result.Error}, nil
+		}
+		result.Migrations = append(result.Migrations, params.ContainerMigration{
+			ContainerTag:  names.NewMachineTag(migration.ContainerId()).String(),
+			ContainerType: string(container.ContainerType()),
+			InstanceId:    string(instanceId),
+			SourceHostTag: names.NewMachineTag(migration.SourceHostId()).String(),
+			TargetHostTag: names.NewMachineTag(migration.TargetHostId()).String(),
+			Status:        string(migration.Status()),
+			Message:       migration.Message(),
+		})
+	}
+	return result, nil
+}
+
+// SetContainerMigrationStatus records the progress made by the
+// authenticated host machine on container migrations. The source host
+// reports a container as exported, rolled back or done; the target host
+// reports it as imported; and either may report a failure.
+func (api *ContainerMigratorAPI) SetContainerMigrationStatus(args params.ContainerMigrationStatuses) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Migrations)),
+	}
+	for i, arg := range args.Migrations {
+		err := api.setStatus(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *ContainerMigratorAPI) setStatus(arg params.ContainerMigrationStatus) error {
+	tag, err := names.ParseMachineTag(arg.ContainerTag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	migration, err := api.st.ContainerMigration(tag.Id())
+	if errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	isSource := migration.SourceHostId() == api.hostId
+	isTarget := migration.TargetHostId() == api.hostId
+	if !isSource && !isTarget {
+		return common.ErrPerm
+	}
+
+	removeArchive := false
+	switch status := state.ContainerMigrationStatus(arg.Status); {
+	case status == state.MigrationExported && isSource:
+		err = migration.SetExported()
+	case status == state.MigrationImported && isTarget:
+		err = migration.SetImported()
+		removeArchive = true
+	case status == state.MigrationDone && isSource:
+		err = migration.SetDone()
+	case status == state.MigrationFailed:
+		err = migration.Fail(arg.Message)
+	case status == state.MigrationRolledBack && isSource:
+		err = migration.SetRolledBack()
+		removeArchive = true
+	default:
+		return common.ErrPerm
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if removeArchive {
+		stor := storage.NewStorage(api.st.EnvironUUID(), api.st.MongoSession())
+		if err := stor.Remove(migration.ArchivePath()); err != nil && !errors.IsNotFound(err) {
+			// The migration has moved on, so don't fail because of this.
+			logger.Warningf("cannot remove filesystem archive for %s: %v", migration, err)
+		}
+	}
+	return nil
+}
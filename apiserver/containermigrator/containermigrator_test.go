@@ -0,0 +1,163 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package containermigrator_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/containermigrator"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type containerMigratorSuite struct {
+	jujutesting.JujuConnSuite
+
+	resources      *common.Resources
+	source, target *state.Machine
+	container      *state.Machine
+}
+
+var _ = gc.Suite(&containerMigratorSuite{})
+
+func (s *containerMigratorSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+
+	var err error
+	s.source, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.target, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.source.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetProvisioned("juju-machine-0-lxc-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MigrateContainer(s.container.Id(), s.target.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *containerMigratorSuite) api(c *gc.C, host *state.Machine) *containermigrator.ContainerMigratorAPI {
+	api, err := containermigrator.NewContainerMigratorAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: host.Tag()},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *containerMigratorSuite) setStatus(c *gc.C, host *state.Machine, status state.ContainerMigrationStatus, message string) error {
+	results, err := s.api(c, host).SetContainerMigrationStatus(params.ContainerMigrationStatuses{
+		Migrations: []params.ContainerMigrationStatus{{
+			ContainerTag: s.container.Tag().String(),
+			Status:       string(status),
+			Message:      message,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	if results.Results[0].Error == nil {
+		return nil
+	}
+	return results.Results[0].Error
+}
+
+func (s *containerMigratorSuite) TestRequiresMachineAgent(c *gc.C) {
+	_, err := containermigrator.NewContainerMigratorAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")},
+	)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *containerMigratorSuite) TestContainerMigrations(c *gc.C) {
+	expected := []params.ContainerMigration{{
+		ContainerTag:  "machine-0-lxc-0",
+		ContainerType: "lxc",
+		InstanceId:    "juju-machine-0-lxc-0",
+		SourceHostTag: "machine-0",
+		TargetHostTag: "machine-1",
+		Status:        "pending",
+	}}
+	for _, host := range []*state.Machine{s.source, s.target} {
+		result, err := s.api(c, host).ContainerMigrations()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result, jc.DeepEquals, params.ContainerMigrationsResult{Migrations: expected})
+	}
+
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := s.api(c, other).ContainerMigrations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Migrations, gc.HasLen, 0)
+}
+
+func (s *containerMigratorSuite) TestWatchContainerMigrations(c *gc.C) {
+	result, err := s.api(c, s.source).WatchContainerMigrations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+}
+
+func (s *containerMigratorSuite) TestMigrationSucceeds(c *gc.C) {
+	err := s.setStatus(c, s.source, state.MigrationExported, "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.setStatus(c, s.target, state.MigrationImported, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	hostId, _ := s.container.ParentId()
+	c.Assert(hostId, gc.Equals, s.target.Id())
+
+	err = s.setStatus(c, s.source, state.MigrationDone, "")
+	c.Assert(err, jc.ErrorIsNil)
+	migration, err := s.State.ContainerMigration(s.container.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationDone)
+}
+
+func (s *containerMigratorSuite) TestMigrationRollsBack(c *gc.C) {
+	err := s.setStatus(c, s.source, state.MigrationExported, "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.setStatus(c, s.target, state.MigrationFailed, "container failed to start")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.setStatus(c, s.source, state.MigrationRolledBack, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	migration, err := s.State.ContainerMigration(s.container.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationRolledBack)
+	c.Assert(migration.Message(), gc.Equals, "container failed to start")
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	hostId, _ := s.container.ParentId()
+	c.Assert(hostId, gc.Equals, s.source.Id())
+}
+
+func (s *containerMigratorSuite) TestSetStatusWrongHost(c *gc.C) {
+	err := s.setStatus(c, s.target, state.MigrationExported, "")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = s.setStatus(c, s.source, state.MigrationImported, "")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.setStatus(c, other, state.MigrationFailed, "")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *containerMigratorSuite) TestSetStatusOutOfOrder(c *gc.C) {
+	err := s.setStatus(c, s.target, state.MigrationImported, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set status of migration of container 0/lxc/0 from machine 0 to machine 1 to "imported": status is "pending"`)
+}
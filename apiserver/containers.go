@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+)
+
+// containerRootfsHandler transfers the filesystem of a container being
+// migrated between host machines. The source host uploads the archive
+// with PUT, and the target host downloads it with GET.
+type containerRootfsHandler struct {
+	httpHandler
+}
+
+func (h *containerRootfsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Validate before authenticate because the authentication is dependent
+	// on the state connection that is determined during the validation.
+	stateWrapper, err := h.validateEnvironUUID(r)
+	if err != nil {
+		h.sendExistingError(w, http.StatusNotFound, err)
+		return
+	}
+	agentTag, err := stateWrapper.authenticateAgent(r)
+	if err != nil {
+		h.authError(w, h)
+		return
+	}
+	hostTag, ok := agentTag.(names.MachineTag)
+	if !ok {
+		h.authError(w, h)
+		return
+	}
+	migration, err := h.migration(r, stateWrapper.state)
+	if err != nil {
+		h.sendExistingError(w, http.StatusNotFound, err)
+		return
+	}
+
+	switch r.Method {
+	case "PUT":
+		if hostTag.Id() != migration.SourceHostId() {
+			h.sendExistingError(w, http.StatusForbidden, common.ErrPerm)
+			return
+		}
+		if err := h.processPut(r, stateWrapper.state, migration); err != nil {
+			logger.Errorf("PUT(%s) failed: %v", r.URL, err)
+			h.sendExistingError(w, http.StatusBadRequest, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case "GET":
+		if hostTag.Id() != migration.TargetHostId() {
+			h.sendExistingError(w, http.StatusForbidden, common.ErrPerm)
+			return
+		}
+		if err := h.processGet(w, stateWrapper.state, migration); err != nil {
+			logger.Errorf("GET(%s) failed: %v", r.URL, err)
+			h.sendExistingError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// migration returns the migration of the container named in the
+// request.
+func (h *containerRootfsHandler) migration(r *http.Request, st *state.State) (*state.ContainerMigration, error) {
+	tag, err := names.ParseMachineTag(r.URL.Query().Get(":tag"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.ContainerMigration(tag.Id())
+}
+
+// processPut stores the uploaded archive of the container's filesystem.
+func (h *containerRootfsHandler) processPut(r *http.Request, st *state.State, migration *state.ContainerMigration) error {
+	if migration.Status() != state.MigrationPending {
+		return errors.Errorf("cannot upload filesystem for %s: status is %q", migration, migration.Status())
+	}
+	if r.ContentLength < 0 {
+		return errors.New("missing Content-Length")
+	}
+	stor := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	if err := stor.Put(migration.ArchivePath(), r.Body, r.ContentLength); err != nil {
+		return errors.Annotate(err, "cannot store filesystem archive")
+	}
+	return nil
+}
+
+// processGet streams the stored archive of the container's filesystem.
+func (h *containerRootfsHandler) processGet(w http.ResponseWriter, st *state.State, migration *state.ContainerMigration) error {
+	if migration.Status() != state.MigrationExported {
+		return errors.Errorf("cannot download filesystem for %s: status is %q", migration, migration.Status())
+	}
+	stor := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
+	reader, length, err := stor.Get(migration.ArchivePath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get filesystem archive")
+	}
+	defer reader.Close()
+	w.Header().Set("Content-Type", "application/x-tar-gz")
+	w.Header().Set("Content-Length", fmt.Sprint(length))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, reader); err != nil {
+		// The status has been sent, so all we can do is log.
+		logger.Errorf("failed to stream filesystem archive for %s: %v", migration, err)
+	}
+	return nil
+}
+
+// sendError sends a JSON-encoded error response using desired
+// error message.
+func (h *containerRootfsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	h.sendExistingError(w, statusCode, errors.New(message))
+}
+
+// sendExistingError sends a JSON-encoded error response
+// for errors encountered during processing.
+func (h *containerRootfsHandler) sendExistingError(w http.ResponseWriter, statusCode int, existing error) {
+	logger.Debugf("sending error: %v %v", statusCode, existing)
+	failure := common.ServerError(existing)
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(failure); err != nil {
+		logger.Errorf("failed to send error: %v", err)
+	}
+}
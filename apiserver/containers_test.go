@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type containerRootfsSuite struct {
+	userAuthHttpSuite
+	source, target *state.Machine
+	container      *state.Machine
+	passwords      map[string]string
+}
+
+var _ = gc.Suite(&containerRootfsSuite{})
+
+func (s *containerRootfsSuite) SetUpTest(c *gc.C) {
+	s.userAuthHttpSuite.SetUpTest(c)
+	s.passwords = make(map[string]string)
+	s.source = s.addHost(c)
+	s.target = s.addHost(c)
+	var err error
+	s.container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.source.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetProvisioned("juju-machine-0-lxc-0", "container_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *containerRootfsSuite) addHost(c *gc.C) *state.Machine {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned(instance.Id("host-"+machine.Id()), "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	password, err := utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetPassword(password)
+	c.Assert(err, jc.ErrorIsNil)
+	s.passwords[machine.Id()] = password
+	return machine
+}
+
+func (s *containerRootfsSuite) rootfsURI(c *gc.C) string {
+	return s.makeURL(c, "https", fmt.Sprintf("/environment/%s/containers/%s/rootfs",
+		s.State.EnvironUUID(), s.container.Tag()), nil).String()
+}
+
+func (s *containerRootfsSuite) hostRequest(c *gc.C, host *state.Machine, method string, body io.Reader) *http.Response {
+	req, err := http.NewRequest(method, s.rootfsURI(c), body)
+	c.Assert(err, jc.ErrorIsNil)
+	req.SetBasicAuth(host.Tag().String(), s.passwords[host.Id()])
+	req.Header.Set("X-Juju-Nonce", "fake_nonce")
+	resp, err := utils.GetNonValidatingHTTPClient().Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp
+}
+
+func (s *containerRootfsSuite) migrate(c *gc.C) *state.ContainerMigration {
+	migration, err := s.State.MigrateContainer(s.container.Id(), s.target.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	return migration
+}
+
+func (s *containerRootfsSuite) assertError(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, apihttp.CTypeJSON)
+	var failure params.Error
+	err := json.Unmarshal(body, &failure)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(failure.Message, gc.Matches, expError)
+}
+
+func (s *containerRootfsSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.rootfsURI(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertError(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *containerRootfsSuite) TestRequiresMachineAgent(c *gc.C) {
+	s.migrate(c)
+	resp, err := s.authRequest(c, "GET", s.rootfsURI(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertError(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *containerRootfsSuite) TestNoMigration(c *gc.C) {
+	resp := s.hostRequest(c, s.source, "PUT", strings.NewReader("archive"))
+	s.assertError(c, resp, http.StatusNotFound, `migration of container 0/lxc/0 not found`)
+}
+
+func (s *containerRootfsSuite) TestUploadAndDownload(c *gc.C) {
+	migration := s.migrate(c)
+	resp := s.hostRequest(c, s.source, "PUT", strings.NewReader("archive"))
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	err := migration.SetExported()
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp = s.hostRequest(c, s.target, "GET", nil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "application/x-tar-gz")
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archive")
+}
+
+func (s *containerRootfsSuite) TestUploadRequiresSourceHost(c *gc.C) {
+	s.migrate(c)
+	resp := s.hostRequest(c, s.target, "PUT", strings.NewReader("archive"))
+	s.assertError(c, resp, http.StatusForbidden, "permission denied")
+}
+
+func (s *containerRootfsSuite) TestUploadRequiresPending(c *gc.C) {
+	migration := s.migrate(c)
+	err := migration.SetExported()
+	c.Assert(err, jc.ErrorIsNil)
+	resp := s.hostRequest(c, s.source, "PUT", strings.NewReader("archive"))
+	s.assertError(c, resp, http.StatusBadRequest,
+		`cannot upload filesystem for migration of container 0/lxc/0 from machine 0 to machine 1: status is "exported"`)
+}
+
+func (s *containerRootfsSuite) TestDownloadRequiresTargetHost(c *gc.C) {
+	s.migrate(c)
+	resp := s.hostRequest(c, s.source, "GET", nil)
+	s.assertError(c, resp, http.StatusForbidden, "permission denied")
+}
+
+func (s *containerRootfsSuite) TestDownloadRequiresExported(c *gc.C) {
+	s.migrate(c)
+	resp := s.hostRequest(c, s.target, "GET", nil)
+	s.assertError(c, resp, http.StatusBadRequest,
+		`cannot download filesystem for migration of container 0/lxc/0 from machine 0 to machine 1: status is "pending"`)
+}
+
+func (s *containerRootfsSuite) TestUnsupportedMethod(c *gc.C) {
+	s.migrate(c)
+	resp := s.hostRequest(c, s.source, "DELETE", nil)
+	s.assertError(c, resp, http.StatusMethodNotAllowed, `unsupported method: "DELETE"`)
+}
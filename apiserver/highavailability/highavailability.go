@@ -16,6 +16,11 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("HighAvailability", 1, NewHighAvailabilityAPI)
+	// Version 2 also records replica set preferences.
+	common.RegisterStandardFacade("HighAvailability", 2, NewHighAvailabilityAPI)
+	// Version 3 also supports refusing to place state servers in
+	// shared availability zones.
+	common.RegisterStandardFacade("HighAvailability", 3, NewHighAvailabilityAPI)
 }
 
 // HighAvailability defines the methods on the highavailability API end point.
@@ -74,6 +79,7 @@ func stateServersChanges(change state.StateServersChanges) params.StateServersCh
 		Promoted:   machineIdsToTags(change.Promoted...),
 		Demoted:    machineIdsToTags(change.Demoted...),
 		Converted:  machineIdsToTags(change.Converted...),
+		Hidden:     machineIdsToTags(change.Hidden...),
 	}
 }
 
@@ -99,13 +105,12 @@ func EnsureAvailabilitySingle(st *state.State, spec params.StateServersSpec) (pa
 		}
 	}
 
+	ssi, err := st.StateServerInfo()
+	if err != nil {
+		return params.StateServersChanges{}, err
+	}
 	series := spec.Series
 	if series == "" {
-		ssi, err := st.StateServerInfo()
-		if err != nil {
-			return params.StateServersChanges{}, err
-		}
-
 		// We should always have at least one voting machine
 		// If we *really* wanted we could just pick whatever series is
 		// in the majority, but really, if we always copy the value of
@@ -120,9 +125,46 @@ func EnsureAvailabilitySingle(st *state.State, spec params.StateServersSpec) (pa
 		}
 		series = templateMachine.Series()
 	}
-	changes, err := st.EnsureAvailability(spec.NumStateServers, spec.Constraints, series, spec.Placement)
+	// Unless the user has chosen where to put the state servers,
+	// spread any new ones across the availability zones.
+	placement := spec.Placement
+	var warnings []string
+	if len(placement) == 0 {
+		zonePlacement, warning, err := zonePlacements(st, desiredStateServerCount(spec.NumStateServers, ssi), spec.Constraints, series)
+		if err != nil {
+			return params.StateServersChanges{}, err
+		}
+		placement = zonePlacement
+		if warning != "" && spec.StrictZones {
+			return params.StateServersChanges{}, errors.New(warning)
+		} else if warning != "" {
+			logger.Warningf("%s", warning)
+			warnings = append(warnings, warning)
+		}
+	}
+	prefs := state.ReplicaSetPreferences{
+		PreferredZone:    spec.Preferences.PreferredZone,
+		PreferredPrimary: spec.Preferences.PreferredPrimary,
+		Hidden:           spec.Preferences.Hidden,
+		Reset:            spec.Preferences.Reset,
+	}
+	changes, err := st.EnsureAvailabilityWithPreferences(spec.NumStateServers, spec.Constraints, series, placement, prefs)
 	if err != nil {
 		return params.StateServersChanges{}, err
 	}
-	return stateServersChanges(changes), nil
+	result := stateServersChanges(changes)
+	result.Warnings = warnings
+	return result, nil
+}
+
+// desiredStateServerCount returns the number of state servers that
+// ensuring availability with the given count will result in.
+func desiredStateServerCount(numStateServers int, ssi *state.StateServerInfo) int {
+	if numStateServers > 0 {
+		return numStateServers
+	}
+	if n := len(ssi.VotingMachineIds); n > 1 {
+		return n
+	}
+	return 3
 }
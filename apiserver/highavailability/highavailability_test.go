@@ -263,6 +263,37 @@ func (s *clientSuite) TestEnsureAvailabilityPlacementTo(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestEnsureAvailabilityPreferences(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.pingers = append(s.pingers, s.setAgentPresence(c, "1"))
+
+	arg := params.StateServersSpecs{
+		Specs: []params.StateServersSpec{{
+			NumStateServers: 1,
+			Preferences: params.ReplicaSetPreferences{
+				PreferredPrimary: "0",
+				Hidden:           []string{"1"},
+			},
+		}}}
+	results, err := s.haServer.EnsureAvailability(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	result := results.Results[0].Result
+	c.Assert(result.Maintained, gc.DeepEquals, []string{"machine-0"})
+	c.Assert(result.Hidden, gc.DeepEquals, []string{"machine-1"})
+
+	m0, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m0.ReplicaSetPriority(), gc.Equals, 3.0)
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.IsManager(), jc.IsTrue)
+	c.Assert(m1.WantsVote(), jc.IsFalse)
+	c.Assert(m1.ReplicaSetHidden(), jc.IsTrue)
+}
+
 func (s *clientSuite) TestEnsureAvailability0Preserves(c *gc.C) {
 	// A value of 0 says either "if I'm not HA, make me HA" or "preserve my
 	// current HA settings".
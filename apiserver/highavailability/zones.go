@@ -0,0 +1,180 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/utils"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.highavailability")
+
+var getEnvironment = utils.GetEnvironment
+
+// zonePlacementPrefix is the prefix of the placement directives used
+// to start instances in a given availability zone.
+const zonePlacementPrefix = "zone="
+
+// zonePlacements returns placement directives that spread the new
+// machines needed for numStateServers state servers across the
+// availability zones of the environment, filling the zones with the
+// fewest state servers first. Existing voting state servers, and the
+// non-voting ones that will be promoted, are counted in their zones.
+// If the environment does not support availability zones, or does not
+// support placing instances in them, no directives are returned.
+//
+// It also returns a warning if the state servers cannot all be in
+// distinct zones, whether or not any machines need to be added.
+func zonePlacements(st *state.State, numStateServers int, cons constraints.Value, series string) ([]string, string, error) {
+	env, err := getEnvironment(st)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	zonedEnv, ok := env.(common.ZonedEnviron)
+	if !ok {
+		return nil, "", nil
+	}
+	allZones, err := zonedEnv.AvailabilityZones()
+	if errors.IsNotSupported(err) {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", errors.Annotate(err, "cannot get availability zones")
+	}
+	var zones []string
+	for _, zone := range allZones {
+		if zone.Available() {
+			zones = append(zones, zone.Name())
+		}
+	}
+	if len(zones) == 0 {
+		return nil, "", nil
+	}
+	// Make sure the provider understands zone placement
+	// before relying on it.
+	if err := env.PrecheckInstance(series, cons, zonePlacementPrefix+zones[0]); err != nil {
+		logger.Debugf("not spreading state servers across zones: %v", err)
+		return nil, "", nil
+	}
+	population, numExisting, err := stateServerZones(st, numStateServers)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	var placements []string
+	if numNew := numStateServers - numExisting; numNew > 0 {
+		for _, zone := range spreadZones(zones, population, numNew) {
+			placements = append(placements, zonePlacementPrefix+zone)
+			population[zone]++
+		}
+	}
+	var shared []string
+	for _, zone := range zones {
+		if population[zone] > 1 {
+			shared = append(shared, zone)
+		}
+	}
+	var warning string
+	if len(shared) > 0 {
+		warning = fmt.Sprintf(
+			"cannot spread %d state servers across %d availability zones (%s); zones %s will hold more than one",
+			numStateServers, len(zones), strings.Join(zones, ", "), strings.Join(shared, ", "),
+		)
+	}
+	return placements, warning, nil
+}
+
+// stateServerZones returns the number of state servers in each
+// availability zone that will vote once numStateServers are ensured,
+// and how many such state servers there are, mirroring the choices
+// made by state.EnsureAvailability: available voting state servers
+// are kept, unavailable ones are replaced, and available non-voting
+// ones that are not hidden are promoted to make up any shortfall.
+// Machines that have not yet been provisioned are counted in the zone
+// named by their placement directive, if any.
+//
+// Machines converted to state servers are not counted, since they are
+// only chosen by explicit placement, in which case zones are not used.
+func stateServerZones(st *state.State, numStateServers int) (map[string]int, int, error) {
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	var voting, promotable []*state.Machine
+	for _, id := range info.MachineIds {
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		available, err := m.AgentPresence()
+		if err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		switch {
+		case !available:
+			// Unavailable voting state servers are demoted
+			// and replaced, so their zones are not counted.
+		case m.WantsVote():
+			voting = append(voting, m)
+		case !m.ReplicaSetHidden():
+			promotable = append(promotable, m)
+		}
+	}
+	if n := numStateServers - len(voting); n < len(promotable) {
+		if n < 0 {
+			n = 0
+		}
+		promotable = promotable[:n]
+	}
+	population := make(map[string]int)
+	for _, m := range append(voting, promotable...) {
+		zone, err := m.AvailabilityZone()
+		if errors.IsNotProvisioned(err) {
+			zone = strings.TrimPrefix(m.Placement(), zonePlacementPrefix)
+			if zone == m.Placement() {
+				continue
+			}
+		} else if err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		if zone != "" {
+			population[zone]++
+		}
+	}
+	return population, len(voting) + len(promotable), nil
+}
+
+// spreadZones returns count zones from the given zones, choosing
+// the least populated zone each time and breaking ties by name.
+// The population map is not modified.
+func spreadZones(zones []string, population map[string]int, count int) []string {
+	if len(zones) == 0 {
+		return nil
+	}
+	tally := make(map[string]int)
+	for _, zone := range zones {
+		tally[zone] = population[zone]
+	}
+	sorted := append([]string(nil), zones...)
+	sort.Strings(sorted)
+	result := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		best := sorted[0]
+		for _, zone := range sorted[1:] {
+			if tally[zone] < tally[best] {
+				best = zone
+			}
+		}
+		tally[best]++
+		result = append(result, best)
+	}
+	return result
+}
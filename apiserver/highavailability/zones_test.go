@@ -0,0 +1,271 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability_test
+
+import (
+	"fmt"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/presence"
+	"github.com/juju/juju/state/utils"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type zonesSuite struct {
+	testing.JujuConnSuite
+	zones   []common.AvailabilityZone
+	pingers []*presence.Pinger
+}
+
+var _ = gc.Suite(&zonesSuite{})
+
+func (s *zonesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.zones = []common.AvailabilityZone{
+		fakeZone{"az1", true},
+		fakeZone{"az2", true},
+		fakeZone{"az3", true},
+		fakeZone{"az4", false},
+	}
+	s.pingers = nil
+	s.PatchValue(highavailability.GetEnvironment, func(st *state.State) (environs.Environ, error) {
+		env, err := utils.GetEnvironment(st)
+		if err != nil {
+			return nil, err
+		}
+		return &fakeZonedEnviron{Environ: env, zones: s.zones}, nil
+	})
+}
+
+func (s *zonesSuite) TearDownTest(c *gc.C) {
+	for _, pinger := range s.pingers {
+		assertKill(c, pinger)
+	}
+	s.JujuConnSuite.TearDownTest(c)
+}
+
+// addStateServer adds a voting state server in the given zone,
+// whose agent is running.
+func (s *zonesSuite) addStateServer(c *gc.C, zone string) *state.Machine {
+	return s.addStateServerWithVote(c, zone, true, true)
+}
+
+func (s *zonesSuite) addStateServerWithVote(c *gc.C, zone string, vote, available bool) *state.Machine {
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobManageEnviron},
+		NoVote: !vote,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	if zone != "" {
+		hc := &instance.HardwareCharacteristics{AvailabilityZone: &zone}
+		err = m.SetProvisioned(instance.Id("inst-"+m.Id()), "fake_nonce", hc)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	if available {
+		pinger, err := m.SetAgentPresence()
+		c.Assert(err, jc.ErrorIsNil)
+		s.pingers = append(s.pingers, pinger)
+		s.State.StartSync()
+		err = m.WaitAgentPresence(coretesting.LongWait)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return m
+}
+
+func (s *zonesSuite) TestZonePlacements(c *gc.C) {
+	s.addStateServer(c, "az1")
+	s.addStateServer(c, "")
+
+	placements, warning, err := highavailability.ZonePlacements(s.State, 3, emptyCons, "quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, jc.DeepEquals, []string{"zone=az2"})
+	c.Assert(warning, gc.Equals, "")
+}
+
+func (s *zonesSuite) TestZonePlacementsWarnsWhenSpreadImpossible(c *gc.C) {
+	s.addStateServer(c, "az2")
+
+	placements, warning, err := highavailability.ZonePlacements(s.State, 5, emptyCons, "quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, jc.DeepEquals, []string{"zone=az1", "zone=az3", "zone=az1", "zone=az2"})
+	c.Assert(warning, gc.Equals, "cannot spread 5 state servers across 3 availability zones (az1, az2, az3); zones az1, az2 will hold more than one")
+}
+
+func (s *zonesSuite) TestZonePlacementsCountsPromotedMachines(c *gc.C) {
+	s.addStateServer(c, "az1")
+	s.addStateServerWithVote(c, "az2", false, true)
+	// Unavailable non-voting state servers are not promoted.
+	s.addStateServerWithVote(c, "az3", false, false)
+
+	placements, warning, err := highavailability.ZonePlacements(s.State, 3, emptyCons, "quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, jc.DeepEquals, []string{"zone=az3"})
+	c.Assert(warning, gc.Equals, "")
+}
+
+func (s *zonesSuite) TestZonePlacementsIgnoresUnavailableStateServers(c *gc.C) {
+	// The unavailable state server will be demoted and replaced.
+	s.addStateServerWithVote(c, "az1", true, false)
+	s.addStateServer(c, "az2")
+
+	placements, warning, err := highavailability.ZonePlacements(s.State, 3, emptyCons, "quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, jc.DeepEquals, []string{"zone=az1", "zone=az3"})
+	c.Assert(warning, gc.Equals, "")
+}
+
+func (s *zonesSuite) TestZonePlacementsWarnsWithoutNewMachines(c *gc.C) {
+	s.addStateServer(c, "az1")
+	s.addStateServer(c, "az1")
+	s.addStateServer(c, "az2")
+
+	placements, warning, err := highavailability.ZonePlacements(s.State, 3, emptyCons, "quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, gc.HasLen, 0)
+	c.Assert(warning, gc.Equals, "cannot spread 3 state servers across 3 availability zones (az1, az2, az3); zones az1 will hold more than one")
+}
+
+func (s *zonesSuite) TestZonePlacementsNoAvailableZones(c *gc.C) {
+	s.addStateServer(c, "az1")
+	s.zones = s.zones[3:]
+
+	placements, warning, err := highavailability.ZonePlacements(s.State, 3, emptyCons, "quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, gc.HasLen, 0)
+	c.Assert(warning, gc.Equals, "")
+}
+
+func (s *zonesSuite) TestZonePlacementsUnsupported(c *gc.C) {
+	// The dummy provider does not support zone placement.
+	s.PatchValue(highavailability.GetEnvironment, utils.GetEnvironment)
+	s.addStateServer(c, "az1")
+
+	placements, warning, err := highavailability.ZonePlacements(s.State, 3, emptyCons, "quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, gc.HasLen, 0)
+	c.Assert(warning, gc.Equals, "")
+}
+
+func (s *zonesSuite) TestEnsureAvailabilityReturnsWarningsWithoutNewMachines(c *gc.C) {
+	s.addStateServer(c, "az1")
+	s.addStateServer(c, "az1")
+	s.addStateServer(c, "az2")
+
+	result, err := highavailability.EnsureAvailabilitySingle(s.State, params.StateServersSpec{
+		NumStateServers: 3,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Added, gc.HasLen, 0)
+	c.Assert(result.Warnings, jc.DeepEquals, []string{
+		"cannot spread 3 state servers across 3 availability zones (az1, az2, az3); zones az1 will hold more than one",
+	})
+}
+
+func (s *zonesSuite) TestEnsureAvailabilityStrictZones(c *gc.C) {
+	s.addStateServer(c, "az2")
+
+	_, err := highavailability.EnsureAvailabilitySingle(s.State, params.StateServersSpec{
+		NumStateServers: 5,
+		StrictZones:     true,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot spread 5 state servers across 3 availability zones .*`)
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.MachineIds, gc.HasLen, 1)
+
+	result, err := highavailability.EnsureAvailabilitySingle(s.State, params.StateServersSpec{
+		NumStateServers: 3,
+		StrictZones:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Added, gc.HasLen, 2)
+	c.Assert(result.Warnings, gc.HasLen, 0)
+}
+
+func (s *zonesSuite) TestSpreadZones(c *gc.C) {
+	zones := []string{"c", "a", "b"}
+	for i, test := range []struct {
+		population map[string]int
+		count      int
+		expect     []string
+	}{{
+		count:  3,
+		expect: []string{"a", "b", "c"},
+	}, {
+		population: map[string]int{"a": 1},
+		count:      3,
+		expect:     []string{"b", "c", "a"},
+	}, {
+		population: map[string]int{"a": 2, "b": 1, "other": 5},
+		count:      4,
+		expect:     []string{"c", "b", "c", "a"},
+	}, {
+		count:  5,
+		expect: []string{"a", "b", "c", "a", "b"},
+	}} {
+		c.Logf("test %d: %v", i, test.population)
+		population := make(map[string]int)
+		for zone, n := range test.population {
+			population[zone] = n
+		}
+		got := highavailability.SpreadZones(zones, population, test.count)
+		c.Check(got, jc.DeepEquals, test.expect)
+		c.Check(population, jc.DeepEquals, copyPopulation(test.population))
+	}
+}
+
+func copyPopulation(population map[string]int) map[string]int {
+	result := make(map[string]int)
+	for zone, n := range population {
+		result[zone] = n
+	}
+	return result
+}
+
+type fakeZone struct {
+	name      string
+	available bool
+}
+
+func (z fakeZone) Name() string {
+	return z.name
+}
+
+func (z fakeZone) Available() bool {
+	return z.available
+}
+
+// fakeZonedEnviron is an environ whose availability zones
+// are given by the test, and which accepts zone placement.
+type fakeZonedEnviron struct {
+	environs.Environ
+	zones []common.AvailabilityZone
+}
+
+func (e *fakeZonedEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	return e.zones, nil
+}
+
+func (e *fakeZonedEnviron) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *fakeZonedEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if strings.HasPrefix(placement, "zone=") {
+		return nil
+	}
+	return e.Environ.PrecheckInstance(series, cons, placement)
+}
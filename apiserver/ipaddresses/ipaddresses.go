@@ -0,0 +1,224 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package ipaddresses implements the API facade used by clients to
+// list, reserve and release the static IP addresses known to Juju.
+package ipaddresses
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.ipaddresses")
+
+func init() {
+	common.RegisterStandardFacade("IPAddresses", 1, NewAPI)
+}
+
+// API implements the IPAddresses API facade.
+type API struct {
+	st         *state.State
+	authorizer common.Authorizer
+}
+
+// NewAPI creates a new server-side IPAddresses API facade.
+func NewAPI(st *state.State, _ *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		st:         st,
+		authorizer: authorizer,
+	}, nil
+}
+
+// ListIPAddresses returns all IP addresses known to Juju, optionally
+// filtered by subnet and/or machine, including the machine holding
+// each address and whether it is still waiting to be released.
+func (api *API) ListIPAddresses(args params.IPAddressFilter) (params.IPAddressInfoResults, error) {
+	var results params.IPAddressInfoResults
+	subnets, err := api.st.AllSubnets()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	// IP addresses refer to subnets by their document ID.
+	subnetCIDRs := make(map[string]string)
+	for _, subnet := range subnets {
+		subnetCIDRs[subnet.ID()] = subnet.CIDR()
+	}
+
+	var withSubnet, withMachine string
+	if args.SubnetTag != "" {
+		tag, err := names.ParseSubnetTag(args.SubnetTag)
+		if err != nil {
+			return results, errors.Trace(err)
+		}
+		withSubnet = tag.Id()
+	}
+	if args.MachineTag != "" {
+		tag, err := names.ParseMachineTag(args.MachineTag)
+		if err != nil {
+			return results, errors.Trace(err)
+		}
+		withMachine = tag.Id()
+	}
+
+	addresses, err := api.st.AllIPAddresses()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for _, addr := range addresses {
+		cidr := subnetCIDRs[addr.SubnetId()]
+		if withSubnet != "" && cidr != withSubnet {
+			continue
+		}
+		if withMachine != "" && addr.MachineId() != withMachine {
+			continue
+		}
+		info := params.IPAddressInfo{
+			Value:      addr.Value(),
+			InstanceId: string(addr.InstanceId()),
+			MACAddress: addr.MACAddress(),
+			State:      string(addr.State()),
+			Life:       params.Life(addr.Life().String()),
+		}
+		if cidr != "" {
+			info.SubnetTag = names.NewSubnetTag(cidr).String()
+		}
+		if addr.MachineId() != "" {
+			info.MachineTag = names.NewMachineTag(addr.MachineId()).String()
+		}
+		results.Results = append(results.Results, info)
+	}
+	return results, nil
+}
+
+// ReserveIPAddresses reserves IP addresses in the given subnets, so
+// they are never picked when allocating addresses to containers. The
+// reserved address values are returned.
+func (api *API) ReserveIPAddresses(args params.ReserveIPAddressesParams) (params.StringResults, error) {
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Reservations)),
+	}
+	for i, arg := range args.Reservations {
+		value, err := api.reserveOne(arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = value
+	}
+	return results, nil
+}
+
+func (api *API) reserveOne(arg params.ReserveIPAddressParams) (string, error) {
+	tag, err := names.ParseSubnetTag(arg.SubnetTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	subnet, err := api.st.Subnet(tag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	addr, err := subnet.ReserveAddress(arg.Address)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return addr.Value(), nil
+}
+
+// netEnvReleaseAddress is used for testability.
+var netEnvReleaseAddress = func(env environs.NetworkingEnviron,
+	instId instance.Id, subnetId network.Id, addr network.Address, macAddress string) error {
+	return env.ReleaseAddress(instId, subnetId, addr, macAddress)
+}
+
+// ReleaseIPAddresses releases the given IP addresses with the
+// provider, if they were allocated, and removes them. Addresses still
+// held by live machines are only released when args.Force is true. If
+// the provider fails to release an address, it remains dead in state
+// so that releasing can be retried.
+func (api *API) ReleaseIPAddresses(args params.ReleaseIPAddressesParams) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Addresses)),
+	}
+	var netEnv environs.NetworkingEnviron
+	getNetEnv := func() (environs.NetworkingEnviron, error) {
+		if netEnv != nil {
+			return netEnv, nil
+		}
+		var err error
+		netEnv, err = api.networkingEnviron()
+		return netEnv, err
+	}
+	for i, value := range args.Addresses {
+		err := api.releaseOne(value, args.Force, getNetEnv)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) releaseOne(
+	value string, force bool, getNetEnv func() (environs.NetworkingEnviron, error),
+) error {
+	addr, err := api.st.IPAddress(value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if machineId := addr.MachineId(); machineId != "" && !force {
+		machine, err := api.st.Machine(machineId)
+		if err == nil && machine.Life() != state.Dead {
+			return errors.Errorf("IP address %q is in use by machine %q", value, machineId)
+		} else if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	if err := addr.EnsureDead(); err != nil {
+		return errors.Trace(err)
+	}
+	if instId := addr.InstanceId(); instId != instance.UnknownId {
+		netEnv, err := getNetEnv()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var subnetId network.Id
+		if subnet, err := api.st.Subnet(addr.SubnetId()); err == nil {
+			subnetId = network.Id(subnet.ProviderId())
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		logger.Debugf("releasing IP address %q from instance %q", value, instId)
+		err = netEnvReleaseAddress(netEnv, instId, subnetId, addr.Address(), addr.MACAddress())
+		if err != nil {
+			return errors.Annotatef(err, "cannot release IP address %q", value)
+		}
+	}
+	return errors.Trace(addr.Remove())
+}
+
+// networkingEnviron returns the environment as a
+// environs.NetworkingEnviron, or an error satisfying
+// errors.IsNotSupported if it has no networking support.
+func (api *API) networkingEnviron() (environs.NetworkingEnviron, error) {
+	envConfig, err := api.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting environment config")
+	}
+	env, err := environs.New(envConfig)
+	if err != nil {
+		return nil, errors.Annotate(err, "opening environment")
+	}
+	if netEnv, ok := environs.SupportsNetworking(env); ok {
+		return netEnv, nil
+	}
+	return nil, errors.NotSupportedf("environment networking features")
+}
@@ -0,0 +1,247 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddresses_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/ipaddresses"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type ipAddressesSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *ipaddresses.API
+	authoriser apiservertesting.FakeAuthorizer
+	subnet     *state.Subnet
+	machine    *state.Machine
+}
+
+var _ = gc.Suite(&ipAddressesSuite{})
+
+func (s *ipAddressesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = ipaddresses.NewAPI(s.State, nil, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.subnet, err = s.State.AddSubnet(state.SubnetInfo{
+		CIDR:              "10.0.0.0/24",
+		ProviderId:        "subnet-a",
+		AllocatableIPLow:  "10.0.0.10",
+		AllocatableIPHigh: "10.0.0.20",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine = s.Factory.MakeMachine(c, nil)
+}
+
+func (s *ipAddressesSuite) allocateAddress(c *gc.C, value string) *state.IPAddress {
+	addr, err := s.State.AddIPAddress(network.NewAddress(value), s.subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+	err = addr.AllocateTo(s.machine.Id(), "eth0", "aa:bb:cc:dd:ee:f0")
+	c.Assert(err, jc.ErrorIsNil)
+	return addr
+}
+
+func (s *ipAddressesSuite) patchReleaseAddress(c *gc.C, err error) *[]string {
+	var released []string
+	s.PatchValue(ipaddresses.NetEnvReleaseAddress, func(env environs.NetworkingEnviron,
+		instId instance.Id, subnetId network.Id, addr network.Address, macAddress string) error {
+		c.Check(subnetId, gc.Equals, network.Id("subnet-a"))
+		c.Check(macAddress, gc.Equals, "aa:bb:cc:dd:ee:f0")
+		released = append(released, addr.Value)
+		return err
+	})
+	return &released
+}
+
+func (s *ipAddressesSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	api, err := ipaddresses.NewAPI(s.State, nil, auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(api, gc.IsNil)
+}
+
+func (s *ipAddressesSuite) TestListIPAddresses(c *gc.C) {
+	s.allocateAddress(c, "10.0.0.11")
+	_, err := s.subnet.ReserveAddress("10.0.0.15")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListIPAddresses(params.IPAddressFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	instId, err := s.machine.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.SameContents, []params.IPAddressInfo{{
+		Value:      "10.0.0.11",
+		SubnetTag:  "subnet-10.0.0.0/24",
+		MachineTag: s.machine.Tag().String(),
+		InstanceId: string(instId),
+		MACAddress: "aa:bb:cc:dd:ee:f0",
+		State:      "allocated",
+		Life:       params.Alive,
+	}, {
+		Value:     "10.0.0.15",
+		SubnetTag: "subnet-10.0.0.0/24",
+		State:     "reserved",
+		Life:      params.Alive,
+	}})
+}
+
+func (s *ipAddressesSuite) TestListIPAddressesWithFilter(c *gc.C) {
+	s.allocateAddress(c, "10.0.0.11")
+	_, err := s.subnet.ReserveAddress("10.0.0.15")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListIPAddresses(params.IPAddressFilter{
+		MachineTag: s.machine.Tag().String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Value, gc.Equals, "10.0.0.11")
+
+	results, err = s.api.ListIPAddresses(params.IPAddressFilter{
+		SubnetTag: "subnet-192.168.0.0/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+
+	_, err = s.api.ListIPAddresses(params.IPAddressFilter{SubnetTag: "foo"})
+	c.Assert(err, gc.ErrorMatches, `"foo" is not a valid tag`)
+}
+
+func (s *ipAddressesSuite) TestReserveIPAddresses(c *gc.C) {
+	results, err := s.api.ReserveIPAddresses(params.ReserveIPAddressesParams{
+		Reservations: []params.ReserveIPAddressParams{
+			{SubnetTag: "subnet-10.0.0.0/24", Address: "10.0.0.12"},
+			{SubnetTag: "subnet-10.0.0.0/24", Address: "10.0.1.12"},
+			{SubnetTag: "subnet-192.168.0.0/24"},
+			{SubnetTag: "foo"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "10.0.0.12"},
+			{Error: &params.Error{
+				Message: `cannot reserve address in subnet "10.0.0.0/24": address "10.0.1.12" for subnet "10.0.0.0/24" not valid`,
+				Code:    params.CodeNotValid,
+			}},
+			{Error: &params.Error{
+				Message: `subnet "192.168.0.0/24" not found`,
+				Code:    params.CodeNotFound,
+			}},
+			{Error: &params.Error{
+				Message: `"foo" is not a valid tag`,
+			}},
+		},
+	})
+
+	addr, err := s.State.IPAddress("10.0.0.12")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.State(), gc.Equals, state.AddressStateReserved)
+}
+
+func (s *ipAddressesSuite) TestReleaseIPAddressesUnallocated(c *gc.C) {
+	released := s.patchReleaseAddress(c, nil)
+	_, err := s.subnet.ReserveAddress("10.0.0.15")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ReleaseIPAddresses(params.ReleaseIPAddressesParams{
+		Addresses: []string{"10.0.0.15", "10.0.0.16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{
+				Message: `IP address "10.0.0.16" not found`,
+				Code:    params.CodeNotFound,
+			}},
+		},
+	})
+	// Reserved addresses are not known to the provider.
+	c.Assert(*released, gc.HasLen, 0)
+
+	_, err = s.State.IPAddress("10.0.0.15")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ipAddressesSuite) TestReleaseIPAddressesInUse(c *gc.C) {
+	released := s.patchReleaseAddress(c, nil)
+	s.allocateAddress(c, "10.0.0.11")
+
+	results, err := s.api.ReleaseIPAddresses(params.ReleaseIPAddressesParams{
+		Addresses: []string{"10.0.0.11"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches,
+		`IP address "10.0.0.11" is in use by machine "`+s.machine.Id()+`"`)
+	c.Assert(*released, gc.HasLen, 0)
+
+	addr, err := s.State.IPAddress("10.0.0.11")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Life(), gc.Equals, state.Alive)
+}
+
+func (s *ipAddressesSuite) TestReleaseIPAddressesForce(c *gc.C) {
+	released := s.patchReleaseAddress(c, nil)
+	s.allocateAddress(c, "10.0.0.11")
+
+	results, err := s.api.ReleaseIPAddresses(params.ReleaseIPAddressesParams{
+		Addresses: []string{"10.0.0.11"},
+		Force:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(*released, jc.DeepEquals, []string{"10.0.0.11"})
+
+	_, err = s.State.IPAddress("10.0.0.11")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ipAddressesSuite) TestReleaseIPAddressesOfDeadMachine(c *gc.C) {
+	released := s.patchReleaseAddress(c, nil)
+	s.allocateAddress(c, "10.0.0.11")
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ReleaseIPAddresses(params.ReleaseIPAddressesParams{
+		Addresses: []string{"10.0.0.11"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(*released, jc.DeepEquals, []string{"10.0.0.11"})
+}
+
+func (s *ipAddressesSuite) TestReleaseIPAddressesProviderFailure(c *gc.C) {
+	s.patchReleaseAddress(c, errors.New("boom"))
+	s.allocateAddress(c, "10.0.0.11")
+
+	results, err := s.api.ReleaseIPAddresses(params.ReleaseIPAddressesParams{
+		Addresses: []string{"10.0.0.11"},
+		Force:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, `cannot release IP address "10.0.0.11": boom`)
+
+	// The address is left dead, so releasing it can be retried.
+	addr, err := s.State.IPAddress("10.0.0.11")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Life(), gc.Equals, state.Dead)
+}
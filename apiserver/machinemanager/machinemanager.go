@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -157,3 +158,97 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	}
 	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
 }
+
+// MigrateContainers starts moving each of the given containers to a new
+// host machine. The host machine agents do the work of moving them.
+// Only LXC and KVM containers can be migrated; requests for other
+// container types fail with a NotSupported error before anything is
+// changed.
+func (mm *MachineManagerAPI) MigrateContainers(args params.MigrateContainers) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Migrations)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Migrations {
+		err := mm.migrateOneContainer(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) migrateOneContainer(arg params.MigrateContainer) error {
+	containerTag, err := names.ParseMachineTag(arg.ContainerTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	targetTag, err := names.ParseMachineTag(arg.TargetHostTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = mm.st.MigrateContainer(containerTag.Id(), targetTag.Id())
+	return err
+}
+
+// CordonMachines cordons each of the given machines, so that no new
+// units or containers are placed on them.
+func (mm *MachineManagerAPI) CordonMachines(args params.Entities) (params.ErrorResults, error) {
+	return mm.setMachinesCordoned(args, true)
+}
+
+// UncordonMachines uncordons each of the given machines, allowing new
+// units and containers to be placed on them again.
+func (mm *MachineManagerAPI) UncordonMachines(args params.Entities) (params.ErrorResults, error) {
+	return mm.setMachinesCordoned(args, false)
+}
+
+func (mm *MachineManagerAPI) setMachinesCordoned(args params.Entities, cordoned bool) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err == nil {
+			err = mm.st.SetMachineCordoned(tag.Id(), cordoned)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// DrainMachines cordons each of the given machines, and replaces the
+// units on them with new units elsewhere.
+func (mm *MachineManagerAPI) DrainMachines(args params.Entities) (params.DrainMachinesResults, error) {
+	results := params.DrainMachinesResults{
+		Results: make([]params.DrainMachineResult, len(args.Entities)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		units, err := mm.drainOneMachine(entity.Tag)
+		results.Results[i].Units = units
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) drainOneMachine(machineTag string) ([]params.DrainedUnit, error) {
+	tag, err := names.ParseMachineTag(machineTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	drained, err := mm.st.DrainMachine(tag.Id())
+	units := make([]params.DrainedUnit, len(drained))
+	for i, unit := range drained {
+		units[i].UnitTag = names.NewUnitTag(unit.Unit).String()
+		if unit.Replacement != "" {
+			units[i].ReplacementTag = names.NewUnitTag(unit.Replacement).String()
+		}
+	}
+	return units, err
+}
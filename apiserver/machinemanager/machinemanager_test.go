@@ -118,10 +118,125 @@ func (s *MachineManagerSuite) TestAddMachinesStateError(c *gc.C) {
 	c.Assert(s.st.calls, gc.Equals, 1)
 }
 
+func (s *MachineManagerSuite) TestMigrateContainers(c *gc.C) {
+	s.st.err = errors.New("boom")
+	results, err := s.api.MigrateContainers(params.MigrateContainers{
+		Migrations: []params.MigrateContainer{
+			{ContainerTag: "machine-0-lxc-0", TargetHostTag: "machine-1"},
+			{ContainerTag: "machine-0", TargetHostTag: "machine-1"},
+			{ContainerTag: "unit-foo-0", TargetHostTag: "machine-1"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "boom"}},
+			{Error: &params.Error{Message: `"unit-foo-0" is not a valid machine tag`}},
+		},
+	})
+	c.Assert(s.st.migrations, jc.DeepEquals, [][]string{
+		{"0/lxc/0", "1"},
+		{"0", "1"},
+	})
+}
+
+func (s *MachineManagerSuite) TestCordonMachines(c *gc.C) {
+	s.st.err = errors.New("boom")
+	results, err := s.api.CordonMachines(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-1"},
+			{Tag: "machine-0"},
+			{Tag: "unit-foo-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "boom"}},
+			{Error: &params.Error{Message: `"unit-foo-0" is not a valid machine tag`}},
+		},
+	})
+	c.Assert(s.st.cordoned, jc.DeepEquals, map[string]bool{"1": true, "0": true})
+}
+
+func (s *MachineManagerSuite) TestUncordonMachines(c *gc.C) {
+	results, err := s.api.UncordonMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	c.Assert(s.st.cordoned, jc.DeepEquals, map[string]bool{"1": false})
+}
+
+func (s *MachineManagerSuite) TestDrainMachines(c *gc.C) {
+	s.st.err = errors.New("boom")
+	results, err := s.api.DrainMachines(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-1"},
+			{Tag: "machine-0"},
+			{Tag: "unit-foo-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.DrainMachinesResults{
+		Results: []params.DrainMachineResult{{
+			Units: []params.DrainedUnit{
+				{UnitTag: "unit-mysql-0", ReplacementTag: "unit-mysql-1"},
+				{UnitTag: "unit-wordpress-0"},
+			},
+		}, {
+			Units: []params.DrainedUnit{
+				{UnitTag: "unit-mysql-0", ReplacementTag: "unit-mysql-1"},
+			},
+			Error: &params.Error{Message: "boom"},
+		}, {
+			Error: &params.Error{Message: `"unit-foo-0" is not a valid machine tag`},
+		}},
+	})
+	c.Assert(s.st.drained, jc.DeepEquals, []string{"1", "0"})
+}
+
 type mockState struct {
-	calls    int
-	machines []state.MachineTemplate
-	err      error
+	calls      int
+	machines   []state.MachineTemplate
+	migrations [][]string
+	cordoned   map[string]bool
+	drained    []string
+	err        error
+}
+
+func (st *mockState) SetMachineCordoned(id string, cordoned bool) error {
+	if st.cordoned == nil {
+		st.cordoned = make(map[string]bool)
+	}
+	st.cordoned[id] = cordoned
+	if id == "0" {
+		return st.err
+	}
+	return nil
+}
+
+func (st *mockState) DrainMachine(id string) ([]state.DrainedUnit, error) {
+	st.drained = append(st.drained, id)
+	if id == "0" {
+		return []state.DrainedUnit{{Unit: "mysql/0", Replacement: "mysql/1"}}, st.err
+	}
+	return []state.DrainedUnit{
+		{Unit: "mysql/0", Replacement: "mysql/1"},
+		{Unit: "wordpress/0"},
+	}, nil
+}
+
+func (st *mockState) MigrateContainer(containerId, targetHostId string) (*state.ContainerMigration, error) {
+	st.migrations = append(st.migrations, []string{containerId, targetHostId})
+	if containerId == "0" {
+		return nil, st.err
+	}
+	return nil, nil
 }
 
 func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machine, error) {
@@ -16,6 +16,9 @@ type stateInterface interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	MigrateContainer(containerId, targetHostId string) (*state.ContainerMigration, error)
+	SetMachineCordoned(id string, cordoned bool) error
+	DrainMachine(id string) ([]state.DrainedUnit, error)
 }
 
 type stateShim struct {
@@ -45,3 +48,23 @@ func (s stateShim) AddMachineInsideNewMachine(template, parentTemplate state.Mac
 func (s stateShim) AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error) {
 	return s.State.AddMachineInsideMachine(template, parentId, containerType)
 }
+
+func (s stateShim) MigrateContainer(containerId, targetHostId string) (*state.ContainerMigration, error) {
+	return s.State.MigrateContainer(containerId, targetHostId)
+}
+
+func (s stateShim) SetMachineCordoned(id string, cordoned bool) error {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return err
+	}
+	return m.SetCordoned(cordoned)
+}
+
+func (s stateShim) DrainMachine(id string) ([]state.DrainedUnit, error) {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return nil, err
+	}
+	return m.Drain()
+}
@@ -0,0 +1,180 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package metricsdebug contains the implementation of an api endpoint
+// for querying the metrics collected from units.
+package metricsdebug
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.metricsdebug")
+
+func init() {
+	common.RegisterStandardFacade("MetricsDebug", 1, NewMetricsDebugAPI)
+}
+
+// MetricsDebug defines the methods on the metricsdebug API end point.
+type MetricsDebug interface {
+	GetMetrics(args params.MetricsQueries) (params.MetricResults, error)
+	MeterThresholds(args params.Entities) (params.MeterThresholdsResults, error)
+	SetMeterThresholds(args params.MeterThresholdsArgs) (params.ErrorResults, error)
+}
+
+// MetricsDebugAPI implements the metricsdebug interface and is the concrete
+// implementation of the api end point.
+type MetricsDebugAPI struct {
+	state *state.State
+}
+
+var _ MetricsDebug = (*MetricsDebugAPI)(nil)
+
+// NewMetricsDebugAPI creates a new API endpoint for querying metrics.
+func NewMetricsDebugAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*MetricsDebugAPI, error) {
+	// Only clients can access the metrics debug facade.
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &MetricsDebugAPI{state: st}, nil
+}
+
+// GetMetrics returns the metrics recorded by each specified unit, or by
+// all units of each specified service, ordered by the time at which
+// the metrics were recorded.
+func (api *MetricsDebugAPI) GetMetrics(args params.MetricsQueries) (params.MetricResults, error) {
+	results := params.MetricResults{
+		Results: make([]params.EntityMetrics, len(args.Queries)),
+	}
+	for i, arg := range args.Queries {
+		metrics, err := api.getMetrics(arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Metrics = metrics
+	}
+	return results, nil
+}
+
+func (api *MetricsDebugAPI) getMetrics(arg params.MetricsQuery) ([]params.MetricResult, error) {
+	tag, err := names.ParseTag(arg.Tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var batches []state.MetricBatch
+	switch tag := tag.(type) {
+	case names.UnitTag:
+		batches, err = api.state.MetricBatchesForUnit(tag.Id())
+	case names.ServiceTag:
+		batches, err = api.state.MetricBatchesForService(tag.Id())
+	default:
+		return nil, errors.Errorf("%q is not a unit or service tag", arg.Tag)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting metrics for %s", names.ReadableString(tag))
+	}
+	var metrics []params.MetricResult
+	for _, batch := range batches {
+		for _, metric := range batch.Metrics() {
+			if metric.Time.Before(arg.Since) {
+				continue
+			}
+			metrics = append(metrics, params.MetricResult{
+				Unit:  batch.Unit(),
+				Key:   metric.Key,
+				Value: metric.Value,
+				Time:  metric.Time,
+			})
+		}
+	}
+	sort.Stable(metricsByTime(metrics))
+	logger.Tracef("found %d metrics for %s", len(metrics), names.ReadableString(tag))
+	return metrics, nil
+}
+
+// MeterThresholds returns the meter thresholds of each specified service.
+func (api *MetricsDebugAPI) MeterThresholds(args params.Entities) (params.MeterThresholdsResults, error) {
+	results := params.MeterThresholdsResults{
+		Results: make([]params.MeterThresholdsResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		thresholds, err := api.meterThresholds(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Thresholds = thresholds
+	}
+	return results, nil
+}
+
+func (api *MetricsDebugAPI) meterThresholds(tagString string) ([]string, error) {
+	service, err := api.service(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	thresholds, err := service.MeterThresholds()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]string, len(thresholds))
+	for i, t := range thresholds {
+		result[i] = t.String()
+	}
+	return result, nil
+}
+
+// SetMeterThresholds replaces the meter thresholds of each specified
+// service. The meter status of a service's units is set when one of
+// its thresholds is breached by the metrics the units record.
+func (api *MetricsDebugAPI) SetMeterThresholds(args params.MeterThresholdsArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := api.setMeterThresholds(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *MetricsDebugAPI) setMeterThresholds(arg params.MeterThresholds) error {
+	service, err := api.service(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	thresholds := make([]state.MeterThreshold, len(arg.Thresholds))
+	for i, s := range arg.Thresholds {
+		if thresholds[i], err = state.ParseMeterThreshold(s); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return service.SetMeterThresholds(thresholds)
+}
+
+func (api *MetricsDebugAPI) service(tagString string) (*state.Service, error) {
+	tag, err := names.ParseServiceTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return api.state.Service(tag.Id())
+}
+
+type metricsByTime []params.MetricResult
+
+func (m metricsByTime) Len() int           { return len(m) }
+func (m metricsByTime) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m metricsByTime) Less(i, j int) bool { return m[i].Time.Before(m[j].Time) }
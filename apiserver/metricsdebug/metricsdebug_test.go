@@ -0,0 +1,180 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/metricsdebug"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type metricsDebugSuite struct {
+	jujutesting.JujuConnSuite
+
+	metricsdebug *metricsdebug.MetricsDebugAPI
+	service      *state.Service
+	unit         *state.Unit
+}
+
+var _ = gc.Suite(&metricsDebugSuite{})
+
+func (s *metricsDebugSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	debug, err := metricsdebug.NewMetricsDebugAPI(s.State, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.metricsdebug = debug
+	meteredCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "cs:quantal/metered"})
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Charm: meteredCharm})
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+}
+
+func (s *metricsDebugSuite) TestNewMetricsDebugAPIRefusesNonClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	}
+	_, err := metricsdebug.NewMetricsDebugAPI(s.State, nil, authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *metricsDebugSuite) TestGetMetrics(c *gc.C) {
+	unit1 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+	s.Factory.MakeMetric(c, &factory.MetricParams{
+		Unit: unit1, Time: &t1, Metrics: []state.Metric{{"pings", "7", t1}},
+	})
+	s.Factory.MakeMetric(c, &factory.MetricParams{
+		Unit: s.unit, Time: &t0, Metrics: []state.Metric{{"pings", "5", t0}},
+	})
+
+	results, err := s.metricsdebug.GetMetrics(params.MetricsQueries{
+		Queries: []params.MetricsQuery{
+			{Tag: s.unit.Tag().String()},
+			{Tag: s.service.Tag().String()},
+			{Tag: s.service.Tag().String(), Since: t1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MetricResults{
+		Results: []params.EntityMetrics{{
+			Metrics: []params.MetricResult{
+				{Unit: "metered/0", Key: "pings", Value: "5", Time: t0},
+			},
+		}, {
+			Metrics: []params.MetricResult{
+				{Unit: "metered/0", Key: "pings", Value: "5", Time: t0},
+				{Unit: "metered/1", Key: "pings", Value: "7", Time: t1},
+			},
+		}, {
+			Metrics: []params.MetricResult{
+				{Unit: "metered/1", Key: "pings", Value: "7", Time: t1},
+			},
+		}},
+	})
+}
+
+func (s *metricsDebugSuite) TestGetMetricsNoMetrics(c *gc.C) {
+	results, err := s.metricsdebug.GetMetrics(params.MetricsQueries{
+		Queries: []params.MetricsQuery{{Tag: s.unit.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MetricResults{
+		Results: []params.EntityMetrics{{}},
+	})
+}
+
+func (s *metricsDebugSuite) TestGetMetricsErrors(c *gc.C) {
+	results, err := s.metricsdebug.GetMetrics(params.MetricsQueries{
+		Queries: []params.MetricsQuery{
+			{Tag: "unit-metered-9"},
+			{Tag: "machine-0"},
+			{Tag: "invalid"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MetricResults{
+		Results: []params.EntityMetrics{{
+			Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `getting metrics for unit metered/9: unit "metered/9" not found`,
+			},
+		}, {
+			Error: &params.Error{
+				Message: `"machine-0" is not a unit or service tag`,
+			},
+		}, {
+			Error: &params.Error{
+				Message: `"invalid" is not a valid tag`,
+			},
+		}},
+	})
+}
+
+func (s *metricsDebugSuite) TestSetMeterThresholds(c *gc.C) {
+	results, err := s.metricsdebug.SetMeterThresholds(params.MeterThresholdsArgs{
+		Args: []params.MeterThresholds{{
+			Tag:        s.service.Tag().String(),
+			Thresholds: []string{"pings > 1000 for 5m -> AMBER", "pings > 5000 -> RED"},
+		}, {
+			Tag:        s.service.Tag().String(),
+			Thresholds: []string{"pings > lots -> AMBER"},
+		}, {
+			Tag:        "service-unknown",
+			Thresholds: []string{"pings > 1000 -> AMBER"},
+		}, {
+			Tag: s.unit.Tag().String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches,
+		`invalid meter threshold "pings > lots -> AMBER": expected numeric value, got "lots"`,
+	)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "unknown" not found`)
+	c.Assert(results.Results[2].Error.Code, gc.Equals, params.CodeNotFound)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `"unit-metered-0" is not a valid service tag`)
+
+	thresholds, err := s.service.MeterThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(thresholds, gc.HasLen, 2)
+}
+
+func (s *metricsDebugSuite) TestMeterThresholds(c *gc.C) {
+	threshold, err := state.ParseMeterThreshold("pings > 1000 for 5m -> AMBER")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetMeterThresholds([]state.MeterThreshold{threshold})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := s.metricsdebug.MeterThresholds(params.Entities{
+		Entities: []params.Entity{
+			{s.service.Tag().String()},
+			{"service-unknown"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MeterThresholdsResults{
+		Results: []params.MeterThresholdsResult{{
+			Thresholds: []string{"pings > 1000 for 5m0s -> AMBER"},
+		}, {
+			Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `service "unknown" not found`,
+			},
+		}},
+	})
+}
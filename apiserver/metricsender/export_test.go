@@ -16,3 +16,5 @@ func PatchHostAndCertPool(host string, certPool *x509.CertPool) func() {
 		restoreCertsPool()
 	}
 }
+
+var RetryStrategy = &retryStrategy
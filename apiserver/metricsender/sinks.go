@@ -0,0 +1,262 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+)
+
+var (
+	// retryStrategy defines how sending metrics to a configured
+	// sink is retried before giving up until the next send.
+	retryStrategy = utils.AttemptStrategy{
+		Total: 10 * time.Second,
+		Delay: 2 * time.Second,
+	}
+
+	// sinkTimeout is the timeout for connecting to, and
+	// sending metrics to, network sinks.
+	sinkTimeout = 30 * time.Second
+)
+
+// NewSender returns a MetricSender that sends metrics to the sink
+// identified by the specified URL, as given in the "metrics-sender"
+// environment config attribute. The following URL schemes are
+// supported:
+//
+//	http, https: POST metric batches, in wire format, to the URL.
+//	file: append metric batches, in wire format, to the named file
+//	      on the state server, rotating it as it grows.
+//	statsd: send each metric as a gauge to the StatsD listener at
+//	        the URL's host, over UDP.
+//	graphite: send each metric to the Graphite listener at the URL's
+//	          host, using the plaintext protocol over TCP.
+//
+// Sending is retried on failure; metric batches are acknowledged only
+// once they have been delivered to the sink.
+func NewSender(senderURL string) (MetricSender, error) {
+	u, err := url.Parse(senderURL)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing metrics sender URL")
+	}
+	var sender MetricSender
+	switch u.Scheme {
+	case "http", "https":
+		sender = &endpointSender{url: u.String()}
+	case "file":
+		if u.Path == "" {
+			return nil, errors.Errorf("metrics sender URL %q does not specify a file", senderURL)
+		}
+		sender = &fileSender{path: u.Path}
+	case "statsd", "graphite":
+		if u.Host == "" {
+			return nil, errors.Errorf("metrics sender URL %q does not specify a host", senderURL)
+		}
+		prefix := strings.Replace(strings.Trim(u.Path, "/"), "/", ".", -1)
+		if u.Scheme == "statsd" {
+			sender = &statsdSender{addr: u.Host, prefix: prefix}
+		} else {
+			sender = &graphiteSender{addr: u.Host, prefix: prefix}
+		}
+	default:
+		return nil, errors.NotSupportedf("metrics sender URL scheme %q", u.Scheme)
+	}
+	return &retrySender{sender}, nil
+}
+
+// retrySender is a MetricSender that retries sending
+// with another MetricSender until it succeeds.
+type retrySender struct {
+	sender MetricSender
+}
+
+// Send implements the MetricSender interface.
+func (s *retrySender) Send(batches []*wireformat.MetricBatch) (resp *wireformat.Response, err error) {
+	for a := retryStrategy.Start(); a.Next(); {
+		resp, err = s.sender.Send(batches)
+		if err == nil {
+			return resp, nil
+		}
+		logger.Debugf("failed to send metrics (will retry): %v", err)
+	}
+	return nil, errors.Trace(err)
+}
+
+// ackAll returns a response acknowledging all of the given batches.
+func ackAll(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	resp := make(wireformat.EnvironmentResponses)
+	for _, batch := range batches {
+		resp.Ack(batch.EnvUUID, batch.UUID)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &wireformat.Response{UUID: uuid.String(), EnvResponses: resp}, nil
+}
+
+// endpointSender sends metrics to an arbitrary HTTP endpoint.
+type endpointSender struct {
+	url string
+}
+
+// Send implements the MetricSender interface.
+//
+// The metrics are considered delivered if the endpoint responds with
+// a 2xx status code. If the endpoint responds with a wire format
+// response, such as the metrics collector's, then that response is
+// used; otherwise all of the batches are acknowledged.
+func (s *endpointSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	b, err := json.Marshal(batches)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client := &http.Client{Timeout: sinkTimeout}
+	resp, err := client.Post(s.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, errors.Errorf("failed to send metrics http %v", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var metricsResponse wireformat.Response
+	if err := json.Unmarshal(body, &metricsResponse); err == nil && len(metricsResponse.EnvResponses) > 0 {
+		return &metricsResponse, nil
+	}
+	return ackAll(batches)
+}
+
+// fileSender writes metrics to a rotated file on the state server.
+type fileSender struct {
+	path string
+}
+
+// Send implements the MetricSender interface.
+//
+// Each metric batch is written to the file as a line of JSON.
+func (s *fileSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := &lumberjack.Logger{
+		Filename:   s.path,
+		MaxSize:    100, // MB
+		MaxBackups: 5,
+	}
+	defer out.Close()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, batch := range batches {
+		if err := enc.Encode(batch); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if _, err := out.Write(buf.Bytes()); err != nil {
+		return nil, errors.Annotatef(err, "writing metrics to %q", s.path)
+	}
+	return ackAll(batches)
+}
+
+// statsdSender sends metrics to a StatsD listener as gauges.
+type statsdSender struct {
+	addr   string
+	prefix string
+}
+
+// Send implements the MetricSender interface.
+//
+// StatsD uses UDP, so metrics are considered delivered once they
+// have been written to the socket.
+func (s *statsdSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	conn, err := net.DialTimeout("udp", s.addr, sinkTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer conn.Close()
+	for _, batch := range batches {
+		for _, m := range batch.Metrics {
+			line := fmt.Sprintf("%s:%s|g", metricPath(s.prefix, batch, m), m.Value)
+			if _, err := conn.Write([]byte(line)); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+	return ackAll(batches)
+}
+
+// graphiteSender sends metrics to a Graphite listener using
+// the plaintext protocol.
+type graphiteSender struct {
+	addr   string
+	prefix string
+}
+
+// Send implements the MetricSender interface.
+func (s *graphiteSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	conn, err := net.DialTimeout("tcp", s.addr, sinkTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(sinkTimeout)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := bufio.NewWriter(conn)
+	for _, batch := range batches {
+		for _, m := range batch.Metrics {
+			fmt.Fprintf(w, "%s %s %d\n", metricPath(s.prefix, batch, m), m.Value, m.Time.Unix())
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ackAll(batches)
+}
+
+// metricPath returns the dot-separated path used to identify a metric
+// in StatsD and Graphite, of the form
+// [<prefix>.]juju.<env-uuid>.<service>.<unit-number>.<key>.
+func metricPath(prefix string, batch *wireformat.MetricBatch, m wireformat.Metric) string {
+	parts := []string{"juju", batch.EnvUUID}
+	parts = append(parts, strings.Split(batch.UnitName, "/")...)
+	parts = append(parts, sanitizeMetricKey(m.Key))
+	if prefix != "" {
+		parts = append([]string{prefix}, parts...)
+	}
+	return strings.Join(parts, ".")
+}
+
+// sanitizeMetricKey replaces characters that have special meaning
+// in StatsD and Graphite metric paths.
+func sanitizeMetricKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ':', '|', '@', ' ', '\t', '\n', '/':
+			return '_'
+		}
+		return r
+	}, key)
+}
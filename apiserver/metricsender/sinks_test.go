@@ -0,0 +1,276 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SinksSuite struct {
+	coretesting.BaseSuite
+	batches []*wireformat.MetricBatch
+}
+
+var _ = gc.Suite(&SinksSuite{})
+
+func (s *SinksSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(metricsender.RetryStrategy, utils.AttemptStrategy{Min: 3})
+	now := time.Unix(1434000000, 0).UTC()
+	s.batches = []*wireformat.MetricBatch{{
+		UUID:     "batch-1",
+		EnvUUID:  "env-uuid",
+		UnitName: "metered/0",
+		CharmUrl: "cs:quantal/metered",
+		Created:  now,
+		Metrics: []wireformat.Metric{
+			{Key: "pings", Value: "5", Time: now},
+			{Key: "queue.depth", Value: "10", Time: now},
+		},
+	}, {
+		UUID:     "batch-2",
+		EnvUUID:  "env-uuid",
+		UnitName: "metered/1",
+		CharmUrl: "cs:quantal/metered",
+		Created:  now,
+		Metrics: []wireformat.Metric{
+			{Key: "pings", Value: "7", Time: now},
+		},
+	}}
+}
+
+func (s *SinksSuite) assertAcked(c *gc.C, resp *wireformat.Response) {
+	c.Assert(resp, gc.NotNil)
+	c.Assert(resp.EnvResponses, gc.HasLen, 1)
+	c.Assert(resp.EnvResponses["env-uuid"].AcknowledgedBatches, jc.SameContents, []string{"batch-1", "batch-2"})
+}
+
+func (s *SinksSuite) TestNewSenderErrors(c *gc.C) {
+	for i, test := range []struct {
+		url string
+		err string
+	}{{
+		url: "ftp://example.com/metrics",
+		err: `metrics sender URL scheme "ftp" not supported`,
+	}, {
+		url: "file://",
+		err: `metrics sender URL "file://" does not specify a file`,
+	}, {
+		url: "statsd:///prefix",
+		err: `metrics sender URL "statsd:///prefix" does not specify a host`,
+	}, {
+		url: "graphite://",
+		err: `metrics sender URL "graphite://" does not specify a host`,
+	}} {
+		c.Logf("test %d: %s", i, test.url)
+		_, err := metricsender.NewSender(test.url)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	_, err := metricsender.NewSender("ftp://example.com/metrics")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *SinksSuite) TestHTTPSink(c *gc.C) {
+	received := make(chan []wireformat.MetricBatch, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batches []wireformat.MetricBatch
+		err := json.NewDecoder(r.Body).Decode(&batches)
+		c.Check(err, jc.ErrorIsNil)
+		received <- batches
+	}))
+	defer ts.Close()
+
+	sender, err := metricsender.NewSender(ts.URL + "/metrics")
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertAcked(c, resp)
+
+	batches := <-received
+	c.Assert(batches, gc.HasLen, 2)
+	c.Assert(batches[0].UUID, gc.Equals, "batch-1")
+	c.Assert(batches[0].Metrics, gc.HasLen, 2)
+}
+
+func (s *SinksSuite) TestHTTPSinkWireFormatResponse(c *gc.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := wireformat.EnvironmentResponses{}
+		resp.Ack("env-uuid", "batch-1")
+		err := json.NewEncoder(w).Encode(wireformat.Response{UUID: "response", EnvResponses: resp})
+		c.Check(err, jc.ErrorIsNil)
+	}))
+	defer ts.Close()
+
+	sender, err := metricsender.NewSender(ts.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.UUID, gc.Equals, "response")
+	c.Assert(resp.EnvResponses["env-uuid"].AcknowledgedBatches, jc.DeepEquals, []string{"batch-1"})
+}
+
+func (s *SinksSuite) TestHTTPSinkRetries(c *gc.C) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	sender, err := metricsender.NewSender(ts.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 3)
+	s.assertAcked(c, resp)
+}
+
+func (s *SinksSuite) TestHTTPSinkFails(c *gc.C) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	sender, err := metricsender.NewSender(ts.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(s.batches)
+	c.Assert(err, gc.ErrorMatches, "failed to send metrics http 500")
+	c.Assert(calls, gc.Equals, 3)
+}
+
+func (s *SinksSuite) TestFileSink(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "metrics", "metrics.log")
+	sender, err := metricsender.NewSender("file://" + path)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertAcked(c, resp)
+	_, err = sender.Send(s.batches[:1])
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	c.Assert(lines, gc.HasLen, 3)
+	for i, uuid := range []string{"batch-1", "batch-2", "batch-1"} {
+		var batch wireformat.MetricBatch
+		err := json.Unmarshal([]byte(lines[i]), &batch)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(batch.UUID, gc.Equals, uuid)
+	}
+}
+
+func (s *SinksSuite) TestFileSinkFails(c *gc.C) {
+	dir := c.MkDir()
+	blocker := filepath.Join(dir, "blocker")
+	err := ioutil.WriteFile(blocker, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	sender, err := metricsender.NewSender("file://" + filepath.Join(blocker, "metrics.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(s.batches)
+	c.Assert(err, jc.Satisfies, func(err error) bool {
+		_, ok := errors.Cause(err).(*os.PathError)
+		return ok
+	})
+}
+
+func (s *SinksSuite) TestStatsdSink(c *gc.C) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+
+	sender, err := metricsender.NewSender(fmt.Sprintf("statsd://%s/prod/metrics", conn.LocalAddr()))
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertAcked(c, resp)
+
+	var received []string
+	buf := make([]byte, 1024)
+	for i := 0; i < 3; i++ {
+		err := conn.SetReadDeadline(time.Now().Add(coretesting.LongWait))
+		c.Assert(err, jc.ErrorIsNil)
+		n, _, err := conn.ReadFrom(buf)
+		c.Assert(err, jc.ErrorIsNil)
+		received = append(received, string(buf[:n]))
+	}
+	c.Assert(received, jc.DeepEquals, []string{
+		"prod.metrics.juju.env-uuid.metered.0.pings:5|g",
+		"prod.metrics.juju.env-uuid.metered.0.queue_depth:10|g",
+		"prod.metrics.juju.env-uuid.metered.1.pings:7|g",
+	})
+}
+
+func (s *SinksSuite) TestGraphiteSink(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if !c.Check(err, jc.ErrorIsNil) {
+			close(received)
+			return
+		}
+		defer conn.Close()
+		var lines []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		received <- lines
+	}()
+
+	sender, err := metricsender.NewSender(fmt.Sprintf("graphite://%s", listener.Addr()))
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertAcked(c, resp)
+
+	select {
+	case lines := <-received:
+		c.Assert(lines, jc.DeepEquals, []string{
+			"juju.env-uuid.metered.0.pings 5 1434000000",
+			"juju.env-uuid.metered.0.queue_depth 10 1434000000",
+			"juju.env-uuid.metered.1.pings 7 1434000000",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for metrics")
+	}
+}
+
+func (s *SinksSuite) TestGraphiteSinkFails(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	sender, err := metricsender.NewSender("graphite://" + addr)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(s.batches)
+	c.Assert(err, gc.ErrorMatches, ".*connection refused")
+}
@@ -6,6 +6,8 @@
 package metricsmanager
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
@@ -31,6 +33,7 @@ func init() {
 type MetricsManager interface {
 	CleanupOldMetrics(arg params.Entities) (params.ErrorResults, error)
 	SendMetrics(args params.Entities) (params.ErrorResults, error)
+	EvaluateMeterThresholds(args params.Entities) (params.ErrorResults, error)
 }
 
 // MetricsManagerAPI implements the metrics manager interface and is the concrete
@@ -125,7 +128,13 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 			result.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
-		err = metricsender.SendMetrics(api.state, sender, maxBatchesPerSend)
+		metricSender, err := api.metricSender()
+		if err != nil {
+			err = errors.Annotate(err, "failed to create metric sender")
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = metricsender.SendMetrics(api.state, metricSender, maxBatchesPerSend)
 		if err != nil {
 			err = errors.Annotate(err, "failed to send metrics")
 			logger.Warningf("%v", err)
@@ -135,3 +144,50 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 	}
 	return result, nil
 }
+
+// EvaluateMeterThresholds evaluates the meter thresholds defined for
+// services in the environment, and sets the meter status of their units
+// accordingly.
+func (api *MetricsManagerAPI) EvaluateMeterThresholds(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	canAccess, err := api.accessEnviron()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseEnvironTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = api.state.EvaluateMeterThresholds(time.Now())
+		if err != nil {
+			err = errors.Annotate(err, "failed to evaluate meter thresholds")
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// metricSender returns the MetricSender to use for sending metrics,
+// as configured by the environment's "metrics-sender" attribute.
+func (api *MetricsManagerAPI) metricSender() (metricsender.MetricSender, error) {
+	cfg, err := api.state.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	senderURL, ok := cfg.MetricsSender()
+	if !ok {
+		return sender, nil
+	}
+	return metricsender.NewSender(senderURL)
+}
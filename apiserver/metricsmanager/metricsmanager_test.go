@@ -4,6 +4,8 @@
 package metricsmanager_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/errors"
@@ -136,6 +138,28 @@ func (s *metricsManagerSuite) TestSendMetrics(c *gc.C) {
 	c.Assert(m.Sent(), jc.IsTrue)
 }
 
+func (s *metricsManagerSuite) TestSendMetricsToConfiguredSink(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "metrics.log")
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"metrics-sender": "file://" + path}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now()
+	metric := state.Metric{"pings", "5", now}
+	unsent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false, Time: &now, Metrics: []state.Metric{metric}})
+	args := params.Entities{Entities: []params.Entity{
+		{s.State.EnvironTag().String()},
+	}}
+	result, err := s.metricsmanager.SendMetrics(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0], gc.DeepEquals, params.ErrorResult{Error: nil})
+	m, err := s.State.MetricBatch(unsent.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Sent(), jc.IsTrue)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, unsent.UUID())
+}
+
 func (s *metricsManagerSuite) TestSendOldMetricsInvalidArg(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{"invalid"},
@@ -209,3 +233,28 @@ func (s *metricsManagerSuite) TestLastSuccessfulNotChangedIfNothingToSend(c *gc.
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(mm.LastSuccessfulSend().Equal(time.Time{}), jc.IsTrue)
 }
+
+func (s *metricsManagerSuite) TestEvaluateMeterThresholds(c *gc.C) {
+	service, err := s.unit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	threshold, err := state.ParseMeterThreshold("pings > 1000 -> AMBER")
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetMeterThresholds([]state.MeterThreshold{threshold})
+	c.Assert(err, jc.ErrorIsNil)
+	pastTime := time.Now().Add(-time.Second)
+	metric := state.Metric{"pings", "2000", pastTime}
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &pastTime, Metrics: []state.Metric{metric}})
+
+	args := params.Entities{Entities: []params.Entity{
+		{s.State.EnvironTag().String()},
+		{"environment-deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+	}}
+	result, err := s.metricsmanager.EvaluateMeterThresholds(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "permission denied")
+	status, err := s.unit.GetMeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Code, gc.Equals, state.MeterAmber)
+}
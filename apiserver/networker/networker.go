@@ -52,7 +52,7 @@ func NewNetworkerAPI(
 				return false
 			}
 			id := tag.Id()
-			for parentId := state.ParentId(id); parentId != ""; parentId = state.ParentId(parentId) {
+			for parentId := common.ContainerHostId(st, id); parentId != ""; parentId = common.ContainerHostId(st, parentId) {
 				// Until a top-level machine is reached.
 
 				// TODO (thumper): remove the names.Tag conversion when gccgo
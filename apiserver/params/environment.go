@@ -53,6 +53,85 @@ type SetEnvironAgentVersion struct {
 	Version version.Number
 }
 
+// StartStagedUpgrade contains the arguments for starting a staged
+// agent upgrade: the state servers and canary machines upgrade first,
+// followed by the remaining machines, BatchSize at a time.
+type StartStagedUpgrade struct {
+	Version        version.Number
+	CanaryMachines []string
+	BatchSize      int
+}
+
+// UpgradeCheck contains the arguments for a pre-flight check of an
+// agent upgrade.
+type UpgradeCheck struct {
+	Version version.Number
+}
+
+// UpgradeCheckResult holds the report of a pre-flight check for an
+// agent upgrade to Version. Nothing is changed by the check.
+type UpgradeCheckResult struct {
+	CurrentVersion version.Number
+	Version        version.Number
+
+	// ProblemAgents holds the agents that are down or in error.
+	ProblemAgents []UpgradeCheckAgent
+
+	// Tools holds, for each series and architecture in use, where
+	// tools for Version were found.
+	Tools []UpgradeCheckTools
+
+	// ReplicaSet holds the health of the mongo replica set members.
+	// ReplicaSetError is set if the replica set could not be queried.
+	ReplicaSet      []UpgradeCheckMember
+	ReplicaSetError string
+
+	// UpgradeSteps holds the descriptions of the upgrade steps,
+	// defined by the server's version of Juju, that will run.
+	ServerVersion version.Number
+	UpgradeSteps  []string
+
+	// DiskSpace holds the free space available for the backup
+	// taken during the upgrade, on each state server.
+	DiskSpace []UpgradeCheckDiskSpace
+}
+
+// UpgradeCheckAgent describes an agent that is down or in error.
+type UpgradeCheckAgent struct {
+	Tag     string
+	Status  Status
+	Message string
+	Down    bool
+}
+
+// UpgradeCheckTools describes the availability of tools for a
+// series and architecture. Source is empty if no tools were found.
+type UpgradeCheckTools struct {
+	Series string
+	Arch   string
+	Source string
+	Error  string
+}
+
+// UpgradeCheckMember describes the health of a mongo replica set
+// member.
+type UpgradeCheckMember struct {
+	Address string
+	State   string
+	Healthy bool
+	Message string
+}
+
+// UpgradeCheckDiskSpace describes the free space in a state server's
+// data directory, as last recorded at Updated.
+type UpgradeCheckDiskSpace struct {
+	MachineId string
+	Path      string
+	Free      uint64
+	Updated   time.Time
+	Error     string
+}
+
 // EnvUserInfo holds information on a user.
 type EnvUserInfo struct {
 	UserName       string     `json:"user"`
@@ -217,6 +217,19 @@ type RelationUnits struct {
 	RelationUnits []RelationUnit
 }
 
+// UnitEndpoint holds a unit tag and the name of one of the relation
+// endpoints of the unit's service.
+type UnitEndpoint struct {
+	Unit     string
+	Endpoint string
+}
+
+// UnitEndpoints holds the parameters for API calls expecting pairs of
+// unit tag and relation endpoint name.
+type UnitEndpoints struct {
+	UnitEndpoints []UnitEndpoint
+}
+
 // RelationIds holds multiple relation ids.
 type RelationIds struct {
 	RelationIds []int
@@ -620,3 +633,36 @@ type MeterStatusResult struct {
 type MeterStatusResults struct {
 	Results []MeterStatusResult
 }
+
+// ContainerMigration describes the migration of a container from one
+// host machine to another.
+type ContainerMigration struct {
+	ContainerTag  string
+	ContainerType string
+	InstanceId    string
+	SourceHostTag string
+	TargetHostTag string
+	Status        string
+	Message       string
+}
+
+// ContainerMigrationsResult holds the container migrations involving
+// a host machine, or an error.
+type ContainerMigrationsResult struct {
+	Migrations []ContainerMigration
+	Error      *Error
+}
+
+// ContainerMigrationStatus holds the new status of a container
+// migration.
+type ContainerMigrationStatus struct {
+	ContainerTag string
+	Status       string
+	Message      string
+}
+
+// ContainerMigrationStatuses holds the new statuses of multiple
+// container migrations.
+type ContainerMigrationStatuses struct {
+	Migrations []ContainerMigrationStatus
+}
@@ -564,3 +564,90 @@ type Space struct {
 	Subnets []Subnet `json:"Subnets"`
 	Error   *Error   `json:"Error,omitempty"`
 }
+
+// DiscoverSpacesParams holds the arguments for discovering subnets
+// and spaces from the provider.
+type DiscoverSpacesParams struct {
+	// Import, when true, adds the discovered subnets and spaces to
+	// the environment. Otherwise they are only proposed.
+	Import bool `json:"Import"`
+}
+
+// DiscoverSpacesResults holds the space groupings proposed for the
+// subnets known to the provider but not yet to Juju. Subnets the
+// provider does not associate with any space are grouped under a
+// space with an empty name.
+type DiscoverSpacesResults struct {
+	Results []Space `json:"Results"`
+}
+
+// IPAddressFilter holds optional filters for listing IP addresses.
+type IPAddressFilter struct {
+	// SubnetTag, if set, only includes addresses in the subnet.
+	SubnetTag string `json:"SubnetTag,omitempty"`
+
+	// MachineTag, if set, only includes addresses allocated to the
+	// machine (or container).
+	MachineTag string `json:"MachineTag,omitempty"`
+}
+
+// IPAddressInfo describes a single IP address known to Juju.
+type IPAddressInfo struct {
+	// Value is the IP address itself.
+	Value string `json:"Value"`
+
+	// SubnetTag is the tag of the subnet the address belongs to.
+	SubnetTag string `json:"SubnetTag,omitempty"`
+
+	// MachineTag is the tag of the machine (or container) holding
+	// the address, if allocated.
+	MachineTag string `json:"MachineTag,omitempty"`
+
+	// InstanceId is the provider instance the address was allocated
+	// on. For containers, this is the host's instance.
+	InstanceId string `json:"InstanceId,omitempty"`
+
+	// MACAddress is the MAC address of the NIC using the address.
+	MACAddress string `json:"MACAddress,omitempty"`
+
+	// State is the allocation state of the address, e.g. allocated
+	// or reserved.
+	State string `json:"State"`
+
+	// Life is the address' life cycle value. Dead addresses are
+	// waiting to be released by the provider.
+	Life Life `json:"Life"`
+}
+
+// IPAddressInfoResults holds the result of listing IP addresses.
+type IPAddressInfoResults struct {
+	Results []IPAddressInfo `json:"Results"`
+}
+
+// ReserveIPAddressParams holds the arguments for reserving a single
+// IP address.
+type ReserveIPAddressParams struct {
+	// SubnetTag is the tag of the subnet to reserve an address in.
+	SubnetTag string `json:"SubnetTag"`
+
+	// Address is the address to reserve. If empty, an available
+	// address is picked from the subnet's allocatable range.
+	Address string `json:"Address,omitempty"`
+}
+
+// ReserveIPAddressesParams holds the arguments for reserving one or
+// more IP addresses.
+type ReserveIPAddressesParams struct {
+	Reservations []ReserveIPAddressParams `json:"Reservations"`
+}
+
+// ReleaseIPAddressesParams holds the arguments for releasing one or
+// more IP addresses.
+type ReleaseIPAddressesParams struct {
+	// Addresses holds the values of the addresses to release.
+	Addresses []string `json:"Addresses"`
+
+	// Force allows releasing addresses still in use by live
+	// machines.
+	Force bool `json:"Force"`
+}
@@ -168,6 +168,38 @@ type AddMachines struct {
 	MachineParams []AddMachineParams `json:"MachineParams"`
 }
 
+// MigrateContainers holds the parameters for moving containers to new
+// host machines.
+type MigrateContainers struct {
+	Migrations []MigrateContainer `json:"Migrations"`
+}
+
+// MigrateContainer holds the parameters for moving a container to a
+// new host machine.
+type MigrateContainer struct {
+	ContainerTag  string `json:"ContainerTag"`
+	TargetHostTag string `json:"TargetHostTag"`
+}
+
+// DrainMachinesResults holds the results of a DrainMachines call.
+type DrainMachinesResults struct {
+	Results []DrainMachineResult `json:"Results"`
+}
+
+// DrainMachineResult holds the units moved off a single machine by a
+// DrainMachines call, along with any error that stopped the drain.
+type DrainMachineResult struct {
+	Units []DrainedUnit `json:"Units"`
+	Error *Error        `json:"Error"`
+}
+
+// DrainedUnit holds the tag of a unit removed from a drained machine,
+// and the tag of the unit added elsewhere to replace it, if any.
+type DrainedUnit struct {
+	UnitTag        string `json:"UnitTag"`
+	ReplacementTag string `json:"ReplacementTag"`
+}
+
 // AddMachinesResults holds the results of an AddMachines call.
 type AddMachinesResults struct {
 	Machines []AddMachinesResult `json:"Machines"`
@@ -203,6 +235,14 @@ type ServiceDeploy struct {
 	Placement     []*instance.Placement
 	Networks      []string
 	Storage       map[string]storage.Constraints
+
+	// EndpointBindings maps relation endpoint names to the names of
+	// the spaces they should be bound to.
+	EndpointBindings map[string]string
+
+	// ConfigProfile names the environment config profile whose
+	// settings the service uses in place of the charm defaults.
+	ConfigProfile string
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -223,6 +263,48 @@ type ServiceSetCharm struct {
 	Force       bool
 }
 
+// ServicesSetCharmRolling holds the parameters for starting rolling
+// charm upgrades of one or more services.
+type ServicesSetCharmRolling struct {
+	Services []ServiceSetCharmRolling `json:"Services"`
+}
+
+// ServiceSetCharmRolling holds the parameters for starting a rolling
+// charm upgrade of a service, batch size units at a time.
+type ServiceSetCharmRolling struct {
+	ServiceName string `json:"ServiceName"`
+	CharmUrl    string `json:"CharmUrl"`
+	Force       bool   `json:"Force"`
+	BatchSize   int    `json:"BatchSize"`
+}
+
+// ServicesRollBackCharm holds the parameters for returning one or
+// more services to charms they used previously.
+type ServicesRollBackCharm struct {
+	Services []ServiceRollBackCharm `json:"Services"`
+}
+
+// ServiceRollBackCharm holds the parameters for returning a service
+// to a charm it used previously. A negative Revision selects the
+// charm used most recently.
+type ServiceRollBackCharm struct {
+	ServiceName string `json:"ServiceName"`
+	Revision    int    `json:"Revision"`
+	Force       bool   `json:"Force"`
+}
+
+// RollBackCharmResults holds the results of a RollBackCharms call.
+type RollBackCharmResults struct {
+	Results []RollBackCharmResult `json:"Results"`
+}
+
+// RollBackCharmResult holds the URL of the charm a service was
+// returned to, or an error.
+type RollBackCharmResult struct {
+	CharmURL string `json:"CharmURL"`
+	Error    *Error `json:"Error"`
+}
+
 // ServiceExpose holds the parameters for making the ServiceExpose call.
 type ServiceExpose struct {
 	ServiceName string
@@ -235,6 +317,14 @@ type ServiceSet struct {
 	Options     map[string]string
 }
 
+// ServiceSetConfigProfile holds the parameters for a
+// ServiceSetConfigProfile command. An empty ConfigProfile causes the
+// service to stop using a config profile.
+type ServiceSetConfigProfile struct {
+	ServiceName   string
+	ConfigProfile string
+}
+
 // ServiceSetYAML holds the parameters for
 // a ServiceSetYAML command. Config contains the
 // configuration data in YAML format.
@@ -259,10 +349,11 @@ type ServiceGet struct {
 
 // ServiceGetResults holds results of the ServiceGet call.
 type ServiceGetResults struct {
-	Service     string
-	Charm       string
-	Config      map[string]interface{}
-	Constraints constraints.Value
+	Service       string
+	Charm         string
+	ConfigProfile string
+	Config        map[string]interface{}
+	Constraints   constraints.Value
 }
 
 // ServiceCharmRelations holds parameters for making the ServiceCharmRelations call.
@@ -291,6 +382,105 @@ type ServiceMetricCredentials struct {
 	Creds []ServiceMetricCredential
 }
 
+// MetricsQuery identifies the unit or service whose metrics are
+// to be returned by the MetricsDebug facade's GetMetrics call.
+type MetricsQuery struct {
+	Tag string
+
+	// Since, if non-zero, restricts the results to metrics
+	// recorded after the specified time.
+	Since time.Time
+}
+
+// MetricsQueries holds parameters for the GetMetrics call.
+type MetricsQueries struct {
+	Queries []MetricsQuery
+}
+
+// MetricResult holds a single metric recorded by a unit.
+type MetricResult struct {
+	Unit  string
+	Key   string
+	Value string
+	Time  time.Time
+}
+
+// EntityMetrics holds the metrics for a unit or service, or an error.
+type EntityMetrics struct {
+	Metrics []MetricResult
+	Error   *Error
+}
+
+// MetricResults holds the results of the GetMetrics call.
+type MetricResults struct {
+	Results []EntityMetrics
+}
+
+// MeterThresholds holds the meter thresholds of a service, each
+// in the form "<metric> <operator> <value> [for <duration>] -> <status>".
+type MeterThresholds struct {
+	Tag        string
+	Thresholds []string
+}
+
+// MeterThresholdsArgs holds parameters for the SetMeterThresholds call.
+type MeterThresholdsArgs struct {
+	Args []MeterThresholds
+}
+
+// MeterThresholdsResult holds the meter thresholds of a service,
+// or an error.
+type MeterThresholdsResult struct {
+	Thresholds []string
+	Error      *Error
+}
+
+// MeterThresholdsResults holds the results of the MeterThresholds call.
+type MeterThresholdsResults struct {
+	Results []MeterThresholdsResult
+}
+
+// ConfigProfile holds the name and settings of an environment config
+// profile.
+type ConfigProfile struct {
+	Name     string
+	Settings map[string]interface{}
+}
+
+// AddConfigProfile holds the parameters for adding a config profile.
+// The settings are given as YAML so that their types are preserved.
+type AddConfigProfile struct {
+	Name         string
+	SettingsYAML string
+}
+
+// AddConfigProfiles holds the parameters for the AddConfigProfiles call.
+type AddConfigProfiles struct {
+	Profiles []AddConfigProfile
+}
+
+// ConfigProfileNames holds the names of config profiles.
+type ConfigProfileNames struct {
+	Names []string
+}
+
+// ConfigProfileResult holds a config profile, or an error.
+type ConfigProfileResult struct {
+	Result *ConfigProfile
+	Error  *Error
+}
+
+// ConfigProfileResults holds the results of the ShowConfigProfiles call.
+type ConfigProfileResults struct {
+	Results []ConfigProfileResult
+}
+
+// ListConfigProfilesResults holds the results of the
+// ListConfigProfiles call.
+type ListConfigProfilesResults struct {
+	Profiles []ConfigProfile
+}
+
 // PublicAddress holds parameters for the PublicAddress call.
 type PublicAddress struct {
 	Target string
@@ -493,6 +683,7 @@ type ContainerConfig struct {
 	AptProxy                proxy.Settings
 	AptMirror               string
 	PreferIPv6              bool
+	IPv6Only                bool
 	AllowLXCLoopMounts      bool
 	*UpdateBehavior
 }
@@ -646,6 +837,30 @@ type StateServersSpec struct {
 	Series string `json:"series,omitempty"`
 	// Placement defines specific machines to become new state server machines.
 	Placement []string `json:"placement,omitempty"`
+	// Preferences holds the replica set preferences to record
+	// on the state server machines.
+	Preferences ReplicaSetPreferences `json:"preferences"`
+	// StrictZones, if true, causes an error to be returned instead
+	// of a warning if the state servers cannot all be placed in
+	// distinct availability zones.
+	StrictZones bool `json:"strict-zones,omitempty"`
+}
+
+// ReplicaSetPreferences holds the preferences used when maintaining
+// the state servers' mongo replica set.
+type ReplicaSetPreferences struct {
+	// PreferredZone, if set, names the availability zone whose
+	// state servers are preferred when electing a primary.
+	PreferredZone string `json:"preferred-zone,omitempty"`
+	// PreferredPrimary, if set, holds the id of the state server
+	// machine that should be primary whenever it is healthy.
+	PreferredPrimary string `json:"preferred-primary,omitempty"`
+	// Hidden holds the ids of machines to become hidden,
+	// non-voting state servers for use by backups.
+	Hidden []string `json:"hidden,omitempty"`
+	// Reset, if true, clears any preferences recorded before.
+	// It cannot be combined with other preferences.
+	Reset bool `json:"reset,omitempty"`
 }
 
 // StateServersSpecs contains all the arguments
@@ -678,6 +893,11 @@ type StateServersChanges struct {
 	Promoted   []string `json:"promoted,omitempty"`
 	Demoted    []string `json:"demoted,omitempty"`
 	Converted  []string `json:"converted,omitempty"`
+	Hidden     []string `json:"hidden,omitempty"`
+	// Warnings holds any problems, such as being unable to spread
+	// the new state servers across availability zones, that did not
+	// prevent the changes being made.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // FindToolsParams defines parameters for the FindTools method.
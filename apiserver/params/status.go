@@ -29,6 +29,19 @@ type FullStatus struct {
 	Services        map[string]ServiceStatus
 	Networks        map[string]NetworkStatus
 	Relations       []RelationStatus
+	StagedUpgrade   *StagedUpgradeStatus
+}
+
+// StagedUpgradeStatus holds the progress of a staged agent upgrade.
+type StagedUpgradeStatus struct {
+	PreviousVersion string
+	Version         string
+	CanaryMachines  []string
+	BatchSize       int
+	Batch           []string
+	Held            []string
+	Status          string
+	Message         string
 }
 
 // MachineStatus holds status info about a machine.
@@ -55,6 +68,14 @@ type MachineStatus struct {
 	Jobs          []multiwatcher.MachineJob
 	HasVote       bool
 	WantsVote     bool
+
+	// AvailabilityZone holds the zone the machine was
+	// provisioned in, if known.
+	AvailabilityZone string
+
+	// Cordoned records whether the machine has been taken out of
+	// service for new units and containers.
+	Cordoned bool
 }
 
 // ServiceStatus holds status info about a service.
@@ -70,6 +91,20 @@ type ServiceStatus struct {
 	Units         map[string]UnitStatus
 	MeterStatuses map[string]MeterStatus
 	Status        AgentStatus
+
+	// RollingUpgrade holds the progress of the service's rolling
+	// charm upgrade, if one is under way.
+	RollingUpgrade *RollingUpgradeStatus
+}
+
+// RollingUpgradeStatus holds the progress of a rolling charm upgrade.
+type RollingUpgradeStatus struct {
+	PreviousCharm string
+	BatchSize     int
+	Batch         []string
+	Held          []string
+	Status        string
+	Message       string
 }
 
 // MeterStatus represents the meter status of a unit.
@@ -99,6 +134,13 @@ type UnitStatus struct {
 	PublicAddress string
 	Charm         string
 	Subordinates  map[string]UnitStatus
+
+	// Leader is true if the unit is the current leader of its service.
+	Leader bool
+
+	// LeaderExpiry holds the latest time at which the unit's leadership
+	// might still be valid; it is only set if Leader is true.
+	LeaderExpiry *time.Time
 }
 
 // TODO(ericsnow) Rename to ServiceNetworksSepcification.
@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/juju/storage"
+import (
+	"time"
+
+	"github.com/juju/juju/storage"
+)
 
 // MachineBlockDevices holds a machine tag and the block devices present
 // on that machine.
@@ -94,6 +98,9 @@ type StorageAttachment struct {
 	Kind     StorageKind
 	Location string
 	Life     Life
+
+	// Size is the size of the attached volume or filesystem, in MiB.
+	Size uint64 `json:"size,omitempty"`
 }
 
 // StorageAttachmentId identifies a storage attachment by the tags of the
@@ -209,6 +216,7 @@ type VolumeParams struct {
 	Attributes map[string]interface{}  `json:"attributes,omitempty"`
 	Tags       map[string]string       `json:"tags,omitempty"`
 	Attachment *VolumeAttachmentParams `json:"attachment,omitempty"`
+	SnapshotId string                  `json:"snapshotid,omitempty"`
 }
 
 // VolumeAttachmentParams holds the parameters for creating a volume
@@ -282,6 +290,127 @@ type VolumeAttachmentParamsResults struct {
 	Results []VolumeAttachmentParamsResult `json:"results,omitempty"`
 }
 
+// VolumeSnapshotIds holds a set of volume snapshot IDs.
+type VolumeSnapshotIds struct {
+	Ids []string `json:"ids"`
+}
+
+// VolumeSnapshot identifies a volume snapshot, and describes the
+// snapshot created by the storage provider.
+type VolumeSnapshot struct {
+	Id         string `json:"id"`
+	SnapshotId string `json:"snapshotid,omitempty"`
+	// Size is the size of the snapshotted volume in MiB.
+	Size uint64 `json:"size,omitempty"`
+	// Error, if non-empty, records why the snapshot could not be created.
+	Error string `json:"error,omitempty"`
+}
+
+// VolumeSnapshots describes a set of volume snapshots.
+type VolumeSnapshots struct {
+	VolumeSnapshots []VolumeSnapshot `json:"volumesnapshots"`
+}
+
+// VolumeSnapshotParams holds the parameters for creating a snapshot
+// of a storage volume.
+type VolumeSnapshotParams struct {
+	Id         string                 `json:"id"`
+	VolumeTag  string                 `json:"volumetag"`
+	VolumeId   string                 `json:"volumeid"`
+	Provider   string                 `json:"provider"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Tags       map[string]string      `json:"tags,omitempty"`
+}
+
+// VolumeSnapshotParamsResult holds provisioning parameters for a
+// volume snapshot.
+type VolumeSnapshotParamsResult struct {
+	Result VolumeSnapshotParams `json:"result"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// VolumeSnapshotParamsResults holds provisioning parameters for
+// multiple volume snapshots.
+type VolumeSnapshotParamsResults struct {
+	Results []VolumeSnapshotParamsResult `json:"results,omitempty"`
+}
+
+// VolumeResizeParams holds the parameters for resizing a volume.
+type VolumeResizeParams struct {
+	VolumeTag string `json:"volumetag"`
+	VolumeId  string `json:"volumeid"`
+	Provider  string `json:"provider"`
+	// Size is the requested size of the volume in MiB.
+	Size uint64 `json:"size"`
+}
+
+// VolumeResizeParamsResult holds the parameters for resizing a volume,
+// or an error.
+type VolumeResizeParamsResult struct {
+	Result VolumeResizeParams `json:"result"`
+	Error  *Error             `json:"error,omitempty"`
+}
+
+// VolumeResizeParamsResults holds the parameters for resizing multiple
+// volumes.
+type VolumeResizeParamsResults struct {
+	Results []VolumeResizeParamsResult `json:"results,omitempty"`
+}
+
+// VolumeResize identifies a resized volume, and describes the outcome
+// of resizing it.
+type VolumeResize struct {
+	VolumeTag string `json:"volumetag"`
+	// Size is the new size of the volume in MiB.
+	Size uint64 `json:"size,omitempty"`
+	// Error, if non-empty, records why the volume could not be resized.
+	Error string `json:"error,omitempty"`
+}
+
+// VolumeResizes describes a set of volume resize outcomes.
+type VolumeResizes struct {
+	VolumeResizes []VolumeResize `json:"volumeresizes"`
+}
+
+// FilesystemResizeParams holds the parameters for resizing a filesystem.
+type FilesystemResizeParams struct {
+	FilesystemTag string `json:"filesystemtag"`
+	VolumeTag     string `json:"volumetag,omitempty"`
+	FilesystemId  string `json:"filesystemid"`
+	Provider      string `json:"provider"`
+	// Size is the requested size of the filesystem in MiB.
+	Size uint64 `json:"size"`
+}
+
+// FilesystemResizeParamsResult holds the parameters for resizing a
+// filesystem, or an error.
+type FilesystemResizeParamsResult struct {
+	Result FilesystemResizeParams `json:"result"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// FilesystemResizeParamsResults holds the parameters for resizing
+// multiple filesystems.
+type FilesystemResizeParamsResults struct {
+	Results []FilesystemResizeParamsResult `json:"results,omitempty"`
+}
+
+// FilesystemResize identifies a resized filesystem, and describes the
+// outcome of resizing it.
+type FilesystemResize struct {
+	FilesystemTag string `json:"filesystemtag"`
+	// Size is the new size of the filesystem in MiB.
+	Size uint64 `json:"size,omitempty"`
+	// Error, if non-empty, records why the filesystem could not be
+	// resized.
+	Error string `json:"error,omitempty"`
+}
+
+// FilesystemResizes describes a set of filesystem resize outcomes.
+type FilesystemResizes struct {
+	FilesystemResizes []FilesystemResize `json:"filesystemresizes"`
+}
+
 // Filesystem identifies and describes a storage filesystem in the environment.
 type Filesystem struct {
 	FilesystemTag string         `json:"filesystemtag"`
@@ -529,6 +658,66 @@ type VolumeItemsResult struct {
 	Results []VolumeItem `json:"results,omitempty"`
 }
 
+// VolumeSnapshotFilter holds a filter for the volume snapshot list
+// API call.
+type VolumeSnapshotFilter struct {
+	// Volumes are volume tags to filter on.
+	Volumes []string `json:"volumes,omitempty"`
+}
+
+// VolumeSnapshotInstance describes a volume snapshot in the environment
+// for the purpose of volume snapshot CLI commands.
+type VolumeSnapshotInstance struct {
+	// Id is the Juju-assigned ID of the snapshot.
+	Id string `json:"id"`
+
+	// VolumeTag is the tag of the snapshotted volume.
+	VolumeTag string `json:"volumetag"`
+
+	// SnapshotId is the provider-supplied ID of the snapshot, if
+	// the snapshot has been created.
+	SnapshotId string `json:"snapshotid,omitempty"`
+
+	// Pool is the storage pool of the snapshotted volume.
+	Pool string `json:"pool"`
+
+	// Size is the size of the snapshotted volume in MiB.
+	Size uint64 `json:"size"`
+
+	// Created is the time at which the snapshot was requested.
+	Created time.Time `json:"created"`
+
+	// Status is one of "pending", "created" or "error".
+	Status string `json:"status"`
+
+	// Message describes why snapshot creation failed, if it did.
+	Message string `json:"message,omitempty"`
+}
+
+// VolumeSnapshotResult holds a volume snapshot, or an error.
+type VolumeSnapshotResult struct {
+	Result *VolumeSnapshotInstance `json:"result,omitempty"`
+	Error  *Error                  `json:"error,omitempty"`
+}
+
+// VolumeSnapshotResults holds a set of VolumeSnapshotResults.
+type VolumeSnapshotResults struct {
+	Results []VolumeSnapshotResult `json:"results,omitempty"`
+}
+
+// RestoreVolumeSnapshot identifies a volume snapshot, and the machine
+// to attach a volume restored from it to.
+type RestoreVolumeSnapshot struct {
+	Id         string `json:"id"`
+	MachineTag string `json:"machinetag"`
+}
+
+// RestoreVolumeSnapshots holds the arguments for restoring volumes
+// from a set of volume snapshots.
+type RestoreVolumeSnapshots struct {
+	Snapshots []RestoreVolumeSnapshot `json:"snapshots"`
+}
+
 // StorageConstraints contains constraints for storage instance.
 type StorageConstraints struct {
 	// Pool is the name of the storage pool from which to provision the
@@ -558,3 +747,54 @@ type StorageAddParams struct {
 type StoragesAddParams struct {
 	Storages []StorageAddParams `json:"storages"`
 }
+
+// StorageResize identifies a storage instance, and the size it
+// should be grown to.
+type StorageResize struct {
+	StorageTag string `json:"storagetag"`
+
+	// Size is the requested size of the storage instance, in MiB.
+	Size uint64 `json:"size"`
+}
+
+// StorageResizes holds a set of storage resize requests.
+type StorageResizes struct {
+	Storage []StorageResize `json:"storage"`
+}
+
+// StorageRemoval identifies a storage instance to remove, and what
+// should happen to the volume or filesystem backing it.
+type StorageRemoval struct {
+	StorageTag string `json:"storagetag"`
+
+	// ReleasePolicy is either "destroy", to destroy the storage
+	// and the volume or filesystem backing it, or "retain", to
+	// detach the storage from its unit and retain it. If empty,
+	// the release policy of the storage's pool is used.
+	ReleasePolicy string `json:"release-policy,omitempty"`
+}
+
+// StorageRemovals holds a set of storage removal requests.
+type StorageRemovals struct {
+	Storage []StorageRemoval `json:"storage"`
+}
+
+// StorageImport identifies an existing volume or filesystem in a
+// storage provider, to be imported as storage with the specified name.
+type StorageImport struct {
+	// Pool is the name of the storage pool whose provider
+	// is used to verify the volume or filesystem.
+	Pool string `json:"pool"`
+
+	// ProviderId is the provider's ID for the volume or filesystem.
+	ProviderId string `json:"provider-id"`
+
+	// StorageName is the name of the storage, as defined in the
+	// charm storage metadata, that the volume is imported as.
+	StorageName string `json:"storage-name"`
+}
+
+// StorageImports holds a set of storage import requests.
+type StorageImports struct {
+	Storage []StorageImport `json:"storage"`
+}
@@ -84,14 +84,14 @@ func NewProvisionerAPI(st *state.State, resources *common.Resources, authorizer
 			}
 			switch tag := tag.(type) {
 			case names.MachineTag:
-				parentId := state.ParentId(tag.Id())
+				parentId := common.ContainerHostId(st, tag.Id())
 				if parentId == "" {
 					// All top-level machines are accessible by the
 					// environment manager.
 					return isEnvironManager
 				}
-				// All containers with the authenticated machine as a
-				// parent are accessible by it.
+				// All containers hosted by the authenticated machine
+				// are accessible by it.
 				// TODO(dfc) sometimes authEntity tag is nil, which is fine because nil is
 				// only equal to nil, but it suggests someone is passing an authorizer
 				// with a nil tag.
@@ -307,6 +307,7 @@ func (p *ProvisionerAPI) ContainerConfig() (params.ContainerConfig, error) {
 	result.Proxy = config.ProxySettings()
 	result.AptProxy = config.AptProxySettings()
 	result.PreferIPv6 = config.PreferIPv6()
+	result.IPv6Only = config.IPv6Only()
 	result.AllowLXCLoopMounts, _ = config.AllowLXCLoopMounts()
 
 	return result, nil
@@ -589,6 +590,10 @@ func (p *ProvisionerAPI) machineVolumeParams(m *state.Machine) ([]params.VolumeP
 		if err != nil {
 			return nil, errors.Annotatef(err, "getting volume %q parameters", volumeTag.Id())
 		}
+		volumeParams.SnapshotId, err = common.VolumeSnapshotId(volume, p.st.VolumeSnapshot)
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting volume %q snapshot", volumeTag.Id())
+		}
 		provider, err := registry.StorageProvider(storage.ProviderType(volumeParams.Provider))
 		if err != nil {
 			return nil, errors.Annotate(err, "getting storage provider")
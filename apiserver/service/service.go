@@ -32,6 +32,10 @@ func init() {
 // Service defines the methods on the service API end point.
 type Service interface {
 	SetMetricCredentials(args params.ServiceMetricCredentials) (params.ErrorResults, error)
+	SetCharmsRolling(args params.ServicesSetCharmRolling) (params.ErrorResults, error)
+	ResumeRollingUpgrades(args params.Entities) (params.ErrorResults, error)
+	RollBackRollingUpgrades(args params.Entities) (params.ErrorResults, error)
+	RollBackCharms(args params.ServicesRollBackCharm) (params.RollBackCharmResults, error)
 }
 
 // API implements the service interface and is the concrete
@@ -103,6 +107,127 @@ func (api *API) ServicesDeployWithPlacement(args params.ServicesDeploy) (params.
 	return result, nil
 }
 
+// SetCharmsRolling starts rolling charm upgrades of the given
+// services, upgrading batch size units at a time.
+func (api *API) SetCharmsRolling(args params.ServicesSetCharmRolling) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Services)),
+	}
+	for i, arg := range args.Services {
+		err := api.setCharmRolling(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) setCharmRolling(arg params.ServiceSetCharmRolling) error {
+	// when forced, don't block
+	if !arg.Force {
+		if err := api.check.ChangeAllowed(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	curl, err := charm.ParseURL(arg.CharmUrl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ch, err := api.state.Charm(curl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	service, err := api.state.Service(arg.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.SetCharmRolling(ch, arg.Force, arg.BatchSize)
+}
+
+// ResumeRollingUpgrades resumes the paused rolling charm upgrades of
+// the given services.
+func (api *API) ResumeRollingUpgrades(args params.Entities) (params.ErrorResults, error) {
+	return api.updateRollingUpgrades(args, (*state.Service).ResumeRollingUpgrade)
+}
+
+// RollBackRollingUpgrades abandons the rolling charm upgrades of the
+// given services, returning them to their previous charms.
+func (api *API) RollBackRollingUpgrades(args params.Entities) (params.ErrorResults, error) {
+	return api.updateRollingUpgrades(args, (*state.Service).RollBackRollingUpgrade)
+}
+
+func (api *API) updateRollingUpgrades(args params.Entities, update func(*state.Service) error) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		service, err := api.state.Service(tag.Id())
+		if err == nil {
+			err = update(service)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RollBackCharms returns the given services to charms they used
+// previously, as recorded in their charm histories.
+func (api *API) RollBackCharms(args params.ServicesRollBackCharm) (params.RollBackCharmResults, error) {
+	result := params.RollBackCharmResults{
+		Results: make([]params.RollBackCharmResult, len(args.Services)),
+	}
+	for i, arg := range args.Services {
+		curl, err := api.rollBackCharm(arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].CharmURL = curl.String()
+	}
+	return result, nil
+}
+
+func (api *API) rollBackCharm(arg params.ServiceRollBackCharm) (*charm.URL, error) {
+	// when forced, don't block
+	if !arg.Force {
+		if err := api.check.ChangeAllowed(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	service, err := api.state.Service(arg.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	curl, err := service.PreviousCharmURL(arg.Revision)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, err := api.state.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The previous charm's config schema may not accept settings
+	// made since the service left it; refuse rather than silently
+	// dropping them.
+	settings, err := service.ConfigSettings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := ch.Config().ValidateSettings(settings); err != nil {
+		return nil, errors.Annotatef(err, "service settings not valid for charm %q", curl)
+	}
+	if err := service.SetCharm(ch, arg.Force); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return curl, nil
+}
+
 // DeployService fetches the charm from the charm store and deploys it.
 // The logic has been factored out into a common function which is called by
 // both the legacy API on the client facade, as well as the new service facade.
@@ -169,6 +294,9 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 			Placement:      args.Placement,
 			Networks:       requestedNetworks,
 			Storage:        args.Storage,
+
+			EndpointBindings: args.EndpointBindings,
+			ConfigProfile:    args.ConfigProfile,
 		})
 	return err
 }
@@ -6,6 +6,8 @@ package service_test
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path/filepath"
 	"sync"
 
 	"github.com/juju/errors"
@@ -614,3 +616,168 @@ func (s *recordingStorage) Remove(path string) error {
 	s.blobs.Remove(path)
 	return nil
 }
+
+func (s *serviceSuite) setUpRollingUpgrade(c *gc.C) (*state.Service, *state.Charm, *state.Charm) {
+	oldCharm := s.AddTestingCharm(c, "upgrade1")
+	newCharm := s.AddTestingCharm(c, "upgrade2")
+	svc := s.AddTestingService(c, "upgrade", oldCharm)
+	for i := 0; i < 2; i++ {
+		_, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return svc, oldCharm, newCharm
+}
+
+func (s *serviceSuite) TestSetCharmsRolling(c *gc.C) {
+	svc, _, newCharm := s.setUpRollingUpgrade(c)
+	results, err := s.serviceApi.SetCharmsRolling(params.ServicesSetCharmRolling{
+		Services: []params.ServiceSetCharmRolling{{
+			ServiceName: "upgrade",
+			CharmUrl:    newCharm.String(),
+			BatchSize:   1,
+		}, {
+			ServiceName: "upgrade",
+			CharmUrl:    newCharm.String(),
+			BatchSize:   1,
+		}, {
+			ServiceName: "missing",
+			CharmUrl:    newCharm.String(),
+			BatchSize:   1,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot start rolling upgrade of service "upgrade": rolling upgrade already in progress`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "missing" not found`)
+
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, ok := svc.RollingUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.BatchSize, gc.Equals, 1)
+	c.Assert(upgrade.Held, jc.DeepEquals, []string{"upgrade/0", "upgrade/1"})
+}
+
+func (s *serviceSuite) TestSetCharmsRollingBlocked(c *gc.C) {
+	_, _, newCharm := s.setUpRollingUpgrade(c)
+	s.BlockAllChanges(c, "TestSetCharmsRollingBlocked")
+	results, err := s.serviceApi.SetCharmsRolling(params.ServicesSetCharmRolling{
+		Services: []params.ServiceSetCharmRolling{{
+			ServiceName: "upgrade",
+			CharmUrl:    newCharm.String(),
+			BatchSize:   1,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertBlocked(c, results.OneError(), "TestSetCharmsRollingBlocked")
+}
+
+func (s *serviceSuite) TestResumeAndRollBackRollingUpgrades(c *gc.C) {
+	svc, oldCharm, newCharm := s.setUpRollingUpgrade(c)
+	err := svc.SetCharmRolling(newCharm, false, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.PauseRollingUpgrade("oops")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "service-upgrade"},
+		{Tag: "unit-upgrade-0"},
+	}}
+	results, err := s.serviceApi.ResumeRollingUpgrades(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `"unit-upgrade-0" is not a valid service tag`)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ := svc.RollingUpgrade()
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradeRunning)
+
+	results, err = s.serviceApi.RollBackRollingUpgrades(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := svc.RollingUpgrade()
+	c.Assert(ok, jc.IsFalse)
+	curl, _ := svc.CharmURL()
+	c.Assert(curl, gc.DeepEquals, oldCharm.URL())
+}
+
+// addDummyCharm adds a copy of the dummy charm with the given URL,
+// replacing its config with the given YAML.
+func (s *serviceSuite) addDummyCharm(c *gc.C, url, configYAML string) *state.Charm {
+	dir := testcharms.Repo.ClonedDir(c.MkDir(), "dummy")
+	err := ioutil.WriteFile(filepath.Join(dir.Path, "config.yaml"), []byte(configYAML), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	dir, err = charm.ReadCharmDir(dir.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := s.State.AddCharm(dir, charm.MustParseURL(url), "dummy-path", url+"-sha256")
+	c.Assert(err, jc.ErrorIsNil)
+	return ch
+}
+
+func (s *serviceSuite) TestRollBackCharms(c *gc.C) {
+	oldCharm := s.addDummyCharm(c, "cs:quantal/dummy-1", `
+options:
+  title: {type: string, default: My Title}
+`)
+	newCharm := s.addDummyCharm(c, "cs:quantal/dummy-2", `
+options:
+  title: {type: string, default: My Title}
+  outlook: {type: string}
+`)
+	svc := s.AddTestingService(c, "dummy", oldCharm)
+	err := svc.SetCharm(newCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.UpdateConfigSettings(charm.Settings{"title": "Foo", "outlook": "sunny"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.ServicesRollBackCharm{
+		Services: []params.ServiceRollBackCharm{{
+			ServiceName: "dummy",
+			Revision:    -1,
+		}, {
+			ServiceName: "dummy",
+			Revision:    5,
+		}, {
+			ServiceName: "missing",
+			Revision:    -1,
+		}},
+	}
+	results, err := s.serviceApi.RollBackCharms(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `service settings not valid for charm "cs:quantal/dummy-1": unknown option "outlook"`)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `revision 5 in charm history of service "dummy" not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "missing" not found`)
+
+	// Once the setting unknown to the old charm is cleared, the
+	// service can be rolled back.
+	err = svc.UpdateConfigSettings(charm.Settings{"outlook": nil})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.serviceApi.RollBackCharms(params.ServicesRollBackCharm{
+		Services: args.Services[:1],
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.RollBackCharmResult{{
+		CharmURL: "cs:quantal/dummy-1",
+	}})
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := svc.CharmURL()
+	c.Assert(curl, gc.DeepEquals, oldCharm.URL())
+	c.Assert(svc.CharmHistory(), gc.HasLen, 0)
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{"title": "Foo"})
+}
+
+func (s *serviceSuite) TestBlockRollBackCharms(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockRollBackCharms")
+	results, err := s.serviceApi.RollBackCharms(params.ServicesRollBackCharm{
+		Services: []params.ServiceRollBackCharm{{ServiceName: "dummy", Revision: -1}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertBlocked(c, results.Results[0].Error, "TestBlockRollBackCharms")
+}
@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces
+
+import (
+	"net"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// DiscoverSpaces fetches all subnets known to the provider and
+// returns those which are not yet known to Juju, grouped by the space
+// the provider proposes for each of them: the EC2 provider proposes
+// the "juju-space" tag of each subnet, and the MAAS provider the MAAS
+// space holding it. When args.Import is true, the proposed subnets and
+// any new spaces are also added to the environment in one go.
+func (api *spacesAPI) DiscoverSpaces(args params.DiscoverSpacesParams) (params.DiscoverSpacesResults, error) {
+	var results params.DiscoverSpacesResults
+	netEnv, err := api.networkingEnviron()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	providerSubnets, err := netEnv.Subnets(instance.UnknownId, nil)
+	if err != nil {
+		return results, errors.Annotate(err, "cannot get provider subnets")
+	}
+	knownSubnets, err := api.backing.AllSubnets()
+	if err != nil {
+		return results, errors.Annotate(err, "cannot get known subnets")
+	}
+	knownCIDRs := set.NewStrings()
+	for _, subnet := range knownSubnets {
+		knownCIDRs.Add(subnet.CIDR())
+	}
+
+	var newSubnets []common.BackingSubnetInfo
+	for _, subnet := range providerSubnets {
+		info, ok := discoveredSubnetInfo(subnet)
+		if !ok || knownCIDRs.Contains(info.CIDR) {
+			continue
+		}
+		knownCIDRs.Add(info.CIDR)
+		newSubnets = append(newSubnets, info)
+	}
+	if args.Import && len(newSubnets) > 0 {
+		if err := api.backing.ImportSubnets(newSubnets); err != nil {
+			return results, errors.Trace(err)
+		}
+	}
+	results.Results = proposeSpaces(newSubnets)
+	return results, nil
+}
+
+// discoveredSubnetInfo converts a subnet reported by the provider to
+// a BackingSubnetInfo which can be imported. It returns false if the
+// subnet cannot be imported.
+func discoveredSubnetInfo(subnet network.SubnetInfo) (common.BackingSubnetInfo, bool) {
+	_, ipNet, err := net.ParseCIDR(subnet.CIDR)
+	if err != nil || ipNet.String() != subnet.CIDR {
+		logger.Warningf("skipping provider subnet %q with invalid CIDR %q", subnet.ProviderId, subnet.CIDR)
+		return common.BackingSubnetInfo{}, false
+	}
+	spaceName := subnet.SpaceName
+	if spaceName != "" && !names.IsValidSpace(spaceName) {
+		logger.Warningf(
+			"provider subnet %q proposes invalid space name %q; using the default space",
+			subnet.CIDR, spaceName,
+		)
+		spaceName = ""
+	}
+	info := common.BackingSubnetInfo{
+		CIDR:              subnet.CIDR,
+		ProviderId:        string(subnet.ProviderId),
+		VLANTag:           subnet.VLANTag,
+		AvailabilityZones: subnet.AvailabilityZones,
+		SpaceName:         spaceName,
+	}
+	if subnet.AllocatableIPLow != nil && subnet.AllocatableIPHigh != nil {
+		info.AllocatableIPLow = subnet.AllocatableIPLow.String()
+		info.AllocatableIPHigh = subnet.AllocatableIPHigh.String()
+	}
+	return info, true
+}
+
+// proposeSpaces groups the given subnets by space name, with the
+// spaces sorted by name.
+func proposeSpaces(subnets []common.BackingSubnetInfo) []params.Space {
+	bySpace := make(map[string][]params.Subnet)
+	for _, info := range subnets {
+		var spaceTag string
+		if info.SpaceName != "" {
+			spaceTag = names.NewSpaceTag(info.SpaceName).String()
+		}
+		bySpace[info.SpaceName] = append(bySpace[info.SpaceName], params.Subnet{
+			CIDR:              info.CIDR,
+			ProviderId:        info.ProviderId,
+			VLANTag:           info.VLANTag,
+			SpaceTag:          spaceTag,
+			Zones:             info.AvailabilityZones,
+			StaticRangeLowIP:  net.ParseIP(info.AllocatableIPLow),
+			StaticRangeHighIP: net.ParseIP(info.AllocatableIPHigh),
+		})
+	}
+	spaceNames := make([]string, 0, len(bySpace))
+	for name := range bySpace {
+		spaceNames = append(spaceNames, name)
+	}
+	sort.Strings(spaceNames)
+	spaces := make([]params.Space, len(spaceNames))
+	for i, name := range spaceNames {
+		spaces[i] = params.Space{Name: name, Subnets: bySpace[name]}
+	}
+	return spaces
+}
+
+// networkingEnviron returns a environs.NetworkingEnviron instance
+// from the current environment config, if supported. If the
+// environment does not support environs.Networking, an error
+// satisfying errors.IsNotSupported() will be returned.
+func (api *spacesAPI) networkingEnviron() (environs.NetworkingEnviron, error) {
+	envConfig, err := api.backing.EnvironConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting environment config")
+	}
+	env, err := environs.New(envConfig)
+	if err != nil {
+		return nil, errors.Annotate(err, "opening environment")
+	}
+	if netEnv, ok := environs.SupportsNetworking(env); ok {
+		return netEnv, nil
+	}
+	return nil, errors.NotSupportedf("environment networking features")
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
@@ -99,3 +100,40 @@ func (s *stateShim) AllSpaces() ([]common.BackingSpace, error) {
 	}
 	return spaces, nil
 }
+
+func (s *stateShim) EnvironConfig() (*config.Config, error) {
+	return s.st.EnvironConfig()
+}
+
+func (s *stateShim) AllSubnets() ([]common.BackingSubnet, error) {
+	results, err := s.st.AllSubnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets := make([]common.BackingSubnet, len(results))
+	for i, result := range results {
+		subnets[i] = &subnetShim{subnet: result}
+	}
+	return subnets, nil
+}
+
+func (s *stateShim) ImportSubnets(infos []common.BackingSubnetInfo) error {
+	subnets := make([]state.SubnetInfo, len(infos))
+	for i, info := range infos {
+		var firstZone string
+		if len(info.AvailabilityZones) > 0 {
+			firstZone = info.AvailabilityZones[0]
+		}
+		subnets[i] = state.SubnetInfo{
+			CIDR:              info.CIDR,
+			VLANTag:           info.VLANTag,
+			ProviderId:        info.ProviderId,
+			AllocatableIPLow:  info.AllocatableIPLow,
+			AllocatableIPHigh: info.AllocatableIPHigh,
+			AvailabilityZone:  firstZone,
+			SpaceName:         info.SpaceName,
+		}
+	}
+	_, err := s.st.ImportSubnets(subnets)
+	return err
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
@@ -23,6 +24,7 @@ func init() {
 type API interface {
 	CreateSpaces(params.CreateSpacesParams) (params.ErrorResults, error)
 	ListSpaces() (params.ListSpacesResults, error)
+	DiscoverSpaces(params.DiscoverSpacesParams) (params.DiscoverSpacesResults, error)
 }
 
 // Backing defines the state methods this facede needs, so they can be
@@ -33,6 +35,16 @@ type Backing interface {
 
 	// AllSpaces returns all known Juju network spaces.
 	AllSpaces() ([]common.BackingSpace, error)
+
+	// EnvironConfig returns the current environment config.
+	EnvironConfig() (*config.Config, error)
+
+	// AllSubnets returns all known Juju subnets.
+	AllSubnets() ([]common.BackingSubnet, error)
+
+	// ImportSubnets adds the given subnets and any spaces they
+	// refer to which do not exist yet, all at once.
+	ImportSubnets([]common.BackingSubnetInfo) error
 }
 
 // spacesAPI implements the API interface.
@@ -51,8 +63,9 @@ func NewAPI(st *state.State, res *common.Resources, auth common.Authorizer) (API
 // newAPIWithBacking creates a new server-side Spaces API facade with
 // the given Backing.
 func newAPIWithBacking(backing Backing, resources *common.Resources, authorizer common.Authorizer) (API, error) {
-	// Only clients can access the Spaces facade.
-	if !authorizer.AuthClient() {
+	// Only clients and environment managers (which run the worker
+	// importing new provider subnets) can access the Spaces facade.
+	if !authorizer.AuthClient() && !authorizer.AuthEnvironManager() {
 		return nil, common.ErrPerm
 	}
 	return &spacesAPI{
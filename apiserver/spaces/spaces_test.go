@@ -4,6 +4,8 @@
 package spaces_test
 
 import (
+	"net"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -13,6 +15,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/spaces"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -71,7 +75,17 @@ func (s *SpacesSuite) TestNewAPIWithBacking(c *gc.C) {
 	// No calls so far.
 	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
 
-	// Agents are not allowed
+	// Environment managers are allowed.
+	managerAuthorizer := s.authorizer
+	managerAuthorizer.Tag = names.NewMachineTag("0")
+	managerAuthorizer.EnvironManager = true
+	facade, err = spaces.NewAPIWithBacking(
+		apiservertesting.BackingInstance, s.resources, managerAuthorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(facade, gc.NotNil)
+
+	// Other agents are not allowed
 	agentAuthorizer := s.authorizer
 	agentAuthorizer.Tag = names.NewMachineTag("42")
 	facade, err = spaces.NewAPIWithBacking(
@@ -275,3 +289,191 @@ func (s *SpacesSuite) TestListSpacesSubnetsSingleSubnetError(c *gc.C) {
 	}
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *SpacesSuite) setUpDiscovery(c *gc.C) {
+	apiservertesting.BackingInstance.SetUp(c,
+		apiservertesting.StubNetworkingEnvironName,
+		apiservertesting.WithoutZones,
+		apiservertesting.WithoutSpaces,
+		apiservertesting.WithSubnets,
+	)
+	s.PatchValue(&apiservertesting.ProviderInstance.Subnets, []network.SubnetInfo{{
+		// Already known, so not proposed again.
+		CIDR:       "10.10.0.0/24",
+		ProviderId: "sn-zadf00d",
+		SpaceName:  "private",
+	}, {
+		CIDR:              "10.20.0.0/24",
+		ProviderId:        "sn-db",
+		SpaceName:         "internal",
+		AvailabilityZones: []string{"zone1"},
+		AllocatableIPLow:  net.ParseIP("10.20.0.10"),
+		AllocatableIPHigh: net.ParseIP("10.20.0.100"),
+	}, {
+		CIDR:       "10.30.0.0/24",
+		ProviderId: "sn-web",
+		SpaceName:  "Not Valid",
+	}, {
+		CIDR:       "10.40.0.0/24",
+		ProviderId: "sn-cache",
+		SpaceName:  "internal",
+		VLANTag:    42,
+	}, {
+		CIDR:       "0.1.2.3/4",
+		ProviderId: "sn-bogus",
+	}})
+}
+
+var expectedProposedSpaces = []params.Space{{
+	Name: "",
+	Subnets: []params.Subnet{{
+		CIDR:       "10.30.0.0/24",
+		ProviderId: "sn-web",
+	}},
+}, {
+	Name: "internal",
+	Subnets: []params.Subnet{{
+		CIDR:              "10.20.0.0/24",
+		ProviderId:        "sn-db",
+		SpaceTag:          "space-internal",
+		Zones:             []string{"zone1"},
+		StaticRangeLowIP:  net.ParseIP("10.20.0.10"),
+		StaticRangeHighIP: net.ParseIP("10.20.0.100"),
+	}, {
+		CIDR:       "10.40.0.0/24",
+		ProviderId: "sn-cache",
+		SpaceTag:   "space-internal",
+		VLANTag:    42,
+	}},
+}}
+
+func (s *SpacesSuite) TestDiscoverSpacesProposesOnly(c *gc.C) {
+	s.setUpDiscovery(c)
+
+	results, err := s.facade.DiscoverSpaces(params.DiscoverSpacesParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, expectedProposedSpaces)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		apiservertesting.BackingCall("EnvironConfig"),
+		apiservertesting.ProviderCall("Open", apiservertesting.BackingInstance.EnvConfig),
+		apiservertesting.NetworkingEnvironCall("Subnets", instance.UnknownId, []network.Id(nil)),
+		apiservertesting.BackingCall("AllSubnets"),
+	)
+}
+
+func (s *SpacesSuite) TestDiscoverSpacesImports(c *gc.C) {
+	s.setUpDiscovery(c)
+
+	results, err := s.facade.DiscoverSpaces(params.DiscoverSpacesParams{Import: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, expectedProposedSpaces)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		apiservertesting.BackingCall("EnvironConfig"),
+		apiservertesting.ProviderCall("Open", apiservertesting.BackingInstance.EnvConfig),
+		apiservertesting.NetworkingEnvironCall("Subnets", instance.UnknownId, []network.Id(nil)),
+		apiservertesting.BackingCall("AllSubnets"),
+		apiservertesting.BackingCall("ImportSubnets", []common.BackingSubnetInfo{{
+			CIDR:              "10.20.0.0/24",
+			ProviderId:        "sn-db",
+			AvailabilityZones: []string{"zone1"},
+			SpaceName:         "internal",
+			AllocatableIPLow:  "10.20.0.10",
+			AllocatableIPHigh: "10.20.0.100",
+		}, {
+			CIDR:       "10.30.0.0/24",
+			ProviderId: "sn-web",
+		}, {
+			CIDR:       "10.40.0.0/24",
+			ProviderId: "sn-cache",
+			VLANTag:    42,
+			SpaceName:  "internal",
+		}}),
+	)
+}
+
+func (s *SpacesSuite) TestDiscoverSpacesProposesMAASSpaces(c *gc.C) {
+	s.setUpDiscovery(c)
+	// MAAS reports every subnet in a space, using its numeric
+	// subnet ids as provider ids.
+	s.PatchValue(&apiservertesting.ProviderInstance.Subnets, []network.SubnetInfo{{
+		CIDR:       "192.168.1.0/24",
+		ProviderId: "1",
+		SpaceName:  "space-0",
+	}, {
+		CIDR:       "172.16.0.0/24",
+		ProviderId: "2",
+		VLANTag:    42,
+		SpaceName:  "dmz",
+	}, {
+		CIDR:       "10.0.0.0/16",
+		ProviderId: "3",
+		SpaceName:  "space-0",
+	}})
+
+	results, err := s.facade.DiscoverSpaces(params.DiscoverSpacesParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.Space{{
+		Name: "dmz",
+		Subnets: []params.Subnet{{
+			CIDR:       "172.16.0.0/24",
+			ProviderId: "2",
+			VLANTag:    42,
+			SpaceTag:   "space-dmz",
+		}},
+	}, {
+		Name: "space-0",
+		Subnets: []params.Subnet{{
+			CIDR:       "192.168.1.0/24",
+			ProviderId: "1",
+			SpaceTag:   "space-space-0",
+		}, {
+			CIDR:       "10.0.0.0/16",
+			ProviderId: "3",
+			SpaceTag:   "space-space-0",
+		}},
+	}})
+}
+
+func (s *SpacesSuite) TestDiscoverSpacesNothingNew(c *gc.C) {
+	s.setUpDiscovery(c)
+	s.PatchValue(&apiservertesting.ProviderInstance.Subnets, []network.SubnetInfo{{
+		CIDR:       "10.10.0.0/24",
+		ProviderId: "sn-zadf00d",
+	}})
+
+	results, err := s.facade.DiscoverSpaces(params.DiscoverSpacesParams{Import: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		apiservertesting.BackingCall("EnvironConfig"),
+		apiservertesting.ProviderCall("Open", apiservertesting.BackingInstance.EnvConfig),
+		apiservertesting.NetworkingEnvironCall("Subnets", instance.UnknownId, []network.Id(nil)),
+		apiservertesting.BackingCall("AllSubnets"),
+	)
+}
+
+func (s *SpacesSuite) TestDiscoverSpacesNotSupported(c *gc.C) {
+	// The default stub environ does not support networking.
+	_, err := s.facade.DiscoverSpaces(params.DiscoverSpacesParams{})
+	c.Assert(err, gc.ErrorMatches, "environment networking features not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *SpacesSuite) TestDiscoverSpacesProviderError(c *gc.C) {
+	s.setUpDiscovery(c)
+	apiservertesting.SharedStub.SetErrors(nil, nil, errors.New("boom"))
+
+	_, err := s.facade.DiscoverSpaces(params.DiscoverSpacesParams{})
+	c.Assert(err, gc.ErrorMatches, "cannot get provider subnets: boom")
+}
+
+func (s *SpacesSuite) TestDiscoverSpacesImportError(c *gc.C) {
+	s.setUpDiscovery(c)
+	apiservertesting.SharedStub.SetErrors(nil, nil, nil, nil, errors.New("boom"))
+
+	_, err := s.facade.DiscoverSpaces(params.DiscoverSpacesParams{Import: true})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -5,6 +5,7 @@ package storage_test
 
 import (
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -16,8 +17,10 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/storage"
 	"github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	jujustorage "github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/poolmanager"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -42,6 +45,8 @@ type baseStorageSuite struct {
 	volumeTag        names.VolumeTag
 	volume           state.Volume
 	volumeAttachment state.VolumeAttachment
+	volumeSnapshot   *mockVolumeSnapshot
+	filesystem       *mockFilesystem
 	calls            []string
 
 	poolManager *mockPoolManager
@@ -83,6 +88,19 @@ const (
 	allVolumesCall                          = "allVolumes"
 	addStorageForUnitCall                   = "addStorageForUnit"
 	getBlockForTypeCall                     = "getBlockForType"
+	createVolumeSnapshotCall                = "createVolumeSnapshot"
+	volumeSnapshotsCall                     = "volumeSnapshots"
+	allVolumeSnapshotsCall                  = "allVolumeSnapshots"
+	restoreVolumeSnapshotCall               = "restoreVolumeSnapshot"
+	detachStorageCall                       = "detachStorage"
+	attachStorageCall                       = "attachStorage"
+	resizeVolumeCall                        = "resizeVolume"
+	resizeFilesystemCall                    = "resizeFilesystem"
+	destroyStorageInstanceCall              = "destroyStorageInstance"
+	storageReleasePolicyCall                = "storageReleasePolicy"
+	environConfigCall                       = "environConfig"
+	importVolumeCall                        = "importVolume"
+	importFilesystemCall                    = "importFilesystem"
 )
 
 func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
@@ -100,7 +118,7 @@ func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
 	s.machineTag = names.NewMachineTag("66")
 	filesystemTag := names.NewFilesystemTag("104")
 	s.volumeTag = names.NewVolumeTag("22")
-	filesystem := &mockFilesystem{tag: filesystemTag}
+	s.filesystem = &mockFilesystem{tag: filesystemTag}
 	filesystemAttachment := &mockFilesystemAttachment{}
 	s.volume = &mockVolume{tag: s.volumeTag, storage: s.storageTag}
 	s.volumeAttachment = &mockVolumeAttachment{
@@ -108,6 +126,8 @@ func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
 		MachineTag: s.machineTag,
 	}
 
+	s.volumeSnapshot = &mockVolumeSnapshot{id: "22/0", volume: s.volumeTag}
+
 	s.blocks = make(map[state.BlockType]state.Block)
 	return &mockState{
 		allStorageInstances: func() ([]state.StorageInstance, error) {
@@ -127,7 +147,7 @@ func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
 		storageInstanceFilesystem: func(sTag names.StorageTag) (state.Filesystem, error) {
 			s.calls = append(s.calls, storageInstanceFilesystemCall)
 			c.Assert(sTag, gc.DeepEquals, s.storageTag)
-			return filesystem, nil
+			return s.filesystem, nil
 		},
 		storageInstanceFilesystemAttachment: func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemAttachment, error) {
 			s.calls = append(s.calls, storageInstanceFilesystemAttachmentCall)
@@ -174,6 +194,69 @@ func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
 			val, found := s.blocks[t]
 			return val, found, nil
 		},
+		createVolumeSnapshot: func(tag names.VolumeTag) (state.VolumeSnapshot, error) {
+			s.calls = append(s.calls, createVolumeSnapshotCall)
+			c.Assert(tag, gc.DeepEquals, s.volumeTag)
+			return s.volumeSnapshot, nil
+		},
+		volumeSnapshots: func(tag names.VolumeTag) ([]state.VolumeSnapshot, error) {
+			s.calls = append(s.calls, volumeSnapshotsCall)
+			c.Assert(tag, gc.DeepEquals, s.volumeTag)
+			return []state.VolumeSnapshot{s.volumeSnapshot}, nil
+		},
+		allVolumeSnapshots: func() ([]state.VolumeSnapshot, error) {
+			s.calls = append(s.calls, allVolumeSnapshotsCall)
+			return []state.VolumeSnapshot{s.volumeSnapshot}, nil
+		},
+		restoreVolumeSnapshot: func(id string, machine names.MachineTag) (names.VolumeTag, error) {
+			s.calls = append(s.calls, restoreVolumeSnapshotCall)
+			c.Assert(id, gc.Equals, s.volumeSnapshot.id)
+			c.Assert(machine, gc.DeepEquals, s.machineTag)
+			return names.NewVolumeTag("66/1"), nil
+		},
+		detachStorage: func(sTag names.StorageTag, u names.UnitTag) error {
+			s.calls = append(s.calls, detachStorageCall)
+			c.Assert(sTag, gc.DeepEquals, s.storageTag)
+			c.Assert(u, gc.DeepEquals, s.unitTag)
+			return nil
+		},
+		attachStorage: func(sTag names.StorageTag, u names.UnitTag) error {
+			s.calls = append(s.calls, attachStorageCall)
+			c.Assert(sTag, gc.DeepEquals, s.storageTag)
+			return nil
+		},
+		resizeVolume: func(tag names.VolumeTag, size uint64) error {
+			s.calls = append(s.calls, resizeVolumeCall)
+			c.Assert(tag, gc.DeepEquals, s.volumeTag)
+			return nil
+		},
+		resizeFilesystem: func(tag names.FilesystemTag, size uint64) error {
+			s.calls = append(s.calls, resizeFilesystemCall)
+			c.Assert(tag, gc.DeepEquals, filesystemTag)
+			return nil
+		},
+		destroyStorageInstance: func(sTag names.StorageTag) error {
+			s.calls = append(s.calls, destroyStorageInstanceCall)
+			c.Assert(sTag, gc.DeepEquals, s.storageTag)
+			return nil
+		},
+		storageReleasePolicy: func(sTag names.StorageTag) (poolmanager.ReleasePolicy, error) {
+			s.calls = append(s.calls, storageReleasePolicyCall)
+			c.Assert(sTag, gc.DeepEquals, s.storageTag)
+			return poolmanager.ReleaseDestroy, nil
+		},
+		environConfig: func() (*config.Config, error) {
+			s.calls = append(s.calls, environConfigCall)
+			return coretesting.EnvironConfig(c), nil
+		},
+		importVolume: func(storageName string, info state.VolumeInfo) (names.StorageTag, error) {
+			s.calls = append(s.calls, importVolumeCall)
+			return names.NewStorageTag(storageName + "/1"), nil
+		},
+		importFilesystem: func(storageName string, info state.FilesystemInfo) (names.StorageTag, error) {
+			s.calls = append(s.calls, importFilesystemCall)
+			return names.NewStorageTag(storageName + "/1"), nil
+		},
 	}
 }
 
@@ -265,6 +348,8 @@ type mockState struct {
 	watchStorageAttachment              func(names.StorageTag, names.UnitTag) state.NotifyWatcher
 	watchFilesystemAttachment           func(names.MachineTag, names.FilesystemTag) state.NotifyWatcher
 	watchVolumeAttachment               func(names.MachineTag, names.VolumeTag) state.NotifyWatcher
+	watchFilesystem                     func(names.FilesystemTag) state.NotifyWatcher
+	watchVolume                         func(names.VolumeTag) state.NotifyWatcher
 	envName                             string
 	volume                              func(tag names.VolumeTag) (state.Volume, error)
 	machineVolumeAttachments            func(machine names.MachineTag) ([]state.VolumeAttachment, error)
@@ -272,6 +357,19 @@ type mockState struct {
 	allVolumes                          func() ([]state.Volume, error)
 	addStorageForUnit                   func(u names.UnitTag, name string, cons state.StorageConstraints) error
 	getBlockForType                     func(t state.BlockType) (state.Block, bool, error)
+	createVolumeSnapshot                func(names.VolumeTag) (state.VolumeSnapshot, error)
+	volumeSnapshots                     func(names.VolumeTag) ([]state.VolumeSnapshot, error)
+	allVolumeSnapshots                  func() ([]state.VolumeSnapshot, error)
+	restoreVolumeSnapshot               func(string, names.MachineTag) (names.VolumeTag, error)
+	detachStorage                       func(names.StorageTag, names.UnitTag) error
+	attachStorage                       func(names.StorageTag, names.UnitTag) error
+	resizeVolume                        func(names.VolumeTag, uint64) error
+	resizeFilesystem                    func(names.FilesystemTag, uint64) error
+	destroyStorageInstance              func(names.StorageTag) error
+	storageReleasePolicy                func(names.StorageTag) (poolmanager.ReleasePolicy, error)
+	environConfig                       func() (*config.Config, error)
+	importVolume                        func(string, state.VolumeInfo) (names.StorageTag, error)
+	importFilesystem                    func(string, state.FilesystemInfo) (names.StorageTag, error)
 }
 
 func (st *mockState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
@@ -318,6 +416,14 @@ func (st *mockState) WatchVolumeAttachment(mtag names.MachineTag, v names.Volume
 	return st.watchVolumeAttachment(mtag, v)
 }
 
+func (st *mockState) WatchFilesystem(f names.FilesystemTag) state.NotifyWatcher {
+	return st.watchFilesystem(f)
+}
+
+func (st *mockState) WatchVolume(v names.VolumeTag) state.NotifyWatcher {
+	return st.watchVolume(v)
+}
+
 func (st *mockState) EnvName() (string, error) {
 	return st.envName, nil
 }
@@ -346,6 +452,58 @@ func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, erro
 	return st.getBlockForType(t)
 }
 
+func (st *mockState) CreateVolumeSnapshot(tag names.VolumeTag) (state.VolumeSnapshot, error) {
+	return st.createVolumeSnapshot(tag)
+}
+
+func (st *mockState) VolumeSnapshots(tag names.VolumeTag) ([]state.VolumeSnapshot, error) {
+	return st.volumeSnapshots(tag)
+}
+
+func (st *mockState) AllVolumeSnapshots() ([]state.VolumeSnapshot, error) {
+	return st.allVolumeSnapshots()
+}
+
+func (st *mockState) RestoreVolumeSnapshot(id string, machine names.MachineTag) (names.VolumeTag, error) {
+	return st.restoreVolumeSnapshot(id, machine)
+}
+
+func (st *mockState) DetachStorage(storage names.StorageTag, unit names.UnitTag) error {
+	return st.detachStorage(storage, unit)
+}
+
+func (st *mockState) AttachStorage(storage names.StorageTag, unit names.UnitTag) error {
+	return st.attachStorage(storage, unit)
+}
+
+func (st *mockState) ResizeVolume(tag names.VolumeTag, size uint64) error {
+	return st.resizeVolume(tag, size)
+}
+
+func (st *mockState) ResizeFilesystem(tag names.FilesystemTag, size uint64) error {
+	return st.resizeFilesystem(tag, size)
+}
+
+func (st *mockState) DestroyStorageInstance(tag names.StorageTag) error {
+	return st.destroyStorageInstance(tag)
+}
+
+func (st *mockState) StorageReleasePolicy(tag names.StorageTag) (poolmanager.ReleasePolicy, error) {
+	return st.storageReleasePolicy(tag)
+}
+
+func (st *mockState) EnvironConfig() (*config.Config, error) {
+	return st.environConfig()
+}
+
+func (st *mockState) ImportVolume(storageName string, info state.VolumeInfo) (names.StorageTag, error) {
+	return st.importVolume(storageName, info)
+}
+
+func (st *mockState) ImportFilesystem(storageName string, info state.FilesystemInfo) (names.StorageTag, error) {
+	return st.importFilesystem(storageName, info)
+}
+
 type mockNotifyWatcher struct {
 	state.NotifyWatcher
 	changes chan struct{}
@@ -388,15 +546,65 @@ func (m *mockVolume) Status() (state.StatusInfo, error) {
 	return state.StatusInfo{Status: state.StatusAttached}, nil
 }
 
+type mockVolumeSnapshot struct {
+	state.VolumeSnapshot
+	id     string
+	volume names.VolumeTag
+	info   *state.VolumeSnapshotInfo
+}
+
+func (m *mockVolumeSnapshot) Id() string {
+	return m.id
+}
+
+func (m *mockVolumeSnapshot) Volume() names.VolumeTag {
+	return m.volume
+}
+
+func (m *mockVolumeSnapshot) Pool() string {
+	return "loop"
+}
+
+func (m *mockVolumeSnapshot) Size() uint64 {
+	return 1024
+}
+
+func (m *mockVolumeSnapshot) Created() time.Time {
+	return time.Time{}
+}
+
+func (m *mockVolumeSnapshot) Info() (state.VolumeSnapshotInfo, error) {
+	if m.info == nil {
+		return state.VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", m.id)
+	}
+	return *m.info, nil
+}
+
+func (m *mockVolumeSnapshot) Error() string {
+	return ""
+}
+
 type mockFilesystem struct {
 	state.Filesystem
-	tag names.FilesystemTag
+	tag    names.FilesystemTag
+	volume names.VolumeTag
 }
 
 func (m *mockFilesystem) FilesystemTag() names.FilesystemTag {
 	return m.tag
 }
 
+func (m *mockFilesystem) Info() (state.FilesystemInfo, error) {
+	return state.FilesystemInfo{}, nil
+}
+
+func (m *mockFilesystem) Volume() (names.VolumeTag, error) {
+	if m.volume == (names.VolumeTag{}) {
+		return names.VolumeTag{}, state.ErrNoBackingVolume
+	}
+	return m.volume, nil
+}
+
 type mockFilesystemAttachment struct {
 	state.FilesystemAttachment
 	tag names.FilesystemTag
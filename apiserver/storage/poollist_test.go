@@ -54,7 +54,7 @@ func (s *poolSuite) TestListManyResults(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	assertPoolNames(c, pools.Results,
 		"testpool0", "testpool1",
-		"dummy", "loop",
+		"dummy", "loop", "nfs",
 		"tmpfs", "rootfs")
 }
 
@@ -146,7 +146,7 @@ func (s *poolSuite) TestListByTypesOr(c *gc.C) {
 func (s *poolSuite) TestListNoPools(c *gc.C) {
 	pools, err := s.api.ListPools(params.StoragePoolFilter{})
 	c.Assert(err, jc.ErrorIsNil)
-	assertPoolNames(c, pools.Results, "dummy", "rootfs", "loop", "tmpfs")
+	assertPoolNames(c, pools.Results, "dummy", "rootfs", "loop", "nfs", "tmpfs")
 }
 
 func (s *poolSuite) TestListFilterEmpty(c *gc.C) {
@@ -7,7 +7,9 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage/poolmanager"
 )
 
 type storageAccess interface {
@@ -44,6 +46,12 @@ type storageAccess interface {
 	// WatchVolumeAttachment is required for storage functionality.
 	WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher
 
+	// WatchFilesystem is required for storage functionality.
+	WatchFilesystem(names.FilesystemTag) state.NotifyWatcher
+
+	// WatchVolume is required for storage functionality.
+	WatchVolume(names.VolumeTag) state.NotifyWatcher
+
 	// EnvName is required for pool functionality.
 	EnvName() (string, error)
 
@@ -64,6 +72,45 @@ type storageAccess interface {
 
 	// GetBlockForType is required to block operations.
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
+
+	// CreateVolumeSnapshot is required for storage snapshot create.
+	CreateVolumeSnapshot(tag names.VolumeTag) (state.VolumeSnapshot, error)
+
+	// VolumeSnapshots is required for storage snapshot list.
+	VolumeSnapshots(tag names.VolumeTag) ([]state.VolumeSnapshot, error)
+
+	// AllVolumeSnapshots is required for storage snapshot list.
+	AllVolumeSnapshots() ([]state.VolumeSnapshot, error)
+
+	// RestoreVolumeSnapshot is required for storage snapshot restore.
+	RestoreVolumeSnapshot(id string, machine names.MachineTag) (names.VolumeTag, error)
+
+	// DetachStorage is required for storage detach functionality.
+	DetachStorage(storage names.StorageTag, unit names.UnitTag) error
+
+	// AttachStorage is required for storage attach functionality.
+	AttachStorage(storage names.StorageTag, unit names.UnitTag) error
+
+	// DestroyStorageInstance is required for storage remove functionality.
+	DestroyStorageInstance(names.StorageTag) error
+
+	// StorageReleasePolicy is required for storage remove functionality.
+	StorageReleasePolicy(names.StorageTag) (poolmanager.ReleasePolicy, error)
+
+	// ResizeVolume is required for storage resize functionality.
+	ResizeVolume(tag names.VolumeTag, size uint64) error
+
+	// ResizeFilesystem is required for storage resize functionality.
+	ResizeFilesystem(tag names.FilesystemTag, size uint64) error
+
+	// EnvironConfig is required for storage import functionality.
+	EnvironConfig() (*config.Config, error)
+
+	// ImportVolume is required for storage import functionality.
+	ImportVolume(storageName string, info state.VolumeInfo) (names.StorageTag, error)
+
+	// ImportFilesystem is required for storage import functionality.
+	ImportFilesystem(storageName string, info state.FilesystemInfo) (names.StorageTag, error)
 }
 
 var getState = func(st *state.State) storageAccess {
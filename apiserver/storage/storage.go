@@ -629,3 +629,364 @@ func (a *API) AddToUnit(args params.StoragesAddParams) (params.ErrorResults, err
 	}
 	return params.ErrorResults{Results: result}, nil
 }
+
+// CreateVolumeSnapshots requests snapshots of the volumes with the
+// specified tags. Snapshots are created asynchronously by the storage
+// provisioner; the returned snapshots will initially be pending.
+// A "CHANGE" block can block this operation.
+func (a *API) CreateVolumeSnapshots(args params.Entities) (params.VolumeSnapshotResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.VolumeSnapshotResults{}, errors.Trace(err)
+	}
+	results := make([]params.VolumeSnapshotResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		tag, err := names.ParseVolumeTag(arg.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		snapshot, err := a.storage.CreateVolumeSnapshot(tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		result := common.VolumeSnapshotFromState(snapshot)
+		results[i].Result = &result
+	}
+	return params.VolumeSnapshotResults{Results: results}, nil
+}
+
+// ListVolumeSnapshots returns the volume snapshots in the environment,
+// optionally filtered by the volumes they were taken of.
+func (a *API) ListVolumeSnapshots(filter params.VolumeSnapshotFilter) (params.VolumeSnapshotResults, error) {
+	var snapshots []state.VolumeSnapshot
+	var results []params.VolumeSnapshotResult
+	if len(filter.Volumes) == 0 {
+		all, err := a.storage.AllVolumeSnapshots()
+		if err != nil {
+			return params.VolumeSnapshotResults{}, common.ServerError(err)
+		}
+		snapshots = all
+	}
+	for _, volume := range filter.Volumes {
+		tag, err := names.ParseVolumeTag(volume)
+		if err != nil {
+			results = append(results, params.VolumeSnapshotResult{
+				Error: common.ServerError(errors.Annotatef(err, "parsing volume tag %v", volume)),
+			})
+			continue
+		}
+		volumeSnapshots, err := a.storage.VolumeSnapshots(tag)
+		if err != nil {
+			results = append(results, params.VolumeSnapshotResult{
+				Error: common.ServerError(errors.Annotatef(err, "getting snapshots of volume %v", tag.Id())),
+			})
+			continue
+		}
+		snapshots = append(snapshots, volumeSnapshots...)
+	}
+	for _, snapshot := range snapshots {
+		result := common.VolumeSnapshotFromState(snapshot)
+		results = append(results, params.VolumeSnapshotResult{Result: &result})
+	}
+	return params.VolumeSnapshotResults{Results: results}, nil
+}
+
+// RestoreVolumeSnapshots creates new volumes from the specified volume
+// snapshots, attached to the specified machines. The tags of the new
+// volumes are returned.
+// A "CHANGE" block can block this operation.
+func (a *API) RestoreVolumeSnapshots(args params.RestoreVolumeSnapshots) (params.StringResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+	results := make([]params.StringResult, len(args.Snapshots))
+	for i, arg := range args.Snapshots {
+		machineTag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil {
+			results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		volumeTag, err := a.storage.RestoreVolumeSnapshot(arg.Id, machineTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = volumeTag.String()
+	}
+	return params.StringResults{Results: results}, nil
+}
+
+// Detach detaches the storage instances with the specified tags from
+// the units they are attached to. The storage instances are retained,
+// and may later be attached to other units with Attach.
+// A "CHANGE" block can block this operation.
+func (a *API) Detach(args params.Entities) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		err := a.detachStorage(arg.Tag)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (a *API) detachStorage(tagString string) error {
+	tag, err := names.ParseStorageTag(tagString)
+	if err != nil {
+		return common.ErrPerm
+	}
+	attachments, err := a.storage.StorageAttachments(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(attachments) == 0 {
+		return errors.Errorf("storage %q is not attached", tag.Id())
+	}
+	for _, att := range attachments {
+		if err := a.storage.DetachStorage(tag, att.Unit()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Remove removes the specified storage instances from the units they are
+// attached to. Storage released with the "destroy" policy is destroyed,
+// along with the volumes and filesystems bound to it; storage released
+// with the "retain" policy is detached and retained, so that it may later
+// be attached to another unit. If no policy is specified, the release
+// policy of the storage's pool is used.
+// A "CHANGE" block can block this operation.
+func (a *API) Remove(args params.StorageRemovals) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Storage))
+	for i, arg := range args.Storage {
+		err := a.removeStorage(arg)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (a *API) removeStorage(arg params.StorageRemoval) error {
+	tag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	var policy poolmanager.ReleasePolicy
+	if arg.ReleasePolicy == "" {
+		policy, err = a.storage.StorageReleasePolicy(tag)
+	} else {
+		policy, err = poolmanager.ParseReleasePolicy(arg.ReleasePolicy)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if policy == poolmanager.ReleaseDestroy {
+		return a.storage.DestroyStorageInstance(tag)
+	}
+	// The storage is to be retained; detach it from its units, if
+	// it is attached to any. Storage that is not attached is
+	// already retained.
+	attachments, err := a.storage.StorageAttachments(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, att := range attachments {
+		if err := a.storage.DetachStorage(tag, att.Unit()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Resize requests that the specified storage instances be grown to the
+// specified sizes. Block storage is resized by resizing its volume, as
+// are volume-backed filesystems; the filesystem is then grown to fill
+// the volume. Resizing is performed asynchronously by the storage
+// provisioner.
+// A "CHANGE" block can block this operation.
+func (a *API) Resize(args params.StorageResizes) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Storage))
+	for i, arg := range args.Storage {
+		err := a.resizeStorage(arg)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (a *API) resizeStorage(arg params.StorageResize) error {
+	tag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	si, err := a.storage.StorageInstance(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch si.Kind() {
+	case state.StorageKindBlock:
+		volume, err := a.storage.StorageInstanceVolume(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return a.storage.ResizeVolume(volume.VolumeTag(), arg.Size)
+	case state.StorageKindFilesystem:
+		filesystem, err := a.storage.StorageInstanceFilesystem(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		volumeTag, err := filesystem.Volume()
+		if err == nil {
+			return a.storage.ResizeVolume(volumeTag, arg.Size)
+		} else if errors.Cause(err) != state.ErrNoBackingVolume {
+			return errors.Trace(err)
+		}
+		return a.storage.ResizeFilesystem(filesystem.FilesystemTag(), arg.Size)
+	}
+	return errors.NotSupportedf("resizing storage of kind %q", si.Kind())
+}
+
+// Attach attaches detached storage instances to the specified units.
+// A "CHANGE" block can block this operation.
+func (a *API) Attach(args params.StorageAttachmentIds) (params.ErrorResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Ids))
+	for i, arg := range args.Ids {
+		storageTag, err := names.ParseStorageTag(arg.StorageTag)
+		if err != nil {
+			results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unitTag, err := names.ParseUnitTag(arg.UnitTag)
+		if err != nil {
+			results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = a.storage.AttachStorage(storageTag, unitTag)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// Import imports existing volumes and filesystems into the environment
+// as storage. Each volume or filesystem is verified with the provider of
+// the specified pool, and then recorded as detached storage, which may be
+// attached to units with Attach, or used by new units whose storage
+// constraints name the storage and pool. The tags of the new storage
+// instances are returned.
+// A "CHANGE" block can block this operation.
+func (a *API) Import(args params.StorageImports) (params.StringResults, error) {
+	blockChecker := common.NewBlockChecker(a.storage)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+	results := make([]params.StringResult, len(args.Storage))
+	for i, arg := range args.Storage {
+		storageTag, err := a.importStorage(arg)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = storageTag.String()
+	}
+	return params.StringResults{Results: results}, nil
+}
+
+func (a *API) importStorage(arg params.StorageImport) (names.StorageTag, error) {
+	providerType, cfg, err := common.StoragePoolConfig(arg.Pool, a.poolManager)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	provider, err := registry.StorageProvider(providerType)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	if importer, ok := provider.(storage.FilesystemImporter); ok {
+		return a.importFilesystem(importer, cfg, arg)
+	}
+	return a.importVolume(provider, cfg, arg)
+}
+
+func (a *API) importFilesystem(
+	importer storage.FilesystemImporter, cfg *storage.Config, arg params.StorageImport,
+) (names.StorageTag, error) {
+	filesystemInfo, err := importer.ImportFilesystem(cfg, arg.ProviderId)
+	if err != nil {
+		return names.StorageTag{}, errors.Annotatef(err, "checking filesystem %q", arg.ProviderId)
+	}
+	return a.storage.ImportFilesystem(arg.StorageName, state.FilesystemInfo{
+		FilesystemId: filesystemInfo.FilesystemId,
+		Size:         filesystemInfo.Size,
+		Pool:         arg.Pool,
+	})
+}
+
+func (a *API) importVolume(
+	provider storage.Provider, cfg *storage.Config, arg params.StorageImport,
+) (names.StorageTag, error) {
+	source, err := a.importVolumeSource(provider, cfg)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	results, err := source.DescribeVolumes([]string{arg.ProviderId})
+	if err != nil {
+		return names.StorageTag{}, errors.Annotatef(err, "describing volume %q", arg.ProviderId)
+	}
+	if len(results) != 1 {
+		return names.StorageTag{}, errors.Errorf(
+			"expected 1 result describing volume %q, got %d",
+			arg.ProviderId, len(results),
+		)
+	}
+	if results[0].Error != nil {
+		return names.StorageTag{}, errors.Annotatef(
+			results[0].Error, "describing volume %q", arg.ProviderId,
+		)
+	}
+	volumeInfo := results[0].VolumeInfo
+	return a.storage.ImportVolume(arg.StorageName, state.VolumeInfo{
+		HardwareId: volumeInfo.HardwareId,
+		Size:       volumeInfo.Size,
+		Pool:       arg.Pool,
+		VolumeId:   arg.ProviderId,
+		Persistent: volumeInfo.Persistent,
+	})
+}
+
+// importVolumeSource returns the volume source for the specified pool,
+// which must be environment-scoped; volumes managed by machine-scoped
+// sources cannot be inspected from the controller.
+func (a *API) importVolumeSource(provider storage.Provider, cfg *storage.Config) (storage.VolumeSource, error) {
+	pool := cfg.Name()
+	if provider.Scope() != storage.ScopeEnviron {
+		return nil, errors.NotSupportedf("importing storage from machine-scoped pool %q", pool)
+	}
+	if !provider.Supports(storage.StorageKindBlock) {
+		return nil, errors.NotSupportedf("importing storage from non-block pool %q", pool)
+	}
+	envConfig, err := a.storage.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	source, err := provider.VolumeSource(envConfig, cfg)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting volume source for pool %q", pool)
+	}
+	return source, nil
+}
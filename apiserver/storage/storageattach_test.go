@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type storageAttachSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageAttachSuite{})
+
+func (s *storageAttachSuite) TestDetach(c *gc.C) {
+	results, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}, {"machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	})
+	s.assertCalls(c, []string{getBlockForTypeCall, storageInstanceAttachmentsCall, detachStorageCall})
+}
+
+func (s *storageAttachSuite) TestDetachNotAttached(c *gc.C) {
+	s.state.storageInstanceAttachments = func(names.StorageTag) ([]state.StorageAttachment, error) {
+		s.calls = append(s.calls, storageInstanceAttachmentsCall)
+		return nil, nil
+	}
+	results, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{Error: &params.Error{Message: `storage "data/0" is not attached`}},
+	})
+	s.assertCalls(c, []string{getBlockForTypeCall, storageInstanceAttachmentsCall})
+}
+
+func (s *storageAttachSuite) TestDetachBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestDetachBlocked")
+	_, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestDetachBlocked")
+}
+
+func (s *storageAttachSuite) TestAttach(c *gc.C) {
+	results, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: s.storageTag.String(),
+			UnitTag:    "unit-mysql-1",
+		}, {
+			StorageTag: s.storageTag.String(),
+			UnitTag:    "machine-0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	})
+	s.assertCalls(c, []string{getBlockForTypeCall, attachStorageCall})
+}
+
+func (s *storageAttachSuite) TestAttachError(c *gc.C) {
+	s.state.attachStorage = func(names.StorageTag, names.UnitTag) error {
+		s.calls = append(s.calls, attachStorageCall)
+		return errors.New("storage is not detached")
+	}
+	results, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: s.storageTag.String(),
+			UnitTag:    "unit-mysql-1",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{Error: &params.Error{Message: "storage is not detached"}},
+	})
+}
+
+func (s *storageAttachSuite) TestAttachBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestAttachBlocked")
+	_, err := s.api.Attach(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: s.storageTag.String(),
+			UnitTag:    "unit-mysql-1",
+		}},
+	})
+	s.assertBlocked(c, err, "TestAttachBlocked")
+}
@@ -0,0 +1,197 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	jujustorage "github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider/dummy"
+	"github.com/juju/juju/storage/provider/registry"
+)
+
+type storageImportSuite struct {
+	baseStorageSuite
+
+	describeVolumes func([]string) ([]jujustorage.DescribeVolumesResult, error)
+}
+
+var _ = gc.Suite(&storageImportSuite{})
+
+func (s *storageImportSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+
+	s.describeVolumes = func(volIds []string) ([]jujustorage.DescribeVolumesResult, error) {
+		c.Assert(volIds, jc.DeepEquals, []string{"vol-123"})
+		return []jujustorage.DescribeVolumesResult{{
+			VolumeInfo: &jujustorage.VolumeInfo{
+				VolumeId:   "vol-123",
+				HardwareId: "hw-123",
+				Size:       1024,
+				Persistent: true,
+			},
+		}}, nil
+	}
+	registry.RegisterProvider("importable", &dummy.StorageProvider{
+		StorageScope: jujustorage.ScopeEnviron,
+		IsDynamic:    true,
+		VolumeSourceFunc: func(*config.Config, *jujustorage.Config) (jujustorage.VolumeSource, error) {
+			return &dummy.VolumeSource{
+				DescribeVolumesFunc: s.describeVolumes,
+			}, nil
+		},
+	})
+	registry.RegisterProvider("machine-importable", &dummy.StorageProvider{
+		StorageScope: jujustorage.ScopeMachine,
+		IsDynamic:    true,
+	})
+	registry.RegisterProvider("filesystem-importable", &filesystemImporter{
+		StorageProvider: dummy.StorageProvider{
+			StorageScope: jujustorage.ScopeMachine,
+			IsDynamic:    true,
+		},
+		importFilesystem: func(cfg *jujustorage.Config, id string) (*jujustorage.FilesystemInfo, error) {
+			c.Assert(cfg.Name(), gc.Equals, "filesystem-pool")
+			if id != "server:/export/data" {
+				return nil, errors.NotValidf("filesystem ID %q", id)
+			}
+			return &jujustorage.FilesystemInfo{FilesystemId: id, Size: 2048}, nil
+		},
+	})
+	s.AddCleanup(func(*gc.C) {
+		registry.RegisterProvider("importable", nil)
+		registry.RegisterProvider("machine-importable", nil)
+		registry.RegisterProvider("filesystem-importable", nil)
+	})
+
+	var err error
+	s.pools["import-pool"], err = jujustorage.NewConfig("import-pool", "importable", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.pools["machine-pool"], err = jujustorage.NewConfig("machine-pool", "machine-importable", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.pools["filesystem-pool"], err = jujustorage.NewConfig("filesystem-pool", "filesystem-importable", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// filesystemImporter is a storage provider that supports
+// importing filesystems.
+type filesystemImporter struct {
+	dummy.StorageProvider
+	importFilesystem func(*jujustorage.Config, string) (*jujustorage.FilesystemInfo, error)
+}
+
+func (p *filesystemImporter) ImportFilesystem(cfg *jujustorage.Config, id string) (*jujustorage.FilesystemInfo, error) {
+	return p.importFilesystem(cfg, id)
+}
+
+func (s *storageImportSuite) TestImport(c *gc.C) {
+	s.state.importVolume = func(storageName string, info state.VolumeInfo) (names.StorageTag, error) {
+		s.calls = append(s.calls, importVolumeCall)
+		c.Assert(storageName, gc.Equals, "data")
+		c.Assert(info, jc.DeepEquals, state.VolumeInfo{
+			VolumeId:   "vol-123",
+			HardwareId: "hw-123",
+			Pool:       "import-pool",
+			Size:       1024,
+			Persistent: true,
+		})
+		return names.NewStorageTag("data/1"), nil
+	}
+	results, err := s.api.Import(params.StorageImports{
+		Storage: []params.StorageImport{{
+			Pool: "import-pool", ProviderId: "vol-123", StorageName: "data",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringResult{{Result: "storage-data-1"}})
+	s.assertCalls(c, []string{getBlockForTypeCall, environConfigCall, importVolumeCall})
+}
+
+func (s *storageImportSuite) TestImportVolumeNotFound(c *gc.C) {
+	s.describeVolumes = func(volIds []string) ([]jujustorage.DescribeVolumesResult, error) {
+		return []jujustorage.DescribeVolumesResult{{
+			Error: errors.NotFoundf("volume %q", volIds[0]),
+		}}, nil
+	}
+	results, err := s.api.Import(params.StorageImports{
+		Storage: []params.StorageImport{{
+			Pool: "import-pool", ProviderId: "vol-123", StorageName: "data",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringResult{{
+		Error: &params.Error{
+			Code:    params.CodeNotFound,
+			Message: `describing volume "vol-123": volume "vol-123" not found`,
+		},
+	}})
+	s.assertCalls(c, []string{getBlockForTypeCall, environConfigCall})
+}
+
+func (s *storageImportSuite) TestImportMachineScoped(c *gc.C) {
+	results, err := s.api.Import(params.StorageImports{
+		Storage: []params.StorageImport{{
+			Pool: "machine-pool", ProviderId: "vol-123", StorageName: "data",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringResult{{
+		Error: &params.Error{
+			Message: `importing storage from machine-scoped pool "machine-pool" not supported`,
+		},
+	}})
+	s.assertCalls(c, []string{getBlockForTypeCall})
+}
+
+func (s *storageImportSuite) TestImportFilesystem(c *gc.C) {
+	s.state.importFilesystem = func(storageName string, info state.FilesystemInfo) (names.StorageTag, error) {
+		s.calls = append(s.calls, importFilesystemCall)
+		c.Assert(storageName, gc.Equals, "data")
+		c.Assert(info, jc.DeepEquals, state.FilesystemInfo{
+			FilesystemId: "server:/export/data",
+			Pool:         "filesystem-pool",
+			Size:         2048,
+		})
+		return names.NewStorageTag("data/1"), nil
+	}
+	results, err := s.api.Import(params.StorageImports{
+		Storage: []params.StorageImport{{
+			Pool: "filesystem-pool", ProviderId: "server:/export/data", StorageName: "data",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringResult{{Result: "storage-data-1"}})
+	s.assertCalls(c, []string{getBlockForTypeCall, importFilesystemCall})
+}
+
+func (s *storageImportSuite) TestImportFilesystemInvalid(c *gc.C) {
+	results, err := s.api.Import(params.StorageImports{
+		Storage: []params.StorageImport{{
+			Pool: "filesystem-pool", ProviderId: "server:/elsewhere", StorageName: "data",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringResult{{
+		Error: &params.Error{
+			Message: `checking filesystem "server:/elsewhere": filesystem ID "server:/elsewhere" not valid`,
+		},
+	}})
+	s.assertCalls(c, []string{getBlockForTypeCall})
+}
+
+func (s *storageImportSuite) TestImportBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestImportBlocked")
+	_, err := s.api.Import(params.StorageImports{
+		Storage: []params.StorageImport{{
+			Pool: "import-pool", ProviderId: "vol-123", StorageName: "data",
+		}},
+	})
+	s.assertBlocked(c, err, "TestImportBlocked")
+}
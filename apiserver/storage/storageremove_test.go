@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage/poolmanager"
+)
+
+type storageRemoveSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageRemoveSuite{})
+
+func (s *storageRemoveSuite) TestRemovePoolPolicy(c *gc.C) {
+	results, err := s.api.Remove(params.StorageRemovals{
+		Storage: []params.StorageRemoval{
+			{StorageTag: s.storageTag.String()},
+			{StorageTag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall, storageReleasePolicyCall, destroyStorageInstanceCall,
+	})
+}
+
+func (s *storageRemoveSuite) TestRemoveRetainPoolPolicy(c *gc.C) {
+	s.state.storageReleasePolicy = func(names.StorageTag) (poolmanager.ReleasePolicy, error) {
+		s.calls = append(s.calls, storageReleasePolicyCall)
+		return poolmanager.ReleaseRetain, nil
+	}
+	results, err := s.api.Remove(params.StorageRemovals{
+		Storage: []params.StorageRemoval{{StorageTag: s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall, storageReleasePolicyCall,
+		storageInstanceAttachmentsCall, detachStorageCall,
+	})
+}
+
+func (s *storageRemoveSuite) TestRemoveDestroy(c *gc.C) {
+	results, err := s.api.Remove(params.StorageRemovals{
+		Storage: []params.StorageRemoval{{
+			StorageTag:    s.storageTag.String(),
+			ReleasePolicy: "destroy",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	s.assertCalls(c, []string{getBlockForTypeCall, destroyStorageInstanceCall})
+}
+
+func (s *storageRemoveSuite) TestRemoveRetain(c *gc.C) {
+	results, err := s.api.Remove(params.StorageRemovals{
+		Storage: []params.StorageRemoval{{
+			StorageTag:    s.storageTag.String(),
+			ReleasePolicy: "retain",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall, storageInstanceAttachmentsCall, detachStorageCall,
+	})
+}
+
+func (s *storageRemoveSuite) TestRemoveInvalidPolicy(c *gc.C) {
+	results, err := s.api.Remove(params.StorageRemovals{
+		Storage: []params.StorageRemoval{{
+			StorageTag:    s.storageTag.String(),
+			ReleasePolicy: "shred",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{Error: &params.Error{Message: `release policy "shred" not valid`}},
+	})
+}
+
+func (s *storageRemoveSuite) TestRemoveBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestRemoveBlocked")
+	_, err := s.api.Remove(params.StorageRemovals{
+		Storage: []params.StorageRemoval{{StorageTag: s.storageTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestRemoveBlocked")
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type storageResizeSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageResizeSuite{})
+
+func (s *storageResizeSuite) TestResizeBlock(c *gc.C) {
+	s.storageInstance.kind = state.StorageKindBlock
+	results, err := s.api.Resize(params.StorageResizes{
+		Storage: []params.StorageResize{
+			{StorageTag: s.storageTag.String(), Size: 2048},
+			{StorageTag: "machine-0", Size: 2048},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall, storageInstanceCall, storageInstanceVolumeCall, resizeVolumeCall,
+	})
+}
+
+func (s *storageResizeSuite) TestResizeFilesystem(c *gc.C) {
+	results, err := s.api.Resize(params.StorageResizes{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall, storageInstanceCall, storageInstanceFilesystemCall, resizeFilesystemCall,
+	})
+}
+
+func (s *storageResizeSuite) TestResizeVolumeBackedFilesystem(c *gc.C) {
+	s.filesystem.volume = s.volumeTag
+	results, err := s.api.Resize(params.StorageResizes{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	s.assertCalls(c, []string{
+		getBlockForTypeCall, storageInstanceCall, storageInstanceFilesystemCall, resizeVolumeCall,
+	})
+}
+
+func (s *storageResizeSuite) TestResizeError(c *gc.C) {
+	s.state.resizeFilesystem = func(names.FilesystemTag, uint64) error {
+		s.calls = append(s.calls, resizeFilesystemCall)
+		return errors.New("new size 1024MiB must be greater than current size 1024MiB")
+	}
+	results, err := s.api.Resize(params.StorageResizes{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 1024}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{Error: &params.Error{Message: "new size 1024MiB must be greater than current size 1024MiB"}},
+	})
+}
+
+func (s *storageResizeSuite) TestResizeBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestResizeBlocked")
+	_, err := s.api.Resize(params.StorageResizes{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	s.assertBlocked(c, err, "TestResizeBlocked")
+}
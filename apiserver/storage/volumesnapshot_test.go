@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type volumeSnapshotSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&volumeSnapshotSuite{})
+
+func (s *volumeSnapshotSuite) expectedSnapshot() *params.VolumeSnapshotInstance {
+	return &params.VolumeSnapshotInstance{
+		Id:        "22/0",
+		VolumeTag: s.volumeTag.String(),
+		Pool:      "loop",
+		Size:      1024,
+		Created:   time.Time{},
+		Status:    "pending",
+	}
+}
+
+func (s *volumeSnapshotSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	results, err := s.api.CreateVolumeSnapshots(params.Entities{
+		Entities: []params.Entity{{s.volumeTag.String()}, {"machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.VolumeSnapshotResult{
+		{Result: s.expectedSnapshot()},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	})
+	s.assertCalls(c, []string{getBlockForTypeCall, createVolumeSnapshotCall})
+}
+
+func (s *volumeSnapshotSuite) TestCreateVolumeSnapshotsBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestCreateVolumeSnapshotsBlocked")
+	_, err := s.api.CreateVolumeSnapshots(params.Entities{
+		Entities: []params.Entity{{s.volumeTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestCreateVolumeSnapshotsBlocked")
+}
+
+func (s *volumeSnapshotSuite) TestListVolumeSnapshots(c *gc.C) {
+	s.volumeSnapshot.info = &state.VolumeSnapshotInfo{SnapshotId: "snap-shot", Size: 2048}
+	results, err := s.api.ListVolumeSnapshots(params.VolumeSnapshotFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	expected := s.expectedSnapshot()
+	expected.SnapshotId = "snap-shot"
+	expected.Size = 2048
+	expected.Status = "created"
+	c.Assert(results.Results, jc.DeepEquals, []params.VolumeSnapshotResult{{Result: expected}})
+	s.assertCalls(c, []string{allVolumeSnapshotsCall})
+}
+
+func (s *volumeSnapshotSuite) TestListVolumeSnapshotsFilter(c *gc.C) {
+	results, err := s.api.ListVolumeSnapshots(params.VolumeSnapshotFilter{
+		Volumes: []string{s.volumeTag.String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.VolumeSnapshotResult{{Result: s.expectedSnapshot()}})
+	s.assertCalls(c, []string{volumeSnapshotsCall})
+}
+
+func (s *volumeSnapshotSuite) TestRestoreVolumeSnapshots(c *gc.C) {
+	results, err := s.api.RestoreVolumeSnapshots(params.RestoreVolumeSnapshots{
+		Snapshots: []params.RestoreVolumeSnapshot{{
+			Id: "22/0", MachineTag: s.machineTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringResult{{Result: "volume-66-1"}})
+	s.assertCalls(c, []string{getBlockForTypeCall, restoreVolumeSnapshotCall})
+}
@@ -28,6 +28,12 @@ type provisionerState interface {
 	WatchEnvironVolumeAttachments() state.StringsWatcher
 	WatchMachineVolumes(names.MachineTag) state.StringsWatcher
 	WatchMachineVolumeAttachments(names.MachineTag) state.StringsWatcher
+	WatchEnvironVolumeSnapshots() state.StringsWatcher
+	WatchMachineVolumeSnapshots(names.MachineTag) state.StringsWatcher
+	WatchEnvironVolumeResizes() state.StringsWatcher
+	WatchMachineVolumeResizes(names.MachineTag) state.StringsWatcher
+	WatchEnvironFilesystemResizes() state.StringsWatcher
+	WatchMachineFilesystemResizes(names.MachineTag) state.StringsWatcher
 	WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher
 
 	StorageInstance(names.StorageTag) (state.StorageInstance, error)
@@ -38,6 +44,9 @@ type provisionerState interface {
 	Volume(names.VolumeTag) (state.Volume, error)
 	VolumeAttachment(names.MachineTag, names.VolumeTag) (state.VolumeAttachment, error)
 	VolumeAttachments(names.VolumeTag) ([]state.VolumeAttachment, error)
+	VolumeSnapshot(string) (state.VolumeSnapshot, error)
+	PendingVolumeResize(names.VolumeTag) (uint64, error)
+	PendingFilesystemResize(names.FilesystemTag) (uint64, error)
 
 	RemoveFilesystem(names.FilesystemTag) error
 	RemoveFilesystemAttachment(names.MachineTag, names.FilesystemTag) error
@@ -48,6 +57,12 @@ type provisionerState interface {
 	SetFilesystemAttachmentInfo(names.MachineTag, names.FilesystemTag, state.FilesystemAttachmentInfo) error
 	SetVolumeInfo(names.VolumeTag, state.VolumeInfo) error
 	SetVolumeAttachmentInfo(names.MachineTag, names.VolumeTag, state.VolumeAttachmentInfo) error
+	SetVolumeSnapshotInfo(string, state.VolumeSnapshotInfo) error
+	SetVolumeSnapshotError(string, string) error
+	SetVolumeResized(names.VolumeTag, uint64) error
+	SetFilesystemResized(names.FilesystemTag, uint64) error
+	FailVolumeResize(names.VolumeTag, string) error
+	FailFilesystemResize(names.FilesystemTag, string) error
 }
 
 type stateShim struct {
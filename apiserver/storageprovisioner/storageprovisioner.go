@@ -61,12 +61,12 @@ func NewStorageProvisionerAPI(st *state.State, resources *common.Resources, auth
 			// scoped to their own machine.
 			return true
 		}
-		parentId := state.ParentId(tag.Id())
+		parentId := common.ContainerHostId(st, tag.Id())
 		if parentId == "" {
 			return allowEnvironManager && authorizer.AuthEnvironManager()
 		}
-		// All containers with the authenticated
-		// machine as a parent are accessible by it.
+		// All containers hosted by the authenticated
+		// machine are accessible by it.
 		return names.NewMachineTag(parentId) == authEntityTag
 	}
 	getScopeAuthFunc := func() (common.AuthFunc, error) {
@@ -266,6 +266,24 @@ func (s *StorageProvisionerAPI) WatchFilesystems(args params.Entities) (params.S
 	return s.watchStorageEntities(args, s.st.WatchEnvironFilesystems, s.st.WatchMachineFilesystems)
 }
 
+// WatchVolumeSnapshots watches for changes to snapshots of volumes
+// scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchVolumeSnapshots(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironVolumeSnapshots, s.st.WatchMachineVolumeSnapshots)
+}
+
+// WatchVolumeResizes watches for changes to pending resizes of volumes
+// scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchVolumeResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironVolumeResizes, s.st.WatchMachineVolumeResizes)
+}
+
+// WatchFilesystemResizes watches for changes to pending resizes of
+// filesystems scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchFilesystemResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironFilesystemResizes, s.st.WatchMachineFilesystemResizes)
+}
+
 func (s *StorageProvisionerAPI) watchStorageEntities(
 	args params.Entities,
 	watchEnvironStorage func() state.StringsWatcher,
@@ -577,6 +595,10 @@ func (s *StorageProvisionerAPI) VolumeParams(args params.Entities) (params.Volum
 		if err != nil {
 			return params.VolumeParams{}, err
 		}
+		volumeParams.SnapshotId, err = common.VolumeSnapshotId(volume, s.st.VolumeSnapshot)
+		if err != nil {
+			return params.VolumeParams{}, err
+		}
 		if len(volumeAttachments) == 1 {
 			// There is exactly one attachment to be made, so make
 			// it immediately. Otherwise we will defer attachments
@@ -622,6 +644,167 @@ func (s *StorageProvisionerAPI) VolumeParams(args params.Entities) (params.Volum
 	return results, nil
 }
 
+// VolumeSnapshotParams returns the parameters for creating the volume
+// snapshots with the specified IDs. Snapshots that have already been
+// created, or that could not be created, are reported with an error
+// satisfying params.IsCodeAlreadyExists.
+func (s *StorageProvisionerAPI) VolumeSnapshotParams(args params.VolumeSnapshotIds) (params.VolumeSnapshotParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	envConfig, err := s.st.EnvironConfig()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	results := params.VolumeSnapshotParamsResults{
+		Results: make([]params.VolumeSnapshotParamsResult, len(args.Ids)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(id string) (params.VolumeSnapshotParams, error) {
+		snapshot, err := s.st.VolumeSnapshot(id)
+		if errors.IsNotFound(err) {
+			return params.VolumeSnapshotParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		if !canAccess(snapshot.Volume()) {
+			return params.VolumeSnapshotParams{}, common.ErrPerm
+		}
+		if _, err := snapshot.Info(); err == nil || snapshot.Error() != "" {
+			return params.VolumeSnapshotParams{}, errors.AlreadyExistsf("volume snapshot %q", id)
+		}
+		return common.VolumeSnapshotParams(snapshot, envConfig, poolManager)
+	}
+	for i, id := range args.Ids {
+		var result params.VolumeSnapshotParamsResult
+		snapshotParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = snapshotParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags. Volumes that have no pending resize are
+// reported with an error satisfying params.IsCodeNotFound.
+func (s *StorageProvisionerAPI) VolumeResizeParams(args params.Entities) (params.VolumeResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeResizeParamsResults{}, err
+	}
+	results := params.VolumeResizeParamsResults{
+		Results: make([]params.VolumeResizeParamsResult, len(args.Entities)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(arg params.Entity) (params.VolumeResizeParams, error) {
+		tag, err := names.ParseVolumeTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		}
+		volume, err := s.st.Volume(tag)
+		if errors.IsNotFound(err) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		size, err := s.st.PendingVolumeResize(tag)
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		info, err := volume.Info()
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		providerType, _, err := common.StoragePoolConfig(info.Pool, poolManager)
+		if err != nil {
+			return params.VolumeResizeParams{}, errors.Trace(err)
+		}
+		return params.VolumeResizeParams{
+			VolumeTag: tag.String(),
+			VolumeId:  info.VolumeId,
+			Provider:  string(providerType),
+			Size:      size,
+		}, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.VolumeResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// FilesystemResizeParams returns the parameters for resizing the
+// filesystems with the specified tags. Filesystems that have no pending
+// resize are reported with an error satisfying params.IsCodeNotFound.
+func (s *StorageProvisionerAPI) FilesystemResizeParams(args params.Entities) (params.FilesystemResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.FilesystemResizeParamsResults{}, err
+	}
+	results := params.FilesystemResizeParamsResults{
+		Results: make([]params.FilesystemResizeParamsResult, len(args.Entities)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(arg params.Entity) (params.FilesystemResizeParams, error) {
+		tag, err := names.ParseFilesystemTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.FilesystemResizeParams{}, common.ErrPerm
+		}
+		filesystem, err := s.st.Filesystem(tag)
+		if errors.IsNotFound(err) {
+			return params.FilesystemResizeParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		size, err := s.st.PendingFilesystemResize(tag)
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		info, err := filesystem.Info()
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		providerType, _, err := common.StoragePoolConfig(info.Pool, poolManager)
+		if err != nil {
+			return params.FilesystemResizeParams{}, errors.Trace(err)
+		}
+		result := params.FilesystemResizeParams{
+			FilesystemTag: tag.String(),
+			FilesystemId:  info.FilesystemId,
+			Provider:      string(providerType),
+			Size:          size,
+		}
+		if volumeTag, err := filesystem.Volume(); err == nil {
+			result.VolumeTag = volumeTag.String()
+		} else if err != state.ErrNoBackingVolume {
+			return params.FilesystemResizeParams{}, err
+		}
+		return result, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.FilesystemResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
 // FilesystemParams returns the parameters for creating the filesystems
 // with the specified tags.
 func (s *StorageProvisionerAPI) FilesystemParams(args params.Entities) (params.FilesystemParamsResults, error) {
@@ -954,6 +1137,107 @@ func (s *StorageProvisionerAPI) SetVolumeInfo(args params.Volumes) (params.Error
 	return results, nil
 }
 
+// SetVolumeSnapshotInfo records the details of newly created volume
+// snapshots, or the reason they could not be created.
+func (s *StorageProvisionerAPI) SetVolumeSnapshotInfo(args params.VolumeSnapshots) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.VolumeSnapshots)),
+	}
+	one := func(arg params.VolumeSnapshot) error {
+		snapshot, err := s.st.VolumeSnapshot(arg.Id)
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if !canAccess(snapshot.Volume()) {
+			return common.ErrPerm
+		}
+		if arg.Error != "" {
+			return s.st.SetVolumeSnapshotError(arg.Id, arg.Error)
+		}
+		return s.st.SetVolumeSnapshotInfo(arg.Id, state.VolumeSnapshotInfo{
+			SnapshotId: arg.SnapshotId,
+			Size:       arg.Size,
+		})
+	}
+	for i, arg := range args.VolumeSnapshots {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetVolumeResizeResults records the outcome of resizing volumes. Volumes
+// that were resized have their new size recorded; those that could not
+// be resized have their status set to record the failure.
+func (s *StorageProvisionerAPI) SetVolumeResizeResults(args params.VolumeResizes) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.VolumeResizes)),
+	}
+	one := func(arg params.VolumeResize) error {
+		tag, err := names.ParseVolumeTag(arg.VolumeTag)
+		if err != nil || !canAccess(tag) {
+			return common.ErrPerm
+		}
+		if _, err := s.st.Volume(tag); errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if arg.Error != "" {
+			return s.st.FailVolumeResize(tag, arg.Error)
+		}
+		return s.st.SetVolumeResized(tag, arg.Size)
+	}
+	for i, arg := range args.VolumeResizes {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetFilesystemResizeResults records the outcome of resizing filesystems.
+// Filesystems that were resized have their new size recorded; those that
+// could not be resized have their status set to record the failure.
+func (s *StorageProvisionerAPI) SetFilesystemResizeResults(args params.FilesystemResizes) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.FilesystemResizes)),
+	}
+	one := func(arg params.FilesystemResize) error {
+		tag, err := names.ParseFilesystemTag(arg.FilesystemTag)
+		if err != nil || !canAccess(tag) {
+			return common.ErrPerm
+		}
+		if _, err := s.st.Filesystem(tag); errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if arg.Error != "" {
+			return s.st.FailFilesystemResize(tag, arg.Error)
+		}
+		return s.st.SetFilesystemResized(tag, arg.Size)
+	}
+	for i, arg := range args.FilesystemResizes {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
 // SetFilesystemInfo records the details of newly provisioned filesystems.
 func (s *StorageProvisionerAPI) SetFilesystemInfo(args params.Filesystems) (params.ErrorResults, error) {
 	canAccessFilesystem, err := s.getStorageEntityAuthFunc()
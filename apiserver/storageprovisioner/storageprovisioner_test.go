@@ -417,6 +417,107 @@ func (s *provisionerSuite) TestVolumeParamsEmptyArgs(c *gc.C) {
 	c.Assert(results.Results, gc.HasLen, 0)
 }
 
+func (s *provisionerSuite) setupVolumeSnapshots(c *gc.C) {
+	s.setupVolumes(c)
+	_, err := s.State.CreateVolumeSnapshot(names.NewVolumeTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.CreateVolumeSnapshot(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *provisionerSuite) TestVolumeSnapshotParams(c *gc.C) {
+	s.setupVolumeSnapshots(c)
+	results, err := s.api.VolumeSnapshotParams(params.VolumeSnapshotIds{
+		Ids: []string{"0/0", "1", "42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeSnapshotParamsResults{
+		Results: []params.VolumeSnapshotParamsResult{
+			{Result: params.VolumeSnapshotParams{
+				Id:        "0/0",
+				VolumeTag: "volume-0-0",
+				VolumeId:  "abc",
+				Provider:  "machinescoped",
+				Tags: map[string]string{
+					tags.JujuEnv: testing.EnvironmentTag.Id(),
+				},
+			}},
+			{Result: params.VolumeSnapshotParams{
+				Id:        "1",
+				VolumeTag: "volume-2",
+				VolumeId:  "def",
+				Provider:  "environscoped",
+				Tags: map[string]string{
+					tags.JujuEnv: testing.EnvironmentTag.Id(),
+				},
+			}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	s.setupVolumeSnapshots(c)
+	results, err := s.api.SetVolumeSnapshotInfo(params.VolumeSnapshots{
+		VolumeSnapshots: []params.VolumeSnapshot{
+			{Id: "0/0", SnapshotId: "snap-0", Size: 1024},
+			{Id: "1", Error: "out of cheese"},
+			{Id: "42", SnapshotId: "snap-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+
+	snapshot, err := s.State.VolumeSnapshot("0/0")
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := snapshot.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, state.VolumeSnapshotInfo{SnapshotId: "snap-0", Size: 1024})
+	snapshot, err = s.State.VolumeSnapshot("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Error(), gc.Equals, "out of cheese")
+
+	// Neither snapshot is pending any longer.
+	paramsResults, err := s.api.VolumeSnapshotParams(params.VolumeSnapshotIds{
+		Ids: []string{"0/0", "1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paramsResults.Results, gc.HasLen, 2)
+	for _, result := range paramsResults.Results {
+		c.Assert(result.Error, jc.Satisfies, params.IsCodeAlreadyExists)
+	}
+}
+
+func (s *provisionerSuite) TestVolumeParamsFromSnapshot(c *gc.C) {
+	s.setupVolumeSnapshots(c)
+	err := s.State.SetVolumeSnapshotInfo("1", state.VolumeSnapshotInfo{SnapshotId: "snap-1", Size: 4096})
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.factory.MakeMachine(c, &factory.MachineParams{
+		Volumes: []state.MachineVolumeParams{{
+			Volume: state.VolumeParams{Pool: "environscoped", Size: 4096, Snapshot: "1"},
+		}},
+	})
+	attachments, err := s.State.MachineVolumeAttachments(machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	volumeTag := attachments[0].Volume()
+
+	results, err := s.api.VolumeParams(params.Entities{
+		Entities: []params.Entity{{volumeTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Size, gc.Equals, uint64(4096))
+	c.Assert(results.Results[0].Result.SnapshotId, gc.Equals, "snap-1")
+}
+
 func (s *provisionerSuite) TestFilesystemParams(c *gc.C) {
 	s.setupFilesystems(c)
 	results, err := s.api.FilesystemParams(params.Entities{
@@ -717,6 +818,179 @@ func (s *provisionerSuite) TestWatchVolumes(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *provisionerSuite) TestWatchVolumeSnapshots(c *gc.C) {
+	s.setupVolumeSnapshots(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.State.EnvironTag().String()},
+		{"machine-42"}},
+	}
+	result, err := s.api.WatchVolumeSnapshots(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{"0/0"}},
+			{StringsWatcherId: "2", Changes: []string{"1"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	defer statetesting.AssertStop(c, s.resources.Get("1"))
+	defer statetesting.AssertStop(c, s.resources.Get("2"))
+}
+
+func (s *provisionerSuite) TestWatchVolumeResizes(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("0/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ResizeVolume(names.NewVolumeTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.State.EnvironTag().String()},
+		{"machine-42"}},
+	}
+	result, err := s.api.WatchVolumeResizes(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{"0/0"}},
+			{StringsWatcherId: "2", Changes: []string{"2"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	defer statetesting.AssertStop(c, s.resources.Get("1"))
+	defer statetesting.AssertStop(c, s.resources.Get("2"))
+}
+
+func (s *provisionerSuite) TestVolumeResizeParams(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("0/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.VolumeResizeParams(params.Entities{
+		Entities: []params.Entity{{"volume-0-0"}, {"volume-2"}, {"volume-42"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeResizeParamsResults{
+		Results: []params.VolumeResizeParamsResult{
+			{Result: params.VolumeResizeParams{
+				VolumeTag: "volume-0-0",
+				VolumeId:  "abc",
+				Provider:  "machinescoped",
+				Size:      2048,
+			}},
+			{Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `pending resize of volume "2" not found`,
+			}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestSetVolumeResizeResults(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("0/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ResizeVolume(names.NewVolumeTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SetVolumeResizeResults(params.VolumeResizes{
+		VolumeResizes: []params.VolumeResize{
+			{VolumeTag: "volume-0-0", Size: 2048},
+			{VolumeTag: "volume-2", Error: "out of cheese"},
+			{VolumeTag: "volume-42", Size: 1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+
+	volume, err := s.State.Volume(names.NewVolumeTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := volume.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(2048))
+
+	status, err := s.State.VolumeStatus(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusError)
+	c.Assert(status.Message, gc.Equals, "resizing volume: out of cheese")
+
+	// Neither resize is pending any longer.
+	paramsResults, err := s.api.VolumeResizeParams(params.Entities{
+		Entities: []params.Entity{{"volume-0-0"}, {"volume-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paramsResults.Results, gc.HasLen, 2)
+	for _, result := range paramsResults.Results {
+		c.Assert(result.Error, jc.Satisfies, params.IsCodeNotFound)
+	}
+}
+
+func (s *provisionerSuite) TestFilesystemResizeParams(c *gc.C) {
+	s.setupFilesystems(c)
+	err := s.State.ResizeFilesystem(names.NewFilesystemTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.FilesystemResizeParams(params.Entities{
+		Entities: []params.Entity{{"filesystem-0-0"}, {"filesystem-2"}, {"filesystem-42"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.FilesystemResizeParamsResults{
+		Results: []params.FilesystemResizeParamsResult{
+			{Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `pending resize of filesystem "0/0" not found`,
+			}},
+			{Result: params.FilesystemResizeParams{
+				FilesystemTag: "filesystem-2",
+				FilesystemId:  "def",
+				Provider:      "environscoped",
+				Size:          8192,
+			}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestSetFilesystemResizeResults(c *gc.C) {
+	s.setupFilesystems(c)
+	err := s.State.ResizeFilesystem(names.NewFilesystemTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SetFilesystemResizeResults(params.FilesystemResizes{
+		FilesystemResizes: []params.FilesystemResize{
+			{FilesystemTag: "filesystem-2", Size: 8192},
+			{FilesystemTag: "filesystem-42", Size: 1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+
+	filesystem, err := s.State.Filesystem(names.NewFilesystemTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := filesystem.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(8192))
+}
+
 func (s *provisionerSuite) TestWatchVolumeAttachments(c *gc.C) {
 	s.setupVolumes(c)
 	s.factory.MakeMachine(c, nil)
@@ -483,6 +483,17 @@ func (sb *StubBacking) AddSubnet(subnetInfo common.BackingSubnetInfo) (common.Ba
 	return fs, nil
 }
 
+func (sb *StubBacking) ImportSubnets(infos []common.BackingSubnetInfo) error {
+	sb.MethodCall(sb, "ImportSubnets", infos)
+	if err := sb.NextErr(); err != nil {
+		return err
+	}
+	for _, info := range infos {
+		sb.Subnets = append(sb.Subnets, &FakeSubnet{info: info})
+	}
+	return nil
+}
+
 func (sb *StubBacking) AddSpace(name string, subnets []string, public bool) error {
 	sb.MethodCall(sb, "AddSpace", name, subnets, public)
 	if err := sb.NextErr(); err != nil {
@@ -407,6 +407,8 @@ func (u *uniterBaseAPI) HasSubordinates(args params.Entities) (params.BoolResult
 }
 
 // CharmURL returns the charm URL for all given units or services.
+// The charm URL of a service is the one the calling unit should be
+// running.
 func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults, error) {
 	result := params.StringBoolResults{
 		Results: make([]params.StringBoolResult, len(args.Entities)),
@@ -427,10 +429,18 @@ func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults
 			var unitOrService state.Entity
 			unitOrService, err = u.st.FindEntity(tag)
 			if err == nil {
-				charmURLer := unitOrService.(interface {
-					CharmURL() (*charm.URL, bool)
-				})
-				curl, ok := charmURLer.CharmURL()
+				var curl *charm.URL
+				var ok bool
+				if service, isService := unitOrService.(*state.Service); isService {
+					// Services in a rolling upgrade may hold
+					// the calling unit at the previous charm.
+					curl, ok = service.CharmURLForUnit(u.auth.GetAuthTag().Id())
+				} else {
+					charmURLer := unitOrService.(interface {
+						CharmURL() (*charm.URL, bool)
+					})
+					curl, ok = charmURLer.CharmURL()
+				}
 				if curl != nil {
 					result.Results[i].Result = curl.String()
 					result.Results[i].Ok = ok
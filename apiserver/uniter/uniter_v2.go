@@ -61,6 +61,42 @@ func (u *UniterAPIV2) AddMetricBatches(args params.MetricBatchParams) (params.Er
 	return result, nil
 }
 
+// EndpointAddresses returns, for each given unit and relation endpoint,
+// the address the unit should advertise over that endpoint. This is
+// an address in the space the endpoint is bound to, if any, and the
+// unit's private address otherwise.
+func (u *UniterAPIV2) EndpointAddresses(args params.UnitEndpoints) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.UnitEndpoints)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, arg := range args.UnitEndpoints {
+		tag, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				address, ok := unit.EndpointAddress(arg.Endpoint)
+				if ok {
+					result.Results[i].Result = address
+				} else {
+					err = common.NoAddressSetError(tag, arg.Endpoint+" endpoint")
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // NewUniterAPIV2 creates a new instance of the Uniter API, version 2.
 func NewUniterAPIV2(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV2, error) {
 	baseAPI, err := NewUniterAPIV1(st, resources, authorizer)
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
@@ -92,6 +93,62 @@ func (s *uniterV2Suite) TestStorageAttachments(c *gc.C) {
 
 // TestSetStatus tests backwards compatibility for
 // set status has been properly implemented.
+func (s *uniterV2Suite) TestCharmURLRollingUpgrade(c *gc.C) {
+	newCharm := factory.NewFactory(s.State).MakeCharm(c, &factory.CharmParams{
+		Name: "wordpress",
+		URL:  "cs:quantal/wordpress-4",
+	})
+	err := s.wordpress.SetCharmRolling(newCharm, false, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: "service-wordpress"}}}
+	result, err := s.uniter.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: s.wpCharm.String()}},
+	})
+
+	// Once released, the unit is given the new charm.
+	err = s.wordpress.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: newCharm.String()}},
+	})
+}
+
+func (s *uniterV2Suite) TestEndpointAddresses(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine0.SetProviderAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopeCloudLocal),
+		network.NewScopedAddress("10.0.0.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.UnitEndpoints{UnitEndpoints: []params.UnitEndpoint{
+		{Unit: "unit-mysql-0", Endpoint: "server"},
+		{Unit: "unit-wordpress-0", Endpoint: "db"},
+		{Unit: "unit-wordpress-0", Endpoint: "url"},
+		{Unit: "unit-foo-42", Endpoint: "db"},
+	}}
+	result, err := s.uniter.EndpointAddresses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: "10.0.0.4"},
+			{Result: "1.2.3.4"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterV2Suite) TestSetStatus(c *gc.C) {
 	s.testSetStatus(c, s.uniter)
 }
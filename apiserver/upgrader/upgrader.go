@@ -103,7 +103,7 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.st.WatchForEnvironConfigChanges()
+			watch := u.st.WatchAgentVersion()
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -161,6 +161,12 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	}
 	// Is the desired version greater than the current API server version?
 	isNewerVersion := agentVersion.Compare(version.Current.Number) > 0
+	stagedUpgrade, err := u.st.StagedUpgrade()
+	if errors.IsNotFound(err) {
+		stagedUpgrade = nil
+	} else if err != nil {
+		return params.VersionResults{}, common.ServerError(err)
+	}
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -179,7 +185,14 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
+			//
+			// Machines held back by a staged upgrade are told to
+			// remain at the version being upgraded from.
+			if stagedUpgrade != nil && stagedUpgrade.IsHeld(tag.Id()) {
+				previous := stagedUpgrade.PreviousVersion()
+				logger.Debugf("desired version is %s, but agent is held at %s by a staged upgrade", agentVersion, previous)
+				results[i].Version = &previous
+			} else if !isNewerVersion || u.entityIsManager(tag) {
 				results[i].Version = &agentVersion
 			} else {
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, version.Current.Number)
@@ -326,3 +326,65 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, version.Current.Number)
 }
+
+func (s *upgraderSuite) desiredVersion(c *gc.C, m *state.Machine) version.Number {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: m.Tag()}
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: m.Tag().String()}}}
+	results, err := upgraderAPI.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	return *results.Results[0].Version
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldByStagedUpgrade(c *gc.C) {
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, m := range []*state.Machine{s.apiMachine, s.rawMachine, other} {
+		err := m.SetAgentVersion(version.Current)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	previous := version.Current.Number
+	newer := previous
+	newer.Patch++
+	err = s.State.StartStagedUpgrade(newer, []string{s.rawMachine.Id()}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	// Pretend the state server has already upgraded.
+	s.PatchValue(&version.Current.Number, newer)
+
+	c.Check(s.desiredVersion(c, s.apiMachine), gc.Equals, newer)
+	c.Check(s.desiredVersion(c, s.rawMachine), gc.Equals, newer)
+	c.Check(s.desiredVersion(c, other), gc.Equals, previous)
+}
+
+func (s *upgraderSuite) TestWatchAPIVersionStagedUpgrade(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.upgrader.WatchAPIVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	w := s.resources.Get(results.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	for _, m := range []*state.Machine{s.apiMachine, s.rawMachine} {
+		err := m.SetAgentVersion(version.Current)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	newer := version.Current.Number
+	newer.Patch++
+	err = s.State.StartStagedUpgrade(newer, nil, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Progress of the staged upgrade alone is also noticed.
+	err = s.State.AbortStagedUpgrade("stop")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
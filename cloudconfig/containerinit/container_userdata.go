@@ -125,6 +125,16 @@ func newCloudInitConfigWithNetworks(series string, networkConfig *container.Netw
 	return cloudConfig, nil
 }
 
+// CloudInitUserData returns the serialized cloud-init user-data for a
+// container, for container types that take it directly rather than
+// from a file.
+func CloudInitUserData(
+	instanceConfig *instancecfg.InstanceConfig,
+	networkConfig *container.NetworkConfig,
+) ([]byte, error) {
+	return cloudInitUserData(instanceConfig, networkConfig)
+}
+
 func cloudInitUserData(
 	instanceConfig *instancecfg.InstanceConfig,
 	networkConfig *container.NetworkConfig,
@@ -162,6 +162,11 @@ type InstanceConfig struct {
 	// servers will be preferred over IPv4 ones.
 	PreferIPv6 bool
 
+	// IPv6Only mirrors the value of ipv6-only environment setting and
+	// when set only IPv6 addresses (and hostnames) are used for
+	// connecting to the API/state servers.
+	IPv6Only bool
+
 	// The type of Simple Stream to download and deploy on this instance.
 	ImageStream string
 
@@ -230,6 +235,7 @@ func (cfg *InstanceConfig) AgentConfig(
 		CACert:            cfg.MongoInfo.CACert,
 		Values:            cfg.AgentEnvironment,
 		PreferIPv6:        cfg.PreferIPv6,
+		IPv6Only:          cfg.IPv6Only,
 		Environment:       cfg.APIInfo.EnvironTag,
 	}
 	if !cfg.Bootstrap {
@@ -513,6 +519,7 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 	); err != nil {
 		return errors.Trace(err)
 	}
+	icfg.IPv6Only = cfg.IPv6Only()
 
 	if isStateInstanceConfig(icfg) {
 		// Add NUMACTL preference. Needed to work for both bootstrap and high availability
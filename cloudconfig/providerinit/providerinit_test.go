@@ -102,6 +102,24 @@ func (s *CloudInitSuite) TestFinishInstanceConfig(c *gc.C) {
 	c.Assert(icfg, jc.DeepEquals, expectedMcfg)
 }
 
+func (s *CloudInitSuite) TestFinishInstanceConfigIPv6Only(c *gc.C) {
+	userTag := names.NewLocalUserTag("not-touched")
+	cfg, err := config.New(config.NoDefaults, dummySampleConfig().Merge(testing.Attrs{
+		"authorized-keys": "we-are-the-keys",
+		"prefer-ipv6":     false,
+		"ipv6-only":       true,
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	icfg := &instancecfg.InstanceConfig{
+		MongoInfo: &mongo.MongoInfo{Tag: userTag},
+		APIInfo:   &api.Info{Tag: userTag},
+	}
+	err = instancecfg.FinishInstanceConfig(icfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(icfg.IPv6Only, jc.IsTrue)
+	c.Assert(icfg.PreferIPv6, jc.IsTrue)
+}
+
 func (s *CloudInitSuite) TestFinishInstanceConfigNonDefault(c *gc.C) {
 	userTag := names.NewLocalUserTag("not-touched")
 	attrs := dummySampleConfig().Merge(testing.Attrs{
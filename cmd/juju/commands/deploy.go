@@ -42,6 +42,17 @@ type DeployCommand struct {
 	// Storage is a map of storage constraints, keyed on the storage name
 	// defined in charm storage metadata.
 	Storage map[string]storage.Constraints
+
+	// BindToSpaces holds the unparsed value of the --bind flag.
+	BindToSpaces string
+
+	// Bindings maps charm relation endpoint names to the names of the
+	// spaces they should be bound to.
+	Bindings map[string]string
+
+	// ConfigProfile names the environment config profile whose settings
+	// the service should use in place of the charm defaults.
+	ConfigProfile string
 }
 
 const deployDoc = `
@@ -99,7 +110,26 @@ Examples:
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
 
+   juju deploy mysql --bind "db=internal monitors=admin"
+   (deploy mysql with its db endpoint bound to the internal space and its
+   monitors endpoint bound to the admin space)
+
+   juju deploy mysql --config-profile production
+   (deploy mysql using the settings of the production config profile in
+   place of the charm's defaults)
+
+Relation endpoints defined by the charm can be bound to spaces with --bind,
+which takes a space-separated list of <endpoint>=<space> pairs. Addresses
+advertised to related units over a bound endpoint (for example the
+private-address relation setting, and the output of network-get) will be
+taken from the bound space.
+
+Settings from the config profile named with --config-profile apply to the
+charm's options that are not set explicitly with --config; see
+"juju help config-profile".
+
 See Also:
+   juju help config-profile
    juju help constraints
    juju help set-constraints
    juju help get-constraints
@@ -124,6 +154,8 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "deprecated and ignored: use space constraints instead.")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.StringVar(&c.BindToSpaces, "bind", "", "bind charm endpoints to spaces, e.g. \"db=internal website=public\"")
+	f.StringVar(&c.ConfigProfile, "config-profile", "", "environment config profile providing the service's default settings")
 }
 
 func (c *DeployCommand) Init(args []string) error {
@@ -144,6 +176,11 @@ func (c *DeployCommand) Init(args []string) error {
 	default:
 		return cmd.CheckEmpty(args[2:])
 	}
+	bindings, err := parseBindings(c.BindToSpaces)
+	if err != nil {
+		return errors.Annotate(err, "invalid --bind value")
+	}
+	c.Bindings = bindings
 	return c.UnitCommandBase.Init(args)
 }
 
@@ -219,9 +256,10 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	// If storage or placement is specified, we attempt to use a new API on the service facade.
-	if len(c.Storage) > 0 || len(c.Placement) > 0 {
-		notSupported := errors.New("cannot deploy charms with storage or placement: not supported by the API server")
+	// If storage, placement, endpoint bindings or a config profile are
+	// specified, we attempt to use a new API on the service facade.
+	if len(c.Storage) > 0 || len(c.Placement) > 0 || len(c.Bindings) > 0 || c.ConfigProfile != "" {
+		notSupported := errors.New("cannot deploy charms with storage, placement, endpoint bindings or config profiles: not supported by the API server")
 		serviceClient, err := c.newServiceAPIClient()
 		if err != nil {
 			return notSupported
@@ -243,6 +281,8 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			c.Placement,
 			[]string{},
 			c.Storage,
+			c.Bindings,
+			c.ConfigProfile,
 		)
 		if params.IsCodeNotImplemented(err) {
 			return notSupported
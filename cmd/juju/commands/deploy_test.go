@@ -85,6 +85,15 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db"},
+		err:  `invalid --bind value: expected <endpoint>=<space>, got "db"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=-bad-"},
+		err:  `invalid --bind value: invalid space name "-bad-"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=a db=b"},
+		err:  `invalid --bind value: endpoint "db" bound more than once`,
 	},
 }
 
@@ -239,6 +248,49 @@ func (s *DeploySuite) TestStorage(c *gc.C) {
 	})
 }
 
+func (s *DeploySuite) TestEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err = runDeploy(c, "local:wordpress", "--bind", "db=internal")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/wordpress-3")
+	service, _ := s.AssertService(c, "wordpress", curl, 1, 0)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"db": "internal"})
+}
+
+func (s *DeploySuite) TestEndpointBindingsUnknownEndpoint(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err = runDeploy(c, "local:wordpress", "--bind", "website=internal")
+	c.Assert(err, gc.ErrorMatches, `.*endpoint "website" not defined by charm "wordpress"`)
+}
+
+func (s *DeploySuite) TestConfigProfile(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{"blog-title": "Production"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err = runDeploy(c, "local:wordpress", "--config-profile", "production")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/wordpress-3")
+	service, _ := s.AssertService(c, "wordpress", curl, 1, 0)
+	profile, err := service.ConfigProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, gc.Equals, "production")
+}
+
+func (s *DeploySuite) TestConfigProfileNotFound(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err := runDeploy(c, "local:wordpress", "--config-profile", "production")
+	c.Assert(err, gc.ErrorMatches, `config profile "production" not found`)
+}
+
 // TODO(wallyworld) - add another test that deploy with placement fails for older environments
 // (need deploy client to be refactored to use API stub)
 func (s *DeploySuite) TestPlacement(c *gc.C) {
@@ -42,6 +42,24 @@ type EnsureAvailabilityCommand struct {
 	Placement []string
 	// PlacementSpec holds the unparsed placement directives argument (--to).
 	PlacementSpec string
+	// PreferredZone, if specified, names the availability zone whose
+	// state servers are preferred when mongo elects a primary.
+	PreferredZone string
+	// PreferredPrimary, if specified, is the id of the state server
+	// machine that should be primary whenever it is healthy.
+	PreferredPrimary string
+	// Hidden holds the ids of machines to become hidden, non-voting
+	// state servers, for use by backups.
+	Hidden []string
+	// HiddenSpec holds the unparsed hidden machines argument (--hidden).
+	HiddenSpec string
+	// ResetPreferences, if true, clears the preferred zone, preferred
+	// primary and hidden state servers recorded previously.
+	ResetPreferences bool
+	// StrictZones, if true, causes the command to fail rather than
+	// warn if the state servers cannot all be placed in distinct
+	// availability zones.
+	StrictZones bool
 }
 
 const ensureAvailabilityDoc = `
@@ -49,7 +67,11 @@ To ensure availability of deployed services, the Juju infrastructure
 must itself be highly available.  Ensure-availability must be called
 to ensure that the specified number of state servers are made available.
 
-An odd number of state servers is required.
+An odd number of state servers is required. Where the provider supports
+availability zones, new state servers are spread across them, with a
+warning if some state servers will share a zone; with --strict-zones,
+the command fails instead and no changes are made. Placing state
+servers explicitly with --to disables this.
 
 Examples:
  juju ensure-availability
@@ -68,6 +90,22 @@ Examples:
      Ensure that 7 state servers are available, with machines server1 and
      server2 used first, and if necessary, newly created state server
      machines having the default series, and at least 8GB RAM.
+ juju ensure-availability --prefer-zone us-east-1a --primary 2
+     Ensure that the system is still in highly available mode,
+     preferring state servers in the us-east-1a availability zone
+     when electing a primary, and machine 2 above all others.
+ juju ensure-availability -n 5 --strict-zones
+     Ensure that 5 state servers are available, failing if they
+     cannot all be in distinct availability zones.
+ juju ensure-availability --hidden 4
+     Ensure that the system is still in highly available mode, and
+     make machine 4 a hidden, non-voting state server which holds a
+     copy of the database for backups but never becomes primary.
+ juju ensure-availability --reset-preferences
+     Ensure that the system is still in highly available mode, and
+     forget any preferred zone or primary, giving all state servers
+     equal priority again; hidden state servers become ordinary
+     non-voting state servers.
 `
 
 // formatSimple marshals value to a yaml-formatted []byte, unless value is nil.
@@ -107,6 +145,10 @@ func formatSimple(value interface{}) ([]byte, error) {
 			"converting machines: %s\n",
 			ensureAvailabilityResult.Converted,
 		},
+		{
+			"hiding machines: %s\n",
+			ensureAvailabilityResult.Hidden,
+		},
 	} {
 		if len(machineList.list) == 0 {
 			continue
@@ -133,6 +175,11 @@ func (c *EnsureAvailabilityCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Series, "series", "", "the charm series")
 	f.StringVar(&c.PlacementSpec, "to", "", "the machine(s) to become state servers, bypasses constraints")
 	f.Var(constraints.ConstraintsValue{&c.Constraints}, "constraints", "additional machine constraints")
+	f.StringVar(&c.PreferredZone, "prefer-zone", "", "the availability zone whose state servers are preferred as primary")
+	f.StringVar(&c.PreferredPrimary, "primary", "", "the state server machine preferred as primary")
+	f.StringVar(&c.HiddenSpec, "hidden", "", "the machine(s) to become hidden, non-voting state servers")
+	f.BoolVar(&c.ResetPreferences, "reset-preferences", false, "clear the preferred zone, primary and hidden state servers")
+	f.BoolVar(&c.StrictZones, "strict-zones", false, "fail if the state servers cannot all be in distinct availability zones")
 	c.out.AddFlags(f, "simple", map[string]cmd.Formatter{
 		"yaml":   cmd.FormatYaml,
 		"json":   cmd.FormatJson,
@@ -164,6 +211,32 @@ func (c *EnsureAvailabilityCommand) Init(args []string) error {
 			c.Placement[i] = spec
 		}
 	}
+	if c.PreferredPrimary != "" && !names.IsValidMachine(c.PreferredPrimary) {
+		return fmt.Errorf("invalid machine id %q", c.PreferredPrimary)
+	}
+	if c.HiddenSpec != "" {
+		for _, id := range strings.Split(c.HiddenSpec, ",") {
+			id = strings.TrimSpace(id)
+			if !names.IsValidMachine(id) {
+				return fmt.Errorf("invalid machine id %q", id)
+			}
+			if names.IsContainerMachine(id) {
+				return errors.New("ensure-availability cannot hide containers")
+			}
+			for _, p := range c.Placement {
+				if p == instance.MachineScope+":"+id {
+					return fmt.Errorf("machine %s cannot be both a voting and a hidden state server", id)
+				}
+			}
+			if id == c.PreferredPrimary {
+				return fmt.Errorf("machine %s cannot be both the primary and a hidden state server", id)
+			}
+			c.Hidden = append(c.Hidden, id)
+		}
+	}
+	if c.ResetPreferences && (c.PreferredZone != "" || c.PreferredPrimary != "" || len(c.Hidden) > 0) {
+		return errors.New("--reset-preferences cannot be combined with --prefer-zone, --primary or --hidden")
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -174,6 +247,7 @@ type availabilityInfo struct {
 	Promoted   []string `json:"promoted,omitempty" yaml:"promoted,flow,omitempty"`
 	Demoted    []string `json:"demoted,omitempty" yaml:"demoted,flow,omitempty"`
 	Converted  []string `json:"converted,omitempty" yaml:"converted,flow,omitempty"`
+	Hidden     []string `json:"hidden,omitempty" yaml:"hidden,flow,omitempty"`
 }
 
 // EnsureAvailabilityClient defines the methods
@@ -183,7 +257,8 @@ type EnsureAvailabilityClient interface {
 	Close() error
 	EnsureAvailability(
 		numStateServers int, cons constraints.Value, series string,
-		placement []string) (params.StateServersChanges, error)
+		placement []string, prefs params.ReplicaSetPreferences,
+		strictZones bool) (params.StateServersChanges, error)
 }
 
 func (c *EnsureAvailabilityCommand) getHAClient() (EnsureAvailabilityClient, error) {
@@ -214,10 +289,20 @@ func (c *EnsureAvailabilityCommand) Run(ctx *cmd.Context) error {
 		c.Constraints,
 		c.Series,
 		c.Placement,
+		params.ReplicaSetPreferences{
+			PreferredZone:    c.PreferredZone,
+			PreferredPrimary: c.PreferredPrimary,
+			Hidden:           c.Hidden,
+			Reset:            c.ResetPreferences,
+		},
+		c.StrictZones,
 	)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
+	for _, warning := range ensureAvailabilityResult.Warnings {
+		ctx.Infof("WARNING: %s", warning)
+	}
 
 	result := availabilityInfo{
 		Added:      machineTagsToIds(ensureAvailabilityResult.Added...),
@@ -226,6 +311,7 @@ func (c *EnsureAvailabilityCommand) Run(ctx *cmd.Context) error {
 		Promoted:   machineTagsToIds(ensureAvailabilityResult.Promoted...),
 		Demoted:    machineTagsToIds(ensureAvailabilityResult.Demoted...),
 		Converted:  machineTagsToIds(ensureAvailabilityResult.Converted...),
+		Hidden:     machineTagsToIds(ensureAvailabilityResult.Hidden...),
 	}
 	return c.out.Write(ctx, result)
 }
@@ -52,6 +52,8 @@ type fakeHAClient struct {
 	err             error
 	series          string
 	placement       []string
+	prefs           params.ReplicaSetPreferences
+	strictZones     bool
 	result          params.StateServersChanges
 }
 
@@ -60,12 +62,15 @@ func (f *fakeHAClient) Close() error {
 }
 
 func (f *fakeHAClient) EnsureAvailability(numStateServers int, cons constraints.Value,
-	series string, placement []string) (params.StateServersChanges, error) {
+	series string, placement []string, prefs params.ReplicaSetPreferences,
+	strictZones bool) (params.StateServersChanges, error) {
 
 	f.numStateServers = numStateServers
 	f.cons = cons
 	f.series = series
 	f.placement = placement
+	f.prefs = prefs
+	f.strictZones = strictZones
 
 	if f.err != nil {
 		return f.result, f.err
@@ -95,6 +100,10 @@ func (f *fakeHAClient) EnsureAvailability(numStateServers int, cons constraints.
 		f.result.Added = append(f.result.Added, fmt.Sprintf("machine-%d", i))
 	}
 
+	for _, id := range prefs.Hidden {
+		f.result.Hidden = append(f.result.Hidden, "machine-"+id)
+	}
+
 	return f.result, nil
 }
 
@@ -275,3 +284,80 @@ converting machines: 1, 2
 	c.Check(s.fake.series, gc.Equals, "")
 	c.Check(len(s.fake.placement), gc.Equals, 2)
 }
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityPreferences(c *gc.C) {
+	ctx, err := s.runEnsureAvailability(c, "--prefer-zone", "zone1", "--primary", "0", "--hidden", "3, 4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(coretesting.Stdout(ctx), gc.Equals, `
+maintaining machines: 0
+adding machines: 1, 2
+hiding machines: 3, 4
+
+`[1:])
+
+	c.Check(s.fake.numStateServers, gc.Equals, 0)
+	c.Check(s.fake.prefs, jc.DeepEquals, params.ReplicaSetPreferences{
+		PreferredZone:    "zone1",
+		PreferredPrimary: "0",
+		Hidden:           []string{"3", "4"},
+	})
+}
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityResetPreferences(c *gc.C) {
+	_, err := s.runEnsureAvailability(c, "--reset-preferences")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.fake.prefs, jc.DeepEquals, params.ReplicaSetPreferences{Reset: true})
+}
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityStrictZones(c *gc.C) {
+	_, err := s.runEnsureAvailability(c, "-n", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.fake.strictZones, jc.IsFalse)
+
+	_, err = s.runEnsureAvailability(c, "-n", "3", "--strict-zones")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.fake.strictZones, jc.IsTrue)
+}
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityPreferencesErrors(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		expectErr string
+	}{{
+		args:      []string{"--primary", "foo"},
+		expectErr: `invalid machine id "foo"`,
+	}, {
+		args:      []string{"--hidden", "1,bar"},
+		expectErr: `invalid machine id "bar"`,
+	}, {
+		args:      []string{"--hidden", "1/lxc/0"},
+		expectErr: "ensure-availability cannot hide containers",
+	}, {
+		args:      []string{"--to", "1,2", "--hidden", "2"},
+		expectErr: "machine 2 cannot be both a voting and a hidden state server",
+	}, {
+		args:      []string{"--primary", "1", "--hidden", "1"},
+		expectErr: "machine 1 cannot be both the primary and a hidden state server",
+	}, {
+		args:      []string{"--reset-preferences", "--primary", "1"},
+		expectErr: "--reset-preferences cannot be combined with --prefer-zone, --primary or --hidden",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.runEnsureAvailability(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.expectErr)
+	}
+
+	// Verify that ensure-availability didn't call into the API
+	c.Assert(s.fake.numStateServers, gc.Equals, invalidNumServers)
+}
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityWarnings(c *gc.C) {
+	s.fake.result.Warnings = []string{"cannot spread 3 state servers across 2 availability zones"}
+	ctx, err := s.runEnsureAvailability(c, "-n", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(coretesting.Stdout(ctx), gc.Equals,
+		"maintaining machines: 0\n"+
+			"adding machines: 1, 2\n\n")
+	c.Check(coretesting.Stderr(ctx), gc.Equals,
+		"WARNING: cannot spread 3 state servers across 2 availability zones\n")
+}
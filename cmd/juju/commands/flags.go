@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/storage"
 )
@@ -33,6 +34,31 @@ func (f storageFlag) Set(s string) error {
 	return nil
 }
 
+// parseBindings parses a space-separated list of <endpoint>=<space>
+// pairs, as supplied to the --bind flag, into a map of endpoint name
+// to space name.
+func parseBindings(s string) (map[string]string, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	bindings := make(map[string]string)
+	for _, field := range fields {
+		pair := strings.SplitN(field, "=", 2)
+		if len(pair) < 2 || pair[0] == "" || pair[1] == "" {
+			return nil, errors.Errorf("expected <endpoint>=<space>, got %q", field)
+		}
+		if !names.IsValidSpace(pair[1]) {
+			return nil, errors.Errorf("invalid space name %q", pair[1])
+		}
+		if _, ok := bindings[pair[0]]; ok {
+			return nil, errors.Errorf("endpoint %q bound more than once", pair[0])
+		}
+		bindings[pair[0]] = pair[1]
+	}
+	return bindings, nil
+}
+
 // Set implements gnuflag.Value.String.
 func (f storageFlag) String() string {
 	strs := make([]string, 0, len(*f.stores))
@@ -17,9 +17,12 @@ import (
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/cachedimages"
 	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/juju/configprofile"
 	"github.com/juju/juju/cmd/juju/environment"
 	"github.com/juju/juju/cmd/juju/helptopics"
+	"github.com/juju/juju/cmd/juju/ipaddress"
 	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/cmd/juju/metricsdebug"
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/cmd/juju/space"
 	"github.com/juju/juju/cmd/juju/status"
@@ -144,6 +147,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&ResolvedCommand{}))
 	r.Register(wrapEnvCommand(&DebugLogCommand{}))
 	r.Register(wrapEnvCommand(&DebugHooksCommand{}))
+	r.Register(wrapEnvCommand(&metricsdebug.MetricsCommand{}))
+	r.Register(wrapEnvCommand(&metricsdebug.GetMeterThresholdsCommand{}))
+	r.Register(wrapEnvCommand(&metricsdebug.SetMeterThresholdsCommand{}))
 
 	// Configuration commands.
 	r.Register(&InitCommand{})
@@ -156,6 +162,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&UnexposeCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeJujuCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeCharmCommand{}))
+	r.Register(wrapEnvCommand(&RollBackCharmCommand{}))
 
 	// Charm publishing commands.
 	r.Register(wrapEnvCommand(&PublishCommand{}))
@@ -181,6 +188,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterSuperAlias("remove-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 	r.RegisterSuperAlias("destroy-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 	r.RegisterSuperAlias("terminate-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
+	r.RegisterSuperAlias("migrate-container", "machine", "migrate-container", nil)
 
 	// Mangage environment
 	r.Register(environment.NewSuperCommand())
@@ -205,6 +213,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterSuperAlias("set", "service", "set", twoDotOhDeprecation("service set"))
 	r.RegisterSuperAlias("unset", "service", "unset", twoDotOhDeprecation("service unset"))
 
+	// Manage charm config profiles
+	r.Register(configprofile.NewSuperCommand())
+
 	// Operation protection commands
 	r.Register(block.NewSuperBlockCommand())
 	r.Register(wrapEnvCommand(&block.UnblockCommand{}))
@@ -218,6 +229,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage subnets
 	r.Register(subnet.NewSuperCommand())
 
+	// Manage static IP addresses
+	r.Register(ipaddress.NewSuperCommand())
+
 	// Manage systems
 	if featureflag.Enabled(feature.JES) {
 		r.Register(system.NewSuperCommand())
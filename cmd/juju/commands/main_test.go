@@ -201,6 +201,7 @@ var commandNames = []string{
 	"block",
 	"bootstrap",
 	"cached-images",
+	"config-profile",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
@@ -218,10 +219,14 @@ var commandNames = []string{
 	"get-constraints",
 	"get-env", // alias for get-environment
 	"get-environment",
+	"get-meter-thresholds",
 	"help",
 	"help-tool",
 	"init",
+	"ip-address",
 	"machine",
+	"metrics",
+	"migrate-container",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
@@ -229,6 +234,7 @@ var commandNames = []string{
 	"remove-unit",     // alias for destroy-unit
 	"resolved",
 	"retry-provisioning",
+	"rollback-charm",
 	"run",
 	"scp",
 	"service",
@@ -236,6 +242,7 @@ var commandNames = []string{
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"set-meter-thresholds",
 	"space",
 	"ssh",
 	"stat", // alias for status
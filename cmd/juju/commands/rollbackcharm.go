@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// RollBackCharmCommand returns a service to a charm it used
+// previously.
+type RollBackCharmCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Revision    int // defaults to -1 (most recent)
+	Force       bool
+}
+
+const rollbackCharmDoc = `
+Juju remembers the charms a service has used. When no flags are set, the
+service's charm will be returned to the one it used before its last upgrade.
+An earlier charm can be chosen by its revision with the --to-revision flag.
+
+The rollback is applied in the same way as an upgrade-charm, so the units
+of the service will run their upgrade-charm hooks with the older charm. The
+service's current configuration must be valid for the older charm; settings
+for options the older charm does not have must be unset before rolling back.
+
+Charms rolled back from are forgotten, so rolling back repeatedly steps back
+through the service's history rather than alternating between two charms.
+
+Use of the --force flag is not generally recommended; units rolled back while
+in an error state will not have upgrade-charm hooks executed, and may cause
+unexpected behavior.
+`
+
+func (c *RollBackCharmCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rollback-charm",
+		Args:    "<service>",
+		Purpose: "return a service to a charm it used previously",
+		Doc:     rollbackCharmDoc,
+	}
+}
+
+func (c *RollBackCharmCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.Revision, "to-revision", -1, "revision of the previous charm to return to")
+	f.BoolVar(&c.Force, "force", false, "roll back all units immediately, even if in error state")
+}
+
+func (c *RollBackCharmCommand) Init(args []string) error {
+	switch len(args) {
+	case 1:
+		if !names.IsValidService(args[0]) {
+			return fmt.Errorf("invalid service name %q", args[0])
+		}
+		c.ServiceName = args[0]
+	case 0:
+		return fmt.Errorf("no service specified")
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+	if c.Revision < -1 {
+		return fmt.Errorf("invalid revision %d", c.Revision)
+	}
+	return nil
+}
+
+// Run connects to the specified environment and rolls back the
+// service's charm.
+func (c *RollBackCharmCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client := apiservice.NewClient(root)
+	defer client.Close()
+	curl, err := client.RollBackCharm(c.ServiceName, c.Revision, c.Force)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("rolled back service %q to charm %q", c.ServiceName, curl)
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing"
+)
+
+type RollBackCharmSuite struct {
+	jujutesting.RepoSuite
+	CmdBlockHelper
+	riak *state.Service
+}
+
+var _ = gc.Suite(&RollBackCharmSuite{})
+
+func (s *RollBackCharmSuite) SetUpTest(c *gc.C) {
+	s.RepoSuite.SetUpTest(c)
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "riak")
+	err := runDeploy(c, "local:riak", "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	s.riak, err = s.State.Service("riak")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.CmdBlockHelper = NewCmdBlockHelper(s.APIState)
+	c.Assert(s.CmdBlockHelper, gc.NotNil)
+	s.AddCleanup(func(*gc.C) { s.CmdBlockHelper.Close() })
+}
+
+func runRollBackCharm(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&RollBackCharmCommand{}), args...)
+}
+
+func (s *RollBackCharmSuite) assertCharmURL(c *gc.C, expect string) {
+	err := s.riak.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.riak.CharmURL()
+	c.Assert(curl.String(), gc.Equals, expect)
+}
+
+func (s *RollBackCharmSuite) TestInvalidArgs(c *gc.C) {
+	_, err := runRollBackCharm(c)
+	c.Assert(err, gc.ErrorMatches, "no service specified")
+	_, err = runRollBackCharm(c, "invalid:name")
+	c.Assert(err, gc.ErrorMatches, `invalid service name "invalid:name"`)
+	_, err = runRollBackCharm(c, "foo", "bar")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["bar"\]`)
+	_, err = runRollBackCharm(c, "foo", "--to-revision=-2")
+	c.Assert(err, gc.ErrorMatches, "invalid revision -2")
+}
+
+func (s *RollBackCharmSuite) TestNoHistory(c *gc.C) {
+	_, err := runRollBackCharm(c, "riak")
+	c.Assert(err, gc.ErrorMatches, `previous charm of service "riak" not found`)
+}
+
+func (s *RollBackCharmSuite) TestRollBack(c *gc.C) {
+	for i := 0; i < 2; i++ {
+		err := runUpgradeCharm(c, "riak")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.assertCharmURL(c, "local:trusty/riak-9")
+
+	ctx, err := runRollBackCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, `rolled back service "riak" to charm "local:trusty/riak-8"`+"\n")
+	s.assertCharmURL(c, "local:trusty/riak-8")
+
+	_, err = runRollBackCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCharmURL(c, "local:trusty/riak-7")
+}
+
+func (s *RollBackCharmSuite) TestRollBackToRevision(c *gc.C) {
+	for i := 0; i < 2; i++ {
+		err := runUpgradeCharm(c, "riak")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := runRollBackCharm(c, "riak", "--to-revision=7")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCharmURL(c, "local:trusty/riak-7")
+	c.Assert(s.riak.CharmHistory(), gc.HasLen, 0)
+
+	_, err = runRollBackCharm(c, "riak", "--to-revision=8")
+	c.Assert(err, gc.ErrorMatches, `revision 8 in charm history of service "riak" not found`)
+}
+
+func (s *RollBackCharmSuite) TestBlockRollBack(c *gc.C) {
+	err := runUpgradeCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockAllChanges(c, "TestBlockRollBack")
+	_, err = runRollBackCharm(c, "riak")
+	s.AssertBlocked(c, err, ".*TestBlockRollBack.*")
+}
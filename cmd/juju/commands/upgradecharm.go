@@ -13,6 +13,7 @@ import (
 	"gopkg.in/juju/charm.v5"
 	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/service"
@@ -26,6 +27,10 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	Rolling     bool
+	BatchSize   int
+	Resume      bool
+	RollBack    bool
 }
 
 const upgradeCharmDoc = `
@@ -64,6 +69,26 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+The --rolling flag upgrades the service's units in batches rather than all at
+once. The first batch is upgraded straight away; each following batch is only
+upgraded once every unit in the previous one has run its upgrade hooks and
+reports an active workload status. The number of units in each batch is set
+with --batch-size, and defaults to 1. Units not yet upgraded keep running the
+previous charm.
+
+If any unit in a batch fails a hook, the rolling upgrade is paused, and no
+further units are upgraded until the operator intervenes. It is also paused if
+an upgraded unit has run all its hooks but has not reported an active workload
+status after 30 minutes, as happens with charms that do not set their status.
+Once the problem has been fixed, or the unit checked by hand, --resume
+continues the upgrade with the next batch; alternatively, --rollback abandons
+the upgrade and returns every unit of the service to the charm it was running
+before. The progress of a rolling upgrade is shown by
+juju status.
+
+--resume and --rollback take only the service name, and cannot be combined
+with any other flag.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -80,6 +105,10 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.BoolVar(&c.Rolling, "rolling", false, "upgrade units in batches, waiting for each batch to become active")
+	f.IntVar(&c.BatchSize, "batch-size", 1, "number of units to upgrade at a time in a rolling upgrade")
+	f.BoolVar(&c.Resume, "resume", false, "resume a paused rolling upgrade")
+	f.BoolVar(&c.RollBack, "rollback", false, "abandon a rolling upgrade and return to the previous charm")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -97,12 +126,61 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if c.Resume || c.RollBack {
+		if c.Resume && c.RollBack {
+			return fmt.Errorf("--resume and --rollback are mutually exclusive")
+		}
+		if c.Rolling || c.Force || c.SwitchURL != "" || c.Revision != -1 {
+			return fmt.Errorf("--resume and --rollback cannot be combined with other flags")
+		}
+	}
+	if c.Rolling && c.Force {
+		return fmt.Errorf("--rolling and --force are mutually exclusive")
+	}
+	if c.BatchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1, got %d", c.BatchSize)
+	}
+	return nil
+}
+
+func (c *UpgradeCharmCommand) newServiceAPIClient() (*apiservice.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apiservice.NewClient(root), nil
+}
+
+// updateRollingUpgrade resumes or rolls back the service's rolling
+// upgrade, as requested.
+func (c *UpgradeCharmCommand) updateRollingUpgrade(ctx *cmd.Context) error {
+	serviceClient, err := c.newServiceAPIClient()
+	if err != nil {
+		return err
+	}
+	defer serviceClient.Close()
+	if c.Resume {
+		err = serviceClient.ResumeRollingUpgrade(c.ServiceName)
+	} else {
+		err = serviceClient.RollBackRollingUpgrade(c.ServiceName)
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if c.Resume {
+		ctx.Infof("resumed rolling upgrade of service %q", c.ServiceName)
+	} else {
+		ctx.Infof("rolled back upgrade of service %q", c.ServiceName)
+	}
 	return nil
 }
 
 // Run connects to the specified environment and starts the charm
 // upgrade process.
 func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
+	if c.Resume || c.RollBack {
+		return c.updateRollingUpgrade(ctx)
+	}
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
@@ -158,5 +236,18 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
+	if c.Rolling {
+		serviceClient, err := c.newServiceAPIClient()
+		if err != nil {
+			return err
+		}
+		defer serviceClient.Close()
+		err = serviceClient.SetCharmRolling(c.ServiceName, addedURL.String(), false, c.BatchSize)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		ctx.Infof("started rolling upgrade of service %q to %s, %d unit(s) at a time", c.ServiceName, addedURL, c.BatchSize)
+		return nil
+	}
 	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
 }
@@ -108,6 +108,32 @@ func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid value "blah" for flag --revision: strconv.ParseInt: parsing "blah": invalid syntax`)
 }
 
+func (s *UpgradeCharmErrorsSuite) TestRollingFlagsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"riak", "--resume", "--rollback"},
+		err:  "--resume and --rollback are mutually exclusive",
+	}, {
+		args: []string{"riak", "--resume", "--rolling"},
+		err:  "--resume and --rollback cannot be combined with other flags",
+	}, {
+		args: []string{"riak", "--rollback", "--revision=2"},
+		err:  "--resume and --rollback cannot be combined with other flags",
+	}, {
+		args: []string{"riak", "--rolling", "--force"},
+		err:  "--rolling and --force are mutually exclusive",
+	}, {
+		args: []string{"riak", "--rolling", "--batch-size=0"},
+		err:  "--batch-size must be at least 1, got 0",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := runUpgradeCharm(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 type UpgradeCharmSuccessSuite struct {
 	jujutesting.RepoSuite
 	CmdBlockHelper
@@ -167,6 +193,52 @@ func (s *UpgradeCharmSuccessSuite) TestBlockUpgradeCharm(c *gc.C) {
 	s.AssertBlocked(c, err, ".*TestBlockUpgradeCharm.*")
 }
 
+func (s *UpgradeCharmSuccessSuite) TestRollingUpgrade(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		_, err := s.riak.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := runUpgradeCharm(c, "riak", "--rolling", "--batch-size=2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+	upgrade, ok := s.riak.RollingUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.PreviousCharmURL.String(), gc.Equals, "local:trusty/riak-7")
+	c.Assert(upgrade.BatchSize, gc.Equals, 2)
+	c.Assert(upgrade.Held, jc.DeepEquals, []string{"riak/0", "riak/1", "riak/2"})
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradeRunning)
+}
+
+func (s *UpgradeCharmSuccessSuite) TestRollingUpgradeResumeAndRollBack(c *gc.C) {
+	_, err := s.riak.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = runUpgradeCharm(c, "riak", "--rolling")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.riak.PauseRollingUpgrade("unit riak/0 failed")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runUpgradeCharm(c, "riak", "--resume")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.riak.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, ok := s.riak.RollingUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradeRunning)
+
+	err = runUpgradeCharm(c, "riak", "--rollback")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := s.assertUpgraded(c, 7, false)
+	c.Assert(curl.String(), gc.Equals, "local:trusty/riak-7")
+	_, ok = s.riak.RollingUpgrade()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *UpgradeCharmSuccessSuite) TestBlockRollingUpgrade(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockRollingUpgrade")
+	err := runUpgradeCharm(c, "riak", "--rolling")
+	s.AssertBlocked(c, err, ".*TestBlockRollingUpgrade.*")
+}
+
 func (s *UpgradeCharmSuccessSuite) TestRespectsLocalRevisionWhenPossible(c *gc.C) {
 	dir, err := charm.ReadCharmDir(s.path)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/version"
+)
+
+// checkUpgrade reports on the readiness of the environment for an
+// upgrade, failing if any problems are found. The version to upgrade to
+// is chosen as for a real upgrade; if that fails but a version was
+// given with --version, the failure is reported as a problem alongside
+// the environment's own checks rather than cutting the report short.
+func (c *UpgradeJujuCommand) checkUpgrade(ctx *cmd.Context, client upgradeJujuAPI, cfg *config.Config) error {
+	chosen := c.Version
+	context, versionErr := c.initVersions(client, cfg)
+	if versionErr == nil {
+		versionErr = context.validate()
+	}
+	switch {
+	case versionErr == nil:
+		chosen = context.chosen
+	case versionErr == errUpToDate || chosen == version.Zero:
+		return versionErr
+	}
+	result, err := client.UpgradeCheck(chosen)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	problems := formatUpgradeCheck(ctx.Stdout, result)
+	if versionErr != nil {
+		problems++
+		fmt.Fprintf(ctx.Stdout, "\nclient tools for %s:\n    error: %v\n", chosen, versionErr)
+	}
+	if problems > 0 {
+		return errors.Errorf("upgrade check found %d problem(s)", problems)
+	}
+	ctx.Infof("no problems found")
+	return nil
+}
+
+// formatUpgradeCheck writes the upgrade check report to w, and returns
+// the number of problems found.
+func formatUpgradeCheck(w io.Writer, result params.UpgradeCheckResult) (problems int) {
+	fmt.Fprintf(w, "upgrade from %s to %s\n", result.CurrentVersion, result.Version)
+
+	fmt.Fprintf(w, "\nagents down or in error:\n")
+	if len(result.ProblemAgents) == 0 {
+		fmt.Fprintf(w, "    none\n")
+	}
+	for _, agent := range result.ProblemAgents {
+		problems++
+		status := string(agent.Status)
+		if agent.Message != "" {
+			status += ": " + agent.Message
+		}
+		if agent.Down {
+			status = fmt.Sprintf("down (%s)", status)
+		}
+		fmt.Fprintf(w, "    %s: %s\n", agent.Tag, status)
+	}
+
+	fmt.Fprintf(w, "\ntools for %s:\n", result.Version)
+	for _, tools := range result.Tools {
+		source := tools.Source
+		switch {
+		case tools.Error != "":
+			problems++
+			source = "error: " + tools.Error
+		case source == "":
+			problems++
+			source = "not found"
+		}
+		fmt.Fprintf(w, "    %s/%s: %s\n", tools.Series, tools.Arch, source)
+	}
+
+	fmt.Fprintf(w, "\nreplica set:\n")
+	if result.ReplicaSetError != "" {
+		problems++
+		fmt.Fprintf(w, "    error: %s\n", result.ReplicaSetError)
+	}
+	for _, member := range result.ReplicaSet {
+		health := "healthy"
+		if !member.Healthy {
+			problems++
+			health = "unhealthy"
+		}
+		if member.Message != "" {
+			health += ": " + member.Message
+		}
+		fmt.Fprintf(w, "    %s: %s, %s\n", member.Address, member.State, health)
+	}
+
+	fmt.Fprintf(w, "\nupgrade steps known to %s that will run:\n", result.ServerVersion)
+	if len(result.UpgradeSteps) == 0 {
+		fmt.Fprintf(w, "    none\n")
+	}
+	for _, step := range result.UpgradeSteps {
+		fmt.Fprintf(w, "    %s\n", step)
+	}
+
+	fmt.Fprintf(w, "\nfree space on state servers:\n")
+	for _, disk := range result.DiskSpace {
+		if disk.Error != "" {
+			problems++
+			fmt.Fprintf(w, "    machine %s: error: %s\n", disk.MachineId, disk.Error)
+			continue
+		}
+		fmt.Fprintf(w, "    machine %s: %dMiB in %s\n", disk.MachineId, disk.Free/(1024*1024), disk.Path)
+	}
+	return problems
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -32,9 +33,16 @@ type UpgradeJujuCommand struct {
 	Version       version.Number
 	UploadTools   bool
 	DryRun        bool
+	Check         bool
 	ResetPrevious bool
 	AssumeYes     bool
 	Series        []string
+
+	canaries     string
+	Canaries     []string
+	BatchSize    int
+	AbortStaged  bool
+	ResumeStaged bool
 }
 
 var upgradeJujuDoc = `
@@ -74,7 +82,31 @@ progress. It will also abort if a previous upgrade was partially
 completed - this can happen if one of the state servers in a high
 availability environment failed to upgrade. If a failed upgrade has
 been resolved, the --reset-previous-upgrade flag can be used to reset
-the environment's upgrade tracking state, allowing further upgrades.`
+the environment's upgrade tracking state, allowing further upgrades.
+
+By default every agent upgrades as soon as the state servers have. A staged
+upgrade instead holds the other machines, and the units on them, at the
+current version while the state servers and a set of canary machines,
+named with --canary, upgrade. Once all of those have run their upgrade steps
+and report healthy, the remaining machines are released --batch-size at a
+time, each batch upgrading only after the one before it is healthy. Giving
+either --canary or --batch-size starts a staged upgrade.
+
+If any machine fails to upgrade, the staged upgrade pauses. The operator can
+then continue it with --resume-staged, or stop it with --abort-staged, which
+pins the machines not yet upgraded at the previous version. An aborted
+upgrade can also be continued with --resume-staged. The progress of a staged
+upgrade is shown by juju status.
+
+The --check flag reports, without changing anything, on the readiness of the
+environment for an upgrade to the chosen version: agents that are down or in
+error, whether tools for every series and architecture in use can be found,
+the health of the mongo replica set, the upgrade steps that will run, and the
+free space for the backup taken during the upgrade, as last recorded by each
+state server. The upgrade steps listed are those known to the state server's
+version of Juju. If --version is given but no tools for it can be found, that
+is reported alongside the other checks. The command fails if any problems are
+found.`
 
 func (c *UpgradeJujuCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -88,10 +120,15 @@ func (c *UpgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.vers, "version", "", "upgrade to specific version")
 	f.BoolVar(&c.UploadTools, "upload-tools", false, "upload local version of tools")
 	f.BoolVar(&c.DryRun, "dry-run", false, "don't change anything, just report what would change")
+	f.BoolVar(&c.Check, "check", false, "don't change anything, just check the environment is ready to upgrade")
 	f.BoolVar(&c.ResetPrevious, "reset-previous-upgrade", false, "clear the previous (incomplete) upgrade status (use with care)")
 	f.BoolVar(&c.AssumeYes, "y", false, "answer 'yes' to confirmation prompts")
 	f.BoolVar(&c.AssumeYes, "yes", false, "")
 	f.Var(newSeriesValue(nil, &c.Series), "series", "upload tools for supplied comma-separated series list (OBSOLETE)")
+	f.StringVar(&c.canaries, "canary", "", "comma-separated list of machines to upgrade first in a staged upgrade")
+	f.IntVar(&c.BatchSize, "batch-size", 0, "number of machines to upgrade at a time in a staged upgrade")
+	f.BoolVar(&c.AbortStaged, "abort-staged", false, "stop the staged upgrade, holding machines not yet upgraded")
+	f.BoolVar(&c.ResumeStaged, "resume-staged", false, "continue a paused or aborted staged upgrade")
 }
 
 func (c *UpgradeJujuCommand) Init(args []string) error {
@@ -117,9 +154,36 @@ func (c *UpgradeJujuCommand) Init(args []string) error {
 	if len(c.Series) > 0 && !c.UploadTools {
 		return fmt.Errorf("--series requires --upload-tools")
 	}
+	if c.canaries != "" {
+		for _, id := range strings.Split(c.canaries, ",") {
+			if !names.IsValidMachine(id) {
+				return fmt.Errorf("invalid machine %q in --canary", id)
+			}
+			c.Canaries = append(c.Canaries, id)
+		}
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("--batch-size must be at least 1, got %d", c.BatchSize)
+	}
+	if c.AbortStaged || c.ResumeStaged {
+		if c.AbortStaged && c.ResumeStaged {
+			return fmt.Errorf("--abort-staged and --resume-staged are mutually exclusive")
+		}
+		if c.vers != "" || c.UploadTools || c.DryRun || c.Check || c.ResetPrevious || c.staged() {
+			return fmt.Errorf("--abort-staged and --resume-staged cannot be combined with other flags")
+		}
+	}
+	if c.Check && (c.UploadTools || c.ResetPrevious || c.staged()) {
+		return fmt.Errorf("--check cannot be combined with --upload-tools, --reset-previous-upgrade or staged upgrade flags")
+	}
 	return cmd.CheckEmpty(args)
 }
 
+// staged returns whether a staged upgrade was requested.
+func (c *UpgradeJujuCommand) staged() bool {
+	return len(c.Canaries) > 0 || c.BatchSize > 0
+}
+
 var errUpToDate = stderrors.New("no upgrades available")
 
 func formatTools(tools coretools.List) string {
@@ -136,6 +200,10 @@ type upgradeJujuAPI interface {
 	UploadTools(r io.Reader, vers version.Binary, additionalSeries ...string) (*coretools.Tools, error)
 	AbortCurrentUpgrade() error
 	SetEnvironAgentVersion(version version.Number) error
+	StartStagedUpgrade(version version.Number, canaryMachines []string, batchSize int) error
+	AbortStagedUpgrade() error
+	ResumeStagedUpgrade() error
+	UpgradeCheck(version version.Number) (params.UpgradeCheckResult, error)
 	Close() error
 }
 
@@ -154,6 +222,9 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 		return err
 	}
 	defer client.Close()
+	if c.AbortStaged || c.ResumeStaged {
+		return c.updateStagedUpgrade(ctx, client)
+	}
 	defer func() {
 		if err == errUpToDate {
 			ctx.Infof(err.Error())
@@ -170,6 +241,9 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if c.Check {
+		return c.checkUpgrade(ctx, client, cfg)
+	}
 	context, err := c.initVersions(client, cfg)
 	if err != nil {
 		return err
@@ -200,6 +274,9 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 				return block.ProcessBlockedError(err, block.BlockChange)
 			}
 		}
+		if c.staged() {
+			return c.startStagedUpgrade(ctx, client, context.chosen)
+		}
 		if err := client.SetEnvironAgentVersion(context.chosen); err != nil {
 			if params.IsCodeUpgradeInProgress(err) {
 				return errors.Errorf("%s\n\n"+
@@ -216,6 +293,37 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// startStagedUpgrade starts a staged upgrade to the given version.
+func (c *UpgradeJujuCommand) startStagedUpgrade(ctx *cmd.Context, client upgradeJujuAPI, chosen version.Number) error {
+	batchSize := c.BatchSize
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	if err := client.StartStagedUpgrade(chosen, c.Canaries, batchSize); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	logger.Infof("started staged upgrade to %s", chosen)
+	ctx.Infof("started staged upgrade to %s, %d machine(s) at a time", chosen, batchSize)
+	return nil
+}
+
+// updateStagedUpgrade aborts or resumes the current staged upgrade,
+// as requested.
+func (c *UpgradeJujuCommand) updateStagedUpgrade(ctx *cmd.Context, client upgradeJujuAPI) error {
+	if c.AbortStaged {
+		if err := client.AbortStagedUpgrade(); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		ctx.Infof("aborted staged upgrade")
+		return nil
+	}
+	if err := client.ResumeStagedUpgrade(); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("resumed staged upgrade")
+	return nil
+}
+
 const resetPreviousUpgradeMessage = `
 WARNING! using --reset-previous-upgrade when an upgrade is in progress
 will cause the upgrade to fail. Only use this option to clear an
@@ -7,6 +7,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
@@ -605,6 +606,188 @@ func (s *UpgradeJujuSuite) TestUpgradeInProgress(c *gc.C) {
 	)
 }
 
+func (s *UpgradeJujuSuite) TestUpgradeCheck(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.checkResult = params.UpgradeCheckResult{
+		CurrentVersion: version.MustParse("1.2.3"),
+		Tools: []params.UpgradeCheckTools{{
+			Series: "trusty", Arch: "amd64", Source: "tool storage",
+		}},
+		ReplicaSet: []params.UpgradeCheckMember{{
+			Address: "10.0.0.1:37017", State: "PRIMARY", Healthy: true,
+		}},
+		ServerVersion: version.MustParse("1.2.3"),
+		UpgradeSteps:  []string{"migrate things"},
+		DiskSpace: []params.UpgradeCheckDiskSpace{{
+			MachineId: "0", Path: "/var/lib/juju", Free: 3 * 1024 * 1024,
+		}, {
+			MachineId: "1", Path: "/var/lib/juju", Free: 5 * 1024 * 1024,
+		}},
+	}
+	fakeAPI.patch(s)
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--check"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := coretesting.Context(c)
+	err = cmd.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.checkCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, fmt.Sprintf(`
+upgrade from 1.2.3 to %s
+
+agents down or in error:
+    none
+
+tools for %s:
+    trusty/amd64: tool storage
+
+replica set:
+    10.0.0.1:37017: PRIMARY, healthy
+
+upgrade steps known to 1.2.3 that will run:
+    migrate things
+
+free space on state servers:
+    machine 0: 3MiB in /var/lib/juju
+    machine 1: 5MiB in /var/lib/juju
+`[1:], fakeAPI.nextVersion.Number, fakeAPI.nextVersion.Number))
+	c.Assert(coretesting.Stderr(ctx), jc.Contains, "no problems found")
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeCheckProblems(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.checkResult = params.UpgradeCheckResult{
+		ProblemAgents: []params.UpgradeCheckAgent{{
+			Tag: "machine-1", Status: params.StatusStarted, Down: true,
+		}, {
+			Tag: "unit-mysql-0", Status: params.StatusError, Message: "hook failed",
+		}},
+		Tools: []params.UpgradeCheckTools{{
+			Series: "trusty", Arch: "arm64",
+		}},
+		ReplicaSet: []params.UpgradeCheckMember{{
+			Address: "10.0.0.2:37017", State: "UNKNOWN", Message: "no route to host",
+		}},
+		DiskSpace: []params.UpgradeCheckDiskSpace{{
+			MachineId: "0", Error: "free space not recorded",
+		}},
+	}
+	fakeAPI.patch(s)
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--check"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := coretesting.Context(c)
+	err = cmd.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, `upgrade check found 5 problem\(s\)`)
+	output := coretesting.Stdout(ctx)
+	c.Assert(output, jc.Contains, "    machine-1: down (started)\n")
+	c.Assert(output, jc.Contains, "    unit-mysql-0: error: hook failed\n")
+	c.Assert(output, jc.Contains, "    trusty/arm64: not found\n")
+	c.Assert(output, jc.Contains, "    10.0.0.2:37017: UNKNOWN, unhealthy: no route to host\n")
+	c.Assert(output, jc.Contains, "    machine 0: error: free space not recorded\n")
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeCheckReportsMissingClientTools(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	missing := fakeAPI.nextVersion.Number
+	missing.Patch += 5
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--check", "--version", missing.String()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := coretesting.Context(c)
+	err = cmd.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, `upgrade check found 1 problem\(s\)`)
+	c.Assert(fakeAPI.checkCalledWith, gc.Equals, missing)
+	c.Assert(coretesting.Stdout(ctx), jc.HasSuffix, fmt.Sprintf(
+		"\nclient tools for %s:\n    error: no matching tools available\n", missing,
+	))
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeCheckFlagsInvalid(c *gc.C) {
+	for i, args := range [][]string{
+		{"--check", "--upload-tools"},
+		{"--check", "--reset-previous-upgrade"},
+		{"--check", "--canary", "1"},
+	} {
+		c.Logf("test %d: %v", i, args)
+		err := coretesting.InitCommand(envcmd.Wrap(&UpgradeJujuCommand{}), args)
+		c.Check(err, gc.ErrorMatches, "--check cannot be combined with --upload-tools, --reset-previous-upgrade or staged upgrade flags")
+	}
+}
+
+func (s *UpgradeJujuSuite) TestStagedUpgrade(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--canary", "1,2/lxc/0", "--batch-size", "3"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.stagedCalls, jc.DeepEquals, []string{"StartStagedUpgrade"})
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(fakeAPI.stagedCanaries, jc.DeepEquals, []string{"1", "2/lxc/0"})
+	c.Assert(fakeAPI.stagedBatchSize, gc.Equals, 3)
+}
+
+func (s *UpgradeJujuSuite) TestStagedUpgradeDefaultBatchSize(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--canary", "1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.stagedCalls, jc.DeepEquals, []string{"StartStagedUpgrade"})
+	c.Assert(fakeAPI.stagedBatchSize, gc.Equals, 1)
+}
+
+func (s *UpgradeJujuSuite) TestAbortAndResumeStagedUpgrade(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	for _, flag := range []string{"--abort-staged", "--resume-staged"} {
+		cmd := &UpgradeJujuCommand{}
+		err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{flag})
+		c.Assert(err, jc.ErrorIsNil)
+		err = cmd.Run(coretesting.Context(c))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(fakeAPI.stagedCalls, jc.DeepEquals, []string{"AbortStagedUpgrade", "ResumeStagedUpgrade"})
+	c.Assert(fakeAPI.findToolsCalled, jc.IsFalse)
+}
+
+func (s *UpgradeJujuSuite) TestStagedUpgradeFlagsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--canary", "1,foo"},
+		err:  `invalid machine "foo" in --canary`,
+	}, {
+		args: []string{"--batch-size", "-1"},
+		err:  "--batch-size must be at least 1, got -1",
+	}, {
+		args: []string{"--abort-staged", "--resume-staged"},
+		err:  "--abort-staged and --resume-staged are mutually exclusive",
+	}, {
+		args: []string{"--abort-staged", "--canary", "1"},
+		err:  "--abort-staged and --resume-staged cannot be combined with other flags",
+	}, {
+		args: []string{"--resume-staged", "--dry-run"},
+		err:  "--abort-staged and --resume-staged cannot be combined with other flags",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := coretesting.InitCommand(envcmd.Wrap(&UpgradeJujuCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *UpgradeJujuSuite) TestBlockUpgradeInProgress(c *gc.C) {
 	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
 	fakeAPI.setVersionErr = common.ErrOperationBlocked("The operation has been blocked.")
@@ -696,6 +879,11 @@ type fakeUpgradeJujuAPI struct {
 	setVersionCalledWith      version.Number
 	tools                     []string
 	findToolsCalled           bool
+	stagedCalls               []string
+	stagedCanaries            []string
+	stagedBatchSize           int
+	checkCalledWith           version.Number
+	checkResult               params.UpgradeCheckResult
 }
 
 func (a *fakeUpgradeJujuAPI) reset() {
@@ -754,6 +942,31 @@ func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersion(v version.Number) error {
 	return a.setVersionErr
 }
 
+func (a *fakeUpgradeJujuAPI) StartStagedUpgrade(v version.Number, canaryMachines []string, batchSize int) error {
+	a.setVersionCalledWith = v
+	a.stagedCalls = append(a.stagedCalls, "StartStagedUpgrade")
+	a.stagedCanaries = canaryMachines
+	a.stagedBatchSize = batchSize
+	return a.setVersionErr
+}
+
+func (a *fakeUpgradeJujuAPI) AbortStagedUpgrade() error {
+	a.stagedCalls = append(a.stagedCalls, "AbortStagedUpgrade")
+	return nil
+}
+
+func (a *fakeUpgradeJujuAPI) ResumeStagedUpgrade() error {
+	a.stagedCalls = append(a.stagedCalls, "ResumeStagedUpgrade")
+	return nil
+}
+
+func (a *fakeUpgradeJujuAPI) UpgradeCheck(v version.Number) (params.UpgradeCheckResult, error) {
+	a.checkCalledWith = v
+	result := a.checkResult
+	result.Version = v
+	return result, nil
+}
+
 func (a *fakeUpgradeJujuAPI) Close() error {
 	return nil
 }
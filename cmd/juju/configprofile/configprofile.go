@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/configprofiles"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const commandDoc = `
+"juju config-profile" provides commands to manage the environment's charm
+config profiles.
+
+A config profile is a named set of charm config settings which services
+may use in place of their charm's defaults. A service uses a profile when
+deployed with "juju deploy --config-profile <profile>", or after running
+"juju set <service> --profile <profile>". Settings set explicitly for the
+service take precedence over those of its profile, and settings in the
+profile that are not options of the service's charm are ignored.
+
+Config profiles may not be changed once created, so that the config of the
+services using them does not change unexpectedly; create a new profile and
+switch services to it instead.
+
+"juju get <service>" shows the service's profile, and marks the settings
+taken from it with "profile: true".
+`
+
+// NewSuperCommand creates the "config-profile" supercommand and
+// registers the subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	profileCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "config-profile",
+		Doc:         strings.TrimSpace(commandDoc),
+		UsagePrefix: "juju",
+		Purpose:     "manage charm config profiles",
+	})
+	profileCmd.Register(envcmd.Wrap(&CreateCommand{}))
+	profileCmd.Register(envcmd.Wrap(&ListCommand{}))
+	profileCmd.Register(envcmd.Wrap(&ShowCommand{}))
+	return profileCmd
+}
+
+// ConfigProfilesAPI defines the API methods that the config-profile
+// subcommands use.
+type ConfigProfilesAPI interface {
+	AddConfigProfile(name, settingsYAML string) error
+	ListConfigProfiles() ([]params.ConfigProfile, error)
+	ConfigProfile(name string) (params.ConfigProfile, error)
+	Close() error
+}
+
+// configProfileCommandBase is the base type embedded into all
+// config-profile subcommands.
+type configProfileCommandBase struct {
+	envcmd.EnvCommandBase
+	api ConfigProfilesAPI
+}
+
+func (c *configProfileCommandBase) getAPI() (ConfigProfilesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return configprofiles.NewClient(root), nil
+}
+
+// notSupported returns a more helpful error if err indicates that the
+// API server does not support config profiles.
+func notSupported(err error) error {
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("config profiles are not supported by the API server")
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile_test
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// mockConfigProfilesAPI is a fake ConfigProfilesAPI holding config
+// profiles in memory.
+type mockConfigProfilesAPI struct {
+	profiles []params.ConfigProfile
+	added    map[string]string
+	err      error
+}
+
+func (m *mockConfigProfilesAPI) AddConfigProfile(name, settingsYAML string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.added == nil {
+		m.added = make(map[string]string)
+	}
+	m.added[name] = settingsYAML
+	return nil
+}
+
+func (m *mockConfigProfilesAPI) ListConfigProfiles() ([]params.ConfigProfile, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.profiles, nil
+}
+
+func (m *mockConfigProfilesAPI) ConfigProfile(name string) (params.ConfigProfile, error) {
+	if m.err != nil {
+		return params.ConfigProfile{}, m.err
+	}
+	for _, profile := range m.profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return params.ConfigProfile{}, errors.NotFoundf("config profile %q", name)
+}
+
+func (m *mockConfigProfilesAPI) Close() error {
+	return nil
+}
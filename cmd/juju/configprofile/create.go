@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const createDoc = `
+Create a config profile from a YAML file mapping charm config option
+names to values, for example:
+
+    title: Production
+    log-level: WARNING
+    workers: 8
+
+A profile may hold settings for the options of several charms; each
+service using the profile takes only the settings for its charm's options.
+
+Examples:
+
+    juju config-profile create production production.yaml
+    juju deploy mysql --config-profile production
+
+See Also:
+    juju help config-profile
+    juju help deploy
+    juju help set
+`
+
+// CreateCommand creates a config profile.
+type CreateCommand struct {
+	configProfileCommandBase
+	name string
+	path string
+}
+
+// Info implements Command.Info.
+func (c *CreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<name> <settings.yaml>",
+		Purpose: "create a charm config profile",
+		Doc:     createDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *CreateCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no config profile name specified")
+	case 1:
+		return errors.New("no settings file specified")
+	}
+	c.name, c.path = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run implements Command.Run.
+func (c *CreateCommand) Run(ctx *cmd.Context) error {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.path))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	err = api.AddConfigProfile(c.name, string(data))
+	return block.ProcessBlockedError(notSupported(err), block.BlockChange)
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/configprofile"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type createSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *mockConfigProfilesAPI
+}
+
+var _ = gc.Suite(&createSuite{})
+
+func (s *createSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockConfigProfilesAPI{}
+}
+
+func (s *createSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := configprofile.NewCreateCommand(s.api)
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *createSuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no config profile name specified")
+	_, err = s.run(c, "production")
+	c.Assert(err, gc.ErrorMatches, "no settings file specified")
+	_, err = s.run(c, "production", "settings.yaml", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *createSuite) TestCreate(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "production.yaml")
+	err := ioutil.WriteFile(path, []byte("title: Production\nworkers: 8\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.run(c, "production", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.added, jc.DeepEquals, map[string]string{
+		"production": "title: Production\nworkers: 8\n",
+	})
+}
+
+func (s *createSuite) TestCreateMissingFile(c *gc.C) {
+	_, err := s.run(c, "production", filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "open .*missing.yaml: .*")
+	c.Assert(s.api.added, gc.HasLen, 0)
+}
+
+func (s *createSuite) TestCreateNotSupported(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "production.yaml")
+	err := ioutil.WriteFile(path, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.err = &params.Error{Code: params.CodeNotImplemented, Message: "not implemented"}
+	_, err = s.run(c, "production", path)
+	c.Assert(err, gc.ErrorMatches, "config profiles are not supported by the API server")
+}
@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile
+
+// NewCreateCommand returns a CreateCommand with the api provided as
+// specified.
+func NewCreateCommand(api ConfigProfilesAPI) *CreateCommand {
+	c := &CreateCommand{}
+	c.api = api
+	return c
+}
+
+// NewListCommand returns a ListCommand with the api provided as
+// specified.
+func NewListCommand(api ConfigProfilesAPI) *ListCommand {
+	c := &ListCommand{}
+	c.api = api
+	return c
+}
+
+// NewShowCommand returns a ShowCommand with the api provided as
+// specified.
+func NewShowCommand(api ConfigProfilesAPI) *ShowCommand {
+	c := &ShowCommand{}
+	c.api = api
+	return c
+}
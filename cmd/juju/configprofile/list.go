@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const listDoc = `
+List the names of the environment's config profiles. Use the yaml or
+json formats to include each profile's settings.
+
+See Also:
+    juju help config-profile
+`
+
+// ListCommand lists the environment's config profiles.
+type ListCommand struct {
+	configProfileCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list charm config profiles",
+		Doc:     listDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	profiles, err := api.ListConfigProfiles()
+	if err != nil {
+		return notSupported(err)
+	}
+	if c.out.Name() == "smart" {
+		if len(profiles) == 0 {
+			fmt.Fprintln(ctx.Stderr, "no config profiles to display")
+			return nil
+		}
+		names := make([]string, len(profiles))
+		for i, profile := range profiles {
+			names[i] = profile.Name
+		}
+		return c.out.Write(ctx, names)
+	}
+	result := make(map[string]map[string]interface{})
+	for _, profile := range profiles {
+		result[profile.Name] = profile.Settings
+	}
+	return c.out.Write(ctx, result)
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/configprofile"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type listSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *mockConfigProfilesAPI
+}
+
+var _ = gc.Suite(&listSuite{})
+
+func (s *listSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockConfigProfilesAPI{
+		profiles: []params.ConfigProfile{{
+			Name:     "production",
+			Settings: map[string]interface{}{"title": "Production"},
+		}, {
+			Name:     "staging",
+			Settings: map[string]interface{}{"title": "Staging"},
+		}},
+	}
+}
+
+func (s *listSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := configprofile.NewListCommand(s.api)
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *listSuite) TestList(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "production\nstaging\n")
+}
+
+func (s *listSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+production:
+  title: Production
+staging:
+  title: Staging
+`[1:])
+}
+
+func (s *listSuite) TestListEmpty(c *gc.C) {
+	s.api.profiles = nil
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "no config profiles to display\n")
+}
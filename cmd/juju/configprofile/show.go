@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+const showDoc = `
+Display the settings of a config profile.
+
+See Also:
+    juju help config-profile
+`
+
+// ShowCommand displays the settings of a config profile.
+type ShowCommand struct {
+	configProfileCommandBase
+	name string
+	out  cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ShowCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show",
+		Args:    "<name>",
+		Purpose: "display a charm config profile",
+		Doc:     showDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ShowCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ShowCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no config profile name specified")
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *ShowCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	profile, err := api.ConfigProfile(c.name)
+	if err != nil {
+		return notSupported(err)
+	}
+	return c.out.Write(ctx, map[string]interface{}{
+		"name":     profile.Name,
+		"settings": profile.Settings,
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofile_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/configprofile"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type showSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *mockConfigProfilesAPI
+}
+
+var _ = gc.Suite(&showSuite{})
+
+func (s *showSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockConfigProfilesAPI{
+		profiles: []params.ConfigProfile{{
+			Name: "production",
+			Settings: map[string]interface{}{
+				"title":   "Production",
+				"workers": float64(8),
+			},
+		}},
+	}
+}
+
+func (s *showSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := configprofile.NewShowCommand(s.api)
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *showSuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no config profile name specified")
+	_, err = s.run(c, "production", "staging")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["staging"\]`)
+}
+
+func (s *showSuite) TestShow(c *gc.C) {
+	ctx, err := s.run(c, "production")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+name: production
+settings:
+  title: Production
+  workers: 8
+`[1:])
+}
+
+func (s *showSuite) TestShowNotFound(c *gc.C) {
+	_, err := s.run(c, "staging")
+	c.Assert(err, gc.ErrorMatches, `config profile "staging" not found`)
+}
@@ -0,0 +1,22 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress
+
+func NewListCommand(api IPAddressAPI) *ListCommand {
+	listCmd := &ListCommand{}
+	listCmd.api = api
+	return listCmd
+}
+
+func NewReserveCommand(api IPAddressAPI) *ReserveCommand {
+	reserveCmd := &ReserveCommand{}
+	reserveCmd.api = api
+	return reserveCmd
+}
+
+func NewReleaseCommand(api IPAddressAPI) *ReleaseCommand {
+	releaseCmd := &ReleaseCommand{}
+	releaseCmd.api = api
+	return releaseCmd
+}
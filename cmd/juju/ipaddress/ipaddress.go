@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/ipaddresses"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// IPAddressAPI defines the necessary API methods needed by the
+// ip-address subcommands.
+type IPAddressAPI interface {
+	io.Closer
+
+	// ListIPAddresses returns the IP addresses known to Juju,
+	// optionally only those in the given subnet and/or held by the
+	// given machine.
+	ListIPAddresses(subnetCIDR, machineId string) ([]params.IPAddressInfo, error)
+
+	// ReserveIPAddress reserves an address in the given subnet, so it
+	// is never allocated to a container. When address is empty, a
+	// free address is picked. The reserved address is returned.
+	ReserveIPAddress(subnetCIDR, address string) (string, error)
+
+	// ReleaseIPAddresses releases the given addresses with the
+	// provider and removes them from Juju, returning one result per
+	// address.
+	ReleaseIPAddresses(addresses []string, force bool) ([]params.ErrorResult, error)
+}
+
+const commandDoc = `
+"juju ip-address" provides commands to manage the static IP addresses
+Juju allocates to containers from the subnets it knows about.
+
+Addresses can be listed to see which machine holds which address on
+which subnet, reserved so that Juju never allocates them, and released
+to return leaked allocations to the provider.`
+
+// NewSuperCommand creates the "ip-address" supercommand and registers
+// the subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	ipAddressCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "ip-address",
+		Doc:         strings.TrimSpace(commandDoc),
+		UsagePrefix: "juju",
+		Purpose:     "manage static IP addresses",
+	})
+	ipAddressCmd.Register(envcmd.Wrap(&ListCommand{}))
+	ipAddressCmd.Register(envcmd.Wrap(&ReserveCommand{}))
+	ipAddressCmd.Register(envcmd.Wrap(&ReleaseCommand{}))
+	return ipAddressCmd
+}
+
+// IPAddressCommandBase is the base type embedded into all ip-address
+// subcommands.
+type IPAddressCommandBase struct {
+	envcmd.EnvCommandBase
+	api IPAddressAPI
+}
+
+// apiShim forwards IPAddressAPI methods to the real API facade.
+type apiShim struct {
+	apiState api.Connection
+	facade   *ipaddresses.API
+}
+
+func (s *apiShim) Close() error {
+	return s.apiState.Close()
+}
+
+func (s *apiShim) ListIPAddresses(subnetCIDR, machineId string) ([]params.IPAddressInfo, error) {
+	return s.facade.ListIPAddresses(subnetCIDR, machineId)
+}
+
+func (s *apiShim) ReserveIPAddress(subnetCIDR, address string) (string, error) {
+	return s.facade.ReserveIPAddress(subnetCIDR, address)
+}
+
+func (s *apiShim) ReleaseIPAddresses(addresses []string, force bool) ([]params.ErrorResult, error) {
+	return s.facade.ReleaseIPAddresses(addresses, force)
+}
+
+// NewAPI returns an IPAddressAPI for the root api endpoint that the
+// environment command returns.
+func (c *IPAddressCommandBase) NewAPI() (IPAddressAPI, error) {
+	if c.api != nil {
+		// Already created.
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &apiShim{
+		apiState: root,
+		facade:   ipaddresses.NewAPI(root),
+	}, nil
+}
+
+type RunOnAPI func(api IPAddressAPI, ctx *cmd.Context) error
+
+func (c *IPAddressCommandBase) RunWithAPI(ctx *cmd.Context, toRun RunOnAPI) error {
+	api, err := c.NewAPI()
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to the API server")
+	}
+	defer api.Close()
+	return toRun(api, ctx)
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/ipaddress"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type IPAddressCommandSuite struct {
+	BaseIPAddressSuite
+}
+
+var _ = gc.Suite(&IPAddressCommandSuite{})
+
+func (s *IPAddressCommandSuite) TestHelpSubcommands(c *gc.C) {
+	ctx, err := coretesting.RunCommand(c, ipaddress.NewSuperCommand(), "--help")
+	c.Assert(err, jc.ErrorIsNil)
+	stdout := coretesting.Stdout(ctx)
+	for _, expected := range []string{
+		"list +- list IP addresses known to Juju",
+		"release +- release IP addresses and remove them from Juju",
+		"reserve +- reserve an IP address in a subnet",
+	} {
+		c.Check(stdout, gc.Matches, "(?sm).*^ +"+expected+"$.*")
+	}
+}
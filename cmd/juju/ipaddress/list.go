@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+)
+
+// ListCommand displays the IP addresses known to Juju.
+type ListCommand struct {
+	IPAddressCommandBase
+	SubnetCIDR string
+	MachineId  string
+	out        cmd.Output
+}
+
+const listCommandDoc = `
+Displays the static IP addresses known to Juju, with the subnet each
+belongs to and, for allocated addresses, the machine (usually a
+container) holding it and the instance hosting that machine.
+
+Addresses in the "reserved" state are never allocated by Juju. Dead
+addresses are waiting to be released with the provider; a release
+which failed can be retried with "juju ip-address release".
+
+The output can be limited to a single subnet with --subnet and to a
+single machine with --machine.`
+
+// Info is defined on the cmd.Command interface.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Args:    "[--subnet <CIDR>] [--machine <id>] [--format yaml|json] [--output <path>]",
+		Purpose: "list IP addresses known to Juju",
+		Doc:     strings.TrimSpace(listCommandDoc),
+	}
+}
+
+// SetFlags is defined on the cmd.Command interface.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.IPAddressCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+
+	f.StringVar(&c.SubnetCIDR, "subnet", "", "only list addresses in the given subnet")
+	f.StringVar(&c.MachineId, "machine", "", "only list addresses held by the given machine")
+}
+
+// Init is defined on the cmd.Command interface. It checks the
+// arguments for sanity and sets up the command to run.
+func (c *ListCommand) Init(args []string) error {
+	if c.SubnetCIDR != "" {
+		_, ipNet, err := net.ParseCIDR(c.SubnetCIDR)
+		if err != nil {
+			return errors.Errorf("%q is not a valid CIDR", c.SubnetCIDR)
+		}
+		c.SubnetCIDR = ipNet.String()
+	}
+	if c.MachineId != "" && !names.IsValidMachine(c.MachineId) {
+		return errors.Errorf("%q is not a valid machine id", c.MachineId)
+	}
+	return errors.Trace(cmd.CheckEmpty(args))
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	return c.RunWithAPI(ctx, func(api IPAddressAPI, ctx *cmd.Context) error {
+		addresses, err := api.ListIPAddresses(c.SubnetCIDR, c.MachineId)
+		if err != nil {
+			return errors.Annotate(err, "cannot list IP addresses")
+		}
+		if len(addresses) == 0 {
+			ctx.Infof("no IP addresses to display")
+			return nil
+		}
+
+		result := formattedList{
+			Addresses: make(map[string]formattedAddress),
+		}
+		for _, addr := range addresses {
+			formatted := formattedAddress{
+				InstanceId: addr.InstanceId,
+				MACAddress: addr.MACAddress,
+				State:      addr.State,
+				Life:       string(addr.Life),
+			}
+			if tag, err := names.ParseSubnetTag(addr.SubnetTag); err == nil {
+				formatted.Subnet = tag.Id()
+			}
+			if tag, err := names.ParseMachineTag(addr.MachineTag); err == nil {
+				formatted.Machine = tag.Id()
+			}
+			result.Addresses[addr.Value] = formatted
+		}
+		return c.out.Write(ctx, result)
+	})
+}
+
+type formattedList struct {
+	Addresses map[string]formattedAddress `json:"ip-addresses" yaml:"ip-addresses"`
+}
+
+type formattedAddress struct {
+	Subnet     string `json:"subnet,omitempty" yaml:"subnet,omitempty"`
+	Machine    string `json:"machine,omitempty" yaml:"machine,omitempty"`
+	InstanceId string `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
+	MACAddress string `json:"mac-address,omitempty" yaml:"mac-address,omitempty"`
+	State      string `json:"state" yaml:"state"`
+	Life       string `json:"life" yaml:"life"`
+}
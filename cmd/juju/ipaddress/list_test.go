@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress_test
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/ipaddress"
+)
+
+type ListSuite struct {
+	BaseIPAddressSuite
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.BaseIPAddressSuite.SetUpTest(c)
+	s.command = ipaddress.NewListCommand(s.api)
+}
+
+func (s *ListSuite) TestInitErrors(c *gc.C) {
+	s.AssertRunFails(c, `"foo" is not a valid CIDR`, "--subnet", "foo")
+	s.AssertRunFails(c, `"bar" is not a valid machine id`, "--machine", "bar")
+	s.AssertRunFails(c, `unrecognized args: \["baz"\]`, "baz")
+	s.api.CheckCallNames(c)
+}
+
+func (s *ListSuite) TestRunYAML(c *gc.C) {
+	s.AssertRunSucceeds(c, "", `
+ip-addresses:
+  10.0.0.11:
+    subnet: 10.0.0.0/24
+    machine: 0/lxc/1
+    instance-id: i-host
+    mac-address: aa:bb:cc:dd:ee:f0
+    state: allocated
+    life: alive
+  10.0.0.15:
+    subnet: 10.0.0.0/24
+    state: reserved
+    life: alive
+`[1:])
+	s.api.CheckCallNames(c, "ListIPAddresses", "Close")
+	s.api.CheckCall(c, 0, "ListIPAddresses", "", "")
+}
+
+func (s *ListSuite) TestRunWithFilterJSON(c *gc.C) {
+	s.api.Addresses = s.api.Addresses[1:]
+	s.AssertRunSucceeds(c, "",
+		`{"ip-addresses":{"10.0.0.15":{"subnet":"10.0.0.0/24","state":"reserved","life":"alive"}}}`+"\n",
+		"--subnet", "10.0.0.1/24", "--machine", "0/lxc/1", "--format", "json",
+	)
+	s.api.CheckCall(c, 0, "ListIPAddresses", "10.0.0.0/24", "0/lxc/1")
+}
+
+func (s *ListSuite) TestRunNoAddresses(c *gc.C) {
+	s.api.Addresses = nil
+	s.AssertRunSucceeds(c, "no IP addresses to display\n", "")
+}
+
+func (s *ListSuite) TestRunAPIFails(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	s.AssertRunFails(c, "cannot list IP addresses: boom")
+	s.api.CheckCallNames(c, "ListIPAddresses", "Close")
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress_test
+
+import (
+	stdtesting "testing"
+
+	"github.com/juju/cmd"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/ipaddress"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+// BaseIPAddressSuite is used for embedding in other suites.
+type BaseIPAddressSuite struct {
+	coretesting.FakeJujuHomeSuite
+	coretesting.BaseSuite
+
+	command cmd.Command
+	api     *StubAPI
+}
+
+func (s *BaseIPAddressSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.FakeJujuHomeSuite.SetUpTest(c)
+
+	s.api = NewStubAPI()
+
+	// All subcommand suites embedding this one should initialize
+	// s.command immediately after calling this method!
+}
+
+// RunSubCommand executes the s.command subcommand passing any args
+// and returning the stdout and stderr output as strings, as well as
+// any error.
+func (s *BaseIPAddressSuite) RunSubCommand(c *gc.C, args ...string) (string, string, error) {
+	ctx, err := coretesting.RunCommand(c, s.command, args...)
+	if ctx != nil {
+		return coretesting.Stdout(ctx), coretesting.Stderr(ctx), err
+	}
+	return "", "", err
+}
+
+// AssertRunFails is a shortcut for calling RunSubCommand with the
+// passed args then asserting the output is empty and the error is as
+// expected.
+func (s *BaseIPAddressSuite) AssertRunFails(c *gc.C, expectErr string, args ...string) {
+	stdout, stderr, err := s.RunSubCommand(c, args...)
+	c.Assert(err, gc.ErrorMatches, expectErr)
+	c.Assert(stdout, gc.Equals, "")
+	c.Assert(stderr, gc.Equals, "")
+}
+
+// AssertRunSucceeds is a shortcut for calling RunSubCommand with the
+// passed args then asserting the stderr output matches expectStderr,
+// stdout is equal to expectStdout, and the error is nil.
+func (s *BaseIPAddressSuite) AssertRunSucceeds(c *gc.C, expectStderr, expectStdout string, args ...string) {
+	stdout, stderr, err := s.RunSubCommand(c, args...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, expectStdout)
+	c.Assert(stderr, gc.Matches, expectStderr)
+}
+
+// StubAPI defines a testing stub for the IPAddressAPI interface.
+type StubAPI struct {
+	*testing.Stub
+
+	Addresses      []params.IPAddressInfo
+	Reserved       string
+	ReleaseResults []params.ErrorResult
+}
+
+var _ ipaddress.IPAddressAPI = (*StubAPI)(nil)
+
+// NewStubAPI creates a StubAPI suitable for passing to
+// ipaddress.New*Command().
+func NewStubAPI() *StubAPI {
+	return &StubAPI{
+		Stub: &testing.Stub{},
+		Addresses: []params.IPAddressInfo{{
+			Value:      "10.0.0.11",
+			SubnetTag:  "subnet-10.0.0.0/24",
+			MachineTag: "machine-0-lxc-1",
+			InstanceId: "i-host",
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			State:      "allocated",
+			Life:       params.Alive,
+		}, {
+			Value:     "10.0.0.15",
+			SubnetTag: "subnet-10.0.0.0/24",
+			State:     "reserved",
+			Life:      params.Alive,
+		}},
+		Reserved: "10.0.0.12",
+	}
+}
+
+func (sa *StubAPI) Close() error {
+	sa.MethodCall(sa, "Close")
+	return sa.NextErr()
+}
+
+func (sa *StubAPI) ListIPAddresses(subnetCIDR, machineId string) ([]params.IPAddressInfo, error) {
+	sa.MethodCall(sa, "ListIPAddresses", subnetCIDR, machineId)
+	if err := sa.NextErr(); err != nil {
+		return nil, err
+	}
+	return sa.Addresses, nil
+}
+
+func (sa *StubAPI) ReserveIPAddress(subnetCIDR, address string) (string, error) {
+	sa.MethodCall(sa, "ReserveIPAddress", subnetCIDR, address)
+	if err := sa.NextErr(); err != nil {
+		return "", err
+	}
+	return sa.Reserved, nil
+}
+
+func (sa *StubAPI) ReleaseIPAddresses(addresses []string, force bool) ([]params.ErrorResult, error) {
+	sa.MethodCall(sa, "ReleaseIPAddresses", addresses, force)
+	if err := sa.NextErr(); err != nil {
+		return nil, err
+	}
+	if sa.ReleaseResults != nil {
+		return sa.ReleaseResults, nil
+	}
+	return make([]params.ErrorResult, len(addresses)), nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// ReleaseCommand releases IP addresses with the provider and removes
+// them from Juju.
+type ReleaseCommand struct {
+	IPAddressCommandBase
+	Addresses []string
+	Force     bool
+}
+
+const releaseCommandDoc = `
+Releases the given IP addresses with the cloud provider, if they were
+allocated there, and removes them from Juju. Reserved addresses are
+simply removed.
+
+Addresses still held by a machine which is alive are refused, unless
+--force is given. Use --force to clean up allocations leaked by
+containers which no longer exist but are still recorded as alive. If
+the provider fails to release an address it is left dead, as shown by
+"juju ip-address list", and the release can be retried.`
+
+// Info is defined on the cmd.Command interface.
+func (c *ReleaseCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "release",
+		Args:    "[--force] <address> [<address> ...]",
+		Purpose: "release IP addresses and remove them from Juju",
+		Doc:     strings.TrimSpace(releaseCommandDoc),
+	}
+}
+
+// SetFlags is defined on the cmd.Command interface.
+func (c *ReleaseCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.IPAddressCommandBase.SetFlags(f)
+	f.BoolVar(&c.Force, "force", false, "release addresses even when held by live machines")
+}
+
+// Init is defined on the cmd.Command interface. It checks the
+// arguments for sanity and sets up the command to run.
+func (c *ReleaseCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("at least one IP address is required")
+	}
+	for _, arg := range args {
+		if net.ParseIP(arg) == nil {
+			return errors.Errorf("%q is not a valid IP address", arg)
+		}
+	}
+	c.Addresses = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *ReleaseCommand) Run(ctx *cmd.Context) error {
+	return c.RunWithAPI(ctx, func(api IPAddressAPI, ctx *cmd.Context) error {
+		results, err := api.ReleaseIPAddresses(c.Addresses, c.Force)
+		if err != nil {
+			return errors.Annotate(err, "cannot release IP addresses")
+		}
+		failed := 0
+		for i, result := range results {
+			if result.Error != nil {
+				ctx.Infof("cannot release IP address %q: %v", c.Addresses[i], result.Error)
+				failed++
+				continue
+			}
+			ctx.Infof("released IP address %q", c.Addresses[i])
+		}
+		if failed > 0 {
+			return cmd.ErrSilent
+		}
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/ipaddress"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ReleaseSuite struct {
+	BaseIPAddressSuite
+}
+
+var _ = gc.Suite(&ReleaseSuite{})
+
+func (s *ReleaseSuite) SetUpTest(c *gc.C) {
+	s.BaseIPAddressSuite.SetUpTest(c)
+	s.command = ipaddress.NewReleaseCommand(s.api)
+}
+
+func (s *ReleaseSuite) TestInitErrors(c *gc.C) {
+	s.AssertRunFails(c, "at least one IP address is required")
+	s.AssertRunFails(c, `"foo" is not a valid IP address`, "10.0.0.1", "foo")
+	s.api.CheckCallNames(c)
+}
+
+func (s *ReleaseSuite) TestRun(c *gc.C) {
+	s.AssertRunSucceeds(c,
+		`released IP address "10.0.0.11"\nreleased IP address "10.0.0.15"\n`, "",
+		"10.0.0.11", "10.0.0.15",
+	)
+	s.api.CheckCallNames(c, "ReleaseIPAddresses", "Close")
+	s.api.CheckCall(c, 0, "ReleaseIPAddresses", []string{"10.0.0.11", "10.0.0.15"}, false)
+}
+
+func (s *ReleaseSuite) TestRunForce(c *gc.C) {
+	s.AssertRunSucceeds(c, `released IP address "10.0.0.11"\n`, "", "--force", "10.0.0.11")
+	s.api.CheckCall(c, 0, "ReleaseIPAddresses", []string{"10.0.0.11"}, true)
+}
+
+func (s *ReleaseSuite) TestRunPartialFailure(c *gc.C) {
+	s.api.ReleaseResults = []params.ErrorResult{
+		{Error: &params.Error{Message: `IP address "10.0.0.11" is in use by machine "0/lxc/1"`}},
+		{Error: nil},
+	}
+	ctx, err := coretesting.RunCommand(c, s.command, "10.0.0.11", "10.0.0.15")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, ""+
+		`cannot release IP address "10.0.0.11": IP address "10.0.0.11" is in use by machine "0/lxc/1"`+"\n"+
+		`released IP address "10.0.0.15"`+"\n",
+	)
+}
+
+func (s *ReleaseSuite) TestRunAPIFails(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	s.AssertRunFails(c, "cannot release IP addresses: boom", "10.0.0.11")
+	s.api.CheckCallNames(c, "ReleaseIPAddresses", "Close")
+}
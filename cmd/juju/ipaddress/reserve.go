@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// ReserveCommand reserves an IP address in a subnet, so that Juju
+// never allocates it.
+type ReserveCommand struct {
+	IPAddressCommandBase
+	SubnetCIDR string
+	Address    string
+}
+
+const reserveCommandDoc = `
+Reserves an address in the subnet with the given CIDR, so that Juju
+never allocates it to a container. This is useful for addresses used
+outside of Juju, e.g. by appliances or manually configured hosts.
+
+If no address is given, a free one is picked from the allocatable
+range of the subnet. The reserved address is printed on success.
+Use "juju ip-address release" to remove the reservation.`
+
+// Info is defined on the cmd.Command interface.
+func (c *ReserveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "reserve",
+		Args:    "<CIDR> [<address>]",
+		Purpose: "reserve an IP address in a subnet",
+		Doc:     strings.TrimSpace(reserveCommandDoc),
+	}
+}
+
+// Init is defined on the cmd.Command interface. It checks the
+// arguments for sanity and sets up the command to run.
+func (c *ReserveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("subnet CIDR is required")
+	}
+	_, ipNet, err := net.ParseCIDR(args[0])
+	if err != nil {
+		return errors.Errorf("%q is not a valid CIDR", args[0])
+	}
+	c.SubnetCIDR = ipNet.String()
+	if len(args) > 1 {
+		ip := net.ParseIP(args[1])
+		if ip == nil {
+			return errors.Errorf("%q is not a valid IP address", args[1])
+		}
+		if !ipNet.Contains(ip) {
+			return errors.Errorf("address %q is not in subnet %q", args[1], c.SubnetCIDR)
+		}
+		c.Address = ip.String()
+	}
+	if len(args) > 2 {
+		return cmd.CheckEmpty(args[2:])
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *ReserveCommand) Run(ctx *cmd.Context) error {
+	return c.RunWithAPI(ctx, func(api IPAddressAPI, ctx *cmd.Context) error {
+		value, err := api.ReserveIPAddress(c.SubnetCIDR, c.Address)
+		if err != nil {
+			return errors.Annotatef(err, "cannot reserve IP address in subnet %q", c.SubnetCIDR)
+		}
+		ctx.Infof("reserved IP address %q in subnet %q", value, c.SubnetCIDR)
+		return nil
+	})
+}
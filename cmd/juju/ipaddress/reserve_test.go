@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ipaddress_test
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/ipaddress"
+)
+
+type ReserveSuite struct {
+	BaseIPAddressSuite
+}
+
+var _ = gc.Suite(&ReserveSuite{})
+
+func (s *ReserveSuite) SetUpTest(c *gc.C) {
+	s.BaseIPAddressSuite.SetUpTest(c)
+	s.command = ipaddress.NewReserveCommand(s.api)
+}
+
+func (s *ReserveSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		expectErr string
+	}{{
+		expectErr: "subnet CIDR is required",
+	}, {
+		args:      []string{"foo"},
+		expectErr: `"foo" is not a valid CIDR`,
+	}, {
+		args:      []string{"10.0.0.0/24", "bar"},
+		expectErr: `"bar" is not a valid IP address`,
+	}, {
+		args:      []string{"10.0.0.0/24", "10.0.1.5"},
+		expectErr: `address "10.0.1.5" is not in subnet "10.0.0.0/24"`,
+	}, {
+		args:      []string{"10.0.0.0/24", "10.0.0.5", "baz"},
+		expectErr: `unrecognized args: \["baz"\]`,
+	}} {
+		c.Logf("test #%d: %v", i, test.args)
+		s.AssertRunFails(c, test.expectErr, test.args...)
+	}
+	s.api.CheckCallNames(c)
+}
+
+func (s *ReserveSuite) TestRunPicksAddress(c *gc.C) {
+	s.AssertRunSucceeds(c, `reserved IP address "10.0.0.12" in subnet "10.0.0.0/24"\n`, "", "10.0.0.0/24")
+	s.api.CheckCallNames(c, "ReserveIPAddress", "Close")
+	s.api.CheckCall(c, 0, "ReserveIPAddress", "10.0.0.0/24", "")
+}
+
+func (s *ReserveSuite) TestRunGivenAddress(c *gc.C) {
+	s.api.Reserved = "10.0.0.5"
+	s.AssertRunSucceeds(c, `reserved IP address "10.0.0.5" in subnet "10.0.0.0/24"\n`, "", "10.0.0.0/24", "10.0.0.5")
+	s.api.CheckCall(c, 0, "ReserveIPAddress", "10.0.0.0/24", "10.0.0.5")
+}
+
+func (s *ReserveSuite) TestRunAPIFails(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	s.AssertRunFails(c, `cannot reserve IP address in subnet "10.0.0.0/24": boom`, "10.0.0.0/24")
+	s.api.CheckCallNames(c, "ReserveIPAddress", "Close")
+}
@@ -0,0 +1,145 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const cordonMachineDoc = `
+Takes machines out of service for new work. Units and containers already on a
+cordoned machine are left running, but no new units or containers are placed
+on it, whether by explicit placement or by Juju choosing a machine, until it
+is uncordoned. Cordoned machines are marked as such in "juju status".
+
+Examples:
+	# Stop new units from being placed on machines 2 and 3
+	$ juju machine cordon 2 3
+
+See Also:
+    juju help machine uncordon
+    juju help machine drain
+`
+
+const uncordonMachineDoc = `
+Returns cordoned machines to service, so that new units and containers may be
+placed on them again.
+
+Examples:
+	# Allow new units to be placed on machine 2 again
+	$ juju machine uncordon 2
+
+See Also:
+    juju help machine cordon
+`
+
+// CordonMachineAPI defines the API methods used by the cordon and
+// uncordon commands.
+type CordonMachineAPI interface {
+	CordonMachines(machines ...names.MachineTag) error
+	UncordonMachines(machines ...names.MachineTag) error
+	Close() error
+}
+
+// cordonCommandBase holds the behaviour shared by the cordon and
+// uncordon commands.
+type cordonCommandBase struct {
+	envcmd.EnvCommandBase
+	api        CordonMachineAPI
+	MachineIds []string
+}
+
+func (c *cordonCommandBase) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+func (c *cordonCommandBase) getAPI() (CordonMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *cordonCommandBase) run(ctx *cmd.Context, cordon bool) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	machines := make([]names.MachineTag, len(c.MachineIds))
+	for i, id := range c.MachineIds {
+		machines[i] = names.NewMachineTag(id)
+	}
+	action := "cordoned"
+	if cordon {
+		err = client.CordonMachines(machines...)
+	} else {
+		action = "uncordoned"
+		err = client.UncordonMachines(machines...)
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("%s machine(s) %s", action, strings.Join(c.MachineIds, ", "))
+	return nil
+}
+
+// CordonCommand stops new units and containers being placed on
+// machines.
+type CordonCommand struct {
+	cordonCommandBase
+}
+
+func (c *CordonCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cordon",
+		Args:    "<machine> ...",
+		Purpose: "stop new units and containers being placed on machines",
+		Doc:     cordonMachineDoc,
+	}
+}
+
+func (c *CordonCommand) Run(ctx *cmd.Context) error {
+	return c.run(ctx, true)
+}
+
+// UncordonCommand allows new units and containers to be placed on
+// cordoned machines again.
+type UncordonCommand struct {
+	cordonCommandBase
+}
+
+func (c *UncordonCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "uncordon",
+		Args:    "<machine> ...",
+		Purpose: "return cordoned machines to service",
+		Doc:     uncordonMachineDoc,
+	}
+}
+
+func (c *UncordonCommand) Run(ctx *cmd.Context) error {
+	return c.run(ctx, false)
+}
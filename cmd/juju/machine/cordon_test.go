@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type CordonSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeCordonMachineAPI
+}
+
+var _ = gc.Suite(&CordonSuite{})
+
+func (s *CordonSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeCordonMachineAPI{}
+}
+
+func (s *CordonSuite) runCordon(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(machine.NewCordonCommand(s.fake)), args...)
+}
+
+func (s *CordonSuite) runUncordon(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(machine.NewUncordonCommand(s.fake)), args...)
+}
+
+func (s *CordonSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machines    []string
+		errorString string
+	}{
+		{
+			errorString: "no machines specified",
+		}, {
+			args:        []string{"1", "foo"},
+			errorString: `invalid machine id "foo"`,
+		}, {
+			args:     []string{"1"},
+			machines: []string{"1"},
+		}, {
+			args:     []string{"1", "2/lxc/0"},
+			machines: []string{"1", "2/lxc/0"},
+		},
+	} {
+		c.Logf("test %d", i)
+		cordonCmd := &machine.CordonCommand{}
+		err := testing.InitCommand(cordonCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(cordonCmd.MachineIds, jc.DeepEquals, test.machines)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *CordonSuite) TestCordon(c *gc.C) {
+	ctx, err := s.runCordon(c, "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.cordoned, jc.DeepEquals, []names.MachineTag{
+		names.NewMachineTag("1"), names.NewMachineTag("2"),
+	})
+	c.Assert(s.fake.uncordoned, gc.HasLen, 0)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "cordoned machine(s) 1, 2\n")
+}
+
+func (s *CordonSuite) TestUncordon(c *gc.C) {
+	ctx, err := s.runUncordon(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.uncordoned, jc.DeepEquals, []names.MachineTag{names.NewMachineTag("1")})
+	c.Assert(s.fake.cordoned, gc.HasLen, 0)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "uncordoned machine(s) 1\n")
+}
+
+func (s *CordonSuite) TestCordonError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.runCordon(c, "1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	_, err = s.runUncordon(c, "1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *CordonSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockedError")
+	_, err := s.runCordon(c, "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestBlockedError.*")
+}
+
+type fakeCordonMachineAPI struct {
+	cordoned   []names.MachineTag
+	uncordoned []names.MachineTag
+	err        error
+}
+
+func (f *fakeCordonMachineAPI) Close() error {
+	return nil
+}
+
+func (f *fakeCordonMachineAPI) CordonMachines(machines ...names.MachineTag) error {
+	f.cordoned = append(f.cordoned, machines...)
+	return f.err
+}
+
+func (f *fakeCordonMachineAPI) UncordonMachines(machines ...names.MachineTag) error {
+	f.uncordoned = append(f.uncordoned, machines...)
+	return f.err
+}
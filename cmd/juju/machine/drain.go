@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const drainMachineDoc = `
+Cordons a machine and moves its units elsewhere, so that the machine can be
+taken down for maintenance or removed. The units are handled one at a time:
+a replacement unit is added to the unit's service on a clean machine, and the
+original unit is then removed. Units of services that are being destroyed are
+removed without replacement.
+
+Draining does not wait for the replacement units to become healthy: each
+original unit is removed as soon as its replacement has been assigned a
+machine. Check the replacements with "juju status" before taking the machine
+down.
+
+Containers on the machine are not touched; they should be moved with
+"juju machine migrate-container" or removed before the machine itself is.
+The machine stays cordoned once drained; use "juju machine uncordon" to
+return it to service.
+
+Examples:
+	# Move all units off machine 3
+	$ juju machine drain 3
+
+See Also:
+    juju help machine cordon
+    juju help machine migrate-container
+`
+
+// DrainMachineAPI defines the API methods used by the drain command.
+type DrainMachineAPI interface {
+	DrainMachine(machine names.MachineTag) ([]params.DrainedUnit, error)
+	Close() error
+}
+
+// DrainCommand moves the units on a machine elsewhere.
+type DrainCommand struct {
+	envcmd.EnvCommandBase
+	api       DrainMachineAPI
+	MachineId string
+}
+
+func (c *DrainCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "drain",
+		Args:    "<machine>",
+		Purpose: "move the units on a machine elsewhere",
+		Doc:     drainMachineDoc,
+	}
+}
+
+func (c *DrainCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machine specified")
+	}
+	id, args := args[0], args[1:]
+	if !names.IsValidMachine(id) {
+		return errors.Errorf("invalid machine id %q", id)
+	}
+	c.MachineId = id
+	return cmd.CheckEmpty(args)
+}
+
+func (c *DrainCommand) getAPI() (DrainMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *DrainCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	units, err := client.DrainMachine(names.NewMachineTag(c.MachineId))
+	// Report the units moved, even if the drain failed part way.
+	for _, unit := range units {
+		if err := reportDrainedUnit(ctx, unit); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
+
+func reportDrainedUnit(ctx *cmd.Context, unit params.DrainedUnit) error {
+	unitTag, err := names.ParseUnitTag(unit.UnitTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if unit.ReplacementTag == "" {
+		ctx.Infof("removed unit %s", unitTag.Id())
+		return nil
+	}
+	replacementTag, err := names.ParseUnitTag(unit.ReplacementTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("removed unit %s, replaced by %s", unitTag.Id(), replacementTag.Id())
+	return nil
+}
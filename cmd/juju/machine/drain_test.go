@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type DrainSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeDrainMachineAPI
+}
+
+var _ = gc.Suite(&DrainSuite{})
+
+func (s *DrainSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeDrainMachineAPI{
+		units: []params.DrainedUnit{{
+			UnitTag:        "unit-mysql-0",
+			ReplacementTag: "unit-mysql-1",
+		}, {
+			UnitTag: "unit-wordpress-0",
+		}},
+	}
+}
+
+func (s *DrainSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	drain := machine.NewDrainCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(drain), args...)
+}
+
+func (s *DrainSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machine     string
+		errorString string
+	}{
+		{
+			errorString: "no machine specified",
+		}, {
+			args:        []string{"foo"},
+			errorString: `invalid machine id "foo"`,
+		}, {
+			args:        []string{"1", "2"},
+			errorString: `unrecognized args: \["2"\]`,
+		}, {
+			args:    []string{"1"},
+			machine: "1",
+		},
+	} {
+		c.Logf("test %d", i)
+		drainCmd := &machine.DrainCommand{}
+		err := testing.InitCommand(drainCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(drainCmd.MachineId, gc.Equals, test.machine)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *DrainSuite) TestDrain(c *gc.C) {
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.machine, gc.Equals, names.NewMachineTag("1"))
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"removed unit mysql/0, replaced by mysql/1\n"+
+		"removed unit wordpress/0\n",
+	)
+}
+
+func (s *DrainSuite) TestDrainError(c *gc.C) {
+	s.fake.units = s.fake.units[:1]
+	s.fake.err = errors.New("boom")
+	ctx, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "removed unit mysql/0, replaced by mysql/1\n")
+}
+
+func (s *DrainSuite) TestBlockedError(c *gc.C) {
+	s.fake.units = nil
+	s.fake.err = common.ErrOperationBlocked("TestBlockedError")
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestBlockedError.*")
+}
+
+type fakeDrainMachineAPI struct {
+	machine names.MachineTag
+	units   []params.DrainedUnit
+	err     error
+}
+
+func (f *fakeDrainMachineAPI) Close() error {
+	return nil
+}
+
+func (f *fakeDrainMachineAPI) DrainMachine(machine names.MachineTag) ([]params.DrainedUnit, error) {
+	f.machine = machine
+	return f.units, f.err
+}
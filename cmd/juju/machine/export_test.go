@@ -24,6 +24,34 @@ func NewRemoveCommand(api RemoveMachineAPI) *RemoveCommand {
 	}
 }
 
+// NewMigrateContainerCommand returns a MigrateContainerCommand with the
+// api provided as specified.
+func NewMigrateContainerCommand(api MigrateContainerAPI) *MigrateContainerCommand {
+	return &MigrateContainerCommand{
+		api: api,
+	}
+}
+
+// NewCordonCommand returns a CordonCommand with the api provided as
+// specified.
+func NewCordonCommand(api CordonMachineAPI) *CordonCommand {
+	return &CordonCommand{cordonCommandBase{api: api}}
+}
+
+// NewUncordonCommand returns an UncordonCommand with the api provided
+// as specified.
+func NewUncordonCommand(api CordonMachineAPI) *UncordonCommand {
+	return &UncordonCommand{cordonCommandBase{api: api}}
+}
+
+// NewDrainCommand returns a DrainCommand with the api provided as
+// specified.
+func NewDrainCommand(api DrainMachineAPI) *DrainCommand {
+	return &DrainCommand{
+		api: api,
+	}
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
@@ -13,7 +13,9 @@ import (
 var logger = loggo.GetLogger("juju.cmd.juju.machine")
 
 const machineCommandDoc = `
-"juju machine" provides commands to add and remove machines in the Juju environment.
+"juju machine" provides commands to add and remove machines in the Juju environment,
+to move containers between machines, and to take machines out of service for
+maintenance.
 `
 
 const machineCommandPurpose = "manage machines"
@@ -29,5 +31,9 @@ func NewSuperCommand() cmd.Command {
 	})
 	machineCmd.Register(envcmd.Wrap(&AddCommand{}))
 	machineCmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	machineCmd.Register(envcmd.Wrap(&MigrateContainerCommand{}))
+	machineCmd.Register(envcmd.Wrap(&CordonCommand{}))
+	machineCmd.Register(envcmd.Wrap(&UncordonCommand{}))
+	machineCmd.Register(envcmd.Wrap(&DrainCommand{}))
 	return machineCmd
 }
@@ -21,8 +21,12 @@ var _ = gc.Suite(&MachineCommandSuite{})
 
 var expectedCommmandNames = []string{
 	"add",
+	"cordon",
+	"drain",
 	"help",
+	"migrate-container",
 	"remove",
+	"uncordon",
 }
 
 func (s *MachineCommandSuite) TestHelp(c *gc.C) {
@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/instance"
+)
+
+const migrateContainerDoc = `
+Moves a container to another host machine: an LXC container along with the
+loop storage in its filesystem, or a KVM container along with its disks. The
+container is stopped while it is copied to the new host, where it is started
+again; the container keeps its machine id, but its addresses will change.
+Should the new host fail to start the container, it is started again on its
+original host.
+
+Only LXC and KVM containers can be migrated; LXD containers are not
+supported. Containers with storage volumes or filesystems managed by Juju
+cannot be migrated either. Progress can be followed with "juju status".
+
+Examples:
+	# Move container 0/lxc/1 to machine 3
+	$ juju machine migrate-container 0/lxc/1 --to 3
+
+	# Move KVM container 2/kvm/0 to machine 4
+	$ juju machine migrate-container 2/kvm/0 --to 4
+`
+
+// MigrateContainerCommand moves a container to another host machine.
+type MigrateContainerCommand struct {
+	envcmd.EnvCommandBase
+	api         MigrateContainerAPI
+	ContainerId string
+	TargetId    string
+}
+
+func (c *MigrateContainerCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "migrate-container",
+		Args:    "<machine> --to <host>",
+		Purpose: "move a container to another host machine",
+		Doc:     migrateContainerDoc,
+	}
+}
+
+func (c *MigrateContainerCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.TargetId, "to", "", "the machine to move the container to")
+}
+
+func (c *MigrateContainerCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no container specified")
+	}
+	id, args := args[0], args[1:]
+	if !names.IsValidMachine(id) {
+		return errors.Errorf("invalid machine id %q", id)
+	}
+	if !names.IsContainerMachine(id) {
+		return errors.Errorf("machine %q is not a container", id)
+	}
+	if ctype := containerType(id); ctype != instance.LXC && ctype != instance.KVM {
+		return errors.Errorf("cannot migrate %s container %q: only lxc and kvm containers can be migrated", ctype, id)
+	}
+	if c.TargetId == "" {
+		return errors.New("no target host specified, use --to")
+	}
+	if !names.IsValidMachine(c.TargetId) {
+		return errors.Errorf("invalid machine id %q", c.TargetId)
+	}
+	c.ContainerId = id
+	return cmd.CheckEmpty(args)
+}
+
+// containerType returns the type of the container with the given
+// machine id.
+func containerType(id string) instance.ContainerType {
+	parts := strings.Split(id, "/")
+	return instance.ContainerType(parts[len(parts)-2])
+}
+
+// MigrateContainerAPI defines the API methods used by the
+// migrate-container command.
+type MigrateContainerAPI interface {
+	MigrateContainer(container, targetHost names.MachineTag) error
+	Close() error
+}
+
+func (c *MigrateContainerCommand) getAPI() (MigrateContainerAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *MigrateContainerCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	err = client.MigrateContainer(names.NewMachineTag(c.ContainerId), names.NewMachineTag(c.TargetId))
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("migrating container %s to machine %s", c.ContainerId, c.TargetId)
+	return nil
+}
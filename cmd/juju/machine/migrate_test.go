@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type MigrateContainerSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeMigrateContainerAPI
+}
+
+var _ = gc.Suite(&MigrateContainerSuite{})
+
+func (s *MigrateContainerSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeMigrateContainerAPI{}
+}
+
+func (s *MigrateContainerSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	migrate := machine.NewMigrateContainerCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(migrate), args...)
+}
+
+func (s *MigrateContainerSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		container   string
+		target      string
+		errorString string
+	}{
+		{
+			errorString: "no container specified",
+		}, {
+			args:        []string{"0/lxc/1"},
+			errorString: "no target host specified, use --to",
+		}, {
+			args:        []string{"lxc", "--to", "1"},
+			errorString: `invalid machine id "lxc"`,
+		}, {
+			args:        []string{"0", "--to", "1"},
+			errorString: `machine "0" is not a container`,
+		}, {
+			args:        []string{"0/lxd/1", "--to", "1"},
+			errorString: `cannot migrate lxd container "0/lxd/1": only lxc and kvm containers can be migrated`,
+		}, {
+			args:        []string{"0/lxc/1", "--to", "lxc"},
+			errorString: `invalid machine id "lxc"`,
+		}, {
+			args:        []string{"0/lxc/1", "--to", "1", "2"},
+			errorString: `unrecognized args: \["2"\]`,
+		}, {
+			args:      []string{"0/lxc/1", "--to", "1"},
+			container: "0/lxc/1",
+			target:    "1",
+		}, {
+			args:      []string{"0/kvm/1", "--to", "1"},
+			container: "0/kvm/1",
+			target:    "1",
+		}, {
+			args:      []string{"--to", "2/kvm/0", "0/lxc/1"},
+			container: "0/lxc/1",
+			target:    "2/kvm/0",
+		},
+	} {
+		c.Logf("test %d", i)
+		migrateCmd := &machine.MigrateContainerCommand{}
+		err := testing.InitCommand(migrateCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(migrateCmd.ContainerId, gc.Equals, test.container)
+			c.Check(migrateCmd.TargetId, gc.Equals, test.target)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *MigrateContainerSuite) TestMigrate(c *gc.C) {
+	ctx, err := s.run(c, "0/lxc/1", "--to", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.container, gc.Equals, names.NewMachineTag("0/lxc/1"))
+	c.Assert(s.fake.target, gc.Equals, names.NewMachineTag("1"))
+	c.Assert(testing.Stderr(ctx), gc.Equals, "migrating container 0/lxc/1 to machine 1\n")
+}
+
+func (s *MigrateContainerSuite) TestMigrateError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c, "0/lxc/1", "--to", "1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *MigrateContainerSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockedError")
+	_, err := s.run(c, "0/lxc/1", "--to", "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestBlockedError.*")
+}
+
+type fakeMigrateContainerAPI struct {
+	container names.MachineTag
+	target    names.MachineTag
+	err       error
+}
+
+func (f *fakeMigrateContainerAPI) Close() error {
+	return nil
+}
+
+func (f *fakeMigrateContainerAPI) MigrateContainer(container, target names.MachineTag) error {
+	f.container = container
+	f.target = target
+	return f.err
+}
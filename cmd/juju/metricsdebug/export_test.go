@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug
+
+var Now = &now
+
+// NewMetricsCommand returns a MetricsCommand with the api provided as
+// specified.
+func NewMetricsCommand(api GetMetricsAPI) *MetricsCommand {
+	return &MetricsCommand{api: api}
+}
+
+// NewSetMeterThresholdsCommand returns a SetMeterThresholdsCommand with
+// the api provided as specified.
+func NewSetMeterThresholdsCommand(api MeterThresholdsAPI) *SetMeterThresholdsCommand {
+	c := &SetMeterThresholdsCommand{}
+	c.api = api
+	return c
+}
+
+// NewGetMeterThresholdsCommand returns a GetMeterThresholdsCommand with
+// the api provided as specified.
+func NewGetMeterThresholdsCommand(api MeterThresholdsAPI) *GetMeterThresholdsCommand {
+	c := &GetMeterThresholdsCommand{}
+	c.api = api
+	return c
+}
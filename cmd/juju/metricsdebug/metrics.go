@@ -0,0 +1,220 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package metricsdebug contains the implementation of the juju metrics
+// command, which displays the metrics collected from units, and of the
+// commands that manage the meter thresholds evaluated over them.
+package metricsdebug
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/metricsdebug"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const metricsDoc = `
+Display the metrics collected from a unit, or from all units of a service.
+
+Metrics are added by charms using the add-metric hook tool, and are
+stored by the state server until they have been sent to the metrics
+collection service and expired.
+
+The --since flag restricts the output to metrics recorded after the
+specified time, given either as an RFC3339 timestamp or as a duration
+relative to now.
+
+In addition to the default tabular output, metrics may be displayed as
+yaml, json or csv.
+
+Examples:
+
+    juju metrics mysql/0
+    juju metrics mysql --since 2h
+    juju metrics mysql --since 2015-06-01T12:00:00Z --format csv
+`
+
+// MetricsCommand displays the metrics collected from a unit or service.
+type MetricsCommand struct {
+	envcmd.EnvCommandBase
+	out   cmd.Output
+	api   GetMetricsAPI
+	tag   names.Tag
+	since time.Time
+}
+
+// GetMetricsAPI defines the API methods that the metrics command uses.
+type GetMetricsAPI interface {
+	GetMetrics(tag names.Tag, since time.Time) ([]params.MetricResult, error)
+	Close() error
+}
+
+// now returns the current time, and may be replaced for testing.
+var now = time.Now
+
+// Info implements Command.Info.
+func (c *MetricsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "metrics",
+		Args:    "<service or unit>",
+		Purpose: "display the metrics collected from a unit or service",
+		Doc:     metricsDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *MetricsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(sinceValue{&c.since}, "since", "only display metrics recorded after this time or duration")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTabular,
+		"csv":     formatCSV,
+	})
+}
+
+// Init implements Command.Init.
+func (c *MetricsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service or unit specified")
+	}
+	switch entity := args[0]; {
+	case names.IsValidUnit(entity):
+		c.tag = names.NewUnitTag(entity)
+	case names.IsValidService(entity):
+		c.tag = names.NewServiceTag(entity)
+	default:
+		return errors.Errorf("%q is not a valid service or unit name", entity)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// MetricInfo defines the serialization behaviour of a metric.
+type MetricInfo struct {
+	Unit  string `yaml:"unit" json:"unit"`
+	Key   string `yaml:"key" json:"key"`
+	Value string `yaml:"value" json:"value"`
+	Time  string `yaml:"time" json:"time"`
+}
+
+// Run implements Command.Run.
+func (c *MetricsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.GetMetrics(c.tag, c.since)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 && c.out.Name() == "tabular" {
+		fmt.Fprintf(ctx.Stderr, "no metrics collected from %s\n", names.ReadableString(c.tag))
+		return nil
+	}
+	metrics := make([]MetricInfo, len(results))
+	for i, result := range results {
+		metrics[i] = MetricInfo{
+			Unit:  result.Unit,
+			Key:   result.Key,
+			Value: result.Value,
+			Time:  result.Time.UTC().Format(time.RFC3339),
+		}
+	}
+	return c.out.Write(ctx, metrics)
+}
+
+func (c *MetricsCommand) getAPI() (GetMetricsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return metricsdebug.NewClient(root), nil
+}
+
+func formatTabular(value interface{}) ([]byte, error) {
+	metrics, ok := value.([]MetricInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", metrics, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "UNIT\tTIME\tKEY\tVALUE\n")
+	for _, m := range metrics {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Unit, m.Time, m.Key, m.Value)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+func formatCSV(value interface{}) ([]byte, error) {
+	metrics, ok := value.([]MetricInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", metrics, value)
+	}
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	w.Write([]string{"unit", "time", "key", "value"})
+	for _, m := range metrics {
+		w.Write([]string{m.Unit, m.Time, m.Key, m.Value})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// cmd.Output appends a newline to the formatted output.
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+// sinceValue is a gnuflag.Value that accepts either an RFC3339
+// timestamp, or a duration which is subtracted from the current time.
+type sinceValue struct {
+	t *time.Time
+}
+
+// Set implements gnuflag.Value.Set.
+func (v sinceValue) Set(s string) error {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return errors.Errorf("duration %q must not be negative", s)
+		}
+		*v.t = now().Add(-d)
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return errors.Errorf("expected RFC3339 timestamp or duration, got %q", s)
+	}
+	*v.t = t
+	return nil
+}
+
+// String implements gnuflag.Value.String.
+func (v sinceValue) String() string {
+	if v.t == nil || v.t.IsZero() {
+		return ""
+	}
+	return v.t.Format(time.RFC3339)
+}
@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/metricsdebug"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type metricsSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *mockGetMetricsAPI
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+var t0 = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *metricsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockGetMetricsAPI{
+		metrics: []params.MetricResult{{
+			Unit: "mysql/0", Key: "pings", Value: "5", Time: t0,
+		}, {
+			Unit: "mysql/1", Key: "queue,depth", Value: "10", Time: t0.Add(time.Minute),
+		}},
+	}
+	s.PatchValue(metricsdebug.Now, func() time.Time { return t0.Add(time.Hour) })
+}
+
+func (s *metricsSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := metricsdebug.NewMetricsCommand(s.api)
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *metricsSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service or unit specified",
+	}, {
+		args: []string{"mysql/0", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"Mysql"},
+		err:  `"Mysql" is not a valid service or unit name`,
+	}, {
+		args: []string{"mysql", "--since", "yesterday"},
+		err:  `invalid value "yesterday" for flag --since: expected RFC3339 timestamp or duration, got "yesterday"`,
+	}} {
+		c.Logf("test %d: %q", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *metricsSuite) TestMetricsTabular(c *gc.C) {
+	ctx, err := s.run(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.tag, gc.Equals, names.NewServiceTag("mysql"))
+	c.Assert(s.api.since.IsZero(), jc.IsTrue)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+UNIT     TIME                  KEY          VALUE
+mysql/0  2015-06-01T12:00:00Z  pings        5
+mysql/1  2015-06-01T12:01:00Z  queue,depth  10
+
+`[1:])
+}
+
+func (s *metricsSuite) TestMetricsCSV(c *gc.C) {
+	ctx, err := s.run(c, "mysql", "--format", "csv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+unit,time,key,value
+mysql/0,2015-06-01T12:00:00Z,pings,5
+mysql/1,2015-06-01T12:01:00Z,"queue,depth",10
+`[1:])
+}
+
+func (s *metricsSuite) TestMetricsJSON(c *gc.C) {
+	s.api.metrics = s.api.metrics[:1]
+	ctx, err := s.run(c, "mysql/0", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.tag, gc.Equals, names.NewUnitTag("mysql/0"))
+	c.Assert(coretesting.Stdout(ctx), gc.Equals,
+		`[{"unit":"mysql/0","key":"pings","value":"5","time":"2015-06-01T12:00:00Z"}]`+"\n",
+	)
+}
+
+func (s *metricsSuite) TestMetricsSince(c *gc.C) {
+	_, err := s.run(c, "mysql", "--since", "30m")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.since, gc.Equals, t0.Add(30*time.Minute))
+
+	_, err = s.run(c, "mysql", "--since", "2015-06-01T12:00:30Z")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.since.Equal(t0.Add(30*time.Second)), jc.IsTrue)
+}
+
+func (s *metricsSuite) TestNoMetrics(c *gc.C) {
+	s.api.metrics = nil
+	ctx, err := s.run(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "no metrics collected from service mysql\n")
+}
+
+func (s *metricsSuite) TestMetricsError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.run(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockGetMetricsAPI struct {
+	tag     names.Tag
+	since   time.Time
+	metrics []params.MetricResult
+	err     error
+}
+
+func (m *mockGetMetricsAPI) GetMetrics(tag names.Tag, since time.Time) ([]params.MetricResult, error) {
+	m.tag, m.since = tag, since
+	return m.metrics, m.err
+}
+
+func (m *mockGetMetricsAPI) Close() error {
+	return nil
+}
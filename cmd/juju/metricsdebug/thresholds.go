@@ -0,0 +1,176 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/metricsdebug"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const setMeterThresholdsDoc = `
+Set the meter thresholds of a service, replacing any existing thresholds.
+
+Each threshold is a condition on the value of a metric recorded by the
+service's units, of the form
+
+    <metric> <operator> <value> [for <duration>] -> <status>
+
+where <operator> is one of >, >=, <, <=, == or !=, and <status> is AMBER
+or RED. When a unit's most recent values of the metric have satisfied the
+condition for at least the specified duration, the state server sets the
+unit's meter status to the threshold's status, causing the charm's
+meter-status-changed hook to run. If a unit breaches several thresholds,
+the most severe status is used. When the unit no longer breaches any
+threshold, its meter status is set back to GREEN. A value recorded more
+than 15 minutes ago is considered stale and never breaches a threshold.
+
+Specifying no thresholds removes the service's thresholds.
+
+Examples:
+
+    juju set-meter-thresholds rabbitmq-server "queue-depth > 1000 for 5m -> AMBER"
+    juju set-meter-thresholds rabbitmq-server \
+        "queue-depth > 1000 for 5m -> AMBER" "queue-depth > 5000 -> RED"
+    juju set-meter-thresholds rabbitmq-server
+
+See Also:
+    juju help get-meter-thresholds
+`
+
+// MeterThresholdsAPI defines the API methods that the meter
+// thresholds commands use.
+type MeterThresholdsAPI interface {
+	MeterThresholds(service string) ([]string, error)
+	SetMeterThresholds(service string, thresholds []string) error
+	Close() error
+}
+
+// meterThresholdsCommandBase is the base type for the meter
+// thresholds commands.
+type meterThresholdsCommandBase struct {
+	envcmd.EnvCommandBase
+	api         MeterThresholdsAPI
+	serviceName string
+}
+
+func (c *meterThresholdsCommandBase) initService(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no service specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return nil, errors.Errorf("invalid service name %q", args[0])
+	}
+	c.serviceName = args[0]
+	return args[1:], nil
+}
+
+func (c *meterThresholdsCommandBase) getAPI() (MeterThresholdsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return metricsdebug.NewClient(root), nil
+}
+
+// SetMeterThresholdsCommand sets the meter thresholds of a service.
+type SetMeterThresholdsCommand struct {
+	meterThresholdsCommandBase
+	thresholds []string
+}
+
+// Info implements Command.Info.
+func (c *SetMeterThresholdsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-meter-thresholds",
+		Args:    "<service> [<threshold> ...]",
+		Purpose: "set the thresholds used to set the meter status of a service's units",
+		Doc:     setMeterThresholdsDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *SetMeterThresholdsCommand) Init(args []string) error {
+	thresholds, err := c.initService(args)
+	if err != nil {
+		return err
+	}
+	c.thresholds = thresholds
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *SetMeterThresholdsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return api.SetMeterThresholds(c.serviceName, c.thresholds)
+}
+
+const getMeterThresholdsDoc = `
+Display the meter thresholds of a service, as set with the
+set-meter-thresholds command.
+
+See Also:
+    juju help set-meter-thresholds
+`
+
+// GetMeterThresholdsCommand displays the meter thresholds of a service.
+type GetMeterThresholdsCommand struct {
+	meterThresholdsCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *GetMeterThresholdsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "get-meter-thresholds",
+		Args:    "<service>",
+		Purpose: "display the meter thresholds of a service",
+		Doc:     getMeterThresholdsDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *GetMeterThresholdsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *GetMeterThresholdsCommand) Init(args []string) error {
+	args, err := c.initService(args)
+	if err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *GetMeterThresholdsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	thresholds, err := api.MeterThresholds(c.serviceName)
+	if err != nil {
+		return err
+	}
+	if len(thresholds) == 0 && c.out.Name() == "smart" {
+		fmt.Fprintf(ctx.Stderr, "no meter thresholds set for service %s\n", c.serviceName)
+		return nil
+	}
+	return c.out.Write(ctx, thresholds)
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsdebug_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/metricsdebug"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type thresholdsSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *mockMeterThresholdsAPI
+}
+
+var _ = gc.Suite(&thresholdsSuite{})
+
+func (s *thresholdsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockMeterThresholdsAPI{
+		thresholds: []string{
+			"queue-depth > 1000 for 5m0s -> AMBER",
+			"queue-depth > 5000 -> RED",
+		},
+	}
+}
+
+func (s *thresholdsSuite) runSet(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := metricsdebug.NewSetMeterThresholdsCommand(s.api)
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *thresholdsSuite) runGet(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := metricsdebug.NewGetMeterThresholdsCommand(s.api)
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *thresholdsSuite) TestSetInit(c *gc.C) {
+	_, err := s.runSet(c)
+	c.Assert(err, gc.ErrorMatches, "no service specified")
+	_, err = s.runSet(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `invalid service name "mysql/0"`)
+}
+
+func (s *thresholdsSuite) TestSetMeterThresholds(c *gc.C) {
+	_, err := s.runSet(c, "rabbitmq-server", "queue-depth > 1000 for 5m -> AMBER", "queue-depth > 5000 -> RED")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "rabbitmq-server")
+	c.Assert(s.api.thresholds, jc.DeepEquals, []string{
+		"queue-depth > 1000 for 5m -> AMBER",
+		"queue-depth > 5000 -> RED",
+	})
+}
+
+func (s *thresholdsSuite) TestSetMeterThresholdsNone(c *gc.C) {
+	_, err := s.runSet(c, "rabbitmq-server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "rabbitmq-server")
+	c.Assert(s.api.thresholds, gc.HasLen, 0)
+}
+
+func (s *thresholdsSuite) TestSetMeterThresholdsError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.runSet(c, "rabbitmq-server", "queue-depth > lots -> AMBER")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *thresholdsSuite) TestGetInit(c *gc.C) {
+	_, err := s.runGet(c)
+	c.Assert(err, gc.ErrorMatches, "no service specified")
+	_, err = s.runGet(c, "rabbitmq-server", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *thresholdsSuite) TestGetMeterThresholds(c *gc.C) {
+	ctx, err := s.runGet(c, "rabbitmq-server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "rabbitmq-server")
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+queue-depth > 1000 for 5m0s -> AMBER
+queue-depth > 5000 -> RED
+`[1:])
+}
+
+func (s *thresholdsSuite) TestGetMeterThresholdsJSON(c *gc.C) {
+	ctx, err := s.runGet(c, "rabbitmq-server", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals,
+		`["queue-depth > 1000 for 5m0s -> AMBER","queue-depth > 5000 -> RED"]`+"\n",
+	)
+}
+
+func (s *thresholdsSuite) TestGetMeterThresholdsNone(c *gc.C) {
+	s.api.thresholds = nil
+	ctx, err := s.runGet(c, "rabbitmq-server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "no meter thresholds set for service rabbitmq-server\n")
+}
+
+type mockMeterThresholdsAPI struct {
+	service    string
+	thresholds []string
+	err        error
+}
+
+func (m *mockMeterThresholdsAPI) MeterThresholds(service string) ([]string, error) {
+	m.service = service
+	return m.thresholds, m.err
+}
+
+func (m *mockMeterThresholdsAPI) SetMeterThresholds(service string, thresholds []string) error {
+	m.service, m.thresholds = service, thresholds
+	return m.err
+}
+
+func (m *mockMeterThresholdsAPI) Close() error {
+	return nil
+}
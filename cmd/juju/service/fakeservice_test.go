@@ -19,6 +19,7 @@ type fakeServiceAPI struct {
 	servName  string
 	charmName string
 	config    string
+	profile   string
 	err       error
 }
 
@@ -54,9 +55,10 @@ func (f *fakeServiceAPI) ServiceGet(service string) (*params.ServiceGetResults,
 	}
 
 	return &params.ServiceGetResults{
-		Service: f.servName,
-		Charm:   f.charmName,
-		Config:  configInfo,
+		Service:       f.servName,
+		Charm:         f.charmName,
+		ConfigProfile: f.profile,
+		Config:        configInfo,
 	}, nil
 }
 
@@ -76,6 +78,19 @@ func (f *fakeServiceAPI) ServiceSet(service string, options map[string]string) e
 	return nil
 }
 
+func (f *fakeServiceAPI) ServiceSetConfigProfile(service, profile string) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if service != f.servName {
+		return errors.NotFoundf("service %q", service)
+	}
+
+	f.profile = profile
+	return nil
+}
+
 func (f *fakeServiceAPI) ServiceUnset(service string, options []string) error {
 	if f.err != nil {
 		return f.err
@@ -22,9 +22,11 @@ type GetCommand struct {
 }
 
 const getDoc = `
-The command output includes the service and charm names, a detailed list of all config
-settings for <service>, including the setting name, whether it uses the default value
-or not ("default: true"), description (if set), type, and current value. Example:
+The command output includes the service and charm names, the service's config
+profile (if any), a detailed list of all config settings for <service>, including
+the setting name, whether it uses the default value ("default: true") or a value
+from the config profile ("profile: true"), description (if set), type, and current
+value. Example:
 
 $ juju service get wordpress
 
@@ -44,6 +46,9 @@ settings:
 NOTE: In the example above the descriptions and most other settings were omitted for
 brevity. The "engine" setting was left at its default value ("nginx"), while the
 "tuning" setting was set to "optimized" (the default value is "single").
+
+See Also:
+   juju help config-profile
 `
 
 func (c *GetCommand) Info() *cmd.Info {
@@ -104,5 +109,8 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 		"charm":    results.Charm,
 		"settings": results.Config,
 	}
+	if results.ConfigProfile != "" {
+		resultsMap["config-profile"] = results.ConfigProfile
+	}
 	return c.out.Write(ctx, resultsMap)
 }
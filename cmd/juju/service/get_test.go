@@ -80,22 +80,43 @@ func (s *GetSuite) TestGetCommandInit(c *gc.C) {
 
 func (s *GetSuite) TestGetConfig(c *gc.C) {
 	for _, t := range getTests {
-		ctx := coretesting.Context(c)
-		code := cmd.Main(envcmd.Wrap(service.NewGetCommand(s.fake)), ctx, []string{t.service})
-		c.Check(code, gc.Equals, 0)
-		c.Assert(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
-		// round trip via goyaml to avoid being sucked into a quagmire of
-		// map[interface{}]interface{} vs map[string]interface{}. This is
-		// also required if we add json support to this command.
-		buf, err := goyaml.Marshal(t.expected)
-		c.Assert(err, jc.ErrorIsNil)
-		expected := make(map[string]interface{})
-		err = goyaml.Unmarshal(buf, &expected)
-		c.Assert(err, jc.ErrorIsNil)
-
-		actual := make(map[string]interface{})
-		err = goyaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &actual)
-		c.Assert(err, jc.ErrorIsNil)
-		c.Assert(actual, gc.DeepEquals, expected)
+		s.assertGetConfig(c, t.service, t.expected)
 	}
 }
+
+func (s *GetSuite) TestGetConfigProfile(c *gc.C) {
+	s.fake.profile = "production"
+	s.fake.values = map[string]interface{}{"title": "Nearly There"}
+	s.assertGetConfig(c, "dummy-service", map[string]interface{}{
+		"service":        "dummy-service",
+		"charm":          "dummy",
+		"config-profile": "production",
+		"settings": map[string]interface{}{
+			"title": map[string]interface{}{
+				"description": "Specifies title",
+				"type":        "string",
+				"value":       "Nearly There",
+			},
+		},
+	})
+}
+
+func (s *GetSuite) assertGetConfig(c *gc.C, serviceName string, expected map[string]interface{}) {
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(service.NewGetCommand(s.fake)), ctx, []string{serviceName})
+	c.Check(code, gc.Equals, 0)
+	c.Assert(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
+	// round trip via goyaml to avoid being sucked into a quagmire of
+	// map[interface{}]interface{} vs map[string]interface{}. This is
+	// also required if we add json support to this command.
+	buf, err := goyaml.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	expectedMap := make(map[string]interface{})
+	err = goyaml.Unmarshal(buf, &expectedMap)
+	c.Assert(err, jc.ErrorIsNil)
+
+	actual := make(map[string]interface{})
+	err = goyaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &actual)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actual, gc.DeepEquals, expectedMap)
+}
@@ -27,6 +27,12 @@ type SetCommand struct {
 	SettingsStrings map[string]string
 	SettingsYAML    cmd.FileVar
 	api             SetServiceAPI
+
+	// ConfigProfile holds the value of the --profile flag, which is
+	// only acted upon if setConfigProfile is true; an empty value
+	// causes the service to stop using a config profile.
+	ConfigProfile    string
+	setConfigProfile bool
 }
 
 const setDoc = `
@@ -40,6 +46,17 @@ size of this value is 5M.
 
 Option values may be any UTF-8 encoded string. UTF-8 is accepted on the command
 line and in configuration files.
+
+The --profile flag sets the environment config profile used by the service.
+Settings from the profile apply to the charm options that are not set
+explicitly for the service, in place of the charm defaults. Specifying an
+empty profile name stops the service using a config profile:
+
+    juju set wordpress --profile production
+    juju set wordpress --profile ""
+
+See Also:
+    juju help config-profile
 `
 
 const maxValueSize = 5242880
@@ -47,7 +64,7 @@ const maxValueSize = 5242880
 func (c *SetCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "set",
-		Args:    "<service> name=value ...",
+		Args:    "<service> [--profile <profile>] name=value ...",
 		Purpose: "set service config options",
 		Doc:     setDoc,
 	}
@@ -55,6 +72,26 @@ func (c *SetCommand) Info() *cmd.Info {
 
 func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
+	f.Var(configProfileValue{c}, "profile", "environment config profile providing the service's default settings")
+}
+
+// configProfileValue implements gnuflag.Value for the --profile flag,
+// recording that the flag was specified so that an empty profile name
+// can be distinguished from no profile name.
+type configProfileValue struct {
+	c *SetCommand
+}
+
+// String implements gnuflag.Value.String.
+func (v configProfileValue) String() string {
+	return v.c.ConfigProfile
+}
+
+// Set implements gnuflag.Value.Set.
+func (v configProfileValue) Set(s string) error {
+	v.c.ConfigProfile = s
+	v.c.setConfigProfile = true
+	return nil
 }
 
 func (c *SetCommand) Init(args []string) error {
@@ -80,6 +117,7 @@ type SetServiceAPI interface {
 	ServiceSetYAML(service string, yaml string) error
 	ServiceGet(service string) (*params.ServiceGetResults, error)
 	ServiceSet(service string, options map[string]string) error
+	ServiceSetConfigProfile(service, profile string) error
 }
 
 func (c *SetCommand) getAPI() (SetServiceAPI, error) {
@@ -97,6 +135,16 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.setConfigProfile {
+		err := api.ServiceSetConfigProfile(c.ServiceName, c.ConfigProfile)
+		if params.IsCodeNotImplemented(err) {
+			return errors.New("cannot set config profile: not supported by the API server")
+		}
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+
 	if c.SettingsYAML.Path != "" {
 		b, err := c.SettingsYAML.Read(ctx)
 		if err != nil {
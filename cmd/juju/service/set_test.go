@@ -141,6 +141,33 @@ func (s *SetSuite) TestSetConfig(c *gc.C) {
 	c.Check(s.fake.config, gc.Equals, yamlConfigValue)
 }
 
+func (s *SetSuite) TestSetConfigProfile(c *gc.C) {
+	s.assertSetSuccess(c, s.dir, []string{
+		"--profile", "production",
+		"username=hello",
+	}, map[string]interface{}{
+		"username": "hello",
+	})
+	c.Assert(s.fake.profile, gc.Equals, "production")
+
+	s.assertSetSuccess(c, s.dir, []string{
+		"--profile", "",
+	}, map[string]interface{}{
+		"username": "hello",
+	})
+	c.Assert(s.fake.profile, gc.Equals, "")
+}
+
+func (s *SetSuite) TestSetWithoutConfigProfileLeavesProfile(c *gc.C) {
+	s.fake.profile = "production"
+	s.assertSetSuccess(c, s.dir, []string{
+		"username=hello",
+	}, map[string]interface{}{
+		"username": "hello",
+	})
+	c.Assert(s.fake.profile, gc.Equals, "production")
+}
+
 func (s *SetSuite) TestBlockSetConfig(c *gc.C) {
 	// Block operation
 	s.fake.err = common.ErrOperationBlocked("TestBlockSetConfig")
@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// DiscoverCommand calls the API to find subnets known to the provider
+// but not to Juju, and optionally imports them.
+type DiscoverCommand struct {
+	SpaceCommandBase
+	Import bool
+	out    cmd.Output
+}
+
+const discoverCommandDoc = `
+Fetches all subnets known to the cloud provider which Juju does not
+know about yet, and displays them grouped into the spaces the provider
+proposes for them. Subnets without a proposed space are shown under
+"default".
+
+Subnet discovery is supported by the EC2 and MAAS providers. On EC2,
+the space of each subnet is proposed by its "juju-space" tag. On MAAS
+1.9 or later, each subnet is proposed in the MAAS space holding it.
+
+With --import, the displayed subnets are added to the environment,
+creating any missing spaces, all in a single step. Nothing is imported
+without --import, unless the environment's "auto-import-subnets"
+setting is true, in which case the state server also imports subnets
+created in the provider periodically.`
+
+// Info is defined on the cmd.Command interface.
+func (c *DiscoverCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "discover",
+		Args:    "[--import] [--format yaml|json] [--output <path>]",
+		Purpose: "propose (and import) spaces for subnets known to the provider",
+		Doc:     strings.TrimSpace(discoverCommandDoc),
+	}
+}
+
+// SetFlags is defined on the cmd.Command interface.
+func (c *DiscoverCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SpaceCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+
+	f.BoolVar(&c.Import, "import", false, "import the discovered subnets and spaces")
+}
+
+// Init is defined on the cmd.Command interface. It checks the
+// arguments for sanity and sets up the command to run.
+func (c *DiscoverCommand) Init(args []string) error {
+	// No arguments are accepted, just flags.
+	return errors.Trace(cmd.CheckEmpty(args))
+}
+
+// Run implements Command.Run.
+func (c *DiscoverCommand) Run(ctx *cmd.Context) error {
+	return c.RunWithAPI(ctx, func(api SpaceAPI, ctx *cmd.Context) error {
+		spaces, err := api.DiscoverSpaces(c.Import)
+		if err != nil {
+			return errors.Annotate(err, "cannot discover spaces")
+		}
+		if len(spaces) == 0 {
+			ctx.Infof("no new subnets discovered")
+			return nil
+		}
+
+		result := formattedList{
+			Spaces: make(map[string]map[string]formattedSubnet),
+		}
+		count := 0
+		for _, space := range spaces {
+			name := space.Name
+			if name == "" {
+				name = "default"
+			}
+			if result.Spaces[name] == nil {
+				result.Spaces[name] = make(map[string]formattedSubnet)
+			}
+			for _, subnet := range space.Subnets {
+				subResult := formattedSubnet{
+					Type:       typeUnknown,
+					ProviderId: subnet.ProviderId,
+					Zones:      subnet.Zones,
+				}
+				if ip, _, err := net.ParseCIDR(subnet.CIDR); err == nil {
+					if ip.To4() != nil {
+						subResult.Type = typeIPv4
+					} else {
+						subResult.Type = typeIPv6
+					}
+				}
+				result.Spaces[name][subnet.CIDR] = subResult
+				count++
+			}
+		}
+		if err := c.out.Write(ctx, result); err != nil {
+			return errors.Trace(err)
+		}
+		if c.Import {
+			ctx.Infof("imported %d subnet(s) into %d space(s)", count, len(result.Spaces))
+		} else {
+			ctx.Infof("use --import to add the subnets above")
+		}
+		return nil
+	})
+}
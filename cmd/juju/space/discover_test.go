@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/space"
+)
+
+type DiscoverSuite struct {
+	BaseSpaceSuite
+}
+
+var _ = gc.Suite(&DiscoverSuite{})
+
+func (s *DiscoverSuite) SetUpTest(c *gc.C) {
+	s.BaseSpaceSuite.SetUpTest(c)
+	s.command = space.NewDiscoverCommand(s.api)
+	c.Assert(s.command, gc.NotNil)
+	s.api.Discovered = []params.Space{{
+		Name: "",
+		Subnets: []params.Subnet{{
+			CIDR:       "2001:db8::/32",
+			ProviderId: "subnet-v6",
+		}},
+	}, {
+		Name: "internal",
+		Subnets: []params.Subnet{{
+			CIDR:       "10.20.0.0/24",
+			ProviderId: "subnet-db",
+			SpaceTag:   "space-internal",
+			Zones:      []string{"zone1"},
+		}},
+	}}
+}
+
+const expectedDiscoverOutput = `
+spaces:
+  default:
+    2001:db8::/32:
+      type: ipv6
+      provider-id: subnet-v6
+      zones: []
+  internal:
+    10.20.0.0/24:
+      type: ipv4
+      provider-id: subnet-db
+      zones:
+      - zone1
+`[1:]
+
+func (s *DiscoverSuite) TestInitUnrecognizedArgs(c *gc.C) {
+	s.AssertRunFails(c, `unrecognized args: \["foo"\]`, "foo")
+	s.api.CheckCallNames(c)
+}
+
+func (s *DiscoverSuite) TestRunProposesOnly(c *gc.C) {
+	s.AssertRunSucceeds(c,
+		"use --import to add the subnets above\n",
+		expectedDiscoverOutput,
+	)
+
+	s.api.CheckCallNames(c, "DiscoverSpaces", "Close")
+	s.api.CheckCall(c, 0, "DiscoverSpaces", false)
+}
+
+func (s *DiscoverSuite) TestRunImports(c *gc.C) {
+	s.AssertRunSucceeds(c,
+		`imported 2 subnet\(s\) into 2 space\(s\)\n`,
+		expectedDiscoverOutput,
+		"--import",
+	)
+
+	s.api.CheckCallNames(c, "DiscoverSpaces", "Close")
+	s.api.CheckCall(c, 0, "DiscoverSpaces", true)
+}
+
+func (s *DiscoverSuite) TestRunNothingDiscovered(c *gc.C) {
+	s.api.Discovered = nil
+	s.AssertRunSucceeds(c, "no new subnets discovered\n", "", "--import")
+
+	s.api.CheckCallNames(c, "DiscoverSpaces", "Close")
+}
+
+func (s *DiscoverSuite) TestRunWhenSpacesAPIFails(c *gc.C) {
+	s.api.SetErrors(errors.New("API error"))
+
+	s.AssertRunFails(c, "cannot discover spaces: API error")
+
+	s.api.CheckCallNames(c, "DiscoverSpaces", "Close")
+}
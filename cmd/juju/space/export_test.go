@@ -33,6 +33,12 @@ func NewListCommand(api SpaceAPI) *ListCommand {
 	return listCmd
 }
 
+func NewDiscoverCommand(api SpaceAPI) *DiscoverCommand {
+	discoverCmd := &DiscoverCommand{}
+	discoverCmd.api = api
+	return discoverCmd
+}
+
 func ListFormat(cmd *ListCommand) string {
 	return cmd.out.Name()
 }
@@ -149,8 +149,9 @@ func (s *BaseSpaceSuite) Strings(values ...string) []string {
 type StubAPI struct {
 	*testing.Stub
 
-	Spaces  []params.Space
-	Subnets []params.Subnet
+	Spaces     []params.Space
+	Subnets    []params.Subnet
+	Discovered []params.Space
 }
 
 var _ space.SpaceAPI = (*StubAPI)(nil)
@@ -237,3 +238,11 @@ func (sa *StubAPI) RenameSpace(name, newName string) error {
 	sa.MethodCall(sa, "RenameSpace", name, newName)
 	return sa.NextErr()
 }
+
+func (sa *StubAPI) DiscoverSpaces(importSubnets bool) ([]params.Space, error) {
+	sa.MethodCall(sa, "DiscoverSpaces", importSubnets)
+	if err := sa.NextErr(); err != nil {
+		return nil, err
+	}
+	return sa.Discovered, nil
+}
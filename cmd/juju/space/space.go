@@ -50,6 +50,11 @@ type SpaceAPI interface {
 
 	// RenameSpace changes the name of the space.
 	RenameSpace(name, newName string) error
+
+	// DiscoverSpaces returns the subnets known to the provider but
+	// not to Juju, grouped by their proposed spaces, importing them
+	// when importSubnets is true.
+	DiscoverSpaces(importSubnets bool) ([]params.Space, error)
 }
 
 var logger = loggo.GetLogger("juju.cmd.juju.space")
@@ -103,6 +108,7 @@ func NewSuperCommand() cmd.Command {
 	})
 	spaceCmd.Register(envcmd.Wrap(&CreateCommand{}))
 	spaceCmd.Register(envcmd.Wrap(&ListCommand{}))
+	spaceCmd.Register(envcmd.Wrap(&DiscoverCommand{}))
 	if featureflag.Enabled(feature.PostNetCLIMVP) {
 		// The following commands are not part of the MVP.
 		spaceCmd.Register(envcmd.Wrap(&RemoveCommand{}))
@@ -199,6 +205,10 @@ func (m *mvpAPIShim) ListSpaces() ([]params.Space, error) {
 	return m.facade.ListSpaces()
 }
 
+func (m *mvpAPIShim) DiscoverSpaces(importSubnets bool) ([]params.Space, error) {
+	return m.facade.DiscoverSpaces(importSubnets)
+}
+
 // NewAPI returns a SpaceAPI for the root api endpoint that the
 // environment command returns.
 func (c *SpaceCommandBase) NewAPI() (SpaceAPI, error) {
@@ -15,6 +15,7 @@ import (
 var mvpSubcommandNames = []string{
 	"create",
 	"list",
+	"discover",
 	"help",
 }
 
@@ -16,6 +16,19 @@ type formattedStatus struct {
 	Machines    map[string]machineStatus `json:"machines"`
 	Services    map[string]serviceStatus `json:"services"`
 	Networks    map[string]networkStatus `json:"networks,omitempty" yaml:",omitempty"`
+
+	StagedUpgrade *stagedUpgradeStatus `json:"staged-upgrade,omitempty" yaml:"staged-upgrade,omitempty"`
+}
+
+type stagedUpgradeStatus struct {
+	Status          string   `json:"status" yaml:"status"`
+	Message         string   `json:"message,omitempty" yaml:"message,omitempty"`
+	PreviousVersion string   `json:"previous-version" yaml:"previous-version"`
+	Version         string   `json:"version" yaml:"version"`
+	Canaries        []string `json:"canaries,omitempty" yaml:"canaries,omitempty"`
+	BatchSize       int      `json:"batch-size" yaml:"batch-size"`
+	Upgrading       []string `json:"upgrading,omitempty" yaml:"upgrading,omitempty"`
+	Waiting         []string `json:"waiting,omitempty" yaml:"waiting,omitempty"`
 }
 
 type errorStatus struct {
@@ -36,6 +49,8 @@ type machineStatus struct {
 	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+	HAZone         string                   `json:"state-server-zone,omitempty" yaml:"state-server-zone,omitempty"`
+	Cordoned       bool                     `json:"cordoned,omitempty" yaml:"cordoned,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -72,10 +87,21 @@ type serviceStatus struct {
 	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
 	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
 	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
+
+	RollingUpgrade *rollingUpgradeStatus `json:"rolling-upgrade,omitempty" yaml:"rolling-upgrade,omitempty"`
 }
 
 type serviceStatusNoMarshal serviceStatus
 
+type rollingUpgradeStatus struct {
+	Status        string   `json:"status" yaml:"status"`
+	Message       string   `json:"message,omitempty" yaml:"message,omitempty"`
+	PreviousCharm string   `json:"previous-charm" yaml:"previous-charm"`
+	BatchSize     int      `json:"batch-size" yaml:"batch-size"`
+	Upgrading     []string `json:"upgrading,omitempty" yaml:"upgrading,omitempty"`
+	Waiting       []string `json:"waiting,omitempty" yaml:"waiting,omitempty"`
+}
+
 func (s serviceStatus) MarshalJSON() ([]byte, error) {
 	if s.Err != nil {
 		return json.Marshal(errorStatus{s.Err.Error()})
@@ -115,6 +141,8 @@ type unitStatus struct {
 	OpenedPorts   []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
 	PublicAddress string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
 	Subordinates  map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	Leader        bool                  `json:"leader,omitempty" yaml:"leader,omitempty"`
+	LeaderExpiry  string                `json:"leader-expiry,omitempty" yaml:"leader-expiry,omitempty"`
 }
 
 type statusInfoContents struct {
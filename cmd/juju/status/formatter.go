@@ -15,15 +15,17 @@ type statusFormatter struct {
 	status        *params.FullStatus
 	relations     map[int]params.RelationStatus
 	isoTime       bool
+	leadersOnly   bool
 	compatVersion int
 }
 
-func newStatusFormatter(status *params.FullStatus, compatVersion int, isoTime bool) *statusFormatter {
+func newStatusFormatter(status *params.FullStatus, compatVersion int, isoTime, leadersOnly bool) *statusFormatter {
 	sf := statusFormatter{
 		status:        status,
 		relations:     make(map[int]params.RelationStatus),
 		compatVersion: compatVersion,
 		isoTime:       isoTime,
+		leadersOnly:   leadersOnly,
 	}
 	for _, relation := range status.Relations {
 		sf.relations[relation.Id] = relation
@@ -52,6 +54,18 @@ func (sf *statusFormatter) format() formattedStatus {
 		}
 		out.Networks[k] = sf.formatNetwork(n)
 	}
+	if upgrade := sf.status.StagedUpgrade; upgrade != nil {
+		out.StagedUpgrade = &stagedUpgradeStatus{
+			Status:          upgrade.Status,
+			Message:         upgrade.Message,
+			PreviousVersion: upgrade.PreviousVersion,
+			Version:         upgrade.Version,
+			Canaries:        upgrade.CanaryMachines,
+			BatchSize:       upgrade.BatchSize,
+			Upgrading:       upgrade.Batch,
+			Waiting:         upgrade.Held,
+		}
+	}
 	return out
 }
 
@@ -74,6 +88,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 			Id:             machine.Id,
 			Containers:     make(map[string]machineStatus),
 			Hardware:       machine.Hardware,
+			Cordoned:       machine.Cordoned,
 		}
 	} else {
 		// New server
@@ -91,6 +106,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 			Id:             machine.Id,
 			Containers:     make(map[string]machineStatus),
 			Hardware:       machine.Hardware,
+			Cordoned:       machine.Cordoned,
 		}
 	}
 
@@ -101,6 +117,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 	for _, job := range machine.Jobs {
 		if job == multiwatcher.JobManageEnviron {
 			out.HAStatus = makeHAStatus(machine.HasVote, machine.WantsVote)
+			out.HAZone = machine.AvailabilityZone
 			break
 		}
 	}
@@ -120,6 +137,16 @@ func (sf *statusFormatter) formatService(name string, service params.ServiceStat
 		Units:         make(map[string]unitStatus),
 		StatusInfo:    sf.getServiceStatusInfo(service),
 	}
+	if upgrade := service.RollingUpgrade; upgrade != nil {
+		out.RollingUpgrade = &rollingUpgradeStatus{
+			Status:        upgrade.Status,
+			Message:       upgrade.Message,
+			PreviousCharm: upgrade.PreviousCharm,
+			BatchSize:     upgrade.BatchSize,
+			Upgrading:     upgrade.Batch,
+			Waiting:       upgrade.Held,
+		}
+	}
 	if len(service.Networks.Enabled) > 0 {
 		out.Networks["enabled"] = service.Networks.Enabled
 	}
@@ -127,6 +154,9 @@ func (sf *statusFormatter) formatService(name string, service params.ServiceStat
 		out.Networks["disabled"] = service.Networks.Disabled
 	}
 	for k, m := range service.Units {
+		if sf.leadersOnly && !leaderWithin(m) {
+			continue
+		}
 		out.Units[k] = sf.formatUnit(unitFormatInfo{
 			unit:          m,
 			unitName:      k,
@@ -169,6 +199,10 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		PublicAddress:      info.unit.PublicAddress,
 		Charm:              info.unit.Charm,
 		Subordinates:       make(map[string]unitStatus),
+		Leader:             info.unit.Leader,
+	}
+	if info.unit.LeaderExpiry != nil {
+		out.LeaderExpiry = common.FormatTime(info.unit.LeaderExpiry, sf.isoTime)
 	}
 
 	if ms, ok := info.meterStatuses[info.unitName]; ok {
@@ -188,6 +222,9 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 	}
 
 	for k, m := range info.unit.Subordinates {
+		if sf.leadersOnly && !leaderWithin(m) {
+			continue
+		}
 		out.Subordinates[k] = sf.formatUnit(unitFormatInfo{
 			unit:          m,
 			unitName:      k,
@@ -198,6 +235,20 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 	return out
 }
 
+// leaderWithin returns true if the unit, or any of its subordinates,
+// is the leader of its service.
+func leaderWithin(unit params.UnitStatus) bool {
+	if unit.Leader {
+		return true
+	}
+	for _, sub := range unit.Subordinates {
+		if leaderWithin(sub) {
+			return true
+		}
+	}
+	return false
+}
+
 func (sf *statusFormatter) getWorkloadStatusInfo(unit params.UnitStatus) statusInfoContents {
 	info := statusInfoContents{
 		Err:     unit.Workload.Err,
@@ -58,6 +58,9 @@ func formatOneline(value interface{}, printf onelinePrintf) ([]byte, error) {
 			fmtPorts = fmt.Sprintf(" %s", strings.Join(u.OpenedPorts, ", "))
 		}
 		format := indent("\n", level*2, "- %s: %s (%v)"+fmtPorts)
+		if u.Leader {
+			uName += "*"
+		}
 		printf(&out, format, uName, u, level)
 	}
 
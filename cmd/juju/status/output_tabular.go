@@ -53,6 +53,9 @@ func FormatTabular(value interface{}) ([]byte, error) {
 		if agentDoing != "" {
 			message = fmt.Sprintf("(%s) %s", agentDoing, message)
 		}
+		if u.Leader {
+			name += "*"
+		}
 		p(
 			indent("", level*2, name),
 			u.WorkloadStatusInfo.Current,
@@ -22,9 +22,10 @@ var logger = loggo.GetLogger("juju.cmd.juju.status")
 
 type StatusCommand struct {
 	envcmd.EnvCommandBase
-	out      cmd.Output
-	patterns []string
-	isoTime  bool
+	out         cmd.Output
+	patterns    []string
+	isoTime     bool
+	leadersOnly bool
 }
 
 var statusDoc = `
@@ -56,6 +57,11 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+The leader unit of each service is marked in all formats; in the tabular and
+oneline formats its name is followed by '*'. The --leaders-only flag limits
+the displayed units to service leaders (and the principals of subordinate
+leaders).
 `
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -70,6 +76,7 @@ func (c *StatusCommand) Info() *cmd.Info {
 
 func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.BoolVar(&c.leadersOnly, "leaders-only", false, "only display units that are service leaders")
 
 	oneLineFormatter := FormatOneline
 	defaultFormat := "yaml"
@@ -141,7 +148,7 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 		return errors.Errorf("unable to obtain the current status")
 	}
 
-	formatter := newStatusFormatter(status, c.CompatVersion(), c.isoTime)
+	formatter := newStatusFormatter(status, c.CompatVersion(), c.isoTime, c.leadersOnly)
 	formatted := formatter.format()
 	return c.out.Write(ctx, formatted)
 }
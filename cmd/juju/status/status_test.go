@@ -347,6 +347,74 @@ var statusTests = []testCase{
 				"services": M{},
 			},
 		},
+	), test(
+		"state server in an availability zone",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", []network.Address{
+			network.NewAddress("10.0.0.1"),
+			network.NewScopedAddress("dummyenv-0.dns", network.ScopePublic),
+		}},
+		startAliveMachineInZone{"0", "zone1"},
+		setMachineStatus{"0", state.StatusStarted, ""},
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		startAliveMachineInZone{"1", "zone2"},
+		setMachineStatus{"1", state.StatusStarted, ""},
+		expect{
+			"the zone of the state server is shown",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": M{
+						"agent-state":                "started",
+						"dns-name":                   "dummyenv-0.dns",
+						"instance-id":                "dummyenv-0",
+						"series":                     "quantal",
+						"hardware":                   "arch=amd64 cpu-cores=1 mem=1024M root-disk=8192M availability-zone=zone1",
+						"state-server-member-status": "adding-vote",
+						"state-server-zone":          "zone1",
+					},
+					"1": M{
+						"agent-state": "started",
+						"instance-id": "dummyenv-1",
+						"series":      "quantal",
+						"hardware":    "arch=amd64 cpu-cores=1 mem=1024M root-disk=8192M availability-zone=zone2",
+					},
+				},
+				"services": M{},
+			},
+		},
+	), test(
+		"cordoned machine",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", state.StatusStarted, ""},
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		startAliveMachine{"1"},
+		setMachineStatus{"1", state.StatusStarted, ""},
+		cordonMachine{"1"},
+		expect{
+			"machine 1 is shown as cordoned",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": M{
+						"agent-state":                "started",
+						"instance-id":                "dummyenv-0",
+						"series":                     "quantal",
+						"hardware":                   "arch=amd64 cpu-cores=1 mem=1024M root-disk=8192M",
+						"state-server-member-status": "adding-vote",
+					},
+					"1": M{
+						"agent-state": "started",
+						"instance-id": "dummyenv-1",
+						"series":      "quantal",
+						"hardware":    "arch=amd64 cpu-cores=1 mem=1024M root-disk=8192M",
+						"cordoned":    true,
+					},
+				},
+				"services": M{},
+			},
+		},
 	), test(
 		"instance without addresses",
 		addMachine{machineId: "0", cons: machineCons, job: state.JobManageEnviron},
@@ -2003,6 +2071,102 @@ var statusTests = []testCase{
 				},
 			},
 		},
+	), test(
+		"service in a rolling upgrade",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", network.NewAddresses("dummyenv-0.dns")},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", state.StatusStarted, ""},
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		setAddresses{"1", network.NewAddresses("dummyenv-1.dns")},
+		startAliveMachine{"1"},
+		setMachineStatus{"1", state.StatusStarted, ""},
+		addCharm{"mysql"},
+		addService{name: "mysql", charm: "mysql"},
+		addAliveUnit{"mysql", "1"},
+		setUnitCharmURL{"mysql/0", "cs:quantal/mysql-1"},
+		addCharmWithRevision{addCharm{"mysql"}, "local", 1},
+		setServiceCharmRolling{"mysql", "local:quantal/mysql-1", 2},
+
+		expect{
+			"the rolling upgrade is shown",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": machine0,
+					"1": machine1,
+				},
+				"services": M{
+					"mysql": M{
+						"charm":   "local:quantal/mysql-1",
+						"exposed": false,
+						"service-status": M{
+							"current": "active",
+							"since":   "01 Apr 15 01:23+10:00",
+						},
+						"rolling-upgrade": M{
+							"status":         "running",
+							"previous-charm": "cs:quantal/mysql-1",
+							"batch-size":     2,
+							"waiting":        L{"mysql/0"},
+						},
+						"units": M{
+							"mysql/0": M{
+								"machine":     "1",
+								"agent-state": "started",
+								"workload-status": M{
+									"current": "active",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"agent-status": M{
+									"current": "idle",
+									"since":   "01 Apr 15 01:23+10:00",
+								},
+								"upgrading-from": "cs:quantal/mysql-1",
+								"public-address": "dummyenv-1.dns",
+							},
+						},
+					},
+				},
+			},
+		},
+	), test(
+		"staged agent upgrade",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", network.NewAddresses("dummyenv-0.dns")},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", state.StatusStarted, ""},
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		setAddresses{"1", network.NewAddresses("dummyenv-1.dns")},
+		startAliveMachine{"1"},
+		setMachineStatus{"1", state.StatusStarted, ""},
+		addMachine{machineId: "2", job: state.JobHostUnits},
+		setAddresses{"2", network.NewAddresses("dummyenv-2.dns")},
+		startAliveMachine{"2"},
+		setMachineStatus{"2", state.StatusStarted, ""},
+		startStagedUpgrade{[]string{"1"}, 2},
+
+		expect{
+			"the staged upgrade is shown",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": machine0,
+					"1": machine1,
+					"2": machine2,
+				},
+				"services": M{},
+				"staged-upgrade": M{
+					"status":           "running",
+					"previous-version": version.Current.Number.String(),
+					"version":          nextVersion().String(),
+					"canaries":         L{"1"},
+					"batch-size":       2,
+					"upgrading":        L{"0", "1"},
+					"waiting":          L{"2"},
+				},
+			},
+		},
 	), test(
 		"service and unit with out of date charms",
 		addMachine{machineId: "0", job: state.JobManageEnviron},
@@ -2363,6 +2527,38 @@ func (sam startAliveMachine) step(c *gc.C, ctx *context) {
 	ctx.pingers[m.Id()] = pinger
 }
 
+// startAliveMachineInZone starts a machine, as startAliveMachine
+// does, recording it as being in the given availability zone.
+type startAliveMachineInZone struct {
+	machineId string
+	zone      string
+}
+
+func (sam startAliveMachineInZone) step(c *gc.C, ctx *context) {
+	m, err := ctx.st.Machine(sam.machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	pinger := ctx.setAgentPresence(c, m)
+	cons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	inst, hc := testing.AssertStartInstanceWithConstraints(c, ctx.env, m.Id(), cons)
+	zone := sam.zone
+	hc.AvailabilityZone = &zone
+	err = m.SetProvisioned(inst.Id(), "fake_nonce", hc)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx.pingers[m.Id()] = pinger
+}
+
+type cordonMachine struct {
+	machineId string
+}
+
+func (cm cordonMachine) step(c *gc.C, ctx *context) {
+	m, err := ctx.st.Machine(cm.machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type setAddresses struct {
 	machineId string
 	addresses []network.Address
@@ -2475,6 +2671,38 @@ func (ssc setServiceCharm) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setServiceCharmRolling struct {
+	name      string
+	charm     string
+	batchSize int
+}
+
+func (ssc setServiceCharmRolling) step(c *gc.C, ctx *context) {
+	ch, err := ctx.st.Charm(charm.MustParseURL(ssc.charm))
+	c.Assert(err, jc.ErrorIsNil)
+	s, err := ctx.st.Service(ssc.name)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.SetCharmRolling(ch, false, ssc.batchSize)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type startStagedUpgrade struct {
+	canaries  []string
+	batchSize int
+}
+
+func (ssu startStagedUpgrade) step(c *gc.C, ctx *context) {
+	err := ctx.st.StartStagedUpgrade(nextVersion(), ssu.canaries, ssu.batchSize)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// nextVersion returns the version a staged upgrade moves to.
+func nextVersion() version.Number {
+	vers := version.Current.Number
+	vers.Patch++
+	return vers
+}
+
 type addCharmPlaceholder struct {
 	name string
 	rev  int
@@ -3225,6 +3453,41 @@ func (s *StatusSuite) TestFormatTabularHookActionName(c *gc.C) {
 	)
 }
 
+func (s *StatusSuite) TestFormatLeadersOnly(c *gc.C) {
+	expiry := time.Date(2015, 8, 1, 12, 0, 0, 0, time.UTC)
+	status := &params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"foo": {
+				Units: map[string]params.UnitStatus{
+					"foo/0": {
+						Subordinates: map[string]params.UnitStatus{
+							"logging/0": {Leader: true, LeaderExpiry: &expiry},
+						},
+					},
+					"foo/1": {Leader: true, LeaderExpiry: &expiry},
+					"foo/2": {},
+				},
+			},
+		},
+	}
+	formatted := newStatusFormatter(status, 1, true, true).format()
+	units := formatted.Services["foo"].Units
+	c.Assert(units, gc.HasLen, 2)
+	c.Check(units["foo/1"].Leader, jc.IsTrue)
+	c.Check(units["foo/1"].LeaderExpiry, gc.Equals, "2015-08-01 12:00:00Z")
+	c.Check(units["foo/0"].Leader, jc.IsFalse)
+	c.Check(units["foo/0"].Subordinates, gc.HasLen, 1)
+	c.Check(units["foo/0"].Subordinates["logging/0"].Leader, jc.IsTrue)
+
+	out, err := FormatOneline(formatted)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, ""+
+		"\n- foo/0:  ()"+
+		"\n  - logging/0*:  ()"+
+		"\n- foo/1*:  ()",
+	)
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
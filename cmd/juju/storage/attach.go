@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+)
+
+const attachCommandDoc = `
+Attach a detached storage instance to a unit.
+
+The unit's charm must define storage with the same name and type
+as the storage instance, and the unit must be assigned to a machine.
+The storage-attached hook will be run for the unit once the storage
+has been attached to its machine.
+
+Storage backed by machine-scoped volumes or filesystems, such as
+loop devices, may only be attached to units on the same machine.
+
+Example:
+    Attach detached storage instance data/0 to unit mysql/1:
+
+      juju storage attach data/0 mysql/1
+`
+
+// AttachCommand attaches a detached storage instance to a unit.
+type AttachCommand struct {
+	StorageCommandBase
+	storageId string
+	unitId    string
+}
+
+// Init implements Command.Init.
+func (c *AttachCommand) Init(args []string) error {
+	switch len(args) {
+	case 0, 1:
+		return errors.New("storage attach requires a storage ID and a unit")
+	case 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage ID %q", args[0])
+	}
+	if !names.IsValidUnit(args[1]) {
+		return errors.NotValidf("unit name %q", args[1])
+	}
+	c.storageId, c.unitId = args[0], args[1]
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *AttachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "attach",
+		Args:    "<storage ID> <unit name>",
+		Purpose: "attach a detached storage instance to a unit",
+		Doc:     attachCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *AttachCommand) Run(ctx *cmd.Context) error {
+	api, err := getStorageAttachAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return api.Attach(c.storageId, c.unitId)
+}
+
+var getStorageAttachAPI = (*AttachCommand).getStorageAttachAPI
+
+// StorageAttachAPI defines the API methods that the storage attach
+// command uses.
+type StorageAttachAPI interface {
+	Close() error
+	Attach(storageId, unitId string) error
+}
+
+func (c *AttachCommand) getStorageAttachAPI() (StorageAttachAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type attachSuite struct {
+	SubStorageSuite
+	mockAPI *mockAttachAPI
+}
+
+var _ = gc.Suite(&attachSuite{})
+
+func (s *attachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockAttachAPI{}
+	s.PatchValue(storage.GetStorageAttachAPI, func(*storage.AttachCommand) (storage.StorageAttachAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runAttach(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.AttachCommand{}), args...)
+}
+
+func (s *attachSuite) TestAttachArgs(c *gc.C) {
+	for i, t := range []tstData{
+		{nil, "storage attach requires a storage ID and a unit"},
+		{[]string{"data/0"}, "storage attach requires a storage ID and a unit"},
+		{[]string{"data/0", "mysql/1", "extra"}, `unrecognized args: \["extra"\]`},
+		{[]string{"data", "mysql/1"}, `storage ID "data" not valid`},
+		{[]string{"data/0", "mysql"}, `unit name "mysql" not valid`},
+	} {
+		c.Logf("test %d for %q", i, t.args)
+		_, err := runAttach(c, t.args...)
+		c.Assert(err, gc.ErrorMatches, t.expectedErr)
+	}
+}
+
+func (s *attachSuite) TestAttach(c *gc.C) {
+	_, err := runAttach(c, "data/0", "mysql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.storageId, gc.Equals, "data/0")
+	c.Assert(s.mockAPI.unitId, gc.Equals, "mysql/1")
+}
+
+func (s *attachSuite) TestAttachError(c *gc.C) {
+	s.mockAPI.err = errors.New("storage is not detached")
+	_, err := runAttach(c, "data/0", "mysql/1")
+	c.Assert(err, gc.ErrorMatches, "storage is not detached")
+}
+
+type mockAttachAPI struct {
+	storageId, unitId string
+	err               error
+}
+
+func (s *mockAttachAPI) Close() error {
+	return nil
+}
+
+func (s *mockAttachAPI) Attach(storageId, unitId string) error {
+	s.storageId, s.unitId = storageId, unitId
+	return s.err
+}
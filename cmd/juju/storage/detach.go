@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const detachCommandDoc = `
+Detach storage instances from the units they are attached to.
+
+The storage-detaching hook will be run for each unit before the
+storage is detached. Detached storage instances, and the volumes or
+filesystems backing them, are retained so that they may later be
+attached to another unit with "juju storage attach".
+
+Example:
+    Detach storage instance data/0 from its unit:
+
+      juju storage detach data/0
+`
+
+// DetachCommand detaches storage instances from their units.
+type DetachCommand struct {
+	StorageCommandBase
+	ids []string
+}
+
+// Init implements Command.Init.
+func (c *DetachCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("storage detach requires at least one storage ID")
+	}
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.NotValidf("storage ID %q", id)
+		}
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *DetachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "detach",
+		Args:    "<storage ID> [<storage ID> ...]",
+		Purpose: "detach storage instances from their units",
+		Doc:     detachCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *DetachCommand) Run(ctx *cmd.Context) error {
+	api, err := getStorageDetachAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.Detach(c.ids)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(c.ids) {
+		return errors.Errorf("expected %d results, got %d", len(c.ids), len(results))
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, fail+": %v\n", c.ids[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+var getStorageDetachAPI = (*DetachCommand).getStorageDetachAPI
+
+// StorageDetachAPI defines the API methods that the storage detach
+// command uses.
+type StorageDetachAPI interface {
+	Close() error
+	Detach(storageIds []string) ([]params.ErrorResult, error)
+}
+
+func (c *DetachCommand) getStorageDetachAPI() (StorageDetachAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type detachSuite struct {
+	SubStorageSuite
+	mockAPI *mockDetachAPI
+}
+
+var _ = gc.Suite(&detachSuite{})
+
+func (s *detachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockDetachAPI{}
+	s.PatchValue(storage.GetStorageDetachAPI, func(*storage.DetachCommand) (storage.StorageDetachAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runDetach(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.DetachCommand{}), args...)
+}
+
+func (s *detachSuite) TestDetachNoArgs(c *gc.C) {
+	_, err := runDetach(c)
+	c.Assert(err, gc.ErrorMatches, "storage detach requires at least one storage ID")
+}
+
+func (s *detachSuite) TestDetachInvalidId(c *gc.C) {
+	_, err := runDetach(c, "data/0", "data")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+}
+
+func (s *detachSuite) TestDetach(c *gc.C) {
+	context, err := runDetach(c, "data/0", "data/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.ids, jc.DeepEquals, []string{"data/0", "data/1"})
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+	c.Assert(testing.Stderr(context), gc.Equals, "")
+}
+
+func (s *detachSuite) TestDetachFailure(c *gc.C) {
+	s.mockAPI.results = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "storage is not owned by unit mysql/0"}},
+	}
+	context, err := runDetach(c, "data/0", "data/1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals,
+		"fail: storage \"data/1\": storage is not owned by unit mysql/0\n",
+	)
+}
+
+func (s *detachSuite) TestDetachAPIError(c *gc.C) {
+	s.mockAPI.err = errors.New("aborted")
+	_, err := runDetach(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, "aborted")
+}
+
+type mockDetachAPI struct {
+	ids     []string
+	results []params.ErrorResult
+	err     error
+}
+
+func (s *mockDetachAPI) Close() error {
+	return nil
+}
+
+func (s *mockDetachAPI) Detach(ids []string) ([]params.ErrorResult, error) {
+	s.ids = ids
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.results != nil {
+		return s.results, nil
+	}
+	return make([]params.ErrorResult, len(ids)), nil
+}
@@ -12,4 +12,14 @@ var (
 
 	ConvertToVolumeInfo = convertToVolumeInfo
 	GetStorageAddAPI    = &getStorageAddAPI
+
+	GetStorageDetachAPI = &getStorageDetachAPI
+	GetStorageAttachAPI = &getStorageAttachAPI
+	GetStorageResizeAPI = &getStorageResizeAPI
+	GetStorageRemoveAPI = &getStorageRemoveAPI
+	GetStorageImportAPI = &getStorageImportAPI
+
+	GetSnapshotCreateAPI  = &getSnapshotCreateAPI
+	GetSnapshotListAPI    = &getSnapshotListAPI
+	GetSnapshotRestoreAPI = &getSnapshotRestoreAPI
 )
@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+)
+
+const importCommandDoc = `
+Import an existing volume or filesystem into the environment as storage.
+
+The volume or filesystem is identified by its provider ID, and is verified
+using the provider of the specified storage pool. Volumes may be imported
+from environment-scoped pools, such as EBS. Filesystems may be imported
+from pools whose provider supports it, such as NFS, where the provider ID
+is "<server>:<path>" for a directory directly under the pool's export.
+
+The imported storage is detached. It may be attached to a unit whose
+charm defines storage of the same kind with the name given by
+--storage-name, using "juju storage attach". Units added later whose
+storage constraints name the same storage and pool will use imported
+storage before any new storage is created. The storage provisioner
+then attaches the volume or filesystem to the unit's machine.
+
+An imported NFS filesystem is recorded against the first machine it is
+attached to, and cannot then be moved to another machine.
+
+Examples:
+    Import the EBS volume vol-123456 as "data" storage, and attach
+    it to unit postgresql/0:
+
+      juju storage import ebs vol-123456 --storage-name data
+      juju storage attach data/1 postgresql/0
+
+    Import a directory from the NFS server of the pool "shared", and
+    deploy a service whose first unit uses it:
+
+      juju storage import shared nfs.example.com:/srv/juju/data --storage-name data
+      juju deploy postgresql --storage data=shared
+`
+
+// ImportCommand imports an existing volume or filesystem into the
+// environment as storage.
+type ImportCommand struct {
+	StorageCommandBase
+	pool        string
+	providerId  string
+	storageName string
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ImportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	f.StringVar(&c.storageName, "storage-name", "", "the charm storage name to import the storage as")
+}
+
+// Init implements Command.Init.
+func (c *ImportCommand) Init(args []string) error {
+	switch len(args) {
+	case 0, 1:
+		return errors.New("storage import requires a pool and a provider ID")
+	case 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	if c.storageName == "" {
+		return errors.New("--storage-name must be specified")
+	}
+	if !names.IsValidStorageName(c.storageName) {
+		return errors.NotValidf("storage name %q", c.storageName)
+	}
+	c.pool, c.providerId = args[0], args[1]
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *ImportCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "import",
+		Args:    "<pool> <provider ID>",
+		Purpose: "import an existing volume or filesystem into the environment as storage",
+		Doc:     importCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *ImportCommand) Run(ctx *cmd.Context) error {
+	api, err := getStorageImportAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	storageTag, err := api.Import(c.pool, c.providerId, c.storageName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "imported storage %s\n", storageTag.Id())
+	return nil
+}
+
+var getStorageImportAPI = (*ImportCommand).getStorageImportAPI
+
+// StorageImportAPI defines the API methods that the storage import
+// command uses.
+type StorageImportAPI interface {
+	Close() error
+	Import(pool, providerId, storageName string) (names.StorageTag, error)
+}
+
+func (c *ImportCommand) getStorageImportAPI() (StorageImportAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type importSuite struct {
+	SubStorageSuite
+	mockAPI *mockImportAPI
+}
+
+var _ = gc.Suite(&importSuite{})
+
+func (s *importSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockImportAPI{}
+	s.PatchValue(storage.GetStorageImportAPI, func(*storage.ImportCommand) (storage.StorageImportAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runImport(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.ImportCommand{}), args...)
+}
+
+func (s *importSuite) TestImportArgs(c *gc.C) {
+	for i, t := range []tstData{
+		{nil, "storage import requires a pool and a provider ID"},
+		{[]string{"ebs"}, "storage import requires a pool and a provider ID"},
+		{[]string{"ebs", "vol-123", "extra"}, `unrecognized args: \["extra"\]`},
+		{[]string{"ebs", "vol-123"}, "--storage-name must be specified"},
+		{[]string{"ebs", "vol-123", "--storage-name", "Data!"}, `storage name "Data!" not valid`},
+	} {
+		c.Logf("test %d for %q", i, t.args)
+		_, err := runImport(c, t.args...)
+		c.Assert(err, gc.ErrorMatches, t.expectedErr)
+	}
+}
+
+func (s *importSuite) TestImport(c *gc.C) {
+	ctx, err := runImport(c, "ebs", "vol-123", "--storage-name", "data")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.pool, gc.Equals, "ebs")
+	c.Assert(s.mockAPI.providerId, gc.Equals, "vol-123")
+	c.Assert(s.mockAPI.storageName, gc.Equals, "data")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "imported storage data/1\n")
+}
+
+func (s *importSuite) TestImportError(c *gc.C) {
+	s.mockAPI.err = errors.New(`describing volume "vol-123": volume not found`)
+	_, err := runImport(c, "ebs", "vol-123", "--storage-name", "data")
+	c.Assert(err, gc.ErrorMatches, `describing volume "vol-123": volume not found`)
+}
+
+type mockImportAPI struct {
+	pool, providerId, storageName string
+	err                           error
+}
+
+func (s *mockImportAPI) Close() error {
+	return nil
+}
+
+func (s *mockImportAPI) Import(pool, providerId, storageName string) (names.StorageTag, error) {
+	s.pool, s.providerId, s.storageName = pool, providerId, storageName
+	if s.err != nil {
+		return names.StorageTag{}, s.err
+	}
+	return names.NewStorageTag(storageName + "/1"), nil
+}
@@ -30,6 +30,16 @@ into named resources that can be used during deployment.
 
 Pools defined at the environment level are easily reused across services.
 
+The "nfs" provider type creates filesystems as directories on an NFS
+server, and requires the "server" and "export" attributes. If the
+"share" attribute names a directory under the export, every filesystem
+created from the pool mounts that directory, so that the units of a
+service using the pool see the same data. Sharing is configured by the
+pool only: charms that declare shared storage are not yet supported.
+
+Example:
+    juju storage pool create shared-data nfs server=nfs.example.com export=/srv/juju share=data
+
 options:
     -e, --environment (= "")
         juju environment to operate in
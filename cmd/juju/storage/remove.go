@@ -0,0 +1,135 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const removeCommandDoc = `
+Remove storage instances from the units they are attached to.
+
+The storage-detaching hook will be run for each unit before the
+storage is removed. What then happens to the storage depends on the
+release policy:
+
+    destroy  the storage instance is removed, and the volumes or
+             filesystems backing it are destroyed
+    retain   the storage instance is detached and retained, along
+             with the volumes or filesystems backing it, so that it
+             may later be attached to another unit with
+             "juju storage attach"
+
+If neither --destroy nor --retain is specified, the release policy
+of each storage instance's pool is used. A pool's release policy is
+set with the "release-policy" pool attribute, and defaults to
+"destroy". The pool's release policy also applies when a unit that
+owns storage is removed.
+
+Examples:
+    Remove storage instance data/0, using its pool's release policy:
+
+      juju storage remove data/0
+
+    Remove storage instance data/0, retaining its volume:
+
+      juju storage remove data/0 --retain
+`
+
+// RemoveCommand removes storage instances from their units.
+type RemoveCommand struct {
+	StorageCommandBase
+	ids     []string
+	destroy bool
+	retain  bool
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *RemoveCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	f.BoolVar(&c.destroy, "destroy", false, "destroy the storage, and the volumes or filesystems backing it")
+	f.BoolVar(&c.retain, "retain", false, "detach and retain the storage, and the volumes or filesystems backing it")
+}
+
+// Init implements Command.Init.
+func (c *RemoveCommand) Init(args []string) error {
+	if c.destroy && c.retain {
+		return errors.New("--destroy and --retain are mutually exclusive")
+	}
+	if len(args) < 1 {
+		return errors.New("storage remove requires at least one storage ID")
+	}
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.NotValidf("storage ID %q", id)
+		}
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *RemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<storage ID> [<storage ID> ...]",
+		Purpose: "remove storage instances from their units",
+		Doc:     removeCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *RemoveCommand) Run(ctx *cmd.Context) error {
+	api, err := getStorageRemoveAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	var releasePolicy string
+	switch {
+	case c.destroy:
+		releasePolicy = "destroy"
+	case c.retain:
+		releasePolicy = "retain"
+	}
+	results, err := api.Remove(c.ids, releasePolicy)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(c.ids) {
+		return errors.Errorf("expected %d results, got %d", len(c.ids), len(results))
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, fail+": %v\n", c.ids[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+var getStorageRemoveAPI = (*RemoveCommand).getStorageRemoveAPI
+
+// StorageRemoveAPI defines the API methods that the storage remove
+// command uses.
+type StorageRemoveAPI interface {
+	Close() error
+	Remove(storageIds []string, releasePolicy string) ([]params.ErrorResult, error)
+}
+
+func (c *RemoveCommand) getStorageRemoveAPI() (StorageRemoveAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type removeSuite struct {
+	SubStorageSuite
+	mockAPI *mockRemoveAPI
+}
+
+var _ = gc.Suite(&removeSuite{})
+
+func (s *removeSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockRemoveAPI{}
+	s.PatchValue(storage.GetStorageRemoveAPI, func(*storage.RemoveCommand) (storage.StorageRemoveAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runRemove(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.RemoveCommand{}), args...)
+}
+
+func (s *removeSuite) TestRemoveNoArgs(c *gc.C) {
+	_, err := runRemove(c)
+	c.Assert(err, gc.ErrorMatches, "storage remove requires at least one storage ID")
+}
+
+func (s *removeSuite) TestRemoveInvalidId(c *gc.C) {
+	_, err := runRemove(c, "data/0", "data")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+}
+
+func (s *removeSuite) TestRemoveDestroyAndRetain(c *gc.C) {
+	_, err := runRemove(c, "data/0", "--destroy", "--retain")
+	c.Assert(err, gc.ErrorMatches, "--destroy and --retain are mutually exclusive")
+}
+
+func (s *removeSuite) TestRemove(c *gc.C) {
+	context, err := runRemove(c, "data/0", "data/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.ids, jc.DeepEquals, []string{"data/0", "data/1"})
+	c.Assert(s.mockAPI.releasePolicy, gc.Equals, "")
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+	c.Assert(testing.Stderr(context), gc.Equals, "")
+}
+
+func (s *removeSuite) TestRemoveDestroy(c *gc.C) {
+	_, err := runRemove(c, "data/0", "--destroy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.releasePolicy, gc.Equals, "destroy")
+}
+
+func (s *removeSuite) TestRemoveRetain(c *gc.C) {
+	_, err := runRemove(c, "data/0", "--retain")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.releasePolicy, gc.Equals, "retain")
+}
+
+func (s *removeSuite) TestRemoveFailure(c *gc.C) {
+	s.mockAPI.results = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "storage is not owned by unit mysql/0"}},
+	}
+	context, err := runRemove(c, "data/0", "data/1", "--retain")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals,
+		"fail: storage \"data/1\": storage is not owned by unit mysql/0\n",
+	)
+}
+
+func (s *removeSuite) TestRemoveAPIError(c *gc.C) {
+	s.mockAPI.err = errors.New("aborted")
+	_, err := runRemove(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, "aborted")
+}
+
+type mockRemoveAPI struct {
+	ids           []string
+	releasePolicy string
+	results       []params.ErrorResult
+	err           error
+}
+
+func (s *mockRemoveAPI) Close() error {
+	return nil
+}
+
+func (s *mockRemoveAPI) Remove(ids []string, releasePolicy string) ([]params.ErrorResult, error) {
+	s.ids = ids
+	s.releasePolicy = releasePolicy
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.results != nil {
+		return s.results, nil
+	}
+	return make([]params.ErrorResult, len(ids)), nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+)
+
+const resizeCommandDoc = `
+Grow a storage instance to the specified size.
+
+The size is specified in the same way as in storage constraints,
+with an optional unit suffix (M, G, T, P or E); the default unit
+is MiB. Storage may only be grown, not shrunk.
+
+Resizing is performed asynchronously by the storage provisioner.
+Block storage is resized by growing its volume; filesystem storage
+backed by a volume is resized by growing the volume and then the
+filesystem to fill it. Once the storage has been resized, the
+storage-resized hook will be run for the units it is attached to.
+If the storage could not be resized, the reason will be recorded
+in the status of the volume or filesystem.
+
+Not all storage providers support resizing storage.
+
+Example:
+    Grow storage instance data/0 to 20GiB:
+
+      juju storage resize data/0 20G
+`
+
+// ResizeCommand requests that a storage instance be grown.
+type ResizeCommand struct {
+	StorageCommandBase
+	storageId string
+	size      uint64
+}
+
+// Init implements Command.Init.
+func (c *ResizeCommand) Init(args []string) error {
+	switch len(args) {
+	case 0, 1:
+		return errors.New("storage resize requires a storage ID and a size")
+	case 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage ID %q", args[0])
+	}
+	size, err := utils.ParseSize(args[1])
+	if err != nil {
+		return errors.Annotatef(err, "cannot parse size %q", args[1])
+	}
+	if size == 0 {
+		return errors.Errorf("size must be greater than zero")
+	}
+	c.storageId, c.size = args[0], size
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *ResizeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "resize",
+		Args:    "<storage ID> <size>",
+		Purpose: "grow a storage instance",
+		Doc:     resizeCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *ResizeCommand) Run(ctx *cmd.Context) error {
+	api, err := getStorageResizeAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return api.Resize(c.storageId, c.size)
+}
+
+var getStorageResizeAPI = (*ResizeCommand).getStorageResizeAPI
+
+// StorageResizeAPI defines the API methods that the storage resize
+// command uses.
+type StorageResizeAPI interface {
+	Close() error
+	Resize(storageId string, size uint64) error
+}
+
+func (c *ResizeCommand) getStorageResizeAPI() (StorageResizeAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type resizeSuite struct {
+	SubStorageSuite
+	mockAPI *mockResizeAPI
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockResizeAPI{}
+	s.PatchValue(storage.GetStorageResizeAPI, func(*storage.ResizeCommand) (storage.StorageResizeAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runResize(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.ResizeCommand{}), args...)
+}
+
+func (s *resizeSuite) TestResizeArgs(c *gc.C) {
+	for i, t := range []tstData{
+		{nil, "storage resize requires a storage ID and a size"},
+		{[]string{"data/0"}, "storage resize requires a storage ID and a size"},
+		{[]string{"data/0", "10G", "extra"}, `unrecognized args: \["extra"\]`},
+		{[]string{"data", "10G"}, `storage ID "data" not valid`},
+		{[]string{"data/0", "lots"}, `cannot parse size "lots": .*`},
+		{[]string{"data/0", "0"}, "size must be greater than zero"},
+	} {
+		c.Logf("test %d for %q", i, t.args)
+		_, err := runResize(c, t.args...)
+		c.Assert(err, gc.ErrorMatches, t.expectedErr)
+	}
+}
+
+func (s *resizeSuite) TestResize(c *gc.C) {
+	_, err := runResize(c, "data/0", "10G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.storageId, gc.Equals, "data/0")
+	c.Assert(s.mockAPI.size, gc.Equals, uint64(10*1024))
+}
+
+func (s *resizeSuite) TestResizeError(c *gc.C) {
+	s.mockAPI.err = errors.New("new size 1024MiB must be greater than current size 1024MiB")
+	_, err := runResize(c, "data/0", "1024")
+	c.Assert(err, gc.ErrorMatches, "new size 1024MiB must be greater than current size 1024MiB")
+}
+
+type mockResizeAPI struct {
+	storageId string
+	size      uint64
+	err       error
+}
+
+func (s *mockResizeAPI) Close() error {
+	return nil
+}
+
+func (s *mockResizeAPI) Resize(storageId string, size uint64) error {
+	s.storageId, s.size = storageId, size
+	return s.err
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/common"
+)
+
+const snapshotCmdDoc = `
+"juju storage snapshot" is used to manage snapshots of storage
+ volumes in the Juju environment.
+
+Snapshots are taken by the storage provider that manages the
+volume. Restoring a snapshot creates a new volume with the
+contents of the snapshot, and attaches it to a machine.
+`
+
+const snapshotCmdPurpose = "manage storage volume snapshots"
+
+// NewSnapshotSuperCommand creates the storage snapshot super subcommand
+// and registers the subcommands that it supports.
+func NewSnapshotSuperCommand() cmd.Command {
+	snapshotcmd := jujucmd.NewSubSuperCommand(cmd.SuperCommandParams{
+		Name:        "snapshot",
+		Doc:         snapshotCmdDoc,
+		UsagePrefix: "juju storage",
+		Purpose:     snapshotCmdPurpose,
+	})
+	snapshotcmd.Register(envcmd.Wrap(&SnapshotCreateCommand{}))
+	snapshotcmd.Register(envcmd.Wrap(&SnapshotListCommand{}))
+	snapshotcmd.Register(envcmd.Wrap(&SnapshotRestoreCommand{}))
+	return snapshotcmd
+}
+
+// SnapshotAPI defines the API methods that the snapshot commands use.
+type SnapshotAPI interface {
+	Close() error
+	CreateVolumeSnapshots(volumes []string) ([]params.VolumeSnapshotResult, error)
+	ListVolumeSnapshots(volumes []string) ([]params.VolumeSnapshotResult, error)
+	RestoreVolumeSnapshots(snapshots []params.RestoreVolumeSnapshot) ([]params.StringResult, error)
+}
+
+// SnapshotInfo defines the serialization behaviour for volume snapshots.
+type SnapshotInfo struct {
+	// Volume is the Juju ID of the snapshotted volume.
+	Volume string `yaml:"volume" json:"volume"`
+
+	// SnapshotId is the provider-supplied unique snapshot ID.
+	SnapshotId string `yaml:"id,omitempty" json:"id,omitempty"`
+
+	Pool    string `yaml:"pool" json:"pool"`
+	Size    uint64 `yaml:"size" json:"size"`
+	Created string `yaml:"created" json:"created"`
+	Status  string `yaml:"status" json:"status"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// convertToSnapshotInfo returns a map of snapshot info, keyed
+// on the Juju snapshot ID.
+func convertToSnapshotInfo(all []params.VolumeSnapshotInstance) (map[string]SnapshotInfo, error) {
+	result := make(map[string]SnapshotInfo)
+	for _, one := range all {
+		volume, err := idFromTag(one.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[one.Id] = SnapshotInfo{
+			Volume:     volume,
+			SnapshotId: one.SnapshotId,
+			Pool:       one.Pool,
+			Size:       one.Size,
+			Created:    common.FormatTime(&one.Created, false),
+			Status:     one.Status,
+			Message:    one.Message,
+		}
+	}
+	return result, nil
+}
+
+// formatSnapshotListTabular returns a tabular summary of volume snapshots.
+func formatSnapshotListTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.(map[string]SnapshotInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("SNAPSHOT", "VOLUME", "ID", "POOL", "SIZE", "CREATED", "STATE", "MESSAGE")
+
+	ids := make([]string, 0, len(infos))
+	for id := range infos {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		info := infos[id]
+		var size string
+		if info.Size > 0 {
+			size = humanize.IBytes(info.Size * humanize.MiByte)
+		}
+		print(id, info.Volume, info.SnapshotId, info.Pool, size, info.Created, info.Status, info.Message)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
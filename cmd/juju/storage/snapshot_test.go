@@ -0,0 +1,200 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	jujucommon "github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+var expectedSnapshotCommmandNames = []string{
+	"create",
+	"help",
+	"list",
+	"restore",
+}
+
+type snapshotHelpSuite struct {
+	HelpStorageSuite
+}
+
+var _ = gc.Suite(&snapshotHelpSuite{})
+
+func (s *snapshotHelpSuite) TestSnapshotHelp(c *gc.C) {
+	s.command = storage.NewSnapshotSuperCommand()
+	s.assertHelp(c, expectedSnapshotCommmandNames)
+}
+
+type snapshotSuite struct {
+	SubStorageSuite
+	mockAPI *mockSnapshotAPI
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockSnapshotAPI{}
+	s.PatchValue(storage.GetSnapshotCreateAPI, func(*storage.SnapshotCreateCommand) (storage.SnapshotAPI, error) {
+		return s.mockAPI, nil
+	})
+	s.PatchValue(storage.GetSnapshotListAPI, func(*storage.SnapshotListCommand) (storage.SnapshotAPI, error) {
+		return s.mockAPI, nil
+	})
+	s.PatchValue(storage.GetSnapshotRestoreAPI, func(*storage.SnapshotRestoreCommand) (storage.SnapshotAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *snapshotSuite) TestCreateArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCreateCommand{}))
+	c.Assert(err, gc.ErrorMatches, "storage snapshot create requires at least one volume")
+	_, err = testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCreateCommand{}), "volume-0")
+	c.Assert(err, gc.ErrorMatches, `volume ID "volume-0" not valid`)
+}
+
+func (s *snapshotSuite) TestCreate(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCreateCommand{}), "0/1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(s.mockAPI.created, jc.DeepEquals, []string{"0/1", "2"})
+	c.Assert(testing.Stdout(context), gc.Equals, `snapshot "0/3" of volume "0/1" requested`+"\n")
+	c.Assert(testing.Stderr(context), gc.Equals, `fail: volume "2": volume "2" not provisioned`+"\n")
+}
+
+func (s *snapshotSuite) TestCreateError(c *gc.C) {
+	s.mockAPI.err = errors.New("just my luck")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCreateCommand{}), "0/1")
+	c.Assert(err, gc.ErrorMatches, "just my luck")
+}
+
+func (s *snapshotSuite) TestListTabular(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotListCommand{}), "0/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.listed, jc.DeepEquals, []string{"0/1"})
+	c.Assert(testing.Stdout(context), gc.Matches, `
+SNAPSHOT +VOLUME +ID +POOL +SIZE +CREATED +STATE +MESSAGE
+0/3 +0/1 +snap-shot +loop +1.0GiB +.* +created +
+0/4 +0/1 +loop +1.0GiB +.* +error +out of cheese
+`[1:])
+	c.Assert(testing.Stderr(context), gc.Equals, "snapshot error\n")
+}
+
+func (s *snapshotSuite) TestListYaml(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotListCommand{}), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	created := jujucommon.FormatTime(&snapshotCreated, false)
+	c.Assert(testing.Stdout(context), gc.Equals, fmt.Sprintf(`
+0/3:
+  volume: 0/1
+  id: snap-shot
+  pool: loop
+  size: 1024
+  created: %[1]s
+  status: created
+0/4:
+  volume: 0/1
+  pool: loop
+  size: 1024
+  created: %[1]s
+  status: error
+  message: out of cheese
+`[1:], created))
+}
+
+func (s *snapshotSuite) TestRestoreArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotRestoreCommand{}), "0/3")
+	c.Assert(err, gc.ErrorMatches, "storage snapshot restore requires a snapshot and a machine")
+	_, err = testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotRestoreCommand{}), "0/3", "machine-0")
+	c.Assert(err, gc.ErrorMatches, `machine ID "machine-0" not valid`)
+	_, err = testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotRestoreCommand{}), "0/3", "0", "1")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["1"\]`)
+}
+
+func (s *snapshotSuite) TestRestore(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotRestoreCommand{}), "0/3", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.restored, jc.DeepEquals, []params.RestoreVolumeSnapshot{{
+		Id: "0/3", MachineTag: "machine-0",
+	}})
+	c.Assert(testing.Stdout(context), gc.Equals, `restored snapshot "0/3" to volume "0/2"`+"\n")
+}
+
+func (s *snapshotSuite) TestRestoreResultError(c *gc.C) {
+	s.mockAPI.restoreErr = &params.Error{Message: "not provisioned"}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotRestoreCommand{}), "0/3", "0")
+	c.Assert(err, gc.ErrorMatches, "not provisioned")
+}
+
+var snapshotCreated = time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC)
+
+type mockSnapshotAPI struct {
+	err        error
+	restoreErr *params.Error
+	created    []string
+	listed     []string
+	restored   []params.RestoreVolumeSnapshot
+}
+
+func (s mockSnapshotAPI) Close() error {
+	return nil
+}
+
+func (s *mockSnapshotAPI) CreateVolumeSnapshots(volumes []string) ([]params.VolumeSnapshotResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.created = volumes
+	return []params.VolumeSnapshotResult{
+		{Result: &params.VolumeSnapshotInstance{Id: "0/3", VolumeTag: "volume-0-1"}},
+		{Error: common.ServerError(errors.NotProvisionedf("volume %q", "2"))},
+	}, nil
+}
+
+func (s *mockSnapshotAPI) ListVolumeSnapshots(volumes []string) ([]params.VolumeSnapshotResult, error) {
+	s.listed = volumes
+	return []params.VolumeSnapshotResult{{
+		Result: &params.VolumeSnapshotInstance{
+			Id:         "0/3",
+			VolumeTag:  "volume-0-1",
+			SnapshotId: "snap-shot",
+			Pool:       "loop",
+			Size:       1024,
+			Created:    snapshotCreated,
+			Status:     "created",
+		},
+	}, {
+		Result: &params.VolumeSnapshotInstance{
+			Id:        "0/4",
+			VolumeTag: "volume-0-1",
+			Pool:      "loop",
+			Size:      1024,
+			Created:   snapshotCreated,
+			Status:    "error",
+			Message:   "out of cheese",
+		},
+	}, {
+		Error: &params.Error{Message: "snapshot error"},
+	}}, nil
+}
+
+func (s *mockSnapshotAPI) RestoreVolumeSnapshots(snapshots []params.RestoreVolumeSnapshot) ([]params.StringResult, error) {
+	s.restored = snapshots
+	if s.restoreErr != nil {
+		return []params.StringResult{{Error: s.restoreErr}}, nil
+	}
+	return []params.StringResult{{Result: "volume-0-2"}}, nil
+}
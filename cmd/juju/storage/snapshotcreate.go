@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+)
+
+const snapshotCreateCommandDoc = `
+Request snapshots of one or more storage volumes.
+
+Snapshots are created asynchronously by the storage provisioner.
+Use "juju storage snapshot list" to see when they have been created.
+
+Example:
+    Snapshot volumes 0/1 and 2:
+
+      juju storage snapshot create 0/1 2
+`
+
+// SnapshotCreateCommand requests snapshots of storage volumes.
+type SnapshotCreateCommand struct {
+	VolumeCommandBase
+	Ids []string
+}
+
+// Init implements Command.Init.
+func (c *SnapshotCreateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("storage snapshot create requires at least one volume")
+	}
+	for _, id := range args {
+		if !names.IsValidVolume(id) {
+			return errors.NotValidf("volume ID %q", id)
+		}
+	}
+	c.Ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *SnapshotCreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<volume> ...",
+		Purpose: "snapshot storage volumes",
+		Doc:     snapshotCreateCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *SnapshotCreateCommand) Run(ctx *cmd.Context) error {
+	api, err := getSnapshotCreateAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.CreateVolumeSnapshots(c.Ids)
+	if err != nil {
+		return err
+	}
+	var failed bool
+	for i, one := range results {
+		if one.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "fail: volume %q: %v\n", c.Ids[i], one.Error)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(ctx.Stdout, "snapshot %q of volume %q requested\n", one.Result.Id, c.Ids[i])
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+var getSnapshotCreateAPI = (*SnapshotCreateCommand).getSnapshotAPI
+
+func (c *SnapshotCreateCommand) getSnapshotAPI() (SnapshotAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const snapshotListCommandDoc = `
+List snapshots of storage volumes in the environment.
+
+options:
+-e, --environment (= "")
+    juju environment to operate in
+-o, --output (= "")
+    specify an output file
+[volume]
+    volume ids for filtering the list
+
+`
+
+// SnapshotListCommand lists storage volume snapshots.
+type SnapshotListCommand struct {
+	VolumeCommandBase
+	Ids []string
+	out cmd.Output
+}
+
+// Init implements Command.Init.
+func (c *SnapshotListCommand) Init(args []string) error {
+	c.Ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *SnapshotListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list storage volume snapshots",
+		Doc:     snapshotListCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *SnapshotListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSnapshotListTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *SnapshotListCommand) Run(ctx *cmd.Context) error {
+	api, err := getSnapshotListAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	found, err := api.ListVolumeSnapshots(c.Ids)
+	if err != nil {
+		return err
+	}
+	// filter out valid output, if any
+	var valid []params.VolumeSnapshotInstance
+	for _, one := range found {
+		if one.Error == nil {
+			valid = append(valid, *one.Result)
+			continue
+		}
+		// display individual error
+		fmt.Fprintf(ctx.Stderr, "%v\n", one.Error)
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+	output, err := convertToSnapshotInfo(valid)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, output)
+}
+
+var getSnapshotListAPI = (*SnapshotListCommand).getSnapshotAPI
+
+func (c *SnapshotListCommand) getSnapshotAPI() (SnapshotAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const snapshotRestoreCommandDoc = `
+Restore a storage volume snapshot to a new volume.
+
+The new volume is created in the same storage pool as the
+snapshotted volume, and attached to the specified machine.
+Snapshots of machine-scoped volumes, such as loop devices, may
+only be restored to the machine that the volume was attached to.
+
+Example:
+    Restore snapshot 0/0 to a new volume attached to machine 0:
+
+      juju storage snapshot restore 0/0 0
+`
+
+// SnapshotRestoreCommand restores a volume snapshot to a new volume.
+type SnapshotRestoreCommand struct {
+	VolumeCommandBase
+	SnapshotId string
+	MachineId  string
+}
+
+// Init implements Command.Init.
+func (c *SnapshotRestoreCommand) Init(args []string) error {
+	switch len(args) {
+	case 0, 1:
+		return errors.New("storage snapshot restore requires a snapshot and a machine")
+	case 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	if !names.IsValidMachine(args[1]) {
+		return errors.NotValidf("machine ID %q", args[1])
+	}
+	c.SnapshotId, c.MachineId = args[0], args[1]
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *SnapshotRestoreCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "restore",
+		Args:    "<snapshot> <machine>",
+		Purpose: "restore a storage volume snapshot to a new volume",
+		Doc:     snapshotRestoreCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *SnapshotRestoreCommand) Run(ctx *cmd.Context) error {
+	api, err := getSnapshotRestoreAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.RestoreVolumeSnapshots([]params.RestoreVolumeSnapshot{{
+		Id:         c.SnapshotId,
+		MachineTag: names.NewMachineTag(c.MachineId).String(),
+	}})
+	if err != nil {
+		return err
+	}
+	if len(results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	volume, err := idFromTag(results[0].Result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "restored snapshot %q to volume %q\n", c.SnapshotId, volume)
+	return nil
+}
+
+var getSnapshotRestoreAPI = (*SnapshotRestoreCommand).getSnapshotAPI
+
+func (c *SnapshotRestoreCommand) getSnapshotAPI() (SnapshotAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -36,8 +36,14 @@ func NewSuperCommand() cmd.Command {
 	storagecmd.Register(envcmd.Wrap(&ShowCommand{}))
 	storagecmd.Register(envcmd.Wrap(&ListCommand{}))
 	storagecmd.Register(envcmd.Wrap(&AddCommand{}))
+	storagecmd.Register(envcmd.Wrap(&DetachCommand{}))
+	storagecmd.Register(envcmd.Wrap(&AttachCommand{}))
+	storagecmd.Register(envcmd.Wrap(&ResizeCommand{}))
+	storagecmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	storagecmd.Register(envcmd.Wrap(&ImportCommand{}))
 	storagecmd.Register(NewPoolSuperCommand())
 	storagecmd.Register(NewVolumeSuperCommand())
+	storagecmd.Register(NewSnapshotSuperCommand())
 	return storagecmd
 }
 
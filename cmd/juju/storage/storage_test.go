@@ -11,10 +11,16 @@ import (
 
 var expectedSubCommmandNames = []string{
 	"add",
+	"attach",
+	"detach",
 	"help",
+	"import",
 	"list",
 	"pool",
+	"remove",
+	"resize",
 	"show",
+	"snapshot",
 	"volume",
 }
 
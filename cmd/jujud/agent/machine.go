@@ -36,6 +36,7 @@ import (
 	apiagent "github.com/juju/juju/api/agent"
 	apideployer "github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/api/metricsmanager"
+	"github.com/juju/juju/api/spaces"
 	apiupgrader "github.com/juju/juju/api/upgrader"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
@@ -47,6 +48,7 @@ import (
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/lxcutils"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
@@ -71,10 +73,13 @@ import (
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
+	"github.com/juju/juju/worker/containermigrator"
 	"github.com/juju/juju/worker/conv2state"
 	"github.com/juju/juju/worker/dblogpruner"
 	"github.com/juju/juju/worker/deployer"
+	"github.com/juju/juju/worker/discoverspaces"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/diskspacerecorder"
 	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
 	"github.com/juju/juju/worker/gate"
@@ -91,8 +96,10 @@ import (
 	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/resumer"
+	"github.com/juju/juju/worker/rollingupgrader"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/stagedupgrader"
 	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/terminationworker"
@@ -915,9 +922,106 @@ func (a *MachineAgent) setupContainerSupport(runner worker.Runner, st api.Connec
 	if err == nil && supportsKvm {
 		supportedContainers = append(supportedContainers, instance.KVM)
 	}
+
+	// LXC and KVM containers can be migrated to and from this machine.
+	var migratableContainers []instance.ContainerType
+	for _, ctype := range supportedContainers {
+		if ctype == instance.LXC || ctype == instance.KVM {
+			migratableContainers = append(migratableContainers, ctype)
+		}
+	}
+	if len(migratableContainers) > 0 {
+		a.startWorkerAfterUpgrade(runner, "containermigrator", func() (worker.Worker, error) {
+			return newContainerMigrator(st, agentConfig, migratableContainers)
+		})
+	}
+
+	supportsLXD, err := lxd.IsLXDSupported()
+	if err != nil {
+		logger.Warningf("determining lxd support: %v\nno lxd containers possible", err)
+	}
+	if err == nil && supportsLXD {
+		supportedContainers = append(supportedContainers, instance.LXD)
+	}
 	return a.updateSupportedContainers(runner, st, entity.Tag(), supportedContainers, agentConfig)
 }
 
+// newContainerMigrator returns a worker that moves containers of the
+// given types to and from this machine, using the same container
+// manager configuration as the provisioners for those types.
+func newContainerMigrator(st api.Connection, agentConfig agent.Config, ctypes []instance.ContainerType) (worker.Worker, error) {
+	tag, ok := agentConfig.Tag().(names.MachineTag)
+	if !ok {
+		return nil, errors.Errorf("expected names.MachineTag, got %T", agentConfig.Tag())
+	}
+	managers := make(map[instance.ContainerType]containermigrator.ContainerManager)
+	networks := make(map[instance.ContainerType]*container.NetworkConfig)
+	for _, ctype := range ctypes {
+		manager, network, err := newMigrationManager(st, agentConfig, ctype)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot migrate %s containers", ctype)
+		}
+		managers[ctype] = manager
+		networks[ctype] = network
+	}
+	return containermigrator.NewWorker(containermigrator.Config{
+		API:      st.ContainerMigrator(),
+		Managers: managers,
+		HostTag:  tag,
+		Networks: networks,
+	})
+}
+
+// newMigrationManager returns the container manager used to migrate
+// containers of the given type, and the network imported containers
+// are connected to.
+func newMigrationManager(
+	st api.Connection, agentConfig agent.Config, ctype instance.ContainerType,
+) (containermigrator.ContainerManager, *container.NetworkConfig, error) {
+	result, err := st.Provisioner().ContainerManagerConfig(
+		params.ContainerManagerConfigParams{Type: ctype},
+	)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	managerConfig := container.ManagerConfig(result.ManagerConfig)
+	if namespace := agentConfig.Value(agent.Namespace); namespace != "" {
+		managerConfig[container.ConfigName] = namespace
+	}
+	// Host networking is set up by the provisioner; only the MTU
+	// matters for imported containers.
+	managerConfig.PopValue(container.ConfigIPForwarding)
+	managerConfig.PopValue(container.ConfigEnableNAT)
+	mtu := 0
+	if value := managerConfig.PopValue(container.ConfigLXCDefaultMTU); value != "" {
+		if mtu, err = strconv.Atoi(value); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	// KVM containers use the bridge configured for LXC, as they
+	// do when provisioned.
+	bridgeDevice := agentConfig.Value(agent.LxcBridge)
+	var manager container.Manager
+	switch ctype {
+	case instance.LXC:
+		manager, err = lxc.NewContainerManager(managerConfig, nil, looputil.NewLoopDeviceManager())
+		if bridgeDevice == "" {
+			bridgeDevice = lxc.DefaultLxcBridge
+		}
+	case instance.KVM:
+		manager, err = kvm.NewContainerManager(managerConfig)
+		if bridgeDevice == "" {
+			bridgeDevice = kvm.DefaultKvmBridge
+		}
+	default:
+		return nil, nil, errors.NotSupportedf("migrating %s containers", ctype)
+	}
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return manager.(containermigrator.ContainerManager), container.BridgeNetworkConfig(bridgeDevice, mtu, nil), nil
+}
+
 // updateSupportedContainers records in state that a machine can run the specified containers.
 // It starts a watcher and when a container of a given type is first added to the machine,
 // the watcher is killed, the machine is set up to be able to start containers of the given type,
@@ -1051,6 +1155,9 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(runner, "certupdater", func() (worker.Worker, error) {
 				return newCertificateUpdater(m, agentConfig, st, st, stateServingSetter, certChangedChan), nil
 			})
+			a.startWorkerAfterUpgrade(runner, "diskspacerecorder", func() (worker.Worker, error) {
+				return diskspacerecorder.New(st, m.Id(), agentConfig.DataDir(), 5*time.Minute), nil
+			})
 
 			if feature.IsDbLogEnabled() {
 				a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
@@ -1134,6 +1241,12 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("rollingupgrader", func() (worker.Worker, error) {
+		return rollingupgrader.NewRollingUpgrader(st), nil
+	})
+	singularRunner.StartWorker("stagedupgrader", func() (worker.Worker, error) {
+		return stagedupgrader.NewStagedUpgrader(st), nil
+	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -1162,6 +1275,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
 		return newAddresser(apiSt.Addresser())
 	})
+	singularRunner.StartWorker("discoverspaces", func() (worker.Worker, error) {
+		return discoverspaces.NewWorker(spaces.NewAPI(apiSt), apiSt.Environment()), nil
+	})
 
 	// TODO(axw) 2013-09-24 bug #1229506
 	// Make another job to enable the firewaller. Not all
@@ -259,7 +259,10 @@ type MachineSuite struct {
 var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
+	"rollingupgrader",
+	"stagedupgrader",
 	"addresserworker",
+	"discoverspaces",
 	"environ-provisioner",
 	"charm-revision-updater",
 	"instancepoller",
@@ -2329,6 +2332,10 @@ func (m *mockMetricAPI) SendMetrics() error {
 	return nil
 }
 
+func (m *mockMetricAPI) EvaluateMeterThresholds() error {
+	return nil
+}
+
 func (m *mockMetricAPI) SendCalled() <-chan struct{} {
 	return m.sendCalled
 }
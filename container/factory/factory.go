@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/storage/looputil"
 )
@@ -24,6 +25,8 @@ func NewContainerManager(forType instance.ContainerType, conf container.ManagerC
 		return lxc.NewContainerManager(conf, imageURLGetter, looputil.NewLoopDeviceManager())
 	case instance.KVM:
 		return kvm.NewContainerManager(conf)
+	case instance.LXD:
+		return lxd.NewContainerManager(conf)
 	}
 	return nil, errors.Errorf("unknown container type: %q", forType)
 }
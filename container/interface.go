@@ -4,6 +4,8 @@
 package container
 
 import (
+	"io"
+
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/instance"
 )
@@ -60,6 +62,23 @@ type Manager interface {
 	IsInitialized() bool
 }
 
+// Migrator is implemented by managers whose containers can be moved
+// from one host machine to another.
+type Migrator interface {
+	// ExportContainer stops the container identified by instance id
+	// and writes an archive of its filesystem to w. The stopped
+	// container is left in place, so it can be restarted should the
+	// migration fail.
+	ExportContainer(id instance.Id, w io.Writer) error
+
+	// ImportContainer recreates an exported container from the archive
+	// read from r, connects it to the given network and starts it.
+	ImportContainer(id instance.Id, r io.Reader, network *NetworkConfig) (instance.Instance, error)
+
+	// StartContainer starts a stopped container.
+	StartContainer(id instance.Id) error
+}
+
 // Initialiser is responsible for performing the steps required to initialise
 // a host machine so it can run containers.
 type Initialiser interface {
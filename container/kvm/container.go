@@ -66,8 +66,16 @@ func (c *kvmContainer) Start(params StartParams) error {
 
 func (c *kvmContainer) Stop() error {
 	if !c.IsRunning() {
-		logger.Debugf("%s is already stopped", c.name)
-		return nil
+		// A machine powered off for migration is still defined,
+		// and must be destroyed all the same.
+		machines, err := ListMachines()
+		if err != nil {
+			return err
+		}
+		if _, ok := machines[c.name]; !ok {
+			logger.Debugf("%s is already stopped", c.name)
+			return nil
+		}
 	}
 	// Make started state unknown again.
 	c.started = nil
@@ -8,6 +8,9 @@ var (
 
 	// Used to export the parameters used to call Start on the KVM Container
 	TestStartParams = &startParams
+
+	Run            = &run
+	UvtoolImageDir = &uvtoolImageDir
 )
 
 func NewEmptyKvmContainer() *kvmContainer {
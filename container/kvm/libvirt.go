@@ -33,8 +33,9 @@ var (
 	machineListPattern = regexp.MustCompile(`(?m)^\s+\d+\s+(?P<hostname>[-\w]+)\s+(?P<status>.+)\s*$`)
 )
 
-// run the command and return the combined output.
-func run(command string, args ...string) (output string, err error) {
+// run the command and return the combined output. It is a variable
+// so it can be overridden in tests.
+var run = func(command string, args ...string) (output string, err error) {
 	logger.Tracef("%s %v", command, args)
 	output, err = utils.RunCommand(command, args...)
 	logger.Tracef("output: %v", output)
@@ -152,3 +153,44 @@ func ListMachines() (map[string]string, error) {
 	}
 	return result, nil
 }
+
+// PoweroffMachine stops the virtual machine identified by hostname
+// immediately, leaving it defined along with its disks.
+func PoweroffMachine(hostname string) error {
+	_, err := run("virsh", "destroy", hostname)
+	return err
+}
+
+// StartMachine starts the stopped virtual machine identified by
+// hostname.
+func StartMachine(hostname string) error {
+	_, err := run("virsh", "start", hostname)
+	return err
+}
+
+// MachineXML returns the libvirt domain XML of the virtual machine
+// identified by hostname.
+func MachineXML(hostname string) (string, error) {
+	return run("virsh", "dumpxml", hostname)
+}
+
+// DefineMachine defines a virtual machine from the libvirt domain XML
+// in the given file, without starting it.
+func DefineMachine(xmlFile string) error {
+	_, err := run("virsh", "define", xmlFile)
+	return err
+}
+
+// RefreshImagePool makes libvirt pick up changes to the disk images
+// in the uvtool storage pool.
+func RefreshImagePool() error {
+	_, err := run("virsh", "pool-refresh", "uvtool")
+	return err
+}
+
+// FlattenImage writes a copy of the qcow2 disk image src to dst that
+// holds the contents of any backing images, so it can be used alone.
+func FlattenImage(src, dst string) error {
+	_, err := run("qemu-img", "convert", "-O", "qcow2", src, dst)
+	return err
+}
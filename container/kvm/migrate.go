@@ -0,0 +1,203 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+)
+
+// containerManager implements container.Migrator.
+var _ container.Migrator = (*containerManager)(nil)
+
+// uvtoolImageDir is the directory of the uvtool storage pool, which
+// holds the disk images of the virtual machines.
+var uvtoolImageDir = "/var/lib/uvtool/libvirt/images"
+
+// domainFilename is the name of the file in a container archive that
+// holds the container's libvirt domain XML.
+const domainFilename = "domain.xml"
+
+// bridgeSourcePattern matches the bridge of a network interface in
+// libvirt domain XML.
+var bridgeSourcePattern = regexp.MustCompile(`<source bridge='[^']*'/>`)
+
+// runTar runs tar with the given arguments, reading from stdin and
+// writing to stdout. It is a variable so it can be overridden in tests.
+var runTar = func(args []string, stdin io.Reader, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("tar", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Annotatef(err, "tar failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// machineImages returns the names of the disk images uvtool creates for
+// the named machine: its root disk and its cloud-init data source.
+func machineImages(name string) []string {
+	return []string{name + ".qcow", name + "-ds.qcow"}
+}
+
+// findContainer returns the existing container with the given name.
+func findContainer(name string) (Container, error) {
+	containers, err := KvmObjectFactory.List()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, kvmContainer := range containers {
+		if kvmContainer.Name() == name {
+			return kvmContainer, nil
+		}
+	}
+	return nil, errors.NotFoundf("container %q", name)
+}
+
+// ExportContainer is specified on the container.Migrator interface.
+// The archive holds the machine's domain XML and its disk images,
+// flattened so they no longer depend on the cloud image they were
+// created from.
+func (manager *containerManager) ExportContainer(id instance.Id, w io.Writer) error {
+	name := string(id)
+	kvmContainer, err := findContainer(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if kvmContainer.IsRunning() {
+		// Stopping the container would destroy it, so the machine
+		// is powered off instead.
+		logger.Debugf("stopping container %q for export", name)
+		if err := PoweroffMachine(name); err != nil {
+			return errors.Annotatef(err, "cannot stop container %q", name)
+		}
+	}
+	dir, err := ioutil.TempDir("", "juju-kvm-export")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	domain, err := MachineXML(name)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get definition of container %q", name)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, domainFilename), []byte(domain), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	for _, image := range machineImages(name) {
+		src := filepath.Join(uvtoolImageDir, image)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := FlattenImage(src, filepath.Join(dir, image)); err != nil {
+			return errors.Annotatef(err, "cannot copy disk image %q", image)
+		}
+	}
+	args := []string{"--create", "--gzip", "--sparse", "--file", "-", "-C", dir, "."}
+	if err := runTar(args, nil, w); err != nil {
+		return errors.Annotatef(err, "cannot archive container %q", name)
+	}
+	logger.Infof("exported container %q", name)
+	return nil
+}
+
+// ImportContainer is specified on the container.Migrator interface.
+func (manager *containerManager) ImportContainer(id instance.Id, r io.Reader, network *container.NetworkConfig) (_ instance.Instance, err error) {
+	name := string(id)
+	if _, err := findContainer(name); err == nil {
+		return nil, errors.AlreadyExistsf("container %q", name)
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	dir, err := ioutil.TempDir("", "juju-kvm-import")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"--extract", "--gzip", "--file", "-", "-C", dir}
+	if err := runTar(args, r, nil); err != nil {
+		return nil, errors.Annotatef(err, "cannot extract container %q", name)
+	}
+	domainFile := filepath.Join(dir, domainFilename)
+	domain, err := ioutil.ReadFile(domainFile)
+	if err != nil {
+		return nil, errors.Errorf("archive does not hold container %q", name)
+	}
+	if network != nil && network.NetworkType == container.BridgeNetwork && network.Device != "" {
+		bridge := fmt.Sprintf("<source bridge='%s'/>", network.Device)
+		domain = bridgeSourcePattern.ReplaceAll(domain, []byte(bridge))
+		if err := ioutil.WriteFile(domainFile, domain, 0644); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	var imported []string
+	defer func() {
+		if err != nil {
+			for _, path := range imported {
+				if err := os.Remove(path); err != nil {
+					logger.Errorf("cannot remove partially imported disk image %q: %v", path, err)
+				}
+			}
+		}
+	}()
+	for _, image := range machineImages(name) {
+		src := filepath.Join(dir, image)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		dst := filepath.Join(uvtoolImageDir, image)
+		if err := utils.CopyFile(dst, src); err != nil {
+			return nil, errors.Annotatef(err, "cannot copy disk image %q", image)
+		}
+		imported = append(imported, dst)
+	}
+	if err := RefreshImagePool(); err != nil {
+		return nil, errors.Annotate(err, "cannot refresh disk images")
+	}
+	if err := DefineMachine(domainFile); err != nil {
+		return nil, errors.Annotatef(err, "cannot define container %q", name)
+	}
+	if err := AutostartMachine(name); err != nil {
+		return nil, errors.Annotatef(err, "cannot set container %q to autostart", name)
+	}
+	if err := StartMachine(name); err != nil {
+		return nil, errors.Annotatef(err, "cannot start container %q", name)
+	}
+	logger.Infof("imported container %q", name)
+	return &kvmInstance{KvmObjectFactory.New(name), name}, nil
+}
+
+// StartContainer is specified on the container.Migrator interface.
+func (manager *containerManager) StartContainer(id instance.Id) error {
+	name := string(id)
+	kvmContainer, err := findContainer(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if kvmContainer.IsRunning() {
+		return nil
+	}
+	if err := StartMachine(name); err != nil {
+		return errors.Annotatef(err, "cannot start container %q", name)
+	}
+	return nil
+}
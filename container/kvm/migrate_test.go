@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/kvm"
+	"github.com/juju/juju/container/kvm/mock"
+	containertesting "github.com/juju/juju/container/testing"
+)
+
+const testDomainXML = "<domain><interface type='bridge'><source bridge='virbr0'/></interface></domain>"
+
+// patchLibvirt replaces the commands run on libvirt's machines with a
+// fake that records them, and returns the recorded commands along with
+// the domain XML last defined.
+func (s *KVMSuite) patchLibvirt(c *gc.C) (*[]string, *string) {
+	var commands []string
+	var defined string
+	s.PatchValue(kvm.Run, func(command string, args ...string) (string, error) {
+		switch {
+		case command == "virsh" && args[0] == "dumpxml":
+			commands = append(commands, "virsh dumpxml "+args[1])
+			return testDomainXML, nil
+		case command == "virsh" && args[0] == "define":
+			data, err := ioutil.ReadFile(args[1])
+			c.Assert(err, jc.ErrorIsNil)
+			defined = string(data)
+			commands = append(commands, "virsh define")
+			return "", nil
+		case command == "qemu-img":
+			// Flattening copies the image.
+			src, dst := args[len(args)-2], args[len(args)-1]
+			data, err := ioutil.ReadFile(src)
+			c.Assert(err, jc.ErrorIsNil)
+			err = ioutil.WriteFile(dst, data, 0644)
+			c.Assert(err, jc.ErrorIsNil)
+			commands = append(commands, "qemu-img convert "+filepath.Base(src))
+			return "", nil
+		}
+		commands = append(commands, command+" "+strings.Join(args, " "))
+		return "", nil
+	})
+	return &commands, &defined
+}
+
+func (s *KVMSuite) migrator(c *gc.C) container.Migrator {
+	migrator, ok := s.manager.(container.Migrator)
+	c.Assert(ok, jc.IsTrue)
+	return migrator
+}
+
+func (s *KVMSuite) TestExportImportContainer(c *gc.C) {
+	imageDir := c.MkDir()
+	s.PatchValue(kvm.UvtoolImageDir, imageDir)
+	commands, defined := s.patchLibvirt(c)
+	migrator := s.migrator(c)
+	inst := containertesting.CreateContainer(c, s.manager, "1/kvm/0")
+	name := string(inst.Id())
+	err := ioutil.WriteFile(filepath.Join(imageDir, name+".qcow"), []byte("disk"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var archive bytes.Buffer
+	err = migrator.ExportContainer(inst.Id(), &archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*commands, jc.DeepEquals, []string{
+		"virsh destroy " + name,
+		"virsh dumpxml " + name,
+		"qemu-img convert " + name + ".qcow",
+	})
+
+	// Import the archive on another host.
+	*commands = nil
+	imageDir = c.MkDir()
+	s.PatchValue(kvm.UvtoolImageDir, imageDir)
+	s.PatchValue(&kvm.KvmObjectFactory, mock.MockFactory())
+	network := container.BridgeNetworkConfig("br-target", 0, nil)
+	imported, err := migrator.ImportContainer(inst.Id(), &archive, network)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(imported.Id(), gc.Equals, inst.Id())
+	c.Assert(*commands, jc.DeepEquals, []string{
+		"virsh pool-refresh uvtool",
+		"virsh define",
+		"virsh autostart " + name,
+		"virsh start " + name,
+	})
+	c.Assert(*defined, gc.Equals, "<domain><interface type='bridge'><source bridge='br-target'/></interface></domain>")
+	data, err := ioutil.ReadFile(filepath.Join(imageDir, name+".qcow"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "disk")
+	c.Assert(filepath.Join(imageDir, name+"-ds.qcow"), jc.DoesNotExist)
+}
+
+func (s *KVMSuite) TestExportContainerNotFound(c *gc.C) {
+	err := s.migrator(c).ExportContainer("test-machine-1-kvm-0", ioutil.Discard)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *KVMSuite) TestImportContainerAlreadyExists(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/kvm/0")
+
+	_, err := s.migrator(c).ImportContainer(inst.Id(), &bytes.Buffer{}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *KVMSuite) TestImportContainerBadArchive(c *gc.C) {
+	commands, _ := s.patchLibvirt(c)
+
+	_, err := s.migrator(c).ImportContainer("test-machine-1-kvm-0", bytes.NewBufferString("rubbish"), nil)
+	c.Assert(err, gc.ErrorMatches, `cannot extract container "test-machine-1-kvm-0": tar failed: .*`)
+	c.Assert(*commands, gc.HasLen, 0)
+}
+
+func (s *KVMSuite) TestStartContainer(c *gc.C) {
+	commands, _ := s.patchLibvirt(c)
+	// The container exists, but is stopped.
+	kvm.KvmObjectFactory.New("test-machine-1-kvm-0")
+
+	err := s.migrator(c).StartContainer("test-machine-1-kvm-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*commands, jc.DeepEquals, []string{"virsh start test-machine-1-kvm-0"})
+}
@@ -21,6 +21,7 @@ var (
 	RuntimeGOOS             = &runtimeGOOS
 	RunningInsideLXC        = &runningInsideLXC
 	WriteWgetTmpFile        = &writeWgetTmpFile
+	HostCapacity            = &hostCapacity
 )
 
 func GetCreateWithCloneValue(mgr container.Manager) bool {
@@ -0,0 +1,215 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+)
+
+// cpuSharesPerCore is the cgroup cpu.shares weight of a process that
+// is not otherwise constrained, which we take to represent one core
+// at the cpu-power reference of 100.
+const cpuSharesPerCore = 1024
+
+// cfsPeriod is the CFS scheduler period, in microseconds, over which
+// a container's CPU quota is enforced. A quota of one period per
+// period amounts to one core's worth of CPU time, spread across
+// whichever of the host's cores are least busy.
+const cfsPeriod = 100000
+
+const (
+	memoryLimitSetting = "lxc.cgroup.memory.limit_in_bytes"
+	cfsPeriodSetting   = "lxc.cgroup.cpu.cfs_period_us"
+	cfsQuotaSetting    = "lxc.cgroup.cpu.cfs_quota_us"
+	cpuSharesSetting   = "lxc.cgroup.cpu.shares"
+)
+
+// resourceLimits holds the cgroup limits applied to a container.
+// Zero values mean no limit.
+type resourceLimits struct {
+	Memory   uint64 // MB
+	CpuCores uint64
+	CpuPower uint64
+}
+
+// hostCapacity returns the total memory in MB and the number of CPU
+// cores of the host. It is a variable so it can be overridden in tests.
+var hostCapacity = func() (memory, cpuCores uint64, err error) {
+	data, err := ioutil.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, 0, errors.Annotate(err, "cannot parse MemTotal")
+			}
+			return kb / 1024, uint64(runtime.NumCPU()), nil
+		}
+	}
+	return 0, 0, errors.New("cannot find MemTotal in /proc/meminfo")
+}
+
+// constraintsToLimits converts the mem, cpu-cores and cpu-power
+// constraints into container limits, rejecting those the host cannot
+// satisfy on top of the limits of the containers it already has.
+func constraintsToLimits(cons constraints.Value) (resourceLimits, error) {
+	var limits resourceLimits
+	if cons.Mem != nil {
+		limits.Memory = *cons.Mem
+	}
+	if cons.CpuCores != nil {
+		limits.CpuCores = *cons.CpuCores
+	}
+	if cons.CpuPower != nil {
+		limits.CpuPower = *cons.CpuPower
+	}
+	if limits == (resourceLimits{}) {
+		return limits, nil
+	}
+	hostMemory, hostCores, err := hostCapacity()
+	if err != nil {
+		return limits, errors.Annotate(err, "cannot determine host capacity")
+	}
+	allocated, err := allocatedLimits()
+	if err != nil {
+		return limits, errors.Annotate(err, "cannot determine resources allocated to existing containers")
+	}
+	if limits.Memory > 0 && allocated.Memory+limits.Memory > hostMemory {
+		return limits, errors.NewNotValid(nil, fmt.Sprintf(
+			"mem constraint of %dM exceeds host memory of %dM (%dM allocated to other containers)",
+			limits.Memory, hostMemory, allocated.Memory,
+		))
+	}
+	if limits.CpuCores > 0 && allocated.CpuCores+limits.CpuCores > hostCores {
+		return limits, errors.NewNotValid(nil, fmt.Sprintf(
+			"cpu-cores constraint of %d exceeds host cores of %d (%d allocated to other containers)",
+			limits.CpuCores, hostCores, allocated.CpuCores,
+		))
+	}
+	return limits, nil
+}
+
+// allocatedLimits returns the total memory and cores granted to all
+// of the containers on the host, as recorded in their config files.
+func allocatedLimits() (resourceLimits, error) {
+	var total resourceLimits
+	containers, err := LxcObjectFactory.List()
+	if err != nil {
+		return total, errors.Trace(err)
+	}
+	for _, container := range containers {
+		limits, err := containerLimits(container.Name())
+		if err != nil {
+			return total, errors.Annotatef(err, "container %q", container.Name())
+		}
+		total.Memory += limits.Memory
+		total.CpuCores += limits.CpuCores
+	}
+	return total, nil
+}
+
+// containerLimits returns the memory and cores granted to the named
+// container by its config file.
+func containerLimits(name string) (resourceLimits, error) {
+	var limits resourceLimits
+	data, err := ioutil.ReadFile(containerConfigFilename(name))
+	if os.IsNotExist(err) {
+		return limits, nil
+	} else if err != nil {
+		return limits, errors.Trace(err)
+	}
+	period, quota := uint64(cfsPeriod), uint64(0)
+	for _, line := range strings.Split(string(data), "\n") {
+		setting, value := parseConfigLine(line)
+		var err error
+		switch setting {
+		case memoryLimitSetting:
+			limits.Memory, err = parseMemoryLimit(value)
+		case cfsPeriodSetting:
+			period, err = strconv.ParseUint(value, 10, 64)
+		case cfsQuotaSetting:
+			quota, err = strconv.ParseUint(value, 10, 64)
+		}
+		if err != nil {
+			return limits, errors.Annotatef(err, "cannot parse %s", setting)
+		}
+	}
+	if period > 0 {
+		// Round partial cores up, as they still use up a core.
+		limits.CpuCores = (quota + period - 1) / period
+	}
+	return limits, nil
+}
+
+// cgroupConfig returns the lxc config lines enforcing the limits.
+// Memory is a hard limit, cores are granted as a CFS quota of CPU
+// time, so containers are not pinned to particular cores, and
+// cpu-power is translated into a relative cpu.shares weight.
+func (limits resourceLimits) cgroupConfig() string {
+	var lines []string
+	if limits.Memory > 0 {
+		lines = append(lines, fmt.Sprintf("%s = %dM", memoryLimitSetting, limits.Memory))
+	}
+	if limits.CpuCores > 0 {
+		lines = append(lines,
+			fmt.Sprintf("%s = %d", cfsPeriodSetting, cfsPeriod),
+			fmt.Sprintf("%s = %d", cfsQuotaSetting, limits.CpuCores*cfsPeriod),
+		)
+	}
+	if limits.CpuPower > 0 {
+		shares := limits.CpuPower * cpuSharesPerCore / 100
+		lines = append(lines, fmt.Sprintf("%s = %d", cpuSharesSetting, shares))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// applyResourceLimits appends the cgroup limits to the container's
+// config.
+func applyResourceLimits(name string, limits resourceLimits) error {
+	config := limits.cgroupConfig()
+	if config == "" {
+		return nil
+	}
+	return appendToContainerConfig(name, config)
+}
+
+// parseMemoryLimit parses a cgroup memory limit, in bytes or with a
+// K, M or G suffix, returning it in MB.
+func parseMemoryLimit(value string) (uint64, error) {
+	multiplier := uint64(1)
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier = 1024
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return n * multiplier / (1024 * 1024), nil
+}
@@ -209,6 +209,13 @@ func (manager *containerManager) CreateContainer(
 		name = fmt.Sprintf("%s-%s", manager.name, name)
 	}
 
+	// Refuse constraints the host cannot satisfy before doing
+	// anything else.
+	limits, err := constraintsToLimits(instanceConfig.Constraints)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "invalid container constraints")
+	}
+
 	// Create the cloud-init.
 	directory, err := container.NewDirectory(name)
 	if err != nil {
@@ -316,6 +323,9 @@ func (manager *containerManager) CreateContainer(
 			return nil, nil, errors.Annotate(err, "failed to configure the container for loopback devices")
 		}
 	}
+	if err := applyResourceLimits(name, limits); err != nil {
+		return nil, nil, errors.Annotate(err, "failed to configure the container's resource limits")
+	}
 	// Update the network settings inside the run-time config of the
 	// container (e.g. /var/lib/lxc/<name>/config) before starting it.
 	netConfig := generateNetworkConfig(networkConfig)
@@ -388,6 +398,15 @@ func (manager *containerManager) CreateContainer(
 	hardware := &instance.HardwareCharacteristics{
 		Arch: &arch,
 	}
+	if limits.Memory > 0 {
+		hardware.Mem = &limits.Memory
+	}
+	if limits.CpuCores > 0 {
+		hardware.CpuCores = &limits.CpuCores
+	}
+	if limits.CpuPower > 0 {
+		hardware.CpuPower = &limits.CpuPower
+	}
 
 	return &lxcInstance{lxcContainer, name}, hardware, nil
 }
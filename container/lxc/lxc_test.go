@@ -24,6 +24,7 @@ import (
 	"launchpad.net/golxc"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/mock"
@@ -33,6 +34,7 @@ import (
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	instancetest "github.com/juju/juju/instance/testing"
+	"github.com/juju/juju/juju/arch"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
 	coretesting "github.com/juju/juju/testing"
@@ -981,6 +983,81 @@ lxc.cgroup.devices.allow = c 10:237 rwm
 	c.Assert(autostartLink, jc.DoesNotExist)
 }
 
+func (s *LxcSuite) createContainerWithConstraints(c *gc.C, machineId, cons string) (instance.Instance, *instance.HardwareCharacteristics, error) {
+	s.PatchValue(lxc.HostCapacity, func() (uint64, uint64, error) {
+		return 4096, 4, nil
+	})
+	s.PatchValue(&arch.HostArch, func() string { return arch.AMD64 })
+	manager := s.makeManager(c, "test")
+	instanceConfig, err := containertesting.MockMachineConfig(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Config, err = config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Constraints = constraints.MustParse(cons)
+	networkConfig := container.BridgeNetworkConfig("nic42", 0, nil)
+	return manager.CreateContainer(instanceConfig, "quantal", networkConfig, &container.StorageConfig{})
+}
+
+func (s *LxcSuite) TestCreateContainerAppliesResourceLimits(c *gc.C) {
+	inst, hardware, err := s.createContainerWithConstraints(c, "1/lxc/0", "mem=1G cpu-cores=2 cpu-power=50")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hardware.String(), gc.Equals, "arch=amd64 cpu-cores=2 cpu-power=50 mem=1024M")
+
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), jc.HasSuffix, `
+lxc.cgroup.memory.limit_in_bytes = 1024M
+lxc.cgroup.cpu.cfs_period_us = 100000
+lxc.cgroup.cpu.cfs_quota_us = 200000
+lxc.cgroup.cpu.shares = 512
+`)
+}
+
+func (s *LxcSuite) TestCreateContainerWithoutConstraintsHasNoLimits(c *gc.C) {
+	s.PatchValue(lxc.HostCapacity, func() (uint64, uint64, error) {
+		return 0, 0, errors.New("should not be called")
+	})
+	manager := s.makeManager(c, "test")
+	inst := containertesting.CreateContainer(c, manager, "1/lxc/0")
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), gc.Not(jc.Contains), "lxc.cgroup.memory")
+	c.Assert(string(config), gc.Not(jc.Contains), "lxc.cgroup.cpu")
+}
+
+func (s *LxcSuite) TestCreateContainerRejectsImpossibleConstraints(c *gc.C) {
+	for i, test := range []struct {
+		cons string
+		err  string
+	}{{
+		cons: "mem=8G",
+		err:  `invalid container constraints: mem constraint of 8192M exceeds host memory of 4096M \(0M allocated to other containers\)`,
+	}, {
+		cons: "cpu-cores=16",
+		err:  `invalid container constraints: cpu-cores constraint of 16 exceeds host cores of 4 \(0 allocated to other containers\)`,
+	}} {
+		c.Logf("test %d: %s", i, test.cons)
+		_, _, err := s.createContainerWithConstraints(c, "1/lxc/0", test.cons)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+		c.Check(filepath.Join(s.ContainerDir, "test-machine-1-lxc-0"), jc.DoesNotExist)
+	}
+}
+
+func (s *LxcSuite) TestCreateContainerCountsExistingContainerLimits(c *gc.C) {
+	_, _, err := s.createContainerWithConstraints(c, "1/lxc/0", "mem=3G cpu-cores=3")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, _, err = s.createContainerWithConstraints(c, "1/lxc/1", "mem=2G")
+	c.Assert(err, gc.ErrorMatches, `invalid container constraints: mem constraint of 2048M exceeds host memory of 4096M \(3072M allocated to other containers\)`)
+	_, _, err = s.createContainerWithConstraints(c, "1/lxc/1", "cpu-cores=2")
+	c.Assert(err, gc.ErrorMatches, `invalid container constraints: cpu-cores constraint of 2 exceeds host cores of 4 \(3 allocated to other containers\)`)
+
+	// What is left can still be allocated.
+	_, _, err = s.createContainerWithConstraints(c, "1/lxc/1", "mem=1G cpu-cores=1")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *LxcSuite) TestDestroyContainerRemovesAutostartLink(c *gc.C) {
 	manager := s.makeManager(c, "test")
 	instance := containertesting.CreateContainer(c, manager, "1/lxc/0")
@@ -0,0 +1,169 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"launchpad.net/golxc"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+)
+
+// containerManager implements container.Migrator.
+var _ container.Migrator = (*containerManager)(nil)
+
+// runTar runs tar with the given arguments, reading from stdin and
+// writing to stdout. It is a variable so it can be overridden in tests.
+var runTar = func(args []string, stdin io.Reader, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("tar", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Annotatef(err, "tar failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ExportContainer is specified on the container.Migrator interface.
+// The archive holds the container's directory, which includes its
+// config and its rootfs, along with any loop storage inside it.
+func (manager *containerManager) ExportContainer(id instance.Id, w io.Writer) error {
+	name := string(id)
+	lxcContainer := LxcObjectFactory.New(name)
+	if !lxcContainer.IsConstructed() {
+		return errors.NotFoundf("container %q", name)
+	}
+	if err := checkRootfsExportable(name); err != nil {
+		return errors.Trace(err)
+	}
+	if lxcContainer.IsRunning() {
+		logger.Debugf("stopping container %q for export", name)
+		if err := lxcContainer.Stop(); err != nil {
+			return errors.Annotatef(err, "cannot stop container %q", name)
+		}
+	}
+	// Loop devices backed by files in the rootfs must be detached
+	// before the files are archived.
+	rootfs := filepath.Join(LxcContainerDir, name, "rootfs")
+	if err := manager.loopDeviceManager.DetachLoopDevices(rootfs, "/"); err != nil {
+		return errors.Annotatef(err, "cannot detach loop devices from container %q", name)
+	}
+	args := []string{"--create", "--gzip", "--numeric-owner", "--xattrs", "--file", "-", "-C", LxcContainerDir, name}
+	if err := runTar(args, nil, w); err != nil {
+		return errors.Annotatef(err, "cannot archive container %q", name)
+	}
+	logger.Infof("exported container %q", name)
+	return nil
+}
+
+// checkRootfsExportable returns an error if the container's rootfs is
+// not held entirely within its directory, as with overlay snapshots of
+// a clone template.
+func checkRootfsExportable(name string) error {
+	data, err := ioutil.ReadFile(containerConfigFilename(name))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		setting, value := parseConfigLine(line)
+		if setting != "lxc.rootfs" {
+			continue
+		}
+		if i := strings.Index(value, ":"); i > 0 {
+			return errors.NotSupportedf("exporting containers with a %s rootfs", value[:i])
+		}
+	}
+	return nil
+}
+
+// ImportContainer is specified on the container.Migrator interface.
+func (manager *containerManager) ImportContainer(id instance.Id, r io.Reader, network *container.NetworkConfig) (_ instance.Instance, err error) {
+	name := string(id)
+	containerDir := filepath.Join(LxcContainerDir, name)
+	if _, err := os.Stat(containerDir); err == nil {
+		return nil, errors.AlreadyExistsf("container %q", name)
+	}
+	defer func() {
+		if err != nil {
+			if err := os.RemoveAll(containerDir); err != nil {
+				logger.Errorf("cannot remove partially imported container %q: %v", name, err)
+			}
+		}
+	}()
+	args := []string{"--extract", "--gzip", "--numeric-owner", "--xattrs", "--preserve-permissions", "--file", "-", "-C", LxcContainerDir}
+	if err := runTar(args, r, nil); err != nil {
+		return nil, errors.Annotatef(err, "cannot extract container %q", name)
+	}
+	if _, err := os.Stat(containerConfigFilename(name)); err != nil {
+		return nil, errors.Errorf("archive does not hold container %q", name)
+	}
+
+	if network != nil {
+		if err := updateContainerConfig(name, generateNetworkConfig(network)); err != nil {
+			return nil, errors.Annotate(err, "failed to update network config")
+		}
+		if _, err := reorderNetworkConfig(containerConfigFilename(name)); err != nil {
+			return nil, errors.Annotate(err, "failed to reorder network settings")
+		}
+	}
+	// The config already asks for the host's log dir to be mounted
+	// and, from Trusty onwards, for the container to be started
+	// automatically.
+	if err := os.MkdirAll(manager.logdir, 0777); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if useRestartDir() {
+		if err := autostartContainer(name); err != nil {
+			return nil, errors.Annotate(err, "failed to configure the container for autostart")
+		}
+		defer func() {
+			if err != nil {
+				os.Remove(restartSymlink(name))
+			}
+		}()
+	}
+	lxcContainer, err := manager.startContainer(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("imported container %q", name)
+	return &lxcInstance{lxcContainer, name}, nil
+}
+
+// StartContainer is specified on the container.Migrator interface.
+func (manager *containerManager) StartContainer(id instance.Id) error {
+	_, err := manager.startContainer(string(id))
+	return errors.Trace(err)
+}
+
+func (manager *containerManager) startContainer(name string) (golxc.Container, error) {
+	lxcContainer := LxcObjectFactory.New(name)
+	if !lxcContainer.IsConstructed() {
+		return nil, errors.NotFoundf("container %q", name)
+	}
+	if lxcContainer.IsRunning() {
+		return lxcContainer, nil
+	}
+	directory, err := container.NewDirectory(name)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to create a directory for the container")
+	}
+	consoleFile := filepath.Join(directory, "console.log")
+	lxcContainer.SetLogFile(filepath.Join(directory, "container.log"), golxc.LogDebug)
+	if err := lxcContainer.Start("", consoleFile); err != nil {
+		return nil, errors.Annotatef(err, "cannot start container %q", name)
+	}
+	return lxcContainer, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxc_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/golxc"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxc"
+	containertesting "github.com/juju/juju/container/testing"
+)
+
+func (s *LxcSuite) migrator(c *gc.C) container.Migrator {
+	manager := s.makeManager(c, "juju")
+	migrator, ok := manager.(container.Migrator)
+	c.Assert(ok, jc.IsTrue)
+	return migrator
+}
+
+func (s *LxcSuite) TestExportImportContainer(c *gc.C) {
+	migrator := s.migrator(c)
+	inst := containertesting.CreateContainer(c, migrator.(container.Manager), "1/lxc/0")
+	name := string(inst.Id())
+	rootfs := filepath.Join(s.LxcDir, name, "rootfs")
+	err := ioutil.WriteFile(filepath.Join(rootfs, "loop-backing-file"), []byte("data"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var archive bytes.Buffer
+	err = migrator.ExportContainer(inst.Id(), &archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inst.Status(), gc.Equals, string(golxc.StateStopped))
+	c.Assert(s.loopDeviceManager.detachLoopDevicesArgs, jc.DeepEquals, [][]string{{rootfs, "/"}})
+
+	// Remove the container, as if the archive were taken to another host.
+	err = migrator.(container.Manager).DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filepath.Join(s.LxcDir, name), jc.DoesNotExist)
+
+	network := container.BridgeNetworkConfig("br-target", 0, nil)
+	imported, err := migrator.ImportContainer(inst.Id(), &archive, network)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(imported.Id(), gc.Equals, inst.Id())
+	c.Assert(imported.Status(), gc.Equals, string(golxc.StateRunning))
+
+	data, err := ioutil.ReadFile(filepath.Join(rootfs, "loop-backing-file"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "data")
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(name))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), jc.Contains, "lxc.network.link = br-target\n")
+}
+
+func (s *LxcSuite) TestExportContainerNotFound(c *gc.C) {
+	err := s.migrator(c).ExportContainer("juju-machine-1-lxc-0", ioutil.Discard)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LxcSuite) TestExportContainerOverlayRootfs(c *gc.C) {
+	migrator := s.migrator(c)
+	inst := containertesting.CreateContainer(c, migrator.(container.Manager), "1/lxc/0")
+	err := lxc.UpdateContainerConfig(string(inst.Id()), "lxc.rootfs = overlayfs:/var/lib/lxc/template/rootfs:/var/lib/lxc/c/delta0\n")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = migrator.ExportContainer(inst.Id(), ioutil.Discard)
+	c.Assert(err, gc.ErrorMatches, "exporting containers with a overlayfs rootfs not supported")
+	c.Assert(inst.Status(), gc.Equals, string(golxc.StateRunning))
+}
+
+func (s *LxcSuite) TestImportContainerAlreadyExists(c *gc.C) {
+	migrator := s.migrator(c)
+	inst := containertesting.CreateContainer(c, migrator.(container.Manager), "1/lxc/0")
+
+	_, err := migrator.ImportContainer(inst.Id(), &bytes.Buffer{}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *LxcSuite) TestImportContainerBadArchive(c *gc.C) {
+	migrator := s.migrator(c)
+	_, err := migrator.ImportContainer("juju-machine-1-lxc-0", bytes.NewBufferString("rubbish"), nil)
+	c.Assert(err, gc.ErrorMatches, `cannot extract container "juju-machine-1-lxc-0": tar failed: .*`)
+	c.Assert(filepath.Join(s.LxcDir, "juju-machine-1-lxc-0"), jc.DoesNotExist)
+}
+
+func (s *LxcSuite) TestStartContainerAfterExport(c *gc.C) {
+	migrator := s.migrator(c)
+	inst := containertesting.CreateContainer(c, migrator.(container.Manager), "1/lxc/0")
+	err := migrator.ExportContainer(inst.Id(), ioutil.Discard)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = migrator.StartContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inst.Status(), gc.Equals, string(golxc.StateRunning))
+}
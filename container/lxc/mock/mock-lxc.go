@@ -234,6 +234,9 @@ func (mock *mockContainer) Destroy() error {
 		return fmt.Errorf("container has not been created")
 	}
 	delete(mock.factory.instances, mock.name)
+	if err := os.RemoveAll(filepath.Join(mock.factory.containerDir, mock.name)); err != nil {
+		return errors.Trace(err)
+	}
 	mock.setState(golxc.StateUnknown)
 	mock.factory.notify(event(Destroyed, mock.name))
 	return nil
@@ -299,10 +302,17 @@ func (mock *mockFactory) New(name string) golxc.Container {
 	if ok {
 		return container
 	}
+	state := golxc.StateUnknown
+	configFile := filepath.Join(mock.containerDir, name, "config")
+	if _, err := os.Stat(configFile); err == nil {
+		// The container was put in place by some other means, such
+		// as being extracted from an archive.
+		state = golxc.StateStopped
+	}
 	container = &mockContainer{
 		factory:  mock,
 		name:     name,
-		state:    golxc.StateUnknown,
+		state:    state,
 		logLevel: golxc.LogWarning,
 	}
 	mock.instances[name] = container
@@ -0,0 +1,277 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	apiVersion       = "/1.0"
+	containersPath   = apiVersion + "/containers"
+	profilesPath     = apiVersion + "/profiles"
+	imagesPath       = apiVersion + "/images"
+	imageAliasesPath = imagesPath + "/aliases"
+)
+
+// Client talks to the LXD daemon through its REST API, served on a
+// local unix socket.
+type Client struct {
+	socketPath string
+	http       *http.Client
+}
+
+// NewClient returns a client for the LXD daemon listening on the
+// given unix socket. No connection is made until the first request.
+func NewClient(socketPath string) *Client {
+	dial := func(_, _ string) (net.Conn, error) {
+		return net.Dial("unix", socketPath)
+	}
+	return &Client{
+		socketPath: socketPath,
+		http:       &http.Client{Transport: &http.Transport{Dial: dial}},
+	}
+}
+
+// response is the envelope of every LXD API response.
+type response struct {
+	Type      string          `json:"type"`
+	Operation string          `json:"operation"`
+	Error     string          `json:"error"`
+	ErrorCode int             `json:"error_code"`
+	Metadata  json.RawMessage `json:"metadata"`
+}
+
+// operation describes the outcome of an asynchronous request.
+type operation struct {
+	Status     string            `json:"status"`
+	StatusCode int               `json:"status_code"`
+	Err        string            `json:"err"`
+	Metadata   map[string]string `json:"metadata"`
+}
+
+// call sends a request to the daemon and decodes its response
+// envelope. Error responses are returned as errors, with 404s
+// satisfying errors.IsNotFound.
+func (c *Client) call(method, path string, body interface{}) (*response, error) {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	// The host part is ignored, as we always dial the socket.
+	req, err := http.NewRequest(method, "http://lxd"+path, &reqBody)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	httpResp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to LXD")
+	}
+	defer httpResp.Body.Close()
+
+	var resp response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, errors.Annotatef(err, "cannot decode LXD response to %s %s", method, path)
+	}
+	if resp.Type == "error" {
+		if resp.ErrorCode == http.StatusNotFound {
+			return nil, errors.NewNotFound(nil, resp.Error)
+		}
+		return nil, errors.Errorf("LXD %s %s: %s", method, path, resp.Error)
+	}
+	return &resp, nil
+}
+
+// callAndWait sends a request and, if the daemon handles it
+// asynchronously, waits for the resulting operation to finish.
+func (c *Client) callAndWait(method, path string, body interface{}) (*operation, error) {
+	resp, err := c.call(method, path, body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.Type != "async" {
+		return &operation{}, nil
+	}
+	resp, err = c.call("GET", resp.Operation+"/wait", nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var op operation
+	if err := json.Unmarshal(resp.Metadata, &op); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if op.StatusCode >= http.StatusBadRequest {
+		return nil, errors.Errorf("LXD %s %s failed: %s", method, path, op.Err)
+	}
+	return &op, nil
+}
+
+// get sends a GET request and decodes the response metadata into
+// result.
+func (c *Client) get(path string, result interface{}) error {
+	resp, err := c.call("GET", path, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(resp.Metadata, result))
+}
+
+// ImageAlias returns the fingerprint of the image with the given
+// alias, or an error satisfying errors.IsNotFound.
+func (c *Client) ImageAlias(alias string) (string, error) {
+	var result struct {
+		Target string `json:"target"`
+	}
+	if err := c.get(imageAliasesPath+"/"+alias, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.Target, nil
+}
+
+// CopyRemoteImage pulls the image known as remoteAlias from the given
+// simplestreams server into the local image store, and names it alias.
+func (c *Client) CopyRemoteImage(server, remoteAlias, alias string) error {
+	op, err := c.callAndWait("POST", imagesPath, map[string]interface{}{
+		"source": map[string]string{
+			"type":     "image",
+			"mode":     "pull",
+			"server":   server,
+			"protocol": "simplestreams",
+			"alias":    remoteAlias,
+		},
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot copy image %q from %q", remoteAlias, server)
+	}
+	fingerprint := op.Metadata["fingerprint"]
+	if fingerprint == "" {
+		return errors.Errorf("no fingerprint for image %q from %q", remoteAlias, server)
+	}
+	_, err = c.call("POST", imageAliasesPath, map[string]string{
+		"name":   alias,
+		"target": fingerprint,
+	})
+	return errors.Annotatef(err, "cannot alias image %q", fingerprint)
+}
+
+// CreateProfile creates a profile with the given config and devices.
+func (c *Client) CreateProfile(name string, config map[string]string, devices map[string]map[string]string) error {
+	_, err := c.call("POST", profilesPath, map[string]interface{}{
+		"name":    name,
+		"config":  config,
+		"devices": devices,
+	})
+	return errors.Annotatef(err, "cannot create profile %q", name)
+}
+
+// DeleteProfile removes the named profile.
+func (c *Client) DeleteProfile(name string) error {
+	_, err := c.call("DELETE", profilesPath+"/"+name, nil)
+	return errors.Trace(err)
+}
+
+// CreateContainer creates, but does not start, a container from the
+// image with the given alias, applying profiles in order.
+func (c *Client) CreateContainer(name, imageAlias string, profiles []string, config map[string]string) error {
+	_, err := c.callAndWait("POST", containersPath, map[string]interface{}{
+		"name":     name,
+		"profiles": profiles,
+		"config":   config,
+		"source": map[string]string{
+			"type":  "image",
+			"alias": imageAlias,
+		},
+	})
+	return errors.Annotatef(err, "cannot create container %q", name)
+}
+
+// StartContainer starts the named container.
+func (c *Client) StartContainer(name string) error {
+	return errors.Trace(c.changeState(name, "start"))
+}
+
+// StopContainer forcibly stops the named container.
+func (c *Client) StopContainer(name string) error {
+	return errors.Trace(c.changeState(name, "stop"))
+}
+
+func (c *Client) changeState(name, action string) error {
+	_, err := c.callAndWait("PUT", containersPath+"/"+name+"/state", map[string]interface{}{
+		"action":  action,
+		"timeout": -1,
+		"force":   action == "stop",
+	})
+	return errors.Annotatef(err, "cannot %s container %q", action, name)
+}
+
+// DeleteContainer removes the named container, which must be stopped.
+func (c *Client) DeleteContainer(name string) error {
+	_, err := c.callAndWait("DELETE", containersPath+"/"+name, nil)
+	return errors.Annotatef(err, "cannot delete container %q", name)
+}
+
+// ContainerNames returns the names of all containers.
+func (c *Client) ContainerNames() ([]string, error) {
+	var urls []string
+	if err := c.get(containersPath, &urls); err != nil {
+		return nil, errors.Trace(err)
+	}
+	names := make([]string, len(urls))
+	for i, url := range urls {
+		names[i] = strings.TrimPrefix(url, containersPath+"/")
+	}
+	return names, nil
+}
+
+// ContainerStatus returns the status of the named container, such as
+// "Running" or "Stopped".
+func (c *Client) ContainerStatus(name string) (string, error) {
+	var result struct {
+		Status string `json:"status"`
+	}
+	if err := c.get(containersPath+"/"+name, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.Status, nil
+}
+
+// ContainerAddresses returns the globally scoped addresses of the
+// named container's network interfaces, excluding loopback.
+func (c *Client) ContainerAddresses(name string) ([]string, error) {
+	var result struct {
+		Network map[string]struct {
+			Addresses []struct {
+				Address string `json:"address"`
+				Scope   string `json:"scope"`
+			} `json:"addresses"`
+		} `json:"network"`
+	}
+	if err := c.get(fmt.Sprintf("%s/%s/state", containersPath, name), &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var addresses []string
+	for device, nic := range result.Network {
+		if device == "lo" {
+			continue
+		}
+		for _, addr := range nic.Addresses {
+			if addr.Scope == "global" {
+				addresses = append(addresses, addr.Address)
+			}
+		}
+	}
+	return addresses, nil
+}
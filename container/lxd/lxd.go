@@ -0,0 +1,316 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package lxd implements container.Manager for LXD containers, talking
+// to the LXD daemon through its REST API.
+package lxd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cloudconfig/containerinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/version"
+)
+
+// ConfigSocketPath, if set in the manager config, overrides the path
+// of the LXD daemon's unix socket.
+const ConfigSocketPath = "lxd-socket"
+
+var (
+	logger = loggo.GetLogger("juju.container.lxd")
+
+	// DefaultSocketPath is where the LXD daemon listens for local
+	// API requests.
+	DefaultSocketPath = "/var/lib/lxd/unix.socket"
+
+	// DefaultLxdBridge is the bridge created by the lxd package.
+	DefaultLxdBridge = "lxdbr0"
+
+	// DefaultImageServer is the simplestreams server images are
+	// pulled from, unless the image stream says otherwise.
+	DefaultImageServer = "https://cloud-images.ubuntu.com/releases"
+
+	// There are some values where it doesn't make sense to go below.
+	MinMemory uint64 = 256 // MB
+	MinCpu    uint64 = 1
+)
+
+// minLXDSeriesVersion is the version of the first Ubuntu series with
+// the lxd package in its archive.
+const minLXDSeriesVersion = "15.04"
+
+// IsLXDSupported reports whether LXD containers can be run on this
+// host: either the LXD daemon is already running, or the host's series
+// is one for which the container initialiser can install the lxd
+// package. It is a variable to allow us to override behaviour in the
+// tests.
+var IsLXDSupported = func() (bool, error) {
+	if _, err := os.Stat(DefaultSocketPath); err == nil {
+		return true, nil
+	} else if !os.IsNotExist(err) {
+		return false, errors.Trace(err)
+	}
+	return seriesSupportsLXD(version.Current.Series)
+}
+
+// seriesSupportsLXD reports whether the lxd package is available for
+// the given series.
+func seriesSupportsLXD(series string) (bool, error) {
+	osType, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if osType != version.Ubuntu {
+		return false, nil
+	}
+	seriesVersion, err := version.SeriesVersion(series)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	// Ubuntu series versions are of the form YY.MM, so they can be
+	// compared as strings.
+	return seriesVersion >= minLXDSeriesVersion, nil
+}
+
+// NewContainerManager returns a manager object that can start and stop
+// LXD containers. The containers that are created are namespaced by
+// the name parameter.
+func NewContainerManager(conf container.ManagerConfig) (container.Manager, error) {
+	name := conf.PopValue(container.ConfigName)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	socketPath := conf.PopValue(ConfigSocketPath)
+	if socketPath == "" {
+		socketPath = DefaultSocketPath
+	}
+	// LXD keeps the container logs itself.
+	conf.PopValue(container.ConfigLogDir)
+	conf.WarnAboutUnused()
+	return &containerManager{
+		name:       name,
+		socketPath: socketPath,
+		client:     NewClient(socketPath),
+	}, nil
+}
+
+// containerManager creates LXD containers through the daemon's REST
+// API. Each container gets its own profile, holding the resource
+// limits derived from its constraints and its network device.
+type containerManager struct {
+	name       string
+	socketPath string
+	client     *Client
+
+	// imageMutex serialises image lookups, so concurrently started
+	// containers don't pull the same image more than once.
+	imageMutex sync.Mutex
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+// CreateContainer is specified on the container.Manager interface.
+func (manager *containerManager) CreateContainer(
+	instanceConfig *instancecfg.InstanceConfig,
+	series string,
+	networkConfig *container.NetworkConfig,
+	storageConfig *container.StorageConfig,
+) (instance.Instance, *instance.HardwareCharacteristics, error) {
+	name := names.NewMachineTag(instanceConfig.MachineId).String()
+	if manager.name != "" {
+		name = fmt.Sprintf("%s-%s", manager.name, name)
+	}
+	instanceConfig.MachineContainerHostname = name
+
+	userData, err := containerinit.CloudInitUserData(instanceConfig, networkConfig)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to generate user data")
+	}
+	hostArch := arch.HostArch()
+	imageAlias, err := manager.ensureImage(series, hostArch, instanceConfig.ImageStream)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	limits := ParseConstraintsToLimits(instanceConfig.Constraints)
+	profileConfig := make(map[string]string)
+	hardware := instance.HardwareCharacteristics{Arch: &hostArch}
+	if limits.CpuCores > 0 {
+		profileConfig["limits.cpu"] = fmt.Sprint(limits.CpuCores)
+		hardware.CpuCores = &limits.CpuCores
+	}
+	if limits.Memory > 0 {
+		profileConfig["limits.memory"] = fmt.Sprintf("%dMB", limits.Memory)
+		hardware.Mem = &limits.Memory
+	}
+	devices := make(map[string]map[string]string)
+	if networkConfig != nil && networkConfig.Device != "" {
+		devices["eth0"] = map[string]string{
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  networkConfig.Device,
+		}
+	}
+	// The profile is named after the container, so it can be found
+	// and removed when the container is destroyed.
+	if err := manager.client.CreateProfile(name, profileConfig, devices); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	logger.Tracef("create the container, constraints: %v", instanceConfig.Constraints)
+	config := map[string]string{
+		"user.user-data": string(userData),
+	}
+	err = manager.client.CreateContainer(name, imageAlias, []string{"default", name}, config)
+	if err == nil {
+		err = manager.client.StartContainer(name)
+		if err != nil {
+			manager.client.DeleteContainer(name)
+		}
+	}
+	if err != nil {
+		if err := manager.client.DeleteProfile(name); err != nil {
+			logger.Warningf("cannot remove profile %q: %v", name, err)
+		}
+		err = errors.Annotate(err, "lxd container creation failed")
+		logger.Infof(err.Error())
+		return nil, nil, err
+	}
+	logger.Tracef("lxd container created")
+	return &lxdInstance{name, manager.client}, &hardware, nil
+}
+
+// ensureImage makes sure the image for the given series and
+// architecture is available locally, pulling it from the image server
+// if not, and returns its local alias.
+func (manager *containerManager) ensureImage(series, imageArch, stream string) (string, error) {
+	server := DefaultImageServer
+	alias := fmt.Sprintf("juju/%s/%s", series, imageArch)
+	if stream != "" && stream != imagemetadata.ReleasedStream {
+		server = imagemetadata.UbuntuCloudImagesURL + "/" + stream
+		alias = fmt.Sprintf("juju/%s/%s/%s", stream, series, imageArch)
+	}
+
+	manager.imageMutex.Lock()
+	defer manager.imageMutex.Unlock()
+	_, err := manager.client.ImageAlias(alias)
+	if err == nil {
+		logger.Debugf("using cached image %q", alias)
+		return alias, nil
+	} else if !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	logger.Infof("pulling image %s/%s from %q", series, imageArch, server)
+	if err := manager.client.CopyRemoteImage(server, series+"/"+imageArch, alias); err != nil {
+		return "", errors.Trace(err)
+	}
+	return alias, nil
+}
+
+// IsInitialized is specified on the container.Manager interface.
+func (manager *containerManager) IsInitialized() bool {
+	_, err := os.Stat(manager.socketPath)
+	return err == nil
+}
+
+// DestroyContainer is specified on the container.Manager interface.
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	status, err := manager.client.ContainerStatus(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if status != "Stopped" {
+		if err := manager.client.StopContainer(name); err != nil {
+			logger.Errorf("failed to stop lxd container: %v", err)
+			return err
+		}
+	}
+	if err := manager.client.DeleteContainer(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := manager.client.DeleteProfile(name); err != nil && !errors.IsNotFound(err) {
+		return errors.Annotatef(err, "cannot remove profile %q", name)
+	}
+	return nil
+}
+
+// ListContainers is specified on the container.Manager interface.
+func (manager *containerManager) ListContainers() (result []instance.Instance, err error) {
+	names, err := manager.client.ContainerNames()
+	if err != nil {
+		logger.Errorf("failed getting all instances: %v", err)
+		return nil, errors.Trace(err)
+	}
+	managerPrefix := fmt.Sprintf("%s-", manager.name)
+	for _, name := range names {
+		// Filter out those not starting with our name.
+		if !strings.HasPrefix(name, managerPrefix) {
+			continue
+		}
+		status, err := manager.client.ContainerStatus(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if status == "Running" {
+			result = append(result, &lxdInstance{name, manager.client})
+		}
+	}
+	return result, nil
+}
+
+// Limits holds the resource limits applied to a container through its
+// profile. Zero values mean no limit.
+type Limits struct {
+	Memory   uint64 // MB
+	CpuCores uint64
+}
+
+// ParseConstraintsToLimits takes a constraints object and returns the
+// matching container limits. Unlike KVM, LXD containers share the host's
+// resources, so nothing is limited unless asked for. Constraints which
+// cannot be applied to a container cause a message to be logged.
+func ParseConstraintsToLimits(cons constraints.Value) Limits {
+	var limits Limits
+	if cons.Mem != nil && *cons.Mem > 0 {
+		limits.Memory = *cons.Mem
+		if limits.Memory < MinMemory {
+			limits.Memory = MinMemory
+		}
+	}
+	if cons.CpuCores != nil && *cons.CpuCores > 0 {
+		limits.CpuCores = *cons.CpuCores
+		if limits.CpuCores < MinCpu {
+			limits.CpuCores = MinCpu
+		}
+	}
+	if cons.Arch != nil {
+		logger.Infof("arch constraint of %q being ignored as not supported", *cons.Arch)
+	}
+	if cons.RootDisk != nil {
+		logger.Infof("root-disk constraint of %v being ignored as not supported", *cons.RootDisk)
+	}
+	if cons.Container != nil {
+		logger.Infof("container constraint of %q being ignored as not supported", *cons.Container)
+	}
+	if cons.CpuPower != nil {
+		logger.Infof("cpu-power constraint of %v being ignored as not supported", *cons.CpuPower)
+	}
+	if cons.Tags != nil {
+		logger.Infof("tags constraint of %q being ignored as not supported", strings.Join(*cons.Tags, ","))
+	}
+	return limits
+}
@@ -0,0 +1,241 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/version"
+)
+
+type LXDSuite struct {
+	lxdtesting.TestSuite
+	manager container.Manager
+}
+
+var _ = gc.Suite(&LXDSuite{})
+
+func (s *LXDSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	s.PatchValue(&arch.HostArch, func() string { return arch.AMD64 })
+	var err error
+	s.manager, err = lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: "test"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*LXDSuite) TestManagerNameNeeded(c *gc.C) {
+	manager, err := lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: ""})
+	c.Assert(err, gc.ErrorMatches, "name is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (*LXDSuite) TestManagerWarnsAboutUnknownOption(c *gc.C) {
+	_, err := lxd.NewContainerManager(container.ManagerConfig{
+		container.ConfigName: "BillyBatson",
+		"shazam":             "Captain Marvel",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, `WARNING juju.container unused config option: "shazam" -> "Captain Marvel"`)
+}
+
+func (s *LXDSuite) TestIsInitialized(c *gc.C) {
+	c.Assert(s.manager.IsInitialized(), jc.IsTrue)
+
+	manager, err := lxd.NewContainerManager(container.ManagerConfig{
+		container.ConfigName: "test",
+		lxd.ConfigSocketPath: filepath.Join(c.MkDir(), "missing.socket"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(manager.IsInitialized(), jc.IsFalse)
+}
+
+func (s *LXDSuite) TestIsLXDSupported(c *gc.C) {
+	supported, err := lxd.IsLXDSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+
+	// Without a running daemon, support depends on whether the
+	// lxd package can be installed for the host series.
+	err = os.Remove(s.Server.SocketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	for series, expected := range map[string]bool{
+		"precise":   false,
+		"trusty":    false,
+		"vivid":     true,
+		"wily":      true,
+		"win2012r2": false,
+		"centos7":   false,
+	} {
+		c.Logf("series %q", series)
+		s.PatchValue(&version.Current.Series, series)
+		supported, err = lxd.IsLXDSupported()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(supported, gc.Equals, expected)
+	}
+}
+
+func (s *LXDSuite) TestListInitiallyEmpty(c *gc.C) {
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LXDSuite) TestListMatchesManagerNameAndRunning(c *gc.C) {
+	s.Server.AddContainer("test-match1", "Running")
+	s.Server.AddContainer("test-match2", "Running")
+	s.Server.AddContainer("test-stopped", "Stopped")
+	s.Server.AddContainer("testNoMatch", "Running")
+	s.Server.AddContainer("other", "Running")
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	expectedIds := []instance.Id{"test-match1", "test-match2"}
+	ids := []instance.Id{containers[0].Id(), containers[1].Id()}
+	c.Assert(ids, jc.SameContents, expectedIds)
+}
+
+func (s *LXDSuite) TestCreateContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	c.Assert(inst.Id(), gc.Equals, instance.Id("test-machine-1-lxd-0"))
+	c.Assert(inst.Status(), gc.Equals, "running")
+
+	lxdContainer, ok := s.Server.Container("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(lxdContainer.Image, gc.Equals, "juju/quantal/amd64")
+	c.Assert(lxdContainer.Profiles, jc.DeepEquals, []string{"default", "test-machine-1-lxd-0"})
+	c.Assert(lxdContainer.Config["user.user-data"], jc.HasPrefix, "#cloud-config\n")
+
+	profile, ok := s.Server.Profile("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(profile.Config, gc.HasLen, 0)
+	c.Assert(profile.Devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {"type": "nic", "nictype": "bridged", "parent": "nic42"},
+	})
+}
+
+func (s *LXDSuite) TestCreateContainerCachesImage(c *gc.C) {
+	containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	containertesting.CreateContainer(c, s.manager, "1/lxd/1")
+	c.Assert(s.Server.Pulls(), jc.DeepEquals, []string{
+		lxd.DefaultImageServer + " quantal/amd64",
+	})
+	c.Assert(s.Server.ImageAliases(), jc.DeepEquals, []string{"juju/quantal/amd64"})
+}
+
+func (s *LXDSuite) TestCreateContainerUtilizesDailySimpleStream(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Config, err = config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.ImageStream = "daily"
+	containertesting.CreateContainerWithMachineConfig(c, s.manager, instanceConfig)
+
+	c.Assert(s.Server.Pulls(), jc.DeepEquals, []string{
+		"http://cloud-images.ubuntu.com/daily quantal/amd64",
+	})
+	c.Assert(s.Server.ImageAliases(), jc.DeepEquals, []string{"juju/daily/quantal/amd64"})
+}
+
+func (s *LXDSuite) TestCreateContainerAppliesConstraints(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Config, err = config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Constraints = constraints.MustParse("mem=2G cpu-cores=2 root-disk=10G")
+	networkConfig := container.BridgeNetworkConfig("nic42", 0, nil)
+	_, hardware, err := s.manager.CreateContainer(
+		instanceConfig, "quantal", networkConfig, &container.StorageConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hardware.String(), gc.Equals, "arch=amd64 cpu-cores=2 mem=2048M")
+
+	profile, ok := s.Server.Profile("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(profile.Config, jc.DeepEquals, map[string]string{
+		"limits.cpu":    "2",
+		"limits.memory": "2048MB",
+	})
+	c.Assert(c.GetTestLog(), jc.Contains, "root-disk constraint of 10240 being ignored as not supported")
+}
+
+func (s *LXDSuite) TestCreateContainerFailureRemovesProfile(c *gc.C) {
+	s.Server.SetFailure("PUT", "/1.0/containers/test-machine-1-lxd-0/state", "boom")
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/lxd/0")
+	c.Assert(err, gc.ErrorMatches, `.*lxd container creation failed: cannot start container "test-machine-1-lxd-0": .*boom`)
+
+	_, ok := s.Server.Container("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsFalse)
+	_, ok = s.Server.Profile("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *LXDSuite) TestDestroyContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+
+	err := s.manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.Server.Container("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsFalse)
+	_, ok = s.Server.Profile("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsFalse)
+
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LXDSuite) TestDestroyUnknownContainer(c *gc.C) {
+	err := s.manager.DestroyContainer("test-machine-9")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *LXDSuite) TestInstanceAddresses(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	s.Server.SetAddresses("test-machine-1-lxd-0", "10.0.8.12", "fd00::12")
+
+	addresses, err := inst.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.SameContents, network.NewAddresses("10.0.8.12", "fd00::12"))
+}
+
+func (s *LXDSuite) TestUnreachableDaemon(c *gc.C) {
+	s.Server.Close()
+	_, err := s.manager.ListContainers()
+	c.Assert(err, gc.ErrorMatches, "cannot connect to LXD: .*")
+}
+
+func (s *LXDSuite) TestParseConstraintsToLimits(c *gc.C) {
+	for i, test := range []struct {
+		cons     string
+		expected lxd.Limits
+	}{{
+		cons: "",
+	}, {
+		cons:     "mem=4G",
+		expected: lxd.Limits{Memory: 4096},
+	}, {
+		cons:     "mem=100M cpu-cores=4",
+		expected: lxd.Limits{Memory: lxd.MinMemory, CpuCores: 4},
+	}, {
+		cons: "arch=armhf container=lxd tags=foo",
+	}} {
+		c.Logf("test %d: %q", i, test.cons)
+		limits := lxd.ParseConstraintsToLimits(constraints.MustParse(test.cons))
+		c.Check(limits, gc.Equals, test.expected)
+	}
+}
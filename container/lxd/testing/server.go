@@ -0,0 +1,415 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/testing"
+)
+
+// Container describes a container known to the fake LXD server.
+type Container struct {
+	Name     string
+	Status   string
+	Image    string
+	Profiles []string
+	Config   map[string]string
+}
+
+// Profile describes a profile known to the fake LXD server.
+type Profile struct {
+	Config  map[string]string
+	Devices map[string]map[string]string
+}
+
+// Server is a fake LXD daemon, serving the subset of the REST API used
+// by the lxd package on a unix socket. All state is kept in memory.
+type Server struct {
+	SocketPath string
+
+	httpServer *httptest.Server
+	closeOnce  sync.Once
+
+	mu         sync.Mutex
+	containers map[string]*Container
+	profiles   map[string]Profile
+	aliases    map[string]string
+	pulls      []string
+	addresses  map[string][]string
+	failures   map[string]string
+	operations map[string]map[string]string
+}
+
+// NewServer starts a fake LXD server listening on a unix socket in the
+// given directory.
+func NewServer(dir string) (*Server, error) {
+	socketPath := filepath.Join(dir, "unix.socket")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		SocketPath: socketPath,
+		containers: make(map[string]*Container),
+		profiles:   make(map[string]Profile),
+		aliases:    make(map[string]string),
+		addresses:  make(map[string][]string),
+		failures:   make(map[string]string),
+		operations: make(map[string]map[string]string),
+	}
+	s.httpServer = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.httpServer.Listener.Close()
+	s.httpServer.Listener = listener
+	s.httpServer.Start()
+	return s, nil
+}
+
+// Close stops the server. It is safe to call more than once.
+func (s *Server) Close() {
+	s.closeOnce.Do(s.httpServer.Close)
+}
+
+// Container returns the named container and whether it exists.
+func (s *Server) Container(name string) (Container, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if container, ok := s.containers[name]; ok {
+		return *container, true
+	}
+	return Container{}, false
+}
+
+// AddContainer adds a container with the given status.
+func (s *Server) AddContainer(name, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.containers[name] = &Container{Name: name, Status: status}
+}
+
+// Profile returns the named profile and whether it exists.
+func (s *Server) Profile(name string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, ok := s.profiles[name]
+	return profile, ok
+}
+
+// ImageAliases returns the aliases of all local images.
+func (s *Server) ImageAliases() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var aliases []string
+	for alias := range s.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Pulls returns the "<server> <alias>" of each image pulled from a
+// remote server, in order.
+func (s *Server) Pulls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.pulls...)
+}
+
+// SetAddresses sets the global addresses reported for the named
+// container's eth0 device.
+func (s *Server) SetAddresses(name string, addresses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addresses[name] = addresses
+}
+
+// SetFailure makes requests matching the given method and path fail
+// with message.
+func (s *Server) SetFailure(method, path, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method+" "+path] = message
+}
+
+type apiError struct {
+	code    int
+	message string
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result interface{}
+	var apiErr *apiError
+	if message, ok := s.failures[r.Method+" "+r.URL.Path]; ok {
+		apiErr = &apiError{http.StatusInternalServerError, message}
+	} else {
+		result, apiErr = s.handle(r)
+	}
+	resp := map[string]interface{}{}
+	switch {
+	case apiErr != nil:
+		w.WriteHeader(apiErr.code)
+		resp["type"] = "error"
+		resp["error"] = apiErr.message
+		resp["error_code"] = apiErr.code
+	case result == nil:
+		// Every change to the server's state is handled as an
+		// operation which has already finished.
+		resp["type"] = "async"
+		resp["operation"] = s.newOperation(nil)
+	default:
+		if op, ok := result.(operationResult); ok {
+			resp["type"] = "async"
+			resp["operation"] = s.newOperation(op)
+		} else {
+			resp["type"] = "sync"
+			resp["metadata"] = result
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// operationResult is returned by handlers of asynchronous requests
+// whose operations carry metadata.
+type operationResult map[string]string
+
+func (s *Server) newOperation(metadata map[string]string) string {
+	id := fmt.Sprintf("/1.0/operations/%d", len(s.operations))
+	s.operations[id] = metadata
+	return id
+}
+
+func notFound(what string) *apiError {
+	return &apiError{http.StatusNotFound, what + " not found"}
+}
+
+func (s *Server) handle(r *http.Request) (interface{}, *apiError) {
+	var body map[string]interface{}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "1.0" {
+		return nil, notFound("path")
+	}
+	parts = parts[1:]
+	switch {
+	case parts[0] == "operations" && len(parts) == 3 && parts[2] == "wait":
+		id := "/1.0/operations/" + parts[1]
+		metadata, ok := s.operations[id]
+		if !ok {
+			return nil, notFound("operation")
+		}
+		return map[string]interface{}{
+			"status":      "Success",
+			"status_code": http.StatusOK,
+			"metadata":    metadata,
+		}, nil
+	case parts[0] == "images":
+		return s.handleImages(r.Method, parts[1:], body)
+	case parts[0] == "profiles":
+		return s.handleProfiles(r.Method, parts[1:], body)
+	case parts[0] == "containers":
+		return s.handleContainers(r.Method, parts[1:], body)
+	}
+	return nil, notFound("path")
+}
+
+func (s *Server) handleImages(method string, parts []string, body map[string]interface{}) (interface{}, *apiError) {
+	switch {
+	case method == "POST" && len(parts) == 0:
+		source, _ := body["source"].(map[string]interface{})
+		server, _ := source["server"].(string)
+		alias, _ := source["alias"].(string)
+		s.pulls = append(s.pulls, server+" "+alias)
+		fingerprint := fmt.Sprintf("%x", sha256.Sum256([]byte(server+alias)))
+		return operationResult{"fingerprint": fingerprint}, nil
+	case method == "POST" && len(parts) == 1 && parts[0] == "aliases":
+		name, _ := body["name"].(string)
+		target, _ := body["target"].(string)
+		s.aliases[name] = target
+		return map[string]string{}, nil
+	case method == "GET" && len(parts) > 1 && parts[0] == "aliases":
+		name := strings.Join(parts[1:], "/")
+		target, ok := s.aliases[name]
+		if !ok {
+			return nil, notFound("alias")
+		}
+		return map[string]string{"name": name, "target": target}, nil
+	}
+	return nil, notFound("path")
+}
+
+func (s *Server) handleProfiles(method string, parts []string, body map[string]interface{}) (interface{}, *apiError) {
+	switch {
+	case method == "POST" && len(parts) == 0:
+		name, _ := body["name"].(string)
+		if _, ok := s.profiles[name]; ok {
+			return nil, &apiError{http.StatusConflict, "profile already exists"}
+		}
+		profile := Profile{
+			Config:  make(map[string]string),
+			Devices: make(map[string]map[string]string),
+		}
+		config, _ := body["config"].(map[string]interface{})
+		for key, value := range config {
+			profile.Config[key] = fmt.Sprint(value)
+		}
+		devices, _ := body["devices"].(map[string]interface{})
+		for device, settings := range devices {
+			profile.Devices[device] = make(map[string]string)
+			settings, _ := settings.(map[string]interface{})
+			for key, value := range settings {
+				profile.Devices[device][key] = fmt.Sprint(value)
+			}
+		}
+		s.profiles[name] = profile
+		return map[string]string{}, nil
+	case method == "DELETE" && len(parts) == 1:
+		if _, ok := s.profiles[parts[0]]; !ok {
+			return nil, notFound("profile")
+		}
+		delete(s.profiles, parts[0])
+		return map[string]string{}, nil
+	}
+	return nil, notFound("path")
+}
+
+func (s *Server) handleContainers(method string, parts []string, body map[string]interface{}) (interface{}, *apiError) {
+	if len(parts) == 0 {
+		switch method {
+		case "GET":
+			urls := []string{}
+			for name := range s.containers {
+				urls = append(urls, "/1.0/containers/"+name)
+			}
+			sort.Strings(urls)
+			return urls, nil
+		case "POST":
+			return s.createContainer(body)
+		}
+		return nil, notFound("path")
+	}
+	container, ok := s.containers[parts[0]]
+	if !ok {
+		return nil, notFound("container")
+	}
+	switch {
+	case method == "GET" && len(parts) == 1:
+		return map[string]interface{}{
+			"name":     container.Name,
+			"status":   container.Status,
+			"profiles": container.Profiles,
+			"config":   container.Config,
+		}, nil
+	case method == "DELETE" && len(parts) == 1:
+		if container.Status != "Stopped" {
+			return nil, &apiError{http.StatusBadRequest, "container is running"}
+		}
+		delete(s.containers, container.Name)
+		return nil, nil
+	case method == "GET" && len(parts) == 2 && parts[1] == "state":
+		var addresses []map[string]string
+		for _, addr := range s.addresses[container.Name] {
+			addresses = append(addresses, map[string]string{
+				"address": addr,
+				"scope":   "global",
+			})
+		}
+		return map[string]interface{}{
+			"status": container.Status,
+			"network": map[string]interface{}{
+				"lo": map[string]interface{}{
+					"addresses": []map[string]string{{"address": "127.0.0.1", "scope": "local"}},
+				},
+				"eth0": map[string]interface{}{
+					"addresses": addresses,
+				},
+			},
+		}, nil
+	case method == "PUT" && len(parts) == 2 && parts[1] == "state":
+		switch body["action"] {
+		case "start":
+			container.Status = "Running"
+		case "stop":
+			container.Status = "Stopped"
+		default:
+			return nil, &apiError{http.StatusBadRequest, "unknown action"}
+		}
+		return nil, nil
+	}
+	return nil, notFound("path")
+}
+
+func (s *Server) createContainer(body map[string]interface{}) (interface{}, *apiError) {
+	name, _ := body["name"].(string)
+	if _, ok := s.containers[name]; ok {
+		return nil, &apiError{http.StatusConflict, "container already exists"}
+	}
+	source, _ := body["source"].(map[string]interface{})
+	alias, _ := source["alias"].(string)
+	if _, ok := s.aliases[alias]; !ok {
+		return nil, notFound("image")
+	}
+	container := &Container{
+		Name:   name,
+		Status: "Stopped",
+		Image:  alias,
+		Config: make(map[string]string),
+	}
+	profiles, _ := body["profiles"].([]interface{})
+	for _, profile := range profiles {
+		profile := fmt.Sprint(profile)
+		if _, ok := s.profiles[profile]; !ok && profile != "default" {
+			return nil, notFound("profile")
+		}
+		container.Profiles = append(container.Profiles, profile)
+	}
+	config, _ := body["config"].(map[string]interface{})
+	for key, value := range config {
+		container.Config[key] = fmt.Sprint(value)
+	}
+	s.containers[name] = container
+	return nil, nil
+}
+
+// TestSuite runs a fake LXD server for each test, and points the lxd
+// package at it.
+type TestSuite struct {
+	testing.BaseSuite
+	Server *Server
+}
+
+func (s *TestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	server, err := NewServer(c.MkDir())
+	c.Assert(err, gc.IsNil)
+	s.Server = server
+	s.PatchValue(&lxd.DefaultSocketPath, server.SocketPath)
+}
+
+func (s *TestSuite) TearDownTest(c *gc.C) {
+	if s.Server != nil {
+		s.Server.Close()
+		s.Server = nil
+	}
+	s.BaseSuite.TearDownTest(c)
+}
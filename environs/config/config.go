@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -171,6 +172,11 @@ const (
 	// interfaces created for LXC containers. See also bug #1442257.
 	LXCDefaultMTU = "lxc-default-mtu"
 
+	// MetricsSenderKey is an optional URL identifying where the state
+	// server sends metrics collected from units, in place of the
+	// default metrics collection service.
+	MetricsSenderKey = "metrics-sender"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -195,6 +201,11 @@ const (
 	// machine worker not to discover any machine addresses
 	// on start up.
 	IgnoreMachineAddresses = "ignore-machine-addresses"
+
+	// AutoImportSubnets, when true, will cause the state servers
+	// to periodically import any subnets and spaces discovered
+	// in the provider which are not yet known to Juju.
+	AutoImportSubnets = "auto-import-subnets"
 )
 
 // ParseHarvestMode parses description of harvesting method and
@@ -634,6 +645,12 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.MetricsSender(); ok {
+		if err := validateMetricsSender(v); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	// Check LXCDefaultMTU is a positive integer, when set.
 	if lxcDefaultMTU, ok := cfg.LXCDefaultMTU(); ok && lxcDefaultMTU < 0 {
 		return errors.Errorf("%s: expected positive integer, got %v", LXCDefaultMTU, lxcDefaultMTU)
@@ -1029,9 +1046,18 @@ func (c *Config) Development() bool {
 }
 
 // PreferIPv6 returns whether IPv6 addresses for API endpoints and
-// machines will be preferred (when available) over IPv4.
+// machines will be preferred (when available) over IPv4. It is always
+// true for IPv6-only environments.
 func (c *Config) PreferIPv6() bool {
 	v, _ := c.defined["prefer-ipv6"].(bool)
+	return v || c.IPv6Only()
+}
+
+// IPv6Only returns whether only IPv6 addresses (and hostnames) are
+// usable for API endpoints, machines, mongo replica set members and
+// relations, with IPv4 addresses ignored entirely.
+func (c *Config) IPv6Only() bool {
+	v, _ := c.defined["ipv6-only"].(bool)
 	return v
 }
 
@@ -1149,6 +1175,13 @@ func (c *Config) IgnoreMachineAddresses() (bool, bool) {
 	return v, ok
 }
 
+// AutoImportSubnets reports whether subnets and spaces discovered
+// in the provider are imported automatically.
+func (c *Config) AutoImportSubnets() (bool, bool) {
+	v, ok := c.defined[AutoImportSubnets].(bool)
+	return v, ok
+}
+
 // StorageDefaultBlockSource returns the default block storage
 // source for the environment.
 func (c *Config) StorageDefaultBlockSource() (string, bool) {
@@ -1156,6 +1189,13 @@ func (c *Config) StorageDefaultBlockSource() (string, bool) {
 	return bs, bs != ""
 }
 
+// MetricsSender returns the URL of the sink to which metrics collected
+// from units are sent, and whether it has been set.
+func (c *Config) MetricsSender() (string, bool) {
+	v := c.asString(MetricsSenderKey)
+	return v, v != ""
+}
+
 // AllowLXCLoopMounts returns whether loop devices are allowed
 // to be mounted inside lxc containers.
 func (c *Config) AllowLXCLoopMounts() (bool, bool) {
@@ -1175,6 +1215,31 @@ func (c *Config) ResourceTags() (map[string]string, bool) {
 	return tags, tags != nil
 }
 
+// validateMetricsSender checks that the metrics sender URL
+// has a supported scheme, and a host or path as required.
+func validateMetricsSender(senderURL string) error {
+	u, err := url.Parse(senderURL)
+	if err != nil {
+		return errors.Annotatef(err, "invalid %s", MetricsSenderKey)
+	}
+	switch u.Scheme {
+	case "http", "https", "statsd", "graphite":
+		if u.Host == "" {
+			return errors.Errorf("%s: URL %q must specify a host", MetricsSenderKey, senderURL)
+		}
+	case "file":
+		if u.Path == "" {
+			return errors.Errorf("%s: URL %q must specify a file", MetricsSenderKey, senderURL)
+		}
+	default:
+		return errors.Errorf(
+			"%s: URL %q must have scheme http, https, file, statsd or graphite",
+			MetricsSenderKey, senderURL,
+		)
+	}
+	return nil
+}
+
 func (c *Config) resourceTags() (map[string]string, error) {
 	v, ok := c.defined[ResourceTagsKey].(map[string]string)
 	if !ok {
@@ -1270,10 +1335,12 @@ var alwaysOptional = schema.Defaults{
 	LXCDefaultMTU:                schema.Omit,
 	"disable-network-management": schema.Omit,
 	IgnoreMachineAddresses:       schema.Omit,
+	AutoImportSubnets:            schema.Omit,
 	AgentStreamKey:               schema.Omit,
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	AllowLXCLoopMounts:           false,
 	ResourceTagsKey:              schema.Omit,
+	MetricsSenderKey:             schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1311,6 +1378,7 @@ var alwaysOptional = schema.Defaults{
 	"proxy-ssh":                false,
 	"lxc-clone-aufs":           false,
 	"prefer-ipv6":              false,
+	"ipv6-only":                schema.Omit,
 	"enable-os-refresh-update": schema.Omit,
 	"enable-os-upgrade":        schema.Omit,
 
@@ -1342,6 +1410,7 @@ func allDefaults() schema.Defaults {
 		"prefer-ipv6":                false,
 		"disable-network-management": false,
 		IgnoreMachineAddresses:       false,
+		AutoImportSubnets:            false,
 		SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	}
 	for attr, val := range alwaysOptional {
@@ -1386,6 +1455,7 @@ var immutableAttributes = []string{
 	"lxc-clone-aufs",
 	"syslog-port",
 	"prefer-ipv6",
+	"ipv6-only",
 }
 
 var (
@@ -1656,6 +1726,11 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
+	AutoImportSubnets: {
+		Description: "Whether subnets and spaces discovered in the provider should be imported automatically",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 	"enable-os-refresh-update": {
 		Description: `Whether newly provisioned instances should run their respective OS's update capability.`,
 		Type:        environschema.Tbool,
@@ -1734,6 +1809,11 @@ global or per instance security groups.`,
 		Immutable:   true,
 		Group:       environschema.EnvironGroup,
 	},
+	MetricsSenderKey: {
+		Description: `The URL to which metrics collected from units are sent, in place of the metrics collection service: an http(s) endpoint, a file:// path on the state server, or a statsd:// or graphite:// listener`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	LxcUseClone: {
 		Description: `Whether the LXC provisioner should create a template and use cloning to speed up container provisioning. (deprecated by lxc-clone)`,
 		Type:        environschema.Tbool,
@@ -1756,6 +1836,12 @@ global or per instance security groups.`,
 		Immutable:   true,
 		Group:       environschema.EnvironGroup,
 	},
+	"ipv6-only": {
+		Description: `Whether to use only IPv6 addresses for API endpoints, machines and relations, ignoring any IPv4 addresses`,
+		Type:        environschema.Tbool,
+		Immutable:   true,
+		Group:       environschema.EnvironGroup,
+	},
 	ProvisionerHarvestModeKey: {
 		// default: destroyed, but also depends on current setting of ProvisionerSafeModeKey
 		Description: "What to do with unknown machines. See https://jujucharms.com/docs/stable/config-general#juju-lifecycle-and-harvesting (default destroyed)",
@@ -257,6 +257,32 @@ var configTests = []configTest{
 			"lxc-default-mtu": -42,
 		},
 		err: `lxc-default-mtu: expected positive integer, got -42`,
+	}, {
+		about:       "Metrics sender set explicitly",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"metrics-sender": "statsd://localhost:8125/metrics",
+		},
+	}, {
+		about:       "Metrics sender invalid (unknown scheme)",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"metrics-sender": "ftp://localhost/metrics",
+		},
+		err: `metrics-sender: URL "ftp://localhost/metrics" must have scheme http, https, file, statsd or graphite`,
+	}, {
+		about:       "Metrics sender invalid (no host)",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"metrics-sender": "http:///metrics",
+		},
+		err: `metrics-sender: URL "http:///metrics" must specify a host`,
 	}, {
 		about:       "CA cert & key from path",
 		useDefaults: config.UseDefaults,
@@ -430,6 +456,23 @@ var configTests = []configTest{
 			"name": "my-name",
 			"ignore-machine-addresses": true,
 		},
+	}, {
+		about:       "Invalid auto-import-subnets flag",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"auto-import-subnets": "invalid",
+		},
+		err: `auto-import-subnets: expected bool, got string\("invalid"\)`,
+	}, {
+		about:       "auto-import-subnets on",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"auto-import-subnets": true,
+		},
 	}, {
 		about:       "set-numa-control-policy on",
 		useDefaults: config.UseDefaults,
@@ -1370,6 +1413,14 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		c.Assert(useLxcCloneAufs, jc.IsFalse)
 	}
 
+	metricsSender, hasMetricsSender := cfg.MetricsSender()
+	if v, ok := test.attrs["metrics-sender"]; ok {
+		c.Assert(metricsSender, gc.Equals, v)
+		c.Assert(hasMetricsSender, jc.IsTrue)
+	} else {
+		c.Assert(hasMetricsSender, jc.IsFalse)
+	}
+
 	resourceTags, cfgHasResourceTags := cfg.ResourceTags()
 	if _, ok := test.attrs["resource-tags"]; ok {
 		c.Assert(cfgHasResourceTags, jc.IsTrue)
@@ -1543,6 +1594,11 @@ var validationTests = []validationTest{{
 	old:   testing.Attrs{"prefer-ipv6": false},
 	new:   testing.Attrs{"prefer-ipv6": true},
 	err:   `cannot change prefer-ipv6 from false to true`,
+}, {
+	about: "Cannot change ipv6-only",
+	old:   testing.Attrs{"ipv6-only": true},
+	new:   testing.Attrs{"ipv6-only": false},
+	err:   `cannot change ipv6-only from true to false`,
 }, {
 	about: "Can change uuid from unset to set",
 	new:   testing.Attrs{"uuid": "dcfbdb4a-bca2-49ad-aa7c-f011424e0fe4"},
@@ -1701,6 +1757,17 @@ func (s *ConfigSuite) TestLoggingConfigFromEnvironment(c *gc.C) {
 	c.Assert(config.LoggingConfig(), gc.Equals, "<root>=INFO;unit=DEBUG")
 }
 
+func (s *ConfigSuite) TestIPv6Only(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, nil)
+	c.Assert(config.IPv6Only(), jc.IsFalse)
+	c.Assert(config.PreferIPv6(), jc.IsFalse)
+
+	config = newTestConfig(c, testing.Attrs{"ipv6-only": true})
+	c.Assert(config.IPv6Only(), jc.IsTrue)
+	c.Assert(config.PreferIPv6(), jc.IsTrue)
+}
+
 func (s *ConfigSuite) TestProxyValuesWithFallback(c *gc.C) {
 	s.addJujuFiles(c)
 
@@ -19,7 +19,9 @@ type Networking interface {
 	AllocateAddress(instId instance.Id, subnetId network.Id, addr network.Address, macAddress, hostname string) error
 
 	// ReleaseAddress releases a specific address previously allocated with
-	// AllocateAddress.
+	// AllocateAddress. Unlike AllocateAddress, it must not depend on the
+	// AddressAllocation feature flag, so that leaked addresses can always
+	// be released.
 	ReleaseAddress(instId instance.Id, subnetId network.Id, addr network.Address, macAddress string) error
 
 	// Subnets returns basic information about subnets known
@@ -215,6 +215,8 @@ ebs:
   provider: ebs
 loop:
   provider: loop
+nfs:
+  provider: nfs
 rootfs:
   provider: rootfs
 tmpfs:
@@ -231,6 +233,7 @@ block             loop      it=works
 block-persistent  ebs       persistent=true
 ebs               ebs       
 loop              loop      
+nfs               nfs       
 rootfs            rootfs    
 tmpfs             tmpfs     
 
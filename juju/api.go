@@ -400,9 +400,12 @@ var maybePreferIPv6 = func(info configstore.EnvironInfo) bool {
 	cfg := info.BootstrapConfig()
 	result := false
 	if cfg != nil {
-		if val, ok := cfg["prefer-ipv6"]; ok {
-			// It's optional, so if missing assume false.
-			result, _ = val.(bool)
+		// Both are optional, so if missing assume false. IPv6-only
+		// environments always prefer IPv6.
+		for _, key := range []string{"prefer-ipv6", "ipv6-only"} {
+			if val, ok := cfg[key].(bool); ok && val {
+				result = true
+			}
 		}
 	}
 	return result
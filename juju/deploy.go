@@ -38,6 +38,12 @@ type DeployServiceParams struct {
 	// TODO(dimitern): Drop this in a follow-up in favor of constraints.
 	Networks []string
 	Storage  map[string]storage.Constraints
+	// EndpointBindings maps relation endpoint names to the names of
+	// the spaces they should be bound to.
+	EndpointBindings map[string]string
+	// ConfigProfile names the environment config profile whose settings
+	// the service uses in place of the charm defaults.
+	ConfigProfile string
 }
 
 // DeployService takes a charm and various parameters and deploys it.
@@ -73,12 +79,16 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 
 	// TODO(dimitern): In a follow-up drop Networks and use spaces
 	// constraints for this when possible.
-	service, err := st.AddService(
+	service, err := st.AddServiceWithOptions(
 		args.ServiceName,
 		args.ServiceOwner,
 		args.Charm,
 		args.Networks,
 		stateStorageConstraints(args.Storage),
+		state.AddServiceOptions{
+			EndpointBindings: args.EndpointBindings,
+			ConfigProfile:    args.ConfigProfile,
+		},
 	)
 	if err != nil {
 		return nil, err
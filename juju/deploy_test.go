@@ -116,6 +116,54 @@ func (s *DeployLocalSuite) TestDeploySettingsError(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *DeployLocalSuite) TestDeployConfigProfile(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{
+		"title":   "production title",
+		"outlook": "sunny",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:    "bob",
+			Charm:          s.charm,
+			ConfigProfile:  "production",
+			ConfigSettings: charm.Settings{"title": "banana cupcakes"},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	profile, err := service.ConfigProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, gc.Equals, "production")
+	s.assertSettings(c, service, charm.Settings{"title": "banana cupcakes"})
+}
+
+func (s *DeployLocalSuite) TestDeployConfigProfileNotFound(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			ConfigProfile: "production",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "bob": config profile "production" not found`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DeployLocalSuite) TestDeployConfigProfileInvalid(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{
+		"skill-level": "lots",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			ConfigProfile: "production",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "bob": config profile "production": option "skill-level" expected int, got "lots"`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *DeployLocalSuite) TestDeployConstraints(c *gc.C) {
 	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=2G"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -58,3 +58,24 @@ type Checker interface {
 	// verify the unit's continued leadership as part of another txn.
 	LeadershipCheck(serviceName, unitName string) Token
 }
+
+// Leader describes a unit's leadership of its service.
+type Leader struct {
+
+	// UnitName is the name of the unit holding leadership.
+	UnitName string
+
+	// Expiry is the latest time at which the unit's leadership might
+	// still be valid, unless extended by a further claim.
+	Expiry time.Time
+}
+
+// Reader exposes the current leadership of all services.
+type Reader interface {
+
+	// Leaders returns the current leader of every service that has one,
+	// keyed on service name. The result is a snapshot, and is not guaranteed
+	// to remain accurate for any length of time; use a Checker if you need
+	// to gate changes on leadership.
+	Leaders() (map[string]Leader, error)
+}
@@ -29,11 +29,18 @@ var (
 // bootstrap, agent startup, before any CLI command).
 var globalPreferIPv6 bool = false
 
-// ResetGobalPreferIPv6 resets the global variable back to the default,
-// and is called only from the isolation test suite to make sure we have
-// a clean environment.
+// globalIPv6Only determines whether IPv4 addresses are ignored
+// entirely when selecting public or internal addresses, and when
+// filtering unusable host/ports. Like globalPreferIPv6, it is set by
+// InitializeFromConfig().
+var globalIPv6Only bool = false
+
+// ResetGobalPreferIPv6 resets the global variables back to the
+// defaults, and is called only from the isolation test suite to make
+// sure we have a clean environment.
 func ResetGobalPreferIPv6() {
 	globalPreferIPv6 = false
+	globalIPv6Only = false
 }
 
 func mustParseCIDR(s string) *net.IPNet {
@@ -207,9 +214,9 @@ func deriveScope(addr Address) Scope {
 // appropriate to display as a publicly accessible endpoint. If there
 // are no suitable addresses, the empty string is returned.
 func SelectPublicAddress(addresses []Address) string {
-	index := bestAddressIndex(len(addresses), globalPreferIPv6, func(i int) Address {
+	index := bestAddressIndex(len(addresses), shouldPreferIPv6(), func(i int) Address {
 		return addresses[i]
-	}, publicMatch)
+	}, ipv6OnlyMatcher(publicMatch))
 	if index < 0 {
 		return ""
 	}
@@ -220,9 +227,9 @@ func SelectPublicAddress(addresses []Address) string {
 // appropriate to display as a publicly accessible endpoint. If there
 // are no suitable candidates, the empty string is returned.
 func SelectPublicHostPort(hps []HostPort) string {
-	index := bestAddressIndex(len(hps), globalPreferIPv6, func(i int) Address {
+	index := bestAddressIndex(len(hps), shouldPreferIPv6(), func(i int) Address {
 		return hps[i].Address
-	}, publicMatch)
+	}, ipv6OnlyMatcher(publicMatch))
 	if index < 0 {
 		return ""
 	}
@@ -233,9 +240,9 @@ func SelectPublicHostPort(hps []HostPort) string {
 // used as an endpoint for juju internal communication. If there are
 // no suitable addresses, the empty string is returned.
 func SelectInternalAddress(addresses []Address, machineLocal bool) string {
-	index := bestAddressIndex(len(addresses), globalPreferIPv6, func(i int) Address {
+	index := bestAddressIndex(len(addresses), shouldPreferIPv6(), func(i int) Address {
 		return addresses[i]
-	}, internalAddressMatcher(machineLocal))
+	}, ipv6OnlyMatcher(internalAddressMatcher(machineLocal)))
 	if index < 0 {
 		return ""
 	}
@@ -247,9 +254,9 @@ func SelectInternalAddress(addresses []Address, machineLocal bool) string {
 // in its NetAddr form. If there are no suitable addresses, the empty
 // string is returned.
 func SelectInternalHostPort(hps []HostPort, machineLocal bool) string {
-	index := bestAddressIndex(len(hps), globalPreferIPv6, func(i int) Address {
+	index := bestAddressIndex(len(hps), shouldPreferIPv6(), func(i int) Address {
 		return hps[i].Address
-	}, internalAddressMatcher(machineLocal))
+	}, ipv6OnlyMatcher(internalAddressMatcher(machineLocal)))
 	if index < 0 {
 		return ""
 	}
@@ -284,6 +291,26 @@ func mayPreferIPv6(addr Address, originalScope scopeMatch, preferIPv6 bool) scop
 	return originalScope
 }
 
+// shouldPreferIPv6 returns whether IPv6 addresses should be preferred
+// when selecting addresses, which is always the case in IPv6-only mode.
+func shouldPreferIPv6() bool {
+	return globalPreferIPv6 || globalIPv6Only
+}
+
+// ipv6OnlyMatcher wraps match so that IPv4 addresses never match when
+// globalIPv6Only is set. Otherwise match is returned unchanged.
+func ipv6OnlyMatcher(match func(Address, bool) scopeMatch) func(Address, bool) scopeMatch {
+	if !globalIPv6Only {
+		return match
+	}
+	return func(addr Address, preferIPv6 bool) scopeMatch {
+		if addr.Type == IPv4Address {
+			return invalidScope
+		}
+		return match(addr, preferIPv6)
+	}
+}
+
 func internalAddressMatcher(machineLocal bool) func(Address, bool) scopeMatch {
 	if machineLocal {
 		return cloudOrMachineLocalMatch
@@ -447,6 +447,32 @@ func (s *AddressSuite) TestSelectInternalAddress(c *gc.C) {
 	}
 }
 
+func (s *AddressSuite) TestSelectAddressesIPv6Only(c *gc.C) {
+	oldValue := network.GetPreferIPv6()
+	defer network.SetPreferIPv6(oldValue)
+	network.SetPreferIPv6(false)
+	network.SetIPv6Only(true)
+	defer network.SetIPv6Only(false)
+
+	addresses := []network.Address{
+		{"8.8.8.8", network.IPv4Address, "public", network.ScopePublic},
+		{"10.0.0.1", network.IPv4Address, "cloud", network.ScopeCloudLocal},
+		{"example.com", network.HostName, "public", network.ScopeUnknown},
+		{"fc00::1", network.IPv6Address, "cloud", network.ScopeCloudLocal},
+		{"2001:db8::1", network.IPv6Address, "public", network.ScopePublic},
+	}
+	c.Check(network.SelectPublicAddress(addresses), gc.Equals, "2001:db8::1")
+	c.Check(network.SelectInternalAddress(addresses, false), gc.Equals, "fc00::1")
+
+	// IPv4 addresses are never selected, even when nothing else is
+	// available.
+	c.Check(network.SelectPublicAddress(addresses[:2]), gc.Equals, "")
+	c.Check(network.SelectInternalAddress(addresses[:2], false), gc.Equals, "")
+
+	// Hostnames are still usable.
+	c.Check(network.SelectPublicAddress(addresses[:3]), gc.Equals, "example.com")
+}
+
 var selectInternalMachineTests = []selectTest{{
 	"first cloud local address is selected",
 	[]network.Address{
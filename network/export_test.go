@@ -12,3 +12,11 @@ func SetPreferIPv6(value bool) {
 func GetPreferIPv6() bool {
 	return globalPreferIPv6
 }
+
+func SetIPv6Only(value bool) {
+	globalIPv6Only = value
+}
+
+func GetIPv6Only() bool {
+	return globalIPv6Only
+}
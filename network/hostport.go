@@ -178,7 +178,7 @@ func ResolveOrDropHostnames(hps []HostPort) []HostPort {
 
 // FilterUnusableHostPorts returns a copy of the given HostPorts after
 // removing any addresses unlikely to be usable (ScopeMachineLocal or
-// ScopeLinkLocal).
+// ScopeLinkLocal, or IPv4 addresses in IPv6-only mode).
 func FilterUnusableHostPorts(hps []HostPort) []HostPort {
 	filtered := make([]HostPort, 0, len(hps))
 	for _, hp := range hps {
@@ -186,6 +186,9 @@ func FilterUnusableHostPorts(hps []HostPort) []HostPort {
 		case ScopeMachineLocal, ScopeLinkLocal:
 			continue
 		}
+		if globalIPv6Only && hp.Type == IPv4Address {
+			continue
+		}
 		filtered = append(filtered, hp)
 	}
 	return filtered
@@ -170,6 +170,38 @@ func (s *HostPortSuite) TestFilterUnusableHostPorts(c *gc.C) {
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *HostPortSuite) TestFilterUnusableHostPortsIPv6Only(c *gc.C) {
+	network.SetIPv6Only(true)
+	defer network.SetIPv6Only(false)
+
+	hps := network.NewHostPorts(1234,
+		"10.0.0.1", "::1", "fe80::1", "localhost", "fc00::1", "2001:db8::1",
+	)
+	expected := network.NewHostPorts(1234, "localhost", "fc00::1", "2001:db8::1")
+	c.Assert(network.FilterUnusableHostPorts(hps), jc.DeepEquals, expected)
+}
+
+func (*HostPortSuite) TestSelectHostPortIPv6Only(c *gc.C) {
+	network.SetIPv6Only(true)
+	defer network.SetIPv6Only(false)
+
+	hps := network.NewHostPorts(17070, "10.0.0.1", "8.8.8.8", "fc00::1", "2001:db8::1")
+	c.Check(network.SelectPublicHostPort(hps), gc.Equals, "[2001:db8::1]:17070")
+	c.Check(network.SelectInternalHostPort(hps, false), gc.Equals, "[fc00::1]:17070")
+}
+
+func (*HostPortSuite) TestParseHostPortsBracketedIPv6(c *gc.C) {
+	hps, err := network.ParseHostPorts("[2001:db8::1]:17070", "[::1]:37017")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hps, jc.DeepEquals, []network.HostPort{
+		{Address: network.NewAddress("2001:db8::1"), Port: 17070},
+		{Address: network.NewAddress("::1"), Port: 37017},
+	})
+	c.Assert(network.HostPortsToStrings(hps), jc.DeepEquals, []string{
+		"[2001:db8::1]:17070", "[::1]:37017",
+	})
+}
+
 func (*HostPortSuite) TestCollapseHostPorts(c *gc.C) {
 	servers := [][]network.HostPort{
 		network.NewHostPorts(1234,
@@ -210,12 +210,23 @@ type PreferIPv6Getter interface {
 	PreferIPv6() bool
 }
 
+// IPv6OnlyGetter will be implemented by both the environment and agent
+// config.
+type IPv6OnlyGetter interface {
+	IPv6Only() bool
+}
+
 // InitializeFromConfig needs to be called once after the environment
 // or agent configuration is available to configure networking
-// settings.
+// settings. If config also implements IPv6OnlyGetter, IPv6-only mode
+// is configured as well.
 func InitializeFromConfig(config PreferIPv6Getter) {
 	globalPreferIPv6 = config.PreferIPv6()
 	logger.Infof("setting prefer-ipv6 to %v", globalPreferIPv6)
+	if ipv6OnlyConfig, ok := config.(IPv6OnlyGetter); ok {
+		globalIPv6Only = ipv6OnlyConfig.IPv6Only()
+		logger.Infof("setting ipv6-only to %v", globalIPv6Only)
+	}
 }
 
 // LXCNetDefaultConfig is the location of the default network config
@@ -102,6 +102,14 @@ func (*NetworkSuite) TestInitializeFromConfig(c *gc.C) {
 	})
 	network.InitializeFromConfig(envConfig)
 	c.Check(network.GetPreferIPv6(), jc.IsFalse)
+	c.Check(network.GetIPv6Only(), jc.IsFalse)
+
+	envConfig = testing.CustomEnvironConfig(c, testing.Attrs{
+		"ipv6-only": true,
+	})
+	network.InitializeFromConfig(envConfig)
+	c.Check(network.GetPreferIPv6(), jc.IsTrue)
+	c.Check(network.GetIPv6Only(), jc.IsTrue)
 }
 
 func (s *NetworkSuite) TestFilterLXCAddresses(c *gc.C) {
@@ -201,6 +201,7 @@ type OpStartInstance struct {
 	Networks         []string
 	NetworkInfo      []network.InterfaceInfo
 	Volumes          []storage.Volume
+	VolumeParams     []storage.VolumeParams
 	Info             *mongo.MongoInfo
 	Jobs             []multiwatcher.MachineJob
 	APIInfo          *api.Info
@@ -993,6 +994,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		Constraints:      args.Constraints,
 		SubnetsToZones:   subnetsToZones,
 		Volumes:          volumes,
+		VolumeParams:     args.Volumes,
 		Instance:         i,
 		Jobs:             args.InstanceConfig.Jobs,
 		Info:             args.InstanceConfig.MongoInfo,
@@ -1107,10 +1109,6 @@ func (env *environ) AllocateAddress(instId instance.Id, subnetId network.Id, add
 // ReleaseAddress releases a specific address previously allocated with
 // AllocateAddress.
 func (env *environ) ReleaseAddress(instId instance.Id, subnetId network.Id, addr network.Address, macAddress string) error {
-	if !environs.AddressAllocationEnabled() {
-		return errors.NotSupportedf("address allocation")
-	}
-
 	if err := env.checkBroken("ReleaseAddress"); err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ package ec2
 
 import (
 	"regexp"
+	"strconv"
 	"sync"
 	"time"
 
@@ -204,9 +205,10 @@ func (e *ebsProvider) VolumeSource(environConfig *config.Config, cfg *storage.Co
 		return nil, errors.NotFoundf("environment UUID")
 	}
 	source := &ebsVolumeSource{
-		ec2:     ec2,
-		envName: environConfig.Name(),
-		envUUID: uuid,
+		ec2:      ec2,
+		modifier: newVolumeModifier(ec2),
+		envName:  environConfig.Name(),
+		envUUID:  uuid,
 	}
 	return source, nil
 }
@@ -217,12 +219,15 @@ func (e *ebsProvider) FilesystemSource(environConfig *config.Config, providerCon
 }
 
 type ebsVolumeSource struct {
-	ec2     *ec2.EC2
-	envName string // non-unique, informational only
-	envUUID string
+	ec2      *ec2.EC2
+	modifier volumeModifier
+	envName  string // non-unique, informational only
+	envUUID  string
 }
 
 var _ storage.VolumeSource = (*ebsVolumeSource)(nil)
+var _ storage.VolumeSnapshotter = (*ebsVolumeSource)(nil)
+var _ storage.VolumeResizer = (*ebsVolumeSource)(nil)
 
 // parseVolumeOptions uses storage volume parameters to make a struct used to create volumes.
 func parseVolumeOptions(size uint64, attrs map[string]interface{}) (_ ec2.CreateVolume, _ error) {
@@ -308,6 +313,7 @@ func (v *ebsVolumeSource) createVolume(p storage.VolumeParams, instances instanc
 	}
 	vol, _ := parseVolumeOptions(p.Size, p.Attributes)
 	vol.AvailZone = inst.AvailZone
+	vol.SnapshotId = p.SnapshotId
 	resp, err := v.ec2.CreateVolume(vol)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
@@ -507,6 +513,98 @@ func (v *ebsVolumeSource) destroyVolume(volumeId string) error {
 	return nil
 }
 
+// CreateVolumeSnapshots is specified on the storage.VolumeSnapshotter
+// interface.
+func (v *ebsVolumeSource) CreateVolumeSnapshots(params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		snapshot, err := v.createVolumeSnapshot(p)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "snapshotting volume %v", p.Volume.Id())
+			continue
+		}
+		results[i].VolumeSnapshot = snapshot
+	}
+	return results, nil
+}
+
+func (v *ebsVolumeSource) createVolumeSnapshot(p storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	description := resourceName(p.Volume, v.envName)
+	resp, err := v.ec2.CreateSnapshot(p.VolumeId, description)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshotId := resp.Snapshot.Id
+
+	resourceTags := make(map[string]string)
+	for k, v := range p.ResourceTags {
+		resourceTags[k] = v
+	}
+	resourceTags[tagName] = description
+	if err := tagResources(v.ec2, resourceTags, snapshotId); err != nil {
+		return nil, errors.Annotate(err, "tagging snapshot")
+	}
+
+	// AWS reports the snapshotted volume's size in GiB.
+	sizeGiB, err := strconv.ParseUint(resp.Snapshot.VolumeSize, 10, 64)
+	if err != nil {
+		return nil, errors.Annotatef(err, "parsing size of snapshot %q", snapshotId)
+	}
+	return &storage.VolumeSnapshot{
+		Volume:     p.Volume,
+		SnapshotId: snapshotId,
+		Size:       gibToMib(sizeGiB),
+	}, nil
+}
+
+// ResizeVolumes is specified on the storage.VolumeResizer interface.
+func (v *ebsVolumeSource) ResizeVolumes(params []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	results := make([]storage.ResizeVolumesResult, len(params))
+	for i, p := range params {
+		info, err := v.resizeVolume(p)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "resizing volume %v", p.Volume.Id())
+			continue
+		}
+		results[i].VolumeInfo = info
+	}
+	return results, nil
+}
+
+// resizeVolume grows the volume to the requested size, rounded up to
+// the nearest GiB. EBS applies the change in the background; the
+// instance sees the new size once the modification is optimizing.
+func (v *ebsVolumeSource) resizeVolume(p storage.VolumeResizeParams) (*storage.VolumeInfo, error) {
+	volume, err := v.describeVolume(p.VolumeId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sizeGiB := mibToGib(p.Size)
+	if uint64(volume.Size) >= sizeGiB {
+		return nil, errors.Errorf(
+			"cannot shrink volume from %dGiB to %dGiB", volume.Size, sizeGiB,
+		)
+	}
+	if sizeGiB > volumeSizeMaxGiB {
+		return nil, errors.Errorf("%d GiB exceeds the maximum of %d GiB", sizeGiB, volumeSizeMaxGiB)
+	}
+	if err := v.modifier.ModifyVolume(p.VolumeId, sizeGiB); err != nil {
+		return nil, errors.Trace(err)
+	}
+	info := &storage.VolumeInfo{
+		VolumeId:   p.VolumeId,
+		Size:       gibToMib(sizeGiB),
+		Persistent: true,
+	}
+	for _, attachment := range volume.Attachments {
+		if attachment.DeleteOnTermination {
+			info.Persistent = false
+			break
+		}
+	}
+	return info, nil
+}
+
 // ValidateVolumeParams is specified on the storage.VolumeSource interface.
 func (v *ebsVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
 	vol, err := parseVolumeOptions(params.Size, params.Attributes)
@@ -363,6 +363,65 @@ func (s *ebsVolumeSuite) TestDescribeVolumes(c *gc.C) {
 	}})
 }
 
+type modifyCall struct {
+	volumeId string
+	sizeGiB  uint64
+}
+
+// fakeVolumeModifier records the volumes it is asked to resize, as
+// the EC2 test server does not support resizing them.
+type fakeVolumeModifier struct {
+	calls []modifyCall
+	errs  map[string]error
+}
+
+func (m *fakeVolumeModifier) ModifyVolume(volumeId string, sizeGiB uint64) error {
+	m.calls = append(m.calls, modifyCall{volumeId, sizeGiB})
+	return m.errs[volumeId]
+}
+
+func (s *ebsVolumeSuite) TestResizeVolumes(c *gc.C) {
+	modifier := &fakeVolumeModifier{errs: map[string]error{
+		"vol-1": &awsec2.Error{Code: "IncorrectModificationState", Message: "already modifying"},
+	}}
+	s.PatchValue(ec2.NewVolumeModifier, ec2.VolumeModifierFactory(func(client *awsec2.EC2) ec2.VolumeModifier {
+		c.Check(client.Region.EC2Endpoint, gc.Equals, s.srv.ec2srv.URL())
+		return modifier
+	}))
+	vs := s.volumeSource(c, nil)
+	s.assertCreateVolumes(c, vs, "")
+
+	resizer, ok := vs.(storage.VolumeResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeVolumes([]storage.VolumeResizeParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+		Provider: ec2.EBS_ProviderType,
+		Size:     15 * 1000,
+	}, {
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "vol-1",
+		Provider: ec2.EBS_ProviderType,
+		Size:     30 * 1024,
+	}, {
+		Volume:   names.NewVolumeTag("2"),
+		VolumeId: "vol-2",
+		Provider: ec2.EBS_ProviderType,
+		Size:     10 * 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId:   "vol-0",
+		Size:       15 * 1024,
+		Persistent: true,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, "resizing volume 1: already modifying .*")
+	c.Assert(results[2].Error, gc.ErrorMatches, "resizing volume 2: cannot shrink volume from 30GiB to 10GiB")
+	c.Assert(modifier.calls, jc.DeepEquals, []modifyCall{{"vol-0", 15}, {"vol-1", 30}})
+}
+
 func (s *ebsVolumeSuite) TestDescribeVolumesNotFound(c *gc.C) {
 	vs := s.volumeSource(c, nil)
 	vols, err := vs.DescribeVolumes([]string{"vol-42"})
@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/amz.v3/ec2"
+)
+
+// modifyVolumeAPIVersion is the first EC2 API version supporting the
+// ModifyVolume action.
+const modifyVolumeAPIVersion = "2016-11-15"
+
+// volumeModifier is the part of the EC2 API used by the EBS volume
+// source to resize volumes.
+type volumeModifier interface {
+	// ModifyVolume grows the EBS volume with the given ID to sizeGiB.
+	ModifyVolume(volumeId string, sizeGiB uint64) error
+}
+
+// newVolumeModifier returns the volumeModifier used alongside the given
+// EC2 client. It is a variable so it can be overridden in tests.
+var newVolumeModifier = func(client *ec2.EC2) volumeModifier {
+	return &ec2VolumeModifier{
+		ec2:    client,
+		client: utils.GetValidatingHTTPClient(),
+	}
+}
+
+// ec2VolumeModifier implements volumeModifier for an EC2 client.
+//
+// The EC2 client in use does not know the ModifyVolume action, so the
+// request is built here in the same way the client builds its own,
+// sent to the client's endpoint and signed with its credentials and
+// signer.
+type ec2VolumeModifier struct {
+	ec2    *ec2.EC2
+	client *http.Client
+}
+
+// ModifyVolume is part of the volumeModifier interface. Requests which
+// are throttled, or which fail with a server error, are retried.
+func (m *ec2VolumeModifier) ModifyVolume(volumeId string, sizeGiB uint64) (err error) {
+	for a := shortAttempt.Start(); a.Next(); {
+		err = m.modifyVolume(volumeId, sizeGiB)
+		if !isRetryableEC2Error(err) {
+			break
+		}
+		logger.Debugf("retrying ModifyVolume for %s: %v", volumeId, err)
+	}
+	return err
+}
+
+func (m *ec2VolumeModifier) modifyVolume(volumeId string, sizeGiB uint64) error {
+	req, err := http.NewRequest("GET", m.ec2.Region.EC2Endpoint, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := req.URL.Query()
+	query.Add("Action", "ModifyVolume")
+	query.Add("Version", modifyVolumeAPIVersion)
+	query.Add("VolumeId", volumeId)
+	query.Add("Size", strconv.FormatUint(sizeGiB, 10))
+	query.Add("Timestamp", time.Now().In(time.UTC).Format(time.RFC3339))
+	req.URL.RawQuery = query.Encode()
+	if err := m.ec2.Sign(req, m.ec2.Auth); err != nil {
+		return errors.Annotate(err, "signing ModifyVolume request")
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return errors.Annotate(err, "sending ModifyVolume request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var errResp struct {
+		Errors    []ec2.Error `xml:"Errors>Error"`
+		RequestId string      `xml:"RequestID"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&errResp); err != nil || len(errResp.Errors) == 0 {
+		return &ec2.Error{
+			StatusCode: resp.StatusCode,
+			Message:    "ModifyVolume failed: " + resp.Status,
+		}
+	}
+	ec2Err := errResp.Errors[0]
+	ec2Err.StatusCode = resp.StatusCode
+	ec2Err.RequestId = errResp.RequestId
+	return &ec2Err
+}
+
+// isRetryableEC2Error reports whether the request that failed with err
+// may succeed if sent again.
+func isRetryableEC2Error(err error) bool {
+	ec2Err, ok := err.(*ec2.Error)
+	if !ok {
+		return false
+	}
+	return ec2Err.StatusCode >= http.StatusInternalServerError || ec2Err.Code == "RequestLimitExceeded"
+}
@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	"gopkg.in/amz.v3/aws"
+	awsec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/ec2"
+	"github.com/juju/juju/testing"
+)
+
+type modifyVolumeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&modifyVolumeSuite{})
+
+func (s *modifyVolumeSuite) client(url string) *awsec2.EC2 {
+	auth := aws.Auth{AccessKey: "access", SecretKey: "secret"}
+	region := aws.Region{Name: "test", EC2Endpoint: url}
+	return awsec2.New(auth, region, aws.SignV4Factory(region.Name, "ec2"))
+}
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (s *modifyVolumeSuite) modifier(url string) (ec2.VolumeModifier, *countingTransport) {
+	transport := &countingTransport{}
+	return ec2.NewEC2VolumeModifier(s.client(url), &http.Client{Transport: transport}), transport
+}
+
+func (s *modifyVolumeSuite) TestModifyVolume(c *gc.C) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		c.Check(req.Header.Get("Authorization"), gc.Not(gc.Equals), "")
+		w.Write([]byte(`<ModifyVolumeResponse/>`))
+	}))
+	defer srv.Close()
+
+	modifier, transport := s.modifier(srv.URL)
+	err := modifier.ModifyVolume("vol-0", 20)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(transport.requests, gc.Equals, 1)
+	c.Assert(query.Get("Action"), gc.Equals, "ModifyVolume")
+	c.Assert(query.Get("VolumeId"), gc.Equals, "vol-0")
+	c.Assert(query.Get("Size"), gc.Equals, "20")
+}
+
+func (s *modifyVolumeSuite) TestModifyVolumeError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<Response><Errors><Error>` +
+			`<Code>InvalidParameterValue</Code><Message>too big</Message>` +
+			`</Error></Errors><RequestID>req-1</RequestID></Response>`))
+	}))
+	defer srv.Close()
+
+	modifier, transport := s.modifier(srv.URL)
+	err := modifier.ModifyVolume("vol-0", 20000)
+	c.Assert(transport.requests, gc.Equals, 1)
+	ec2Err, ok := err.(*awsec2.Error)
+	c.Assert(ok, jc.IsTrue, gc.Commentf("%v", err))
+	c.Assert(ec2Err.StatusCode, gc.Equals, http.StatusBadRequest)
+	c.Assert(ec2Err.Code, gc.Equals, "InvalidParameterValue")
+	c.Assert(ec2Err.Message, gc.Equals, "too big")
+	c.Assert(ec2Err.RequestId, gc.Equals, "req-1")
+}
+
+func (s *modifyVolumeSuite) TestModifyVolumeUnparsableError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`denied`))
+	}))
+	defer srv.Close()
+
+	modifier, _ := s.modifier(srv.URL)
+	err := modifier.ModifyVolume("vol-0", 20)
+	ec2Err, ok := err.(*awsec2.Error)
+	c.Assert(ok, jc.IsTrue, gc.Commentf("%v", err))
+	c.Assert(ec2Err.StatusCode, gc.Equals, http.StatusForbidden)
+	c.Assert(ec2Err.Message, gc.Equals, "ModifyVolume failed: 403 Forbidden")
+}
+
+func (s *modifyVolumeSuite) TestModifyVolumeRetries(c *gc.C) {
+	s.PatchValue(ec2.ShortAttempt, utils.AttemptStrategy{Min: 3})
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`<ModifyVolumeResponse/>`))
+	}))
+	defer srv.Close()
+
+	modifier, transport := s.modifier(srv.URL)
+	err := modifier.ModifyVolume("vol-0", 20)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requests, gc.Equals, 2)
+	c.Assert(transport.requests, gc.Equals, 2)
+}
+
+func (s *modifyVolumeSuite) TestModifyVolumeDoesNotRetryClientErrors(c *gc.C) {
+	s.PatchValue(ec2.ShortAttempt, utils.AttemptStrategy{Min: 3})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	modifier, transport := s.modifier(srv.URL)
+	err := modifier.ModifyVolume("vol-0", 20)
+	c.Assert(err, gc.ErrorMatches, "ModifyVolume failed: 400 Bad Request.*")
+	c.Assert(transport.requests, gc.Equals, 1)
+}
@@ -898,10 +898,6 @@ func (e *environ) AllocateAddress(instId instance.Id, _ network.Id, addr network
 // ReleaseAddress releases a specific address previously allocated with
 // AllocateAddress. Implements NetworkingEnviron.ReleaseAddress.
 func (e *environ) ReleaseAddress(instId instance.Id, _ network.Id, addr network.Address, _ string) (err error) {
-	if !environs.AddressAllocationEnabled() {
-		return errors.NotSupportedf("address allocation")
-	}
-
 	defer errors.DeferredAnnotatef(&err, "failed to release address %q from instance %q", addr, instId)
 
 	// If the instance ID is unknown the address has already been released
@@ -1052,6 +1048,7 @@ func (e *environ) Subnets(instId instance.Id, subnetIds []network.Id) ([]network
 			AllocatableIPLow:  allocatableLow,
 			AllocatableIPHigh: allocatableHigh,
 			AvailabilityZones: []string{subnet.AvailZone},
+			SpaceName:         subnetSpaceName(subnet),
 		}
 		logger.Tracef("found subnet with info %#v", info)
 		results = append(results, info)
@@ -1070,6 +1067,21 @@ func (e *environ) Subnets(instId instance.Id, subnetIds []network.Id) ([]network
 	return results, nil
 }
 
+// spaceTagKey is the name of the tag which can be set on an EC2
+// subnet to propose the Juju space it belongs to.
+const spaceTagKey = "juju-space"
+
+// subnetSpaceName returns the value of the subnet's spaceTagKey tag,
+// or the empty string if it is not set.
+func subnetSpaceName(subnet ec2.Subnet) string {
+	for _, tag := range subnet.Tags {
+		if tag.Key == spaceTagKey {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
 func (e *environ) AllInstances() ([]instance.Instance, error) {
 	filter := ec2.NewFilter()
 	filter.Add("instance-state-name", "pending", "running")
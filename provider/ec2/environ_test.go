@@ -140,3 +140,11 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 		c.Assert(ipperms, gc.DeepEquals, t.expected)
 	}
 }
+
+func (*Suite) TestSubnetSpaceName(c *gc.C) {
+	subnet := amzec2.Subnet{Id: "subnet-0"}
+	c.Check(subnetSpaceName(subnet), gc.Equals, "")
+
+	subnet.Tags = []amzec2.Tag{{"Name", "db"}, {"juju-space", "internal"}}
+	c.Check(subnetSpaceName(subnet), gc.Equals, "internal")
+}
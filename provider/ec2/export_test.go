@@ -5,6 +5,7 @@ package ec2
 
 import (
 	"io"
+	"net/http"
 
 	"gopkg.in/amz.v3/aws"
 	"gopkg.in/amz.v3/ec2"
@@ -119,8 +120,28 @@ var (
 	ShortAttempt         = &shortAttempt
 	StorageAttempt       = &storageAttempt
 	DestroyVolumeAttempt = &destroyVolumeAttempt
+	NewVolumeModifier    = &newVolumeModifier
 )
 
+// VolumeModifier is implemented by the clients used to resize EBS
+// volumes.
+type VolumeModifier interface {
+	ModifyVolume(volumeId string, sizeGiB uint64) error
+}
+
+// VolumeModifierFactory adapts f so it can replace newVolumeModifier.
+func VolumeModifierFactory(f func(*ec2.EC2) VolumeModifier) func(*ec2.EC2) volumeModifier {
+	return func(client *ec2.EC2) volumeModifier {
+		return f(client)
+	}
+}
+
+// NewEC2VolumeModifier returns a VolumeModifier that sends its requests
+// to the given EC2 client's endpoint with the given HTTP client.
+func NewEC2VolumeModifier(client *ec2.EC2, httpClient *http.Client) VolumeModifier {
+	return &ec2VolumeModifier{ec2: client, client: httpClient}
+}
+
 func EC2ErrCode(err error) string {
 	return ec2ErrCode(err)
 }
@@ -1028,9 +1028,10 @@ func (t *localServerSuite) TestAllocateAddressWithNoFeatureFlag(c *gc.C) {
 func (t *localServerSuite) TestReleaseAddressWithNoFeatureFlag(c *gc.C) {
 	t.SetFeatureFlags() // clear the flags.
 	env := t.prepareEnviron(c)
-	err := env.ReleaseAddress("i-foo", "net1", network.NewAddress("1.2.3.4"), "")
-	c.Assert(err, gc.ErrorMatches, "address allocation not supported")
-	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	// Releasing with an unknown instance is a no-op, and it is
+	// allowed even without the feature flag.
+	err := env.ReleaseAddress(instance.UnknownId, "net1", network.NewAddress("1.2.3.4"), "")
+	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (t *localServerSuite) TestSupportsAddressAllocationCaches(c *gc.C) {
@@ -532,6 +532,7 @@ const (
 	capNetworksManagement = "networks-management"
 	capStaticIPAddresses  = "static-ipaddresses"
 	capDevices            = "devices-management"
+	capNetworkDeployment  = "network-deployment-ubuntu"
 )
 
 func (env *maasEnviron) supportsDevices() (bool, error) {
@@ -1526,10 +1527,6 @@ func (environ *maasEnviron) AllocateAddress(instId instance.Id, subnetId network
 // ReleaseAddress releases a specific address previously allocated with
 // AllocateAddress.
 func (environ *maasEnviron) ReleaseAddress(instId instance.Id, _ network.Id, addr network.Address, macAddress string) (err error) {
-	if !environs.AddressAllocationEnabled() {
-		return errors.NotSupportedf("address allocation")
-	}
-
 	defer errors.DeferredAnnotatef(&err, "failed to release IP address %q from instance %q", addr, instId)
 
 	supportsDevices, err := environ.supportsDevices()
@@ -1680,8 +1677,13 @@ func (environ *maasEnviron) listConnectedMacs(network networkDetails) ([]string,
 
 // Subnets returns basic information about the specified subnets known
 // by the provider for the specified instance. subnetIds must not be
-// empty. Implements NetworkingEnviron.Subnets.
+// empty, unless instId is instance.UnknownId, in which case all
+// subnets known to MAAS are returned with the names of their spaces.
+// Implements NetworkingEnviron.Subnets.
 func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id) ([]network.SubnetInfo, error) {
+	if instId == instance.UnknownId && len(subnetIds) == 0 {
+		return environ.spaceSubnets()
+	}
 	// At some point in the future an empty netIds may mean "fetch all subnets"
 	// but until that functionality is needed it's an error.
 	if len(subnetIds) == 0 {
@@ -1776,6 +1778,112 @@ func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id)
 	return networkInfo, nil
 }
 
+// spaceSubnets returns all the subnets known to MAAS, each with the
+// name of the MAAS space it belongs to. Spaces were introduced in
+// MAAS 1.9, so an error satisfying errors.IsNotSupported is returned
+// by older versions.
+func (environ *maasEnviron) spaceSubnets() ([]network.SubnetInfo, error) {
+	caps, err := environ.getCapabilities()
+	if err != nil {
+		return nil, errors.Annotatef(err, "getCapabilities failed")
+	}
+	if !caps.Contains(capNetworkDeployment) {
+		return nil, errors.NotSupportedf("listing subnets without MAAS spaces")
+	}
+	client := environ.getMAASClient().GetSubObject("spaces")
+	json, err := client.CallGet("", nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list MAAS spaces")
+	}
+	subnets, err := parseSpaceSubnets(json)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	nodegroups, err := environ.getNodegroups()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get node groups")
+	}
+	nodegroupInterfaces := environ.getNodegroupInterfaces(nodegroups)
+	for i, subnet := range subnets {
+		_, netCIDR, err := net.ParseCIDR(subnet.CIDR)
+		if err != nil {
+			continue
+		}
+		for ip, bounds := range nodegroupInterfaces {
+			if netCIDR.Contains(net.ParseIP(ip)) {
+				subnets[i].AllocatableIPLow = bounds[0]
+				subnets[i].AllocatableIPHigh = bounds[1]
+				break
+			}
+		}
+	}
+	logger.Debugf("subnets in MAAS spaces: %#v", subnets)
+	return subnets, nil
+}
+
+// parseSpaceSubnets returns the subnets in the result of a MAAS
+// spaces listing, in the order they are listed. The subnet's MAAS id
+// is used as its provider id, and the name of the space holding it as
+// its space name.
+func parseSpaceSubnets(json gomaasapi.JSONObject) ([]network.SubnetInfo, error) {
+	jsonSpaces, err := json.GetArray()
+	if err != nil {
+		return nil, err
+	}
+	var subnets []network.SubnetInfo
+	for _, jsonSpace := range jsonSpaces {
+		spaceFields, err := jsonSpace.GetMap()
+		if err != nil {
+			return nil, err
+		}
+		spaceName, err := spaceFields["name"].GetString()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get space name: %v", err)
+		}
+		jsonSubnets, err := spaceFields["subnets"].GetArray()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get subnets of space %q: %v", spaceName, err)
+		}
+		for _, jsonSubnet := range jsonSubnets {
+			fields, err := jsonSubnet.GetMap()
+			if err != nil {
+				return nil, err
+			}
+			id, err := fields["id"].GetFloat64()
+			if err != nil {
+				return nil, fmt.Errorf("cannot get subnet id: %v", err)
+			}
+			cidr, err := fields["cidr"].GetString()
+			if err != nil {
+				return nil, fmt.Errorf("cannot get subnet cidr: %v", err)
+			}
+			vlanTag := 0
+			if vlanField, ok := fields["vlan"]; ok && !vlanField.IsNil() {
+				// vlan is optional, so assume the untagged VLAN when missing.
+				vlan, err := vlanField.GetMap()
+				if err != nil {
+					return nil, fmt.Errorf("cannot get subnet vlan: %v", err)
+				}
+				if vidField, ok := vlan["vid"]; ok && !vidField.IsNil() {
+					vid, err := vidField.GetFloat64()
+					if err != nil {
+						return nil, fmt.Errorf("cannot get vlan vid: %v", err)
+					}
+					vlanTag = int(vid)
+				}
+			}
+			subnets = append(subnets, network.SubnetInfo{
+				CIDR:       cidr,
+				ProviderId: network.Id(fmt.Sprintf("%d", int(id))),
+				VLANTag:    vlanTag,
+				SpaceName:  spaceName,
+			})
+		}
+	}
+	return subnets, nil
+}
+
 // AllInstances returns all the instance.Instance in this provider.
 func (environ *maasEnviron) AllInstances() ([]instance.Instance, error) {
 	return environ.acquiredInstances(nil)
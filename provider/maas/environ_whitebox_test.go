@@ -1283,6 +1283,53 @@ func (suite *environSuite) TestSubnetsNoDuplicates(c *gc.C) {
 	c.Assert(netInfo, jc.DeepEquals, expectedInfo)
 }
 
+func (suite *environSuite) TestSubnetsAllWithoutSpaces(c *gc.C) {
+	suite.testMAASObject.TestServer.SetVersionJSON(`{"capabilities": ["networks-management","static-ipaddresses"]}`)
+	_, err := suite.makeEnviron().Subnets(instance.UnknownId, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (suite *environSuite) TestParseSpaceSubnets(c *gc.C) {
+	json, err := gomaasapi.Parse(gomaasapi.Client{}, []byte(`[{
+		"name": "space-0",
+		"id": 0,
+		"subnets": [
+			{"id": 1, "cidr": "192.168.1.0/24", "vlan": {"vid": 0}, "space": "space-0"},
+			{"id": 3, "cidr": "10.0.0.0/16", "space": "space-0"}
+		]
+	}, {
+		"name": "dmz",
+		"id": 1,
+		"subnets": [
+			{"id": 2, "cidr": "172.16.0.0/24", "vlan": {"vid": 42}, "space": "dmz"}
+		]
+	}, {
+		"name": "empty",
+		"id": 2,
+		"subnets": []
+	}]`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnets, err := parseSpaceSubnets(json)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, jc.DeepEquals, []network.SubnetInfo{
+		{CIDR: "192.168.1.0/24", ProviderId: "1", VLANTag: 0, SpaceName: "space-0"},
+		{CIDR: "10.0.0.0/16", ProviderId: "3", VLANTag: 0, SpaceName: "space-0"},
+		{CIDR: "172.16.0.0/24", ProviderId: "2", VLANTag: 42, SpaceName: "dmz"},
+	})
+}
+
+func (suite *environSuite) TestParseSpaceSubnetsMissingCIDR(c *gc.C) {
+	json, err := gomaasapi.Parse(gomaasapi.Client{}, []byte(`[{
+		"name": "space-0",
+		"subnets": [{"id": 1}]
+	}]`))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = parseSpaceSubnets(json)
+	c.Assert(err, gc.ErrorMatches, "cannot get subnet cidr: .*")
+}
+
 func (suite *environSuite) TestAllocateAddress(c *gc.C) {
 	testInstance := suite.createSubnets(c, false)
 	env := suite.makeEnviron()
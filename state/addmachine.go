@@ -362,6 +362,9 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if !parent.supportsContainerType(containerType) {
 		return nil, nil, errors.Errorf("machine %s cannot host %s containers", parentId, containerType)
 	}
+	if parent.Cordoned() {
+		return nil, nil, errors.Errorf("machine %s is cordoned", parentId)
+	}
 	newId, err := st.newContainerId(parentId, containerType)
 	if err != nil {
 		return nil, nil, err
@@ -373,6 +376,12 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 		return nil, nil, errors.Trace(err)
 	}
 	prereqOps = append(prereqOps,
+		// The host machine must not be cordoned meanwhile.
+		txn.Op{
+			C:      machinesC,
+			Id:     parent.doc.DocID,
+			Assert: notCordonedDoc,
+		},
 		// Update containers record for host machine.
 		st.addChildToContainerRefOp(parentId, mdoc.Id),
 		// Create a containers reference document for the container itself.
@@ -568,8 +577,57 @@ func (st *State) machineStorageOps(
 		})
 	}
 
-	// TODO(axw) handle args.filesystemAttachments, args.volumeAttachments
-	// when we handle attaching to existing (e.g. shared) storage.
+	// Attach existing volumes and filesystems, such as those of
+	// imported storage.
+	if len(args.volumeAttachments) > 0 || len(args.filesystemAttachments) > 0 {
+		m := &Machine{st: st, doc: *mdoc}
+		existingVolumes := make(map[names.VolumeTag]VolumeAttachmentParams)
+		for tag, params := range args.volumeAttachments {
+			existingVolumes[tag] = params
+		}
+		for tag, params := range args.filesystemAttachments {
+			f, err := st.filesystemByTag(tag)
+			if err != nil {
+				return nil, nil, nil, errors.Trace(err)
+			}
+			ops, attach, err := st.moveFilesystemAttachmentOps(m, f)
+			if err != nil {
+				return nil, nil, nil, errors.Trace(err)
+			}
+			filesystemOps = append(filesystemOps, ops...)
+			if attach {
+				storageTag, _ := f.Storage()
+				fsAttachments = append(fsAttachments, filesystemAttachmentTemplate{
+					tag, storageTag, params,
+				})
+			}
+			if volumeTag, err := f.Volume(); err == nil {
+				// The filesystem is volume-backed, so the volume
+				// must be attached to the machine too.
+				if _, ok := existingVolumes[volumeTag]; !ok {
+					existingVolumes[volumeTag] = VolumeAttachmentParams{}
+				}
+			} else if errors.Cause(err) != ErrNoBackingVolume {
+				return nil, nil, nil, errors.Trace(err)
+			}
+		}
+		for tag, params := range existingVolumes {
+			v, err := st.volumeByTag(tag)
+			if err != nil {
+				return nil, nil, nil, errors.Trace(err)
+			}
+			ops, attach, err := st.moveVolumeAttachmentOps(m, v, false)
+			if err != nil {
+				return nil, nil, nil, errors.Trace(err)
+			}
+			volumeOps = append(volumeOps, ops...)
+			if attach {
+				volumeAttachments = append(volumeAttachments, volumeAttachmentTemplate{
+					tag, params,
+				})
+			}
+		}
+	}
 
 	ops := make([]txn.Op, 0, len(filesystemOps)+len(volumeOps)+len(fsAttachments)+len(volumeAttachments))
 	if len(fsAttachments) > 0 {
@@ -715,6 +773,16 @@ func (st *State) maintainStateServersOps(mdocs []*machineDoc, currentInfo *State
 func (st *State) EnsureAvailability(
 	numStateServers int, cons constraints.Value, series string, placement []string,
 ) (StateServersChanges, error) {
+	return st.EnsureAvailabilityWithPreferences(numStateServers, cons, series, placement, ReplicaSetPreferences{})
+}
+
+// EnsureAvailabilityWithPreferences ensures the availability of state
+// servers as EnsureAvailability does, and records the given replica set
+// preferences on the resulting state servers in the same transaction.
+func (st *State) EnsureAvailabilityWithPreferences(
+	numStateServers int, cons constraints.Value, series string, placement []string,
+	prefs ReplicaSetPreferences,
+) (StateServersChanges, error) {
 
 	if numStateServers < 0 || (numStateServers != 0 && numStateServers%2 != 1) {
 		return StateServersChanges{}, errors.New("number of state servers must be odd and non-negative")
@@ -722,8 +790,12 @@ func (st *State) EnsureAvailability(
 	if numStateServers > replicaset.MaxPeers {
 		return StateServersChanges{}, errors.Errorf("state server count is too large (allowed %d)", replicaset.MaxPeers)
 	}
+	if err := prefs.Validate(); err != nil {
+		return StateServersChanges{}, errors.Trace(err)
+	}
 	var change StateServersChanges
 	buildTxn := func(attempt int) ([]txn.Op, error) {
+		change = StateServersChanges{}
 		currentInfo, err := st.StateServerInfo()
 		if err != nil {
 			return nil, err
@@ -749,27 +821,38 @@ func (st *State) EnsureAvailability(
 				voteCount++
 			}
 		}
-		if voteCount == desiredStateServerCount && len(intent.remove) == 0 {
-			return nil, jujutxn.ErrNoOperations
-		}
-		// Promote as many machines as we can to fulfil the shortfall.
-		if n := desiredStateServerCount - voteCount; n < len(intent.promote) {
-			intent.promote = intent.promote[:n]
-		}
-		voteCount += len(intent.promote)
+		var ops []txn.Op
+		if voteCount != desiredStateServerCount || len(intent.remove) > 0 {
+			// Promote as many machines as we can to fulfil the shortfall.
+			if n := desiredStateServerCount - voteCount; n < len(intent.promote) {
+				intent.promote = intent.promote[:n]
+			}
+			voteCount += len(intent.promote)
 
-		if n := desiredStateServerCount - voteCount; n < len(intent.convert) {
-			intent.convert = intent.convert[:n]
-		}
-		voteCount += len(intent.convert)
+			if n := desiredStateServerCount - voteCount; n < len(intent.convert) {
+				intent.convert = intent.convert[:n]
+			}
+			voteCount += len(intent.convert)
 
-		intent.newCount = desiredStateServerCount - voteCount
+			intent.newCount = desiredStateServerCount - voteCount
 
-		logger.Infof("%d new machines; promoting %v; converting %v", intent.newCount, intent.promote, intent.convert)
+			logger.Infof("%d new machines; promoting %v; converting %v", intent.newCount, intent.promote, intent.convert)
 
-		var ops []txn.Op
-		ops, change, err = st.ensureAvailabilityIntentionOps(intent, currentInfo, cons, series)
-		return ops, err
+			ops, change, err = st.ensureAvailabilityIntentionOps(intent, currentInfo, cons, series)
+			if err != nil {
+				return nil, err
+			}
+		}
+		prefsOps, hidden, err := st.replicaSetPreferencesOps(currentInfo, change, prefs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, prefsOps...)
+		change.Hidden = hidden
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
 	}
 	if err := st.run(buildTxn); err != nil {
 		err = errors.Annotate(err, "failed to create new state server machines")
@@ -786,6 +869,7 @@ type StateServersChanges struct {
 	Promoted   []string
 	Demoted    []string
 	Converted  []string
+	Hidden     []string
 }
 
 // ensureAvailabilityIntentionOps returns operations to fulfil the desired intent.
@@ -933,7 +1017,9 @@ func (st *State) ensureAvailabilityIntentions(info *StateServerInfo, placement [
 		}
 		logger.Infof("machine %q, available %v, wants vote %v, has vote %v", m, available, m.WantsVote(), m.HasVote())
 		if available {
-			if m.WantsVote() {
+			if m.WantsVote() || m.ReplicaSetHidden() {
+				// Hidden members are kept for backups,
+				// so they are never promoted.
 				intent.maintain = append(intent.maintain, m)
 			} else {
 				intent.promote = append(intent.promote, m)
@@ -1014,6 +1100,7 @@ func removeStateServerOps(m *Machine) []txn.Op {
 		Update: bson.D{
 			{"$pull", bson.D{{"jobs", JobManageEnviron}}},
 			{"$set", bson.D{{"novote", false}}},
+			{"$unset", bson.D{{"replicasetpriority", nil}, {"replicasethidden", nil}}},
 		},
 	}, {
 		C:      stateServersC,
@@ -1021,3 +1108,259 @@ func removeStateServerOps(m *Machine) []txn.Op {
 		Update: bson.D{{"$pull", bson.D{{"machineids", m.doc.Id}}}},
 	}}
 }
+
+const (
+	// preferredZonePriority is the replica set priority given to
+	// voting state servers in the preferred availability zone.
+	preferredZonePriority = 2
+
+	// preferredPrimaryPriority is the replica set priority given to
+	// the preferred primary state server.
+	preferredPrimaryPriority = 3
+)
+
+// ReplicaSetPreferences describes how the mongo replica set members of
+// the state server machines should be configured.
+type ReplicaSetPreferences struct {
+	// PreferredZone, if set, names the availability zone whose
+	// voting state servers are preferred when electing a primary.
+	PreferredZone string
+
+	// PreferredPrimary, if set, holds the id of the voting state
+	// server which should be primary whenever it is healthy.
+	PreferredPrimary string
+
+	// Hidden holds the ids of machines which should become hidden,
+	// non-voting state servers, for use by backups. Machines which are
+	// not yet state servers are converted.
+	Hidden []string
+
+	// Reset, if true, clears the preferences previously recorded:
+	// all voting state servers get the default priority again, and
+	// hidden state servers become ordinary non-voting ones. It cannot
+	// be combined with any other preference.
+	Reset bool
+}
+
+// IsEmpty reports whether no preferences are set.
+func (p ReplicaSetPreferences) IsEmpty() bool {
+	return p.PreferredZone == "" && p.PreferredPrimary == "" && len(p.Hidden) == 0 && !p.Reset
+}
+
+// Validate returns an error if the preferences are inconsistent.
+func (p ReplicaSetPreferences) Validate() error {
+	if p.Reset && (p.PreferredZone != "" || p.PreferredPrimary != "" || len(p.Hidden) > 0) {
+		return errors.NotValidf("resetting replica set preferences while setting new ones")
+	}
+	return nil
+}
+
+// SetReplicaSetPreferences records the replica set preferences on the
+// state server machines, where they are picked up by the worker that
+// maintains the replica set. If a preferred zone or primary is given,
+// or the preferences are reset, the priorities of all voting state
+// servers are recalculated; the returned changes list the machines
+// that have been made hidden.
+func (st *State) SetReplicaSetPreferences(prefs ReplicaSetPreferences) (StateServersChanges, error) {
+	if err := prefs.Validate(); err != nil {
+		return StateServersChanges{}, errors.Trace(err)
+	}
+	var change StateServersChanges
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		currentInfo, err := st.StateServerInfo()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops, hidden, err := st.replicaSetPreferencesOps(currentInfo, StateServersChanges{}, prefs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		change = StateServersChanges{Hidden: hidden}
+		return ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return StateServersChanges{}, errors.Annotate(err, "cannot set replica set preferences")
+	}
+	return change, nil
+}
+
+// replicaSetPreferencesOps returns the operations needed to record
+// prefs on the state servers described by info, once the given changes
+// to the state servers have been made in the same transaction. It also
+// returns the ids of the machines that are to be made hidden.
+func (st *State) replicaSetPreferencesOps(
+	info *StateServerInfo, change StateServersChanges, prefs ReplicaSetPreferences,
+) ([]txn.Op, []string, error) {
+	if prefs.IsEmpty() {
+		return nil, nil, nil
+	}
+	// Machines whose vote changes in the transaction already have
+	// their own assertions, which these operations must not contradict.
+	changing := make(map[string]bool)
+	for _, ids := range [][]string{change.Promoted, change.Demoted, change.Converted, change.Removed} {
+		for _, id := range ids {
+			changing[id] = true
+		}
+	}
+	var votingIds []string
+	for _, id := range info.VotingMachineIds {
+		if !hasString(change.Demoted, id) {
+			votingIds = append(votingIds, id)
+		}
+	}
+	votingIds = append(votingIds, change.Promoted...)
+	votingIds = append(votingIds, change.Converted...)
+
+	var ops []txn.Op
+	if prefs.Reset || prefs.PreferredZone != "" || prefs.PreferredPrimary != "" {
+		priorityOps, err := st.replicaSetPriorityOps(votingIds, changing, prefs)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		ops = append(ops, priorityOps...)
+	}
+	if prefs.Reset {
+		unhideOps, err := st.unhideStateServersOps(info, changing)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		ops = append(ops, unhideOps...)
+	}
+	var hidden []string
+	for _, id := range prefs.Hidden {
+		if changing[id] || hasString(change.Added, id) {
+			return nil, nil, errors.Errorf("machine %s cannot be both a voting and a hidden state server", id)
+		}
+		hiddenOps, err := st.hiddenStateServerOps(id)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if len(hiddenOps) > 0 {
+			ops = append(ops, hiddenOps...)
+			hidden = append(hidden, id)
+		}
+	}
+	return ops, hidden, nil
+}
+
+// replicaSetPriorityOps returns the operations needed to set the
+// replica set priorities of the given voting state servers to match
+// prefs. Machines in changing are not asserted to be voting already.
+func (st *State) replicaSetPriorityOps(votingIds []string, changing map[string]bool, prefs ReplicaSetPreferences) ([]txn.Op, error) {
+	if prefs.PreferredPrimary != "" && !hasString(votingIds, prefs.PreferredPrimary) {
+		return nil, errors.Errorf("machine %s is not a voting state server", prefs.PreferredPrimary)
+	}
+	var ops []txn.Op
+	inZone := 0
+	for _, id := range votingIds {
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var priority float64
+		if prefs.PreferredZone != "" {
+			zone, err := m.AvailabilityZone()
+			if err != nil && !errors.IsNotProvisioned(err) {
+				return nil, errors.Trace(err)
+			}
+			if zone == prefs.PreferredZone {
+				priority = preferredZonePriority
+				inZone++
+			}
+		}
+		if id == prefs.PreferredPrimary {
+			priority = preferredPrimaryPriority
+		}
+		if priority == m.doc.ReplicaSetPriority {
+			continue
+		}
+		var assert interface{} = bson.D{{"novote", false}}
+		if changing[id] {
+			assert = nil
+		}
+		ops = append(ops, txn.Op{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: assert,
+			Update: bson.D{{"$set", bson.D{{"replicasetpriority", priority}}}},
+		})
+	}
+	if prefs.PreferredZone != "" && inZone == 0 {
+		return nil, errors.Errorf("no voting state servers in availability zone %q", prefs.PreferredZone)
+	}
+	return ops, nil
+}
+
+// unhideStateServersOps returns the operations needed to make all of
+// the hidden state servers described by info, except those in
+// changing, ordinary non-voting state servers again.
+func (st *State) unhideStateServersOps(info *StateServerInfo, changing map[string]bool) ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, id := range info.MachineIds {
+		if changing[id] {
+			continue
+		}
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !m.ReplicaSetHidden() {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"replicasethidden", true}},
+			Update: bson.D{{"$unset", bson.D{{"replicasethidden", nil}}}},
+		})
+	}
+	return ops, nil
+}
+
+// hiddenStateServerOps returns the operations needed to make the
+// machine with the given id a hidden state server, or nothing if it
+// already is one.
+func (st *State) hiddenStateServerOps(id string) ([]txn.Op, error) {
+	if names.IsContainerMachine(id) {
+		return nil, errors.Errorf("container %s cannot be a hidden state server", id)
+	}
+	m, err := st.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if m.Life() != Alive {
+		return nil, errors.Errorf("machine %s is not alive", id)
+	}
+	switch {
+	case !m.IsManager():
+		return []txn.Op{{
+			C:  machinesC,
+			Id: m.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"jobs", bson.D{{"$nin", []MachineJob{JobManageEnviron}}}},
+			},
+			Update: bson.D{
+				{"$addToSet", bson.D{{"jobs", JobManageEnviron}}},
+				{"$set", bson.D{{"novote", true}, {"replicasethidden", true}}},
+			},
+		}, {
+			C:      stateServersC,
+			Id:     environGlobalKey,
+			Update: bson.D{{"$addToSet", bson.D{{"machineids", m.doc.Id}}}},
+		}}, nil
+	case m.WantsVote() || m.HasVote():
+		return nil, errors.Errorf("machine %s is a voting state server", id)
+	case m.ReplicaSetHidden():
+		return nil, nil
+	}
+	return []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"novote", true}, {"hasvote", false}},
+		Update: bson.D{{"$set", bson.D{{"replicasethidden", true}}}},
+	}}, nil
+}
@@ -83,6 +83,10 @@ func allCollections() collectionSchema {
 		// upgrades and schema migrations.
 		upgradeInfoC: {global: true},
 
+		// This collection holds the free space last recorded in each state
+		// server's data directory, for checking before an upgrade.
+		stateServerDiskSpaceC: {global: true},
+
 		// This collection holds a convenient representation of the content of
 		// the simplestreams data source pointing to binaries required by juju.
 		toolsmetadataC: {global: true},
@@ -115,7 +119,12 @@ func allCollections() collectionSchema {
 
 		// This collection holds workload metrics reported by certain charms
 		// for passing onward to other tools.
-		metricsC: {global: true},
+		metricsC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"unit", "created"},
+			}},
+		},
 
 		// This collection holds persistent state for the metrics manager.
 		metricsManagerC: {global: true},
@@ -189,6 +198,15 @@ func allCollections() collectionSchema {
 		// meterStatusC is the collection used to store meter status information.
 		meterStatusC:  {},
 		settingsrefsC: {},
+
+		// meterThresholdsC holds the meter thresholds of services.
+		meterThresholdsC: {},
+
+		// configProfilesC holds the environment's charm config profiles,
+		// and serviceConfigProfilesC records the profile used by each
+		// service.
+		configProfilesC:        {},
+		serviceConfigProfilesC: {},
 		relationsC: {
 			indexes: []mgo.Index{{
 				Key: []string{"env-uuid", "endpoints.relationname"},
@@ -206,6 +224,14 @@ func allCollections() collectionSchema {
 		machinesC:      {},
 		rebootC:        {},
 
+		// This collection holds the progress of containers being moved
+		// from one host machine to another.
+		containerMigrationsC: {},
+
+		// This collection holds the progress of a staged agent upgrade,
+		// which releases machines to the new agent version in batches.
+		stagedUpgradesC: {},
+
 		// -----
 
 		// These collections hold information associated with storage.
@@ -225,6 +251,11 @@ func allCollections() collectionSchema {
 				Key: []string{"env-uuid", "owner"},
 			}},
 		},
+		storageImportsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "storageid"},
+			}},
+		},
 		storageAttachmentsC: {
 			indexes: []mgo.Index{{
 				Key: []string{"env-uuid", "storageid"},
@@ -238,6 +269,13 @@ func allCollections() collectionSchema {
 			}},
 		},
 		volumeAttachmentsC: {},
+		volumeSnapshotsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "volumeid"},
+			}},
+		},
+		volumeResizesC:     {},
+		filesystemResizesC: {},
 
 		// -----
 
@@ -342,11 +380,14 @@ const (
 	charmsC                = "charms"
 	cleanupsC              = "cleanups"
 	cloudimagemetadataC    = "cloudimagemetadata"
+	configProfilesC        = "configprofiles"
 	constraintsC           = "constraints"
+	containerMigrationsC   = "containermigrations"
 	containerRefsC         = "containerRefs"
 	envUsersC              = "envusers"
 	environmentsC          = "environments"
 	filesystemAttachmentsC = "filesystemAttachments"
+	filesystemResizesC     = "filesystemresizes"
 	filesystemsC           = "filesystems"
 	instanceDataC          = "instanceData"
 	ipaddressesC           = "ipaddresses"
@@ -354,6 +395,7 @@ const (
 	leasesC                = "leases"
 	machinesC              = "machines"
 	meterStatusC           = "meterStatus"
+	meterThresholdsC       = "meterthresholds"
 	metricsC               = "metrics"
 	metricsManagerC        = "metricsmanager"
 	minUnitsC              = "minunits"
@@ -366,17 +408,21 @@ const (
 	requestedNetworksC     = "requestednetworks"
 	restoreInfoC           = "restoreInfo"
 	sequenceC              = "sequence"
+	serviceConfigProfilesC = "serviceconfigprofiles"
 	servicesC              = "services"
 	settingsC              = "settings"
 	settingsrefsC          = "settingsrefs"
 	stateServersC          = "stateServers"
+	stateServerDiskSpaceC  = "stateServerDiskSpace"
 	statusesC              = "statuses"
 	statusesHistoryC       = "statuseshistory"
 	storageAttachmentsC    = "storageattachments"
 	storageConstraintsC    = "storageconstraints"
+	storageImportsC        = "storageimports"
 	storageInstancesC      = "storageinstances"
 	subnetsC               = "subnets"
 	spacesC                = "spaces"
+	stagedUpgradesC        = "stagedupgrades"
 	toolsmetadataC         = "toolsmetadata"
 	txnLogC                = "txns.log"
 	txnsC                  = "txns"
@@ -387,5 +433,7 @@ const (
 	userLastLoginC         = "userLastLogin"
 	envUserLastConnectionC = "envUserLastConnection"
 	volumeAttachmentsC     = "volumeattachments"
+	volumeResizesC         = "volumeresizes"
+	volumeSnapshotsC       = "volumesnapshots"
 	volumesC               = "volumes"
 )
@@ -6,7 +6,8 @@
 package backups
 
 import (
-	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -91,7 +92,7 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 		return errors.Annotate(err, "cannot produce dial information")
 	}
 
-	memberHostPort := fmt.Sprintf("%s:%d", args.PrivateAddress, ssi.StatePort)
+	memberHostPort := net.JoinHostPort(args.PrivateAddress, strconv.Itoa(ssi.StatePort))
 	err = resetReplicaSet(dialInfo, memberHostPort)
 	if err != nil {
 		return errors.Annotate(err, "cannot reset replicaSet")
@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+)
+
+// maxCharmHistory is the number of previous charm URLs remembered
+// for each service.
+const maxCharmHistory = 10
+
+// CharmHistory returns the URLs of the charms the service used before
+// its current one, most recent last. Charms rolled back from are not
+// included.
+func (s *Service) CharmHistory() []*charm.URL {
+	return append([]*charm.URL(nil), s.doc.CharmHistory...)
+}
+
+// PreviousCharmURL returns the URL of the most recent charm in the
+// service's history with the given revision, or of the most recent
+// charm if revision is negative. It returns an error satisfying
+// errors.IsNotFound if there is no such charm.
+func (s *Service) PreviousCharmURL(revision int) (*charm.URL, error) {
+	history := s.doc.CharmHistory
+	for i := len(history) - 1; i >= 0; i-- {
+		if revision < 0 || history[i].Revision == revision {
+			return history[i], nil
+		}
+	}
+	if revision < 0 {
+		return nil, errors.NotFoundf("previous charm of service %q", s)
+	}
+	return nil, errors.NotFoundf("revision %d in charm history of service %q", revision, s)
+}
+
+// charmHistoryAfter returns the charm history of a service with the
+// given history once it has changed from the current charm to next.
+// Changing to a charm already in the history rolls the service back,
+// forgetting that charm and all those used since; otherwise, the
+// current charm is remembered.
+func charmHistoryAfter(history []*charm.URL, current, next *charm.URL) []*charm.URL {
+	for i, curl := range history {
+		if *curl == *next {
+			return append([]*charm.URL(nil), history[:i]...)
+		}
+	}
+	history = append(append([]*charm.URL(nil), history...), current)
+	if len(history) > maxCharmHistory {
+		history = history[len(history)-maxCharmHistory:]
+	}
+	return history
+}
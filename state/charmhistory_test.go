@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/state"
+)
+
+type CharmHistorySuite struct {
+	ConnSuite
+	mysql *state.Service
+}
+
+var _ = gc.Suite(&CharmHistorySuite{})
+
+func (s *CharmHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingService(c, "mysql", s.AddMetaCharm(c, "mysql", metaBase, 1))
+}
+
+func (s *CharmHistorySuite) setCharmRevision(c *gc.C, revision int) *charm.URL {
+	ch := s.AddMetaCharm(c, "mysql", metaBase, revision)
+	err := s.mysql.SetCharm(ch, false)
+	c.Assert(err, jc.ErrorIsNil)
+	return ch.URL()
+}
+
+func (s *CharmHistorySuite) assertHistory(c *gc.C, revisions ...int) {
+	// Check the history stored, not just that cached.
+	svc, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	var actual []int
+	for _, curl := range svc.CharmHistory() {
+		actual = append(actual, curl.Revision)
+	}
+	c.Assert(actual, jc.DeepEquals, revisions)
+}
+
+func (s *CharmHistorySuite) TestCharmHistory(c *gc.C) {
+	c.Assert(s.mysql.CharmHistory(), gc.HasLen, 0)
+	s.setCharmRevision(c, 2)
+	s.assertHistory(c, 1)
+	s.setCharmRevision(c, 3)
+	s.assertHistory(c, 1, 2)
+
+	// Setting the current charm again changes nothing.
+	s.setCharmRevision(c, 3)
+	s.assertHistory(c, 1, 2)
+}
+
+func (s *CharmHistorySuite) TestCharmHistoryRollBack(c *gc.C) {
+	for revision := 2; revision <= 4; revision++ {
+		s.setCharmRevision(c, revision)
+	}
+	s.assertHistory(c, 1, 2, 3)
+
+	// Returning to a charm in the history forgets it, and all those
+	// used since.
+	s.setCharmRevision(c, 2)
+	s.assertHistory(c, 1)
+	s.setCharmRevision(c, 5)
+	s.assertHistory(c, 1, 2)
+}
+
+func (s *CharmHistorySuite) TestCharmHistoryLimit(c *gc.C) {
+	for revision := 2; revision <= 13; revision++ {
+		s.setCharmRevision(c, revision)
+	}
+	s.assertHistory(c, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+}
+
+func (s *CharmHistorySuite) TestCharmHistoryStaleService(c *gc.C) {
+	stale, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	s.setCharmRevision(c, 2)
+
+	err = stale.SetCharm(s.AddMetaCharm(c, "mysql", metaBase, 3), false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistory(c, 1, 2)
+}
+
+func (s *CharmHistorySuite) TestPreviousCharmURL(c *gc.C) {
+	_, err := s.mysql.PreviousCharmURL(-1)
+	c.Assert(err, gc.ErrorMatches, `previous charm of service "mysql" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	rev2 := s.setCharmRevision(c, 2)
+	rev3 := s.setCharmRevision(c, 3)
+	s.setCharmRevision(c, 4)
+
+	curl, err := s.mysql.PreviousCharmURL(-1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, rev3)
+	curl, err = s.mysql.PreviousCharmURL(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, rev2)
+	_, err = s.mysql.PreviousCharmURL(4)
+	c.Assert(err, gc.ErrorMatches, `revision 4 in charm history of service "mysql" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmHistorySuite) TestRollBackRollingUpgradeHistory(c *gc.C) {
+	_, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetCharmRolling(s.AddMetaCharm(c, "mysql", metaBase, 2), false, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistory(c, 1)
+
+	err = s.mysql.RollBackRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistory(c)
+}
@@ -0,0 +1,303 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+var configProfileLogger = loggo.GetLogger("juju.state.configprofiles")
+
+var validConfigProfileName = regexp.MustCompile("^[a-z][a-z0-9]*(-[a-z0-9]+)*$")
+
+// IsValidConfigProfileName returns whether name is a valid
+// config profile name.
+func IsValidConfigProfileName(name string) bool {
+	return validConfigProfileName.MatchString(name)
+}
+
+// ConfigProfile is a named set of charm config settings, defined for
+// the environment, which services may use as defaults for their own
+// config settings. A service's explicit settings take precedence over
+// the settings of its config profile, which in turn take precedence
+// over the charm's defaults. Settings in the profile that are not
+// options of a service's charm are ignored for that service.
+type ConfigProfile struct {
+	st  *State
+	doc configProfileDoc
+}
+
+// configProfileDoc records a config profile. Config profiles may not
+// be changed once created, so that the config of services using a
+// profile does not change unexpectedly.
+type configProfileDoc struct {
+	DocID    string                 `bson:"_id"`
+	EnvUUID  string                 `bson:"env-uuid"`
+	Name     string                 `bson:"name"`
+	Settings map[string]interface{} `bson:"settings"`
+}
+
+// Name returns the name of the config profile.
+func (p *ConfigProfile) Name() string {
+	return p.doc.Name
+}
+
+// Settings returns the settings of the config profile.
+func (p *ConfigProfile) Settings() map[string]interface{} {
+	return copyMap(p.doc.Settings, unescapeReplacer.Replace)
+}
+
+// AddConfigProfile adds a config profile with the given name and
+// settings to the environment.
+func (st *State) AddConfigProfile(name string, settings map[string]interface{}) (_ *ConfigProfile, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add config profile %q", name)
+	if !IsValidConfigProfileName(name) {
+		return nil, errors.NotValidf("config profile name")
+	}
+	for key, value := range settings {
+		switch value.(type) {
+		case string, bool, int, int64, float64:
+		default:
+			return nil, errors.Errorf("value of %q must be a string, number or boolean, got %T", key, value)
+		}
+	}
+	doc := configProfileDoc{
+		DocID:    st.docID(name),
+		EnvUUID:  st.EnvironUUID(),
+		Name:     name,
+		Settings: copyMap(settings, escapeReplacer.Replace),
+	}
+	ops := []txn.Op{
+		assertEnvAliveOp(st.EnvironUUID()),
+		{
+			C:      configProfilesC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		},
+	}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if err := checkEnvLife(st); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, errors.AlreadyExistsf("config profile %q", name)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ConfigProfile{st, doc}, nil
+}
+
+// ConfigProfile returns the config profile with the given name.
+func (st *State) ConfigProfile(name string) (*ConfigProfile, error) {
+	coll, closer := st.getCollection(configProfilesC)
+	defer closer()
+	var doc configProfileDoc
+	err := coll.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("config profile %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get config profile %q", name)
+	}
+	return &ConfigProfile{st, doc}, nil
+}
+
+// AllConfigProfiles returns all of the environment's config profiles,
+// ordered by name.
+func (st *State) AllConfigProfiles() ([]*ConfigProfile, error) {
+	coll, closer := st.getCollection(configProfilesC)
+	defer closer()
+	var docs []configProfileDoc
+	if err := coll.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get config profiles")
+	}
+	profiles := make([]*ConfigProfile, len(docs))
+	for i, doc := range docs {
+		profiles[i] = &ConfigProfile{st, doc}
+	}
+	return profiles, nil
+}
+
+// charmSettings returns the profile's settings that are options of
+// the given charm, validated against the charm's config. If strict
+// is false, settings that are not valid for the charm are dropped;
+// otherwise an error is returned.
+func (p *ConfigProfile) charmSettings(ch *Charm, strict bool) (charm.Settings, error) {
+	config := ch.Config()
+	settings := make(charm.Settings)
+	for key, value := range p.Settings() {
+		if _, ok := config.Options[key]; !ok {
+			continue
+		}
+		valid, err := config.ValidateSettings(charm.Settings{key: value})
+		if err != nil {
+			if strict {
+				return nil, errors.Annotatef(err, "config profile %q", p.Name())
+			}
+			configProfileLogger.Warningf("ignoring config profile %q setting: %v", p.Name(), err)
+			continue
+		}
+		settings[key] = valid[key]
+	}
+	return settings, nil
+}
+
+// serviceConfigProfileDoc records the config profile used by a service.
+type serviceConfigProfileDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Profile string `bson:"profile"`
+}
+
+// serviceConfigProfile returns the name of the config profile used by
+// the service with the given name, or "" if it does not use one.
+func serviceConfigProfile(st *State, serviceName string) (string, error) {
+	coll, closer := st.getCollection(serviceConfigProfilesC)
+	defer closer()
+	var doc serviceConfigProfileDoc
+	err := coll.FindId(serviceGlobalKey(serviceName)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Annotatef(err, "cannot get config profile for service %q", serviceName)
+	}
+	return doc.Profile, nil
+}
+
+// serviceConfigProfileSettings returns the settings of the config
+// profile used by the named service that apply to the given charm.
+func serviceConfigProfileSettings(st *State, serviceName string, ch *Charm) (charm.Settings, error) {
+	name, err := serviceConfigProfile(st, serviceName)
+	if err != nil || name == "" {
+		return nil, errors.Trace(err)
+	}
+	profile, err := st.ConfigProfile(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return profile.charmSettings(ch, false)
+}
+
+// ConfigProfile returns the name of the config profile used by the
+// service, or "" if the service does not use a config profile.
+func (s *Service) ConfigProfile() (string, error) {
+	return serviceConfigProfile(s.st, s.doc.Name)
+}
+
+// ConfigProfileSettings returns the settings of the service's config
+// profile that are options of the service's charm. Settings the
+// service inherits from the profile are those that are not set
+// explicitly in the service's own config settings.
+func (s *Service) ConfigProfileSettings() (charm.Settings, error) {
+	ch, _, err := s.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return serviceConfigProfileSettings(s.st, s.doc.Name, ch)
+}
+
+// SetConfigProfile sets the config profile used by the service. An
+// empty name causes the service to stop using a config profile.
+func (s *Service) SetConfigProfile(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set config profile for service %q", s)
+	if name != "" {
+		ch, _, err := s.Charm()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := validateConfigProfile(s.st, name, ch); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	docID := s.st.docID(s.globalKey())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("service is no longer alive")
+		}
+		current, err := s.ConfigProfile()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if current == name {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		if name != "" {
+			ops = append(ops, txn.Op{
+				C:      configProfilesC,
+				Id:     s.st.docID(name),
+				Assert: txn.DocExists,
+			})
+		}
+		switch {
+		case current == "":
+			ops = append(ops, createServiceConfigProfileOp(s.st, s.globalKey(), name))
+		case name == "":
+			ops = append(ops, removeServiceConfigProfileOp(s.st, s.globalKey()))
+		default:
+			ops = append(ops, txn.Op{
+				C:      serviceConfigProfilesC,
+				Id:     docID,
+				Assert: bson.D{{"profile", current}},
+				Update: bson.D{{"$set", bson.D{{"profile", name}}}},
+			})
+		}
+		return ops, nil
+	}
+	return s.st.run(buildTxn)
+}
+
+// validateConfigProfile returns an error if the named config profile
+// does not exist, or if any of its settings that are options of the
+// given charm are not valid for the charm.
+func validateConfigProfile(st *State, name string, ch *Charm) error {
+	profile, err := st.ConfigProfile(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = profile.charmSettings(ch, true)
+	return errors.Trace(err)
+}
+
+// createServiceConfigProfileOp returns the operation needed to record
+// that the service with the given global key uses the named config
+// profile.
+func createServiceConfigProfileOp(st *State, globalKey, name string) txn.Op {
+	return txn.Op{
+		C:      serviceConfigProfilesC,
+		Id:     st.docID(globalKey),
+		Assert: txn.DocMissing,
+		Insert: &serviceConfigProfileDoc{
+			EnvUUID: st.EnvironUUID(),
+			Profile: name,
+		},
+	}
+}
+
+// removeServiceConfigProfileOp returns the operation needed to remove
+// the record of the config profile used by the service with the given
+// global key.
+func removeServiceConfigProfileOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      serviceConfigProfilesC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type ConfigProfilesSuite struct {
+	ConnSuite
+	charm   *state.Charm
+	service *state.Service
+}
+
+var _ = gc.Suite(&ConfigProfilesSuite{})
+
+func (s *ConfigProfilesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "dummy")
+	s.service = s.AddTestingService(c, "dummy", s.charm)
+}
+
+func (s *ConfigProfilesSuite) TestAddConfigProfile(c *gc.C) {
+	settings := map[string]interface{}{
+		"title":       "Production",
+		"skill-level": 9,
+		"other.key":   true,
+	}
+	profile, err := s.State.AddConfigProfile("production", settings)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile.Name(), gc.Equals, "production")
+	c.Assert(profile.Settings(), jc.DeepEquals, settings)
+
+	profile, err = s.State.ConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile.Name(), gc.Equals, "production")
+	c.Assert(profile.Settings(), jc.DeepEquals, settings)
+}
+
+func (s *ConfigProfilesSuite) TestAddConfigProfileAlreadyExists(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddConfigProfile("production", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add config profile "production": config profile "production" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ConfigProfilesSuite) TestAddConfigProfileInvalid(c *gc.C) {
+	_, err := s.State.AddConfigProfile("Production!", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add config profile "Production!": config profile name not valid`)
+	_, err = s.State.AddConfigProfile("production", map[string]interface{}{
+		"title": []string{"a", "b"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add config profile "production": value of "title" must be a string, number or boolean, got \[\]string`)
+}
+
+func (s *ConfigProfilesSuite) TestConfigProfileNotFound(c *gc.C) {
+	_, err := s.State.ConfigProfile("production")
+	c.Assert(err, gc.ErrorMatches, `config profile "production" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigProfilesSuite) TestAllConfigProfiles(c *gc.C) {
+	profiles, err := s.State.AllConfigProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, gc.HasLen, 0)
+
+	for _, name := range []string{"staging", "production", "dev"} {
+		_, err := s.State.AddConfigProfile(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	profiles, err = s.State.AllConfigProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, profile := range profiles {
+		names = append(names, profile.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"dev", "production", "staging"})
+}
+
+func (s *ConfigProfilesSuite) TestSetConfigProfile(c *gc.C) {
+	for _, name := range []string{"production", "staging"} {
+		_, err := s.State.AddConfigProfile(name, map[string]interface{}{"title": name})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.assertConfigProfile(c, "")
+
+	err := s.service.SetConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertConfigProfile(c, "production")
+
+	err = s.service.SetConfigProfile("staging")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertConfigProfile(c, "staging")
+
+	err = s.service.SetConfigProfile("")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertConfigProfile(c, "")
+}
+
+func (s *ConfigProfilesSuite) assertConfigProfile(c *gc.C, expected string) {
+	name, err := s.service.ConfigProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, expected)
+}
+
+func (s *ConfigProfilesSuite) TestSetConfigProfileNotFound(c *gc.C) {
+	err := s.service.SetConfigProfile("production")
+	c.Assert(err, gc.ErrorMatches, `cannot set config profile for service "dummy": config profile "production" not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigProfilesSuite) TestSetConfigProfileInvalidForCharm(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{"skill-level": "expert"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetConfigProfile("production")
+	c.Assert(err, gc.ErrorMatches, `cannot set config profile for service "dummy": config profile "production": option "skill-level" expected int, got "expert"`)
+}
+
+func (s *ConfigProfilesSuite) TestSetConfigProfileServiceNotAlive(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetConfigProfile("production")
+	c.Assert(err, gc.ErrorMatches, `cannot set config profile for service "dummy": service is no longer alive`)
+}
+
+func (s *ConfigProfilesSuite) TestAddServiceWithConfigProfile(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{"title": "production"})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.AddServiceWithOptions(
+		"wordpress", s.Owner.String(), s.charm, nil, nil,
+		state.AddServiceOptions{ConfigProfile: "production"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	name, err := service.ConfigProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "production")
+}
+
+func (s *ConfigProfilesSuite) TestAddServiceWithConfigProfileInvalidForCharm(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{"skill-level": "expert"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddServiceWithOptions(
+		"wordpress", s.Owner.String(), s.charm, nil, nil,
+		state.AddServiceOptions{ConfigProfile: "production"},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": config profile "production": option "skill-level" expected int, got "expert"`)
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigProfilesSuite) TestConfigProfileSettings(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{
+		"title":       "Production",
+		"skill-level": 9,
+		"unknown":     "ignored",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := s.service.ConfigProfileSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+
+	err = s.service.SetConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = s.service.ConfigProfileSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"title":       "Production",
+		"skill-level": int64(9),
+	})
+}
+
+func (s *ConfigProfilesSuite) TestUnitConfigSettingsLayering(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{
+		"title":   "Production",
+		"outlook": "sunny",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetCharmURL(s.charm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.UpdateConfigSettings(charm.Settings{"outlook": "cloudy"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Explicit settings take precedence over the profile, which takes
+	// precedence over the charm defaults.
+	settings, err := unit.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"title":    "Production",
+		"outlook":  "cloudy",
+		"username": "admin001",
+	})
+
+	err = s.service.SetConfigProfile("")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = unit.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"title":    "My Title",
+		"outlook":  "cloudy",
+		"username": "admin001",
+	})
+}
+
+func (s *ConfigProfilesSuite) TestWatchConfigSettingsProfileChange(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", map[string]interface{}{"title": "Production"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetCharmURL(s.charm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	w, err := unit.WatchConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.service.SetConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.service.SetConfigProfile("")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *ConfigProfilesSuite) TestConfigProfileRemovedWithService(c *gc.C) {
+	_, err := s.State.AddConfigProfile("production", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll := s.MgoSuite.Session.DB("juju").C("serviceconfigprofiles")
+	var docs []bson.M
+	err = coll.Find(nil).All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 0)
+
+	// The profile itself is left alone.
+	_, err = s.State.ConfigProfile("production")
+	c.Assert(err, jc.ErrorIsNil)
+}
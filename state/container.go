@@ -44,15 +44,15 @@ func (st *State) insertNewContainerRefOp(machineId string, children ...string) t
 
 // removeContainerRefOps returns the txn.Op's necessary to remove a machine container record.
 // These include removing the record itself and updating the host machine's children property.
-func removeContainerRefOps(st *State, machineId string) []txn.Op {
+// The parentId is the id of the machine currently hosting the container, or "" if
+// machineId is not for a container.
+func removeContainerRefOps(st *State, machineId, parentId string) []txn.Op {
 	removeRefOp := txn.Op{
 		C:      containerRefsC,
 		Id:     st.docID(machineId),
 		Assert: txn.DocExists,
 		Remove: true,
 	}
-	// If the machine is a container, figure out its parent host.
-	parentId := ParentId(machineId)
 	if parentId == "" {
 		return []txn.Op{removeRefOp}
 	}
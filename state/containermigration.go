@@ -0,0 +1,399 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+)
+
+// ContainerMigrationStatus describes the progress of a container
+// migration.
+type ContainerMigrationStatus string
+
+const (
+	// MigrationPending is the status of a migration which has been
+	// requested, but whose container has not yet been exported by the
+	// source host.
+	MigrationPending ContainerMigrationStatus = "pending"
+
+	// MigrationExported is the status of a migration whose container
+	// has been stopped and its filesystem uploaded by the source host,
+	// ready to be imported by the target host.
+	MigrationExported ContainerMigrationStatus = "exported"
+
+	// MigrationImported is the status of a migration whose container
+	// has been started on the target host, but whose stopped copy is
+	// yet to be removed by the source host.
+	MigrationImported ContainerMigrationStatus = "imported"
+
+	// MigrationDone is the status of a migration whose container has
+	// been removed from the source host.
+	MigrationDone ContainerMigrationStatus = "done"
+
+	// MigrationFailed is the status of a migration which could not be
+	// completed, and whose container is yet to be restarted on the
+	// source host.
+	MigrationFailed ContainerMigrationStatus = "failed"
+
+	// MigrationRolledBack is the status of a failed migration whose
+	// container has been restarted on the source host.
+	MigrationRolledBack ContainerMigrationStatus = "rolled-back"
+)
+
+// Active reports whether a migration with the status is in progress.
+func (s ContainerMigrationStatus) Active() bool {
+	switch s {
+	case MigrationPending, MigrationExported, MigrationImported, MigrationFailed:
+		return true
+	}
+	return false
+}
+
+// containerMigrationDoc records the migration of a container from one
+// host machine to another. There is at most one per container, keyed
+// on the container's machine id.
+type containerMigrationDoc struct {
+	DocID        string                   `bson:"_id"`
+	EnvUUID      string                   `bson:"env-uuid"`
+	ContainerId  string                   `bson:"containerid"`
+	SourceHostId string                   `bson:"sourcehostid"`
+	TargetHostId string                   `bson:"targethostid"`
+	Status       ContainerMigrationStatus `bson:"status"`
+	Message      string                   `bson:"message,omitempty"`
+}
+
+// ContainerMigration represents the migration of a container from one
+// host machine to another.
+type ContainerMigration struct {
+	st  *State
+	doc containerMigrationDoc
+}
+
+// ContainerId returns the machine id of the container being migrated.
+func (m *ContainerMigration) ContainerId() string {
+	return m.doc.ContainerId
+}
+
+// SourceHostId returns the id of the machine the container is being
+// migrated from.
+func (m *ContainerMigration) SourceHostId() string {
+	return m.doc.SourceHostId
+}
+
+// TargetHostId returns the id of the machine the container is being
+// migrated to.
+func (m *ContainerMigration) TargetHostId() string {
+	return m.doc.TargetHostId
+}
+
+// Status returns the progress of the migration.
+func (m *ContainerMigration) Status() ContainerMigrationStatus {
+	return m.doc.Status
+}
+
+// Message returns the reason a failed migration failed.
+func (m *ContainerMigration) Message() string {
+	return m.doc.Message
+}
+
+// String returns a human readable description of the migration.
+func (m *ContainerMigration) String() string {
+	return fmt.Sprintf("migration of container %s from machine %s to machine %s",
+		m.doc.ContainerId, m.doc.SourceHostId, m.doc.TargetHostId)
+}
+
+// Refresh refreshes the contents of the migration from the underlying
+// state.
+func (m *ContainerMigration) Refresh() error {
+	migrations, closer := m.st.getCollection(containerMigrationsC)
+	defer closer()
+
+	err := migrations.FindId(m.doc.DocID).One(&m.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("migration of container %s", m.doc.ContainerId)
+	}
+	return errors.Trace(err)
+}
+
+// MigrateContainer requests that the container with the given machine
+// id be moved to the machine with targetHostId. The container's current
+// host stops and exports it, after which the target host imports and
+// starts it; see ContainerMigration for the steps that follow. Only LXC
+// and KVM containers can be migrated. The container keeps its id, which
+// still names its original host; the host it runs on is recorded
+// separately and reported by Machine.ParentId.
+func (st *State) MigrateContainer(containerId, targetHostId string) (_ *ContainerMigration, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot migrate container %s to machine %s", containerId, targetHostId)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		container, err := st.Machine(containerId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sourceHostId, isContainer := container.ParentId()
+		if !isContainer {
+			return nil, errors.Errorf("machine %s is not a container", containerId)
+		}
+		if container.Life() != Alive {
+			return nil, errors.Errorf("container is not alive")
+		}
+		if _, err := container.InstanceId(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctype := container.ContainerType()
+		if ctype != instance.LXC && ctype != instance.KVM {
+			return nil, errors.NotSupportedf("migrating %s containers", ctype)
+		}
+		if len(container.doc.Filesystems) > 0 || len(container.doc.Volumes) > 0 {
+			// Only storage held inside the rootfs moves with it.
+			return nil, errors.NotSupportedf("migrating containers with provider storage")
+		}
+		if targetHostId == sourceHostId {
+			return nil, errors.Errorf("container is already on machine %s", targetHostId)
+		}
+		target, err := st.Machine(targetHostId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if target.Life() != Alive {
+			return nil, errors.Errorf("machine %s is not alive", targetHostId)
+		}
+		if target.Cordoned() {
+			return nil, errors.Errorf("machine %s is cordoned", targetHostId)
+		}
+		if supported, known := target.SupportedContainers(); known && !containerTypeIn(ctype, supported) {
+			return nil, errors.Errorf("machine %s does not support %s containers", targetHostId, ctype)
+		}
+
+		// The container must not move while the migration is requested.
+		hostAssert := bson.DocElem{"hostid", container.doc.HostId}
+		if container.doc.HostId == "" {
+			hostAssert.Value = bson.D{{"$exists", false}}
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     container.doc.DocID,
+			Assert: bson.D{{"life", Alive}, hostAssert},
+		}, {
+			C:      machinesC,
+			Id:     target.doc.DocID,
+			Assert: append(isAliveDoc, notCordonedDoc...),
+		}}
+		doc := &containerMigrationDoc{
+			ContainerId:  containerId,
+			SourceHostId: sourceHostId,
+			TargetHostId: targetHostId,
+			Status:       MigrationPending,
+		}
+		existing, err := st.ContainerMigration(containerId)
+		switch {
+		case errors.IsNotFound(err):
+			ops = append(ops, txn.Op{
+				C:      containerMigrationsC,
+				Id:     st.docID(containerId),
+				Assert: txn.DocMissing,
+				Insert: doc,
+			})
+		case err != nil:
+			return nil, errors.Trace(err)
+		case existing.Status().Active():
+			return nil, errors.Errorf("container is already being migrated to machine %s", existing.TargetHostId())
+		default:
+			// Replace the record of the previous migration.
+			ops = append(ops, txn.Op{
+				C:      containerMigrationsC,
+				Id:     existing.doc.DocID,
+				Assert: bson.D{{"status", existing.Status()}},
+				Update: bson.D{
+					{"$set", bson.D{
+						{"sourcehostid", doc.SourceHostId},
+						{"targethostid", doc.TargetHostId},
+						{"status", doc.Status},
+					}},
+					{"$unset", bson.D{{"message", nil}}},
+				},
+			})
+		}
+		return ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.ContainerMigration(containerId)
+}
+
+func containerTypeIn(ctype instance.ContainerType, types []instance.ContainerType) bool {
+	for _, t := range types {
+		if t == ctype {
+			return true
+		}
+	}
+	return false
+}
+
+func removeContainerMigrationOp(st *State, containerId string) txn.Op {
+	return txn.Op{
+		C:      containerMigrationsC,
+		Id:     st.docID(containerId),
+		Remove: true,
+	}
+}
+
+// ContainerMigration returns the most recent migration of the
+// container with the given machine id.
+func (st *State) ContainerMigration(containerId string) (*ContainerMigration, error) {
+	migrations, closer := st.getCollection(containerMigrationsC)
+	defer closer()
+
+	var doc containerMigrationDoc
+	err := migrations.FindId(st.docID(containerId)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("migration of container %s", containerId)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get migration of container %s", containerId)
+	}
+	return &ContainerMigration{st, doc}, nil
+}
+
+// HostContainerMigrations returns the active migrations of containers
+// to or from the machine with the given id.
+func (st *State) HostContainerMigrations(hostId string) ([]*ContainerMigration, error) {
+	migrations, closer := st.getCollection(containerMigrationsC)
+	defer closer()
+
+	var docs []containerMigrationDoc
+	err := migrations.Find(bson.D{
+		{"$or", []bson.D{
+			{{"sourcehostid", hostId}},
+			{{"targethostid", hostId}},
+		}},
+		{"status", bson.D{{"$in", []ContainerMigrationStatus{
+			MigrationPending, MigrationExported, MigrationImported, MigrationFailed,
+		}}}},
+	}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get container migrations for machine %s", hostId)
+	}
+	result := make([]*ContainerMigration, len(docs))
+	for i, doc := range docs {
+		result[i] = &ContainerMigration{st, doc}
+	}
+	return result, nil
+}
+
+// WatchContainerMigrations returns a NotifyWatcher that notifies of
+// changes to any container migration in the environment.
+func (st *State) WatchContainerMigrations() NotifyWatcher {
+	return newNotifyCollWatcher(st, containerMigrationsC, st.isForStateEnv)
+}
+
+// SetExported records that the source host has stopped the container
+// and uploaded its filesystem.
+func (m *ContainerMigration) SetExported() error {
+	return errors.Trace(m.setStatus(MigrationPending, MigrationExported, ""))
+}
+
+// Fail records that the migration could not be completed, so the
+// source host should restart the container.
+func (m *ContainerMigration) Fail(message string) error {
+	var from ContainerMigrationStatus
+	switch m.doc.Status {
+	case MigrationPending, MigrationExported:
+		from = m.doc.Status
+	default:
+		return errors.Errorf("cannot fail %s: status is %q", m, m.doc.Status)
+	}
+	return errors.Trace(m.setStatus(from, MigrationFailed, message))
+}
+
+// SetRolledBack records that the source host has restarted the
+// container after the migration failed.
+func (m *ContainerMigration) SetRolledBack() error {
+	return errors.Trace(m.setStatus(MigrationFailed, MigrationRolledBack, m.doc.Message))
+}
+
+func (m *ContainerMigration) setStatus(from, to ContainerMigrationStatus, message string) error {
+	ops := []txn.Op{{
+		C:      containerMigrationsC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"status", from}},
+		Update: bson.D{{"$set", bson.D{{"status", to}, {"message", message}}}},
+	}}
+	if err := m.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot set status of %s to %q: status is not %q", m, to, from)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set status of %s to %q", m, to)
+	}
+	m.doc.Status = to
+	m.doc.Message = message
+	return nil
+}
+
+// SetImported records that the container has been started on the target
+// host. The container's machine is moved under the target host, and its
+// addresses are cleared until the container reports those it has been
+// given there.
+func (m *ContainerMigration) SetImported() error {
+	if m.doc.Status != MigrationExported {
+		return errors.Errorf("cannot set status of %s to %q: status is %q", m, MigrationImported, m.doc.Status)
+	}
+	containerDocId := m.st.docID(m.doc.ContainerId)
+	hostId := m.doc.TargetHostId
+	if hostId == ParentId(m.doc.ContainerId) {
+		// Moved back to where it started.
+		hostId = ""
+	}
+	clearAddresses := bson.D{{"addresses", []address{}}, {"machineaddresses", []address{}}}
+	update := bson.D{{"$set", append(clearAddresses, bson.DocElem{"hostid", hostId})}}
+	if hostId == "" {
+		update = bson.D{{"$set", clearAddresses}, {"$unset", bson.D{{"hostid", nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      containerMigrationsC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"status", MigrationExported}},
+		Update: bson.D{{"$set", bson.D{{"status", MigrationImported}}}},
+	}, {
+		C:      machinesC,
+		Id:     containerDocId,
+		Assert: notDeadDoc,
+		Update: update,
+	}, {
+		C:      containerRefsC,
+		Id:     m.st.docID(m.doc.SourceHostId),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$pull", bson.D{{"children", m.doc.ContainerId}}}},
+	},
+		m.st.addChildToContainerRefOp(m.doc.TargetHostId, m.doc.ContainerId),
+	}
+	if err := m.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := m.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Errorf("cannot set status of %s to %q: status is %q or container is dead", m, MigrationImported, m.doc.Status)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set status of %s to %q", m, MigrationImported)
+	}
+	m.doc.Status = MigrationImported
+	return nil
+}
+
+// SetDone records that the source host has removed its stopped copy of
+// the container, completing the migration.
+func (m *ContainerMigration) SetDone() error {
+	return errors.Trace(m.setStatus(MigrationImported, MigrationDone, ""))
+}
+
+// ArchivePath returns the path in environment storage at which the
+// archive of the container's filesystem is held while it is moved.
+func (m *ContainerMigration) ArchivePath() string {
+	return fmt.Sprintf("containermigrations/%s/rootfs.tar.gz", m.doc.ContainerId)
+}
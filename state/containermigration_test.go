@@ -0,0 +1,382 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type ContainerMigrationSuite struct {
+	ConnSuite
+	source    *state.Machine
+	target    *state.Machine
+	container *state.Machine
+}
+
+var _ = gc.Suite(&ContainerMigrationSuite{})
+
+func (s *ContainerMigrationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.source = s.addHost(c)
+	s.target = s.addHost(c)
+	var err error
+	s.container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.source.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetProvisioned("juju-machine-0-lxc-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ContainerMigrationSuite) addHost(c *gc.C) *state.Machine {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+	c.Assert(err, jc.ErrorIsNil)
+	return machine
+}
+
+func (s *ContainerMigrationSuite) migrate(c *gc.C) *state.ContainerMigration {
+	migration, err := s.State.MigrateContainer(s.container.Id(), s.target.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	return migration
+}
+
+// completeMigration takes the migration through to being imported on
+// the target host.
+func (s *ContainerMigrationSuite) completeMigration(c *gc.C, migration *state.ContainerMigration) {
+	err := migration.SetExported()
+	c.Assert(err, jc.ErrorIsNil)
+	err = migration.SetImported()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ContainerMigrationSuite) TestMigrateContainer(c *gc.C) {
+	migration := s.migrate(c)
+	c.Assert(migration.ContainerId(), gc.Equals, "0/lxc/0")
+	c.Assert(migration.SourceHostId(), gc.Equals, "0")
+	c.Assert(migration.TargetHostId(), gc.Equals, "1")
+	c.Assert(migration.Status(), gc.Equals, state.MigrationPending)
+	c.Assert(migration.String(), gc.Equals, "migration of container 0/lxc/0 from machine 0 to machine 1")
+
+	found, err := s.State.ContainerMigration(s.container.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Status(), gc.Equals, state.MigrationPending)
+}
+
+func (s *ContainerMigrationSuite) TestContainerMigrationNotFound(c *gc.C) {
+	_, err := s.State.ContainerMigration(s.container.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "migration of container 0/lxc/0 not found")
+}
+
+func (s *ContainerMigrationSuite) TestMigrateContainerErrors(c *gc.C) {
+	unprovisioned, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.source.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.source.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM, instance.LXD})
+	c.Assert(err, jc.ErrorIsNil)
+	lxd, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.source.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = lxd.SetProvisioned("juju-machine-0-lxd-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	noLXC, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = noLXC.SupportsNoContainers()
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		container string
+		target    string
+		err       string
+	}{{
+		container: "0",
+		target:    "1",
+		err:       "cannot migrate container 0 to machine 1: machine 0 is not a container",
+	}, {
+		container: "0/lxc/5",
+		target:    "1",
+		err:       "cannot migrate container 0/lxc/5 to machine 1: machine 0/lxc/5 not found",
+	}, {
+		container: unprovisioned.Id(),
+		target:    "1",
+		err:       `cannot migrate container 0/lxc/1 to machine 1: machine 0/lxc/1 not provisioned`,
+	}, {
+		container: lxd.Id(),
+		target:    "1",
+		err:       "cannot migrate container 0/lxd/0 to machine 1: migrating lxd containers not supported",
+	}, {
+		container: "0/lxc/0",
+		target:    "0",
+		err:       "cannot migrate container 0/lxc/0 to machine 0: container is already on machine 0",
+	}, {
+		container: "0/lxc/0",
+		target:    "7",
+		err:       "cannot migrate container 0/lxc/0 to machine 7: machine 7 not found",
+	}, {
+		container: "0/lxc/0",
+		target:    noLXC.Id(),
+		err:       "cannot migrate container 0/lxc/0 to machine 2: machine 2 does not support lxc containers",
+	}} {
+		c.Logf("test %d: %s to %s", i, test.container, test.target)
+		_, err := s.State.MigrateContainer(test.container, test.target)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainerMigrationSuite) TestMigrateContainerDying(c *gc.C) {
+	err := s.container.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MigrateContainer(s.container.Id(), s.target.Id())
+	c.Assert(err, gc.ErrorMatches, "cannot migrate container 0/lxc/0 to machine 1: container is not alive")
+}
+
+func (s *ContainerMigrationSuite) TestMigrateContainerTargetCordoned(c *gc.C) {
+	err := s.target.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MigrateContainer(s.container.Id(), s.target.Id())
+	c.Assert(err, gc.ErrorMatches, "cannot migrate container 0/lxc/0 to machine 1: machine 1 is cordoned")
+}
+
+func (s *ContainerMigrationSuite) TestMigrateContainerAlreadyMigrating(c *gc.C) {
+	s.migrate(c)
+	_, err := s.State.MigrateContainer(s.container.Id(), s.target.Id())
+	c.Assert(err, gc.ErrorMatches, "cannot migrate container 0/lxc/0 to machine 1: container is already being migrated to machine 1")
+}
+
+func (s *ContainerMigrationSuite) TestStatusTransitions(c *gc.C) {
+	migration := s.migrate(c)
+	err := migration.SetImported()
+	c.Assert(err, gc.ErrorMatches, `cannot set status of .* to "imported": status is "pending"`)
+	err = migration.SetDone()
+	c.Assert(err, gc.ErrorMatches, `cannot set status of .* to "done": status is not "imported"`)
+
+	err = migration.SetExported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationExported)
+	err = migration.SetExported()
+	c.Assert(err, gc.ErrorMatches, `cannot set status of .* to "exported": status is not "pending"`)
+
+	err = migration.SetImported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationImported)
+	err = migration.Fail("too late")
+	c.Assert(err, gc.ErrorMatches, `cannot fail .*: status is "imported"`)
+
+	err = migration.SetDone()
+	c.Assert(err, jc.ErrorIsNil)
+	err = migration.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationDone)
+}
+
+func (s *ContainerMigrationSuite) TestFailAndRollBack(c *gc.C) {
+	migration := s.migrate(c)
+	err := migration.SetExported()
+	c.Assert(err, jc.ErrorIsNil)
+	err = migration.Fail("lxc-start failed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationFailed)
+	c.Assert(migration.Message(), gc.Equals, "lxc-start failed")
+
+	err = migration.SetRolledBack()
+	c.Assert(err, jc.ErrorIsNil)
+	err = migration.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationRolledBack)
+	c.Assert(migration.Message(), gc.Equals, "lxc-start failed")
+
+	// The container stays where it was.
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	parentId, _ := s.container.ParentId()
+	c.Assert(parentId, gc.Equals, s.source.Id())
+
+	// A rolled back migration may be tried again.
+	migration = s.migrate(c)
+	c.Assert(migration.Status(), gc.Equals, state.MigrationPending)
+	c.Assert(migration.Message(), gc.Equals, "")
+}
+
+func (s *ContainerMigrationSuite) TestSetImportedMovesContainer(c *gc.C) {
+	err := s.container.SetProviderAddresses(network.NewAddress("10.0.3.1"))
+	c.Assert(err, jc.ErrorIsNil)
+	migration := s.migrate(c)
+	s.completeMigration(c, migration)
+
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	parentId, isContainer := s.container.ParentId()
+	c.Assert(isContainer, jc.IsTrue)
+	c.Assert(parentId, gc.Equals, s.target.Id())
+	c.Assert(s.container.Addresses(), gc.HasLen, 0)
+
+	containers, err := s.source.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+	containers, err = s.target.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, jc.DeepEquals, []string{"0/lxc/0"})
+
+	// The container can be moved back to its original host.
+	err = migration.SetDone()
+	c.Assert(err, jc.ErrorIsNil)
+	migration, err = s.State.MigrateContainer(s.container.Id(), s.source.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migration.SourceHostId(), gc.Equals, s.target.Id())
+	s.completeMigration(c, migration)
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	parentId, _ = s.container.ParentId()
+	c.Assert(parentId, gc.Equals, s.source.Id())
+}
+
+func (s *ContainerMigrationSuite) TestHostContainerMigrations(c *gc.C) {
+	s.migrate(c)
+	for _, hostId := range []string{s.source.Id(), s.target.Id()} {
+		migrations, err := s.State.HostContainerMigrations(hostId)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(migrations, gc.HasLen, 1)
+		c.Assert(migrations[0].ContainerId(), gc.Equals, s.container.Id())
+	}
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	migrations, err := s.State.HostContainerMigrations(other.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrations, gc.HasLen, 0)
+}
+
+func (s *ContainerMigrationSuite) TestHostContainerMigrationsInactive(c *gc.C) {
+	migration := s.migrate(c)
+	s.completeMigration(c, migration)
+	err := migration.SetDone()
+	c.Assert(err, jc.ErrorIsNil)
+	migrations, err := s.State.HostContainerMigrations(s.source.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrations, gc.HasLen, 0)
+}
+
+func (s *ContainerMigrationSuite) TestWatchContainerMigrations(c *gc.C) {
+	w := s.State.WatchContainerMigrations()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	migration := s.migrate(c)
+	wc.AssertOneChange()
+	err := migration.SetExported()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	wc.AssertNoChange()
+}
+
+func (s *ContainerMigrationSuite) TestWatchContainersFollowsMigration(c *gc.C) {
+	sourceW := s.source.WatchContainers(instance.LXC)
+	defer statetesting.AssertStop(c, sourceW)
+	sourceC := statetesting.NewStringsWatcherC(c, s.State, sourceW)
+	sourceC.AssertChange("0/lxc/0")
+	sourceC.AssertNoChange()
+
+	targetW := s.target.WatchAllContainers()
+	defer statetesting.AssertStop(c, targetW)
+	targetC := statetesting.NewStringsWatcherC(c, s.State, targetW)
+	targetC.AssertChange()
+	targetC.AssertNoChange()
+
+	migration := s.migrate(c)
+	s.completeMigration(c, migration)
+	targetC.AssertChange("0/lxc/0")
+	targetC.AssertNoChange()
+	// The source host no longer sees the container.
+	sourceC.AssertNoChange()
+
+	err := s.container.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	targetC.AssertChange("0/lxc/0")
+	targetC.AssertNoChange()
+	sourceC.AssertNoChange()
+}
+
+func (s *ContainerMigrationSuite) TestMigrateKVMContainer(c *gc.C) {
+	kvm, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.source.Id(), instance.KVM)
+	c.Assert(err, jc.ErrorIsNil)
+	err = kvm.SetProvisioned("juju-machine-0-kvm-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	sourceW := s.source.WatchContainers(instance.KVM)
+	defer statetesting.AssertStop(c, sourceW)
+	sourceC := statetesting.NewStringsWatcherC(c, s.State, sourceW)
+	sourceC.AssertChange("0/kvm/0")
+	sourceC.AssertNoChange()
+
+	targetW := s.target.WatchContainers(instance.KVM)
+	defer statetesting.AssertStop(c, targetW)
+	targetC := statetesting.NewStringsWatcherC(c, s.State, targetW)
+	targetC.AssertChange()
+	targetC.AssertNoChange()
+
+	migration, err := s.State.MigrateContainer(kvm.Id(), s.target.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.completeMigration(c, migration)
+	targetC.AssertChange("0/kvm/0")
+	targetC.AssertNoChange()
+	sourceC.AssertNoChange()
+
+	err = kvm.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	hostId, isContainer := kvm.ParentId()
+	c.Assert(isContainer, jc.IsTrue)
+	c.Assert(hostId, gc.Equals, s.target.Id())
+	containers, err := s.target.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, jc.DeepEquals, []string{"0/kvm/0"})
+	containers, err = s.source.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, jc.DeepEquals, []string{"0/lxc/0"})
+
+	// Rebooting the new host shuts the container down.
+	err = s.target.SetRebootFlag(true)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := kvm.ShouldRebootOrShutdown()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action, gc.Equals, state.ShouldShutdown)
+	err = s.source.SetRebootFlag(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.target.SetRebootFlag(false)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = kvm.ShouldRebootOrShutdown()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action, gc.Equals, state.ShouldDoNothing)
+}
+
+func (s *ContainerMigrationSuite) TestRemoveMigratedContainer(c *gc.C) {
+	migration := s.migrate(c)
+	s.completeMigration(c, migration)
+	err := s.container.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	containers, err := s.target.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+	_, err = s.State.ContainerMigration(s.container.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
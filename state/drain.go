@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// DrainedUnit records a unit removed from a machine while draining
+// it, along with the unit added elsewhere to replace it. Replacement
+// is empty if no replacement unit was added.
+type DrainedUnit struct {
+	Unit        string
+	Replacement string
+}
+
+// Drain cordons the machine and then moves its principal units
+// elsewhere, one at a time in name order: a replacement unit is added
+// to the unit's service and assigned to a clean, empty machine before
+// the original unit is destroyed. Units of services that are being
+// destroyed are removed without replacement. Containers on the machine
+// are left untouched, and must be migrated or removed separately.
+//
+// Drain is not health-gated: each original unit is destroyed as soon
+// as its replacement has been assigned to a machine, without waiting
+// for the replacement to be deployed or to report that it is working.
+//
+// Drain stops at the first error, returning the units drained so far.
+func (m *Machine) Drain() ([]DrainedUnit, error) {
+	if err := m.SetCordoned(true); err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var principals []*Unit
+	for _, unit := range units {
+		if unit.IsPrincipal() && unit.Life() == Alive {
+			principals = append(principals, unit)
+		}
+	}
+	sort.Sort(unitsByName(principals))
+
+	var drained []DrainedUnit
+	for _, unit := range principals {
+		result, err := m.drainUnit(unit)
+		if err != nil {
+			return drained, errors.Annotatef(err, "cannot drain unit %q", unit)
+		}
+		drained = append(drained, result)
+	}
+	return drained, nil
+}
+
+// drainUnit replaces the given unit elsewhere, if its service is
+// still alive, and then destroys it. If the replacement cannot be
+// assigned, it is destroyed again and the original unit is left alone.
+func (m *Machine) drainUnit(unit *Unit) (DrainedUnit, error) {
+	result := DrainedUnit{Unit: unit.Name()}
+	svc, err := unit.Service()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if svc.Life() == Alive {
+		replacement, err := svc.AddUnit()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if err := m.st.AssignUnit(replacement, AssignCleanEmpty); err != nil {
+			if destroyErr := replacement.Destroy(); destroyErr != nil {
+				logger.Errorf("cannot destroy unassigned replacement unit %q: %v", replacement, destroyErr)
+			}
+			return result, errors.Trace(err)
+		}
+		result.Replacement = replacement.Name()
+	}
+	if err := unit.Destroy(); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
+type unitsByName []*Unit
+
+func (u unitsByName) Len() int           { return len(u) }
+func (u unitsByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitsByName) Less(i, j int) bool { return u[i].Name() < u[j].Name() }
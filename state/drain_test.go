@@ -0,0 +1,192 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type DrainSuite struct {
+	ConnSuite
+	wordpress *state.Service
+	machine   *state.Machine
+}
+
+var _ = gc.Suite(&DrainSuite{})
+
+func (s *DrainSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetSupportedContainers([]instance.ContainerType{instance.LXC})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DrainSuite) TestSetCordoned(c *gc.C) {
+	c.Assert(s.machine.Cordoned(), jc.IsFalse)
+
+	err := s.machine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Cordoned(), jc.IsTrue)
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Cordoned(), jc.IsTrue)
+
+	err = s.machine.SetCordoned(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Cordoned(), jc.IsFalse)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Cordoned(), jc.IsFalse)
+}
+
+func (s *DrainSuite) TestSetCordonedDead(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetCordoned(true)
+	c.Assert(err, gc.ErrorMatches, "cannot cordon machine 0: machine is dead")
+	err = s.machine.SetCordoned(false)
+	c.Assert(err, gc.ErrorMatches, "cannot uncordon machine 0: machine is dead")
+}
+
+func (s *DrainSuite) TestCordonedMachineRejectsUnits(c *gc.C) {
+	err := s.machine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: machine is cordoned`)
+
+	// Clean machine policies pass over the cordoned machine.
+	_, err = unit.AssignToCleanEmptyMachine()
+	c.Assert(err, gc.ErrorMatches, `all eligible machines in use`)
+	_, err = unit.AssignToCleanMachine()
+	c.Assert(err, gc.ErrorMatches, `all eligible machines in use`)
+
+	err = s.machine.SetCordoned(false)
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit.AssignToCleanEmptyMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, s.machine.Id())
+}
+
+func (s *DrainSuite) TestCordonedMachineRejectsContainers(c *gc.C) {
+	err := s.machine.SetCordoned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachineInsideMachine(template, s.machine.Id(), instance.LXC)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: machine 0 is cordoned")
+
+	err = s.machine.SetCordoned(false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineInsideMachine(template, s.machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DrainSuite) TestDrain(c *gc.C) {
+	var units []*state.Unit
+	for i := 0; i < 2; i++ {
+		unit, err := s.wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		if i == 0 {
+			err = unit.AssignToMachine(s.machine)
+		} else {
+			err = unit.AssignToNewMachine()
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		units = append(units, unit)
+	}
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	unit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	units = append(units, unit)
+
+	drained, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drained, jc.DeepEquals, []state.DrainedUnit{
+		{Unit: "mysql/0", Replacement: "mysql/1"},
+		{Unit: "wordpress/0", Replacement: "wordpress/2"},
+	})
+	c.Assert(s.machine.Cordoned(), jc.IsTrue)
+
+	// The drained units were removed, and the unit on the other
+	// machine left alone.
+	for _, name := range []string{"mysql/0", "wordpress/0"} {
+		_, err := s.State.Unit(name)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+	}
+	err = units[1].Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units[1].Life(), gc.Equals, state.Alive)
+
+	// The replacements were placed elsewhere.
+	for _, name := range []string{"mysql/1", "wordpress/2"} {
+		unit, err := s.State.Unit(name)
+		c.Assert(err, jc.ErrorIsNil)
+		id, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(id, gc.Not(gc.Equals), s.machine.Id())
+	}
+}
+
+func (s *DrainSuite) TestDrainDyingService(c *gc.C) {
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	drained, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drained, jc.DeepEquals, []state.DrainedUnit{{Unit: "wordpress/0"}})
+	units, err := s.wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *DrainSuite) TestDrainAssignFailureDestroysReplacement(c *gc.C) {
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// With the environment dying, no new machine can be added for the
+	// replacement unit.
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	drained, err := s.machine.Drain()
+	c.Assert(err, gc.ErrorMatches, `cannot drain unit "wordpress/0": .*environment is no longer alive`)
+	c.Assert(drained, gc.HasLen, 0)
+
+	// The replacement was removed, and the original unit left alone.
+	_, err = s.State.Unit("wordpress/1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Life(), gc.Equals, state.Alive)
+}
+
+func (s *DrainSuite) TestDrainEmptyMachine(c *gc.C) {
+	drained, err := s.machine.Drain()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drained, gc.HasLen, 0)
+	c.Assert(s.machine.Cordoned(), jc.IsTrue)
+}
@@ -0,0 +1,196 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// EndpointBindings returns the space names to which the service's
+// relation endpoints are bound, keyed on endpoint name. Endpoints
+// without a binding are not included.
+func (s *Service) EndpointBindings() map[string]string {
+	bindings := make(map[string]string, len(s.doc.EndpointBindings))
+	for endpoint, space := range s.doc.EndpointBindings {
+		bindings[endpoint] = space
+	}
+	return bindings
+}
+
+// SetEndpointBindings replaces the service's endpoint bindings with the
+// supplied map of endpoint name to space name. Every endpoint must be
+// defined by the service's current charm, and every space must exist.
+func (s *Service) SetEndpointBindings(bindings map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set endpoint bindings for service %q", s)
+	ch, _, err := s.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateEndpointBindings(s.st, ch.Meta(), bindings); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"charmurl", ch.URL()}},
+		Update: bson.D{{"$set", bson.D{{"endpointbindings", bindings}}}},
+	}}
+	for _, space := range bindings {
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     s.st.docID(space),
+			Assert: isAliveDoc,
+		})
+	}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return errNotAlive
+		}
+		if *s.doc.CharmURL != *ch.URL() {
+			return errors.Errorf("service charm changed to %q", s.doc.CharmURL)
+		}
+		return errors.Errorf("one or more spaces are no longer alive")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.EndpointBindings = bindings
+	return nil
+}
+
+// validateEndpointBindings returns an error if any of the supplied bindings
+// refers to an endpoint not defined in the charm metadata, or to a space
+// that does not exist.
+func validateEndpointBindings(st *State, meta *charm.Meta, bindings map[string]string) error {
+	for endpoint, space := range bindings {
+		if !charmHasEndpoint(meta, endpoint) {
+			return errors.NewNotValid(nil, fmt.Sprintf("endpoint %q not defined by charm %q", endpoint, meta.Name))
+		}
+		if _, err := st.Space(space); errors.IsNotFound(err) {
+			return errors.NewNotValid(nil, fmt.Sprintf("endpoint %q bound to unknown space %q", endpoint, space))
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// endpointBindingsUpgradeOps returns an error if any of the service's
+// endpoint bindings refers to an endpoint that is not defined by the
+// charm with the supplied metadata. Otherwise it returns operations
+// asserting that none of the current charm's endpoints missing from the
+// new charm acquire a binding before the upgrade is applied.
+func (s *Service) endpointBindingsUpgradeOps(oldMeta, newMeta *charm.Meta) ([]txn.Op, error) {
+	for endpoint, space := range s.doc.EndpointBindings {
+		if !charmHasEndpoint(newMeta, endpoint) {
+			return nil, errors.Errorf(
+				"cannot upgrade service %q to charm %q: endpoint %q bound to space %q removed",
+				s, newMeta.Name, endpoint, space,
+			)
+		}
+	}
+	var unbound bson.D
+	for _, rels := range []map[string]charm.Relation{oldMeta.Provides, oldMeta.Requires, oldMeta.Peers} {
+		for endpoint := range rels {
+			if !charmHasEndpoint(newMeta, endpoint) {
+				unbound = append(unbound, bson.DocElem{
+					"endpointbindings." + endpoint, bson.D{{"$exists", false}},
+				})
+			}
+		}
+	}
+	if len(unbound) == 0 {
+		return nil, nil
+	}
+	return []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: unbound,
+	}}, nil
+}
+
+// charmHasEndpoint returns whether the named relation endpoint is
+// available to services using the charm with the supplied metadata.
+func charmHasEndpoint(meta *charm.Meta, endpoint string) bool {
+	if endpoint == "juju-info" {
+		return true
+	}
+	for _, rels := range []map[string]charm.Relation{meta.Provides, meta.Requires, meta.Peers} {
+		if _, ok := rels[endpoint]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EndpointAddress returns the address that other units should use to
+// reach the unit over the named relation endpoint, and whether it is
+// valid. If the endpoint is bound to a space, the returned address is
+// the unit's machine address within one of that space's subnets;
+// otherwise, it is the unit's private address.
+func (u *Unit) EndpointAddress(endpoint string) (string, bool) {
+	service, err := u.Service()
+	if err != nil {
+		unitLogger.Errorf("%v", err)
+		return "", false
+	}
+	spaceName, ok := service.doc.EndpointBindings[endpoint]
+	if !ok {
+		return u.PrivateAddress()
+	}
+	address, err := u.spaceAddress(spaceName)
+	if errors.IsNotFound(err) {
+		unitLogger.Warningf(
+			"unit %q has no address in space %q bound to endpoint %q; using private address",
+			u, spaceName, endpoint,
+		)
+		return u.PrivateAddress()
+	} else if err != nil {
+		unitLogger.Errorf("%v", err)
+		return "", false
+	}
+	return address, true
+}
+
+// spaceAddress returns the first of the unit's machine addresses that
+// falls within one of the subnets of the named space. It returns an
+// error satisfying errors.IsNotFound if there is no such address.
+func (u *Unit) spaceAddress(spaceName string) (string, error) {
+	space, err := u.st.Space(spaceName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var nets []*net.IPNet
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+		if err != nil {
+			return "", errors.Annotatef(err, "subnet %q of space %q", subnet, spaceName)
+		}
+		nets = append(nets, ipNet)
+	}
+	for _, address := range u.addressesOfMachine() {
+		ip := net.ParseIP(address.Value)
+		if ip == nil {
+			continue
+		}
+		for _, ipNet := range nets {
+			if ipNet.Contains(ip) {
+				return address.Value, nil
+			}
+		}
+	}
+	return "", errors.NotFoundf("address for unit %q in space %q", u, spaceName)
+}
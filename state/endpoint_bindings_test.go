@@ -0,0 +1,169 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type EndpointBindingsSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&EndpointBindingsSuite{})
+
+func (s *EndpointBindingsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	for _, info := range []state.SubnetInfo{{
+		CIDR:              "10.0.0.0/24",
+		AllocatableIPLow:  "10.0.0.10",
+		AllocatableIPHigh: "10.0.0.100",
+	}, {
+		CIDR:              "192.168.1.0/24",
+		AllocatableIPLow:  "192.168.1.10",
+		AllocatableIPHigh: "192.168.1.100",
+	}} {
+		_, err := s.State.AddSubnet(info)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.State.AddSpace("internal", []string{"10.0.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", []string{"192.168.1.0/24"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EndpointBindingsSuite) TestNoBindings(c *gc.C) {
+	c.Assert(s.service.EndpointBindings(), gc.HasLen, 0)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindings(c *gc.C) {
+	bindings := map[string]string{"db": "internal", "url": "public"}
+	err := s.service.SetEndpointBindings(bindings)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.EndpointBindings(), jc.DeepEquals, bindings)
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.EndpointBindings(), jc.DeepEquals, bindings)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsUnknownEndpoint(c *gc.C) {
+	err := s.service.SetEndpointBindings(map[string]string{"website": "public"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": endpoint "website" not defined by charm "wordpress"`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsUnknownSpace(c *gc.C) {
+	err := s.service.SetEndpointBindings(map[string]string{"db": "dmz"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": endpoint "db" bound to unknown space "dmz"`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsDeadService(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": .*`)
+}
+
+func (s *EndpointBindingsSuite) TestEndpointAddress(c *gc.C) {
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(
+		network.NewScopedAddress("192.168.1.20", network.ScopeCloudLocal),
+		network.NewScopedAddress("10.0.0.20", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.SetEndpointBindings(map[string]string{"db": "internal", "url": "public"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	address, ok := unit.EndpointAddress("db")
+	c.Check(ok, jc.IsTrue)
+	c.Check(address, gc.Equals, "10.0.0.20")
+
+	address, ok = unit.EndpointAddress("url")
+	c.Check(ok, jc.IsTrue)
+	c.Check(address, gc.Equals, "192.168.1.20")
+
+	// Unbound endpoints get the usual private address.
+	private, ok := unit.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	address, ok = unit.EndpointAddress("cache")
+	c.Check(ok, jc.IsTrue)
+	c.Check(address, gc.Equals, private)
+}
+
+func (s *EndpointBindingsSuite) TestAddServiceWithEndpointBindings(c *gc.C) {
+	owner := s.Owner.String()
+	bindings := map[string]string{"db": "internal"}
+	service, err := s.State.AddServiceWithOptions(
+		"blog", owner, s.AddTestingCharm(c, "wordpress"), nil, nil,
+		state.AddServiceOptions{EndpointBindings: bindings},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+}
+
+func (s *EndpointBindingsSuite) TestAddServiceWithInvalidEndpointBindings(c *gc.C) {
+	owner := s.Owner.String()
+	_, err := s.State.AddServiceWithOptions(
+		"blog", owner, s.AddTestingCharm(c, "wordpress"), nil, nil,
+		state.AddServiceOptions{EndpointBindings: map[string]string{"db": "dmz"}},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "blog": endpoint "db" bound to unknown space "dmz"`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+
+	// No half-configured service is left behind.
+	_, err = s.State.Service("blog")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+const wordpressWithoutCacheMeta = `
+name: wordpress
+summary: "Blog engine"
+description: "A pretty popular blog engine"
+provides:
+  url:
+    interface: http
+requires:
+  db:
+    interface: mysql
+`
+
+func (s *EndpointBindingsSuite) TestSetCharmRemovesBoundEndpoint(c *gc.C) {
+	err := s.service.SetEndpointBindings(map[string]string{"cache": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddMetaCharm(c, "wordpress", wordpressWithoutCacheMeta, 2)
+	err = s.service.SetCharm(ch, false)
+	c.Assert(err, gc.ErrorMatches, `cannot upgrade service "wordpress" to charm "wordpress": endpoint "cache" bound to space "internal" removed`)
+}
+
+func (s *EndpointBindingsSuite) TestSetCharmKeepsBoundEndpoint(c *gc.C) {
+	bindings := map[string]string{"db": "internal"}
+	err := s.service.SetEndpointBindings(bindings)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddMetaCharm(c, "wordpress", wordpressWithoutCacheMeta, 2)
+	err = s.service.SetCharm(ch, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.EndpointBindings(), jc.DeepEquals, bindings)
+}
@@ -134,6 +134,10 @@ type FilesystemParams struct {
 	// the filesystem's lifecycle will be bound.
 	binding names.Tag
 
+	// info, if non-nil, is the information of an imported
+	// filesystem, which is recorded as already provisioned.
+	info *FilesystemInfo
+
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
 }
@@ -652,6 +656,7 @@ func removeFilesystemOps(st *State, filesystem Filesystem) ([]txn.Op, error) {
 			Remove: true,
 		},
 		removeStatusOp(st, filesystem.globalKey()),
+		removeResizeOp(filesystemResizesC, filesystem.Tag().Id()),
 	}
 	// If the filesystem is backed by a volume, the volume should
 	// be destroyed once the filesystem is removed if it is bound
@@ -736,13 +741,13 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 	if err != nil {
 		return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.Trace(err)
 	}
-	if !provider.Supports(storage.StorageKindFilesystem) {
+	if params.info == nil && !provider.Supports(storage.StorageKindFilesystem) {
 		var volumeOps []txn.Op
 		volumeParams := VolumeParams{
-			params.storage,
-			filesystemTag, // volume is bound to filesystem
-			params.Pool,
-			params.Size,
+			storage: params.storage,
+			binding: filesystemTag, // volume is bound to filesystem
+			Pool:    params.Pool,
+			Size:    params.Size,
 		}
 		volumeOps, volumeTag, err = st.addVolumeOps(volumeParams, machineId)
 		if err != nil {
@@ -752,6 +757,20 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 		ops = append(ops, volumeOps...)
 	}
 
+	doc := &filesystemDoc{
+		FilesystemId: filesystemId,
+		VolumeId:     volumeId,
+		StorageId:    params.storage.Id(),
+		Binding:      params.binding.String(),
+		// Every filesystem is created with one attachment.
+		AttachmentCount: 1,
+	}
+	if params.info != nil {
+		// The filesystem was imported, and so is already provisioned.
+		doc.Info = params.info
+	} else {
+		doc.Params = &params
+	}
 	filesystemOps := []txn.Op{
 		createStatusOp(st, filesystemGlobalKey(filesystemId), statusDoc{
 			Status:  StatusPending,
@@ -761,15 +780,7 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 			C:      filesystemsC,
 			Id:     filesystemId,
 			Assert: txn.DocMissing,
-			Insert: &filesystemDoc{
-				FilesystemId: filesystemId,
-				VolumeId:     volumeId,
-				StorageId:    params.storage.Id(),
-				Binding:      params.binding.String(),
-				Params:       &params,
-				// Every filesystem is created with one attachment.
-				AttachmentCount: 1,
-			},
+			Insert: doc,
 		},
 	}
 	ops = append(ops, filesystemOps...)
@@ -804,7 +815,8 @@ func (st *State) validateFilesystemParams(params FilesystemParams, machineId str
 	if err != nil {
 		return "", errors.Trace(err)
 	}
-	if params.Size == 0 {
+	if params.Size == 0 && params.info == nil {
+		// The size of imported filesystems may be unknown.
 		return "", errors.New("invalid size 0")
 	}
 	return machineId, nil
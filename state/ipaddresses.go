@@ -18,8 +18,9 @@ import (
 	"github.com/juju/juju/network"
 )
 
-// addIPAddress implements the State method to add an IP address.
-func addIPAddress(st *State, addr network.Address, subnetid string) (ipaddress *IPAddress, err error) {
+// addIPAddress implements the State method to add an IP address,
+// which is created in the given state.
+func addIPAddress(st *State, addr network.Address, subnetid string, addrState AddressState) (ipaddress *IPAddress, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add IP address %q", addr)
 
 	// This checks for a missing value as well as invalid values
@@ -40,7 +41,7 @@ func addIPAddress(st *State, addr network.Address, subnetid string) (ipaddress *
 		EnvUUID:  st.EnvironUUID(),
 		UUID:     uuid.String(),
 		Life:     Alive,
-		State:    AddressStateUnknown,
+		State:    addrState,
 		SubnetId: subnetid,
 		Value:    addr.Value,
 		Type:     string(addr.Type),
@@ -146,6 +147,11 @@ const (
 	// the provider failed. We shouldn't use this address, nor should
 	// we attempt to allocate it again in the future.
 	AddressStateUnavailable AddressState = "unavailable"
+
+	// AddressStateReserved means that the IP address has been
+	// reserved by the user. It will never be picked for allocation,
+	// until it is released.
+	AddressStateReserved AddressState = "reserved"
 )
 
 // String implements fmt.Stringer.
@@ -387,6 +387,23 @@ func (s *IPAddressSuite) TestDeadIPAddresses(c *gc.C) {
 	c.Assert(ipAddresses, jc.SameContents, []*state.IPAddress{addr1, addr3})
 }
 
+func (s *IPAddressSuite) TestAllIPAddresses(c *gc.C) {
+	for _, value := range []string{"0.1.2.3", "0.1.2.4"} {
+		_, err := s.State.AddIPAddress(network.NewAddress(value), "foobar")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	addr1, err := s.State.IPAddress("0.1.2.3")
+	c.Assert(err, jc.ErrorIsNil)
+	err = addr1.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	addr2, err := s.State.IPAddress("0.1.2.4")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ipAddresses, err := s.State.AllIPAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddresses, jc.SameContents, []*state.IPAddress{addr1, addr2})
+}
+
 func (s *IPAddressSuite) TestRefresh(c *gc.C) {
 	rawAddr := network.NewAddress("0.1.2.3")
 	addr, err := s.State.AddIPAddress(rawAddr, "foobar")
@@ -46,6 +46,12 @@ func (st *State) LeadershipChecker() leadership.Checker {
 	return st.leadershipManager
 }
 
+// LeadershipReader returns a leadership.Reader for services in the state's
+// environment.
+func (st *State) LeadershipReader() leadership.Reader {
+	return st.leadershipManager
+}
+
 // HackLeadership stops the state's internal leadership manager to prevent it
 // from interfering with apiserver shutdown.
 func (st *State) HackLeadership() {
@@ -16,6 +16,7 @@ import (
 type ManagerWorker interface {
 	leadership.Checker
 	leadership.Claimer
+	leadership.Reader
 	Kill()
 	Wait() error
 }
@@ -31,6 +31,7 @@ func NewManager(config ManagerConfig) (ManagerWorker, error) {
 		claims: make(chan claim),
 		checks: make(chan check),
 		blocks: make(chan block),
+		reads:  make(chan read),
 	}
 	go func() {
 		defer manager.tomb.Done()
@@ -56,6 +57,9 @@ type manager struct {
 
 	// blocks is used to deliver leaderlessness block requests to the loop.
 	blocks chan block
+
+	// reads is used to deliver leadership snapshot requests to the loop.
+	reads chan read
 }
 
 // Kill is part of the worker.Worker interface.
@@ -110,6 +114,8 @@ func (manager *manager) choose(blocks blocks) error {
 	case block := <-manager.blocks:
 		blocks.add(block)
 		return nil
+	case read := <-manager.reads:
+		return manager.handleRead(read)
 	}
 }
 
@@ -198,6 +204,33 @@ func (manager *manager) BlockUntilLeadershipReleased(serviceName string) error {
 	}.invoke(manager.blocks)
 }
 
+// Leaders is part of the leadership.Reader interface.
+func (manager *manager) Leaders() (map[string]leadership.Leader, error) {
+	return read{
+		response: make(chan map[string]leadership.Leader),
+		abort:    manager.tomb.Dying(),
+	}.invoke(manager.reads)
+}
+
+// handleRead refreshes the client's view of lease state and responds to the
+// supplied read with the current holder and expiry of every known lease. It
+// will only return unrecoverable errors.
+func (manager *manager) handleRead(read read) error {
+	client := manager.config.Client
+	if err := client.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	leaders := make(map[string]leadership.Leader)
+	for serviceName, info := range client.Leases() {
+		leaders[serviceName] = leadership.Leader{
+			UnitName: info.Holder,
+			Expiry:   info.Expiry,
+		}
+	}
+	read.respond(leaders)
+	return nil
+}
+
 // nextExpiry returns a channel that will send a value at some point when we
 // expect at least one lease to be ready to expire. If no leases are known,
 // it will return nil.
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coreleadership "github.com/juju/juju/leadership"
+	"github.com/juju/juju/state/leadership"
+	"github.com/juju/juju/state/lease"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LeadersSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&LeadersSuite{})
+
+func (s *LeadersSuite) TestNoLeaders(c *gc.C) {
+	fix := &Fixture{
+		expectCalls: []call{{
+			method: "Refresh",
+		}},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		leaders, err := manager.Leaders()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(leaders, gc.HasLen, 0)
+	})
+}
+
+func (s *LeadersSuite) TestRefreshesLeaders(c *gc.C) {
+	fix := &Fixture{
+		leases: map[string]lease.Info{
+			"redis": lease.Info{
+				Holder: "redis/0",
+				Expiry: offset(time.Second),
+			},
+		},
+		expectCalls: []call{{
+			method: "Refresh",
+			callback: func(leases map[string]lease.Info) {
+				leases["redis"] = lease.Info{
+					Holder: "redis/1",
+					Expiry: offset(time.Minute),
+				}
+				leases["store"] = lease.Info{
+					Holder: "store/3",
+					Expiry: offset(time.Hour),
+				}
+			},
+		}},
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		leaders, err := manager.Leaders()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(leaders, jc.DeepEquals, map[string]coreleadership.Leader{
+			"redis": {UnitName: "redis/1", Expiry: offset(time.Minute)},
+			"store": {UnitName: "store/3", Expiry: offset(time.Hour)},
+		})
+	})
+}
+
+func (s *LeadersSuite) TestRefreshError(c *gc.C) {
+	fix := &Fixture{
+		expectCalls: []call{{
+			method: "Refresh",
+			err:    errors.New("crunch squish"),
+		}},
+		expectDirty: true,
+	}
+	fix.RunTest(c, func(manager leadership.ManagerWorker, _ *coretesting.Clock) {
+		leaders, err := manager.Leaders()
+		c.Check(err, gc.ErrorMatches, "leadership manager stopped")
+		c.Check(leaders, gc.IsNil)
+		err = manager.Wait()
+		c.Check(err, gc.ErrorMatches, "crunch squish")
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadership
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/leadership"
+)
+
+// read is used to deliver leadership-snapshot requests to a manager's loop
+// goroutine on behalf of Leaders.
+type read struct {
+	response chan map[string]leadership.Leader
+	abort    <-chan struct{}
+}
+
+// validate returns an error if any fields are invalid or missing.
+func (r read) validate() error {
+	if r.response == nil {
+		return errors.New("missing response channel")
+	}
+	if r.abort == nil {
+		return errors.New("missing abort channel")
+	}
+	return nil
+}
+
+// invoke sends the read on the supplied channel and waits for the current
+// leaders to be sent back.
+func (r read) invoke(ch chan<- read) (map[string]leadership.Leader, error) {
+	if err := r.validate(); err != nil {
+		return nil, errors.Annotatef(err, "cannot read leadership")
+	}
+	for {
+		select {
+		case <-r.abort:
+			return nil, errStopped
+		case ch <- r:
+			ch = nil
+		case leaders := <-r.response:
+			return leaders, nil
+		}
+	}
+}
+
+// respond sends the supplied leaders back to the originating invoke.
+func (r read) respond(leaders map[string]leadership.Leader) {
+	select {
+	case <-r.abort:
+	case r.response <- leaders:
+	}
+}
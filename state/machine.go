@@ -109,6 +109,15 @@ type machineDoc struct {
 	HasVote       bool
 	PasswordHash  string
 	Clean         bool
+	// ReplicaSetPriority holds the priority of a state server's
+	// mongo replica set member; zero means the default priority.
+	ReplicaSetPriority float64 `bson:"replicasetpriority,omitempty"`
+	// ReplicaSetHidden records whether a non-voting state server's
+	// mongo replica set member is hidden, for use by backups.
+	ReplicaSetHidden bool `bson:"replicasethidden,omitempty"`
+	// Cordoned records whether the machine has been taken out of
+	// service, so that no new units or containers are placed on it.
+	Cordoned bool `bson:"cordoned,omitempty"`
 	// TODO(axw) 2015-06-22 #1467379
 	// We need an upgrade step to populate "volumes" and "filesystems"
 	// for entities created in 1.24.
@@ -130,6 +139,9 @@ type machineDoc struct {
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
+	// HostId is the id of the machine hosting this container, if the
+	// container has been migrated away from the parent encoded in its id.
+	HostId string `bson:"hostid,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -274,6 +286,19 @@ func (m *Machine) SetHasVote(hasVote bool) error {
 	return nil
 }
 
+// ReplicaSetPriority returns the priority of the state server's mongo
+// replica set member, or zero if the default priority should be used.
+func (m *Machine) ReplicaSetPriority() float64 {
+	return m.doc.ReplicaSetPriority
+}
+
+// ReplicaSetHidden reports whether the state server's mongo replica set
+// member is hidden. Hidden members never vote and are never seen by
+// clients, so they can be used for backups.
+func (m *Machine) ReplicaSetHidden() bool {
+	return m.doc.ReplicaSetHidden
+}
+
 // IsManager returns true if the machine has JobManageEnviron.
 func (m *Machine) IsManager() bool {
 	return hasJob(m.doc.Jobs, JobManageEnviron)
@@ -478,7 +503,12 @@ func (m *Machine) Containers() ([]string, error) {
 }
 
 // ParentId returns the Id of the host machine if this machine is a container.
+// This is the machine the container currently runs on, which differs from
+// the parent encoded in its id once the container has been migrated.
 func (m *Machine) ParentId() (string, bool) {
+	if m.doc.HostId != "" {
+		return m.doc.HostId, true
+	}
 	parentId := ParentId(m.Id())
 	return parentId, parentId != ""
 }
@@ -827,6 +857,7 @@ func (m *Machine) Remove() (err error) {
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
 		removeMachineBlockDevicesOp(m.Id()),
+		removeContainerMigrationOp(m.st, m.Id()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
 	if err != nil {
@@ -846,7 +877,8 @@ func (m *Machine) Remove() (err error) {
 	}
 	ops = append(ops, ifacesOps...)
 	ops = append(ops, portsOps...)
-	ops = append(ops, removeContainerRefOps(m.st, m.Id())...)
+	parentId, _ := m.ParentId()
+	ops = append(ops, removeContainerRefOps(m.st, m.Id(), parentId)...)
 	ops = append(ops, filesystemOps...)
 	ops = append(ops, volumeOps...)
 	ipAddresses, err := m.st.AllocatedIPAddresses(m.Id())
@@ -1500,6 +1532,36 @@ func (m *Machine) Clean() bool {
 	return m.doc.Clean
 }
 
+// notCordonedDoc asserts that a machine has not been cordoned.
+var notCordonedDoc = bson.D{{"cordoned", bson.D{{"$ne", true}}}}
+
+// Cordoned returns true if the machine has been cordoned, in which
+// case no new units or containers will be placed on it.
+func (m *Machine) Cordoned() bool {
+	return m.doc.Cordoned
+}
+
+// SetCordoned cordons or uncordons the machine. A cordoned machine
+// keeps the units and containers it already has, but no more will
+// be placed on it until it is uncordoned.
+func (m *Machine) SetCordoned(cordoned bool) error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"cordoned", cordoned}}}},
+	}}
+	if err := m.st.runTransaction(ops); err != nil {
+		action := "cordon"
+		if !cordoned {
+			action = "uncordon"
+		}
+		return fmt.Errorf("cannot %s machine %v: %v", action, m, onAbort(err, ErrDead))
+	}
+	m.doc.Cordoned = cordoned
+	return nil
+}
+
 // SupportedContainers returns any containers this machine is capable of hosting, and a bool
 // indicating if the supported containers have been determined or not.
 func (m *Machine) SupportedContainers() ([]instance.ContainerType, bool) {
@@ -0,0 +1,393 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// meterThresholdInfoPrefix prefixes the meter status info of units
+// whose meter status was set because a meter threshold was breached.
+// Only such statuses are reset to GREEN when the thresholds are no
+// longer breached, so that statuses set by the metrics collection
+// service are left alone.
+const meterThresholdInfoPrefix = "meter threshold breached: "
+
+// meterThresholdStaleness is the age beyond which a unit's most recent
+// value of a metric is considered stale. Units collect metrics every
+// five minutes, so a value this old means several collections have
+// been missed; a stale value never breaches a threshold.
+const meterThresholdStaleness = 15 * time.Minute
+
+// MeterThreshold defines a condition on the value of a charm metric
+// which, when it has held for at least the specified duration, causes
+// the meter status of the unit reporting the metric to be set to the
+// threshold's status.
+type MeterThreshold struct {
+	// Key is the key of the metric to which the threshold applies.
+	Key string
+
+	// Operator is the comparison operator applied to the metric's
+	// value and the threshold's value: one of >, >=, <, <=, == or !=.
+	Operator string
+
+	// Value is the threshold value.
+	Value float64
+
+	// Duration is the length of time for which the condition must
+	// hold before the threshold is breached.
+	Duration time.Duration
+
+	// Status is the meter status to set when the threshold is
+	// breached: either AMBER or RED.
+	Status MeterStatusCode
+}
+
+var meterThresholdOperators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// ParseMeterThreshold parses a meter threshold of the form
+//
+//	<metric> <operator> <value> [for <duration>] -> <status>
+//
+// e.g. "queue-depth > 1000 for 5m -> AMBER".
+func ParseMeterThreshold(s string) (MeterThreshold, error) {
+	fail := func(format string, args ...interface{}) (MeterThreshold, error) {
+		return MeterThreshold{}, errors.Errorf("invalid meter threshold %q: %s", s, fmt.Sprintf(format, args...))
+	}
+	parts := strings.Split(s, "->")
+	if len(parts) != 2 {
+		return fail(`expected "<metric> <operator> <value> [for <duration>] -> <status>"`)
+	}
+	fields := strings.Fields(parts[0])
+	if len(fields) != 3 && !(len(fields) == 5 && fields[3] == "for") {
+		return fail(`expected "<metric> <operator> <value> [for <duration>] -> <status>"`)
+	}
+	t := MeterThreshold{Key: fields[0], Operator: fields[1]}
+	if _, ok := meterThresholdOperators[t.Operator]; !ok {
+		return fail("unknown operator %q", t.Operator)
+	}
+	value, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return fail("expected numeric value, got %q", fields[2])
+	}
+	t.Value = value
+	if len(fields) == 5 {
+		d, err := time.ParseDuration(fields[4])
+		if err != nil || d < 0 {
+			return fail("expected non-negative duration, got %q", fields[4])
+		}
+		t.Duration = d
+	}
+	status := strings.TrimSpace(parts[1])
+	t.Status = MeterStatusFromString(status)
+	if t.Status != MeterAmber && t.Status != MeterRed {
+		return fail("expected status AMBER or RED, got %q", status)
+	}
+	return t, nil
+}
+
+// String returns the threshold in the form accepted by
+// ParseMeterThreshold.
+func (t MeterThreshold) String() string {
+	s := fmt.Sprintf("%s %s %s", t.Key, t.Operator, strconv.FormatFloat(t.Value, 'f', -1, 64))
+	if t.Duration > 0 {
+		s += " for " + t.Duration.String()
+	}
+	return s + " -> " + t.Status.String()
+}
+
+// Validate returns an error if the threshold is not valid.
+func (t MeterThreshold) Validate() error {
+	if t.Key == "" {
+		return errors.NotValidf("meter threshold with empty metric key")
+	}
+	if _, ok := meterThresholdOperators[t.Operator]; !ok {
+		return errors.NotValidf("meter threshold operator %q", t.Operator)
+	}
+	if t.Duration < 0 {
+		return errors.NotValidf("negative meter threshold duration")
+	}
+	if t.Status != MeterAmber && t.Status != MeterRed {
+		return errors.NotValidf("meter threshold status %q", t.Status)
+	}
+	return nil
+}
+
+// window returns the length of time preceding now for which metrics
+// are needed to evaluate the threshold: its duration, plus the
+// staleness limit so that the value recorded before the start of the
+// duration is included.
+func (t MeterThreshold) window() time.Duration {
+	return t.Duration + meterThresholdStaleness
+}
+
+// breached reports whether the threshold is breached, given the
+// specified metrics sorted by time. The threshold is breached if the
+// most recently recorded value of its metric satisfies the condition
+// and is no older than the staleness limit, and every value recorded
+// in the threshold's duration preceding now has also satisfied it.
+// Metrics recorded before the threshold's window are ignored.
+func (t MeterThreshold) breached(metrics []Metric, now time.Time) bool {
+	cmp := meterThresholdOperators[t.Operator]
+	start := now.Add(-t.window())
+	var since time.Time
+	for i := len(metrics) - 1; i >= 0; i-- {
+		m := metrics[i]
+		if m.Time.Before(start) {
+			break
+		}
+		if m.Key != t.Key {
+			continue
+		}
+		value, err := strconv.ParseFloat(m.Value, 64)
+		if err != nil {
+			continue
+		}
+		if since.IsZero() && m.Time.Before(now.Add(-meterThresholdStaleness)) {
+			return false
+		}
+		if !cmp(value, t.Value) {
+			break
+		}
+		since = m.Time
+	}
+	return !since.IsZero() && !now.Before(since.Add(t.Duration))
+}
+
+type meterThresholdDoc struct {
+	Key      string        `bson:"key"`
+	Operator string        `bson:"operator"`
+	Value    float64       `bson:"value"`
+	Duration time.Duration `bson:"duration"`
+	Status   string        `bson:"status"`
+}
+
+// meterThresholdsDoc records the meter thresholds of a service.
+type meterThresholdsDoc struct {
+	DocID       string              `bson:"_id"`
+	EnvUUID     string              `bson:"env-uuid"`
+	ServiceName string              `bson:"servicename"`
+	Thresholds  []meterThresholdDoc `bson:"thresholds"`
+}
+
+func (doc *meterThresholdsDoc) thresholds() []MeterThreshold {
+	thresholds := make([]MeterThreshold, len(doc.Thresholds))
+	for i, t := range doc.Thresholds {
+		thresholds[i] = MeterThreshold{
+			Key:      t.Key,
+			Operator: t.Operator,
+			Value:    t.Value,
+			Duration: t.Duration,
+			Status:   MeterStatusFromString(t.Status),
+		}
+	}
+	return thresholds
+}
+
+// MeterThresholds returns the meter thresholds of the service.
+func (s *Service) MeterThresholds() ([]MeterThreshold, error) {
+	coll, closer := s.st.getCollection(meterThresholdsC)
+	defer closer()
+	var doc meterThresholdsDoc
+	err := coll.FindId(s.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get meter thresholds for service %q", s)
+	}
+	return doc.thresholds(), nil
+}
+
+// SetMeterThresholds replaces the meter thresholds of the service.
+// Setting no thresholds removes them; the meter status of the
+// service's units is then left as it is.
+func (s *Service) SetMeterThresholds(thresholds []MeterThreshold) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set meter thresholds for service %q", s)
+	docs := make([]meterThresholdDoc, len(thresholds))
+	for i, t := range thresholds {
+		if err := t.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		docs[i] = meterThresholdDoc{
+			Key:      t.Key,
+			Operator: t.Operator,
+			Value:    t.Value,
+			Duration: t.Duration,
+			Status:   t.Status.String(),
+		}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("service is no longer alive")
+		}
+		coll, closer := s.st.getCollection(meterThresholdsC)
+		defer closer()
+		count, err := coll.FindId(s.globalKey()).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		switch {
+		case count == 0 && len(docs) == 0:
+			return nil, jujutxn.ErrNoOperations
+		case count == 0:
+			ops = append(ops, txn.Op{
+				C:      meterThresholdsC,
+				Id:     s.st.docID(s.globalKey()),
+				Assert: txn.DocMissing,
+				Insert: &meterThresholdsDoc{
+					EnvUUID:     s.st.EnvironUUID(),
+					ServiceName: s.doc.Name,
+					Thresholds:  docs,
+				},
+			})
+		case len(docs) == 0:
+			ops = append(ops, txn.Op{
+				C:      meterThresholdsC,
+				Id:     s.st.docID(s.globalKey()),
+				Assert: txn.DocExists,
+				Remove: true,
+			})
+		default:
+			ops = append(ops, txn.Op{
+				C:      meterThresholdsC,
+				Id:     s.st.docID(s.globalKey()),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"thresholds", docs}}}},
+			})
+		}
+		return ops, nil
+	}
+	return s.st.run(buildTxn)
+}
+
+// removeMeterThresholdsOp returns the operation needed to remove the
+// meter thresholds of the service with the given global key.
+func removeMeterThresholdsOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      meterThresholdsC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
+
+// EvaluateMeterThresholds evaluates the meter thresholds of each
+// service in the environment against the metrics recorded by the
+// service's units, and sets the meter status of each unit to that of
+// the most severe threshold breached. The meter status of a unit that
+// no longer breaches any threshold is set to GREEN.
+//
+// Changing a unit's meter status causes its meter-status-changed hook
+// to be run.
+func (st *State) EvaluateMeterThresholds(now time.Time) error {
+	coll, closer := st.getCollection(meterThresholdsC)
+	defer closer()
+	var docs []meterThresholdsDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return errors.Annotate(err, "cannot get meter thresholds")
+	}
+	var firstErr error
+	for _, doc := range docs {
+		service, err := st.Service(doc.ServiceName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		units, err := service.AllUnits()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		thresholds := doc.thresholds()
+		for _, unit := range units {
+			if unit.Life() != Alive {
+				continue
+			}
+			if err := unit.evaluateMeterThresholds(thresholds, now); err != nil {
+				meterStatusLogger.Warningf("%v", err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	return errors.Trace(firstErr)
+}
+
+func (u *Unit) evaluateMeterThresholds(thresholds []MeterThreshold, now time.Time) error {
+	// Only the batches created within the longest threshold window
+	// are needed; older metrics cannot affect the result.
+	var window time.Duration
+	for _, t := range thresholds {
+		if t.window() > window {
+			window = t.window()
+		}
+	}
+	batches, err := u.st.metricBatches(bson.M{
+		"unit":    u.Name(),
+		"created": bson.M{"$gte": now.Add(-window)},
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot evaluate meter thresholds for unit %s", u.Name())
+	}
+	var metrics []Metric
+	for _, batch := range batches {
+		metrics = append(metrics, batch.Metrics()...)
+	}
+	sort.Stable(metricsByTime(metrics))
+
+	var breached *MeterThreshold
+	for i, t := range thresholds {
+		if !t.breached(metrics, now) {
+			continue
+		}
+		if breached == nil || t.Status.Severity() < breached.Status.Severity() {
+			breached = &thresholds[i]
+		}
+	}
+	if breached != nil {
+		return u.SetMeterStatus(breached.Status.String(), meterThresholdInfoPrefix+breached.String())
+	}
+	// Only reset the meter status if it was set by a threshold.
+	doc, err := u.getMeterStatusDoc()
+	if err != nil {
+		return errors.Annotatef(err, "cannot get meter status for unit %s", u.Name())
+	}
+	if !strings.HasPrefix(doc.Info, meterThresholdInfoPrefix) {
+		return nil
+	}
+	return u.SetMeterStatus(MeterGreen.String(), "")
+}
+
+type metricsByTime []Metric
+
+func (m metricsByTime) Len() int           { return len(m) }
+func (m metricsByTime) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m metricsByTime) Less(i, j int) bool { return m[i].Time.Before(m[j].Time) }
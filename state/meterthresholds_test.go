@@ -0,0 +1,280 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type MeterThresholdsSuite struct {
+	ConnSuite
+	factory *factory.Factory
+	charm   *state.Charm
+	service *state.Service
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&MeterThresholdsSuite{})
+
+func (s *MeterThresholdsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.factory = factory.NewFactory(s.State)
+	s.charm = s.factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "cs:quantal/metered"})
+	s.service = s.factory.MakeService(c, &factory.ServiceParams{Charm: s.charm})
+	s.unit = s.factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+}
+
+func (s *MeterThresholdsSuite) TestParseMeterThreshold(c *gc.C) {
+	for i, test := range []struct {
+		input    string
+		expected state.MeterThreshold
+		str      string
+		err      string
+	}{{
+		input: "pings > 1000 for 5m -> AMBER",
+		expected: state.MeterThreshold{
+			Key: "pings", Operator: ">", Value: 1000, Duration: 5 * time.Minute, Status: state.MeterAmber,
+		},
+		str: "pings > 1000 for 5m0s -> AMBER",
+	}, {
+		input: " pings   <=  0.5->RED ",
+		expected: state.MeterThreshold{
+			Key: "pings", Operator: "<=", Value: 0.5, Status: state.MeterRed,
+		},
+		str: "pings <= 0.5 -> RED",
+	}, {
+		input: "pings > 1000",
+		err:   `invalid meter threshold "pings > 1000": expected "<metric> <operator> <value> \[for <duration>\] -> <status>"`,
+	}, {
+		input: "pings > 1000 during 5m -> AMBER",
+		err:   `invalid meter threshold .*: expected "<metric> <operator> <value> \[for <duration>\] -> <status>"`,
+	}, {
+		input: "pings ~ 1000 -> AMBER",
+		err:   `invalid meter threshold .*: unknown operator "~"`,
+	}, {
+		input: "pings > lots -> AMBER",
+		err:   `invalid meter threshold .*: expected numeric value, got "lots"`,
+	}, {
+		input: "pings > 1000 for ever -> AMBER",
+		err:   `invalid meter threshold .*: expected non-negative duration, got "ever"`,
+	}, {
+		input: "pings > 1000 -> GREEN",
+		err:   `invalid meter threshold .*: expected status AMBER or RED, got "GREEN"`,
+	}} {
+		c.Logf("test %d: %q", i, test.input)
+		t, err := state.ParseMeterThreshold(test.input)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(t, jc.DeepEquals, test.expected)
+		c.Check(t.String(), gc.Equals, test.str)
+	}
+}
+
+func (s *MeterThresholdsSuite) TestSetMeterThresholds(c *gc.C) {
+	thresholds, err := s.service.MeterThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(thresholds, gc.HasLen, 0)
+
+	expected := []state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 for 5m -> AMBER"),
+		mustParseMeterThreshold(c, "pings > 5000 -> RED"),
+	}
+	err = s.service.SetMeterThresholds(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	thresholds, err = s.service.MeterThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(thresholds, jc.DeepEquals, expected)
+
+	err = s.service.SetMeterThresholds(expected[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	thresholds, err = s.service.MeterThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(thresholds, jc.DeepEquals, expected[1:])
+
+	err = s.service.SetMeterThresholds(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	thresholds, err = s.service.MeterThresholds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(thresholds, gc.HasLen, 0)
+}
+
+func (s *MeterThresholdsSuite) TestSetMeterThresholdsInvalid(c *gc.C) {
+	err := s.service.SetMeterThresholds([]state.MeterThreshold{{
+		Key: "pings", Operator: ">", Value: 1, Status: state.MeterGreen,
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot set meter thresholds for service "metered": meter threshold status "GREEN" not valid`)
+}
+
+func (s *MeterThresholdsSuite) TestSetMeterThresholdsServiceNotAlive(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 -> AMBER"),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot set meter thresholds for service "metered": service is no longer alive`)
+}
+
+func (s *MeterThresholdsSuite) TestMeterThresholdsRemovedWithService(c *gc.C) {
+	service := s.factory.MakeService(c, &factory.ServiceParams{Name: "othermetered", Charm: s.charm})
+	err := service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 -> AMBER"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll := s.MgoSuite.Session.DB("juju").C("meterthresholds")
+	var docs []bson.M
+	err = coll.Find(nil).All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 0)
+}
+
+func (s *MeterThresholdsSuite) addPings(c *gc.C, t time.Time, value string) {
+	s.factory.MakeMetric(c, &factory.MetricParams{
+		Unit:    s.unit,
+		Time:    &t,
+		Metrics: []state.Metric{{"pings", value, t}},
+	})
+}
+
+func (s *MeterThresholdsSuite) assertMeterStatus(c *gc.C, code state.MeterStatusCode, info string) {
+	status, err := s.unit.GetMeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.MeterStatus{code, info})
+}
+
+func (s *MeterThresholdsSuite) TestEvaluateMeterThresholds(c *gc.C) {
+	err := s.service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 for 5m -> AMBER"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now().Round(time.Second).UTC()
+	s.addPings(c, now.Add(-10*time.Minute), "10")
+	s.addPings(c, now.Add(-4*time.Minute), "2000")
+
+	// The threshold has not been exceeded for long enough.
+	err = s.State.EvaluateMeterThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterNotSet, "")
+
+	err = s.State.EvaluateMeterThresholds(now.Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterAmber, "meter threshold breached: pings > 1000 for 5m0s -> AMBER")
+
+	// Once the metric drops below the threshold, the status is reset.
+	s.addPings(c, now.Add(2*time.Minute), "10")
+	err = s.State.EvaluateMeterThresholds(now.Add(2 * time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterGreen, "")
+}
+
+func (s *MeterThresholdsSuite) TestEvaluateMeterThresholdsIgnoresStaleMetrics(c *gc.C) {
+	err := s.service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 -> AMBER"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now().Round(time.Second).UTC()
+	s.addPings(c, now, "2000")
+	err = s.State.EvaluateMeterThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterAmber, "meter threshold breached: pings > 1000 -> AMBER")
+
+	// Once the unit stops reporting the metric, its last value
+	// eventually becomes stale and the status is reset.
+	err = s.State.EvaluateMeterThresholds(now.Add(10 * time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterAmber, "meter threshold breached: pings > 1000 -> AMBER")
+	err = s.State.EvaluateMeterThresholds(now.Add(20 * time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterGreen, "")
+}
+
+func (s *MeterThresholdsSuite) TestEvaluateMeterThresholdsIgnoresMetricsBeforeWindow(c *gc.C) {
+	err := s.service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 for 30m -> AMBER"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now().Round(time.Second).UTC()
+	// The breaching values recorded before the gap in reporting do
+	// not count towards the threshold's duration.
+	s.addPings(c, now.Add(-2*time.Hour), "2000")
+	s.addPings(c, now.Add(-10*time.Minute), "2000")
+	s.addPings(c, now, "2000")
+	err = s.State.EvaluateMeterThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterNotSet, "")
+}
+
+func (s *MeterThresholdsSuite) TestEvaluateMeterThresholdsMostSevere(c *gc.C) {
+	err := s.service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 -> AMBER"),
+		mustParseMeterThreshold(c, "pings > 5000 -> RED"),
+		mustParseMeterThreshold(c, "pings > 1000 for 1h -> RED"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now().Round(time.Second).UTC()
+	s.addPings(c, now, "2000")
+	err = s.State.EvaluateMeterThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterAmber, "meter threshold breached: pings > 1000 -> AMBER")
+
+	s.addPings(c, now.Add(time.Minute), "6000")
+	err = s.State.EvaluateMeterThresholds(now.Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterRed, "meter threshold breached: pings > 5000 -> RED")
+}
+
+func (s *MeterThresholdsSuite) TestEvaluateMeterThresholdsLeavesOtherStatus(c *gc.C) {
+	err := s.service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 -> AMBER"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetMeterStatus("AMBER", "set by the collector")
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now().Round(time.Second).UTC()
+	s.addPings(c, now, "10")
+	err = s.State.EvaluateMeterThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMeterStatus(c, state.MeterAmber, "set by the collector")
+}
+
+func (s *MeterThresholdsSuite) TestEvaluateMeterThresholdsTriggersWatcher(c *gc.C) {
+	err := s.service.SetMeterThresholds([]state.MeterThreshold{
+		mustParseMeterThreshold(c, "pings > 1000 -> RED"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	w := s.unit.WatchMeterStatus()
+	defer w.Stop()
+	assertMeterStatusChanged(c, w)
+
+	now := time.Now().Round(time.Second).UTC()
+	s.addPings(c, now, "2000")
+	err = s.State.EvaluateMeterThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.StartSync()
+	assertMeterStatusChanged(c, w)
+
+	// Evaluating again without a change does not trigger the watcher.
+	err = s.State.EvaluateMeterThresholds(now)
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.StartSync()
+	assertMeterStatusNotChanged(c, w)
+}
+
+func mustParseMeterThreshold(c *gc.C, s string) state.MeterThreshold {
+	t, err := state.ParseMeterThreshold(s)
+	c.Assert(err, jc.ErrorIsNil)
+	return t
+}
@@ -5,6 +5,7 @@ package state
 
 import (
 	"encoding/json"
+	"regexp"
 	"time"
 
 	"github.com/juju/errors"
@@ -156,6 +157,41 @@ func (st *State) MetricBatches() ([]MetricBatch, error) {
 	return results, nil
 }
 
+// MetricBatchesForUnit returns the metric batches reported by the
+// specified unit, ordered by creation time.
+func (st *State) MetricBatchesForUnit(unit string) ([]MetricBatch, error) {
+	if _, err := st.Unit(unit); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.metricBatches(bson.M{"unit": unit})
+}
+
+// MetricBatchesForService returns the metric batches reported by the
+// units of the specified service, ordered by creation time. Batches
+// reported by units that have since been removed are included.
+func (st *State) MetricBatchesForService(service string) ([]MetricBatch, error) {
+	if _, err := st.Service(service); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.metricBatches(bson.M{
+		"unit": bson.M{"$regex": "^" + regexp.QuoteMeta(service) + "/"},
+	})
+}
+
+func (st *State) metricBatches(query bson.M) ([]MetricBatch, error) {
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+	var docs []metricBatchDoc
+	if err := c.Find(query).Sort("created").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]MetricBatch, len(docs))
+	for i, doc := range docs {
+		results[i] = MetricBatch{st: st, doc: doc}
+	}
+	return results, nil
+}
+
 // MetricBatch returns the metric batch with the given id.
 func (st *State) MetricBatch(id string) (*MetricBatch, error) {
 	c, closer := st.getCollection(metricsC)
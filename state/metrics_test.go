@@ -199,6 +199,34 @@ func (s *MetricSuite) TestMetricBatchesCustomCharmURLAndUUID(c *gc.C) {
 	c.Assert(metricBatches[0].Metrics(), gc.HasLen, 1)
 }
 
+func (s *MetricSuite) TestMetricBatchesForUnitAndService(c *gc.C) {
+	now := state.NowToTheSecond()
+	unit1 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	otherService := s.Factory.MakeService(c, &factory.ServiceParams{Name: "othermetered", Charm: s.meteredCharm})
+	otherUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: otherService, SetCharmURL: true})
+
+	batch0 := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &now})
+	later := now.Add(time.Minute)
+	batch1 := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: unit1, Time: &later})
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: otherUnit, Time: &now})
+
+	batches, err := s.State.MetricBatchesForUnit("metered/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batches, gc.HasLen, 1)
+	c.Assert(batches[0].UUID(), gc.Equals, batch1.UUID())
+
+	batches, err = s.State.MetricBatchesForService("metered")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batches, gc.HasLen, 2)
+	c.Assert(batches[0].UUID(), gc.Equals, batch0.UUID())
+	c.Assert(batches[1].UUID(), gc.Equals, batch1.UUID())
+
+	_, err = s.State.MetricBatchesForUnit("metered/9")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.MetricBatchesForService("unknown")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *MetricSuite) TestMetricCredentials(c *gc.C) {
 	now := state.NowToTheSecond()
 	m := state.Metric{"pings", "5", now}
@@ -116,13 +116,24 @@ func (m *Machine) GetRebootFlag() (bool, error) {
 	return true, nil
 }
 
-func (m *Machine) machinesToCareAboutRebootsFor() []string {
+// machinesToCareAboutRebootsFor returns the ids of the machine and of
+// the machines hosting it, following the hosts containers have been
+// migrated to.
+func (m *Machine) machinesToCareAboutRebootsFor() ([]string, error) {
 	var possibleIds []string
-	for currentId := m.Id(); currentId != ""; {
-		possibleIds = append(possibleIds, currentId)
-		currentId = ParentId(currentId)
+	for current := m; ; {
+		possibleIds = append(possibleIds, current.Id())
+		hostId, isContainer := current.ParentId()
+		if !isContainer {
+			break
+		}
+		host, err := m.st.Machine(hostId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		current = host
 	}
-	return possibleIds
+	return possibleIds, nil
 }
 
 // ShouldRebootOrShutdown check if the current node should reboot or shutdown
@@ -132,7 +143,10 @@ func (m *Machine) ShouldRebootOrShutdown() (RebootAction, error) {
 	rebootCol, closer := m.st.getCollection(rebootC)
 	defer closer()
 
-	machines := m.machinesToCareAboutRebootsFor()
+	machines, err := m.machinesToCareAboutRebootsFor()
+	if err != nil {
+		return ShouldDoNothing, errors.Trace(err)
+	}
 
 	docs := []rebootDoc{}
 	sel := bson.D{{"machineid", bson.D{{"$in", machines}}}}
@@ -0,0 +1,387 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// RollingUpgradeStatus describes the progress of a rolling charm
+// upgrade.
+type RollingUpgradeStatus string
+
+const (
+	// RollingUpgradeRunning is the status of a rolling upgrade whose
+	// batches are being released as the units in each batch become
+	// healthy.
+	RollingUpgradeRunning RollingUpgradeStatus = "running"
+
+	// RollingUpgradePaused is the status of a rolling upgrade that
+	// was stopped because a unit failed to upgrade. It stays paused
+	// until it is resumed or rolled back.
+	RollingUpgradePaused RollingUpgradeStatus = "paused"
+)
+
+// rollingUpgradeDoc records the progress of a rolling charm upgrade
+// in the service document.
+type rollingUpgradeDoc struct {
+	PreviousCharmURL   *charm.URL           `bson:"previouscharmurl"`
+	PreviousForceCharm bool                 `bson:"previousforcecharm"`
+	BatchSize          int                  `bson:"batchsize"`
+	Held               []string             `bson:"held"`
+	Batch              []string             `bson:"batch"`
+	Status             RollingUpgradeStatus `bson:"status"`
+	Message            string               `bson:"message,omitempty"`
+	Stalled            bool                 `bson:"stalled,omitempty"`
+}
+
+// RollingUpgrade describes a rolling charm upgrade of a service.
+type RollingUpgrade struct {
+	// PreviousCharmURL is the charm the service was using when the
+	// upgrade was started, which held units continue to run.
+	PreviousCharmURL *charm.URL
+
+	// BatchSize is the number of units upgraded at a time.
+	BatchSize int
+
+	// Held holds the names of the units not yet allowed to upgrade,
+	// in the order they will be released.
+	Held []string
+
+	// Batch holds the names of the units in the batch currently
+	// being upgraded.
+	Batch []string
+
+	// Status records whether the upgrade is running or paused.
+	Status RollingUpgradeStatus
+
+	// Message records why a paused upgrade was paused.
+	Message string
+}
+
+var noRollingUpgradeDoc = bson.D{{"rollingupgrade", bson.D{{"$exists", false}}}}
+var hasRollingUpgradeDoc = bson.D{{"rollingupgrade", bson.D{{"$exists", true}}}}
+
+// RollingUpgrade returns the rolling charm upgrade in progress for
+// the service, if any.
+func (s *Service) RollingUpgrade() (RollingUpgrade, bool) {
+	doc := s.doc.RollingUpgrade
+	if doc == nil {
+		return RollingUpgrade{}, false
+	}
+	return RollingUpgrade{
+		PreviousCharmURL: doc.PreviousCharmURL,
+		BatchSize:        doc.BatchSize,
+		Held:             append([]string(nil), doc.Held...),
+		Batch:            append([]string(nil), doc.Batch...),
+		Status:           doc.Status,
+		Message:          doc.Message,
+	}, true
+}
+
+// CharmURLForUnit returns the charm URL that the named unit of the
+// service should be running, and whether upgrades to it should be
+// forced. During a rolling upgrade, units that have not yet been
+// released continue to run the previous charm.
+func (s *Service) CharmURLForUnit(unitName string) (curl *charm.URL, force bool) {
+	if doc := s.doc.RollingUpgrade; doc != nil {
+		for _, held := range doc.Held {
+			if held == unitName {
+				return doc.PreviousCharmURL, doc.PreviousForceCharm
+			}
+		}
+	}
+	return s.doc.CharmURL, s.doc.ForceCharm
+}
+
+// SetCharmRolling changes the charm for the service, as SetCharm
+// does, but upgrades the service's units batchSize at a time. Units
+// are held at the current charm until released by
+// NextRollingUpgradeBatch; units added to the service meanwhile use
+// the new charm.
+func (s *Service) SetCharmRolling(ch *Charm, force bool, batchSize int) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot start rolling upgrade of service %q", s)
+	if batchSize < 1 {
+		return errors.Errorf("batch size must be at least 1, got %d", batchSize)
+	}
+	if ch.Meta().Subordinate != s.doc.Subordinate {
+		return errors.Errorf("cannot change a service's subordinacy")
+	}
+	if ch.URL().Series != s.doc.Series {
+		return errors.Errorf("cannot change a service's series")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life == Dead {
+			return nil, ErrDead
+		}
+		if s.doc.RollingUpgrade != nil {
+			return nil, errors.New("rolling upgrade already in progress")
+		}
+		if *s.doc.CharmURL == *ch.URL() {
+			return nil, errors.Errorf("service already uses charm %q", ch.URL())
+		}
+		units, err := s.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var held []string
+		for _, unit := range units {
+			held = append(held, unit.Name())
+		}
+		sort.Sort(unitNamesByNumber(held))
+		ops, err := s.changeCharmOps(ch, force)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc := &rollingUpgradeDoc{
+			PreviousCharmURL:   s.doc.CharmURL,
+			PreviousForceCharm: s.doc.ForceCharm,
+			BatchSize:          batchSize,
+			Held:               held,
+			Status:             RollingUpgradeRunning,
+		}
+		return append(ops, txn.Op{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: append(bson.D{{"unitcount", len(units)}}, noRollingUpgradeDoc...),
+			Update: bson.D{{"$set", bson.D{{"rollingupgrade", doc}}}},
+		}), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return err
+	}
+	return s.Refresh()
+}
+
+// NextRollingUpgradeBatch releases the next batch of held units to
+// upgrade, replacing the current batch. If no units are held, the
+// rolling upgrade is complete and its record is removed.
+func (s *Service) NextRollingUpgradeBatch() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		doc := s.doc.RollingUpgrade
+		if doc == nil {
+			return nil, errors.New("no rolling upgrade in progress")
+		}
+		if doc.Status != RollingUpgradeRunning {
+			return nil, errors.Errorf("rolling upgrade is %s", doc.Status)
+		}
+		return s.nextRollingUpgradeBatchOps(doc, nil), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot release next batch of service %q", s)
+	}
+	return s.Refresh()
+}
+
+// nextRollingUpgradeBatchOps returns the operations needed to release
+// the next batch of the given rolling upgrade, or to finish it if no
+// units are held, applying any extra updates given alongside.
+func (s *Service) nextRollingUpgradeBatchOps(doc *rollingUpgradeDoc, extra bson.D) []txn.Op {
+	if len(doc.Held) == 0 {
+		return s.finishRollingUpgradeOps(doc)
+	}
+	n := doc.BatchSize
+	if n > len(doc.Held) {
+		n = len(doc.Held)
+	}
+	update := append(bson.D{
+		{"rollingupgrade.held", doc.Held[n:]},
+		{"rollingupgrade.batch", doc.Held[:n]},
+	}, extra...)
+	return []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: s.rollingUpgradeUnchangedDoc(doc),
+		Update: bson.D{{"$set", update}},
+	}}
+}
+
+// AdvanceRollingUpgrade checks the health of the units in the
+// current batch of a running rolling upgrade. Once every unit in the
+// batch that is still alive runs the new charm, is active and has no
+// hooks left to run, the next batch is released. If any unit is in
+// an error state, the upgrade is paused instead. It is also paused if
+// an upgraded unit has had no hooks left to run for longer than
+// stallTimeout without its workload becoming active, as happens with
+// charms that never set their workload status; resuming such an
+// upgrade releases the next batch without waiting for the stalled one.
+func (s *Service) AdvanceRollingUpgrade(stallTimeout time.Duration) error {
+	doc := s.doc.RollingUpgrade
+	if doc == nil || doc.Status != RollingUpgradeRunning {
+		return nil
+	}
+	for _, name := range doc.Batch {
+		unit, err := s.st.Unit(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if unit.Life() != Alive {
+			continue
+		}
+		status, err := unit.Status()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if status.Status == StatusError {
+			logger.Warningf("pausing rolling upgrade of service %q: unit %q failed: %s", s, name, status.Message)
+			return s.PauseRollingUpgrade(fmt.Sprintf("unit %s failed: %s", name, status.Message))
+		}
+		agentStatus, err := unit.AgentStatus()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		curl, _ := unit.CharmURL()
+		upgraded := curl != nil && *curl == *s.doc.CharmURL
+		if !upgraded || agentStatus.Status != StatusIdle {
+			return nil
+		}
+		if status.Status != StatusActive {
+			if agentStatus.Since == nil || time.Since(*agentStatus.Since) < stallTimeout {
+				return nil
+			}
+			message := fmt.Sprintf(
+				"unit %s has been %s, not active, for more than %v after upgrading",
+				name, status.Status, stallTimeout,
+			)
+			logger.Warningf("pausing rolling upgrade of service %q: %s", s, message)
+			return s.setRollingUpgradeStatus(RollingUpgradePaused, message, true)
+		}
+	}
+	return s.NextRollingUpgradeBatch()
+}
+
+// PauseRollingUpgrade stops the rolling upgrade releasing batches,
+// recording the reason given.
+func (s *Service) PauseRollingUpgrade(message string) error {
+	return s.setRollingUpgradeStatus(RollingUpgradePaused, message, false)
+}
+
+// ResumeRollingUpgrade restarts a paused rolling upgrade. If it was
+// paused because a unit in the current batch stalled, the next batch
+// is released straight away.
+func (s *Service) ResumeRollingUpgrade() error {
+	return s.setRollingUpgradeStatus(RollingUpgradeRunning, "", false)
+}
+
+func (s *Service) setRollingUpgradeStatus(status RollingUpgradeStatus, message string, stalled bool) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		doc := s.doc.RollingUpgrade
+		if doc == nil {
+			return nil, errors.New("no rolling upgrade in progress")
+		}
+		if doc.Status == status && doc.Message == message && doc.Stalled == stalled {
+			return nil, jujutxn.ErrNoOperations
+		}
+		update := bson.D{
+			{"rollingupgrade.status", status},
+			{"rollingupgrade.message", message},
+			{"rollingupgrade.stalled", stalled},
+		}
+		if status == RollingUpgradeRunning && doc.Stalled {
+			return s.nextRollingUpgradeBatchOps(doc, update), nil
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: hasRollingUpgradeDoc,
+			Update: bson.D{{"$set", update}},
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set rolling upgrade of service %q to %s", s, status)
+	}
+	return s.Refresh()
+}
+
+// RollBackRollingUpgrade abandons the rolling upgrade, returning
+// the service to the charm it was using when the upgrade started.
+// Units already upgraded will be downgraded to that charm.
+func (s *Service) RollBackRollingUpgrade() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		doc := s.doc.RollingUpgrade
+		if doc == nil {
+			return nil, errors.New("no rolling upgrade in progress")
+		}
+		ch, err := s.st.Charm(doc.PreviousCharmURL)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops, err := s.changeCharmOps(ch, doc.PreviousForceCharm)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, s.finishRollingUpgradeOps(doc)...), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot roll back upgrade of service %q", s)
+	}
+	return s.Refresh()
+}
+
+// finishRollingUpgradeOps returns the operations needed to remove the
+// record of the given rolling upgrade.
+func (s *Service) finishRollingUpgradeOps(doc *rollingUpgradeDoc) []txn.Op {
+	return []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: s.rollingUpgradeUnchangedDoc(doc),
+		Update: bson.D{{"$unset", bson.D{{"rollingupgrade", nil}}}},
+	}}
+}
+
+// rollingUpgradeUnchangedDoc asserts that the held and batched units
+// of the rolling upgrade are as given.
+func (s *Service) rollingUpgradeUnchangedDoc(doc *rollingUpgradeDoc) bson.D {
+	return bson.D{
+		{"rollingupgrade.held", doc.Held},
+		{"rollingupgrade.batch", doc.Batch},
+	}
+}
+
+// unitNamesByNumber sorts unit names of a single service by unit
+// number.
+type unitNamesByNumber []string
+
+func (u unitNamesByNumber) Len() int      { return len(u) }
+func (u unitNamesByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitNamesByNumber) Less(i, j int) bool {
+	return unitNumber(u[i]) < unitNumber(u[j])
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
@@ -0,0 +1,247 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/state"
+)
+
+type RollingUpgradeSuite struct {
+	ConnSuite
+	oldCharm *state.Charm
+	newCharm *state.Charm
+	service  *state.Service
+	units    []*state.Unit
+}
+
+var _ = gc.Suite(&RollingUpgradeSuite{})
+
+func (s *RollingUpgradeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.oldCharm = s.AddTestingCharm(c, "upgrade1")
+	s.newCharm = s.AddTestingCharm(c, "upgrade2")
+	s.service = s.AddTestingService(c, "upgrade", s.oldCharm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *RollingUpgradeSuite) startUpgrade(c *gc.C, batchSize int) {
+	err := s.service.SetCharmRolling(s.newCharm, false, batchSize)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RollingUpgradeSuite) assertCharmURLForUnits(c *gc.C, expect ...*charm.URL) {
+	for i, unit := range s.units {
+		curl, _ := s.service.CharmURLForUnit(unit.Name())
+		c.Check(curl, gc.DeepEquals, expect[i], gc.Commentf("unit %s", unit))
+	}
+}
+
+// upgradeUnit makes the unit look as though it has finished upgrading
+// to the new charm and become healthy.
+func (s *RollingUpgradeSuite) upgradeUnit(c *gc.C, unit *state.Unit) {
+	err := unit.SetCharmURL(s.newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RollingUpgradeSuite) TestSetCharmRolling(c *gc.C) {
+	s.startUpgrade(c, 2)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.newCharm.URL())
+
+	upgrade, ok := s.service.RollingUpgrade()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(upgrade, jc.DeepEquals, state.RollingUpgrade{
+		PreviousCharmURL: s.oldCharm.URL(),
+		BatchSize:        2,
+		Held:             []string{"upgrade/0", "upgrade/1", "upgrade/2"},
+		Status:           state.RollingUpgradeRunning,
+	})
+	s.assertCharmURLForUnits(c, s.oldCharm.URL(), s.oldCharm.URL(), s.oldCharm.URL())
+
+	// New units use the new charm straight away.
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ = s.service.CharmURLForUnit(unit.Name())
+	c.Assert(curl, gc.DeepEquals, s.newCharm.URL())
+}
+
+func (s *RollingUpgradeSuite) TestSetCharmRollingErrors(c *gc.C) {
+	err := s.service.SetCharmRolling(s.newCharm, false, 0)
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade of service "upgrade": batch size must be at least 1, got 0`)
+	err = s.service.SetCharmRolling(s.oldCharm, false, 1)
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade of service "upgrade": service already uses charm "local:quantal/upgrade-1"`)
+
+	s.startUpgrade(c, 1)
+	err = s.service.SetCharmRolling(s.newCharm, false, 1)
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade of service "upgrade": rolling upgrade already in progress`)
+	err = s.service.SetCharm(s.oldCharm, false)
+	c.Assert(err, gc.ErrorMatches, `rolling upgrade in progress`)
+}
+
+func (s *RollingUpgradeSuite) TestSetCharmStaleServiceRollingUpgrade(c *gc.C) {
+	stale, err := s.State.Service(s.service.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	s.startUpgrade(c, 1)
+	err = stale.SetCharm(s.oldCharm, false)
+	c.Assert(err, gc.ErrorMatches, `rolling upgrade in progress`)
+}
+
+func (s *RollingUpgradeSuite) TestAdvanceRollingUpgrade(c *gc.C) {
+	s.startUpgrade(c, 2)
+
+	// The first batch is released straight away.
+	err := s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ := s.service.RollingUpgrade()
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/0", "upgrade/1"})
+	c.Assert(upgrade.Held, jc.DeepEquals, []string{"upgrade/2"})
+	s.assertCharmURLForUnits(c, s.newCharm.URL(), s.newCharm.URL(), s.oldCharm.URL())
+
+	// The batch is held until all its units are healthy.
+	s.upgradeUnit(c, s.units[0])
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = s.service.RollingUpgrade()
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/0", "upgrade/1"})
+
+	s.upgradeUnit(c, s.units[1])
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = s.service.RollingUpgrade()
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/2"})
+	c.Assert(upgrade.Held, gc.HasLen, 0)
+	s.assertCharmURLForUnits(c, s.newCharm.URL(), s.newCharm.URL(), s.newCharm.URL())
+
+	// Once the last batch is healthy, the upgrade is complete.
+	s.upgradeUnit(c, s.units[2])
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.service.RollingUpgrade()
+	c.Assert(ok, jc.IsFalse)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.service.RollingUpgrade()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *RollingUpgradeSuite) TestAdvanceRollingUpgradePausesOnError(c *gc.C) {
+	s.startUpgrade(c, 1)
+	err := s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.units[0].SetCharmURL(s.newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetAgentStatus(state.StatusError, `hook failed: "upgrade-charm"`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ := s.service.RollingUpgrade()
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradePaused)
+	c.Assert(upgrade.Message, gc.Equals, `unit upgrade/0 failed: hook failed: "upgrade-charm"`)
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/0"})
+
+	// A paused upgrade does not advance, even when healthy.
+	s.upgradeUnit(c, s.units[0])
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = s.service.RollingUpgrade()
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/0"})
+	err = s.service.NextRollingUpgradeBatch()
+	c.Assert(err, gc.ErrorMatches, `cannot release next batch of service "upgrade": rolling upgrade is paused`)
+
+	err = s.service.ResumeRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = s.service.RollingUpgrade()
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradeRunning)
+	c.Assert(upgrade.Message, gc.Equals, "")
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/1"})
+}
+
+func (s *RollingUpgradeSuite) TestAdvanceRollingUpgradePausesWhenStalled(c *gc.C) {
+	s.startUpgrade(c, 1)
+	err := s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The unit is upgraded and idle, but its workload never becomes
+	// active.
+	err = s.units[0].SetCharmURL(s.newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ := s.service.RollingUpgrade()
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradeRunning)
+
+	err = s.service.AdvanceRollingUpgrade(0)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = s.service.RollingUpgrade()
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradePaused)
+	c.Assert(upgrade.Message, gc.Matches, `unit upgrade/0 has been .*, not active, for more than 0 after upgrading`)
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/0"})
+
+	// Resuming releases the next batch without waiting for the
+	// stalled one.
+	err = s.service.ResumeRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ = s.service.RollingUpgrade()
+	c.Assert(upgrade.Status, gc.Equals, state.RollingUpgradeRunning)
+	c.Assert(upgrade.Message, gc.Equals, "")
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/1"})
+}
+
+func (s *RollingUpgradeSuite) TestRollBackRollingUpgrade(c *gc.C) {
+	s.startUpgrade(c, 1)
+	err := s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.PauseRollingUpgrade("oops")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.RollBackRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.oldCharm.URL())
+	_, ok := s.service.RollingUpgrade()
+	c.Assert(ok, jc.IsFalse)
+	s.assertCharmURLForUnits(c, s.oldCharm.URL(), s.oldCharm.URL(), s.oldCharm.URL())
+
+	err = s.service.RollBackRollingUpgrade()
+	c.Assert(err, gc.ErrorMatches, `cannot roll back upgrade of service "upgrade": no rolling upgrade in progress`)
+	err = s.service.ResumeRollingUpgrade()
+	c.Assert(err, gc.ErrorMatches, `cannot set rolling upgrade of service "upgrade" to running: no rolling upgrade in progress`)
+
+	// A normal upgrade is possible again.
+	err = s.service.SetCharm(s.newCharm, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RollingUpgradeSuite) TestAdvanceRollingUpgradeRemovedUnit(c *gc.C) {
+	s.startUpgrade(c, 1)
+	err := s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.AdvanceRollingUpgrade(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, _ := s.service.RollingUpgrade()
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"upgrade/1"})
+}
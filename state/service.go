@@ -47,6 +47,18 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	// EndpointBindings maps relation endpoint names to the names
+	// of the spaces they are bound to.
+	EndpointBindings map[string]string `bson:"endpointbindings,omitempty"`
+
+	// RollingUpgrade records the progress of a rolling charm
+	// upgrade, if one is under way.
+	RollingUpgrade *rollingUpgradeDoc `bson:"rollingupgrade,omitempty"`
+
+	// CharmHistory holds the charm URLs the service used before its
+	// current one, most recent last.
+	CharmHistory []*charm.URL `bson:"charmhistory,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -231,6 +243,8 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
 		removeStatusOp(s.st, s.globalKey()),
+		removeMeterThresholdsOp(s.st, s.globalKey()),
+		removeServiceConfigProfileOp(s.st, s.globalKey()),
 	}
 	return ops
 }
@@ -505,6 +519,7 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 	// Build the transaction.
 	var ops []txn.Op
 	differentCharm := bson.D{{"charmurl", bson.D{{"$ne", ch.URL()}}}}
+	history := charmHistoryAfter(s.doc.CharmHistory, s.doc.CharmURL, ch.URL())
 	if oldSettings != nil {
 		// Old settings shouldn't change (when they exist).
 		ops = append(ops, oldSettings.assertUnchangedOp())
@@ -514,12 +529,22 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		settingsOp,
 		// Increment the ref count.
 		incOp,
-		// Update the charm URL and force flag (if relevant).
+		// Update the charm URL, force flag (if relevant) and history.
 		{
 			C:      servicesC,
 			Id:     s.doc.DocID,
 			Assert: append(notDeadDoc, differentCharm...),
-			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
+			Update: bson.D{{"$set", bson.D{
+				{"charmurl", ch.URL()},
+				{"forcecharm", force},
+				{"charmhistory", history},
+			}}},
+		},
+		// The history was computed from the current charm.
+		{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"charmurl", s.doc.CharmURL}},
 		},
 	}...)
 	// Add any extra peer relations that need creation.
@@ -558,6 +583,17 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		return nil, errors.Trace(err)
 	}
 
+	// Check endpoint bindings to ensure no bound endpoint is removed.
+	oldCharm, _, err := s.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bindingOps, err := s.endpointBindingsUpgradeOps(oldCharm.Meta(), ch.Meta())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, bindingOps...)
+
 	// And finally, decrement the old settings.
 	return append(ops, decOps...), nil
 }
@@ -588,6 +624,13 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 			} else if !notDead {
 				return nil, ErrDead
 			}
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		// Rolling upgrades must be finished or rolled back first.
+		if s.doc.RollingUpgrade != nil {
+			return nil, errors.New("rolling upgrade in progress")
 		}
 		// Make sure the service doesn't have this charm already.
 		sel := bson.D{{"_id", s.doc.DocID}, {"charmurl", ch.URL()}}
@@ -610,14 +653,17 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 				return nil, errors.Trace(err)
 			}
 		}
+		ops = append(ops, txn.Op{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: noRollingUpgradeDoc,
+		})
 		return ops, nil
 	}
-	err := s.st.run(buildTxn)
-	if err == nil {
-		s.doc.CharmURL = ch.URL()
-		s.doc.ForceCharm = force
+	if err := s.st.run(buildTxn); err != nil {
+		return err
 	}
-	return err
+	return s.Refresh()
 }
 
 // String returns the service name.
@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ImportSubnets adds all of the given subnets that are not already
+// known, along with any spaces they refer to which do not exist yet,
+// in a single transaction. Subnets already in state (matched by CIDR)
+// are left untouched, so it is safe to call ImportSubnets repeatedly
+// with the full list of subnets known to the provider. The subnets
+// actually added are returned.
+func (st *State) ImportSubnets(infos []SubnetInfo) (added []*Subnet, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot import subnets")
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := checkEnvLife(st); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		added = nil
+		ops, err := st.importSubnetsOps(infos, &added)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(added) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, subnet := range added {
+		// As with AddSubnet, a clashing ProviderId makes the insert
+		// fail silently; refreshing catches this.
+		if err := subnet.Refresh(); err != nil {
+			return nil, errors.Errorf("ProviderId %q not unique", subnet.ProviderId())
+		}
+	}
+	return added, nil
+}
+
+// importSubnetsOps returns the operations needed to add the subnets
+// from infos which are not yet in state, and any spaces they refer to
+// which do not exist. The subnets to be added are appended to added.
+func (st *State) importSubnetsOps(infos []SubnetInfo, added *[]*Subnet) ([]txn.Op, error) {
+	existing, err := st.AllSubnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	knownCIDRs := set.NewStrings()
+	for _, subnet := range existing {
+		knownCIDRs.Add(subnet.CIDR())
+	}
+	spaces, err := st.AllSpaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	knownSpaces := set.NewStrings()
+	for _, space := range spaces {
+		knownSpaces.Add(space.Name())
+	}
+
+	ops := []txn.Op{assertEnvAliveOp(st.EnvironUUID())}
+	newSpaces := set.NewStrings()
+	for _, info := range infos {
+		if knownCIDRs.Contains(info.CIDR) {
+			continue
+		}
+		knownCIDRs.Add(info.CIDR)
+		subDoc := subnetDoc{
+			DocID:             st.docID(info.CIDR),
+			EnvUUID:           st.EnvironUUID(),
+			Life:              Alive,
+			CIDR:              info.CIDR,
+			VLANTag:           info.VLANTag,
+			ProviderId:        info.ProviderId,
+			AllocatableIPHigh: info.AllocatableIPHigh,
+			AllocatableIPLow:  info.AllocatableIPLow,
+			AvailabilityZone:  info.AvailabilityZone,
+			SpaceName:         info.SpaceName,
+		}
+		subnet := &Subnet{st: st, doc: subDoc}
+		if err := subnet.Validate(); err != nil {
+			return nil, errors.Annotatef(err, "subnet %q", info.CIDR)
+		}
+		ops = append(ops, txn.Op{
+			C:      subnetsC,
+			Id:     subDoc.DocID,
+			Assert: txn.DocMissing,
+			Insert: subDoc,
+		})
+		*added = append(*added, subnet)
+
+		spaceName := info.SpaceName
+		if spaceName == "" || knownSpaces.Contains(spaceName) || newSpaces.Contains(spaceName) {
+			continue
+		}
+		spaceOps, err := st.addSpaceOps(spaceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, spaceOps...)
+		newSpaces.Add(spaceName)
+	}
+	return ops, nil
+}
+
+// addSpaceOps returns the operations needed to create a new private
+// space with the given name and no subnets.
+func (st *State) addSpaceOps(name string) ([]txn.Op, error) {
+	if !names.IsValidSpace(name) {
+		return nil, errors.NewNotValid(nil, fmt.Sprintf("invalid space name %q", name))
+	}
+	spaceID := st.docID(name)
+	return []txn.Op{{
+		C:      spacesC,
+		Id:     spaceID,
+		Assert: txn.DocMissing,
+		Insert: spaceDoc{
+			DocID:   spaceID,
+			EnvUUID: st.EnvironUUID(),
+			Life:    Alive,
+			Name:    name,
+		},
+	}}, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ImportSubnetsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ImportSubnetsSuite{})
+
+func (s *ImportSubnetsSuite) TestImportSubnetsAddsSubnetsAndSpaces(c *gc.C) {
+	added, err := s.State.ImportSubnets([]state.SubnetInfo{{
+		CIDR:             "10.0.0.0/24",
+		ProviderId:       "subnet-a",
+		AvailabilityZone: "zone1",
+		SpaceName:        "internal",
+	}, {
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-b",
+		SpaceName:  "internal",
+	}, {
+		CIDR:       "192.168.1.0/24",
+		ProviderId: "subnet-c",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added, gc.HasLen, 3)
+
+	space, err := s.State.Space("internal")
+	c.Assert(err, jc.ErrorIsNil)
+	subnets, err := space.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 2)
+
+	subnet, err := s.State.Subnet("192.168.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.ProviderId(), gc.Equals, "subnet-c")
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+}
+
+func (s *ImportSubnetsSuite) TestImportSubnetsSkipsKnownSubnets(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24", ProviderId: "subnet-a"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	added, err := s.State.ImportSubnets([]state.SubnetInfo{{
+		CIDR:       "10.0.0.0/24",
+		ProviderId: "subnet-a",
+		SpaceName:  "dmz",
+	}, {
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-b",
+		SpaceName:  "internal",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added, gc.HasLen, 1)
+	c.Assert(added[0].CIDR(), gc.Equals, "10.0.1.0/24")
+	c.Assert(added[0].SpaceName(), gc.Equals, "internal")
+
+	// The known subnet keeps its space, so no "dmz" space is created.
+	subnet, err := s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+	_, err = s.State.Space("dmz")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Importing the same subnets again is a no-op.
+	added, err = s.State.ImportSubnets([]state.SubnetInfo{{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-b",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added, gc.HasLen, 0)
+}
+
+func (s *ImportSubnetsSuite) TestImportSubnetsInvalidSubnet(c *gc.C) {
+	added, err := s.State.ImportSubnets([]state.SubnetInfo{{
+		CIDR:       "10.0.0.0/24",
+		ProviderId: "subnet-a",
+	}, {
+		CIDR: "invalid",
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot import subnets: subnet "invalid": invalid CIDR address: invalid`)
+	c.Assert(added, gc.HasLen, 0)
+
+	// Nothing was imported.
+	subnets, err := s.State.AllSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 0)
+}
+
+func (s *ImportSubnetsSuite) TestImportSubnetsInvalidSpaceName(c *gc.C) {
+	_, err := s.State.ImportSubnets([]state.SubnetInfo{{
+		CIDR:      "10.0.0.0/24",
+		SpaceName: "Not Valid",
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot import subnets: invalid space name "Not Valid"`)
+}
@@ -0,0 +1,399 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/version"
+)
+
+// StagedUpgradeStatus describes the states a staged agent upgrade may
+// be in.
+type StagedUpgradeStatus string
+
+const (
+	// StagedUpgradeRunning indicates that machines are released to
+	// the new agent version as each batch becomes healthy.
+	StagedUpgradeRunning StagedUpgradeStatus = "running"
+
+	// StagedUpgradePaused indicates that a released machine failed
+	// to upgrade, and no more machines will be released until the
+	// upgrade is resumed.
+	StagedUpgradePaused StagedUpgradeStatus = "paused"
+
+	// StagedUpgradeAborted indicates that the operator stopped the
+	// upgrade; held machines remain at the previous version until
+	// the upgrade is resumed.
+	StagedUpgradeAborted StagedUpgradeStatus = "aborted"
+
+	// currentStagedUpgradeId is the local id of the staged upgrade
+	// document of an environment.
+	currentStagedUpgradeId = "current"
+)
+
+// stagedUpgradeDoc records the progress of a staged agent upgrade.
+// The environment's agent-version is set to the new version when the
+// upgrade starts; machines are then held at the previous version until
+// released, a batch at a time.
+type stagedUpgradeDoc struct {
+	DocID           string              `bson:"_id"`
+	EnvUUID         string              `bson:"env-uuid"`
+	PreviousVersion version.Number      `bson:"previousversion"`
+	Version         version.Number      `bson:"version"`
+	Canaries        []string            `bson:"canaries"`
+	BatchSize       int                 `bson:"batchsize"`
+	Batch           []string            `bson:"batch"`
+	Held            []string            `bson:"held"`
+	Status          StagedUpgradeStatus `bson:"status"`
+	Message         string              `bson:"message"`
+}
+
+// StagedUpgrade holds the progress of a staged agent upgrade.
+type StagedUpgrade struct {
+	st  *State
+	doc stagedUpgradeDoc
+}
+
+// PreviousVersion returns the agent version being upgraded from, at
+// which held machines remain.
+func (u *StagedUpgrade) PreviousVersion() version.Number {
+	return u.doc.PreviousVersion
+}
+
+// Version returns the agent version being upgraded to.
+func (u *StagedUpgrade) Version() version.Number {
+	return u.doc.Version
+}
+
+// Canaries returns the ids of the machines upgraded along with the
+// state servers, before any batch is released.
+func (u *StagedUpgrade) Canaries() []string {
+	return append([]string(nil), u.doc.Canaries...)
+}
+
+// BatchSize returns the number of machines released at a time.
+func (u *StagedUpgrade) BatchSize() int {
+	return u.doc.BatchSize
+}
+
+// Batch returns the ids of the machines most recently released, which
+// must become healthy at the new version before more are released.
+func (u *StagedUpgrade) Batch() []string {
+	return append([]string(nil), u.doc.Batch...)
+}
+
+// Held returns the ids of the machines held at the previous version.
+func (u *StagedUpgrade) Held() []string {
+	return append([]string(nil), u.doc.Held...)
+}
+
+// Status returns the status of the upgrade.
+func (u *StagedUpgrade) Status() StagedUpgradeStatus {
+	return u.doc.Status
+}
+
+// Message returns the reason the upgrade was paused or aborted.
+func (u *StagedUpgrade) Message() string {
+	return u.doc.Message
+}
+
+// IsHeld returns whether the machine with the given id is held at the
+// previous agent version.
+func (u *StagedUpgrade) IsHeld(machineId string) bool {
+	for _, id := range u.doc.Held {
+		if id == machineId {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh reads the current state of the upgrade. It returns an error
+// satisfying errors.IsNotFound if the upgrade has completed.
+func (u *StagedUpgrade) Refresh() error {
+	current, err := u.st.StagedUpgrade()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	u.doc = current.doc
+	return nil
+}
+
+// StagedUpgrade returns the environment's staged agent upgrade. It
+// returns an error satisfying errors.IsNotFound if there is none.
+func (st *State) StagedUpgrade() (*StagedUpgrade, error) {
+	coll, closer := st.getCollection(stagedUpgradesC)
+	defer closer()
+	var doc stagedUpgradeDoc
+	err := coll.FindId(st.docID(currentStagedUpgradeId)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("staged upgrade")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read staged upgrade")
+	}
+	return &StagedUpgrade{st: st, doc: doc}, nil
+}
+
+// StartStagedUpgrade sets the agent version for the environment, as
+// SetEnvironAgentVersion does, but holds every machine other than the
+// state servers and the given canaries at the current version. Held
+// machines are released batchSize at a time by AdvanceStagedUpgrade,
+// once all machines released before them have upgraded successfully.
+// Machines added during the upgrade use the new version.
+func (st *State) StartStagedUpgrade(newVersion version.Number, canaries []string, batchSize int) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot start staged upgrade to %s", newVersion)
+	if batchSize < 1 {
+		return errors.Errorf("batch size must be at least 1, got %d", batchSize)
+	}
+	if newVersion.Compare(version.Current.Number) > 0 && !st.IsStateServer() {
+		return errors.Errorf("a hosted environment cannot have a higher version than the server environment: %s > %s",
+			newVersion.String(),
+			version.Current.Number,
+		)
+	}
+	isCanary := make(map[string]bool)
+	for _, id := range canaries {
+		isCanary[id] = true
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.StagedUpgrade(); err == nil {
+			return nil, errors.New("staged upgrade already in progress")
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ops, previous, err := st.setEnvironAgentVersionOps(newVersion)
+		if err == jujutxn.ErrNoOperations {
+			return nil, errors.Errorf("agent version already set to %s", newVersion)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machines, err := st.AllMachines()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var batch, held []string
+		for _, m := range machines {
+			switch {
+			case isCanary[m.Id()]:
+				if m.Life() != Alive {
+					return nil, errors.Errorf("canary machine %s is not alive", m.Id())
+				}
+				ops = append(ops, txn.Op{
+					C:      machinesC,
+					Id:     m.doc.DocID,
+					Assert: isAliveDoc,
+				})
+				batch = append(batch, m.Id())
+			case m.IsManager():
+				batch = append(batch, m.Id())
+			case m.Life() != Dead:
+				held = append(held, m.Id())
+			}
+		}
+		released := set.NewStrings(batch...)
+		for _, id := range canaries {
+			if !released.Contains(id) {
+				return nil, errors.NotFoundf("canary machine %s", id)
+			}
+		}
+		doc := &stagedUpgradeDoc{
+			DocID:           st.docID(currentStagedUpgradeId),
+			EnvUUID:         st.EnvironUUID(),
+			PreviousVersion: previous,
+			Version:         newVersion,
+			Canaries:        canaries,
+			BatchSize:       batchSize,
+			Batch:           batch,
+			Held:            held,
+			Status:          StagedUpgradeRunning,
+		}
+		return append(ops, txn.Op{
+			C:      stagedUpgradesC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}), nil
+	}
+	return st.run(buildTxn)
+}
+
+// AdvanceStagedUpgrade checks the machines in the current batch of the
+// environment's staged upgrade. If any has failed to upgrade, the
+// upgrade is paused; if all are healthy at the new version, the next
+// batch of held machines is released. Once no machines are held, the
+// upgrade is complete and its record removed. AdvanceStagedUpgrade
+// does nothing if the upgrade is not running.
+func (st *State) AdvanceStagedUpgrade() error {
+	u, err := st.StagedUpgrade()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if u.doc.Status != StagedUpgradeRunning {
+		return nil
+	}
+	// State servers run upgrade steps together; wait for them all.
+	if upgrading, err := st.IsUpgrading(); err != nil {
+		return errors.Trace(err)
+	} else if upgrading {
+		return nil
+	}
+	for _, id := range u.doc.Batch {
+		healthy, failure, err := st.checkStagedUpgrade(id, u.doc.Version)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if failure != "" {
+			message := "machine " + id + " failed to upgrade: " + failure
+			logger.Warningf("pausing staged upgrade to %s: %s", u.doc.Version, message)
+			return u.setStatus(StagedUpgradePaused, message)
+		}
+		if !healthy {
+			return nil
+		}
+	}
+	return u.releaseNextBatch()
+}
+
+// checkStagedUpgrade reports whether the machine with the given id is
+// running the given version and has successfully completed its upgrade
+// steps, or if it failed to upgrade, why. Machines that no longer
+// exist are considered healthy.
+func (st *State) checkStagedUpgrade(id string, vers version.Number) (bool, string, error) {
+	m, err := st.Machine(id)
+	if errors.IsNotFound(err) {
+		return true, "", nil
+	} else if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if m.Life() != Alive {
+		return true, "", nil
+	}
+	statusInfo, err := m.Status()
+	if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if statusInfo.Status == StatusError {
+		return false, statusInfo.Message, nil
+	}
+	tools, err := m.AgentTools()
+	if errors.IsNotFound(err) {
+		return false, "", nil
+	} else if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	// Agents report started with a message while running their
+	// upgrade steps, and clear the message when done.
+	healthy := tools.Version.Number == vers &&
+		statusInfo.Status == StatusStarted &&
+		statusInfo.Message == ""
+	return healthy, "", nil
+}
+
+// releaseNextBatch releases the next batch of held machines, or
+// removes the upgrade if none are held.
+func (u *StagedUpgrade) releaseNextBatch() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Status != StagedUpgradeRunning {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if len(u.doc.Held) == 0 {
+			return []txn.Op{{
+				C:      stagedUpgradesC,
+				Id:     u.doc.DocID,
+				Assert: u.unchangedDoc(),
+				Remove: true,
+			}}, nil
+		}
+		n := u.doc.BatchSize
+		if n > len(u.doc.Held) {
+			n = len(u.doc.Held)
+		}
+		return []txn.Op{{
+			C:      stagedUpgradesC,
+			Id:     u.doc.DocID,
+			Assert: u.unchangedDoc(),
+			Update: bson.D{{"$set", bson.D{
+				{"held", u.doc.Held[n:]},
+				{"batch", u.doc.Held[:n]},
+			}}},
+		}}, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot release next batch of staged upgrade to %s", u.doc.Version)
+	}
+	if len(u.doc.Held) == 0 {
+		logger.Infof("staged upgrade to %s complete", u.doc.Version)
+	}
+	return nil
+}
+
+// AbortStagedUpgrade stops the environment's staged upgrade; machines
+// still held stay at the previous version until it is resumed.
+func (st *State) AbortStagedUpgrade(message string) error {
+	u, err := st.StagedUpgrade()
+	if err != nil {
+		return errors.Annotate(err, "cannot abort staged upgrade")
+	}
+	return u.setStatus(StagedUpgradeAborted, message)
+}
+
+// ResumeStagedUpgrade continues the environment's paused or aborted
+// staged upgrade.
+func (st *State) ResumeStagedUpgrade() error {
+	u, err := st.StagedUpgrade()
+	if err != nil {
+		return errors.Annotate(err, "cannot resume staged upgrade")
+	}
+	return u.setStatus(StagedUpgradeRunning, "")
+}
+
+func (u *StagedUpgrade) setStatus(status StagedUpgradeStatus, message string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Status == status && u.doc.Message == message {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      stagedUpgradesC,
+			Id:     u.doc.DocID,
+			Assert: bson.D{{"status", u.doc.Status}},
+			Update: bson.D{{"$set", bson.D{
+				{"status", status},
+				{"message", message},
+			}}},
+		}}, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set staged upgrade to %s", status)
+	}
+	u.doc.Status = status
+	u.doc.Message = message
+	return nil
+}
+
+// unchangedDoc asserts that the upgrade's progress is unchanged.
+func (u *StagedUpgrade) unchangedDoc() bson.D {
+	return bson.D{
+		{"status", u.doc.Status},
+		{"batch", u.doc.Batch},
+		{"held", u.doc.Held},
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type StagedUpgradeSuite struct {
+	ConnSuite
+	machines []*state.Machine
+	previous version.Number
+	newer    version.Number
+}
+
+var _ = gc.Suite(&StagedUpgradeSuite{})
+
+func (s *StagedUpgradeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machines = nil
+	m, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	s.machines = append(s.machines, m)
+	for i := 0; i < 3; i++ {
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		s.machines = append(s.machines, m)
+	}
+	for _, m := range s.machines {
+		err := m.SetAgentVersion(version.Current)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.previous = version.Current.Number
+	s.newer = s.previous
+	s.newer.Patch++
+}
+
+// upgradeMachine makes the machine appear to have upgraded to the new
+// version and completed its upgrade steps.
+func (s *StagedUpgradeSuite) upgradeMachine(c *gc.C, m *state.Machine) {
+	vers := version.Current
+	vers.Number = s.newer
+	err := m.SetAgentVersion(vers)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StagedUpgradeSuite) assertProgress(c *gc.C, batch, held []string) {
+	u, err := s.State.StagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Batch(), jc.DeepEquals, batch)
+	c.Assert(u.Held(), jc.DeepEquals, held)
+}
+
+func (s *StagedUpgradeSuite) TestStartStagedUpgrade(c *gc.C) {
+	err := s.State.StartStagedUpgrade(s.newer, []string{"2"}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, _ := cfg.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, s.newer)
+
+	u, err := s.State.StagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.PreviousVersion(), gc.Equals, s.previous)
+	c.Assert(u.Version(), gc.Equals, s.newer)
+	c.Assert(u.Canaries(), jc.DeepEquals, []string{"2"})
+	c.Assert(u.BatchSize(), gc.Equals, 1)
+	c.Assert(u.Batch(), jc.DeepEquals, []string{"0", "2"})
+	c.Assert(u.Held(), jc.DeepEquals, []string{"1", "3"})
+	c.Assert(u.Status(), gc.Equals, state.StagedUpgradeRunning)
+	c.Assert(u.IsHeld("1"), jc.IsTrue)
+	c.Assert(u.IsHeld("2"), jc.IsFalse)
+}
+
+func (s *StagedUpgradeSuite) TestStartStagedUpgradeErrors(c *gc.C) {
+	err := s.State.StartStagedUpgrade(s.newer, nil, 0)
+	c.Assert(err, gc.ErrorMatches, "cannot start staged upgrade to .*: batch size must be at least 1, got 0")
+	err = s.State.StartStagedUpgrade(s.newer, []string{"42"}, 1)
+	c.Assert(err, gc.ErrorMatches, "cannot start staged upgrade to .*: canary machine 42 not found")
+	err = s.State.StartStagedUpgrade(s.previous, nil, 1)
+	c.Assert(err, gc.ErrorMatches, "cannot start staged upgrade to .*: agent version already set to .*")
+	_, err = s.State.StagedUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.StartStagedUpgrade(s.newer, nil, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	newest := s.newer
+	newest.Patch++
+	err = s.State.StartStagedUpgrade(newest, nil, 1)
+	c.Assert(err, gc.ErrorMatches, "cannot start staged upgrade to .*: staged upgrade already in progress")
+	err = s.State.SetEnvironAgentVersion(s.previous)
+	c.Assert(err, gc.ErrorMatches, "cannot set agent version: staged upgrade in progress")
+}
+
+func (s *StagedUpgradeSuite) TestAdvanceStagedUpgrade(c *gc.C) {
+	err := s.State.StartStagedUpgrade(s.newer, []string{"2"}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing happens until every machine in the batch has upgraded.
+	s.upgradeMachine(c, s.machines[0])
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"0", "2"}, []string{"1", "3"})
+
+	// A machine still running its upgrade steps is not yet healthy.
+	s.upgradeMachine(c, s.machines[2])
+	err = s.machines[2].SetStatus(state.StatusStarted, "upgrading to "+s.newer.String(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"0", "2"}, []string{"1", "3"})
+
+	s.upgradeMachine(c, s.machines[2])
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"1"}, []string{"3"})
+
+	s.upgradeMachine(c, s.machines[1])
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"3"}, []string{})
+
+	s.upgradeMachine(c, s.machines[3])
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StagedUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Advancing with no upgrade is not an error.
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StagedUpgradeSuite) TestAdvanceStagedUpgradeRemovedMachine(c *gc.C) {
+	err := s.State.StartStagedUpgrade(s.newer, []string{"2"}, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	s.upgradeMachine(c, s.machines[0])
+	err = s.machines[2].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"1", "3"}, []string{})
+}
+
+func (s *StagedUpgradeSuite) TestAdvanceStagedUpgradePausesOnError(c *gc.C) {
+	err := s.State.StartStagedUpgrade(s.newer, []string{"2"}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.upgradeMachine(c, s.machines[0])
+	err = s.machines[2].SetStatus(state.StatusError, "upgrade failed", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	u, err := s.State.StagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Status(), gc.Equals, state.StagedUpgradePaused)
+	c.Assert(u.Message(), gc.Equals, "machine 2 failed to upgrade: upgrade failed")
+
+	// A paused upgrade releases nothing, even once healthy.
+	s.upgradeMachine(c, s.machines[2])
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"0", "2"}, []string{"1", "3"})
+
+	err = s.State.ResumeStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"1"}, []string{"3"})
+}
+
+func (s *StagedUpgradeSuite) TestAbortStagedUpgrade(c *gc.C) {
+	err := s.State.AbortStagedUpgrade("stop")
+	c.Assert(err, gc.ErrorMatches, "cannot abort staged upgrade: staged upgrade not found")
+
+	err = s.State.StartStagedUpgrade(s.newer, nil, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AbortStagedUpgrade("stop")
+	c.Assert(err, jc.ErrorIsNil)
+	u, err := s.State.StagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Status(), gc.Equals, state.StagedUpgradeAborted)
+	c.Assert(u.Message(), gc.Equals, "stop")
+
+	// Held machines stay held while aborted.
+	s.upgradeMachine(c, s.machines[0])
+	err = s.State.AdvanceStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertProgress(c, []string{"0"}, []string{"1", "2", "3"})
+
+	err = s.State.ResumeStagedUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Status(), gc.Equals, state.StagedUpgradeRunning)
+	c.Assert(u.Message(), gc.Equals, "")
+}
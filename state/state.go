@@ -438,43 +438,16 @@ func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
 	}
 
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		settings, err := readSettings(st, environGlobalKey)
+		ops, _, err := st.setEnvironAgentVersionOps(newVersion)
 		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		agentVersion, ok := settings.Get("agent-version")
-		if !ok {
-			return nil, errors.Errorf("no agent version set in the environment")
-		}
-		currentVersion, ok := agentVersion.(string)
-		if !ok {
-			return nil, errors.Errorf("invalid agent version format: expected string, got %v", agentVersion)
-		}
-		if newVersion.String() == currentVersion {
-			// Nothing to do.
-			return nil, jujutxn.ErrNoOperations
-		}
-
-		if err := st.checkCanUpgrade(currentVersion, newVersion.String()); err != nil {
-			return nil, errors.Trace(err)
-		}
-
-		ops := []txn.Op{
-			// Can't set agent-version if there's an active upgradeInfo doc.
-			{
-				C:      upgradeInfoC,
-				Id:     currentUpgradeId,
-				Assert: txn.DocMissing,
-			}, {
-				C:      settingsC,
-				Id:     st.docID(environGlobalKey),
-				Assert: bson.D{{"txn-revno", settings.txnRevno}},
-				Update: bson.D{
-					{"$set", bson.D{{"agent-version", newVersion.String()}}},
-				},
-			},
+			return nil, err
 		}
-		return ops, nil
+		// Staged upgrades must be finished before another starts.
+		return append(ops, txn.Op{
+			C:      stagedUpgradesC,
+			Id:     st.docID(currentStagedUpgradeId),
+			Assert: txn.DocMissing,
+		}), nil
 	}
 	if err = st.run(buildTxn); err == jujutxn.ErrExcessiveContention {
 		// Although there is a small chance of a race here, try to
@@ -482,6 +455,8 @@ func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
 		// active upgradeInfo document being in place.
 		if upgrading, _ := st.IsUpgrading(); upgrading {
 			err = UpgradeInProgressError
+		} else if _, suErr := st.StagedUpgrade(); suErr == nil {
+			return errors.New("cannot set agent version: staged upgrade in progress")
 		} else {
 			err = errors.Annotate(err, "cannot set agent version")
 		}
@@ -489,6 +464,54 @@ func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
 	return errors.Trace(err)
 }
 
+// setEnvironAgentVersionOps returns the operations needed to change
+// the agent version for the environment to the given version, along
+// with the current version. It returns jujutxn.ErrNoOperations if the
+// version is already set.
+func (st *State) setEnvironAgentVersionOps(newVersion version.Number) ([]txn.Op, version.Number, error) {
+	settings, err := readSettings(st, environGlobalKey)
+	if err != nil {
+		return nil, version.Zero, errors.Trace(err)
+	}
+	agentVersion, ok := settings.Get("agent-version")
+	if !ok {
+		return nil, version.Zero, errors.Errorf("no agent version set in the environment")
+	}
+	currentVersion, ok := agentVersion.(string)
+	if !ok {
+		return nil, version.Zero, errors.Errorf("invalid agent version format: expected string, got %v", agentVersion)
+	}
+	if newVersion.String() == currentVersion {
+		// Nothing to do.
+		return nil, version.Zero, jujutxn.ErrNoOperations
+	}
+
+	if err := st.checkCanUpgrade(currentVersion, newVersion.String()); err != nil {
+		return nil, version.Zero, errors.Trace(err)
+	}
+	current, err := version.Parse(currentVersion)
+	if err != nil {
+		return nil, version.Zero, errors.Trace(err)
+	}
+
+	ops := []txn.Op{
+		// Can't set agent-version if there's an active upgradeInfo doc.
+		{
+			C:      upgradeInfoC,
+			Id:     currentUpgradeId,
+			Assert: txn.DocMissing,
+		}, {
+			C:      settingsC,
+			Id:     st.docID(environGlobalKey),
+			Assert: bson.D{{"txn-revno", settings.txnRevno}},
+			Update: bson.D{
+				{"$set", bson.D{{"agent-version", newVersion.String()}}},
+			},
+		},
+	}
+	return ops, current, nil
+}
+
 func (st *State) buildAndValidateEnvironConfig(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) (validCfg *config.Config, err error) {
 	newConfig, err := oldConfig.Apply(updateAttrs)
 	if err != nil {
@@ -1096,6 +1119,27 @@ func (st *State) addPeerRelationsOps(serviceName string, peers map[string]charm.
 // they will be created automatically.
 func (st *State) AddService(
 	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints,
+) (*Service, error) {
+	return st.AddServiceWithOptions(name, owner, ch, networks, storage, AddServiceOptions{})
+}
+
+// AddServiceOptions holds optional attributes of a service that are
+// set in the same transaction that creates it.
+type AddServiceOptions struct {
+	// EndpointBindings maps relation endpoint names to the names of
+	// the spaces they are bound to.
+	EndpointBindings map[string]string
+
+	// ConfigProfile names the environment config profile whose
+	// settings the service uses in place of the charm defaults.
+	ConfigProfile string
+}
+
+// AddServiceWithOptions creates a new service as AddService does, also
+// setting the supplied optional attributes.
+func (st *State) AddServiceWithOptions(
+	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints,
+	options AddServiceOptions,
 ) (service *Service, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add service %q", name)
 	ownerTag, err := names.ParseUserTag(owner)
@@ -1132,6 +1176,14 @@ func (st *State) AddService(
 	if err := validateStorageConstraints(st, storage, ch.Meta()); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := validateEndpointBindings(st, ch.Meta(), options.EndpointBindings); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if options.ConfigProfile != "" {
+		if err := validateConfigProfile(st, options.ConfigProfile, ch); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	serviceID := st.docID(name)
 	// Create the service addition operations.
 	peers := ch.Meta().Peers
@@ -1146,6 +1198,9 @@ func (st *State) AddService(
 		Life:          Alive,
 		OwnerTag:      owner,
 	}
+	if len(options.EndpointBindings) > 0 {
+		svcDoc.EndpointBindings = options.EndpointBindings
+	}
 	svc := newService(st, svcDoc)
 
 	statusDoc := statusDoc{
@@ -1194,6 +1249,20 @@ func (st *State) AddService(
 		return nil, errors.Trace(err)
 	}
 	ops = append(ops, peerOps...)
+	for _, space := range options.EndpointBindings {
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     st.docID(space),
+			Assert: isAliveDoc,
+		})
+	}
+	if options.ConfigProfile != "" {
+		ops = append(ops, txn.Op{
+			C:      configProfilesC,
+			Id:     st.docID(options.ConfigProfile),
+			Assert: txn.DocExists,
+		}, createServiceConfigProfileOp(st, svc.globalKey(), options.ConfigProfile))
+	}
 
 	// At the last moment before inserting the service, prime status history.
 	probablyUpdateStatusHistory(st, svc.globalKey(), statusDoc)
@@ -1202,6 +1271,13 @@ func (st *State) AddService(
 		if err := checkEnvLife(st); err != nil {
 			return nil, errors.Trace(err)
 		}
+		if len(options.EndpointBindings) > 0 {
+			if exists, err := isNotDead(st, servicesC, name); err != nil {
+				return nil, errors.Trace(err)
+			} else if !exists {
+				return nil, errors.Errorf("one or more bound spaces are no longer alive")
+			}
+		}
 		return nil, errors.Errorf("service already exists")
 	} else if err != nil {
 		return nil, errors.Trace(err)
@@ -1217,7 +1293,7 @@ func (st *State) AddService(
 // error satisfying IsNotValid() or IsAlreadyExists() when the addr
 // does not contain a valid IP, or when addr is already added.
 func (st *State) AddIPAddress(addr network.Address, subnetID string) (*IPAddress, error) {
-	return addIPAddress(st, addr, subnetID)
+	return addIPAddress(st, addr, subnetID, AddressStateUnknown)
 }
 
 // IPAddress returns an existing IP address from the state.
@@ -1236,6 +1312,11 @@ func (st *State) AllocatedIPAddresses(machineId string) ([]*IPAddress, error) {
 	return fetchIPAddresses(st, bson.D{{"machineid", machineId}})
 }
 
+// AllIPAddresses returns all IP addresses known to the environment.
+func (st *State) AllIPAddresses() ([]*IPAddress, error) {
+	return fetchIPAddresses(st, nil)
+}
+
 // DeadIPAddresses returns all IP addresses with a Life of Dead
 func (st *State) DeadIPAddresses() ([]*IPAddress, error) {
 	return fetchIPAddresses(st, isDeadDoc)
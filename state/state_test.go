@@ -3711,6 +3711,7 @@ func (s *StateSuite) TestSetEnvironAgentVersionExcessiveContention(c *gc.C) {
 	}
 	defer state.SetBeforeHooks(c, s.State, changeFuncs...).Check()
 	err := s.State.SetEnvironAgentVersion(version.MustParse("4.5.6"))
+	c.Assert(err, gc.ErrorMatches, "cannot set agent version: state changing too quickly; try again soon")
 	c.Assert(errors.Cause(err), gc.Equals, txn.ErrExcessiveContention)
 	// Make sure the version remained the same.
 	assertAgentVersion(c, s.State, currentVersion)
@@ -4219,6 +4220,174 @@ func (s *StateSuite) TestEnsureAvailabilityConcurrentMore(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+// addStateServersInZones ensures availability with a state server in
+// each of the given availability zones.
+func (s *StateSuite) addStateServersInZones(c *gc.C, zones ...string) {
+	changes, err := s.State.EnsureAvailability(len(zones), constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, len(zones))
+	for i, zone := range zones {
+		zone := zone
+		m, err := s.State.Machine(fmt.Sprint(i))
+		c.Assert(err, jc.ErrorIsNil)
+		hc := &instance.HardwareCharacteristics{AvailabilityZone: &zone}
+		err = m.SetProvisioned(instance.Id(fmt.Sprint("inst-", i)), "fake_nonce", hc)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *StateSuite) assertReplicaSetPriorities(c *gc.C, expect ...float64) {
+	for i, priority := range expect {
+		m, err := s.State.Machine(fmt.Sprint(i))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(m.ReplicaSetPriority(), gc.Equals, priority, gc.Commentf("machine %d", i))
+	}
+}
+
+func (s *StateSuite) TestSetReplicaSetPreferencesZoneAndPrimary(c *gc.C) {
+	s.addStateServersInZones(c, "az1", "az2", "az1")
+
+	_, err := s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{PreferredZone: "az1"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReplicaSetPriorities(c, 2, 0, 2)
+
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{
+		PreferredZone:    "az1",
+		PreferredPrimary: "2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReplicaSetPriorities(c, 2, 0, 3)
+
+	// A new preference replaces the old priorities.
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{PreferredPrimary: "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReplicaSetPriorities(c, 0, 3, 0)
+
+	// Setting the same preferences again is a no-op.
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{PreferredPrimary: "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReplicaSetPriorities(c, 0, 3, 0)
+}
+
+func (s *StateSuite) TestSetReplicaSetPreferencesErrors(c *gc.C) {
+	s.addStateServersInZones(c, "az1", "az2", "az1")
+	m3, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{PreferredZone: "az3"})
+	c.Assert(err, gc.ErrorMatches, `cannot set replica set preferences: no voting state servers in availability zone "az3"`)
+
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{PreferredPrimary: m3.Id()})
+	c.Assert(err, gc.ErrorMatches, `cannot set replica set preferences: machine 3 is not a voting state server`)
+
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{Hidden: []string{"0"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set replica set preferences: machine 0 is a voting state server`)
+
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{Hidden: []string{"3/lxc/0"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set replica set preferences: container 3/lxc/0 cannot be a hidden state server`)
+	s.assertReplicaSetPriorities(c, 0, 0, 0)
+}
+
+func (s *StateSuite) TestSetReplicaSetPreferencesHidden(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	s.addStateServersInZones(c, "az1", "az2", "az1")
+	m3, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	changes, err := s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{Hidden: []string{m3.Id()}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Hidden, gc.DeepEquals, []string{"3"})
+
+	err = m3.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m3.Jobs(), gc.DeepEquals, []state.MachineJob{
+		state.JobHostUnits,
+		state.JobManageEnviron,
+	})
+	c.Assert(m3.WantsVote(), jc.IsFalse)
+	c.Assert(m3.ReplicaSetHidden(), jc.IsTrue)
+	s.assertStateServerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "1", "2"}, nil)
+
+	// Hiding the machine again changes nothing.
+	changes, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{Hidden: []string{m3.Id()}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Hidden, gc.HasLen, 0)
+
+	// A hidden state server is never promoted to a voter.
+	changes, err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Promoted, gc.HasLen, 0)
+	c.Assert(changes.Maintained, jc.SameContents, []string{"0", "1", "2", "3"})
+	s.assertStateServerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "1", "2"}, nil)
+}
+
+func (s *StateSuite) TestSetReplicaSetPreferencesReset(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	s.addStateServersInZones(c, "az1", "az2", "az1")
+	m3, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{
+		PreferredZone:    "az1",
+		PreferredPrimary: "2",
+		Hidden:           []string{m3.Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReplicaSetPriorities(c, 2, 0, 3)
+
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{Reset: true})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReplicaSetPriorities(c, 0, 0, 0)
+	err = m3.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m3.ReplicaSetHidden(), jc.IsFalse)
+	c.Assert(m3.WantsVote(), jc.IsFalse)
+
+	_, err = s.State.SetReplicaSetPreferences(state.ReplicaSetPreferences{
+		Reset:            true,
+		PreferredPrimary: "1",
+	})
+	c.Assert(err, gc.ErrorMatches, "resetting replica set preferences while setting new ones not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *StateSuite) TestEnsureAvailabilityWithPreferences(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	s.addStateServersInZones(c, "az1")
+	m1, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	changes, err := s.State.EnsureAvailabilityWithPreferences(3, constraints.Value{}, "quantal", nil, state.ReplicaSetPreferences{
+		PreferredPrimary: "0",
+		Hidden:           []string{m1.Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.DeepEquals, []string{"2", "3"})
+	c.Assert(changes.Hidden, gc.DeepEquals, []string{"1"})
+	s.assertReplicaSetPriorities(c, 3)
+	s.assertStateServerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "2", "3"}, nil)
+}
+
+func (s *StateSuite) TestEnsureAvailabilityWithInvalidPreferencesChangesNothing(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	s.addStateServersInZones(c, "az1")
+
+	_, err := s.State.EnsureAvailabilityWithPreferences(3, constraints.Value{}, "quantal", nil, state.ReplicaSetPreferences{
+		PreferredZone: "az2",
+	})
+	c.Assert(err, gc.ErrorMatches, `failed to create new state server machines: no voting state servers in availability zone "az2"`)
+	s.assertStateServerInfo(c, []string{"0"}, []string{"0"}, nil)
+	_, err = s.State.Machine("1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *StateSuite) TestStateServingInfo(c *gc.C) {
 	info, err := s.State.StateServingInfo()
 	c.Assert(err, gc.ErrorMatches, "state serving info not found")
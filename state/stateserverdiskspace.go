@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// StateServerDiskSpace records the free space in a state server's
+// data directory, as last reported by its machine agent.
+type StateServerDiskSpace struct {
+	MachineId string
+	Path      string
+	Free      uint64
+	Updated   time.Time
+}
+
+// stateServerDiskSpaceDoc is the document recording the free space in
+// a state server's data directory, keyed by machine id.
+type stateServerDiskSpaceDoc struct {
+	MachineId string    `bson:"_id"`
+	Path      string    `bson:"path"`
+	Free      int64     `bson:"free"`
+	Updated   time.Time `bson:"updated"`
+}
+
+// SetStateServerDiskSpace records the free space in the data directory
+// of the given state server machine, replacing any earlier record.
+// The record is not part of the environment's transactional state, so
+// it is written directly.
+func (st *State) SetStateServerDiskSpace(machineId, path string, free uint64) error {
+	diskSpace, closer := st.getCollection(stateServerDiskSpaceC)
+	defer closer()
+
+	doc := stateServerDiskSpaceDoc{
+		MachineId: machineId,
+		Path:      path,
+		Free:      int64(free),
+		Updated:   nowToTheSecond(),
+	}
+	if _, err := diskSpace.Writeable().UpsertId(machineId, doc); err != nil {
+		return errors.Annotatef(err, "cannot record free space on state server %q", machineId)
+	}
+	return nil
+}
+
+// StateServerDiskSpace returns the free space last recorded for each
+// state server, ordered by machine id.
+func (st *State) StateServerDiskSpace() ([]StateServerDiskSpace, error) {
+	diskSpace, closer := st.getCollection(stateServerDiskSpaceC)
+	defer closer()
+
+	var docs []stateServerDiskSpaceDoc
+	if err := diskSpace.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get state server free space")
+	}
+	results := make([]StateServerDiskSpace, len(docs))
+	for i, doc := range docs {
+		results[i] = StateServerDiskSpace{
+			MachineId: doc.MachineId,
+			Path:      doc.Path,
+			Free:      uint64(doc.Free),
+			Updated:   doc.Updated,
+		}
+	}
+	return results, nil
+}
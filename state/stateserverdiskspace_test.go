@@ -0,0 +1,42 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type StateServerDiskSpaceSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&StateServerDiskSpaceSuite{})
+
+func (s *StateServerDiskSpaceSuite) TestNoneRecorded(c *gc.C) {
+	recorded, err := s.State.StateServerDiskSpace()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, gc.HasLen, 0)
+}
+
+func (s *StateServerDiskSpaceSuite) TestSetStateServerDiskSpace(c *gc.C) {
+	err := s.State.SetStateServerDiskSpace("1", "/var/lib/juju", 1000)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetStateServerDiskSpace("0", "/var/lib/juju", 2000)
+	c.Assert(err, jc.ErrorIsNil)
+	// A later record replaces the earlier one.
+	err = s.State.SetStateServerDiskSpace("1", "/srv/juju", 3000)
+	c.Assert(err, jc.ErrorIsNil)
+
+	recorded, err := s.State.StateServerDiskSpace()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, gc.HasLen, 2)
+	c.Check(recorded[0].MachineId, gc.Equals, "0")
+	c.Check(recorded[0].Path, gc.Equals, "/var/lib/juju")
+	c.Check(recorded[0].Free, gc.Equals, uint64(2000))
+	c.Check(recorded[1].MachineId, gc.Equals, "1")
+	c.Check(recorded[1].Path, gc.Equals, "/srv/juju")
+	c.Check(recorded[1].Free, gc.Equals, uint64(3000))
+	c.Check(recorded[1].Updated.IsZero(), jc.IsFalse)
+}
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/dustin/go-humanize"
 	"github.com/juju/errors"
@@ -128,6 +129,11 @@ type storageInstanceDoc struct {
 	StorageName     string      `bson:"storagename"`
 	AttachmentCount int         `bson:"attachmentcount"`
 	CharmURL        *charm.URL  `bson:"charmurl"`
+
+	// Detached records that the storage instance has been detached
+	// from its owner, and should be retained when its last attachment
+	// is removed so that it may be attached to another unit.
+	Detached bool `bson:"detached,omitempty"`
 }
 
 type storageAttachment struct {
@@ -312,7 +318,14 @@ func removeStorageInstanceOps(
 	} else if !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
-	return ops, nil
+
+	// If the storage instance was imported, remove the record of the
+	// import so that the provider storage may be imported again.
+	importOps, err := removeStorageImportOps(st, tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(ops, importOps...), nil
 }
 
 // createStorageOps returns txn.Ops for creating storage instances
@@ -393,30 +406,48 @@ func createStorageOps(
 			return nil, -1, errors.Errorf("unknown storage type %q", t.meta.Type)
 		}
 
-		for i := uint64(0); i < t.cons.Count; i++ {
-			id, err := newStorageInstanceId(st, t.storageName)
+		// Units use storage imported into the pool named in the
+		// constraints, before any new storage is created.
+		var imported []*storageInstance
+		if _, ok := entity.(names.UnitTag); ok {
+			imported, err = st.importedStorageInstances(t.storageName, kind, t.cons.Pool)
 			if err != nil {
-				return nil, -1, errors.Annotate(err, "cannot generate storage instance name")
-			}
-			doc := &storageInstanceDoc{
-				Id:          id,
-				Kind:        kind,
-				Owner:       owner,
-				StorageName: t.storageName,
-				CharmURL:    curl,
+				return nil, -1, errors.Trace(err)
 			}
-			if unit, ok := entity.(names.UnitTag); ok {
-				doc.AttachmentCount = 1
-				storage := names.NewStorageTag(id)
-				ops = append(ops, createStorageAttachmentOp(storage, unit))
+		}
+
+		for i := uint64(0); i < t.cons.Count; i++ {
+			var doc *storageInstanceDoc
+			if len(imported) > 0 {
+				doc = &imported[0].doc
+				imported = imported[1:]
+				ops = append(ops, adoptImportedStorageOps(doc, entity.(names.UnitTag), curl)...)
 				numStorageAttachments++
+			} else {
+				id, err := newStorageInstanceId(st, t.storageName)
+				if err != nil {
+					return nil, -1, errors.Annotate(err, "cannot generate storage instance name")
+				}
+				doc = &storageInstanceDoc{
+					Id:          id,
+					Kind:        kind,
+					Owner:       owner,
+					StorageName: t.storageName,
+					CharmURL:    curl,
+				}
+				if unit, ok := entity.(names.UnitTag); ok {
+					doc.AttachmentCount = 1
+					storage := names.NewStorageTag(id)
+					ops = append(ops, createStorageAttachmentOp(storage, unit))
+					numStorageAttachments++
+				}
+				ops = append(ops, txn.Op{
+					C:      storageInstancesC,
+					Id:     id,
+					Assert: txn.DocMissing,
+					Insert: doc,
+				})
 			}
-			ops = append(ops, txn.Op{
-				C:      storageInstancesC,
-				Id:     id,
-				Assert: txn.DocMissing,
-				Insert: doc,
-			})
 			if machineOpsNeeded {
 				machineOps, err := unitAssignedMachineStorageOps(
 					st, entity, charmMeta, cons, series,
@@ -426,7 +457,7 @@ func createStorageOps(
 					ops = append(ops, machineOps...)
 				} else if !errors.IsNotAssigned(err) {
 					return nil, -1, errors.Annotatef(
-						err, "creating machine storage for storage %s", id,
+						err, "creating machine storage for storage %s", doc.Id,
 					)
 				}
 			}
@@ -621,6 +652,374 @@ func destroyStorageAttachmentOps(storage names.StorageTag, unit names.UnitTag) [
 	return ops
 }
 
+// DetachStorage ensures that the storage instance will be detached from
+// the unit that owns it, and retained so that it may later be attached to
+// another unit with AttachStorage. The storage attachment is made Dying,
+// so the unit's storage-detaching hook will run before it is removed.
+func (st *State) DetachStorage(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot detach storage %s from unit %s", storage.Id(), unit.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		si, err := st.storageInstance(storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.doc.Life != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		if si.doc.Owner != unit.String() {
+			// Shared storage is owned by the service, and
+			// cannot be detached from individual units.
+			return nil, errors.Errorf("storage is not owned by unit %s", unit.Id())
+		}
+		s, err := st.storageAttachment(storage, unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.doc.Detached && s.doc.Life != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{{
+			C:  storageInstancesC,
+			Id: si.doc.Id,
+			Assert: append(bson.D{
+				{"owner", unit.String()},
+			}, isAliveDoc...),
+			Update: bson.D{{"$set", bson.D{{"detached", true}}}},
+		}}
+		if s.doc.Life == Alive {
+			ops = append(ops, destroyStorageAttachmentOps(storage, unit)...)
+		} else {
+			// The attachment is already Dying, e.g. because
+			// the unit is being destroyed; we need only ensure
+			// that the storage instance is retained.
+			ops = append(ops, txn.Op{
+				C:      storageAttachmentsC,
+				Id:     storageAttachmentId(unit.Id(), storage.Id()),
+				Assert: txn.DocExists,
+			})
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// AttachStorage attaches the detached storage instance to the specified
+// unit, which must be assigned to a machine. The unit's charm must define
+// non-shared storage with the same name and kind as the storage instance.
+//
+// Volumes and filesystems that are scoped to a machine can only be
+// attached to units assigned to that machine; environment-scoped volumes
+// and filesystems will be detached from any other machines, and attached
+// to the unit's machine.
+func (st *State) AttachStorage(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot attach storage %s to unit %s", storage.Id(), unit.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		si, err := st.storageInstance(storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.doc.Life != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		if !si.doc.Detached {
+			return nil, errors.New("storage is not detached")
+		}
+		if si.doc.AttachmentCount > 0 {
+			return nil, errors.New("storage is still being detached")
+		}
+		u, err := st.Unit(unit.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if u.Life() != Alive {
+			return nil, unitNotAliveErr
+		}
+		machineId, err := u.AssignedMachineId()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m, err := st.Machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		s, err := u.Service()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := s.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		charmStorage, err := st.validateAttachStorage(ch.Meta(), u, si)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machineOps, err := st.attachStorageMachineOps(m, u.Series(), charmStorage, si)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:  storageInstancesC,
+			Id: si.doc.Id,
+			Assert: append(bson.D{
+				{"detached", true},
+				{"attachmentcount", 0},
+			}, isAliveDoc...),
+			Update: bson.D{
+				{"$set", bson.D{
+					{"owner", unit.String()},
+					{"attachmentcount", 1},
+				}},
+				{"$unset", bson.D{{"detached", nil}}},
+			},
+		}, {
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$inc", bson.D{{"storageattachmentcount", 1}}}},
+		},
+			createStorageAttachmentOp(storage, unit),
+		}
+		return append(ops, machineOps...), nil
+	}
+	return st.run(buildTxn)
+}
+
+// validateAttachStorage checks that the storage instance may be attached
+// to the unit, and returns the unit's charm storage metadata for it.
+func (st *State) validateAttachStorage(charmMeta *charm.Meta, u *Unit, si *storageInstance) (charm.Storage, error) {
+	name := si.StorageName()
+	charmStorage, ok := charmMeta.Storage[name]
+	if !ok {
+		return charm.Storage{}, errors.NotFoundf("charm storage %q", name)
+	}
+	if charmStorage.Shared {
+		return charm.Storage{}, errors.Errorf("charm storage %q is shared", name)
+	}
+	var kind StorageKind
+	switch charmStorage.Type {
+	case charm.StorageBlock:
+		kind = StorageKindBlock
+	case charm.StorageFilesystem:
+		kind = StorageKindFilesystem
+	}
+	if kind != si.Kind() {
+		return charm.Storage{}, errors.Errorf(
+			"charm storage %q has type %q, storage has kind %v",
+			name, charmStorage.Type, si.Kind(),
+		)
+	}
+	count, err := st.countEntityStorageInstancesForName(u.Tag(), name)
+	if err != nil {
+		return charm.Storage{}, errors.Trace(err)
+	}
+	if charmStorage.CountMax >= 0 && count >= uint64(charmStorage.CountMax) {
+		return charm.Storage{}, errors.Errorf(
+			"unit already has %d %q storage instance(s), the maximum allowed by the charm",
+			count, name,
+		)
+	}
+	return charmStorage, nil
+}
+
+// attachStorageMachineOps returns txn.Ops to attach the volume or
+// filesystem assigned to the storage instance to the specified machine,
+// detaching it from any other machines.
+func (st *State) attachStorageMachineOps(
+	m *Machine, series string, charmStorage charm.Storage, si *storageInstance,
+) ([]txn.Op, error) {
+	var ops []txn.Op
+	var volumeAttachments []volumeAttachmentTemplate
+	var filesystemAttachments []filesystemAttachmentTemplate
+
+	volume, err := st.storageInstanceVolume(si.StorageTag())
+	if err == nil {
+		params := VolumeAttachmentParams{charmStorage.ReadOnly}
+		volumeOps, attach, err := st.moveVolumeAttachmentOps(m, volume, true)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, volumeOps...)
+		if attach {
+			volumeAttachments = append(volumeAttachments, volumeAttachmentTemplate{
+				volume.VolumeTag(), params,
+			})
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	filesystem, err := st.storageInstanceFilesystem(si.StorageTag())
+	if err == nil {
+		location, err := filesystemMountPoint(charmStorage, si.StorageTag(), series)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		params := FilesystemAttachmentParams{
+			charmStorage.Location == "", // auto-generated location
+			location,
+			charmStorage.ReadOnly,
+		}
+		filesystemOps, attach, err := st.moveFilesystemAttachmentOps(m, filesystem)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, filesystemOps...)
+		if attach {
+			filesystemAttachments = append(filesystemAttachments, filesystemAttachmentTemplate{
+				filesystem.FilesystemTag(), si.StorageTag(), params,
+			})
+		}
+		if volumeTag, err := filesystem.Volume(); err == nil {
+			// The filesystem is volume-backed, so the volume must
+			// be attached to the machine too. The volume will be
+			// detached from other machines when the filesystem is.
+			volume, err := st.volumeByTag(volumeTag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			volumeOps, attach, err := st.moveVolumeAttachmentOps(m, volume, false)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, volumeOps...)
+			if attach {
+				volumeAttachments = append(volumeAttachments, volumeAttachmentTemplate{
+					volumeTag, VolumeAttachmentParams{},
+				})
+			}
+		} else if errors.Cause(err) != ErrNoBackingVolume {
+			return nil, errors.Trace(err)
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	} else if filesystemParams, err := st.importedFilesystemParams(si.StorageTag()); err == nil {
+		// The storage was imported from a filesystem, which is
+		// recorded now that the storage is attached to a machine.
+		location, err := filesystemMountPoint(charmStorage, si.StorageTag(), series)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		params := FilesystemAttachmentParams{
+			charmStorage.Location == "", // auto-generated location
+			location,
+			charmStorage.ReadOnly,
+		}
+		filesystemOps, filesystemTag, _, err := st.addFilesystemOps(filesystemParams, m.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, filesystemOps...)
+		filesystemAttachments = append(filesystemAttachments, filesystemAttachmentTemplate{
+			filesystemTag, si.StorageTag(), params,
+		})
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	if len(volumeAttachments) == 0 && len(filesystemAttachments) == 0 {
+		return ops, nil
+	}
+	ops = append(ops, createMachineVolumeAttachmentsOps(m.Id(), volumeAttachments)...)
+	ops = append(ops, createMachineFilesystemAttachmentsOps(m.Id(), filesystemAttachments)...)
+	attachmentOps, err := addMachineStorageAttachmentsOps(m, volumeAttachments, filesystemAttachments)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(ops, attachmentOps...), nil
+}
+
+// moveVolumeAttachmentOps returns txn.Ops to prepare the volume for
+// attachment to the specified machine, and whether or not a new volume
+// attachment is required. If detach is true, then the volume will be
+// detached from any other machines.
+func (st *State) moveVolumeAttachmentOps(m *Machine, v *volume, detach bool) ([]txn.Op, bool, error) {
+	if v.doc.Life != Alive {
+		return nil, false, errors.Errorf("volume %q is not alive", v.doc.Name)
+	}
+	if scope := storageMachineScope(v.doc.Name); scope != "" && scope != m.Id() {
+		return nil, false, errors.Errorf(
+			"volume %q is scoped to machine %s, and cannot be attached to machine %s",
+			v.doc.Name, scope, m.Id(),
+		)
+	}
+	attachments, err := st.VolumeAttachments(v.VolumeTag())
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	var ops []txn.Op
+	for _, a := range attachments {
+		if a.Machine() == m.MachineTag() {
+			if a.Life() != Alive {
+				return nil, false, errors.Errorf(
+					"volume %q is still being detached from machine %s",
+					v.doc.Name, m.Id(),
+				)
+			}
+			return nil, false, nil
+		}
+		if detach && a.Life() == Alive {
+			ops = append(ops, detachVolumeOps(a.Machine(), v.VolumeTag())...)
+		}
+	}
+	return append(ops, txn.Op{
+		C:      volumesC,
+		Id:     v.doc.Name,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+	}), true, nil
+}
+
+// moveFilesystemAttachmentOps returns txn.Ops to prepare the filesystem
+// for attachment to the specified machine, detaching it from any other
+// machines, and whether or not a new filesystem attachment is required.
+func (st *State) moveFilesystemAttachmentOps(m *Machine, f *filesystem) ([]txn.Op, bool, error) {
+	if f.doc.Life != Alive {
+		return nil, false, errors.Errorf("filesystem %q is not alive", f.doc.FilesystemId)
+	}
+	if scope := storageMachineScope(f.doc.FilesystemId); scope != "" && scope != m.Id() {
+		return nil, false, errors.Errorf(
+			"filesystem %q is scoped to machine %s, and cannot be attached to machine %s",
+			f.doc.FilesystemId, scope, m.Id(),
+		)
+	}
+	attachments, err := st.FilesystemAttachments(f.FilesystemTag())
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	var ops []txn.Op
+	for _, a := range attachments {
+		if a.Machine() == m.MachineTag() {
+			if a.Life() != Alive {
+				return nil, false, errors.Errorf(
+					"filesystem %q is still being detached from machine %s",
+					f.doc.FilesystemId, m.Id(),
+				)
+			}
+			return nil, false, nil
+		}
+		if a.Life() == Alive {
+			ops = append(ops, detachFilesystemOps(a.Machine(), f.FilesystemTag())...)
+		}
+	}
+	return append(ops, txn.Op{
+		C:      filesystemsC,
+		Id:     f.doc.FilesystemId,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+	}), true, nil
+}
+
+// storageMachineScope returns the ID of the machine that the volume or
+// filesystem with the specified ID is scoped to, or "" if it is scoped
+// to the environment.
+func storageMachineScope(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		return id[:i]
+	}
+	return ""
+}
+
 // Remove removes the storage attachment from state, and may remove its storage
 // instance as well, if the storage instance is Dying and no other references to
 // it exist. It will fail if the storage attachment is not Dead.
@@ -673,8 +1072,25 @@ func removeStorageAttachmentOps(
 		var hasLastRef bson.D
 		if si.doc.Life == Dying {
 			hasLastRef = bson.D{{"life", Dying}, {"attachmentcount", 1}}
-		} else if si.doc.Owner == names.NewUnitTag(s.doc.Unit).String() {
-			hasLastRef = bson.D{{"attachmentcount", 1}}
+		} else if si.doc.Owner == names.NewUnitTag(s.doc.Unit).String() && !si.doc.Detached {
+			policy, err := st.StorageReleasePolicy(si.StorageTag())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if policy == poolmanager.ReleaseRetain {
+				// The storage's pool requires that it be retained
+				// when released by its owner, so we detach it rather
+				// than removing it.
+				retainOps, err := st.retainStorageInstanceOps(si)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				return append(ops, retainOps...), nil
+			}
+			hasLastRef = bson.D{
+				{"attachmentcount", 1},
+				{"detached", bson.D{{"$ne", true}}},
+			}
 		}
 		if len(hasLastRef) > 0 {
 			// Either the storage instance is dying, or its owner
@@ -688,6 +1104,15 @@ func removeStorageAttachmentOps(
 			return ops, nil
 		}
 	}
+	if si.doc.Life == Alive && si.doc.Detached && si.doc.AttachmentCount == 1 {
+		// The last attachment of detached storage is being
+		// removed, so detach its volume or filesystem too.
+		detachOps, err := st.detachRetainedStorageOps(si.StorageTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, detachOps...)
+	}
 	decrefOp := txn.Op{
 		C:      storageInstancesC,
 		Id:     si.doc.Id,
@@ -715,14 +1140,131 @@ func removeStorageAttachmentOps(
 	return ops, nil
 }
 
+// StorageReleasePolicy returns the release policy for the storage
+// instance with the specified tag, as configured on the pool that its
+// volume or filesystem was provisioned from. Storage provisioned
+// directly from a storage provider, rather than a pool, is destroyed
+// when released.
+func (st *State) StorageReleasePolicy(tag names.StorageTag) (poolmanager.ReleasePolicy, error) {
+	poolName, err := st.storageInstancePool(tag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if poolName == "" {
+		return poolmanager.ReleaseDestroy, nil
+	}
+	pool, err := poolmanager.New(NewStateSettings(st)).Get(poolName)
+	if errors.IsNotFound(err) {
+		return poolmanager.ReleaseDestroy, nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return poolmanager.PoolReleasePolicy(pool)
+}
+
+// storageInstancePool returns the name of the pool that the volume or
+// filesystem assigned to the storage instance was provisioned from, or
+// "" if no volume or filesystem has been assigned.
+func (st *State) storageInstancePool(tag names.StorageTag) (string, error) {
+	filesystem, err := st.storageInstanceFilesystem(tag)
+	if err == nil {
+		if filesystem.doc.Info != nil {
+			return filesystem.doc.Info.Pool, nil
+		} else if filesystem.doc.Params != nil {
+			return filesystem.doc.Params.Pool, nil
+		}
+		return "", nil
+	} else if !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	volume, err := st.storageInstanceVolume(tag)
+	if err == nil {
+		if volume.doc.Info != nil {
+			return volume.doc.Info.Pool, nil
+		} else if volume.doc.Params != nil {
+			return volume.doc.Params.Pool, nil
+		}
+		return "", nil
+	} else if !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	return "", nil
+}
+
+// retainStorageInstanceOps returns txn.Ops to detach the storage instance
+// from its owner as its last attachment is removed, retaining it so that
+// it may be attached to another unit.
+func (st *State) retainStorageInstanceOps(si *storageInstance) ([]txn.Op, error) {
+	ops := []txn.Op{{
+		C:  storageInstancesC,
+		Id: si.doc.Id,
+		Assert: append(bson.D{
+			{"owner", si.doc.Owner},
+			{"attachmentcount", 1},
+			{"detached", bson.D{{"$ne", true}}},
+		}, isAliveDoc...),
+		Update: bson.D{
+			{"$set", bson.D{{"detached", true}}},
+			{"$inc", bson.D{{"attachmentcount", -1}}},
+		},
+	}}
+	detachOps, err := st.detachRetainedStorageOps(si.StorageTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(ops, detachOps...), nil
+}
+
+// detachRetainedStorageOps returns txn.Ops to detach the volume or
+// filesystem assigned to a retained storage instance from the machines
+// it is attached to. Only environment-scoped volumes and filesystems
+// bound to the storage instance are detached: those bound to a machine
+// would be destroyed when detached, and those scoped to a machine can
+// only ever be attached to that machine.
+func (st *State) detachRetainedStorageOps(tag names.StorageTag) ([]txn.Op, error) {
+	var ops []txn.Op
+	volume, err := st.storageInstanceVolume(tag)
+	if err == nil && volume.LifeBinding() == tag && storageMachineScope(volume.doc.Name) == "" {
+		attachments, err := st.VolumeAttachments(volume.VolumeTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, a := range attachments {
+			if a.Life() == Alive {
+				ops = append(ops, detachVolumeOps(a.Machine(), volume.VolumeTag())...)
+			}
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	filesystem, err := st.storageInstanceFilesystem(tag)
+	if err == nil && filesystem.LifeBinding() == tag && storageMachineScope(filesystem.doc.FilesystemId) == "" {
+		attachments, err := st.FilesystemAttachments(filesystem.FilesystemTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, a := range attachments {
+			if a.Life() == Alive {
+				ops = append(ops, detachFilesystemOps(a.Machine(), filesystem.FilesystemTag())...)
+			}
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	return ops, nil
+}
+
 // removeStorageInstancesOps returns the transaction operations to remove all
-// storage instances owned by the specified entity.
+// storage instances owned by the specified entity. Detached storage instances
+// are retained.
 func removeStorageInstancesOps(st *State, owner names.Tag) ([]txn.Op, error) {
 	coll, closer := st.getCollection(storageInstancesC)
 	defer closer()
 
+	notDetached := bson.D{{"detached", bson.D{{"$ne", true}}}}
+	query := append(bson.D{{"owner", owner.String()}}, notDetached...)
 	var docs []storageInstanceDoc
-	err := coll.Find(bson.D{{"owner", owner.String()}}).Select(bson.D{{"id", true}}).All(&docs)
+	err := coll.Find(query).Select(bson.D{{"id", true}}).All(&docs)
 	if err != nil {
 		return nil, errors.Annotatef(err, "cannot get storage instances for %s", owner)
 	}
@@ -731,6 +1273,7 @@ func removeStorageInstancesOps(st *State, owner names.Tag) ([]txn.Op, error) {
 		ops[i] = txn.Op{
 			C:      storageInstancesC,
 			Id:     doc.Id,
+			Assert: notDetached,
 			Remove: true,
 		}
 	}
@@ -1213,6 +1756,7 @@ func (st *State) countEntityStorageInstancesForName(
 		"$and", []bson.D{
 			bson.D{{"owner", tag.String()}},
 			bson.D{{"storagename", name}},
+			bson.D{{"detached", bson.D{{"$ne", true}}}},
 		},
 	}}
 	result, err := storageCollection.Find(criteria).Count()
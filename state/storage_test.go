@@ -918,6 +918,219 @@ func (s *StorageStateSuite) testStorageLocationConflict(c *gc.C, first, second,
 	}
 }
 
+func (s *StorageStateSuite) setupDetachedStorage(c *gc.C, pool string) (*state.Service, *state.Unit, *state.Machine, names.StorageTag) {
+	service, u, storageTag := s.setupSingleStorage(c, "block", pool)
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	return service, u, m, storageTag
+}
+
+func (s *StorageStateSuite) TestDetachStorageRetainsInstance(c *gc.C) {
+	_, u, _, storageTag := s.setupDetachedStorage(c, "environscoped-block")
+
+	// The storage instance is retained after its last attachment
+	// is removed, so that it may be attached to another unit.
+	exists := s.storageInstanceExists(c, storageTag)
+	c.Assert(exists, jc.IsTrue)
+	attachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+}
+
+func (s *StorageStateSuite) TestDetachStorageAttachmentDying(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	att, err := s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Dying)
+
+	// Detaching again is a no-op.
+	err = s.State.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageStateSuite) TestDetachStorageNotOwned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	u2, err := u.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := u2.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DetachStorage(storageTag, other.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot detach storage data/0 from unit storage-block/1: storage is not owned by unit storage-block/1`)
+}
+
+func (s *StorageStateSuite) TestAttachStorageNotDetached(c *gc.C) {
+	service, _, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot attach storage data/0 to unit storage-block/1: storage is not detached`)
+}
+
+func (s *StorageStateSuite) TestAttachStorageAnotherMachine(c *gc.C) {
+	service, _, m1, storageTag := s.setupDetachedStorage(c, "environscoped-block")
+
+	// The new unit must release its own "data" storage first,
+	// as the charm allows only one.
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m2, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.AssignToMachine(m2)
+	c.Assert(err, jc.ErrorIsNil)
+	u2StorageTag := names.NewStorageTag("data/1")
+	err = s.State.DetachStorage(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, u2.Tag())
+	att, err := s.State.StorageAttachment(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Alive)
+
+	// The volume is detached from the old machine, and
+	// attached to the new one.
+	volume := s.storageInstanceVolume(c, storageTag)
+	oldAttachment := s.volumeAttachment(c, m1.MachineTag(), volume.VolumeTag())
+	c.Assert(oldAttachment.Life(), gc.Equals, state.Dying)
+	newAttachment := s.volumeAttachment(c, m2.MachineTag(), volume.VolumeTag())
+	c.Assert(newAttachment.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageStateSuite) TestAttachStorageMachineScopedAnotherMachine(c *gc.C) {
+	service, _, _, storageTag := s.setupDetachedStorage(c, "loop-pool")
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m2, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.AssignToMachine(m2)
+	c.Assert(err, jc.ErrorIsNil)
+	u2StorageTag := names.NewStorageTag("data/1")
+	err = s.State.DetachStorage(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot attach storage data/0 to unit storage-block/1: volume "0/0" is scoped to machine 0, and cannot be attached to machine 1`)
+}
+
+func (s *StorageStateSuite) TestAttachStorageMachineScopedSameMachine(c *gc.C) {
+	service, _, m, storageTag := s.setupDetachedStorage(c, "loop-pool")
+
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	u2StorageTag := names.NewStorageTag("data/1")
+	err = s.State.DetachStorage(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StorageAttachment(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume is already attached to the machine.
+	volume := s.storageInstanceVolume(c, storageTag)
+	attachment := s.volumeAttachment(c, m.MachineTag(), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageStateSuite) createRetainPool(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("retain-block", "environscoped-block", map[string]interface{}{
+		"release-policy": "retain",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageStateSuite) TestStorageReleasePolicy(c *gc.C) {
+	s.createRetainPool(c)
+	_, _, storageTag := s.setupSingleStorage(c, "block", "retain-block")
+	policy, err := s.State.StorageReleasePolicy(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.Equals, poolmanager.ReleaseRetain)
+}
+
+func (s *StorageStateSuite) TestStorageReleasePolicyDefault(c *gc.C) {
+	_, _, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	policy, err := s.State.StorageReleasePolicy(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.Equals, poolmanager.ReleaseDestroy)
+}
+
+func (s *StorageStateSuite) TestRemoveStorageAttachmentRetainPolicy(c *gc.C) {
+	s.createRetainPool(c)
+	service, u, storageTag := s.setupSingleStorage(c, "block", "retain-block")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err = s.State.DestroyStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The storage instance is retained as detached storage, and its
+	// volume is detached from the machine rather than destroyed.
+	exists := s.storageInstanceExists(c, storageTag)
+	c.Assert(exists, jc.IsTrue)
+	volume = s.volume(c, volume.VolumeTag())
+	c.Assert(volume.Life(), gc.Equals, state.Alive)
+	attachment := s.volumeAttachment(c, names.NewMachineTag(machineId), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Dying)
+
+	// The retained storage may be attached to another unit.
+	u2, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u2, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	u2StorageTag := names.NewStorageTag("data/1")
+	err = s.State.DetachStorage(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(u2StorageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageStateSuite) TestRemoveStorageAttachmentDestroyPolicy(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "persistent-block")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err = s.State.DestroyStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	exists := s.storageInstanceExists(c, storageTag)
+	c.Assert(exists, jc.IsFalse)
+	volume = s.volume(c, volume.VolumeTag())
+	c.Assert(volume.Life(), gc.Equals, state.Dying)
+}
+
 // TODO(axw) the following require shared storage support to test:
 // - StorageAttachments can't be added to Dying StorageInstance
 // - StorageInstance without attachments is removed by Destroy
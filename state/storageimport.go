@@ -0,0 +1,364 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/storage"
+)
+
+// storageImportDoc records the provider storage from which a storage
+// instance was imported. The document ID is derived from the pool name
+// and the provider's ID for the storage, so that the same storage cannot
+// be imported into a pool twice, even by concurrent imports.
+type storageImportDoc struct {
+	DocID     string `bson:"_id"`
+	EnvUUID   string `bson:"env-uuid"`
+	StorageId string `bson:"storageid"`
+	Pool      string `bson:"pool"`
+
+	// Filesystem holds the information of an imported filesystem.
+	// Imported filesystems are recorded in state when the storage
+	// is first attached to a machine, so that filesystems from
+	// machine-scoped pools can be scoped to that machine.
+	Filesystem *FilesystemInfo `bson:"filesystem,omitempty"`
+}
+
+// storageImportId returns the ID of the storage import document for
+// the provider storage with the specified ID in the given pool.
+func storageImportId(pool, providerId string) string {
+	return pool + ":" + providerId
+}
+
+// ImportVolume records an existing, provider-managed volume in state,
+// creating a block storage instance with the specified storage name to
+// which the volume is bound. The volume is recorded as provisioned with
+// the supplied info, which must specify the volume's pool and provider
+// volume ID; the pool must be environment-scoped.
+//
+// The storage instance is owned by the environment, and created detached,
+// so that it may be attached to a unit with AttachStorage, or used by a
+// new unit whose storage constraints name the storage and pool. The volume
+// will then be attached to the unit's machine by the storage provisioner.
+func (st *State) ImportVolume(storageName string, info VolumeInfo) (_ names.StorageTag, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot import volume %q", info.VolumeId)
+	if info.VolumeId == "" {
+		return names.StorageTag{}, errors.New("volume ID not set")
+	}
+	if err := validateStorageImport(st, storageName, info.Pool, storage.StorageKindBlock); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	_, provider, err := poolStorageProvider(st, info.Pool)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	if provider.Scope() != storage.ScopeEnviron {
+		return names.StorageTag{}, errors.Errorf(
+			"pool %q is machine-scoped; only environment-scoped volumes can be imported",
+			info.Pool,
+		)
+	}
+
+	var storageTag names.StorageTag
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		tag, ops, err := st.importStorageOps(
+			storageName, StorageKindBlock,
+			storageImportDoc{Pool: info.Pool},
+			info.VolumeId,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// Volumes created by Juju are not recorded as imports.
+		existing, err := st.volumes(bson.D{
+			{"info.volumeid", info.VolumeId},
+			{"info.pool", info.Pool},
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(existing) > 0 {
+			return nil, errors.AlreadyExistsf("volume %q", existing[0].doc.Name)
+		}
+		name, err := newVolumeName(st, "")
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot generate volume name")
+		}
+		volumeInfo := info
+		storageTag = tag
+		return append(ops,
+			createStatusOp(st, volumeGlobalKey(name), statusDoc{
+				Status:  StatusDetached,
+				Updated: time.Now().UnixNano(),
+			}),
+			txn.Op{
+				C:      volumesC,
+				Id:     name,
+				Assert: txn.DocMissing,
+				Insert: &volumeDoc{
+					Name:      name,
+					StorageId: tag.Id(),
+					Binding:   tag.String(),
+					Info:      &volumeInfo,
+				},
+			},
+		), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return names.StorageTag{}, err
+	}
+	return storageTag, nil
+}
+
+// ImportFilesystem records an existing, provider-managed filesystem in
+// state, creating a filesystem storage instance with the specified storage
+// name. The supplied info must specify the filesystem's pool and provider
+// filesystem ID; the pool's provider must manage filesystems directly,
+// rather than by creating them on volumes.
+//
+// As with ImportVolume, the storage instance is owned by the environment
+// and created detached. The filesystem is recorded as provisioned when the
+// storage is first attached to a machine; filesystems from machine-scoped
+// pools, such as NFS, are scoped to that machine, and cannot then be moved
+// to another machine.
+func (st *State) ImportFilesystem(storageName string, info FilesystemInfo) (_ names.StorageTag, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot import filesystem %q", info.FilesystemId)
+	if info.FilesystemId == "" {
+		return names.StorageTag{}, errors.New("filesystem ID not set")
+	}
+	if err := validateStorageImport(st, storageName, info.Pool, storage.StorageKindFilesystem); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	_, provider, err := poolStorageProvider(st, info.Pool)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	if !provider.Supports(storage.StorageKindFilesystem) {
+		return names.StorageTag{}, errors.Errorf(
+			"pool %q creates filesystems on volumes; only provider-managed filesystems can be imported",
+			info.Pool,
+		)
+	}
+
+	var storageTag names.StorageTag
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		filesystemInfo := info
+		tag, ops, err := st.importStorageOps(
+			storageName, StorageKindFilesystem,
+			storageImportDoc{Pool: info.Pool, Filesystem: &filesystemInfo},
+			info.FilesystemId,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// Filesystems created by Juju are not recorded as imports.
+		existing, err := st.filesystems(bson.D{
+			{"info.filesystemid", info.FilesystemId},
+			{"info.pool", info.Pool},
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(existing) > 0 {
+			return nil, errors.AlreadyExistsf("filesystem %q", existing[0].doc.FilesystemId)
+		}
+		storageTag = tag
+		return ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return names.StorageTag{}, err
+	}
+	return storageTag, nil
+}
+
+// validateStorageImport validates the storage name and pool of
+// storage being imported.
+func validateStorageImport(st *State, storageName, pool string, kind storage.StorageKind) error {
+	if pool == "" {
+		return errors.New("pool name not set")
+	}
+	if !names.IsValidStorageName(storageName) {
+		return errors.NotValidf("storage name %q", storageName)
+	}
+	return validateStoragePool(st, pool, kind, nil)
+}
+
+// importStorageOps returns txn.Ops to create a detached storage instance
+// owned by the environment, and to record that it was imported from the
+// provider storage with the specified ID. The returned ops will abort if
+// the provider storage has already been imported into the pool.
+func (st *State) importStorageOps(
+	storageName string, kind StorageKind, doc storageImportDoc, providerId string,
+) (names.StorageTag, []txn.Op, error) {
+	importId := storageImportId(doc.Pool, providerId)
+	imports, closer := st.getCollection(storageImportsC)
+	defer closer()
+	var existing storageImportDoc
+	if err := imports.FindId(importId).One(&existing); err == nil {
+		what := "volume"
+		if kind == StorageKindFilesystem {
+			what = "filesystem"
+		}
+		return names.StorageTag{}, nil, errors.AlreadyExistsf(
+			"%s %q (imported as storage %q)", what, providerId, existing.StorageId,
+		)
+	} else if err != mgo.ErrNotFound {
+		return names.StorageTag{}, nil, errors.Annotate(err, "cannot get storage import details")
+	}
+
+	id, err := newStorageInstanceId(st, storageName)
+	if err != nil {
+		return names.StorageTag{}, nil, errors.Annotate(err, "cannot generate storage instance name")
+	}
+	doc.StorageId = id
+	return names.NewStorageTag(id), []txn.Op{{
+		C:      storageInstancesC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &storageInstanceDoc{
+			Id:          id,
+			Kind:        kind,
+			Owner:       st.EnvironTag().String(),
+			StorageName: storageName,
+			Detached:    true,
+		},
+	}, {
+		C:      storageImportsC,
+		Id:     importId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}, nil
+}
+
+// storageInstanceImport returns the details of the import of the
+// specified storage instance, or a NotFound error if the storage
+// was not imported.
+func (st *State) storageInstanceImport(tag names.StorageTag) (*storageImportDoc, error) {
+	imports, closer := st.getCollection(storageImportsC)
+	defer closer()
+	var doc storageImportDoc
+	err := imports.Find(bson.D{{"storageid", tag.Id()}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("import of storage %q", tag.Id())
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get storage import details")
+	}
+	return &doc, nil
+}
+
+// removeStorageImportOps returns txn.Ops to remove the record of the
+// import of the specified storage instance, if any, so that the
+// provider storage may be imported again.
+func removeStorageImportOps(st *State, tag names.StorageTag) ([]txn.Op, error) {
+	doc, err := st.storageInstanceImport(tag)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      storageImportsC,
+		Id:     doc.DocID,
+		Remove: true,
+	}}, nil
+}
+
+// importedFilesystemParams returns parameters for recording the imported
+// filesystem of the specified storage instance, or a NotFound error if
+// the storage was not imported from a filesystem.
+func (st *State) importedFilesystemParams(tag names.StorageTag) (FilesystemParams, error) {
+	doc, err := st.storageInstanceImport(tag)
+	if err != nil {
+		return FilesystemParams{}, errors.Trace(err)
+	}
+	if doc.Filesystem == nil {
+		return FilesystemParams{}, errors.NotFoundf("imported filesystem for storage %q", tag.Id())
+	}
+	info := *doc.Filesystem
+	return FilesystemParams{
+		storage: tag,
+		binding: tag,
+		info:    &info,
+		Pool:    info.Pool,
+		Size:    info.Size,
+	}, nil
+}
+
+// importedStorageInstances returns the detached storage instances that
+// were imported into the specified pool with the given storage name and
+// kind, and have not yet been attached to a unit, ordered by ID.
+func (st *State) importedStorageInstances(storageName string, kind StorageKind, pool string) ([]*storageInstance, error) {
+	imports, closer := st.getCollection(storageImportsC)
+	defer closer()
+	var importDocs []storageImportDoc
+	if err := imports.Find(bson.D{{"pool", pool}}).All(&importDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot get storage import details")
+	}
+	if len(importDocs) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(importDocs))
+	for i, doc := range importDocs {
+		ids[i] = doc.StorageId
+	}
+
+	storageInstances, closer := st.getCollection(storageInstancesC)
+	defer closer()
+	var docs []storageInstanceDoc
+	query := append(bson.D{
+		{"id", bson.D{{"$in", ids}}},
+		{"storagename", storageName},
+		{"storagekind", kind},
+		{"owner", st.EnvironTag().String()},
+		{"detached", true},
+		{"attachmentcount", 0},
+	}, isAliveDoc...)
+	if err := storageInstances.Find(query).Sort("id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get storage instances")
+	}
+	result := make([]*storageInstance, len(docs))
+	for i, doc := range docs {
+		result[i] = &storageInstance{st, doc}
+	}
+	return result, nil
+}
+
+// adoptImportedStorageOps returns txn.Ops to transfer ownership of the
+// imported storage instance to the specified unit, and to attach the
+// storage to the unit. The storage instance document is updated to
+// reflect the changes.
+func adoptImportedStorageOps(doc *storageInstanceDoc, unit names.UnitTag, curl *charm.URL) []txn.Op {
+	assert := append(bson.D{
+		{"owner", doc.Owner},
+		{"detached", true},
+		{"attachmentcount", 0},
+	}, isAliveDoc...)
+	doc.Owner = unit.String()
+	doc.AttachmentCount = 1
+	doc.Detached = false
+	doc.CharmURL = curl
+	return []txn.Op{{
+		C:      storageInstancesC,
+		Id:     doc.Id,
+		Assert: assert,
+		Update: bson.D{
+			{"$set", bson.D{
+				{"owner", doc.Owner},
+				{"attachmentcount", 1},
+				{"charmurl", curl},
+			}},
+			{"$unset", bson.D{{"detached", nil}}},
+		},
+	},
+		createStorageAttachmentOp(names.NewStorageTag(doc.Id), unit),
+	}
+}
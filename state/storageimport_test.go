@@ -0,0 +1,269 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type StorageImportSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageImportSuite{})
+
+func (s *StorageImportSuite) TestImportVolume(c *gc.C) {
+	storageTag, err := s.State.ImportVolume("data", state.VolumeInfo{
+		VolumeId: "vol-123", Pool: "persistent-block", Size: 1024, Persistent: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageTag, gc.Equals, names.NewStorageTag("data/0"))
+
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Kind(), gc.Equals, state.StorageKindBlock)
+	c.Assert(si.StorageName(), gc.Equals, "data")
+	c.Assert(si.Owner(), gc.Equals, names.Tag(s.State.EnvironTag()))
+
+	volume := s.storageInstanceVolume(c, storageTag)
+	c.Assert(volume.VolumeTag(), gc.Equals, names.NewVolumeTag("0"))
+	c.Assert(volume.LifeBinding(), gc.Equals, names.Tag(storageTag))
+	_, ok := volume.Params()
+	c.Assert(ok, jc.IsFalse)
+	s.assertVolumeInfo(c, volume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "vol-123", Pool: "persistent-block", Size: 1024, Persistent: true,
+	})
+	status, err := volume.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusDetached)
+}
+
+func (s *StorageImportSuite) TestImportVolumeAlreadyImported(c *gc.C) {
+	info := state.VolumeInfo{VolumeId: "vol-123", Pool: "persistent-block", Size: 1024}
+	_, err := s.State.ImportVolume("data", info)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ImportVolume("data", info)
+	c.Assert(err, gc.ErrorMatches, `cannot import volume "vol-123": volume "vol-123" \(imported as storage "data/0"\) already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *StorageImportSuite) TestImportVolumeConcurrently(c *gc.C) {
+	info := state.VolumeInfo{VolumeId: "vol-123", Pool: "persistent-block", Size: 1024}
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.State.ImportVolume("data", info)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+	_, err := s.State.ImportVolume("data", info)
+	c.Assert(err, gc.ErrorMatches, `cannot import volume "vol-123": volume "vol-123" \(imported as storage "data/1"\) already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+
+	volumes, err := s.State.AllVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, gc.HasLen, 1)
+}
+
+func (s *StorageImportSuite) TestImportVolumeMachineScoped(c *gc.C) {
+	_, err := s.State.ImportVolume("data", state.VolumeInfo{VolumeId: "vol-123", Pool: "loop-pool"})
+	c.Assert(err, gc.ErrorMatches, `cannot import volume "vol-123": pool "loop-pool" is machine-scoped; only environment-scoped volumes can be imported`)
+}
+
+func (s *StorageImportSuite) TestImportVolumeInvalidArgs(c *gc.C) {
+	_, err := s.State.ImportVolume("data", state.VolumeInfo{Pool: "persistent-block"})
+	c.Assert(err, gc.ErrorMatches, `cannot import volume "": volume ID not set`)
+	_, err = s.State.ImportVolume("data", state.VolumeInfo{VolumeId: "vol-123"})
+	c.Assert(err, gc.ErrorMatches, `cannot import volume "vol-123": pool name not set`)
+	_, err = s.State.ImportVolume("Data!", state.VolumeInfo{VolumeId: "vol-123", Pool: "persistent-block"})
+	c.Assert(err, gc.ErrorMatches, `cannot import volume "vol-123": storage name "Data!" not valid`)
+	_, err = s.State.ImportVolume("data", state.VolumeInfo{VolumeId: "vol-123", Pool: "nope"})
+	c.Assert(err, gc.ErrorMatches, `cannot import volume "vol-123": pool "nope" not found`)
+}
+
+func (s *StorageImportSuite) TestAttachImportedStorage(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "environscoped-block")
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The charm allows only one "data" storage instance,
+	// so the unit must release its own first.
+	err = s.State.DestroyStorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DestroyStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	imported, err := s.State.ImportVolume("data", state.VolumeInfo{
+		VolumeId: "vol-123", Pool: "persistent-block", Size: 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(imported, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	si, err := s.State.StorageInstance(imported)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, u.Tag())
+
+	// The volume is already provisioned, so the storage
+	// provisioner need only attach it to the machine.
+	volume := s.storageInstanceVolume(c, imported)
+	attachment := s.volumeAttachment(c, m.MachineTag(), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Alive)
+	_, ok := attachment.Params()
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *StorageImportSuite) TestAddUnitUsesImportedVolume(c *gc.C) {
+	imported, err := s.State.ImportVolume("data", state.VolumeInfo{
+		VolumeId: "vol-123", Pool: "persistent-block", Size: 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, u, _ := s.setupSingleStorage(c, "block", "persistent-block")
+
+	attachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	c.Assert(attachments[0].StorageInstance(), gc.Equals, imported)
+	si, err := s.State.StorageInstance(imported)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, u.Tag())
+
+	err = s.State.AssignUnit(u, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The imported volume is attached to the unit's machine,
+	// and no new volume is created.
+	volume := s.storageInstanceVolume(c, imported)
+	attachment := s.volumeAttachment(c, names.NewMachineTag(machineId), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Alive)
+	volumes, err := s.State.AllVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, gc.HasLen, 1)
+}
+
+func (s *StorageImportSuite) TestAddUnitIgnoresStorageImportedIntoOtherPool(c *gc.C) {
+	imported, err := s.State.ImportVolume("data", state.VolumeInfo{
+		VolumeId: "vol-123", Pool: "persistent-block", Size: 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, u, _ := s.setupSingleStorage(c, "block", "environscoped-block")
+
+	attachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	c.Assert(attachments[0].StorageInstance(), gc.Not(gc.Equals), imported)
+	si, err := s.State.StorageInstance(imported)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Owner(), gc.Equals, names.Tag(s.State.EnvironTag()))
+}
+
+func (s *StorageImportSuite) TestImportFilesystem(c *gc.C) {
+	storageTag, err := s.State.ImportFilesystem("data", state.FilesystemInfo{
+		FilesystemId: "server:/export/data", Pool: "machinescoped",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageTag, gc.Equals, names.NewStorageTag("data/0"))
+
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Kind(), gc.Equals, state.StorageKindFilesystem)
+	c.Assert(si.StorageName(), gc.Equals, "data")
+	c.Assert(si.Owner(), gc.Equals, names.Tag(s.State.EnvironTag()))
+
+	// The filesystem is not recorded until the storage is
+	// attached, when its scope is known.
+	_, err = s.State.StorageInstanceFilesystem(storageTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageImportSuite) TestImportFilesystemAlreadyImported(c *gc.C) {
+	info := state.FilesystemInfo{FilesystemId: "server:/export/data", Pool: "machinescoped"}
+	_, err := s.State.ImportFilesystem("data", info)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ImportFilesystem("data", info)
+	c.Assert(err, gc.ErrorMatches, `cannot import filesystem "server:/export/data": filesystem "server:/export/data" \(imported as storage "data/0"\) already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *StorageImportSuite) TestImportFilesystemAfterRemoval(c *gc.C) {
+	info := state.FilesystemInfo{FilesystemId: "server:/export/data", Pool: "machinescoped"}
+	storageTag, err := s.State.ImportFilesystem("data", info)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DestroyStorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.ImportFilesystem("data", info)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageImportSuite) TestImportFilesystemVolumeBacked(c *gc.C) {
+	_, err := s.State.ImportFilesystem("data", state.FilesystemInfo{
+		FilesystemId: "fs-123", Pool: "persistent-block",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot import filesystem "fs-123": pool "persistent-block" creates filesystems on volumes; only provider-managed filesystems can be imported`)
+}
+
+func (s *StorageImportSuite) TestAttachImportedFilesystem(c *gc.C) {
+	_, u, _ := s.setupSingleStorage(c, "filesystem", "machinescoped")
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	imported, err := s.State.ImportFilesystem("data", state.FilesystemInfo{
+		FilesystemId: "server:/export/data", Pool: "machinescoped",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AttachStorage(imported, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The filesystem is recorded as provisioned, and scoped
+	// to the machine that it is attached to.
+	filesystem := s.storageInstanceFilesystem(c, imported)
+	c.Assert(filesystem.FilesystemTag().Id(), jc.HasPrefix, m.Id()+"/")
+	c.Assert(filesystem.LifeBinding(), gc.Equals, names.Tag(imported))
+	s.assertFilesystemInfo(c, filesystem.FilesystemTag(), state.FilesystemInfo{
+		FilesystemId: "server:/export/data", Pool: "machinescoped",
+	})
+	attachment := s.filesystemAttachment(c, m.MachineTag(), filesystem.FilesystemTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageImportSuite) TestAddUnitUsesImportedFilesystem(c *gc.C) {
+	imported, err := s.State.ImportFilesystem("data", state.FilesystemInfo{
+		FilesystemId: "server:/export/data", Pool: "machinescoped",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, u, _ := s.setupSingleStorage(c, "filesystem", "machinescoped")
+
+	attachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	c.Assert(attachments[0].StorageInstance(), gc.Equals, imported)
+
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	filesystem := s.storageInstanceFilesystem(c, imported)
+	c.Assert(filesystem.FilesystemTag().Id(), jc.HasPrefix, m.Id()+"/")
+	_, ok := filesystem.Params()
+	c.Assert(ok, jc.IsFalse)
+	s.assertFilesystemInfo(c, filesystem.FilesystemTag(), state.FilesystemInfo{
+		FilesystemId: "server:/export/data", Pool: "machinescoped",
+	})
+	attachment := s.filesystemAttachment(c, m.MachineTag(), filesystem.FilesystemTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Alive)
+}
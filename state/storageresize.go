@@ -0,0 +1,283 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// storageResizeDoc records a pending request to resize a volume or
+// filesystem. The document's ID is the same as that of the volume or
+// filesystem being resized, so that requests are scoped to machines
+// in the same way as the storage entities themselves.
+type storageResizeDoc struct {
+	DocID   string `bson:"_id"`
+	Id      string `bson:"id"`
+	EnvUUID string `bson:"env-uuid"`
+	Size    uint64 `bson:"size"`
+}
+
+func (st *State) pendingResize(collection, id, description string) (uint64, error) {
+	coll, cleanup := st.getCollection(collection)
+	defer cleanup()
+
+	var doc storageResizeDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, errors.NotFoundf("pending resize of %s", description)
+	} else if err != nil {
+		return 0, errors.Annotatef(err, "cannot get pending resize of %s", description)
+	}
+	return doc.Size, nil
+}
+
+// ResizeVolume records a request to grow the specified volume to the
+// given size, in MiB. The volume will be resized by the storage
+// provisioner responsible for it. The volume must be alive and
+// provisioned, and there must not already be a resize pending.
+func (st *State) ResizeVolume(tag names.VolumeTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize volume %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.New("volume is not alive")
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if size <= info.Size {
+			return nil, errors.Errorf(
+				"new size %dMiB must be greater than current size %dMiB",
+				size, info.Size,
+			)
+		}
+		if _, err := st.PendingVolumeResize(tag); err == nil {
+			return nil, errors.AlreadyExistsf("pending resize of volume %q", tag.Id())
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: append(bson.D{{"info", bson.D{{"$exists", true}}}}, isAliveDoc...),
+		}, {
+			C:      volumeResizesC,
+			Id:     tag.Id(),
+			Assert: txn.DocMissing,
+			Insert: &storageResizeDoc{Id: tag.Id(), Size: size},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// PendingVolumeResize returns the size, in MiB, that the specified
+// volume is to be resized to, or a NotFound error if there is no
+// resize pending.
+func (st *State) PendingVolumeResize(tag names.VolumeTag) (uint64, error) {
+	return st.pendingResize(volumeResizesC, tag.Id(), fmt.Sprintf("volume %q", tag.Id()))
+}
+
+// SetVolumeResized records that the specified volume has been resized
+// to the given size, in MiB, and removes the pending resize request.
+// If the volume backs a provisioned filesystem, a request to resize
+// the filesystem to fill the volume is recorded.
+func (st *State) SetVolumeResized(tag names.VolumeTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set resized volume %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.PendingVolumeResize(tag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		v, err := st.volumeByTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.doc.Info == nil {
+			return nil, errors.NotProvisionedf("volume %q", tag.Id())
+		}
+		ops := []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$set", bson.D{{"info.size", size}}}},
+		}, {
+			C:      volumeResizesC,
+			Id:     tag.Id(),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		f, err := st.volumeFilesystem(tag)
+		if errors.IsNotFound(err) {
+			return ops, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if f.doc.Info == nil || f.doc.Life != Alive {
+			return ops, nil
+		}
+		if _, err := st.PendingFilesystemResize(f.FilesystemTag()); err == nil {
+			return ops, nil
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      filesystemResizesC,
+			Id:     f.doc.FilesystemId,
+			Assert: txn.DocMissing,
+			Insert: &storageResizeDoc{Id: f.doc.FilesystemId, Size: size},
+		}), nil
+	}
+	return st.run(buildTxn)
+}
+
+// FailVolumeResize removes the pending resize request for the specified
+// volume, and sets the volume's status to record the reason for the
+// failure.
+func (st *State) FailVolumeResize(tag names.VolumeTag, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot fail resize of volume %q", tag.Id())
+	if message == "" {
+		return errors.New("error message not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.PendingVolumeResize(tag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      volumeResizesC,
+			Id:     tag.Id(),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return err
+	}
+	return st.SetVolumeStatus(tag, StatusError, "resizing volume: "+message, nil)
+}
+
+// ResizeFilesystem records a request to grow the specified filesystem to
+// the given size, in MiB. The filesystem will be resized by the storage
+// provisioner responsible for it. The filesystem must be alive and
+// provisioned, must not be backed by a volume, and there must not
+// already be a resize pending. Volume-backed filesystems are resized
+// by resizing their volume.
+func (st *State) ResizeFilesystem(tag names.FilesystemTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize filesystem %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		f, err := st.filesystemByTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if f.Life() != Alive {
+			return nil, errors.New("filesystem is not alive")
+		}
+		if f.doc.VolumeId != "" {
+			return nil, errors.Errorf(
+				"filesystem is backed by volume %q; resize the volume instead",
+				f.doc.VolumeId,
+			)
+		}
+		info, err := f.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if size <= info.Size {
+			return nil, errors.Errorf(
+				"new size %dMiB must be greater than current size %dMiB",
+				size, info.Size,
+			)
+		}
+		if _, err := st.PendingFilesystemResize(tag); err == nil {
+			return nil, errors.AlreadyExistsf("pending resize of filesystem %q", tag.Id())
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: append(bson.D{{"info", bson.D{{"$exists", true}}}}, isAliveDoc...),
+		}, {
+			C:      filesystemResizesC,
+			Id:     tag.Id(),
+			Assert: txn.DocMissing,
+			Insert: &storageResizeDoc{Id: tag.Id(), Size: size},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// PendingFilesystemResize returns the size, in MiB, that the specified
+// filesystem is to be resized to, or a NotFound error if there is no
+// resize pending.
+func (st *State) PendingFilesystemResize(tag names.FilesystemTag) (uint64, error) {
+	return st.pendingResize(filesystemResizesC, tag.Id(), fmt.Sprintf("filesystem %q", tag.Id()))
+}
+
+// SetFilesystemResized records that the specified filesystem has been
+// resized to the given size, in MiB, and removes the pending resize
+// request.
+func (st *State) SetFilesystemResized(tag names.FilesystemTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set resized filesystem %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.PendingFilesystemResize(tag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$set", bson.D{{"info.size", size}}}},
+		}, {
+			C:      filesystemResizesC,
+			Id:     tag.Id(),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// FailFilesystemResize removes the pending resize request for the
+// specified filesystem, and sets the filesystem's status to record
+// the reason for the failure.
+func (st *State) FailFilesystemResize(tag names.FilesystemTag, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot fail resize of filesystem %q", tag.Id())
+	if message == "" {
+		return errors.New("error message not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.PendingFilesystemResize(tag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      filesystemResizesC,
+			Id:     tag.Id(),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return err
+	}
+	return st.SetFilesystemStatus(tag, StatusError, "resizing filesystem: "+message, nil)
+}
+
+// removeResizeOp returns an operation that removes any pending resize
+// request for the volume or filesystem with the specified ID.
+func removeResizeOp(collection, id string) txn.Op {
+	return txn.Op{
+		C:      collection,
+		Id:     id,
+		Remove: true,
+	}
+}
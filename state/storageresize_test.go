@@ -0,0 +1,250 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageResizeSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageResizeSuite{})
+
+func (s *StorageResizeSuite) assignUnit(c *gc.C, u *state.Unit) *state.Machine {
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *StorageResizeSuite) addProvisionedVolume(c *gc.C) (*state.Machine, names.VolumeTag) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	m := s.assignUnit(c, u)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err := s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+	return m, volumeTag
+}
+
+func (s *StorageResizeSuite) addProvisionedFilesystem(c *gc.C) (*state.Machine, names.FilesystemTag) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "machinescoped")
+	m := s.assignUnit(c, u)
+	filesystemTag := s.storageInstanceFilesystem(c, storageTag).FilesystemTag()
+	err := s.State.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{Size: 1024, FilesystemId: "fs-id"})
+	c.Assert(err, jc.ErrorIsNil)
+	return m, filesystemTag
+}
+
+func (s *StorageResizeSuite) TestResizeVolume(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+
+	_, err := s.State.PendingVolumeResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	size, err := s.State.PendingVolumeResize(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	err = s.State.ResizeVolume(volumeTag, 4096)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": pending resize of volume "0/0" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *StorageResizeSuite) TestResizeVolumeShrink(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	err := s.State.ResizeVolume(volumeTag, 1024)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": new size 1024MiB must be greater than current size 1024MiB`)
+}
+
+func (s *StorageResizeSuite) TestResizeVolumeUnprovisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	s.assignUnit(c, u)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err := s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": volume "0/0" not provisioned`)
+}
+
+func (s *StorageResizeSuite) TestSetVolumeResized(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	err := s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetVolumeResized(volumeTag, 2050)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeInfo(c, volumeTag, state.VolumeInfo{
+		Size: 2050, VolumeId: "vol-ume", Pool: "loop-pool",
+	})
+	_, err = s.State.PendingVolumeResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetVolumeResized(volumeTag, 2050)
+	c.Assert(err, gc.ErrorMatches, `cannot set resized volume "0/0": pending resize of volume "0/0" not found`)
+}
+
+func (s *StorageResizeSuite) TestSetVolumeResizedResizesFilesystem(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "loop-pool")
+	m := s.assignUnit(c, u)
+	filesystem := s.storageInstanceFilesystem(c, storageTag)
+	volume := s.filesystemVolume(c, filesystem.FilesystemTag())
+	err := s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeAttachmentInfo(
+		m.MachineTag(), volume.VolumeTag(), state.VolumeAttachmentInfo{DeviceName: "loop0"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetFilesystemInfo(filesystem.FilesystemTag(), state.FilesystemInfo{Size: 1000, FilesystemId: "fs-id"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResizeFilesystem(filesystem.FilesystemTag(), 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize filesystem "0/0": filesystem is backed by volume "0/0"; resize the volume instead`)
+
+	err = s.State.ResizeVolume(volume.VolumeTag(), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeResized(volume.VolumeTag(), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	size, err := s.State.PendingFilesystemResize(filesystem.FilesystemTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(2048))
+}
+
+func (s *StorageResizeSuite) TestFailVolumeResize(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	err := s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.FailVolumeResize(volumeTag, "no space")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.PendingVolumeResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertVolumeInfo(c, volumeTag, state.VolumeInfo{
+		Size: 1024, VolumeId: "vol-ume", Pool: "loop-pool",
+	})
+
+	status, err := s.State.VolumeStatus(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusError)
+	c.Assert(status.Message, gc.Equals, "resizing volume: no space")
+}
+
+func (s *StorageResizeSuite) TestRemoveVolumeRemovesResize(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	err := s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.obliterateVolume(c, volumeTag)
+	_, err = s.State.PendingVolumeResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageResizeSuite) TestResizeFilesystem(c *gc.C) {
+	_, filesystemTag := s.addProvisionedFilesystem(c)
+
+	err := s.State.ResizeFilesystem(filesystemTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	size, err := s.State.PendingFilesystemResize(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	err = s.State.SetFilesystemResized(filesystemTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemInfo(c, filesystemTag, state.FilesystemInfo{
+		Size: 2048, FilesystemId: "fs-id", Pool: "machinescoped",
+	})
+	_, err = s.State.PendingFilesystemResize(filesystemTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageResizeSuite) TestFailFilesystemResize(c *gc.C) {
+	_, filesystemTag := s.addProvisionedFilesystem(c)
+	err := s.State.ResizeFilesystem(filesystemTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.FailFilesystemResize(filesystemTag, "no space")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.PendingFilesystemResize(filesystemTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	status, err := s.State.FilesystemStatus(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusError)
+	c.Assert(status.Message, gc.Equals, "resizing filesystem: no space")
+}
+
+func (s *StorageResizeSuite) TestWatchMachineVolumeResizes(c *gc.C) {
+	m, volumeTag := s.addProvisionedVolume(c)
+
+	w := s.State.WatchMachineVolumeResizes(m.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	err := s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	err = s.State.SetVolumeResized(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	ew := s.State.WatchEnvironVolumeResizes()
+	defer testing.AssertStop(c, ew)
+	ewc := testing.NewStringsWatcherC(c, s.State, ew)
+	ewc.AssertChangeInSingleEvent() // initial, machine-scoped excluded
+	ewc.AssertNoChange()
+}
+
+func (s *StorageResizeSuite) TestWatchMachineFilesystemResizes(c *gc.C) {
+	m, filesystemTag := s.addProvisionedFilesystem(c)
+
+	w := s.State.WatchMachineFilesystemResizes(m.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	err := s.State.ResizeFilesystem(filesystemTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	ew := s.State.WatchEnvironFilesystemResizes()
+	defer testing.AssertStop(c, ew)
+	ewc := testing.NewStringsWatcherC(c, s.State, ew)
+	ewc.AssertChangeInSingleEvent() // initial, machine-scoped excluded
+	ewc.AssertNoChange()
+}
+
+func (s *StorageResizeSuite) TestWatchVolume(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	err := s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchVolume(volumeTag)
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange() // initial
+	wc.AssertNoChange()
+
+	err = s.State.SetVolumeResized(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	wc.AssertNoChange()
+}
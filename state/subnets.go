@@ -244,8 +244,14 @@ func (s *Subnet) Refresh() error {
 // The address starts with AddressStateUnknown, for later allocation.
 // This will fail if the subnet is not alive.
 func (s *Subnet) PickNewAddress() (*IPAddress, error) {
+	return s.pickNewAddress(AddressStateUnknown)
+}
+
+// pickNewAddress returns a new IPAddress that isn't in use for the
+// subnet, added with the given state.
+func (s *Subnet) pickNewAddress(addrState AddressState) (*IPAddress, error) {
 	for {
-		addr, err := s.attemptToPickNewAddress()
+		addr, err := s.attemptToPickNewAddress(addrState)
 		if err == nil {
 			return addr, err
 		}
@@ -255,12 +261,45 @@ func (s *Subnet) PickNewAddress() (*IPAddress, error) {
 	}
 }
 
-// attemptToPickNewAddress will try to pick a new address. It can fail
+// ReserveAddress marks an address in the subnet as reserved, so that
+// it is never picked for allocation. If value is empty, a new address
+// is picked from the subnet's allocatable range; otherwise value must
+// be an address within the subnet CIDR which is not already in use.
+func (s *Subnet) ReserveAddress(value string) (_ *IPAddress, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot reserve address in subnet %q", s)
+
+	if s.doc.Life != Alive {
+		return nil, errors.Errorf("subnet is not alive")
+	}
+	// The address is added as reserved straight away, so that it can
+	// never be seen, or picked for allocation, in any other state.
+	var addr *IPAddress
+	if value == "" {
+		addr, err = s.pickNewAddress(AddressStateReserved)
+	} else {
+		_, ipNet, parseErr := net.ParseCIDR(s.doc.CIDR)
+		if parseErr != nil {
+			return nil, errors.Trace(parseErr)
+		}
+		ip := net.ParseIP(value)
+		if ip == nil || !ipNet.Contains(ip) {
+			return nil, errors.NotValidf("address %q for subnet %q", value, s)
+		}
+		addr, err = addIPAddress(s.st, network.NewAddress(value), s.ID(), AddressStateReserved)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return addr, nil
+}
+
+// attemptToPickNewAddress will try to pick a new address, adding it
+// with the given state. It can fail
 // with AlreadyExists due to a race condition between fetching the
 // list of addresses already in use and allocating a new one. If the
 // subnet is not alive, it will also fail. It is called in a loop by
 // PickNewAddress until it gets one or there are no more available!
-func (s *Subnet) attemptToPickNewAddress() (*IPAddress, error) {
+func (s *Subnet) attemptToPickNewAddress(addrState AddressState) (*IPAddress, error) {
 	if s.doc.Life != Alive {
 		return nil, errors.Errorf("cannot pick address: subnet %q is not alive", s)
 	}
@@ -320,7 +359,7 @@ func (s *Subnet) attemptToPickNewAddress() (*IPAddress, error) {
 	newAddr := network.NewAddress(newIP.String())
 
 	// and create a new IPAddress from it and return it
-	return s.st.AddIPAddress(newAddr, s.ID())
+	return addIPAddress(s.st, newAddr, s.ID(), addrState)
 }
 
 // pickAddress will pick a number, representing an IPv4 address, between low
@@ -357,3 +357,43 @@ func (s *SubnetSuite) TestPickNewAddressRace(c *gc.C) {
 	expected := []string{"192.168.1.0", "192.168.1.1"}
 	c.Assert(ipAddresses, jc.DeepEquals, expected)
 }
+
+func (s *SubnetSuite) TestReserveAddressPicksNew(c *gc.C) {
+	subnet := s.getSubnetForAddressPicking(c, "192.168.1.1")
+	_, err := s.State.AddIPAddress(network.NewAddress("192.168.1.0"), subnet.ID())
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err := subnet.ReserveAddress("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value(), gc.Equals, "192.168.1.1")
+	c.Assert(addr.State(), gc.Equals, state.AddressStateReserved)
+
+	// Reserved addresses are never picked again.
+	_, err = subnet.PickNewAddress()
+	c.Assert(err, gc.ErrorMatches, "allocatable IP addresses exhausted for subnet .*")
+}
+
+func (s *SubnetSuite) TestReserveAddressGivenValue(c *gc.C) {
+	subnet := s.getSubnetForAddressPicking(c, "192.168.1.1")
+
+	addr, err := subnet.ReserveAddress("192.168.1.42")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value(), gc.Equals, "192.168.1.42")
+	c.Assert(addr.SubnetId(), gc.Equals, subnet.ID())
+
+	addr, err = s.State.IPAddress("192.168.1.42")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.State(), gc.Equals, state.AddressStateReserved)
+
+	_, err = subnet.ReserveAddress("192.168.1.42")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *SubnetSuite) TestReserveAddressOutsideSubnet(c *gc.C) {
+	subnet := s.getSubnetForAddressPicking(c, "192.168.1.1")
+
+	_, err := subnet.ReserveAddress("10.0.0.1")
+	c.Assert(err, gc.ErrorMatches,
+		`cannot reserve address in subnet "192.168.1.0/24": address "10.0.0.1" for subnet "192.168.1.0/24" not valid`,
+	)
+}
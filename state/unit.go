@@ -128,9 +128,9 @@ func (u *Unit) Service() (*Service, error) {
 }
 
 // ConfigSettings returns the complete set of service charm config settings
-// available to the unit. Unset values will be replaced with the default
-// value for the associated option, and may thus be nil when no default is
-// specified.
+// available to the unit. Unset values will be replaced with the value from
+// the service's config profile, if any, or else with the default value for
+// the associated option, and may thus be nil when no default is specified.
 func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	if u.doc.CharmURL == nil {
 		return nil, fmt.Errorf("unit charm not set")
@@ -143,7 +143,14 @@ func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	if err != nil {
 		return nil, err
 	}
+	profileSettings, err := serviceConfigProfileSettings(u.st, u.doc.Service, chrm)
+	if err != nil {
+		return nil, err
+	}
 	result := chrm.Config().DefaultSettings()
+	for name, value := range profileSettings {
+		result[name] = value
+	}
 	for name, value := range settings.Map() {
 		result[name] = value
 	}
@@ -1109,6 +1116,7 @@ func (u *Unit) AssignedMachineId() (id string, err error) {
 var (
 	machineNotAliveErr = stderrors.New("machine is not alive")
 	machineNotCleanErr = stderrors.New("machine is dirty")
+	machineCordonedErr = stderrors.New("machine is cordoned")
 	unitNotAliveErr    = stderrors.New("unit is not alive")
 	alreadyAssignedErr = stderrors.New("unit is already assigned to a machine")
 	inUseErr           = stderrors.New("machine is not unused")
@@ -1118,6 +1126,7 @@ var (
 // also used by AssignToUnusedMachine. It returns specific errors
 // in some cases:
 // - machineNotAliveErr when the machine is not alive.
+// - machineCordonedErr when the machine is cordoned.
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
@@ -1149,6 +1158,9 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if m.Life() != Alive {
 		return nil, machineNotAliveErr
 	}
+	if m.doc.Cordoned {
+		return nil, machineCordonedErr
+	}
 	if u.doc.Series != m.doc.Series {
 		return nil, fmt.Errorf("series does not match")
 	}
@@ -1209,7 +1221,7 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 			{{"machineid", m.Id()}},
 		}},
 	}...)
-	massert := isAliveDoc
+	massert := append(isAliveDoc, notCordonedDoc...)
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
@@ -1363,7 +1375,7 @@ func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, con
 		ops = append(ops, txn.Op{
 			C:      machinesC,
 			Id:     parentDocId,
-			Assert: bson.D{{"clean", true}},
+			Assert: append(bson.D{{"clean", true}}, notCordonedDoc...),
 		}, txn.Op{
 			C:      containerRefsC,
 			Id:     parentDocId,
@@ -1412,6 +1424,9 @@ func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, con
 	if err != nil {
 		return err
 	}
+	if m.Cordoned() {
+		return machineCordonedErr
+	}
 	if !m.Clean() {
 		return machineNotCleanErr
 	}
@@ -1490,9 +1505,9 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 		RequestedNetworks: requestedNetworks,
 	}
 	err = u.assignToNewMachine(template, host.Id, *cons.Container)
-	if err == machineNotCleanErr {
-		// The clean machine was used before we got a chance to use it so just
-		// stick the unit on a new machine.
+	if err == machineNotCleanErr || err == machineCordonedErr {
+		// The clean machine was used or cordoned before we got a chance
+		// to use it so just stick the unit on a new machine.
 		return u.AssignToNewMachine()
 	}
 	return err
@@ -1629,7 +1644,16 @@ func machineStorageParamsForStorageInstance(
 		volumeAttachmentParams := VolumeAttachmentParams{
 			charmStorage.ReadOnly,
 		}
-		if unit == storage.Owner() {
+		volume, err := st.StorageInstanceVolume(storage.StorageTag())
+		switch {
+		case err == nil:
+			// The storage instance is owned by the service, or was
+			// imported, so there is a volume already, for which we
+			// will just add an attachment.
+			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
+		case !errors.IsNotFound(err) || unit != storage.Owner():
+			return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
+		default:
 			// The storage instance is owned by the unit, so we'll need
 			// to create a volume.
 			cons := allCons[storage.StorageName()]
@@ -1642,15 +1666,6 @@ func machineStorageParamsForStorageInstance(
 			volumes = append(volumes, MachineVolumeParams{
 				volumeParams, volumeAttachmentParams,
 			})
-		} else {
-			// The storage instance is owned by the service, so there
-			// should be a (shared) volume already, for which we will
-			// just add an attachment.
-			volume, err := st.StorageInstanceVolume(storage.StorageTag())
-			if err != nil {
-				return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
-			}
-			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
 		}
 	case StorageKindFilesystem:
 		location, err := filesystemMountPoint(charmStorage, storage.StorageTag(), series)
@@ -1665,28 +1680,33 @@ func machineStorageParamsForStorageInstance(
 			location,
 			charmStorage.ReadOnly,
 		}
-		if unit == storage.Owner() {
+		filesystem, err := st.StorageInstanceFilesystem(storage.StorageTag())
+		switch {
+		case err == nil:
+			// The storage instance is owned by the service, or was
+			// imported and attached before, so there is a filesystem
+			// already, for which we will just add an attachment.
+			filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
+		case !errors.IsNotFound(err) || unit != storage.Owner():
+			return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
+		default:
 			// The storage instance is owned by the unit, so we'll need
-			// to create a filesystem.
-			cons := allCons[storage.StorageName()]
-			filesystemParams := FilesystemParams{
-				storage: storage.StorageTag(),
-				binding: storage.StorageTag(),
-				Pool:    cons.Pool,
-				Size:    cons.Size,
+			// to create a filesystem, or record the imported one.
+			filesystemParams, err := st.importedFilesystemParams(storage.StorageTag())
+			if errors.IsNotFound(err) {
+				cons := allCons[storage.StorageName()]
+				filesystemParams = FilesystemParams{
+					storage: storage.StorageTag(),
+					binding: storage.StorageTag(),
+					Pool:    cons.Pool,
+					Size:    cons.Size,
+				}
+			} else if err != nil {
+				return nil, errors.Trace(err)
 			}
 			filesystems = append(filesystems, MachineFilesystemParams{
 				filesystemParams, filesystemAttachmentParams,
 			})
-		} else {
-			// The storage instance is owned by the service, so there
-			// should be a (shared) filesystem already, for which we will
-			// just add an attachment.
-			filesystem, err := st.StorageInstanceFilesystem(storage.StorageTag())
-			if err != nil {
-				return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
-			}
-			filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
 		}
 	default:
 		return nil, errors.Errorf("invalid storage kind %v", storage.Kind())
@@ -1761,6 +1781,7 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		{"series", u.doc.Series},
 		{"jobs", []MachineJob{JobHostUnits}},
 		{"clean", true},
+		{"cordoned", bson.D{{"$ne", true}}},
 		{"machineid", bson.D{{"$nin", machinesWithContainers}}},
 	}
 	// Add the container filter term if necessary.
@@ -1931,7 +1952,7 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 		if err == nil {
 			return m, nil
 		}
-		if err != inUseErr && err != machineNotAliveErr {
+		if err != inUseErr && err != machineNotAliveErr && err != machineCordonedErr {
 			assignContextf(&err, u, context)
 			return nil, err
 		}
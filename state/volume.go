@@ -116,6 +116,10 @@ type VolumeParams struct {
 
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot, if non-empty, is the ID of the volume snapshot
+	// from which the volume is to be created.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 // VolumeInfo describes information about a volume.
@@ -703,6 +707,7 @@ func (st *State) RemoveVolume(tag names.VolumeTag) (err error) {
 				Remove: true,
 			},
 			removeStatusOp(st, volumeGlobalKey(tag.Id())),
+			removeResizeOp(volumeResizesC, tag.Id()),
 		}, nil
 	}
 	return st.run(buildTxn)
@@ -0,0 +1,364 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// VolumeSnapshot describes a point-in-time snapshot of a volume.
+type VolumeSnapshot interface {
+	Lifer
+
+	// Id returns the unique ID of the snapshot within the environment.
+	// Snapshots of machine-scoped volumes have IDs prefixed with the
+	// ID of the volume's machine, in the same way as volume IDs.
+	Id() string
+
+	// Volume returns the tag of the volume that the snapshot is, or
+	// is to be, taken of.
+	Volume() names.VolumeTag
+
+	// VolumeId returns the provider-supplied ID of the volume at the
+	// time the snapshot was requested.
+	VolumeId() string
+
+	// Pool returns the name of the storage pool of the volume.
+	Pool() string
+
+	// Size returns the size of the volume at the time the snapshot
+	// was requested, in MiB.
+	Size() uint64
+
+	// Created returns the time at which the snapshot was requested.
+	Created() time.Time
+
+	// Info returns the snapshot's VolumeSnapshotInfo, or a
+	// NotProvisioned error if the snapshot has not yet been
+	// created by the storage provider.
+	Info() (VolumeSnapshotInfo, error)
+
+	// Error returns the error message recorded by the storage
+	// provisioner if the snapshot could not be created, or the
+	// empty string otherwise.
+	Error() string
+}
+
+type volumeSnapshot struct {
+	doc volumeSnapshotDoc
+}
+
+// volumeSnapshotDoc records information about a volume snapshot.
+type volumeSnapshotDoc struct {
+	DocID    string              `bson:"_id"`
+	Id       string              `bson:"id"`
+	EnvUUID  string              `bson:"env-uuid"`
+	Life     Life                `bson:"life"`
+	Volume   string              `bson:"volumeid"`
+	VolumeId string              `bson:"providervolumeid"`
+	Pool     string              `bson:"pool"`
+	Size     uint64              `bson:"size"`
+	Created  time.Time           `bson:"created"`
+	Info     *VolumeSnapshotInfo `bson:"info,omitempty"`
+	Error    string              `bson:"error,omitempty"`
+}
+
+// VolumeSnapshotInfo describes information about a volume snapshot
+// that has been created by the storage provider.
+type VolumeSnapshotInfo struct {
+	SnapshotId string `bson:"snapshotid"`
+	Size       uint64 `bson:"size"`
+}
+
+// Id is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Id() string {
+	return s.doc.Id
+}
+
+// Life is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Life() Life {
+	return s.doc.Life
+}
+
+// Volume is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Volume() names.VolumeTag {
+	return names.NewVolumeTag(s.doc.Volume)
+}
+
+// VolumeId is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) VolumeId() string {
+	return s.doc.VolumeId
+}
+
+// Pool is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Pool() string {
+	return s.doc.Pool
+}
+
+// Size is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Size() uint64 {
+	return s.doc.Size
+}
+
+// Created is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Created() time.Time {
+	return s.doc.Created
+}
+
+// Info is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Info() (VolumeSnapshotInfo, error) {
+	if s.doc.Info == nil {
+		return VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", s.doc.Id)
+	}
+	return *s.doc.Info, nil
+}
+
+// Error is required to implement VolumeSnapshot.
+func (s *volumeSnapshot) Error() string {
+	return s.doc.Error
+}
+
+// VolumeSnapshot returns the VolumeSnapshot with the specified ID.
+func (st *State) VolumeSnapshot(id string) (VolumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(bson.D{{"_id", id}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(snapshots) == 0 {
+		return nil, errors.NotFoundf("volume snapshot %q", id)
+	}
+	return snapshots[0], nil
+}
+
+// VolumeSnapshots returns all of the VolumeSnapshots of the specified
+// volume.
+func (st *State) VolumeSnapshots(volume names.VolumeTag) ([]VolumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(bson.D{{"volumeid", volume.Id()}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting snapshots of volume %q", volume.Id())
+	}
+	return snapshots, nil
+}
+
+// AllVolumeSnapshots returns all VolumeSnapshots in the environment.
+func (st *State) AllVolumeSnapshots() ([]VolumeSnapshot, error) {
+	snapshots, err := st.volumeSnapshots(nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get volume snapshots")
+	}
+	return snapshots, nil
+}
+
+func (st *State) volumeSnapshots(query interface{}) ([]VolumeSnapshot, error) {
+	coll, cleanup := st.getCollection(volumeSnapshotsC)
+	defer cleanup()
+
+	var docs []volumeSnapshotDoc
+	if err := coll.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "querying volume snapshots")
+	}
+	snapshots := make([]VolumeSnapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[i] = &volumeSnapshot{doc}
+	}
+	return snapshots, nil
+}
+
+// newVolumeSnapshotId returns a unique volume snapshot ID. Snapshots of
+// machine-scoped volumes are scoped to the same machine, so that the
+// machine's storage provisioner is responsible for creating them.
+func newVolumeSnapshotId(st *State, volume names.VolumeTag) (string, error) {
+	seq, err := st.sequence("volumesnapshot")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	id := fmt.Sprint(seq)
+	if machineTag, ok := names.VolumeMachine(volume); ok {
+		id = machineTag.Id() + "/" + id
+	}
+	return id, nil
+}
+
+// CreateVolumeSnapshot records a request to take a snapshot of the
+// specified volume. The snapshot will be created by the storage
+// provisioner responsible for the volume. The volume must be alive
+// and provisioned.
+func (st *State) CreateVolumeSnapshot(tag names.VolumeTag) (_ VolumeSnapshot, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot snapshot volume %q", tag.Id())
+	var doc *volumeSnapshotDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.New("volume is not alive")
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		id, err := newVolumeSnapshotId(st, tag)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot generate volume snapshot ID")
+		}
+		doc = &volumeSnapshotDoc{
+			Id:       id,
+			Volume:   tag.Id(),
+			VolumeId: info.VolumeId,
+			Pool:     info.Pool,
+			Size:     info.Size,
+			Created:  nowToTheSecond(),
+		}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: append(bson.D{{"info", bson.D{{"$exists", true}}}}, isAliveDoc...),
+		}, {
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, err
+	}
+	return &volumeSnapshot{*doc}, nil
+}
+
+// SetVolumeSnapshotInfo records the details of a newly created volume
+// snapshot. Once set, the snapshot's provider ID cannot be changed.
+func (st *State) SetVolumeSnapshotInfo(id string, info VolumeSnapshotInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set info for volume snapshot %q", id)
+	if info.SnapshotId == "" {
+		return errors.New("snapshot ID not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.VolumeSnapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if oldInfo, err := s.Info(); err == nil {
+			if oldInfo.SnapshotId != info.SnapshotId {
+				return nil, errors.Errorf(
+					"cannot change snapshot ID from %q to %q",
+					oldInfo.SnapshotId, info.SnapshotId,
+				)
+			}
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: bson.D{{"info", bson.D{{"$exists", false}}}},
+			Update: bson.D{
+				{"$set", bson.D{{"info", &info}}},
+				{"$unset", bson.D{{"error", nil}}},
+			},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// SetVolumeSnapshotError records that the storage provisioner failed to
+// create the specified volume snapshot. The snapshot must not already
+// have been created.
+func (st *State) SetVolumeSnapshotError(id, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set error for volume snapshot %q", id)
+	if message == "" {
+		return errors.New("error message not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.VolumeSnapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := s.Info(); err == nil {
+			return nil, errors.New("volume snapshot already created")
+		}
+		return []txn.Op{{
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: bson.D{{"info", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"error", message}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// RestoreVolumeSnapshot creates a new volume from the specified snapshot,
+// and attaches it to the specified machine. The new volume is created in
+// the same storage pool as the snapshotted volume, and is bound to the
+// machine. Snapshots of machine-scoped volumes may only be restored to
+// the same machine.
+func (st *State) RestoreVolumeSnapshot(id string, machineTag names.MachineTag) (_ names.VolumeTag, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot restore volume snapshot %q to machine %s", id, machineTag.Id())
+	s, err := st.VolumeSnapshot(id)
+	if err != nil {
+		return names.VolumeTag{}, errors.Trace(err)
+	}
+	info, err := s.Info()
+	if err != nil {
+		return names.VolumeTag{}, errors.Trace(err)
+	}
+	if i := strings.LastIndex(id, "/"); i >= 0 && id[:i] != machineTag.Id() {
+		return names.VolumeTag{}, errors.Errorf(
+			"snapshot of machine-scoped volume must be restored to machine %s", id[:i],
+		)
+	}
+	size := info.Size
+	if size == 0 {
+		size = s.Size()
+	}
+	storageParams := &machineStorageParams{
+		volumes: []MachineVolumeParams{{
+			Volume: VolumeParams{
+				Pool:     s.Pool(),
+				Size:     size,
+				Snapshot: id,
+			},
+		}},
+	}
+	var volumeTag names.VolumeTag
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		m, err := st.Machine(machineTag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if m.Life() != Alive {
+			return nil, errors.New("machine is not alive")
+		}
+		if err := validateDynamicMachineStorageParams(m, storageParams); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops, volumeAttachments, _, err := st.machineStorageOps(&m.doc, storageParams)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		attachmentOps, err := addMachineStorageAttachmentsOps(m, volumeAttachments, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		volumeTag = volumeAttachments[0].tag
+		ops = append(ops, txn.Op{
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+		})
+		return append(ops, attachmentOps...), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return names.VolumeTag{}, err
+	}
+	return volumeTag, nil
+}
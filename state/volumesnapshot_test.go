@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type VolumeSnapshotSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&VolumeSnapshotSuite{})
+
+func (s *VolumeSnapshotSuite) addProvisionedVolume(c *gc.C) (*state.Machine, names.VolumeTag) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 123, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+	return m, volumeTag
+}
+
+func (s *VolumeSnapshotSuite) TestCreateVolumeSnapshot(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+
+	snapshot, err := s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Id(), gc.Equals, "0/0")
+	c.Assert(snapshot.Life(), gc.Equals, state.Alive)
+	c.Assert(snapshot.Volume(), gc.Equals, volumeTag)
+	c.Assert(snapshot.VolumeId(), gc.Equals, "vol-ume")
+	c.Assert(snapshot.Pool(), gc.Equals, "loop-pool")
+	c.Assert(snapshot.Size(), gc.Equals, uint64(123))
+	c.Assert(snapshot.Created().IsZero(), jc.IsFalse)
+	_, err = snapshot.Info()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	snapshots, err := s.State.VolumeSnapshots(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshots, gc.HasLen, 1)
+	c.Assert(snapshots[0].Id(), gc.Equals, "0/0")
+
+	all, err := s.State.AllVolumeSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+}
+
+func (s *VolumeSnapshotSuite) TestCreateVolumeSnapshotUnprovisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+
+	_, err = s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, gc.ErrorMatches, `cannot snapshot volume "0/0": volume "0/0" not provisioned`)
+}
+
+func (s *VolumeSnapshotSuite) TestVolumeSnapshotNotFound(c *gc.C) {
+	_, err := s.State.VolumeSnapshot("42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `volume snapshot "42" not found`)
+}
+
+func (s *VolumeSnapshotSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	snapshot, err := s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetVolumeSnapshotError(snapshot.Id(), "out of cheese")
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotGet, err := s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotGet.Error(), gc.Equals, "out of cheese")
+
+	info := state.VolumeSnapshotInfo{SnapshotId: "snap-shot", Size: 123}
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), info)
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotGet, err = s.State.VolumeSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	infoGet, err := snapshotGet.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infoGet, jc.DeepEquals, info)
+	c.Assert(snapshotGet.Error(), gc.Equals, "")
+
+	// Setting the same info again is a no-op.
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), info)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *VolumeSnapshotSuite) TestSetVolumeSnapshotInfoImmutable(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	snapshot, err := s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{})
+	c.Assert(err, gc.ErrorMatches, `cannot set info for volume snapshot "0/0": snapshot ID not set`)
+
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{SnapshotId: "snap-shot"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{SnapshotId: "snap-other"})
+	c.Assert(err, gc.ErrorMatches, `cannot set info for volume snapshot "0/0": cannot change snapshot ID from "snap-shot" to "snap-other"`)
+	err = s.State.SetVolumeSnapshotError(snapshot.Id(), "too late")
+	c.Assert(err, gc.ErrorMatches, `cannot set error for volume snapshot "0/0": volume snapshot already created`)
+}
+
+func (s *VolumeSnapshotSuite) TestWatchMachineVolumeSnapshots(c *gc.C) {
+	m, volumeTag := s.addProvisionedVolume(c)
+
+	w := s.State.WatchMachineVolumeSnapshots(m.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	_, err := s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	ew := s.State.WatchEnvironVolumeSnapshots()
+	defer testing.AssertStop(c, ew)
+	ewc := testing.NewStringsWatcherC(c, s.State, ew)
+	ewc.AssertChangeInSingleEvent() // initial, machine-scoped excluded
+	ewc.AssertNoChange()
+}
+
+func (s *VolumeSnapshotSuite) TestRestoreVolumeSnapshot(c *gc.C) {
+	m, volumeTag := s.addProvisionedVolume(c)
+	snapshot, err := s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{
+		SnapshotId: "snap-shot", Size: 456,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	restoredTag, err := s.State.RestoreVolumeSnapshot(snapshot.Id(), m.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restoredTag, gc.Equals, names.NewVolumeTag("0/1"))
+
+	volume := s.volume(c, restoredTag)
+	params, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params, jc.DeepEquals, state.VolumeParams{
+		Pool:     "loop-pool",
+		Size:     456,
+		Snapshot: "0/0",
+	})
+	_, err = s.State.VolumeAttachment(m.MachineTag(), restoredTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *VolumeSnapshotSuite) TestRestoreVolumeSnapshotNotCreated(c *gc.C) {
+	m, volumeTag := s.addProvisionedVolume(c)
+	snapshot, err := s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.RestoreVolumeSnapshot(snapshot.Id(), m.MachineTag())
+	c.Assert(err, gc.ErrorMatches, `cannot restore volume snapshot "0/0" to machine 0: volume snapshot "0/0" not provisioned`)
+}
+
+func (s *VolumeSnapshotSuite) TestRestoreVolumeSnapshotOtherMachine(c *gc.C) {
+	_, volumeTag := s.addProvisionedVolume(c)
+	snapshot, err := s.State.CreateVolumeSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{SnapshotId: "snap-shot"})
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.RestoreVolumeSnapshot(snapshot.Id(), other.MachineTag())
+	c.Assert(err, gc.ErrorMatches, `cannot restore volume snapshot "0/0" to machine 1: snapshot of machine-scoped volume must be restored to machine 0`)
+}
@@ -6,7 +6,6 @@ package state
 import (
 	"fmt"
 	"reflect"
-	"regexp"
 	"strings"
 	"time"
 
@@ -156,6 +155,10 @@ type lifecycleWatcher struct {
 	transform func(string) string
 	// life holds the most recent known life states of interesting entities.
 	life map[string]Life
+	// strictMembers, if true, causes changed entities to be considered
+	// only while they still match members. Entities which stop matching
+	// are forgotten without being reported.
+	strictMembers bool
 }
 
 func collFactory(st *State, collName string) func() (mongo.Collection, func()) {
@@ -213,6 +216,47 @@ func (st *State) WatchMachineFilesystems(m names.MachineTag) StringsWatcher {
 	return st.watchMachineStorage(m, filesystemsC)
 }
 
+// WatchEnvironVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all snapshots of environment-scoped volumes.
+func (st *State) WatchEnvironVolumeSnapshots() StringsWatcher {
+	return st.watchEnvironMachineStorage(volumeSnapshotsC)
+}
+
+// WatchMachineVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all snapshots of volumes scoped to the
+// specified machine.
+func (st *State) WatchMachineVolumeSnapshots(m names.MachineTag) StringsWatcher {
+	return st.watchMachineStorage(m, volumeSnapshotsC)
+}
+
+// WatchEnvironVolumeResizes returns a StringsWatcher that notifies of
+// the creation and removal of pending resize requests for environment-
+// scoped volumes.
+func (st *State) WatchEnvironVolumeResizes() StringsWatcher {
+	return st.watchEnvironMachineStorage(volumeResizesC)
+}
+
+// WatchMachineVolumeResizes returns a StringsWatcher that notifies of
+// the creation and removal of pending resize requests for volumes
+// scoped to the specified machine.
+func (st *State) WatchMachineVolumeResizes(m names.MachineTag) StringsWatcher {
+	return st.watchMachineStorage(m, volumeResizesC)
+}
+
+// WatchEnvironFilesystemResizes returns a StringsWatcher that notifies
+// of the creation and removal of pending resize requests for
+// environment-scoped filesystems.
+func (st *State) WatchEnvironFilesystemResizes() StringsWatcher {
+	return st.watchEnvironMachineStorage(filesystemResizesC)
+}
+
+// WatchMachineFilesystemResizes returns a StringsWatcher that notifies
+// of the creation and removal of pending resize requests for filesystems
+// scoped to the specified machine.
+func (st *State) WatchMachineFilesystemResizes(m names.MachineTag) StringsWatcher {
+	return st.watchMachineStorage(m, filesystemResizesC)
+}
+
 func (st *State) watchMachineStorage(m names.MachineTag, collection string) StringsWatcher {
 	pattern := fmt.Sprintf("^%s/%s$", st.docID(m.Id()), names.NumberSnippet)
 	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
@@ -363,31 +407,53 @@ func (st *State) WatchEnvironMachines() StringsWatcher {
 }
 
 // WatchContainers returns a StringsWatcher that notifies of changes to the
-// lifecycles of containers of the specified type on a machine.
+// lifecycles of containers of the specified type on a machine. Containers
+// migrated to the machine are included, and those migrated away are not.
 func (m *Machine) WatchContainers(ctype instance.ContainerType) StringsWatcher {
 	isChild := fmt.Sprintf("^%s/%s/%s$", m.doc.DocID, ctype, names.NumberSnippet)
-	return m.containersWatcher(isChild)
+	hosted := bson.D{{"hostid", m.doc.Id}, {"containertype", string(ctype)}}
+	return m.containersWatcher(isChild, hosted)
 }
 
 // WatchAllContainers returns a StringsWatcher that notifies of changes to the
-// lifecycles of all containers on a machine.
+// lifecycles of all containers on a machine. Containers migrated to the
+// machine are included, and those migrated away are not.
 func (m *Machine) WatchAllContainers() StringsWatcher {
 	isChild := fmt.Sprintf("^%s/%s/%s$", m.doc.DocID, names.ContainerTypeSnippet, names.NumberSnippet)
-	return m.containersWatcher(isChild)
+	hosted := bson.D{{"hostid", m.doc.Id}}
+	return m.containersWatcher(isChild, hosted)
 }
 
-func (m *Machine) containersWatcher(isChildRegexp string) StringsWatcher {
-	members := bson.D{{"_id", bson.D{{"$regex", isChildRegexp}}}}
-	compiled := regexp.MustCompile(isChildRegexp)
+func (m *Machine) containersWatcher(isChildRegexp string, hosted bson.D) StringsWatcher {
+	members := bson.D{{"$or", []bson.D{{
+		{"_id", bson.D{{"$regex", isChildRegexp}}},
+		{"hostid", bson.D{{"$exists", false}}},
+	}, hosted}}}
 	filter := func(key interface{}) bool {
-		k := key.(string)
-		_, err := m.st.strictLocalID(k)
+		k, err := m.st.strictLocalID(key.(string))
 		if err != nil {
 			return false
 		}
-		return compiled.MatchString(k)
+		// Any container may have been migrated to this machine, so
+		// membership is decided by the document itself.
+		return strings.Contains(k, "/")
 	}
-	return newLifecycleWatcher(m.st, machinesC, members, filter, nil)
+	w := &lifecycleWatcher{
+		commonWatcher: commonWatcher{st: m.st},
+		coll:          collFactory(m.st, machinesC),
+		collName:      machinesC,
+		members:       members,
+		filter:        filter,
+		life:          make(map[string]Life),
+		out:           make(chan []string),
+		strictMembers: true,
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
 }
 
 func newLifecycleWatcher(
@@ -468,7 +534,11 @@ func (w *lifecycleWatcher) merge(ids set.Strings, updates map[interface{}]bool)
 	// exist are ignored (we'll hear about them in the next set of updates --
 	// all that's actually happened in that situation is that the watcher
 	// events have lagged a little behind reality).
-	iter := coll.Find(bson.D{{"_id", bson.D{{"$in", changed}}}}).Select(lifeFields).Iter()
+	query := bson.D{{"_id", bson.D{{"$in", changed}}}}
+	if w.strictMembers {
+		query = bson.D{{"$and", []bson.D{query, w.members}}}
+	}
+	iter := coll.Find(query).Select(lifeFields).Iter()
 	var doc lifeDoc
 	for iter.Next(&doc) {
 		latest[w.st.localID(doc.Id)] = doc.Life
@@ -476,6 +546,15 @@ func (w *lifecycleWatcher) merge(ids set.Strings, updates map[interface{}]bool)
 	if err := iter.Close(); err != nil {
 		return err
 	}
+	if w.strictMembers {
+		// Forget entities that no longer match members.
+		for _, docID := range changed {
+			id := w.st.localID(docID)
+			if _, ok := latest[id]; !ok {
+				delete(w.life, id)
+			}
+		}
+	}
 
 	// Add to ids any whose life state is known to have changed.
 	for id, newLife := range latest {
@@ -1412,6 +1491,21 @@ func (st *State) WatchForEnvironConfigChanges() NotifyWatcher {
 	return newEntityWatcher(st, settingsC, st.docID(environGlobalKey))
 }
 
+// WatchAgentVersion returns a NotifyWatcher that notifies when the
+// agent version machines should run may have changed: when the
+// environment config changes, or when a staged upgrade progresses.
+func (st *State) WatchAgentVersion() NotifyWatcher {
+	return newDocWatcher(st, []docKey{
+		{
+			settingsC,
+			st.docID(environGlobalKey),
+		}, {
+			stagedUpgradesC,
+			st.docID(currentStagedUpgradeId),
+		},
+	})
+}
+
 // WatchAPIHostPorts returns a NotifyWatcher that notifies
 // when the set of API addresses changes.
 func (st *State) WatchAPIHostPorts() NotifyWatcher {
@@ -1439,8 +1533,21 @@ func (st *State) WatchFilesystemAttachment(m names.MachineTag, f names.Filesyste
 	return newEntityWatcher(st, filesystemAttachmentsC, st.docID(id))
 }
 
+// WatchVolume returns a watcher for observing changes to a volume,
+// such as its size being changed by a resize.
+func (st *State) WatchVolume(v names.VolumeTag) NotifyWatcher {
+	return newEntityWatcher(st, volumesC, st.docID(v.Id()))
+}
+
+// WatchFilesystem returns a watcher for observing changes to a
+// filesystem, such as its size being changed by a resize.
+func (st *State) WatchFilesystem(f names.FilesystemTag) NotifyWatcher {
+	return newEntityWatcher(st, filesystemsC, st.docID(f.Id()))
+}
+
 // WatchConfigSettings returns a watcher for observing changes to the
-// unit's service configuration settings. The unit must have a charm URL
+// unit's service configuration settings, including changes to the config
+// profile used by the service. The unit must have a charm URL
 // set before this method is called, and the returned watcher will be
 // valid only while the unit's charm URL is not changed.
 // TODO(fwereade): this could be much smarter; if it were, uniter.Filter
@@ -1450,7 +1557,15 @@ func (u *Unit) WatchConfigSettings() (NotifyWatcher, error) {
 		return nil, fmt.Errorf("unit charm not set")
 	}
 	settingsKey := serviceSettingsKey(u.doc.Service, u.doc.CharmURL)
-	return newEntityWatcher(u.st, settingsC, u.st.docID(settingsKey)), nil
+	return newDocWatcher(u.st, []docKey{
+		{
+			settingsC,
+			u.st.docID(settingsKey),
+		}, {
+			serviceConfigProfilesC,
+			u.st.docID(serviceGlobalKey(u.doc.Service)),
+		},
+	}), nil
 }
 
 // WatchMeterStatus returns a watcher observing changes that affect the meter status
@@ -1834,6 +1949,60 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// notifyCollWatcher notifies of any change to the documents of a
+// collection accepted by its filter.
+type notifyCollWatcher struct {
+	commonWatcher
+	collName string
+	filter   func(interface{}) bool
+	out      chan struct{}
+}
+
+var _ Watcher = (*notifyCollWatcher)(nil)
+
+func newNotifyCollWatcher(st *State, collName string, filter func(interface{}) bool) NotifyWatcher {
+	w := &notifyCollWatcher{
+		commonWatcher: commonWatcher{st: st},
+		collName:      collName,
+		filter:        filter,
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *notifyCollWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *notifyCollWatcher) loop() (err error) {
+	in := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(w.collName, in, w.filter)
+	defer w.st.watcher.UnwatchCollection(w.collName, in)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
@@ -2521,7 +2690,10 @@ func (w *openedPortsWatcher) merge(ids set.Strings, change watcher.Change) error
 // when the reboot flag is set on our machine agent, our parent machine agent
 // or grandparent machine agent
 func (m *Machine) WatchForRebootEvent() (NotifyWatcher, error) {
-	machineIds := m.machinesToCareAboutRebootsFor()
+	machineIds, err := m.machinesToCareAboutRebootsFor()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	machines := set.NewStrings(machineIds...)
 	return newRebootWatcher(m.st, machines), nil
 }
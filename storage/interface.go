@@ -62,6 +62,16 @@ type Provider interface {
 	ValidateConfig(*Config) error
 }
 
+// FilesystemImporter is an optional interface that a Provider may
+// implement if filesystems that were not created by Juju may be
+// imported into its pools.
+type FilesystemImporter interface {
+	// ImportFilesystem checks that the filesystem with the specified
+	// provider ID may be used by a pool with the given configuration,
+	// and returns the filesystem's information.
+	ImportFilesystem(providerConfig *Config, filesystemId string) (*FilesystemInfo, error)
+}
+
 // VolumeSource provides an interface for creating, destroying, describing,
 // attaching and detaching volumes in the environment. A VolumeSource is
 // configured in a particular way, and corresponds to a storage "pool".
@@ -108,6 +118,24 @@ type VolumeSource interface {
 	DetachVolumes(params []VolumeAttachmentParams) ([]error, error)
 }
 
+// VolumeSnapshotter is an optional interface that a VolumeSource may
+// implement if it supports taking point-in-time snapshots of volumes.
+// Volumes are restored from a snapshot by passing the snapshot's ID in
+// VolumeParams.SnapshotId to CreateVolumes.
+type VolumeSnapshotter interface {
+	// CreateVolumeSnapshots creates snapshots of the volumes with the
+	// specified parameters.
+	CreateVolumeSnapshots(params []VolumeSnapshotParams) ([]CreateVolumeSnapshotsResult, error)
+}
+
+// VolumeResizer is an optional interface that a VolumeSource may
+// implement if it supports growing existing volumes.
+type VolumeResizer interface {
+	// ResizeVolumes grows the volumes with the specified parameters,
+	// returning the updated volume information.
+	ResizeVolumes(params []VolumeResizeParams) ([]ResizeVolumesResult, error)
+}
+
 // FilesystemSource provides an interface for creating, destroying and
 // describing filesystems in the environment. A FilesystemSource is
 // configured in a particular way, and corresponds to a storage "pool".
@@ -141,6 +169,14 @@ type FilesystemSource interface {
 	DetachFilesystems(params []FilesystemAttachmentParams) ([]error, error)
 }
 
+// FilesystemResizer is an optional interface that a FilesystemSource
+// may implement if it supports growing existing filesystems.
+type FilesystemResizer interface {
+	// ResizeFilesystems grows the filesystems with the specified
+	// parameters, returning the updated filesystem information.
+	ResizeFilesystems(params []FilesystemResizeParams) ([]ResizeFilesystemsResult, error)
+}
+
 // VolumeParams is a fully specified set of parameters for volume creation,
 // derived from one or more of user-specified storage constraints, a
 // storage pool definition, and charm storage metadata.
@@ -175,6 +211,12 @@ type VolumeParams struct {
 	// once the instance is created there are still unprovisioned volumes,
 	// the dynamic storage provisioner will take care of creating them.
 	Attachment *VolumeAttachmentParams
+
+	// SnapshotId is the unique provider-supplied ID of a snapshot from
+	// which the volume should be created, or empty if the volume should
+	// be created empty. Only volume sources that implement
+	// VolumeSnapshotter will be asked to create volumes from snapshots.
+	SnapshotId string
 }
 
 // IsPersistent returns true if the params has persistent set to true.
@@ -220,6 +262,42 @@ type AttachmentParams struct {
 	ReadOnly bool
 }
 
+// VolumeSnapshotParams is a set of parameters for creating a snapshot
+// of a volume.
+type VolumeSnapshotParams struct {
+	// Volume is the tag of the volume to snapshot.
+	Volume names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume to
+	// snapshot.
+	VolumeId string
+
+	// Provider is the name of the storage provider that manages the
+	// volume.
+	Provider ProviderType
+
+	// ResourceTags is a set of tags to set on the created snapshot, if
+	// the storage provider supports tags.
+	ResourceTags map[string]string
+}
+
+// VolumeResizeParams is a set of parameters for growing a volume.
+type VolumeResizeParams struct {
+	// Volume is the tag of the volume to resize.
+	Volume names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume to
+	// resize.
+	VolumeId string
+
+	// Provider is the name of the storage provider that manages the
+	// volume.
+	Provider ProviderType
+
+	// Size is the minimum size of the resized volume in MiB.
+	Size uint64
+}
+
 // FilesystemParams is a fully specified set of parameters for filesystem creation,
 // derived from one or more of user-specified storage constraints, a
 // storage pool definition, and charm storage metadata.
@@ -245,6 +323,26 @@ type FilesystemParams struct {
 	ResourceTags map[string]string
 }
 
+// FilesystemResizeParams is a set of parameters for growing a filesystem.
+type FilesystemResizeParams struct {
+	// Filesystem is the tag of the filesystem to resize.
+	Filesystem names.FilesystemTag
+
+	// FilesystemId is the unique provider-supplied ID for the
+	// filesystem to resize.
+	FilesystemId string
+
+	// Volume is the tag of the volume that backs the filesystem, if any.
+	Volume names.VolumeTag
+
+	// Provider is the name of the storage provider that manages the
+	// filesystem.
+	Provider ProviderType
+
+	// Size is the minimum size of the resized filesystem in MiB.
+	Size uint64
+}
+
 // FilesystemAttachmentParams is a set of parameters for filesystem attachment
 // or detachment.
 type FilesystemAttachmentParams struct {
@@ -272,6 +370,21 @@ type CreateVolumesResult struct {
 	Error            error
 }
 
+// CreateVolumeSnapshotsResult contains the result of a
+// VolumeSnapshotter.CreateVolumeSnapshots call for one volume.
+// VolumeSnapshot should only be used if Error is nil.
+type CreateVolumeSnapshotsResult struct {
+	VolumeSnapshot *VolumeSnapshot
+	Error          error
+}
+
+// ResizeVolumesResult contains the result of a VolumeResizer.ResizeVolumes
+// call for one volume. VolumeInfo should only be used if Error is nil.
+type ResizeVolumesResult struct {
+	VolumeInfo *VolumeInfo
+	Error      error
+}
+
 // DescribeVolumesResult contains the result of a VolumeSource.DescribeVolumes call
 // for one volume. Volume should only be used if Error is nil.
 type DescribeVolumesResult struct {
@@ -306,3 +419,11 @@ type AttachFilesystemsResult struct {
 	FilesystemAttachment *FilesystemAttachment
 	Error                error
 }
+
+// ResizeFilesystemsResult contains the result of a
+// FilesystemResizer.ResizeFilesystems call for one filesystem.
+// FilesystemInfo should only be used if Error is nil.
+type ResizeFilesystemsResult struct {
+	FilesystemInfo *FilesystemInfo
+	Error          error
+}
@@ -52,6 +52,9 @@ func (pm *poolManager) Create(name string, providerType storage.ProviderType, at
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if _, err := PoolReleasePolicy(cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
 	p, err := registry.StorageProvider(providerType)
 	if err != nil {
 		return nil, errors.Trace(err)
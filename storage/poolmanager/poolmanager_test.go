@@ -128,3 +128,28 @@ func (s *poolSuite) TestDelete(c *gc.C) {
 	err = s.poolManager.Delete("testpool")
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *poolSuite) TestCreateReleasePolicy(c *gc.C) {
+	p, err := s.poolManager.Create("testpool", storage.ProviderType("loop"), map[string]interface{}{
+		"release-policy": "retain",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := poolmanager.PoolReleasePolicy(p)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.Equals, poolmanager.ReleaseRetain)
+}
+
+func (s *poolSuite) TestCreateInvalidReleasePolicy(c *gc.C) {
+	_, err := s.poolManager.Create("testpool", storage.ProviderType("loop"), map[string]interface{}{
+		"release-policy": "shred",
+	})
+	c.Assert(err, gc.ErrorMatches, `release policy "shred" not valid`)
+}
+
+func (s *poolSuite) TestDefaultReleasePolicy(c *gc.C) {
+	p, err := s.poolManager.Create("testpool", storage.ProviderType("loop"), map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := poolmanager.PoolReleasePolicy(p)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.Equals, poolmanager.ReleaseDestroy)
+}
@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package poolmanager
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/storage"
+)
+
+// ReleasePolicy determines what happens to the volumes and filesystems
+// backing a storage instance when the storage is removed, or when the
+// unit that owns the storage is removed.
+type ReleasePolicy string
+
+const (
+	// ReleasePolicyAttr is the name of the pool configuration
+	// attribute that holds the pool's default release policy.
+	ReleasePolicyAttr = "release-policy"
+
+	// ReleaseDestroy causes released storage to be destroyed, along
+	// with the volumes and filesystems bound to it.
+	ReleaseDestroy ReleasePolicy = "destroy"
+
+	// ReleaseRetain causes released storage to be detached from its
+	// unit and retained, so that it may be attached to another unit.
+	ReleaseRetain ReleasePolicy = "retain"
+)
+
+// ParseReleasePolicy parses the given string as a release policy.
+func ParseReleasePolicy(s string) (ReleasePolicy, error) {
+	switch policy := ReleasePolicy(s); policy {
+	case ReleaseDestroy, ReleaseRetain:
+		return policy, nil
+	}
+	return "", errors.NotValidf("release policy %q", s)
+}
+
+// PoolReleasePolicy returns the release policy of the pool with the
+// specified configuration. Storage from pools that do not specify a
+// release policy is destroyed when released.
+func PoolReleasePolicy(cfg *storage.Config) (ReleasePolicy, error) {
+	value, ok := cfg.Attrs()[ReleasePolicyAttr]
+	if !ok {
+		return ReleaseDestroy, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", errors.NotValidf("release policy %v", value)
+	}
+	return ParseReleasePolicy(s)
+}
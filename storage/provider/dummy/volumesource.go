@@ -20,6 +20,9 @@ type VolumeSource struct {
 	ValidateVolumeParamsFunc func(storage.VolumeParams) error
 	AttachVolumesFunc        func([]storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error)
 	DetachVolumesFunc        func([]storage.VolumeAttachmentParams) ([]error, error)
+
+	CreateVolumeSnapshotsFunc func([]storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error)
+	ResizeVolumesFunc         func([]storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error)
 }
 
 // CreateVolumes is defined on storage.VolumeSource.
@@ -84,3 +87,21 @@ func (s *VolumeSource) DetachVolumes(params []storage.VolumeAttachmentParams) ([
 	}
 	return nil, errors.NotImplementedf("DetachVolumes")
 }
+
+// CreateVolumeSnapshots is defined on storage.VolumeSnapshotter.
+func (s *VolumeSource) CreateVolumeSnapshots(params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	s.MethodCall(s, "CreateVolumeSnapshots", params)
+	if s.CreateVolumeSnapshotsFunc != nil {
+		return s.CreateVolumeSnapshotsFunc(params)
+	}
+	return nil, errors.NotImplementedf("CreateVolumeSnapshots")
+}
+
+// ResizeVolumes is defined on storage.VolumeResizer.
+func (s *VolumeSource) ResizeVolumes(params []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	s.MethodCall(s, "ResizeVolumes", params)
+	if s.ResizeVolumesFunc != nil {
+		return s.ResizeVolumesFunc(params)
+	}
+	return nil, errors.NotImplementedf("ResizeVolumes")
+}
@@ -129,6 +129,18 @@ func TmpfsProvider(run func(string, ...string) (string, error)) storage.Provider
 	return &tmpfsProvider{run}
 }
 
+func NFSFilesystemSource(storageDir string, run func(string, ...string) (string, error)) (storage.FilesystemSource, *MockDirFuncs) {
+	d := &MockDirFuncs{
+		osDirFuncs{run},
+		set.NewStrings(),
+	}
+	return &nfsFilesystemSource{d, run, storageDir}, d
+}
+
+func NFSProvider(run func(string, ...string) (string, error)) storage.Provider {
+	return &nfsProvider{run}
+}
+
 // MountedDirs returns all the Dirs which have been created during any CreateFilesystem calls
 // on the specified filesystem source..
 func MountedDirs(fsSource storage.FilesystemSource) set.Strings {
@@ -100,6 +100,8 @@ type loopVolumeSource struct {
 }
 
 var _ storage.VolumeSource = (*loopVolumeSource)(nil)
+var _ storage.VolumeSnapshotter = (*loopVolumeSource)(nil)
+var _ storage.VolumeResizer = (*loopVolumeSource)(nil)
 
 // CreateVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
@@ -120,7 +122,15 @@ func (lvs *loopVolumeSource) createVolume(params storage.VolumeParams) (storage.
 	if err := ensureDir(lvs.dirFuncs, filepath.Dir(loopFilePath)); err != nil {
 		return storage.Volume{}, errors.Trace(err)
 	}
-	if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
+	if params.SnapshotId != "" {
+		snapshotFilePath, err := lvs.snapshotFilePath(params.SnapshotId)
+		if err != nil {
+			return storage.Volume{}, errors.Trace(err)
+		}
+		if err := copyBlockFile(lvs.run, snapshotFilePath, loopFilePath); err != nil {
+			return storage.Volume{}, errors.Annotate(err, "could not restore block file from snapshot")
+		}
+	} else if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
 		return storage.Volume{}, errors.Annotate(err, "could not create block file")
 	}
 	return storage.Volume{
@@ -136,6 +146,17 @@ func (lvs *loopVolumeSource) volumeFilePath(tag names.VolumeTag) string {
 	return filepath.Join(lvs.storageDir, tag.String())
 }
 
+func (lvs *loopVolumeSource) snapshotDir() string {
+	return filepath.Join(lvs.storageDir, "snapshots")
+}
+
+func (lvs *loopVolumeSource) snapshotFilePath(snapshotId string) (string, error) {
+	if snapshotId == "" || filepath.Base(snapshotId) != snapshotId {
+		return "", errors.Errorf("invalid loop snapshot ID %q", snapshotId)
+	}
+	return filepath.Join(lvs.snapshotDir(), snapshotId), nil
+}
+
 // ListVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) ListVolumes() ([]string, error) {
 	// TODO(axw) implement this when we need it.
@@ -172,6 +193,98 @@ func (lvs *loopVolumeSource) destroyVolume(volumeId string) error {
 	return nil
 }
 
+// CreateVolumeSnapshots is defined on the VolumeSnapshotter interface.
+func (lvs *loopVolumeSource) CreateVolumeSnapshots(args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshot, err := lvs.createVolumeSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "snapshotting volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeSnapshot = snapshot
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) createVolumeSnapshot(arg storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	loopFilePath := lvs.volumeFilePath(arg.Volume)
+	info, err := os.Stat(loopFilePath)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading loop backing file")
+	}
+	snapshotDir := lvs.snapshotDir()
+	if err := ensureDir(lvs.dirFuncs, snapshotDir); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Snapshots are named after the volume, with a sequence number
+	// to distinguish multiple snapshots of the same volume.
+	var snapshotId string
+	for n := 0; ; n++ {
+		snapshotId = fmt.Sprintf("%s-%d", arg.Volume.String(), n)
+		_, err := os.Stat(filepath.Join(snapshotDir, snapshotId))
+		if os.IsNotExist(err) {
+			break
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	snapshotFilePath := filepath.Join(snapshotDir, snapshotId)
+	if err := copyBlockFile(lvs.run, loopFilePath, snapshotFilePath); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.VolumeSnapshot{
+		Volume:     arg.Volume,
+		SnapshotId: snapshotId,
+		Size:       uint64(info.Size()) / (1024 * 1024),
+	}, nil
+}
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+func (lvs *loopVolumeSource) ResizeVolumes(args []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	results := make([]storage.ResizeVolumesResult, len(args))
+	for i, arg := range args {
+		info, err := lvs.resizeVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "resizing volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeInfo = info
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) resizeVolume(arg storage.VolumeResizeParams) (*storage.VolumeInfo, error) {
+	loopFilePath := lvs.volumeFilePath(arg.Volume)
+	info, err := os.Stat(loopFilePath)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading loop backing file")
+	}
+	if size := uint64(info.Size()) / (1024 * 1024); size >= arg.Size {
+		return nil, errors.Errorf(
+			"cannot shrink volume from %dMiB to %dMiB", size, arg.Size,
+		)
+	}
+	if err := createBlockFile(lvs.run, loopFilePath, arg.Size); err != nil {
+		return nil, errors.Annotate(err, "could not extend block file")
+	}
+	// Any loop devices attached to the file must be told
+	// to pick up the new size of the backing file.
+	deviceNames, err := associatedLoopDevices(lvs.run, loopFilePath)
+	if err != nil {
+		return nil, errors.Annotate(err, "locating loop device")
+	}
+	for _, deviceName := range deviceNames {
+		if err := refreshLoopDevice(lvs.run, deviceName); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &storage.VolumeInfo{
+		VolumeId: arg.VolumeId,
+		Size:     arg.Size,
+	}, nil
+}
+
 // ValidateVolumeParams is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
 	// ValdiateVolumeParams may be called on a machine other than the
@@ -240,7 +353,8 @@ func (lvs *loopVolumeSource) detachVolume(tag names.VolumeTag) error {
 }
 
 // createBlockFile creates a file at the specified path, with the
-// given size in mebibytes.
+// given size in mebibytes. If the file already exists and is smaller
+// than the specified size, it is extended.
 func createBlockFile(run runCommandFunc, filePath string, sizeInMiB uint64) error {
 	// fallocate will reserve the space without actually writing to it.
 	_, err := run("fallocate", "-l", fmt.Sprintf("%dMiB", sizeInMiB), filePath)
@@ -250,6 +364,16 @@ func createBlockFile(run runCommandFunc, filePath string, sizeInMiB uint64) erro
 	return nil
 }
 
+// copyBlockFile copies the block file at the source path to the
+// destination path, preserving sparseness.
+func copyBlockFile(run runCommandFunc, sourcePath, destPath string) error {
+	_, err := run("cp", "--sparse=always", sourcePath, destPath)
+	if err != nil {
+		return errors.Annotatef(err, "copying %q to %q", sourcePath, destPath)
+	}
+	return nil
+}
+
 // attachLoopDevice attaches a loop device to the file with the
 // specified path, and returns the loop device's name (e.g. "loop0").
 // losetup will create additional loop devices as necessary.
@@ -289,6 +413,16 @@ func detachLoopDevice(run runCommandFunc, deviceName string) error {
 	return err
 }
 
+// refreshLoopDevice causes the loop device with the specified name
+// to pick up any change in the size of its backing file.
+func refreshLoopDevice(run runCommandFunc, deviceName string) error {
+	_, err := run("losetup", "-c", path.Join("/dev", deviceName))
+	if err != nil {
+		return errors.Annotatef(err, "refreshing loop device %q", deviceName)
+	}
+	return nil
+}
+
 // associatedLoopDevices returns the device names of the loop devices
 // associated with the specified file path.
 func associatedLoopDevices(run runCommandFunc, filePath string) ([]string, error) {
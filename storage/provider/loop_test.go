@@ -125,6 +125,113 @@ func (s *loopSuite) TestCreateVolumesNoAttachment(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *loopSuite) TestCreateVolumesFromSnapshot(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	s.commands.expect(
+		"cp", "--sparse=always",
+		filepath.Join(s.storageDir, "snapshots", "volume-0-0"),
+		filepath.Join(s.storageDir, "volume-1"),
+	)
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       2,
+		SnapshotId: "volume-0-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		names.NewVolumeTag("1"),
+		storage.VolumeInfo{
+			VolumeId: "volume-1",
+			Size:     2,
+		},
+	})
+}
+
+func (s *loopSuite) TestCreateVolumesFromInvalidSnapshot(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       2,
+		SnapshotId: "../super/important/stuff",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `.* invalid loop snapshot ID "\.\./super/important/stuff"`)
+}
+
+func (s *loopSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	source, dirFuncs := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0")
+	err := ioutil.WriteFile(fileName, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Truncate(fileName, 2*1024*1024)
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshotDir := filepath.Join(s.storageDir, "snapshots")
+	s.commands.expect("cp", "--sparse=always", fileName, filepath.Join(snapshotDir, "volume-0-0"))
+
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	c.Assert(ok, jc.IsTrue)
+	results, err := snapshotter.CreateVolumeSnapshots([]storage.VolumeSnapshotParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+	}, {
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "volume-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeSnapshot, jc.DeepEquals, &storage.VolumeSnapshot{
+		Volume:     names.NewVolumeTag("0"),
+		SnapshotId: "volume-0-0",
+		Size:       2,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, "snapshotting volume 1: reading loop backing file: .*")
+	c.Assert(dirFuncs.Dirs.Contains(snapshotDir), jc.IsTrue)
+}
+
+func (s *loopSuite) TestResizeVolumes(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0")
+	err := ioutil.WriteFile(fileName, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Truncate(fileName, 2*1024*1024)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.commands.expect("fallocate", "-l", "4MiB", fileName)
+	cmd := s.commands.expect("losetup", "-j", fileName)
+	cmd.respond("/dev/loop0: foo\n", nil)
+	s.commands.expect("losetup", "-c", "/dev/loop0")
+
+	resizer, ok := source.(storage.VolumeResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeVolumes([]storage.VolumeResizeParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Size:     4,
+	}, {
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Size:     1,
+	}, {
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "volume-1",
+		Size:     4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId: "volume-0",
+		Size:     4,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, "resizing volume 0: cannot shrink volume from 2MiB to 1MiB")
+	c.Assert(results[2].Error, gc.ErrorMatches, "resizing volume 1: reading loop backing file: .*")
+}
+
 func (s *loopSuite) TestDestroyVolumes(c *gc.C) {
 	source, _ := s.loopVolumeSource(c)
 	fileName := filepath.Join(s.storageDir, "volume-0")
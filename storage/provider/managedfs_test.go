@@ -112,6 +112,56 @@ func (s *managedfsSuite) TestCreateFilesystemsNoBlockDevice(c *gc.C) {
 	c.Assert(results[0].Error, gc.ErrorMatches, "backing-volume 0 is not yet attached")
 }
 
+func (s *managedfsSuite) TestResizeFilesystems(c *gc.C) {
+	source := s.initSource(c)
+	// The partition on sda is grown before the filesystem.
+	s.commands.expect("growpart", "/dev/sda", "1")
+	s.commands.expect("resize2fs", "/dev/sda1")
+	s.commands.expect("resize2fs", "/dev/xvdf1")
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       4,
+	}
+	s.blockDevices[names.NewVolumeTag("1")] = storage.BlockDevice{
+		DeviceName: "xvdf1",
+		Size:       6,
+	}
+	resizer, ok := source.(storage.FilesystemResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeFilesystems([]storage.FilesystemResizeParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		FilesystemId: "filesystem-0-0",
+		Volume:       names.NewVolumeTag("0"),
+		Size:         4,
+	}, {
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "filesystem-0-1",
+		Volume:       names.NewVolumeTag("1"),
+		Size:         6,
+	}, {
+		Filesystem:   names.NewFilesystemTag("0/2"),
+		FilesystemId: "filesystem-0-2",
+		Volume:       names.NewVolumeTag("2"),
+		Size:         8,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.ResizeFilesystemsResult{{
+		FilesystemInfo: &storage.FilesystemInfo{
+			FilesystemId: "filesystem-0-0",
+			Size:         4,
+		},
+	}, {
+		FilesystemInfo: &storage.FilesystemInfo{
+			FilesystemId: "filesystem-0-1",
+			Size:         6,
+		},
+	}, {
+		Error: results[2].Error,
+	}})
+	c.Assert(results[2].Error, gc.ErrorMatches, "backing-volume 2 is not yet attached")
+}
+
 func (s *managedfsSuite) TestAttachFilesystems(c *gc.C) {
 	s.testAttachFilesystems(c, false, false)
 }
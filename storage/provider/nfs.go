@@ -0,0 +1,333 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	NFSProviderType = storage.ProviderType("nfs")
+
+	// NFS provider config attributes.
+
+	// NFSServer is the host name or address of the NFS server.
+	NFSServer = "server"
+
+	// NFSExport is the absolute path of the export on the NFS server,
+	// under which a directory will be created for each filesystem.
+	NFSExport = "export"
+
+	// NFSShare, if specified, is the name of a directory under the
+	// export that is mounted for every filesystem created from the
+	// pool, so that all units using the pool share the same data.
+	// This is the only way to share NFS storage; charm storage
+	// declared as shared is not supported by Juju yet, and each
+	// unit still gets its own storage instance.
+	NFSShare = "share"
+)
+
+// nfsProviders create storage sources which provide access to
+// directories on a remote NFS server.
+type nfsProvider struct {
+	// run is a function type used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var (
+	_ storage.Provider           = (*nfsProvider)(nil)
+	_ storage.FilesystemImporter = (*nfsProvider)(nil)
+)
+
+// ValidateConfig is defined on the Provider interface.
+func (p *nfsProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newNFSConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// validateFullConfig validates a fully-constructed storage config,
+// combining the user-specified config and any internally specified
+// config.
+func (p *nfsProvider) validateFullConfig(cfg *storage.Config) error {
+	storageDir, ok := cfg.ValueString(storage.ConfigStorageDir)
+	if !ok || storageDir == "" {
+		return errors.New("storage directory not specified")
+	}
+	return nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *nfsProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
+	return nil, errors.NotSupportedf("volumes")
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *nfsProvider) FilesystemSource(environConfig *config.Config, sourceConfig *storage.Config) (storage.FilesystemSource, error) {
+	if err := p.validateFullConfig(sourceConfig); err != nil {
+		return nil, err
+	}
+	// storageDir is validated by validateFullConfig.
+	storageDir, _ := sourceConfig.ValueString(storage.ConfigStorageDir)
+	return &nfsFilesystemSource{
+		&osDirFuncs{p.run},
+		p.run,
+		storageDir,
+	}, nil
+}
+
+// Supports is defined on the Provider interface.
+func (*nfsProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindFilesystem
+}
+
+// Scope is defined on the Provider interface.
+func (*nfsProvider) Scope() storage.Scope {
+	// The filesystems are remote, but creating and mounting
+	// them must be done on the machine that they are attached
+	// to, so the provider is machine-scoped.
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*nfsProvider) Dynamic() bool {
+	return true
+}
+
+// ImportFilesystem is defined on the FilesystemImporter interface.
+//
+// An existing directory on the pool's NFS server may be imported if it
+// is directly under the pool's export, where the pool would have created
+// it. The directory's contents are not inspected, so its size is unknown.
+func (*nfsProvider) ImportFilesystem(cfg *storage.Config, filesystemId string) (*storage.FilesystemInfo, error) {
+	nfsConfig, err := newNFSConfig(cfg.Attrs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	server, remotePath, err := parseNFSFilesystemId(filesystemId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if server != nfsConfig.server {
+		return nil, errors.Errorf(
+			"NFS server %q does not match pool server %q",
+			server, nfsConfig.server,
+		)
+	}
+	if path.Clean(remotePath) != remotePath || path.Dir(remotePath) != nfsConfig.export {
+		return nil, errors.Errorf(
+			"NFS path %q is not a directory under pool export %q",
+			remotePath, nfsConfig.export,
+		)
+	}
+	return &storage.FilesystemInfo{FilesystemId: filesystemId}, nil
+}
+
+// nfsConfig is the validated configuration of an NFS storage pool.
+type nfsConfig struct {
+	server string
+	export string
+	share  string
+}
+
+func newNFSConfig(attrs map[string]interface{}) (*nfsConfig, error) {
+	var cfg nfsConfig
+	for _, attr := range []struct {
+		name     string
+		value    *string
+		required bool
+	}{
+		{NFSServer, &cfg.server, true},
+		{NFSExport, &cfg.export, true},
+		{NFSShare, &cfg.share, false},
+	} {
+		value, ok := attrs[attr.name]
+		if !ok || value == "" {
+			if attr.required {
+				return nil, errors.Errorf("%q must be specified", attr.name)
+			}
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.Errorf("expected string for %q, got %T", attr.name, value)
+		}
+		*attr.value = s
+	}
+	if strings.Contains(cfg.server, ":") {
+		return nil, errors.NotValidf("NFS server %q", cfg.server)
+	}
+	if !path.IsAbs(cfg.export) {
+		return nil, errors.Errorf("NFS export %q must be an absolute path", cfg.export)
+	}
+	cfg.export = path.Clean(cfg.export)
+	if cfg.share != "" && (strings.Contains(cfg.share, "/") || cfg.share == "." || cfg.share == "..") {
+		return nil, errors.NotValidf("NFS share %q", cfg.share)
+	}
+	return &cfg, nil
+}
+
+type nfsFilesystemSource struct {
+	dirFuncs   dirFuncs
+	run        runCommandFunc
+	storageDir string
+}
+
+var _ storage.FilesystemSource = (*nfsFilesystemSource)(nil)
+
+// ValidateFilesystemParams is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
+	_, err := newNFSConfig(params.Attributes)
+	return errors.Trace(err)
+}
+
+// CreateFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) CreateFilesystems(args []storage.FilesystemParams) ([]storage.CreateFilesystemsResult, error) {
+	results := make([]storage.CreateFilesystemsResult, len(args))
+	for i, arg := range args {
+		filesystem, err := s.createFilesystem(arg)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].Filesystem = filesystem
+	}
+	return results, nil
+}
+
+func (s *nfsFilesystemSource) createFilesystem(params storage.FilesystemParams) (*storage.Filesystem, error) {
+	cfg, err := newNFSConfig(params.Attributes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The filesystem ID is the NFS mount source; the directory is
+	// created on the server when the filesystem is first attached.
+	dir := cfg.share
+	if dir == "" {
+		dir = params.Tag.String()
+	}
+	info := storage.FilesystemInfo{
+		FilesystemId: cfg.server + ":" + path.Join(cfg.export, dir),
+		Size:         params.Size,
+	}
+	return &storage.Filesystem{params.Tag, params.Volume, info}, nil
+}
+
+// DestroyFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) DestroyFilesystems(filesystemIds []string) ([]error, error) {
+	// DestroyFilesystems is a no-op; the contents of the
+	// filesystem are left on the NFS server, to be removed
+	// by the server's administrator if desired.
+	return make([]error, len(filesystemIds)), nil
+}
+
+// AttachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) AttachFilesystems(args []storage.FilesystemAttachmentParams) ([]storage.AttachFilesystemsResult, error) {
+	results := make([]storage.AttachFilesystemsResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachFilesystem(arg)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].FilesystemAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *nfsFilesystemSource) attachFilesystem(arg storage.FilesystemAttachmentParams) (*storage.FilesystemAttachment, error) {
+	mountPoint := arg.Path
+	if mountPoint == "" {
+		return nil, errNoMountPoint
+	}
+	server, remotePath, err := parseNFSFilesystemId(arg.FilesystemId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := ensureDir(s.dirFuncs, mountPoint); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Check if the mount already exists.
+	source, err := s.dirFuncs.mountPointSource(mountPoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if source != arg.FilesystemId {
+		if err := ensureEmptyDir(s.dirFuncs, mountPoint); err != nil {
+			return nil, err
+		}
+		// Each filesystem is a directory under the export;
+		// create it if this is the first attachment.
+		if err := s.ensureRemoteDir(arg.Filesystem, server, remotePath); err != nil {
+			return nil, errors.Annotate(err, "cannot create directory on NFS server")
+		}
+		args := []string{"-t", "nfs", arg.FilesystemId, mountPoint}
+		if arg.ReadOnly {
+			args = append(args, "-o", "ro")
+		}
+		if _, err := s.run("mount", args...); err != nil {
+			os.Remove(mountPoint)
+			return nil, errors.Annotate(err, "cannot mount NFS filesystem")
+		}
+	}
+
+	return &storage.FilesystemAttachment{
+		arg.Filesystem,
+		arg.Machine,
+		storage.FilesystemAttachmentInfo{
+			Path:     mountPoint,
+			ReadOnly: arg.ReadOnly,
+		},
+	}, nil
+}
+
+// ensureRemoteDir ensures that the directory backing the filesystem
+// exists on the NFS server, by temporarily mounting its parent in the
+// storage directory.
+func (s *nfsFilesystemSource) ensureRemoteDir(tag names.FilesystemTag, server, remotePath string) error {
+	stagingDir := filepath.Join(s.storageDir, "nfs", tag.String())
+	if err := ensureDir(s.dirFuncs, stagingDir); err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(stagingDir)
+	parent, dir := path.Split(remotePath)
+	if _, err := s.run("mount", "-t", "nfs", server+":"+path.Clean(parent), stagingDir); err != nil {
+		return errors.Annotate(err, "cannot mount NFS export")
+	}
+	err := s.dirFuncs.mkDirAll(filepath.Join(stagingDir, dir), 0755)
+	if _, umountErr := s.run("umount", stagingDir); umountErr != nil && err == nil {
+		err = errors.Annotate(umountErr, "cannot unmount NFS export")
+	}
+	return errors.Trace(err)
+}
+
+// DetachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) DetachFilesystems(args []storage.FilesystemAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := maybeUnmount(s.run, s.dirFuncs, arg.Path); err != nil {
+			results[i] = err
+		}
+	}
+	return results, nil
+}
+
+// parseNFSFilesystemId parses a filesystem ID of the form
+// "server:/path", as returned by CreateFilesystems.
+func parseNFSFilesystemId(filesystemId string) (server, remotePath string, _ error) {
+	i := strings.Index(filesystemId, ":")
+	if i <= 0 || !path.IsAbs(filesystemId[i+1:]) {
+		return "", "", errors.NotValidf("NFS filesystem ID %q", filesystemId)
+	}
+	return filesystemId[:i], filesystemId[i+1:], nil
+}
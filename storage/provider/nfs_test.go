@@ -0,0 +1,320 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&nfsSuite{})
+
+type nfsSuite struct {
+	testing.BaseSuite
+	storageDir   string
+	commands     *mockRunCommand
+	mockDirFuncs *provider.MockDirFuncs
+}
+
+func (s *nfsSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Tests relevant only on *nix systems")
+	}
+	s.BaseSuite.SetUpTest(c)
+	s.storageDir = c.MkDir()
+}
+
+func (s *nfsSuite) TearDownTest(c *gc.C) {
+	if s.commands != nil {
+		s.commands.assertDrained()
+	}
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *nfsSuite) nfsProvider(c *gc.C) storage.Provider {
+	s.commands = &mockRunCommand{c: c}
+	return provider.NFSProvider(s.commands.run)
+}
+
+func (s *nfsSuite) nfsFilesystemSource(c *gc.C) storage.FilesystemSource {
+	s.commands = &mockRunCommand{c: c}
+	source, mockDirFuncs := provider.NFSFilesystemSource(s.storageDir, s.commands.run)
+	s.mockDirFuncs = mockDirFuncs
+	return source
+}
+
+var nfsAttrs = map[string]interface{}{
+	"server": "nfs.example.com",
+	"export": "/srv/juju",
+}
+
+func (s *nfsSuite) TestFilesystemSource(c *gc.C) {
+	p := s.nfsProvider(c)
+	cfg, err := storage.NewConfig("name", provider.NFSProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = p.FilesystemSource(nil, cfg)
+	c.Assert(err, gc.ErrorMatches, "storage directory not specified")
+	cfg, err = storage.NewConfig("name", provider.NFSProviderType, map[string]interface{}{
+		"storage-dir": c.MkDir(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = p.FilesystemSource(nil, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *nfsSuite) TestValidateConfig(c *gc.C) {
+	p := s.nfsProvider(c)
+	cfg, err := storage.NewConfig("name", provider.NFSProviderType, nfsAttrs)
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *nfsSuite) TestValidateConfigInvalid(c *gc.C) {
+	p := s.nfsProvider(c)
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"export": "/srv/juju"},
+		err:   `"server" must be specified`,
+	}, {
+		attrs: map[string]interface{}{"server": "nfs.example.com"},
+		err:   `"export" must be specified`,
+	}, {
+		attrs: map[string]interface{}{"server": 123, "export": "/srv/juju"},
+		err:   `expected string for "server", got int`,
+	}, {
+		attrs: map[string]interface{}{"server": "nfs:2049", "export": "/srv/juju"},
+		err:   `NFS server "nfs:2049" not valid`,
+	}, {
+		attrs: map[string]interface{}{"server": "nfs.example.com", "export": "srv/juju"},
+		err:   `NFS export "srv/juju" must be an absolute path`,
+	}, {
+		attrs: map[string]interface{}{"server": "nfs.example.com", "export": "/srv/juju", "share": "a/b"},
+		err:   `NFS share "a/b" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("name", provider.NFSProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *nfsSuite) TestSupports(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsFalse)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestScope(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+}
+
+func (s *nfsSuite) TestImportFilesystem(c *gc.C) {
+	p := s.nfsProvider(c)
+	cfg, err := storage.NewConfig("name", provider.NFSProviderType, nfsAttrs)
+	c.Assert(err, jc.ErrorIsNil)
+	importer, ok := p.(storage.FilesystemImporter)
+	c.Assert(ok, jc.IsTrue)
+	info, err := importer.ImportFilesystem(cfg, "nfs.example.com:/srv/juju/data")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, &storage.FilesystemInfo{
+		FilesystemId: "nfs.example.com:/srv/juju/data",
+	})
+}
+
+func (s *nfsSuite) TestImportFilesystemInvalid(c *gc.C) {
+	p := s.nfsProvider(c)
+	cfg, err := storage.NewConfig("name", provider.NFSProviderType, nfsAttrs)
+	c.Assert(err, jc.ErrorIsNil)
+	importer := p.(storage.FilesystemImporter)
+	for i, test := range []struct {
+		id  string
+		err string
+	}{{
+		id:  "/srv/juju/data",
+		err: `NFS filesystem ID "/srv/juju/data" not valid`,
+	}, {
+		id:  "other.example.com:/srv/juju/data",
+		err: `NFS server "other.example.com" does not match pool server "nfs.example.com"`,
+	}, {
+		id:  "nfs.example.com:/srv/other/data",
+		err: `NFS path "/srv/other/data" is not a directory under pool export "/srv/juju"`,
+	}, {
+		id:  "nfs.example.com:/srv/juju/a/b",
+		err: `NFS path "/srv/juju/a/b" is not a directory under pool export "/srv/juju"`,
+	}, {
+		id:  "nfs.example.com:/srv/juju/../data",
+		err: `NFS path "/srv/juju/../data" is not a directory under pool export "/srv/juju"`,
+	}} {
+		c.Logf("test %d: %s", i, test.id)
+		_, err := importer.ImportFilesystem(cfg, test.id)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *nfsSuite) TestCreateFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	results, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag:        names.NewFilesystemTag("0/1"),
+		Size:       1024,
+		Attributes: nfsAttrs,
+	}, {
+		Tag:  names.NewFilesystemTag("0/2"),
+		Size: 1024,
+		Attributes: map[string]interface{}{
+			"server": "nfs.example.com",
+			"export": "/srv/juju/",
+			"share":  "shared",
+		},
+	}, {
+		Tag:  names.NewFilesystemTag("0/3"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0], jc.DeepEquals, storage.CreateFilesystemsResult{
+		Filesystem: &storage.Filesystem{
+			Tag: names.NewFilesystemTag("0/1"),
+			FilesystemInfo: storage.FilesystemInfo{
+				FilesystemId: "nfs.example.com:/srv/juju/filesystem-0-1",
+				Size:         1024,
+			},
+		},
+	})
+	c.Assert(results[1], jc.DeepEquals, storage.CreateFilesystemsResult{
+		Filesystem: &storage.Filesystem{
+			Tag: names.NewFilesystemTag("0/2"),
+			FilesystemInfo: storage.FilesystemInfo{
+				FilesystemId: "nfs.example.com:/srv/juju/shared",
+				Size:         1024,
+			},
+		},
+	})
+	c.Assert(results[2].Error, gc.ErrorMatches, `"server" must be specified`)
+}
+
+func (s *nfsSuite) TestAttachFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	stagingDir := filepath.Join(s.storageDir, "nfs", "filesystem-0-1")
+
+	cmd := s.commands.expect("df", "--output=source", "/srv/data")
+	cmd.respond("header\n/dev/sda1", nil)
+	s.commands.expect("mount", "-t", "nfs", "nfs.example.com:/srv/juju", stagingDir)
+	s.commands.expect("umount", stagingDir)
+	s.commands.expect("mount", "-t", "nfs", "nfs.example.com:/srv/juju/filesystem-0-1", "/srv/data", "-o", "ro")
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "nfs.example.com:/srv/juju/filesystem-0-1",
+		Path:         "/srv/data",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachFilesystemsResult{{
+		FilesystemAttachment: &storage.FilesystemAttachment{
+			Filesystem: names.NewFilesystemTag("0/1"),
+			Machine:    names.NewMachineTag("0"),
+			FilesystemAttachmentInfo: storage.FilesystemAttachmentInfo{
+				Path:     "/srv/data",
+				ReadOnly: true,
+			},
+		},
+	}})
+	c.Assert(s.mockDirFuncs.Dirs.Contains(filepath.Join(stagingDir, "filesystem-0-1")), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestAttachFilesystemsAlreadyMounted(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	cmd := s.commands.expect("df", "--output=source", "exists")
+	cmd.respond("header\nnfs.example.com:/srv/juju/shared", nil)
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "nfs.example.com:/srv/juju/shared",
+		Path:         "exists",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachFilesystemsResult{{
+		FilesystemAttachment: &storage.FilesystemAttachment{
+			Filesystem: names.NewFilesystemTag("0/1"),
+			FilesystemAttachmentInfo: storage.FilesystemAttachmentInfo{
+				Path: "exists",
+			},
+		},
+	}})
+}
+
+func (s *nfsSuite) TestAttachFilesystemsExportMountFails(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	stagingDir := filepath.Join(s.storageDir, "nfs", "filesystem-0-1")
+
+	cmd := s.commands.expect("df", "--output=source", "/srv/data")
+	cmd.respond("header\n/dev/sda1", nil)
+	cmd = s.commands.expect("mount", "-t", "nfs", "nfs.example.com:/srv/juju", stagingDir)
+	cmd.respond("", errors.New("access denied"))
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "nfs.example.com:/srv/juju/filesystem-0-1",
+		Path:         "/srv/data",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		"cannot create directory on NFS server: cannot mount NFS export: access denied",
+	)
+}
+
+func (s *nfsSuite) TestAttachFilesystemsInvalidFilesystemId(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "filesystem-0-1",
+		Path:         "/srv/data",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, `NFS filesystem ID "filesystem-0-1" not valid`)
+}
+
+func (s *nfsSuite) TestAttachFilesystemsNoPathSpecified(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "nfs.example.com:/srv/juju/filesystem-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "filesystem mount point not specified")
+}
+
+func (s *nfsSuite) TestDestroyFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	errs, err := source.DestroyFilesystems([]string{"nfs.example.com:/srv/juju/filesystem-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *nfsSuite) TestDetachFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	testDetachFilesystems(c, s.commands, source, true)
+}
+
+func (s *nfsSuite) TestDetachFilesystemsUnattached(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	testDetachFilesystems(c, s.commands, source, false)
+}
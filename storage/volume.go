@@ -66,3 +66,17 @@ type VolumeAttachmentInfo struct {
 	// ReadOnly signifies whether the volume is read only or writable.
 	ReadOnly bool
 }
+
+// VolumeSnapshot identifies and describes a point-in-time snapshot
+// of a volume.
+type VolumeSnapshot struct {
+	// Volume is the unique tag assigned by Juju for the volume
+	// that this snapshot was taken of.
+	Volume names.VolumeTag
+
+	// SnapshotId is a unique provider-supplied ID for the snapshot.
+	SnapshotId string
+
+	// Size is the size of the snapshotted volume, in MiB.
+	Size uint64
+}
@@ -91,6 +91,24 @@ func AreUpgradesDefined(from version.Number) bool {
 	return newUpgradeOpsIterator(from).Next() || newStateUpgradeOpsIterator(from).Next()
 }
 
+// StepDescriptions returns the descriptions of the upgrade steps
+// defined by this version of Juju that run when upgrading from one
+// version to another, in the order they run.
+func StepDescriptions(from, to version.Number) []string {
+	var descriptions []string
+	for _, ops := range []*opsIterator{
+		newOpsIterator(from, to, stateUpgradeOperations()),
+		newOpsIterator(from, to, upgradeOperations()),
+	} {
+		for ops.Next() {
+			for _, step := range ops.Get().Steps() {
+				descriptions = append(descriptions, step.Description())
+			}
+		}
+	}
+	return descriptions
+}
+
 // PerformUpgrade runs the business logic needed to upgrade the current "from" version to this
 // version of Juju on the "target" type of machine.
 func PerformUpgrade(from version.Number, targets []Target, context Context) error {
@@ -370,6 +370,24 @@ func (s *upgradeSuite) TestAreUpgradesDefined(c *gc.C) {
 	}
 }
 
+func (s *upgradeSuite) TestStepDescriptions(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	descriptions := upgrades.StepDescriptions(version.MustParse("1.20.0"), version.MustParse("1.22.0"))
+	c.Assert(descriptions, jc.DeepEquals, []string{
+		"state step 1 - 1.21.0",
+		"state step 2 - 1.21.0",
+		"state step 1 - 1.22.0",
+		"state step 2 - 1.22.0",
+		"step 1 - 1.21.0",
+		"step 1 - 1.22.0",
+		"step 2 - 1.22.0",
+	})
+
+	descriptions = upgrades.StepDescriptions(version.MustParse("1.22.0"), version.MustParse("1.22.1"))
+	c.Assert(descriptions, gc.HasLen, 0)
+}
+
 type upgradeTest struct {
 	about         string
 	fromVersion   string
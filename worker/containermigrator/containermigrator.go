@@ -0,0 +1,223 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package containermigrator implements the worker that moves containers
+// between host machines. On the source host it exports the stopped
+// container's filesystem to the API server; on the target host it
+// imports and starts the container. Should the target fail, the source
+// starts the original container again.
+package containermigrator
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.containermigrator")
+
+// Status values reported to the API server; they mirror the
+// migration status values in state.
+const (
+	statusPending    = "pending"
+	statusExported   = "exported"
+	statusImported   = "imported"
+	statusFailed     = "failed"
+	statusRolledBack = "rolled-back"
+	statusDone       = "done"
+)
+
+// MigratorAPI holds the API methods used by the worker.
+type MigratorAPI interface {
+	WatchContainerMigrations() (watcher.NotifyWatcher, error)
+	ContainerMigrations() ([]params.ContainerMigration, error)
+	SetStatus(container names.MachineTag, status, message string) error
+	UploadRootfs(container names.MachineTag, archive io.Reader, length int64) error
+	DownloadRootfs(container names.MachineTag) (io.ReadCloser, int64, error)
+}
+
+// ContainerManager holds the methods of a container manager used by
+// the worker.
+type ContainerManager interface {
+	container.Migrator
+	DestroyContainer(id instance.Id) error
+}
+
+// Config holds the dependencies of the worker.
+type Config struct {
+	// API is used to follow and record the progress of migrations.
+	API MigratorAPI
+
+	// Managers export and import the containers of each type that
+	// can be migrated to and from this host.
+	Managers map[instance.ContainerType]ContainerManager
+
+	// HostTag identifies the machine the worker runs on.
+	HostTag names.MachineTag
+
+	// Networks holds the network imported containers of each type
+	// are connected to.
+	Networks map[instance.ContainerType]*container.NetworkConfig
+}
+
+// Validate returns an error if the config cannot be used to start the
+// worker.
+func (config Config) Validate() error {
+	if config.API == nil {
+		return errors.NotValidf("nil API")
+	}
+	if len(config.Managers) == 0 {
+		return errors.NotValidf("no Managers")
+	}
+	if config.HostTag.Id() == "" {
+		return errors.NotValidf("empty HostTag")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that migrates containers to and from the
+// configured host machine.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker.NewNotifyWorker(&handler{config}), nil
+}
+
+type handler struct {
+	config Config
+}
+
+// SetUp is part of the worker.NotifyWatchHandler interface.
+func (h *handler) SetUp() (watcher.NotifyWatcher, error) {
+	return h.config.API.WatchContainerMigrations()
+}
+
+// TearDown is part of the worker.NotifyWatchHandler interface.
+func (h *handler) TearDown() error {
+	return nil
+}
+
+// Handle is part of the worker.NotifyWatchHandler interface.
+func (h *handler) Handle(_ <-chan struct{}) error {
+	migrations, err := h.config.API.ContainerMigrations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, migration := range migrations {
+		if err := h.handleMigration(migration); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// handleMigration advances a single migration as far as this host can
+// take it. Failures to move the container are recorded against the
+// migration; only failures to talk to the API server are returned.
+func (h *handler) handleMigration(migration params.ContainerMigration) error {
+	tag, err := names.ParseMachineTag(migration.ContainerTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	id := instance.Id(migration.InstanceId)
+	isSource := migration.SourceHostTag == h.config.HostTag.String()
+	isTarget := migration.TargetHostTag == h.config.HostTag.String()
+	if !isSource && !isTarget {
+		return nil
+	}
+	ctype := instance.ContainerType(migration.ContainerType)
+	manager, ok := h.config.Managers[ctype]
+	if !ok {
+		if migration.Status == statusPending || migration.Status == statusExported {
+			message := fmt.Sprintf("machine %s cannot migrate %s containers", h.config.HostTag.Id(), ctype)
+			logger.Errorf("cannot migrate container %s: %s", tag.Id(), message)
+			return h.config.API.SetStatus(tag, statusFailed, message)
+		}
+		return errors.Errorf("cannot manage %s container %s", ctype, tag.Id())
+	}
+
+	switch {
+	case isSource && migration.Status == statusPending:
+		logger.Infof("exporting container %s", tag.Id())
+		if err := h.export(manager, tag, id); err != nil {
+			logger.Errorf("cannot export container %s: %v", tag.Id(), err)
+			return h.config.API.SetStatus(tag, statusFailed, err.Error())
+		}
+		return h.config.API.SetStatus(tag, statusExported, "")
+
+	case isTarget && migration.Status == statusExported:
+		logger.Infof("importing container %s", tag.Id())
+		if err := h.importContainer(manager, h.config.Networks[ctype], tag, id); err != nil {
+			logger.Errorf("cannot import container %s: %v", tag.Id(), err)
+			return h.config.API.SetStatus(tag, statusFailed, err.Error())
+		}
+		return h.config.API.SetStatus(tag, statusImported, "")
+
+	case isSource && migration.Status == statusFailed:
+		logger.Infof("restarting container %s after failed migration: %s", tag.Id(), migration.Message)
+		if err := manager.StartContainer(id); err != nil {
+			// Leave the migration failed so that the restart is
+			// retried when the worker is restarted.
+			return errors.Annotatef(err, "cannot restart container %s", tag.Id())
+		}
+		return h.config.API.SetStatus(tag, statusRolledBack, "")
+
+	case isSource && migration.Status == statusImported:
+		logger.Infof("removing container %s after migration", tag.Id())
+		if err := manager.DestroyContainer(id); err != nil {
+			return errors.Annotatef(err, "cannot remove migrated container %s", tag.Id())
+		}
+		return h.config.API.SetStatus(tag, statusDone, "")
+	}
+	return nil
+}
+
+// export stops the container and uploads an archive of its filesystem.
+// Should it fail, the container is restarted once the failure has been
+// recorded and reported back to this host.
+func (h *handler) export(manager ContainerManager, tag names.MachineTag, id instance.Id) error {
+	archive, err := ioutil.TempFile("", "juju-container-migration")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if err := manager.ExportContainer(id, archive); err != nil {
+		return errors.Trace(err)
+	}
+	length, err := archive.Seek(0, os.SEEK_CUR)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := archive.Seek(0, os.SEEK_SET); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(h.config.API.UploadRootfs(tag, archive, length))
+}
+
+// importContainer downloads the archive of the container's filesystem
+// and starts the container on this host.
+func (h *handler) importContainer(manager ContainerManager, network *container.NetworkConfig, tag names.MachineTag, id instance.Id) error {
+	archive, _, err := h.config.API.DownloadRootfs(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	if _, err := manager.ImportContainer(id, archive, network); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
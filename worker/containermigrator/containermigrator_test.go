@@ -0,0 +1,237 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package containermigrator_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/containermigrator"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	calls   chan string
+	api     *mockAPI
+	manager *mockManager
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.calls = make(chan string, 10)
+	s.api = &mockAPI{
+		watcher: newMockNotifyWatcher(),
+		calls:   s.calls,
+	}
+	s.manager = &mockManager{
+		calls:   s.calls,
+		archive: "rootfs",
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C, hostId string, migration params.ContainerMigration) worker.Worker {
+	s.api.migrations = []params.ContainerMigration{migration}
+	w, err := containermigrator.NewWorker(containermigrator.Config{
+		API: s.api,
+		Managers: map[instance.ContainerType]containermigrator.ContainerManager{
+			instance.LXC: s.manager,
+		},
+		HostTag: names.NewMachineTag(hostId),
+		Networks: map[instance.ContainerType]*container.NetworkConfig{
+			instance.LXC: container.BridgeNetworkConfig("br0", 0, nil),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *WorkerSuite) assertCalls(c *gc.C, expect ...string) {
+	for _, call := range expect {
+		select {
+		case actual := <-s.calls:
+			c.Assert(actual, gc.Equals, call)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for %s", call)
+		}
+	}
+	select {
+	case actual := <-s.calls:
+		c.Fatalf("unexpected %s", actual)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func migration(status string) params.ContainerMigration {
+	return params.ContainerMigration{
+		ContainerTag:  "machine-0-lxc-0",
+		ContainerType: "lxc",
+		InstanceId:    "juju-machine-0-lxc-0",
+		SourceHostTag: "machine-0",
+		TargetHostTag: "machine-1",
+		Status:        status,
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	managers := map[instance.ContainerType]containermigrator.ContainerManager{instance.LXC: s.manager}
+	_, err := containermigrator.NewWorker(containermigrator.Config{Managers: managers})
+	c.Assert(err, gc.ErrorMatches, "nil API not valid")
+	_, err = containermigrator.NewWorker(containermigrator.Config{API: s.api})
+	c.Assert(err, gc.ErrorMatches, "no Managers not valid")
+	_, err = containermigrator.NewWorker(containermigrator.Config{API: s.api, Managers: managers})
+	c.Assert(err, gc.ErrorMatches, "empty HostTag not valid")
+}
+
+func (s *WorkerSuite) TestSourceExports(c *gc.C) {
+	w := s.startWorker(c, "0", migration("pending"))
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"ExportContainer juju-machine-0-lxc-0",
+		"UploadRootfs 0/lxc/0 6",
+		"SetStatus 0/lxc/0 exported ",
+	)
+	c.Assert(string(s.api.archive), gc.Equals, "rootfs")
+}
+
+func (s *WorkerSuite) TestSourceExportFails(c *gc.C) {
+	s.api.uploadErr = errors.New("boom")
+	w := s.startWorker(c, "0", migration("pending"))
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"ExportContainer juju-machine-0-lxc-0",
+		"UploadRootfs 0/lxc/0 6",
+		"SetStatus 0/lxc/0 failed boom",
+	)
+}
+
+func (s *WorkerSuite) TestTargetImports(c *gc.C) {
+	s.api.archive = []byte("rootfs")
+	w := s.startWorker(c, "1", migration("exported"))
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"DownloadRootfs 0/lxc/0",
+		"ImportContainer juju-machine-0-lxc-0 rootfs br0",
+		"SetStatus 0/lxc/0 imported ",
+	)
+}
+
+func (s *WorkerSuite) TestTargetImportFails(c *gc.C) {
+	s.api.archive = []byte("rootfs")
+	s.manager.importErr = errors.New("boom")
+	w := s.startWorker(c, "1", migration("exported"))
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"DownloadRootfs 0/lxc/0",
+		"ImportContainer juju-machine-0-lxc-0 rootfs br0",
+		"SetStatus 0/lxc/0 failed boom",
+	)
+}
+
+func (s *WorkerSuite) TestSourceRollsBack(c *gc.C) {
+	w := s.startWorker(c, "0", migration("failed"))
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"StartContainer juju-machine-0-lxc-0",
+		"SetStatus 0/lxc/0 rolled-back ",
+	)
+}
+
+func (s *WorkerSuite) TestSourceRemovesImported(c *gc.C) {
+	w := s.startWorker(c, "0", migration("imported"))
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"DestroyContainer juju-machine-0-lxc-0",
+		"SetStatus 0/lxc/0 done ",
+	)
+}
+
+func (s *WorkerSuite) TestIgnoresOtherHostsWork(c *gc.C) {
+	w := s.startWorker(c, "1", migration("pending"))
+	defer worker.Stop(w)
+	s.assertCalls(c, "WatchContainerMigrations", "ContainerMigrations")
+
+	s.api.mu.Lock()
+	s.api.migrations = []params.ContainerMigration{migration("imported")}
+	s.api.mu.Unlock()
+	s.api.watcher.Change()
+	s.assertCalls(c, "ContainerMigrations")
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func kvmMigration(status string) params.ContainerMigration {
+	return params.ContainerMigration{
+		ContainerTag:  "machine-0-kvm-0",
+		ContainerType: "kvm",
+		InstanceId:    "juju-machine-0-kvm-0",
+		SourceHostTag: "machine-0",
+		TargetHostTag: "machine-1",
+		Status:        status,
+	}
+}
+
+func (s *WorkerSuite) TestUsesManagerForContainerType(c *gc.C) {
+	kvmCalls := make(chan string, 10)
+	kvmManager := &mockManager{calls: kvmCalls, archive: "disks"}
+	s.api.archive = []byte("disks")
+	s.api.migrations = []params.ContainerMigration{kvmMigration("exported")}
+	w, err := containermigrator.NewWorker(containermigrator.Config{
+		API: s.api,
+		Managers: map[instance.ContainerType]containermigrator.ContainerManager{
+			instance.LXC: s.manager,
+			instance.KVM: kvmManager,
+		},
+		HostTag: names.NewMachineTag("1"),
+		Networks: map[instance.ContainerType]*container.NetworkConfig{
+			instance.LXC: container.BridgeNetworkConfig("lxcbr0", 0, nil),
+			instance.KVM: container.BridgeNetworkConfig("virbr0", 0, nil),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"DownloadRootfs 0/kvm/0",
+		"SetStatus 0/kvm/0 imported ",
+	)
+	select {
+	case call := <-kvmCalls:
+		c.Assert(call, gc.Equals, "ImportContainer juju-machine-0-kvm-0 disks virbr0")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for ImportContainer")
+	}
+}
+
+func (s *WorkerSuite) TestFailsUnsupportedContainerType(c *gc.C) {
+	w := s.startWorker(c, "1", kvmMigration("exported"))
+	defer worker.Stop(w)
+	s.assertCalls(c,
+		"WatchContainerMigrations",
+		"ContainerMigrations",
+		"SetStatus 0/kvm/0 failed machine 1 cannot migrate kvm containers",
+	)
+}
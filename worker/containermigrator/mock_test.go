@@ -0,0 +1,133 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package containermigrator_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+)
+
+// mockAPI implements containermigrator.MigratorAPI, reporting the
+// calls made to it on the calls channel.
+type mockAPI struct {
+	mu         sync.Mutex
+	watcher    *mockNotifyWatcher
+	calls      chan string
+	migrations []params.ContainerMigration
+	archive    []byte
+	uploadErr  error
+}
+
+func (m *mockAPI) WatchContainerMigrations() (watcher.NotifyWatcher, error) {
+	m.calls <- "WatchContainerMigrations"
+	return m.watcher, nil
+}
+
+func (m *mockAPI) ContainerMigrations() ([]params.ContainerMigration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls <- "ContainerMigrations"
+	return m.migrations, nil
+}
+
+func (m *mockAPI) SetStatus(tag names.MachineTag, status, message string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls <- fmt.Sprintf("SetStatus %s %s %s", tag.Id(), status, message)
+	return nil
+}
+
+func (m *mockAPI) UploadRootfs(tag names.MachineTag, archive io.Reader, length int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return err
+	}
+	if m.uploadErr == nil {
+		m.archive = data
+	}
+	m.calls <- fmt.Sprintf("UploadRootfs %s %d", tag.Id(), length)
+	return m.uploadErr
+}
+
+func (m *mockAPI) DownloadRootfs(tag names.MachineTag) (io.ReadCloser, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls <- fmt.Sprintf("DownloadRootfs %s", tag.Id())
+	return ioutil.NopCloser(bytes.NewReader(m.archive)), int64(len(m.archive)), nil
+}
+
+// mockManager implements containermigrator.ContainerManager, reporting
+// the calls made to it on the calls channel.
+type mockManager struct {
+	calls     chan string
+	archive   string
+	importErr error
+}
+
+func (m *mockManager) ExportContainer(id instance.Id, w io.Writer) error {
+	m.calls <- fmt.Sprintf("ExportContainer %s", id)
+	_, err := io.WriteString(w, m.archive)
+	return err
+}
+
+func (m *mockManager) ImportContainer(id instance.Id, r io.Reader, network *container.NetworkConfig) (instance.Instance, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	m.calls <- fmt.Sprintf("ImportContainer %s %s %s", id, data, network.Device)
+	if m.importErr != nil {
+		return nil, m.importErr
+	}
+	return nil, nil
+}
+
+func (m *mockManager) StartContainer(id instance.Id) error {
+	m.calls <- fmt.Sprintf("StartContainer %s", id)
+	return nil
+}
+
+func (m *mockManager) DestroyContainer(id instance.Id) error {
+	m.calls <- fmt.Sprintf("DestroyContainer %s", id)
+	return nil
+}
+
+type mockNotifyWatcher struct {
+	watcher.NotifyWatcher
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	m := &mockNotifyWatcher{changes: make(chan struct{}, 1)}
+	m.Change()
+	return m
+}
+
+func (m *mockNotifyWatcher) Err() error {
+	return nil
+}
+
+func (m *mockNotifyWatcher) Changes() <-chan struct{} {
+	return m.changes
+}
+
+func (m *mockNotifyWatcher) Stop() error {
+	return nil
+}
+
+func (m *mockNotifyWatcher) Change() {
+	m.changes <- struct{}{}
+}
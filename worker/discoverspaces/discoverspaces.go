@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package discoverspaces
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.discoverspaces")
+
+// refreshPeriod is how often the provider is checked for new subnets.
+const refreshPeriod = 15 * time.Minute
+
+// DiscoverSpacesAPI defines the API methods used by the worker.
+type DiscoverSpacesAPI interface {
+	// DiscoverSpaces returns the subnets known to the provider but
+	// not to Juju, grouped by their proposed spaces, importing them
+	// when importSubnets is true.
+	DiscoverSpaces(importSubnets bool) ([]params.Space, error)
+}
+
+// NewWorker returns a worker that periodically imports any subnets
+// created in the provider since the last check, along with any new
+// spaces they belong to. Nothing is imported unless the environment's
+// auto-import-subnets setting is true; otherwise subnets are only
+// imported explicitly, with "juju space discover --import".
+func NewWorker(api DiscoverSpacesAPI, configGetter worker.EnvironConfigGetter) worker.Worker {
+	f := func(stop <-chan struct{}) error {
+		envConfig, err := configGetter.EnvironConfig()
+		if err != nil {
+			logger.Warningf("cannot read environment config: %v - will retry later", err)
+			return nil
+		}
+		if autoImport, _ := envConfig.AutoImportSubnets(); !autoImport {
+			logger.Tracef("not importing subnets - auto-import-subnets is disabled")
+			return nil
+		}
+		spaces, err := api.DiscoverSpaces(true)
+		if params.IsCodeNotSupported(err) {
+			logger.Debugf("provider does not support subnet discovery: %v", err)
+			return nil
+		} else if err != nil {
+			logger.Warningf("cannot import subnets: %v - will retry later", err)
+			return nil
+		}
+		for _, space := range spaces {
+			for _, subnet := range space.Subnets {
+				logger.Infof("imported subnet %q into space %q", subnet.CIDR, space.Name)
+			}
+		}
+		return nil
+	}
+	return worker.NewPeriodicWorker(f, refreshPeriod, worker.NewTimer)
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package discoverspaces_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/discoverspaces"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+type mockAPI struct {
+	calls chan bool
+	err   error
+}
+
+func (m *mockAPI) DiscoverSpaces(importSubnets bool) ([]params.Space, error) {
+	m.calls <- importSubnets
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []params.Space{{
+		Name:    "internal",
+		Subnets: []params.Subnet{{CIDR: "10.20.0.0/24"}},
+	}}, nil
+}
+
+type mockConfigGetter struct {
+	config *config.Config
+}
+
+func (m *mockConfigGetter) EnvironConfig() (*config.Config, error) {
+	return m.config, nil
+}
+
+func autoImportConfig(c *gc.C, autoImport bool) *mockConfigGetter {
+	return &mockConfigGetter{coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+		"auto-import-subnets": autoImport,
+	})}
+}
+
+func (s *WorkerSuite) assertImports(c *gc.C, api *mockAPI) {
+	w := discoverspaces.NewWorker(api, autoImportConfig(c, true))
+	defer func() {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	}()
+	select {
+	case importSubnets := <-api.calls:
+		c.Assert(importSubnets, jc.IsTrue)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for subnets to be imported")
+	}
+}
+
+func (s *WorkerSuite) TestImportsSubnets(c *gc.C) {
+	s.assertImports(c, &mockAPI{calls: make(chan bool, 1)})
+}
+
+func (s *WorkerSuite) TestErrorsAreNotFatal(c *gc.C) {
+	s.assertImports(c, &mockAPI{
+		calls: make(chan bool, 1),
+		err:   errors.New("boom"),
+	})
+}
+
+func (s *WorkerSuite) TestNotSupportedIsNotFatal(c *gc.C) {
+	s.assertImports(c, &mockAPI{
+		calls: make(chan bool, 1),
+		err:   &params.Error{Code: params.CodeNotSupported, Message: "not supported"},
+	})
+}
+
+func (s *WorkerSuite) TestNoImportUnlessEnabled(c *gc.C) {
+	api := &mockAPI{calls: make(chan bool, 1)}
+	w := discoverspaces.NewWorker(api, autoImportConfig(c, false))
+	defer func() {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	}()
+	select {
+	case <-api.calls:
+		c.Fatalf("unexpected subnet import")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskspacerecorder
+
+import "github.com/juju/errors"
+
+// State servers do not run on Windows.
+func freeSpace(path string) (uint64, error) {
+	return 0, errors.NotSupportedf("checking free space on windows")
+}
@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package diskspacerecorder provides a worker that records the free
+// space in a state server's data directory, so that the space on
+// every state server can be checked before an upgrade.
+package diskspacerecorder
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker"
+)
+
+// DiskSpaceSetter defines the interface for types capable of
+// recording the free space in a state server's data directory.
+type DiskSpaceSetter interface {
+	SetStateServerDiskSpace(machineId, path string, free uint64) error
+}
+
+var diskFree = freeSpace
+
+// New returns a worker that records the free space in dataDir for the
+// given state server machine every interval.
+func New(setter DiskSpaceSetter, machineId, dataDir string, interval time.Duration) worker.Worker {
+	f := func(stop <-chan struct{}) error {
+		free, err := diskFree(dataDir)
+		if err != nil {
+			return errors.Annotatef(err, "cannot check free space in %q", dataDir)
+		}
+		return setter.SetStateServerDiskSpace(machineId, dataDir, free)
+	}
+	return worker.NewPeriodicWorker(f, interval, worker.NewTimer)
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskspacerecorder_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/diskspacerecorder"
+)
+
+type DiskSpaceRecorderSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&DiskSpaceRecorderSuite{})
+
+type recordedSpace struct {
+	machineId string
+	path      string
+	free      uint64
+}
+
+type fakeSetter chan recordedSpace
+
+func (f fakeSetter) SetStateServerDiskSpace(machineId, path string, free uint64) error {
+	select {
+	case f <- recordedSpace{machineId, path, free}:
+	default:
+	}
+	return nil
+}
+
+func (s *DiskSpaceRecorderSuite) TestRecordsFreeSpace(c *gc.C) {
+	dataDir := c.MkDir()
+	free := uint64(1234)
+	s.PatchValue(diskspacerecorder.DiskFree, func(path string) (uint64, error) {
+		c.Check(path, gc.Equals, dataDir)
+		free++
+		return free, nil
+	})
+	setter := make(fakeSetter, 10)
+	w := diskspacerecorder.New(setter, "0", dataDir, time.Millisecond)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	for _, expect := range []uint64{1235, 1236} {
+		select {
+		case recorded := <-setter:
+			c.Assert(recorded, gc.Equals, recordedSpace{"0", dataDir, expect})
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for free space to be recorded")
+		}
+	}
+}
@@ -35,5 +35,11 @@ func NewMetricsManager(client metricsmanager.MetricsManagerClient) (worker.Runne
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	err = runner.StartWorker("thresholds", func() (worker.Worker, error) {
+		return NewThresholds(client), nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return runner, nil
 }
@@ -18,14 +18,14 @@ type MetricManagerSuite struct{}
 var _ = gc.Suite(&MetricManagerSuite{})
 
 func (s *MetricManagerSuite) TestRunner(c *gc.C) {
-	notify := make(chan string, 2)
+	notify := make(chan string, 3)
 	cleanup := metricworker.PatchNotificationChannel(notify)
 	defer cleanup()
 	client := &mockClient{}
 	_, err := metricworker.NewMetricsManager(client)
 	c.Assert(err, jc.ErrorIsNil)
 	expectedCalls := map[string]bool{}
-	for i := 0; i < 2; i++ {
+	for i := 0; i < 3; i++ {
 		select {
 		case call := <-notify:
 			expectedCalls[call] = true
@@ -36,4 +36,5 @@ func (s *MetricManagerSuite) TestRunner(c *gc.C) {
 
 	c.Check(expectedCalls["senderCalled"], jc.IsTrue)
 	c.Check(expectedCalls["cleanupCalled"], jc.IsTrue)
+	c.Check(expectedCalls["thresholdsCalled"], jc.IsTrue)
 }
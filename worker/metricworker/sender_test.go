@@ -59,3 +59,9 @@ func (m *mockClient) SendMetrics() error {
 	m.calls = append(m.calls, "SendMetrics")
 	return nil
 }
+func (m *mockClient) EvaluateMeterThresholds() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.calls = append(m.calls, "EvaluateMeterThresholds")
+	return nil
+}
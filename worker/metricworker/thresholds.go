@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricworker
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/api/metricsmanager"
+	"github.com/juju/juju/worker"
+)
+
+var (
+	thresholdsLogger = loggo.GetLogger("juju.worker.metricworker.thresholds")
+)
+
+const (
+	thresholdsPeriod = time.Minute
+)
+
+// NewThresholds creates a new periodic worker that evaluates the
+// meter thresholds defined for services, updating the meter status
+// of their units.
+func NewThresholds(client metricsmanager.MetricsManagerClient) worker.Worker {
+	f := func(stopCh <-chan struct{}) error {
+		err := client.EvaluateMeterThresholds()
+		if err != nil {
+			thresholdsLogger.Warningf("failed to evaluate meter thresholds %v - will retry later", err)
+			return nil
+		}
+		select {
+		case notify <- "thresholdsCalled":
+		default:
+		}
+		return nil
+	}
+	return worker.NewPeriodicWorker(f, thresholdsPeriod, worker.NewTimer)
+}
@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricworker_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/metricworker"
+)
+
+type ThresholdsSuite struct{}
+
+var _ = gc.Suite(&ThresholdsSuite{})
+
+// TestThresholds create a simple thresholds worker and checks
+// that it evaluates the meter thresholds.
+func (s *ThresholdsSuite) TestThresholds(c *gc.C) {
+	notify := make(chan string)
+	cleanup := metricworker.PatchNotificationChannel(notify)
+	defer cleanup()
+	client := &mockClient{}
+	worker := metricworker.NewThresholds(client)
+	select {
+	case <-notify:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("the thresholds function should have fired by now")
+	}
+	c.Assert(client.calls, gc.DeepEquals, []string{"EvaluateMeterThresholds"})
+	worker.Kill()
+	c.Assert(worker.Wait(), gc.IsNil)
+}
@@ -82,6 +82,9 @@ func desiredPeerGroup(info *peerGroupInfo) ([]replicaset.Member, map[*machine]bo
 	if updateAddresses(members, info.machines) {
 		changed = true
 	}
+	if updatePriorities(members, info.machines) {
+		changed = true
+	}
 	if !changed {
 		return nil, machineVoting, nil
 	}
@@ -161,6 +164,58 @@ func updateAddresses(members map[*machine]*replicaset.Member, machines map[strin
 	return changed
 }
 
+// updatePriorities updates the members' priorities and hidden flags from
+// the machines' replica set preferences. Voting members take their
+// machine's priority, and are never hidden; non-voting members always
+// have zero priority, but may be hidden. It reports whether any
+// changes have been made.
+func updatePriorities(members map[*machine]*replicaset.Member, machines map[string]*machine) bool {
+	changed := false
+	for _, m := range machines {
+		member := members[m]
+		if member == nil {
+			continue
+		}
+		// Unset fields take mongo's defaults.
+		priority, hidden := defaultPriority, false
+		if !isVotingMember(member) {
+			priority, hidden = 0, m.hidden
+		} else if m.priority > 0 {
+			priority = m.priority
+		}
+		if memberPriority(member) != priority {
+			member.Priority = nil
+			if priority != defaultPriority {
+				member.Priority = &priority
+			}
+			changed = true
+		}
+		if memberHidden(member) != hidden {
+			member.Hidden = nil
+			if hidden {
+				member.Hidden = &hidden
+			}
+			changed = true
+		}
+	}
+	return changed
+}
+
+// defaultPriority is the priority mongo gives members
+// with no explicit priority.
+const defaultPriority = 1.0
+
+func memberPriority(member *replicaset.Member) float64 {
+	if member.Priority == nil {
+		return defaultPriority
+	}
+	return *member.Priority
+}
+
+func memberHidden(member *replicaset.Member) bool {
+	return member.Hidden != nil && *member.Hidden
+}
+
 // adjustVotes adjusts the votes of the given machines, taking
 // care not to let the total number of votes become even at
 // any time. It calls setVoting to change the voting status
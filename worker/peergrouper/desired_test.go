@@ -183,6 +183,55 @@ func desiredPeerGroupTests(ipVersion TestIPVersion) []desiredPeerGroupTest {
 			members:       mkMembers("1v 2v 3v", ipVersion),
 			expectVoting:  []bool{true, true, true},
 			expectMembers: nil,
+		}, {
+			about:         "voting members take their machines' priorities",
+			machines:      mkMachines("11vz 12vP 13v", ipVersion),
+			members:       mkMembers("1v 2v 3v", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s", ipVersion),
+			expectVoting:  []bool{true, true, true},
+			expectMembers: mkMembers("1vz 2vP 3v", ipVersion),
+		}, {
+			about:         "priorities are reset when preferences are removed",
+			machines:      mkMachines("11v 12v 13v", ipVersion),
+			members:       mkMembers("1vz 2vP 3v", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s", ipVersion),
+			expectVoting:  []bool{true, true, true},
+			expectMembers: mkMembers("1v 2v 3v", ipVersion),
+		}, {
+			about:         "priorities of preferred non-voting members stay zero",
+			machines:      mkMachines("11v 12z", ipVersion),
+			members:       mkMembers("1v 2", ipVersion),
+			statuses:      mkStatuses("1p 2s", ipVersion),
+			expectVoting:  []bool{true, false},
+			expectMembers: nil,
+		}, {
+			about:         "new voting member takes its machine's priority",
+			machines:      mkMachines("11v 12vP 13vz", ipVersion),
+			members:       mkMembers("1v 2 3", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s", ipVersion),
+			expectVoting:  []bool{true, true, true},
+			expectMembers: mkMembers("1v 2vP 3vz", ipVersion),
+		}, {
+			about:         "hidden machine is added as a hidden member",
+			machines:      mkMachines("11v 12h", ipVersion),
+			members:       mkMembers("1v", ipVersion),
+			statuses:      mkStatuses("1p", ipVersion),
+			expectVoting:  []bool{true, false},
+			expectMembers: mkMembers("1v 2h", ipVersion),
+		}, {
+			about:         "existing member becomes hidden",
+			machines:      mkMachines("11v 12h", ipVersion),
+			members:       mkMembers("1v 2", ipVersion),
+			statuses:      mkStatuses("1p 2s", ipVersion),
+			expectVoting:  []bool{true, false},
+			expectMembers: mkMembers("1v 2h", ipVersion),
+		}, {
+			about:         "hidden member is unhidden",
+			machines:      mkMachines("11v 12", ipVersion),
+			members:       mkMembers("1v 2h", ipVersion),
+			statuses:      mkStatuses("1p 2s", ipVersion),
+			expectVoting:  []bool{true, false},
+			expectMembers: mkMembers("1v 2", ipVersion),
 		}}
 }
 
@@ -256,11 +305,18 @@ func newFloat64(f float64) *float64 {
 	return &f
 }
 
+func newBool(b bool) *bool {
+	return &b
+}
+
 // mkMachines returns a slice of *machine based on
 // the given description.
 // Each machine in the description is white-space separated
-// and holds the decimal machine id followed by an optional
-// "v" if the machine wants a vote.
+// and holds the decimal machine id optionally followed by the characters:
+//	- 'v' if the machine wants a vote.
+//	- 'z' if the machine is in the preferred zone (priority 2).
+//	- 'P' if the machine is the preferred primary (priority 3).
+//	- 'h' if the machine is hidden.
 func mkMachines(description string, ipVersion TestIPVersion) []*machine {
 	descrs := parseDescr(description)
 	ms := make([]*machine, len(descrs))
@@ -276,11 +332,25 @@ func mkMachines(description string, ipVersion TestIPVersion) []*machine {
 				Port: mongoPort,
 			}},
 			wantsVote: strings.Contains(d.flags, "v"),
+			priority:  flagPriority(d.flags),
+			hidden:    strings.Contains(d.flags, "h"),
 		}
 	}
 	return ms
 }
 
+// flagPriority returns the replica set priority
+// implied by the given description flags.
+func flagPriority(flags string) float64 {
+	switch {
+	case strings.Contains(flags, "P"):
+		return 3
+	case strings.Contains(flags, "z"):
+		return 2
+	}
+	return 0
+}
+
 func memberTag(id string) map[string]string {
 	return map[string]string{jujuMachineKey: id}
 }
@@ -291,6 +361,8 @@ func memberTag(id string) map[string]string {
 // and holds the decimal replica-set id optionally followed by the characters:
 //	- 'v' if the member is voting.
 // 	- 'T' if the member has no associated machine tags.
+//	- 'z' or 'P' if the member is voting with priority 2 or 3.
+//	- 'h' if the member is hidden.
 // Unless the T flag is specified, the machine tag
 // will be the replica-set id + 10.
 func mkMembers(description string, ipVersion TestIPVersion) []replicaset.Member {
@@ -306,6 +378,11 @@ func mkMembers(description string, ipVersion TestIPVersion) []replicaset.Member
 		if !strings.Contains(d.flags, "v") {
 			m.Priority = newFloat64(0)
 			m.Votes = newInt(0)
+		} else if priority := flagPriority(d.flags); priority > 0 {
+			m.Priority = newFloat64(priority)
+		}
+		if strings.Contains(d.flags, "h") {
+			m.Hidden = newBool(true)
 		}
 		if strings.Contains(d.flags, "T") {
 			m.Tags = nil
@@ -244,6 +244,8 @@ type machineDoc struct {
 	instanceId     instance.Id
 	mongoHostPorts []network.HostPort
 	apiHostPorts   []network.HostPort
+	priority       float64
+	hidden         bool
 }
 
 func (m *fakeMachine) Refresh() error {
@@ -295,6 +297,18 @@ func (m *fakeMachine) HasVote() bool {
 	return m.doc.hasVote
 }
 
+func (m *fakeMachine) ReplicaSetPriority() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doc.priority
+}
+
+func (m *fakeMachine) ReplicaSetHidden() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doc.hidden
+}
+
 func (m *fakeMachine) MongoHostPorts() []network.HostPort {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -374,6 +388,13 @@ func (m *fakeMachine) setWantsVote(wantsVote bool) {
 	})
 }
 
+func (m *fakeMachine) setReplicaSetPreferences(priority float64, hidden bool) {
+	m.mutate(func(doc *machineDoc) {
+		doc.priority = priority
+		doc.hidden = hidden
+	})
+}
+
 type fakeMongoSession struct {
 	// If InstantlyReady is true, replica status of
 	// all members will be instantly reported as ready.
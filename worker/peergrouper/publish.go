@@ -19,15 +19,17 @@ type apiHostPortsSetter interface {
 type publisher struct {
 	st         apiHostPortsSetter
 	preferIPv6 bool
+	ipv6Only   bool
 
 	mu             sync.Mutex
 	lastAPIServers [][]network.HostPort
 }
 
-func newPublisher(st apiHostPortsSetter, preferIPv6 bool) *publisher {
+func newPublisher(st apiHostPortsSetter, preferIPv6, ipv6Only bool) *publisher {
 	return &publisher{
 		st:         st,
-		preferIPv6: preferIPv6,
+		preferIPv6: preferIPv6 || ipv6Only,
+		ipv6Only:   ipv6Only,
 	}
 }
 
@@ -40,7 +42,14 @@ func (pub *publisher) publishAPIServers(apiServers [][]network.HostPort, instanc
 
 	sortedAPIServers := make([][]network.HostPort, len(apiServers))
 	for i, hostPorts := range apiServers {
-		sortedAPIServers[i] = append([]network.HostPort{}, hostPorts...)
+		for _, hp := range hostPorts {
+			if pub.ipv6Only && hp.Type == network.IPv4Address {
+				// IPv4 addresses are not usable by agents in
+				// IPv6-only environments.
+				continue
+			}
+			sortedAPIServers[i] = append(sortedAPIServers[i], hp)
+		}
 		network.SortHostPorts(sortedAPIServers[i], pub.preferIPv6)
 	}
 	if apiServersEqual(sortedAPIServers, pub.lastAPIServers) {
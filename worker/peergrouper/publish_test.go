@@ -30,7 +30,7 @@ func (s *mockAPIHostPortsSetter) SetAPIHostPorts(apiHostPorts [][]network.HostPo
 
 func (s *publishSuite) TestPublisherSetsAPIHostPortsOnce(c *gc.C) {
 	var mock mockAPIHostPortsSetter
-	statePublish := newPublisher(&mock, false)
+	statePublish := newPublisher(&mock, false, false)
 
 	hostPorts1 := network.NewHostPorts(1234, "testing1.invalid", "127.0.0.1")
 	hostPorts2 := network.NewHostPorts(1234, "testing2.invalid", "127.0.0.2")
@@ -60,7 +60,7 @@ func (s *publishSuite) TestPublisherSortsHostPorts(c *gc.C) {
 
 	check := func(preferIPv6 bool, publish, expect []network.HostPort) {
 		var mock mockAPIHostPortsSetter
-		statePublish := newPublisher(&mock, preferIPv6)
+		statePublish := newPublisher(&mock, preferIPv6, false)
 		for i := 0; i < 2; i++ {
 			err := statePublish.publishAPIServers([][]network.HostPort{publish}, nil)
 			c.Assert(err, jc.ErrorIsNil)
@@ -74,3 +74,15 @@ func (s *publishSuite) TestPublisherSortsHostPorts(c *gc.C) {
 	check(true, ipV4First, ipV6First)
 	check(true, ipV6First, ipV6First)
 }
+
+func (s *publishSuite) TestPublisherDropsIPv4WhenIPv6Only(c *gc.C) {
+	var mock mockAPIHostPortsSetter
+	statePublish := newPublisher(&mock, false, true)
+
+	hostPorts := network.NewHostPorts(17070, "10.0.0.1", "testing1.invalid", "2001:db8::1")
+	err := statePublish.publishAPIServers([][]network.HostPort{hostPorts}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mock.apiHostPorts, gc.DeepEquals, [][]network.HostPort{
+		network.NewHostPorts(17070, "2001:db8::1", "testing1.invalid"),
+	})
+}
@@ -34,6 +34,8 @@ type stateMachine interface {
 	WantsVote() bool
 	HasVote() bool
 	SetHasVote(hasVote bool) error
+	ReplicaSetPriority() float64
+	ReplicaSetHidden() bool
 	APIHostPorts() []network.HostPort
 	MongoHostPorts() []network.HostPort
 }
@@ -122,7 +124,7 @@ func New(st *state.State) (worker.Worker, error) {
 		State:     st,
 		mongoPort: cfg.StatePort(),
 		apiPort:   cfg.APIPort(),
-	}, newPublisher(st, cfg.PreferIPv6())), nil
+	}, newPublisher(st, cfg.PreferIPv6(), cfg.IPv6Only())), nil
 }
 
 func newWorker(st stateInterface, pub publisherInterface) worker.Worker {
@@ -445,6 +447,14 @@ type machine struct {
 	apiHostPorts   []network.HostPort
 	mongoHostPorts []network.HostPort
 
+	// priority holds the replica set priority of the machine's
+	// member while it is voting; zero means the default priority.
+	priority float64
+
+	// hidden records whether the machine's member is hidden
+	// while it is not voting.
+	hidden bool
+
 	worker         *pgWorker
 	stm            stateMachine
 	machineWatcher state.NotifyWatcher
@@ -459,7 +469,8 @@ func (m *machine) String() string {
 }
 
 func (m *machine) GoString() string {
-	return fmt.Sprintf("&peergrouper.machine{id: %q, wantsVote: %v, hostPort: %q}", m.id, m.wantsVote, m.mongoHostPort())
+	return fmt.Sprintf("&peergrouper.machine{id: %q, wantsVote: %v, priority: %v, hidden: %v, hostPort: %q}",
+		m.id, m.wantsVote, m.priority, m.hidden, m.mongoHostPort())
 }
 
 func (w *pgWorker) newMachine(stm stateMachine) *machine {
@@ -470,6 +481,8 @@ func (w *pgWorker) newMachine(stm stateMachine) *machine {
 		apiHostPorts:   stm.APIHostPorts(),
 		mongoHostPorts: stm.MongoHostPorts(),
 		wantsVote:      stm.WantsVote(),
+		priority:       stm.ReplicaSetPriority(),
+		hidden:         stm.ReplicaSetHidden(),
 		machineWatcher: stm.Watch(),
 	}
 	w.start(m.loop)
@@ -512,6 +525,14 @@ func (m *machine) refresh() (bool, error) {
 		m.wantsVote = wantsVote
 		changed = true
 	}
+	if priority := m.stm.ReplicaSetPriority(); priority != m.priority {
+		m.priority = priority
+		changed = true
+	}
+	if hidden := m.stm.ReplicaSetHidden(); hidden != m.hidden {
+		m.hidden = hidden
+		changed = true
+	}
 	if hps := m.stm.MongoHostPorts(); !hostPortsEqual(hps, m.mongoHostPorts) {
 		m.mongoHostPorts = hps
 		changed = true
@@ -37,7 +37,7 @@ func (s *workerJujuConnSuite) TestPublisherSetsAPIHostPorts(c *gc.C) {
 		cwatch := statetesting.NewNotifyWatcherC(c, s.State, watcher)
 		cwatch.AssertOneChange()
 
-		statePublish := peergrouper.NewPublisher(s.State, false)
+		statePublish := peergrouper.NewPublisher(s.State, false, false)
 
 		// Wrap the publisher so that we can call StartSync immediately
 		// after the publishAPIServers method is called.
@@ -63,8 +63,25 @@ func (s *workerJujuConnSuite) TestPublisherRejectsNoServers(c *gc.C) {
 	peergrouper.DoTestForIPv4AndIPv6(func(ipVersion peergrouper.TestIPVersion) {
 		st := peergrouper.NewFakeState()
 		peergrouper.InitState(c, st, 3, ipVersion)
-		statePublish := peergrouper.NewPublisher(s.State, false)
+		statePublish := peergrouper.NewPublisher(s.State, false, false)
 		err := statePublish.PublishAPIServers(nil, nil)
 		c.Assert(err, gc.ErrorMatches, "no api servers specified")
 	})
 }
+
+func (s *workerJujuConnSuite) TestPublisherIPv6OnlyDropsIPv4Addresses(c *gc.C) {
+	statePublish := peergrouper.NewPublisher(s.State, false, true)
+	apiServers := [][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1", "2001:db8::1"),
+		network.NewHostPorts(17070, "10.0.0.2", "2001:db8::2"),
+	}
+	err := statePublish.PublishAPIServers(apiServers, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	hps, err := s.State.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hps, jc.DeepEquals, [][]network.HostPort{
+		network.NewHostPorts(17070, "2001:db8::1"),
+		network.NewHostPorts(17070, "2001:db8::2"),
+	})
+}
@@ -302,6 +302,38 @@ func (s *workerSuite) TestAddressChange(c *gc.C) {
 	})
 }
 
+func (s *workerSuite) TestReplicaSetPreferencesChange(c *gc.C) {
+	DoTestForIPv4AndIPv6(func(ipVersion TestIPVersion) {
+		st := NewFakeState()
+		InitState(c, st, 3, ipVersion)
+
+		memberWatcher := st.session.members.Watch()
+		mustNext(c, memberWatcher)
+		assertMembers(c, memberWatcher.Value(), mkMembers("0v", ipVersion))
+
+		logger.Infof("starting worker")
+		w := newWorker(st, noPublisher{})
+		defer func() {
+			c.Check(worker.Stop(w), gc.IsNil)
+		}()
+
+		// Wait for the worker to set the initial members.
+		mustNext(c, memberWatcher)
+		assertMembers(c, memberWatcher.Value(), mkMembers("0v 1 2", ipVersion))
+
+		// Prefer machine 10 as primary and hide machine 12,
+		// and wait for the members to be changed.
+		st.machine("10").setReplicaSetPreferences(3, false)
+		mustNext(c, memberWatcher)
+		assertMembers(c, memberWatcher.Value(), mkMembers("0vP 1 2", ipVersion))
+
+		st.machine("12").setReplicaSetPreferences(0, true)
+		mustNext(c, memberWatcher)
+		assertMembers(c, memberWatcher.Value(), mkMembers("0vP 1 2h", ipVersion))
+		resetErrors()
+	})
+}
+
 var fatalErrorsTests = []struct {
 	errPattern string
 	err        error
@@ -178,7 +178,7 @@ func (s *ContainerSetupSuite) TestContainerProvisionerStarted(c *gc.C) {
 			Constraints: s.defaultConstraints,
 		})
 		c.Assert(err, jc.ErrorIsNil)
-		err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+		err = m.SetSupportedContainers(instance.ContainerTypes)
 		c.Assert(err, jc.ErrorIsNil)
 		err = m.SetAgentVersion(version.Current)
 		c.Assert(err, jc.ErrorIsNil)
@@ -200,6 +200,13 @@ func (s *ContainerSetupSuite) TestKvmContainerUsesHostArch(c *gc.C) {
 	s.testContainerConstraintsArch(c, instance.KVM, arch.AMD64)
 }
 
+func (s *ContainerSetupSuite) TestLxdContainerUsesHostArch(c *gc.C) {
+	// LXD should override the architecture in constraints with the
+	// host's architecture.
+	s.PatchValue(&arch.HostArch, func() string { return arch.PPC64EL })
+	s.testContainerConstraintsArch(c, instance.LXD, arch.PPC64EL)
+}
+
 func (s *ContainerSetupSuite) testContainerConstraintsArch(c *gc.C, containerType instance.ContainerType, expectArch string) {
 	var called bool
 	s.PatchValue(provisioner.GetToolsFinder, func(*apiprovisioner.State) provisioner.ToolsFinder {
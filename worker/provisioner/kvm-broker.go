@@ -113,6 +113,7 @@ func (broker *kvmBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		kvmLogger.Errorf("failed to populate machine config: %v", err)
 		return nil, err
 	}
+	args.InstanceConfig.IPv6Only = config.IPv6Only
 
 	storageConfig := &container.StorageConfig{
 		AllowMount: true,
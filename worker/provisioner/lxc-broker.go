@@ -136,6 +136,8 @@ func (broker *lxcBroker) StartInstance(args environs.StartInstanceParams) (*envi
 
 	series := archTools.OneSeries()
 	args.InstanceConfig.MachineContainerType = instance.LXC
+	// The manager turns the constraints into resource limits.
+	args.InstanceConfig.Constraints = args.Constraints
 	args.InstanceConfig.Tools = archTools[0]
 
 	config, err := broker.api.ContainerConfig()
@@ -162,6 +164,7 @@ func (broker *lxcBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		lxcLogger.Errorf("failed to populate machine config: %v", err)
 		return nil, err
 	}
+	args.InstanceConfig.IPv6Only = config.IPv6Only
 
 	inst, hardware, err := broker.manager.CreateContainer(args.InstanceConfig, series, network, storageConfig)
 	if err != nil {
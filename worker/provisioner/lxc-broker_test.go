@@ -186,6 +186,23 @@ func (s *lxcBrokerSuite) TestStartInstance(c *gc.C) {
 	s.assertDefaultStorageConfig(c, lxc)
 }
 
+func (s *lxcBrokerSuite) TestStartInstanceAppliesConstraints(c *gc.C) {
+	instanceConfig := s.instanceConfig(c, "1/lxc/0")
+	possibleTools := coretools.List{&coretools.Tools{
+		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
+	}}
+	result, err := s.broker.StartInstance(environs.StartInstanceParams{
+		Constraints:    constraints.MustParse("cpu-power=200"),
+		Tools:          possibleTools,
+		InstanceConfig: instanceConfig,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result.Hardware.CpuPower, gc.Equals, uint64(200))
+	config := filepath.Join(s.LxcDir, string(result.Instance.Id()), "config")
+	AssertFileContains(c, config, "lxc.cgroup.cpu.shares = 2048")
+}
+
 func (s *lxcBrokerSuite) TestStartInstanceAddressAllocationDisabled(c *gc.C) {
 	machineId := "1/lxc/0"
 	lxc := s.startInstance(c, machineId, nil)
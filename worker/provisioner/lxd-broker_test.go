@@ -0,0 +1,192 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/instance"
+	instancetest "github.com/juju/juju/instance/testing"
+	"github.com/juju/juju/juju/arch"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type lxdBrokerSuite struct {
+	lxdtesting.TestSuite
+	broker      environs.InstanceBroker
+	agentConfig agent.Config
+	api         *fakeAPI
+}
+
+var _ = gc.Suite(&lxdBrokerSuite{})
+
+func (s *lxdBrokerSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Skipping lxd tests on windows")
+	}
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.agentConfig, err = agent.NewAgentConfig(
+		agent.AgentConfigParams{
+			DataDir:           "/not/used/here",
+			Tag:               names.NewMachineTag("1"),
+			UpgradedToVersion: version.Current.Number,
+			Password:          "dummy-secret",
+			Nonce:             "nonce",
+			APIAddresses:      []string{"10.0.0.1:1234"},
+			CACert:            coretesting.CACert,
+			Environment:       coretesting.EnvironmentTag,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = NewFakeAPI()
+	managerConfig := container.ManagerConfig{container.ConfigName: "juju"}
+	s.broker, err = provisioner.NewLxdBroker(s.api, s.agentConfig, managerConfig, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lxdBrokerSuite) instanceConfig(c *gc.C, machineId string) *instancecfg.InstanceConfig {
+	machineNonce := "fake-nonce"
+	// To isolate the tests from the host's architecture, we override it here.
+	s.PatchValue(&arch.HostArch, func() string { return arch.AMD64 })
+	stateInfo := jujutesting.FakeStateInfo(machineId)
+	apiInfo := jujutesting.FakeAPIInfo(machineId)
+	instanceConfig, err := instancecfg.NewInstanceConfig(machineId, machineNonce, "released", "quantal", true, nil, stateInfo, apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	return instanceConfig
+}
+
+func (s *lxdBrokerSuite) startInstance(c *gc.C, machineId string) *environs.StartInstanceResult {
+	instanceConfig := s.instanceConfig(c, machineId)
+	possibleTools := coretools.List{&coretools.Tools{
+		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
+	}}
+	result, err := s.broker.StartInstance(environs.StartInstanceParams{
+		Constraints:    constraints.Value{},
+		Tools:          possibleTools,
+		InstanceConfig: instanceConfig,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return result
+}
+
+func (s *lxdBrokerSuite) assertInstances(c *gc.C, inst ...instance.Instance) {
+	results, err := s.broker.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	instancetest.MatchInstances(c, results, inst...)
+}
+
+func (s *lxdBrokerSuite) TestStartInstance(c *gc.C) {
+	result := s.startInstance(c, "1/lxd/0")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "ContainerConfig",
+	}})
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("juju-machine-1-lxd-0"))
+	c.Assert(result.Hardware.String(), gc.Equals, "arch=amd64")
+	s.assertInstances(c, result.Instance)
+
+	profile, ok := s.Server.Profile("juju-machine-1-lxd-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(profile.Devices["eth0"]["parent"], gc.Equals, "lxdbr0")
+}
+
+func (s *lxdBrokerSuite) TestStartInstanceAppliesConstraints(c *gc.C) {
+	instanceConfig := s.instanceConfig(c, "1/lxd/0")
+	result, err := s.broker.StartInstance(environs.StartInstanceParams{
+		Constraints: constraints.MustParse("mem=1G cpu-cores=2"),
+		Tools: coretools.List{&coretools.Tools{
+			Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		}},
+		InstanceConfig: instanceConfig,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceConfig.Constraints, jc.DeepEquals, constraints.MustParse("mem=1G cpu-cores=2"))
+	c.Assert(result.Hardware.String(), gc.Equals, "arch=amd64 cpu-cores=2 mem=1024M")
+
+	profile, ok := s.Server.Profile("juju-machine-1-lxd-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(profile.Config, jc.DeepEquals, map[string]string{
+		"limits.cpu":    "2",
+		"limits.memory": "1024MB",
+	})
+}
+
+func (s *lxdBrokerSuite) TestStartInstanceWithAddressAllocation(c *gc.C) {
+	s.SetFeatureFlags(feature.AddressAllocation)
+	result := s.startInstance(c, "1/lxd/0")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "PrepareContainerInterfaceInfo",
+		Args:     []interface{}{names.NewMachineTag("1-lxd-0")},
+	}, {
+		FuncName: "ContainerConfig",
+	}})
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("juju-machine-1-lxd-0"))
+}
+
+func (s *lxdBrokerSuite) TestStartInstanceContainerConfigError(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	instanceConfig := s.instanceConfig(c, "1/lxd/0")
+	_, err := s.broker.StartInstance(environs.StartInstanceParams{
+		Tools: coretools.List{&coretools.Tools{
+			Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		}},
+		InstanceConfig: instanceConfig,
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	_, ok := s.Server.Container("juju-machine-1-lxd-0")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *lxdBrokerSuite) TestStopInstance(c *gc.C) {
+	lxd0 := s.startInstance(c, "1/lxd/0").Instance
+	lxd1 := s.startInstance(c, "1/lxd/1").Instance
+	lxd2 := s.startInstance(c, "1/lxd/2").Instance
+
+	err := s.broker.StopInstances(lxd0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertInstances(c, lxd1, lxd2)
+	_, ok := s.Server.Container(string(lxd0.Id()))
+	c.Assert(ok, jc.IsFalse)
+
+	err = s.broker.StopInstances(lxd1.Id(), lxd2.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertInstances(c)
+}
+
+func (s *lxdBrokerSuite) TestAllInstances(c *gc.C) {
+	lxd0 := s.startInstance(c, "1/lxd/0").Instance
+	lxd1 := s.startInstance(c, "1/lxd/1").Instance
+	s.assertInstances(c, lxd0, lxd1)
+
+	err := s.broker.StopInstances(lxd1.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	lxd2 := s.startInstance(c, "1/lxd/2").Instance
+	s.assertInstances(c, lxd0, lxd2)
+}
+
+func (s *lxdBrokerSuite) TestMaintainInstanceAddressAllocationDisabled(c *gc.C) {
+	instanceConfig := s.instanceConfig(c, "1/lxd/0")
+	err := s.broker.MaintainInstance(environs.StartInstanceParams{
+		InstanceConfig: instanceConfig,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{})
+}
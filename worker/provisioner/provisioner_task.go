@@ -548,18 +548,19 @@ func constructStartInstanceParams(
 			return environs.StartInstanceParams{}, errors.Errorf("volume attachment params specifies instance ID")
 		}
 		volumes[i] = storage.VolumeParams{
-			volumeTag,
-			v.Size,
-			storage.ProviderType(v.Provider),
-			v.Attributes,
-			v.Tags,
-			&storage.VolumeAttachmentParams{
+			Tag:          volumeTag,
+			Size:         v.Size,
+			Provider:     storage.ProviderType(v.Provider),
+			Attributes:   v.Attributes,
+			ResourceTags: v.Tags,
+			Attachment: &storage.VolumeAttachmentParams{
 				AttachmentParams: storage.AttachmentParams{
 					Machine:  machineTag,
 					ReadOnly: v.Attachment.ReadOnly,
 				},
 				Volume: volumeTag,
 			},
+			SnapshotId: v.SnapshotId,
 		}
 	}
 	var subnetsToZones map[network.Id][]string
@@ -1076,6 +1076,60 @@ func (s *ProvisionerSuite) TestProvisioningMachinesWithRequestedVolumes(c *gc.C)
 	s.waitRemoved(c, m)
 }
 
+func (s *ProvisionerSuite) TestProvisioningMachinesWithVolumeFromSnapshot(c *gc.C) {
+	registry.RegisterProvider("static", &dummystorage.StorageProvider{IsDynamic: false})
+	registry.RegisterEnvironStorageProviders("dummy", "static")
+	defer registry.RegisterProvider("static", nil)
+
+	// Add a machine with a provisioned volume, and snapshot the volume.
+	m0, err := s.addMachineWithRequestedVolumes([]state.MachineVolumeParams{{
+		Volume: state.VolumeParams{Pool: "static", Size: 1024},
+	}}, s.defaultConstraints)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m0.SetProvisioned("i-snapshotted", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeAttachments, err := s.BackingState.MachineVolumeAttachments(m0.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachments, gc.HasLen, 1)
+	err = s.BackingState.SetVolumeInfo(volumeAttachments[0].Volume(), state.VolumeInfo{
+		VolumeId: "vol-0", Size: 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err := s.BackingState.CreateVolumeSnapshot(volumeAttachments[0].Volume())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.BackingState.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{
+		SnapshotId: "snap-0", Size: 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	p := s.newEnvironProvisioner(c)
+	defer p.Stop()
+
+	// Add a machine with a volume created from the snapshot; the
+	// snapshot's provider ID is passed to StartInstance.
+	m1, err := s.addMachineWithRequestedVolumes([]state.MachineVolumeParams{{
+		Volume: state.VolumeParams{Pool: "static", Size: 1024, Snapshot: snapshot.Id()},
+	}}, s.defaultConstraints)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	for {
+		select {
+		case o := <-s.op:
+			start, ok := o.(dummy.OpStartInstance)
+			if !ok {
+				continue
+			}
+			c.Assert(start.MachineId, gc.Equals, m1.Id())
+			c.Assert(start.VolumeParams, gc.HasLen, 1)
+			c.Assert(start.VolumeParams[0].SnapshotId, gc.Equals, "snap-0")
+			c.Assert(start.VolumeParams[0].Size, gc.Equals, uint64(1024))
+			return
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("provisioner did not start an instance for machine %s", m1.Id())
+		}
+	}
+}
+
 func (s *ProvisionerSuite) TestProvisioningDoesNotOccurWithAnInvalidEnvironment(c *gc.C) {
 	s.invalidateEnvironment(c)
 
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package rollingupgrader provides a worker that moves rolling charm
+// upgrades on from one batch of units to the next.
+package rollingupgrader
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.rollingupgrader")
+
+// checkPeriod is how often the units being upgraded are checked.
+var checkPeriod = 10 * time.Second
+
+// stallTimeout is how long an upgraded unit may go without reporting
+// an active workload status before its rolling upgrade is paused.
+var stallTimeout = 30 * time.Minute
+
+// NewRollingUpgrader returns a worker that periodically checks the
+// health of the units in each service's current rolling upgrade
+// batch, releasing the next batch once they are healthy and pausing
+// the upgrade if any fail or stall. A failure to check one service
+// is logged, and does not hold up the others.
+func NewRollingUpgrader(st *state.State) worker.Worker {
+	f := func(stop <-chan struct{}) error {
+		return advanceRollingUpgrades(st)
+	}
+	return worker.NewPeriodicWorker(f, checkPeriod, worker.NewTimer)
+}
+
+func advanceRollingUpgrades(st *state.State) error {
+	services, err := st.AllServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, service := range services {
+		if _, ok := service.RollingUpgrade(); !ok {
+			continue
+		}
+		logger.Debugf("checking rolling upgrade of service %q", service)
+		if err := service.AdvanceRollingUpgrade(stallTimeout); err != nil {
+			logger.Errorf("cannot advance rolling upgrade of service %q: %v", service, err)
+		}
+	}
+	return nil
+}
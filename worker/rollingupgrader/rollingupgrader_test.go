@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rollingupgrader_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/rollingupgrader"
+)
+
+type rollingUpgraderSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&rollingUpgraderSuite{})
+
+func (s *rollingUpgraderSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(rollingupgrader.CheckPeriod, coretesting.ShortWait)
+}
+
+// waitForBatch waits for the given batch of units to be released, or
+// for the rolling upgrade to finish if the batch is nil.
+func (s *rollingUpgraderSuite) waitForBatch(c *gc.C, service *state.Service, expect []string) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		err := service.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		upgrade, ok := service.RollingUpgrade()
+		switch {
+		case expect == nil && !ok:
+			return
+		case expect != nil && ok && strings.Join(upgrade.Batch, " ") == strings.Join(expect, " "):
+			return
+		}
+		select {
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for batch %v; rolling upgrade is %+v", expect, upgrade)
+		}
+	}
+}
+
+func (s *rollingUpgraderSuite) TestRollingUpgrader(c *gc.C) {
+	oldCharm := s.AddTestingCharm(c, "upgrade1")
+	newCharm := s.AddTestingCharm(c, "upgrade2")
+	service := s.AddTestingService(c, "upgrade", oldCharm)
+	var units []*state.Unit
+	for i := 0; i < 2; i++ {
+		unit, err := service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		units = append(units, unit)
+	}
+	err := service.SetCharmRolling(newCharm, false, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := rollingupgrader.NewRollingUpgrader(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.waitForBatch(c, service, []string{"upgrade/0"})
+	for i, unit := range units {
+		err := unit.SetCharmURL(newCharm.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetStatus(state.StatusActive, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		if i == 0 {
+			s.waitForBatch(c, service, []string{"upgrade/1"})
+		}
+	}
+	s.waitForBatch(c, service, nil)
+}
+
+func (s *rollingUpgraderSuite) TestRollingUpgraderPausesStalledUpgrade(c *gc.C) {
+	s.PatchValue(rollingupgrader.StallTimeout, time.Duration(0))
+	oldCharm := s.AddTestingCharm(c, "upgrade1")
+	newCharm := s.AddTestingCharm(c, "upgrade2")
+	service := s.AddTestingService(c, "upgrade", oldCharm)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetCharmRolling(newCharm, false, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := rollingupgrader.NewRollingUpgrader(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.waitForBatch(c, service, []string{"upgrade/0"})
+	err = unit.SetCharmURL(newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	timeout := time.After(coretesting.LongWait)
+	for {
+		err := service.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		upgrade, _ := service.RollingUpgrade()
+		if upgrade.Status == state.RollingUpgradePaused {
+			c.Assert(upgrade.Message, gc.Matches, "unit upgrade/0 has been .*, not active, .*")
+			return
+		}
+		select {
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for rolling upgrade to pause")
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package stagedupgrader provides a worker that moves staged agent
+// upgrades on from one batch of machines to the next.
+package stagedupgrader
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// checkPeriod is how often the machines being upgraded are checked.
+var checkPeriod = 10 * time.Second
+
+// NewStagedUpgrader returns a worker that periodically checks the
+// health of the machines in the environment's current staged upgrade
+// batch, releasing the next batch once they are healthy and pausing
+// the upgrade if any fail.
+func NewStagedUpgrader(st *state.State) worker.Worker {
+	f := func(stop <-chan struct{}) error {
+		if err := st.AdvanceStagedUpgrade(); err != nil {
+			return errors.Annotate(err, "cannot advance staged upgrade")
+		}
+		return nil
+	}
+	return worker.NewPeriodicWorker(f, checkPeriod, worker.NewTimer)
+}
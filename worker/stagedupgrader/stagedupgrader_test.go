@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stagedupgrader_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/stagedupgrader"
+)
+
+type stagedUpgraderSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&stagedUpgraderSuite{})
+
+func (s *stagedUpgraderSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(stagedupgrader.CheckPeriod, coretesting.ShortWait)
+}
+
+// waitForBatch waits for the given batch of machines to be released,
+// or for the staged upgrade to finish if the batch is nil.
+func (s *stagedUpgraderSuite) waitForBatch(c *gc.C, expect []string) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		u, err := s.State.StagedUpgrade()
+		switch {
+		case expect == nil && err != nil:
+			return
+		case expect != nil && err == nil && strings.Join(u.Batch(), " ") == strings.Join(expect, " "):
+			return
+		}
+		select {
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for batch %v", expect)
+		}
+	}
+}
+
+func (s *stagedUpgraderSuite) TestStagedUpgrader(c *gc.C) {
+	var machines []*state.Machine
+	for i := 0; i < 2; i++ {
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetAgentVersion(version.Current)
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+	newer := version.Current
+	newer.Patch++
+	err := s.State.StartStagedUpgrade(newer.Number, []string{machines[0].Id()}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := stagedupgrader.NewStagedUpgrader(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	for i, m := range machines {
+		err := m.SetAgentVersion(newer)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetStatus(state.StatusStarted, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		if i == 0 {
+			s.waitForBatch(c, []string{machines[1].Id()})
+		}
+	}
+	s.waitForBatch(c, nil)
+}
@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// filesystemResizesChanged is called when pending resizes of the
+// filesystems with the provided IDs have been seen to have changed.
+// Filesystems with a pending resize are resized by the relevant
+// filesystem source.
+func filesystemResizesChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tags := make([]names.FilesystemTag, len(changes))
+	for i, change := range changes {
+		tags[i] = names.NewFilesystemTag(change)
+	}
+	paramsResults, err := ctx.filesystemAccessor.FilesystemResizeParams(tags)
+	if err != nil {
+		return errors.Annotatef(err, "getting filesystem resize params for %v", changes)
+	}
+	var resizeParams []storage.FilesystemResizeParams
+	for i, result := range paramsResults {
+		if result.Error != nil {
+			if params.IsCodeNotFound(result.Error) {
+				// The resize has already completed or failed;
+				// either way, there is nothing more to do.
+				continue
+			}
+			return errors.Annotatef(
+				result.Error, "getting resize params for %s",
+				names.ReadableString(tags[i]),
+			)
+		}
+		p := storage.FilesystemResizeParams{
+			Filesystem:   tags[i],
+			FilesystemId: result.Result.FilesystemId,
+			Provider:     storage.ProviderType(result.Result.Provider),
+			Size:         result.Result.Size,
+		}
+		if result.Result.VolumeTag != "" {
+			volumeTag, err := names.ParseVolumeTag(result.Result.VolumeTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			p.Volume = volumeTag
+		}
+		resizeParams = append(resizeParams, p)
+	}
+	if len(resizeParams) == 0 {
+		return nil
+	}
+	return resizeFilesystems(ctx, resizeParams)
+}
+
+// resizeFilesystems resizes the filesystems with the specified parameters,
+// and records the results in state.
+func resizeFilesystems(ctx *context, resizeParams []storage.FilesystemResizeParams) error {
+	paramsBySource := make(map[string][]storage.FilesystemResizeParams)
+	sources := make(map[string]storage.FilesystemSource)
+	for _, p := range resizeParams {
+		sourceName := string(p.Provider)
+		if p.Volume != (names.VolumeTag{}) {
+			// Volume-backed filesystems are managed by the
+			// machine's managed filesystem source, irrespective
+			// of the provider of the backing volume.
+			sourceName = ""
+			sources[sourceName] = ctx.managedFilesystemSource
+		}
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], p)
+	}
+	var resizes []params.FilesystemResize
+	for sourceName, resizeParams := range paramsBySource {
+		var results []storage.ResizeFilesystemsResult
+		source, ok := sources[sourceName]
+		if !ok {
+			var err error
+			source, err = filesystemSource(
+				ctx.environConfig, ctx.storageDir, sourceName, resizeParams[0].Provider,
+			)
+			if err != nil {
+				return errors.Annotate(err, "getting filesystem source")
+			}
+		}
+		if resizer, ok := source.(storage.FilesystemResizer); ok {
+			logger.Debugf("resizing filesystems: %v", resizeParams)
+			var err error
+			results, err = resizer.ResizeFilesystems(resizeParams)
+			if err != nil {
+				return errors.Annotatef(err, "resizing filesystems from source %q", sourceName)
+			}
+		} else {
+			err := errors.NotSupportedf("resizing filesystems with storage provider %q", sourceName)
+			results = make([]storage.ResizeFilesystemsResult, len(resizeParams))
+			for i := range results {
+				results[i].Error = err
+			}
+		}
+		for i, result := range results {
+			tag := resizeParams[i].Filesystem
+			resize := params.FilesystemResize{FilesystemTag: tag.String()}
+			if result.Error != nil {
+				logger.Errorf("failed to resize %s: %v", names.ReadableString(tag), result.Error)
+				resize.Error = result.Error.Error()
+			} else {
+				resize.Size = result.FilesystemInfo.Size
+				if filesystem, ok := ctx.filesystems[tag]; ok {
+					filesystem.Size = resize.Size
+					ctx.filesystems[tag] = filesystem
+				}
+			}
+			resizes = append(resizes, resize)
+		}
+	}
+	errorResults, err := ctx.filesystemAccessor.SetFilesystemResizeResults(resizes)
+	if err != nil {
+		return errors.Annotate(err, "publishing filesystem resizes to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "publishing resize of %s to state",
+				resizes[i].FilesystemTag,
+			)
+		}
+	}
+	return nil
+}
@@ -122,13 +122,19 @@ type mockVolumeAccessor struct {
 	volumesWatcher         *mockStringsWatcher
 	attachmentsWatcher     *mockAttachmentsWatcher
 	blockDevicesWatcher    *mockNotifyWatcher
+	snapshotsWatcher       *mockStringsWatcher
+	resizesWatcher         *mockStringsWatcher
 	provisionedMachines    map[string]instance.Id
 	provisionedVolumes     map[string]params.Volume
 	provisionedAttachments map[params.MachineStorageId]params.VolumeAttachment
 	blockDevices           map[params.MachineStorageId]storage.BlockDevice
+	createdSnapshots       map[string]params.VolumeSnapshot
+	pendingResizes         map[string]uint64
 
 	setVolumeInfo           func([]params.Volume) ([]params.ErrorResult, error)
 	setVolumeAttachmentInfo func([]params.VolumeAttachment) ([]params.ErrorResult, error)
+	setVolumeSnapshotInfo   func([]params.VolumeSnapshot) ([]params.ErrorResult, error)
+	setVolumeResizeResults  func([]params.VolumeResize) ([]params.ErrorResult, error)
 }
 
 func (m *mockVolumeAccessor) provisionVolume(tag names.VolumeTag) params.Volume {
@@ -255,27 +261,97 @@ func (v *mockVolumeAccessor) SetVolumeAttachmentInfo(volumeAttachments []params.
 	return make([]params.ErrorResult, len(volumeAttachments)), nil
 }
 
+func (w *mockVolumeAccessor) WatchVolumeSnapshots() (apiwatcher.StringsWatcher, error) {
+	return w.snapshotsWatcher, nil
+}
+
+func (v *mockVolumeAccessor) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	var result []params.VolumeSnapshotParamsResult
+	for _, id := range ids {
+		if _, ok := v.createdSnapshots[id]; ok {
+			result = append(result, params.VolumeSnapshotParamsResult{
+				Error: common.ServerError(errors.AlreadyExistsf("volume snapshot %q", id)),
+			})
+			continue
+		}
+		// Snapshot "n" is taken of volume "n".
+		volumeTag := names.NewVolumeTag(id)
+		result = append(result, params.VolumeSnapshotParamsResult{Result: params.VolumeSnapshotParams{
+			Id:        id,
+			VolumeTag: volumeTag.String(),
+			VolumeId:  "vol-" + id,
+			Provider:  "dummy",
+		}})
+	}
+	return result, nil
+}
+
+func (v *mockVolumeAccessor) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+	if v.setVolumeSnapshotInfo != nil {
+		return v.setVolumeSnapshotInfo(snapshots)
+	}
+	return make([]params.ErrorResult, len(snapshots)), nil
+}
+
+func (w *mockVolumeAccessor) WatchVolumeResizes() (apiwatcher.StringsWatcher, error) {
+	return w.resizesWatcher, nil
+}
+
+func (v *mockVolumeAccessor) VolumeResizeParams(tags []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	var result []params.VolumeResizeParamsResult
+	for _, tag := range tags {
+		size, ok := v.pendingResizes[tag.Id()]
+		if !ok {
+			result = append(result, params.VolumeResizeParamsResult{
+				Error: common.ServerError(errors.NotFoundf("pending resize of volume %q", tag.Id())),
+			})
+			continue
+		}
+		result = append(result, params.VolumeResizeParamsResult{Result: params.VolumeResizeParams{
+			VolumeTag: tag.String(),
+			VolumeId:  "vol-" + tag.Id(),
+			Provider:  "dummy",
+			Size:      size,
+		}})
+	}
+	return result, nil
+}
+
+func (v *mockVolumeAccessor) SetVolumeResizeResults(resizes []params.VolumeResize) ([]params.ErrorResult, error) {
+	if v.setVolumeResizeResults != nil {
+		return v.setVolumeResizeResults(resizes)
+	}
+	return make([]params.ErrorResult, len(resizes)), nil
+}
+
 func newMockVolumeAccessor() *mockVolumeAccessor {
 	return &mockVolumeAccessor{
 		volumesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
 		attachmentsWatcher:     &mockAttachmentsWatcher{make(chan []params.MachineStorageId, 1)},
 		blockDevicesWatcher:    &mockNotifyWatcher{make(chan struct{}, 1)},
+		snapshotsWatcher:       &mockStringsWatcher{make(chan []string, 1)},
+		resizesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
 		provisionedMachines:    make(map[string]instance.Id),
 		provisionedVolumes:     make(map[string]params.Volume),
 		provisionedAttachments: make(map[params.MachineStorageId]params.VolumeAttachment),
 		blockDevices:           make(map[params.MachineStorageId]storage.BlockDevice),
+		createdSnapshots:       make(map[string]params.VolumeSnapshot),
+		pendingResizes:         make(map[string]uint64),
 	}
 }
 
 type mockFilesystemAccessor struct {
 	filesystemsWatcher     *mockStringsWatcher
 	attachmentsWatcher     *mockAttachmentsWatcher
+	resizesWatcher         *mockStringsWatcher
 	provisionedMachines    map[string]instance.Id
 	provisionedFilesystems map[string]params.Filesystem
 	provisionedAttachments map[params.MachineStorageId]params.FilesystemAttachment
+	pendingResizes         map[string]uint64
 
 	setFilesystemInfo           func([]params.Filesystem) ([]params.ErrorResult, error)
 	setFilesystemAttachmentInfo func([]params.FilesystemAttachment) ([]params.ErrorResult, error)
+	setFilesystemResizeResults  func([]params.FilesystemResize) ([]params.ErrorResult, error)
 }
 
 func (m *mockFilesystemAccessor) provisionFilesystem(tag names.FilesystemTag) params.Filesystem {
@@ -380,13 +456,46 @@ func (f *mockFilesystemAccessor) SetFilesystemAttachmentInfo(filesystemAttachmen
 	return nil, nil
 }
 
+func (w *mockFilesystemAccessor) WatchFilesystemResizes() (apiwatcher.StringsWatcher, error) {
+	return w.resizesWatcher, nil
+}
+
+func (f *mockFilesystemAccessor) FilesystemResizeParams(tags []names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error) {
+	var result []params.FilesystemResizeParamsResult
+	for _, tag := range tags {
+		size, ok := f.pendingResizes[tag.Id()]
+		if !ok {
+			result = append(result, params.FilesystemResizeParamsResult{
+				Error: common.ServerError(errors.NotFoundf("pending resize of filesystem %q", tag.Id())),
+			})
+			continue
+		}
+		result = append(result, params.FilesystemResizeParamsResult{Result: params.FilesystemResizeParams{
+			FilesystemTag: tag.String(),
+			FilesystemId:  "fs-" + tag.Id(),
+			Provider:      "dummy",
+			Size:          size,
+		}})
+	}
+	return result, nil
+}
+
+func (f *mockFilesystemAccessor) SetFilesystemResizeResults(resizes []params.FilesystemResize) ([]params.ErrorResult, error) {
+	if f.setFilesystemResizeResults != nil {
+		return f.setFilesystemResizeResults(resizes)
+	}
+	return make([]params.ErrorResult, len(resizes)), nil
+}
+
 func newMockFilesystemAccessor() *mockFilesystemAccessor {
 	return &mockFilesystemAccessor{
 		filesystemsWatcher:     &mockStringsWatcher{make(chan []string, 1)},
 		attachmentsWatcher:     &mockAttachmentsWatcher{make(chan []params.MachineStorageId, 1)},
+		resizesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
 		provisionedMachines:    make(map[string]instance.Id),
 		provisionedFilesystems: make(map[string]params.Filesystem),
 		provisionedAttachments: make(map[params.MachineStorageId]params.FilesystemAttachment),
+		pendingResizes:         make(map[string]uint64),
 	}
 }
 
@@ -459,6 +568,9 @@ type dummyProvider struct {
 	detachVolumesFunc     func([]storage.VolumeAttachmentParams) ([]error, error)
 	detachFilesystemsFunc func([]storage.FilesystemAttachmentParams) ([]error, error)
 	destroyVolumesFunc    func([]string) ([]error, error)
+
+	createVolumeSnapshotsFunc func([]storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error)
+	resizeVolumesFunc         func([]storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error)
 }
 
 type dummyVolumeSource struct {
@@ -521,6 +633,37 @@ func (s *dummyVolumeSource) CreateVolumes(params []storage.VolumeParams) ([]stor
 	return results, nil
 }
 
+// CreateVolumeSnapshots snapshots volumes.
+func (s *dummyVolumeSource) CreateVolumeSnapshots(params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	if s.provider != nil && s.provider.createVolumeSnapshotsFunc != nil {
+		return s.provider.createVolumeSnapshotsFunc(params)
+	}
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		results[i].VolumeSnapshot = &storage.VolumeSnapshot{
+			Volume:     p.Volume,
+			SnapshotId: "snap-" + p.VolumeId,
+			Size:       1024,
+		}
+	}
+	return results, nil
+}
+
+// ResizeVolumes resizes volumes.
+func (s *dummyVolumeSource) ResizeVolumes(params []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	if s.provider != nil && s.provider.resizeVolumesFunc != nil {
+		return s.provider.resizeVolumesFunc(params)
+	}
+	results := make([]storage.ResizeVolumesResult, len(params))
+	for i, p := range params {
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: p.VolumeId,
+			Size:     p.Size,
+		}
+	}
+	return results, nil
+}
+
 // DestroyVolumes destroys volumes.
 func (s *dummyVolumeSource) DestroyVolumes(volumeIds []string) ([]error, error) {
 	if s.provider.destroyVolumesFunc != nil {
@@ -88,6 +88,29 @@ type VolumeAccessor interface {
 	// SetVolumeAttachmentInfo records the details of newly provisioned
 	// volume attachments.
 	SetVolumeAttachmentInfo([]params.VolumeAttachment) ([]params.ErrorResult, error)
+
+	// WatchVolumeSnapshots watches for changes to volume snapshots
+	// that this storage provisioner is responsible for.
+	WatchVolumeSnapshots() (apiwatcher.StringsWatcher, error)
+
+	// VolumeSnapshotParams returns the parameters for creating the
+	// volume snapshots with the specified IDs.
+	VolumeSnapshotParams([]string) ([]params.VolumeSnapshotParamsResult, error)
+
+	// SetVolumeSnapshotInfo records the details of newly created
+	// volume snapshots, or the errors encountered creating them.
+	SetVolumeSnapshotInfo([]params.VolumeSnapshot) ([]params.ErrorResult, error)
+
+	// WatchVolumeResizes watches for changes to pending resizes of
+	// volumes that this storage provisioner is responsible for.
+	WatchVolumeResizes() (apiwatcher.StringsWatcher, error)
+
+	// VolumeResizeParams returns the parameters for resizing the
+	// volumes with the specified tags.
+	VolumeResizeParams([]names.VolumeTag) ([]params.VolumeResizeParamsResult, error)
+
+	// SetVolumeResizeResults records the outcome of resizing volumes.
+	SetVolumeResizeResults([]params.VolumeResize) ([]params.ErrorResult, error)
 }
 
 // FilesystemAccessor defines an interface used to allow a storage provisioner
@@ -122,6 +145,18 @@ type FilesystemAccessor interface {
 	// SetFilesystemAttachmentInfo records the details of newly provisioned
 	// filesystem attachments.
 	SetFilesystemAttachmentInfo([]params.FilesystemAttachment) ([]params.ErrorResult, error)
+
+	// WatchFilesystemResizes watches for changes to pending resizes of
+	// filesystems that this storage provisioner is responsible for.
+	WatchFilesystemResizes() (apiwatcher.StringsWatcher, error)
+
+	// FilesystemResizeParams returns the parameters for resizing the
+	// filesystems with the specified tags.
+	FilesystemResizeParams([]names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error)
+
+	// SetFilesystemResizeResults records the outcome of resizing
+	// filesystems.
+	SetFilesystemResizeResults([]params.FilesystemResize) ([]params.ErrorResult, error)
 }
 
 // MachineAccessor defines an interface used to allow a storage provisioner
@@ -244,6 +279,12 @@ func (w *storageprovisioner) loop() error {
 	var filesystemAttachmentsWatcher apiwatcher.MachineStorageIdsWatcher
 	var volumeAttachmentsChanges <-chan []params.MachineStorageId
 	var filesystemAttachmentsChanges <-chan []params.MachineStorageId
+	var volumeSnapshotsWatcher apiwatcher.StringsWatcher
+	var volumeSnapshotsChanges <-chan []string
+	var volumeResizesWatcher apiwatcher.StringsWatcher
+	var volumeResizesChanges <-chan []string
+	var filesystemResizesWatcher apiwatcher.StringsWatcher
+	var filesystemResizesChanges <-chan []string
 	var machineBlockDevicesWatcher apiwatcher.NotifyWatcher
 	var machineBlockDevicesChanges <-chan struct{}
 	machineChanges := make(chan names.MachineTag)
@@ -271,6 +312,9 @@ func (w *storageprovisioner) loop() error {
 	defer w.maybeStopWatcher(volumeAttachmentsWatcher)
 	defer w.maybeStopWatcher(filesystemsWatcher)
 	defer w.maybeStopWatcher(filesystemAttachmentsWatcher)
+	defer w.maybeStopWatcher(volumeSnapshotsWatcher)
+	defer w.maybeStopWatcher(volumeResizesWatcher)
+	defer w.maybeStopWatcher(filesystemResizesWatcher)
 
 	startWatchers := func() error {
 		var err error
@@ -290,10 +334,25 @@ func (w *storageprovisioner) loop() error {
 		if err != nil {
 			return errors.Annotate(err, "watching filesystem attachments")
 		}
+		volumeSnapshotsWatcher, err = w.volumes.WatchVolumeSnapshots()
+		if err != nil {
+			return errors.Annotate(err, "watching volume snapshots")
+		}
+		volumeResizesWatcher, err = w.volumes.WatchVolumeResizes()
+		if err != nil {
+			return errors.Annotate(err, "watching volume resizes")
+		}
+		filesystemResizesWatcher, err = w.filesystems.WatchFilesystemResizes()
+		if err != nil {
+			return errors.Annotate(err, "watching filesystem resizes")
+		}
 		volumesChanges = volumesWatcher.Changes()
 		filesystemsChanges = filesystemsWatcher.Changes()
 		volumeAttachmentsChanges = volumeAttachmentsWatcher.Changes()
 		filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
+		volumeSnapshotsChanges = volumeSnapshotsWatcher.Changes()
+		volumeResizesChanges = volumeResizesWatcher.Changes()
+		filesystemResizesChanges = filesystemResizesWatcher.Changes()
 		return nil
 	}
 
@@ -383,6 +442,27 @@ func (w *storageprovisioner) loop() error {
 			if err := filesystemAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case changes, ok := <-volumeSnapshotsChanges:
+			if !ok {
+				return watcher.EnsureErr(volumeSnapshotsWatcher)
+			}
+			if err := volumeSnapshotsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-volumeResizesChanges:
+			if !ok {
+				return watcher.EnsureErr(volumeResizesWatcher)
+			}
+			if err := volumeResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-filesystemResizesChanges:
+			if !ok {
+				return watcher.EnsureErr(filesystemResizesWatcher)
+			}
+			if err := filesystemResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
 		case _, ok := <-machineBlockDevicesChanges:
 			if !ok {
 				return watcher.EnsureErr(machineBlockDevicesWatcher)
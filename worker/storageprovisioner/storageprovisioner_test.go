@@ -1336,6 +1336,122 @@ func (s *storageProvisionerSuite) TestDestroyFilesystems(c *gc.C) {
 	assertNoEvent(c, removedChan, "filesystems removed")
 }
 
+func (s *storageProvisionerSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	volumeSnapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.createdSnapshots["3"] = params.VolumeSnapshot{Id: "3", SnapshotId: "snap-vol-3"}
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+		defer close(volumeSnapshotInfoSet)
+		c.Assert(snapshots, jc.SameContents, []params.VolumeSnapshot{
+			{Id: "1", SnapshotId: "snap-vol-1", Size: 1024},
+			{Id: "2", SnapshotId: "snap-vol-2", Size: 1024},
+		})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Snapshot "3" has already been created, so should be ignored.
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1", "2", "3"}
+	waitChannel(c, volumeSnapshotInfoSet, "waiting for volume snapshot info to be set")
+}
+
+func (s *storageProvisionerSuite) TestCreateVolumeSnapshotsError(c *gc.C) {
+	s.provider.createVolumeSnapshotsFunc = func(p []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+		return []storage.CreateVolumeSnapshotsResult{{Error: errors.New("out of cheese")}}, nil
+	}
+	volumeSnapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+		defer close(volumeSnapshotInfoSet)
+		c.Assert(snapshots, jc.DeepEquals, []params.VolumeSnapshot{
+			{Id: "1", Error: "out of cheese"},
+		})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1"}
+	waitChannel(c, volumeSnapshotInfoSet, "waiting for volume snapshot error to be set")
+}
+
+func (s *storageProvisionerSuite) TestResizeVolumes(c *gc.C) {
+	volumeResizesSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.pendingResizes["1"] = 2048
+	volumeAccessor.pendingResizes["2"] = 4096
+	volumeAccessor.setVolumeResizeResults = func(resizes []params.VolumeResize) ([]params.ErrorResult, error) {
+		defer close(volumeResizesSet)
+		c.Assert(resizes, jc.SameContents, []params.VolumeResize{
+			{VolumeTag: "volume-1", Size: 2048},
+			{VolumeTag: "volume-2", Size: 4096},
+		})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Volume "3" has no pending resize, so should be ignored.
+	volumeAccessor.resizesWatcher.changes <- []string{"1", "2", "3"}
+	waitChannel(c, volumeResizesSet, "waiting for volume resize results to be set")
+}
+
+func (s *storageProvisionerSuite) TestResizeVolumesError(c *gc.C) {
+	s.provider.resizeVolumesFunc = func(p []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+		return []storage.ResizeVolumesResult{{Error: errors.New("out of cheese")}}, nil
+	}
+	volumeResizesSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.pendingResizes["1"] = 2048
+	volumeAccessor.setVolumeResizeResults = func(resizes []params.VolumeResize) ([]params.ErrorResult, error) {
+		defer close(volumeResizesSet)
+		c.Assert(resizes, jc.DeepEquals, []params.VolumeResize{
+			{VolumeTag: "volume-1", Error: "out of cheese"},
+		})
+		return nil, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.resizesWatcher.changes <- []string{"1"}
+	waitChannel(c, volumeResizesSet, "waiting for volume resize error to be set")
+}
+
+func (s *storageProvisionerSuite) TestResizeFilesystemsNotSupported(c *gc.C) {
+	filesystemResizesSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.pendingResizes["1"] = 2048
+	filesystemAccessor.setFilesystemResizeResults = func(resizes []params.FilesystemResize) ([]params.ErrorResult, error) {
+		defer close(filesystemResizesSet)
+		c.Assert(resizes, jc.DeepEquals, []params.FilesystemResize{{
+			FilesystemTag: "filesystem-1",
+			Error:         `resizing filesystems with storage provider "dummy" not supported`,
+		}})
+		return nil, nil
+	}
+
+	args := &workerArgs{filesystems: filesystemAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.resizesWatcher.changes <- []string{"1"}
+	waitChannel(c, filesystemResizesSet, "waiting for filesystem resize error to be set")
+}
+
 func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 	if args == nil {
 		args = &workerArgs{}
@@ -466,12 +466,13 @@ func volumeParamsFromParams(in params.VolumeParams) (storage.VolumeParams, error
 		}
 	}
 	return storage.VolumeParams{
-		volumeTag,
-		in.Size,
-		providerType,
-		in.Attributes,
-		in.Tags,
-		attachment,
+		Tag:          volumeTag,
+		Size:         in.Size,
+		Provider:     providerType,
+		Attributes:   in.Attributes,
+		ResourceTags: in.Tags,
+		Attachment:   attachment,
+		SnapshotId:   in.SnapshotId,
 	}, nil
 }
 
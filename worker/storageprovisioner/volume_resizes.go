@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeResizesChanged is called when pending resizes of the volumes with
+// the provided IDs have been seen to have changed. Volumes with a pending
+// resize are resized by the relevant volume source.
+func volumeResizesChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tags := make([]names.VolumeTag, len(changes))
+	for i, change := range changes {
+		tags[i] = names.NewVolumeTag(change)
+	}
+	paramsResults, err := ctx.volumeAccessor.VolumeResizeParams(tags)
+	if err != nil {
+		return errors.Annotatef(err, "getting volume resize params for %v", changes)
+	}
+	var resizeParams []storage.VolumeResizeParams
+	for i, result := range paramsResults {
+		if result.Error != nil {
+			if params.IsCodeNotFound(result.Error) {
+				// The resize has already completed or failed;
+				// either way, there is nothing more to do.
+				continue
+			}
+			return errors.Annotatef(
+				result.Error, "getting resize params for %s",
+				names.ReadableString(tags[i]),
+			)
+		}
+		resizeParams = append(resizeParams, storage.VolumeResizeParams{
+			Volume:   tags[i],
+			VolumeId: result.Result.VolumeId,
+			Provider: storage.ProviderType(result.Result.Provider),
+			Size:     result.Result.Size,
+		})
+	}
+	if len(resizeParams) == 0 {
+		return nil
+	}
+	return resizeVolumes(ctx, resizeParams)
+}
+
+// resizeVolumes resizes the volumes with the specified parameters, and
+// records the results in state.
+func resizeVolumes(ctx *context, resizeParams []storage.VolumeResizeParams) error {
+	paramsBySource := make(map[string][]storage.VolumeResizeParams)
+	for _, p := range resizeParams {
+		sourceName := string(p.Provider)
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], p)
+	}
+	var resizes []params.VolumeResize
+	for sourceName, resizeParams := range paramsBySource {
+		var results []storage.ResizeVolumesResult
+		source, err := volumeSource(
+			ctx.environConfig, ctx.storageDir, sourceName, resizeParams[0].Provider,
+		)
+		if err != nil && errors.Cause(err) != errNonDynamic {
+			return errors.Annotate(err, "getting volume source")
+		}
+		if resizer, ok := source.(storage.VolumeResizer); ok {
+			logger.Debugf("resizing volumes: %v", resizeParams)
+			results, err = resizer.ResizeVolumes(resizeParams)
+			if err != nil {
+				return errors.Annotatef(err, "resizing volumes from source %q", sourceName)
+			}
+		} else {
+			err := errors.NotSupportedf("resizing volumes with storage provider %q", sourceName)
+			results = make([]storage.ResizeVolumesResult, len(resizeParams))
+			for i := range results {
+				results[i].Error = err
+			}
+		}
+		for i, result := range results {
+			tag := resizeParams[i].Volume
+			resize := params.VolumeResize{VolumeTag: tag.String()}
+			if result.Error != nil {
+				logger.Errorf("failed to resize %s: %v", names.ReadableString(tag), result.Error)
+				resize.Error = result.Error.Error()
+			} else {
+				resize.Size = result.VolumeInfo.Size
+				if volume, ok := ctx.volumes[tag]; ok {
+					volume.Size = resize.Size
+					ctx.volumes[tag] = volume
+				}
+			}
+			resizes = append(resizes, resize)
+		}
+	}
+	errorResults, err := ctx.volumeAccessor.SetVolumeResizeResults(resizes)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume resizes to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "publishing resize of %s to state",
+				resizes[i].VolumeTag,
+			)
+		}
+	}
+	return nil
+}
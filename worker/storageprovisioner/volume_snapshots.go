@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeSnapshotsChanged is called when the lifecycle states of the volume
+// snapshots with the provided IDs have been seen to have changed. Snapshots
+// that have not yet been created are created by the relevant volume source.
+func volumeSnapshotsChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	paramsResults, err := ctx.volumeAccessor.VolumeSnapshotParams(changes)
+	if err != nil {
+		return errors.Annotatef(err, "getting volume snapshot params for %v", changes)
+	}
+	var snapshotIds []string
+	var snapshotParams []storage.VolumeSnapshotParams
+	for i, result := range paramsResults {
+		if result.Error != nil {
+			if params.IsCodeAlreadyExists(result.Error) {
+				// The snapshot has already been created, or
+				// creating it has failed; either way, there
+				// is nothing more to do.
+				continue
+			}
+			return errors.Annotatef(
+				result.Error, "getting params for volume snapshot %q", changes[i],
+			)
+		}
+		volumeTag, err := names.ParseVolumeTag(result.Result.VolumeTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		snapshotIds = append(snapshotIds, result.Result.Id)
+		snapshotParams = append(snapshotParams, storage.VolumeSnapshotParams{
+			Volume:       volumeTag,
+			VolumeId:     result.Result.VolumeId,
+			Provider:     storage.ProviderType(result.Result.Provider),
+			ResourceTags: result.Result.Tags,
+		})
+	}
+	if len(snapshotParams) == 0 {
+		return nil
+	}
+	return createVolumeSnapshots(ctx, snapshotIds, snapshotParams)
+}
+
+// createVolumeSnapshots creates the volume snapshots with the specified
+// IDs and parameters, and records the results in state.
+func createVolumeSnapshots(
+	ctx *context,
+	snapshotIds []string,
+	snapshotParams []storage.VolumeSnapshotParams,
+) error {
+	idsBySource := make(map[string][]string)
+	paramsBySource := make(map[string][]storage.VolumeSnapshotParams)
+	for i, p := range snapshotParams {
+		sourceName := string(p.Provider)
+		idsBySource[sourceName] = append(idsBySource[sourceName], snapshotIds[i])
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], p)
+	}
+	var snapshots []params.VolumeSnapshot
+	for sourceName, snapshotParams := range paramsBySource {
+		var results []storage.CreateVolumeSnapshotsResult
+		source, err := volumeSource(
+			ctx.environConfig, ctx.storageDir, sourceName, snapshotParams[0].Provider,
+		)
+		if err != nil && errors.Cause(err) != errNonDynamic {
+			return errors.Annotate(err, "getting volume source")
+		}
+		if snapshotter, ok := source.(storage.VolumeSnapshotter); ok {
+			logger.Debugf("creating volume snapshots: %v", snapshotParams)
+			results, err = snapshotter.CreateVolumeSnapshots(snapshotParams)
+			if err != nil {
+				return errors.Annotatef(err, "creating volume snapshots from source %q", sourceName)
+			}
+		} else {
+			err := errors.NotSupportedf("volume snapshots with storage provider %q", sourceName)
+			results = make([]storage.CreateVolumeSnapshotsResult, len(snapshotParams))
+			for i := range results {
+				results[i].Error = err
+			}
+		}
+		for i, result := range results {
+			snapshot := params.VolumeSnapshot{Id: idsBySource[sourceName][i]}
+			if result.Error != nil {
+				logger.Errorf(
+					"failed to snapshot %s: %v",
+					names.ReadableString(snapshotParams[i].Volume),
+					result.Error,
+				)
+				snapshot.Error = result.Error.Error()
+			} else {
+				snapshot.SnapshotId = result.VolumeSnapshot.SnapshotId
+				snapshot.Size = result.VolumeSnapshot.Size
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	errorResults, err := ctx.volumeAccessor.SetVolumeSnapshotInfo(snapshots)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume snapshots to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "publishing volume snapshot %q to state",
+				snapshots[i].Id,
+			)
+		}
+	}
+	return nil
+}
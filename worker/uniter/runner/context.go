@@ -322,6 +322,10 @@ func (ctx *HookContext) PrivateAddress() (string, bool) {
 	return ctx.privateAddress, ctx.privateAddress != ""
 }
 
+func (ctx *HookContext) EndpointAddress(endpoint string) (string, error) {
+	return ctx.unit.EndpointAddress(endpoint)
+}
+
 func (ctx *HookContext) AvailabilityZone() (string, bool) {
 	return ctx.availabilityzone, ctx.availabilityzone != ""
 }
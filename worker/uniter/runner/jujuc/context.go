@@ -139,6 +139,11 @@ type ContextNetworking interface {
 	// PrivateAddress returns the executing unit's private address.
 	PrivateAddress() (string, bool)
 
+	// EndpointAddress returns the address the executing unit should
+	// advertise over the named relation endpoint; if the endpoint is
+	// bound to a space, the address will be in that space.
+	EndpointAddress(endpoint string) (string, error)
+
 	// OpenPorts marks the supplied port range for opening when the
 	// executing unit's service is exposed.
 	OpenPorts(protocol string, fromPort, toPort int) error
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// NetworkGetCommand implements the network-get command.
+type NetworkGetCommand struct {
+	cmd.CommandBase
+	ctx            Context
+	Endpoint       string
+	primaryAddress bool
+	out            cmd.Output
+}
+
+// NewNetworkGetCommand returns a new NetworkGetCommand with the given context.
+func NewNetworkGetCommand(ctx Context) cmd.Command {
+	return &NetworkGetCommand{ctx: ctx}
+}
+
+// Info is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Info() *cmd.Info {
+	doc := `
+network-get prints network information for the named relation endpoint of
+the local unit. With --primary-address, it prints the address other units
+should use to reach the local unit over that endpoint. If the endpoint is
+bound to a space, the address will be in that space; otherwise, it is the
+unit's private address.
+`
+	return &cmd.Info{
+		Name:    "network-get",
+		Args:    "<endpoint> --primary-address",
+		Purpose: "get network config",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *NetworkGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.primaryAddress, "primary-address", false, "get the primary address for the endpoint")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no endpoint specified")
+	}
+	c.Endpoint = args[0]
+	if c.Endpoint == "" {
+		return errors.New("endpoint name cannot be empty")
+	}
+	if !c.primaryAddress {
+		return errors.New("--primary-address is currently required")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
+	address, err := c.ctx.EndpointAddress(c.Endpoint)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get address for endpoint %q", c.Endpoint)
+	}
+	return c.out.Write(ctx, address)
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type NetworkGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&NetworkGetSuite{})
+
+func (s *NetworkGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("network-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+var networkGetInitTests = []struct {
+	args []string
+	err  string
+}{
+	{nil, "no endpoint specified"},
+	{[]string{""}, "endpoint name cannot be empty"},
+	{[]string{"db"}, "--primary-address is currently required"},
+	{[]string{"db", "--primary-address", "extra"}, `unrecognized args: \["extra"\]`},
+}
+
+func (s *NetworkGetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range networkGetInitTests {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		err := testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+var networkGetTests = []struct {
+	args []string
+	out  string
+}{
+	{[]string{"db", "--primary-address"}, "10.0.0.99\n"},
+	{[]string{"db", "--primary-address", "--format", "yaml"}, "10.0.0.99\n"},
+	{[]string{"db", "--primary-address", "--format", "json"}, `"10.0.0.99"` + "\n"},
+}
+
+func (s *NetworkGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range networkGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *NetworkGetSuite) TestUnknownEndpoint(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"website", "--primary-address"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, `error: cannot get address for endpoint "website": address for endpoint "website" not found`+"\n")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+}
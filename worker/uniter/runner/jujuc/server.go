@@ -37,6 +37,7 @@ var baseCommands = map[string]creator{
 	"close-port" + cmdSuffix:    NewClosePortCommand,
 	"config-get" + cmdSuffix:    NewConfigGetCommand,
 	"juju-log" + cmdSuffix:      NewJujuLogCommand,
+	"network-get" + cmdSuffix:   NewNetworkGetCommand,
 	"open-port" + cmdSuffix:     NewOpenPortCommand,
 	"opened-ports" + cmdSuffix:  NewOpenedPortsCommand,
 	"relation-get" + cmdSuffix:  NewRelationGetCommand,
@@ -13,9 +13,10 @@ import (
 
 // NetworkInterface holds the values for the hook context.
 type NetworkInterface struct {
-	PublicAddress  string
-	PrivateAddress string
-	Ports          []network.PortRange
+	PublicAddress     string
+	PrivateAddress    string
+	EndpointAddresses map[string]string
+	Ports             []network.PortRange
 }
 
 // CheckPorts checks the current ports.
@@ -77,6 +78,19 @@ func (c *ContextNetworking) PrivateAddress() (string, bool) {
 	return c.info.PrivateAddress, true
 }
 
+// EndpointAddress implements jujuc.ContextNetworking.
+func (c *ContextNetworking) EndpointAddress(endpoint string) (string, error) {
+	c.stub.AddCall("EndpointAddress", endpoint)
+	if err := c.stub.NextErr(); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	if address, ok := c.info.EndpointAddresses[endpoint]; ok {
+		return address, nil
+	}
+	return "", errors.NotFoundf("address for endpoint %q", endpoint)
+}
+
 // OpenPorts implements jujuc.ContextNetworking.
 func (c *ContextNetworking) OpenPorts(protocol string, from, to int) error {
 	c.stub.AddCall("OpenPorts", protocol, from, to)
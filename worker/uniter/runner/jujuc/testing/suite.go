@@ -34,6 +34,9 @@ func (s *ContextSuite) NewInfo() *ContextInfo {
 	info.AvailabilityZone = "us-east-1a"
 	info.PublicAddress = "gimli.minecraft.testing.invalid"
 	info.PrivateAddress = "192.168.0.99"
+	info.EndpointAddresses = map[string]string{
+		"db": "10.0.0.99",
+	}
 	return &info
 }
 
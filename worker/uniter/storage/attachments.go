@@ -364,7 +364,7 @@ func (a *Attachments) removeStorageAttachment(tag names.StorageTag, s *storager)
 }
 
 func (a *Attachments) storagerForHook(hi hook.Info) (*storager, error) {
-	if !hi.Kind.IsStorage() {
+	if !hook.IsStorage(hi.Kind) {
 		return nil, errors.Errorf("not a storage hook: %#v", hi)
 	}
 	storager, ok := a.storagers[names.NewStorageTag(hi.StorageId)]
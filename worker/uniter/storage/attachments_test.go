@@ -292,6 +292,74 @@ func (s *attachmentsSuite) TestAttachmentsCommitHook(c *gc.C) {
 	c.Assert(removed, jc.IsTrue)
 }
 
+func (s *attachmentsSuite) TestAttachmentsReattach(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	storageTag := names.NewStorageTag("data/0")
+	attachment := params.StorageAttachment{
+		StorageTag: storageTag.String(),
+		UnitTag:    unitTag.String(),
+		Life:       params.Alive,
+		Kind:       params.StorageKindBlock,
+		Location:   "/dev/sdb",
+	}
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			c.Assert(u, gc.Equals, unitTag)
+			return nil, nil
+		},
+		watchStorageAttachment: func(s names.StorageTag, u names.UnitTag) (watcher.NotifyWatcher, error) {
+			w := newMockNotifyWatcher()
+			w.changes <- struct{}{}
+			return w, nil
+		},
+		storageAttachment: func(s names.StorageTag, u names.UnitTag) (params.StorageAttachment, error) {
+			c.Assert(s, gc.Equals, storageTag)
+			return attachment, nil
+		},
+		remove: func(s names.StorageTag, u names.UnitTag) error {
+			c.Assert(s, gc.Equals, storageTag)
+			return nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		err := att.Stop()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	attachedHook := hook.Info{
+		Kind:      hooks.StorageAttached,
+		StorageId: storageTag.Id(),
+	}
+	err = att.UpdateStorage([]names.StorageTag{storageTag})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(waitOneHook(c, att.Hooks()), gc.Equals, attachedHook)
+	err = att.CommitHook(attachedHook)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Detaching the storage removes the storage attachment
+	// and its state, once the storage-detaching hook has run.
+	err = att.CommitHook(hook.Info{
+		Kind:      hooks.StorageDetaching,
+		StorageId: storageTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	assertStorageTags(c, att)
+	c.Assert(filepath.Join(stateDir, "data-0"), jc.DoesNotExist)
+
+	// Attaching the storage to the unit again must run
+	// the storage-attached hook again.
+	err = att.UpdateStorage([]names.StorageTag{storageTag})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Pending(), gc.Equals, 1)
+	c.Assert(waitOneHook(c, att.Hooks()), gc.Equals, attachedHook)
+}
+
 func (s *attachmentsSuite) TestAttachmentsSetDying(c *gc.C) {
 	stateDir := c.MkDir()
 	unitTag := names.NewUnitTag("mysql/0")
@@ -28,8 +28,12 @@ func StateAttached(s State) bool {
 	return s.(*stateFile).attached
 }
 
+func StateSize(s State) uint64 {
+	return s.(*stateFile).size
+}
+
 func ValidateHook(tag names.StorageTag, attached bool, hi hook.Info) error {
-	st := &state{tag, attached}
+	st := &state{storage: tag, attached: attached}
 	return st.ValidateHook(hi)
 }
 
@@ -54,11 +58,13 @@ func NewStorageHookQueue(
 	unitTag names.UnitTag,
 	storageTag names.StorageTag,
 	attached bool,
+	size uint64,
 ) StorageHookQueue {
 	return &storageHookQueue{
 		unitTag:    unitTag,
 		storageTag: storageTag,
 		attached:   attached,
+		size:       size,
 	}
 }
 
@@ -67,7 +73,8 @@ func NewStorageSource(
 	unitTag names.UnitTag,
 	storageTag names.StorageTag,
 	attached bool,
+	size uint64,
 ) (hook.Source, error) {
-	source, err := newStorageSource(st, unitTag, storageTag, attached)
+	source, err := newStorageSource(st, unitTag, storageTag, attached, size)
 	return source, err
 }
@@ -38,6 +38,13 @@ type storageHookQueue struct {
 	// hook has been executed.
 	attached bool
 
+	// size records the size of the storage, in MiB, that the charm
+	// has been or is about to be told about, so that growth can be
+	// reported to it. It starts out as the size persisted in the
+	// storage state file, so growth while the agent was down is not
+	// missed.
+	size uint64
+
 	// hookInfo is the next hook.Info to return, if non-nil.
 	hookInfo *hook.Info
 
@@ -52,6 +59,7 @@ func newStorageSource(
 	unitTag names.UnitTag,
 	storageTag names.StorageTag,
 	attached bool,
+	size uint64,
 ) (*storageSource, error) {
 	w, err := st.WatchStorageAttachment(storageTag, unitTag)
 	if err != nil {
@@ -62,6 +70,7 @@ func newStorageSource(
 			unitTag:    unitTag,
 			storageTag: storageTag,
 			attached:   attached,
+			size:       size,
 		},
 		st:      st,
 		watcher: w,
@@ -158,8 +167,14 @@ func (s *storageHookQueue) Pop() {
 	if s.Empty() {
 		panic("source is empty")
 	}
-	if s.hookInfo.Kind == hooks.StorageAttached {
+	switch s.hookInfo.Kind {
+	case hooks.StorageAttached:
 		s.attached = true
+		fallthrough
+	case hook.StorageResized:
+		if s.hookInfo.StorageSize != 0 {
+			s.size = s.hookInfo.StorageSize
+		}
 	}
 	s.hookInfo = nil
 }
@@ -170,10 +185,11 @@ func (s *storageHookQueue) Update(attachment params.StorageAttachment) error {
 	switch attachment.Life {
 	case params.Alive:
 		if s.attached {
-			// Storage attachments currently do not change
-			// (apart from lifecycle) after being provisioned.
-			// We don't process unprovisioned storage here,
-			// so there's nothing to do.
+			// Once provisioned, storage attachments only change
+			// (apart from lifecycle) when the storage is resized.
+			// We don't process unprovisioned storage here.
+			s.ensureContext(attachment)
+			s.updateSize(attachment.Size)
 			return nil
 		}
 	case params.Dying:
@@ -194,6 +210,27 @@ func (s *storageHookQueue) Update(attachment params.StorageAttachment) error {
 	// for this storager. Later, when we need to handle changing
 	// storage, we'll need to have a cache in the runner like
 	// we have for relations.
+	s.ensureContext(attachment)
+
+	if s.hookInfo == nil {
+		s.hookInfo = &hook.Info{
+			StorageId: s.storageTag.Id(),
+		}
+	}
+	if attachment.Life == params.Alive {
+		s.hookInfo.Kind = hooks.StorageAttached
+		s.hookInfo.StorageSize = attachment.Size
+	} else {
+		s.hookInfo.Kind = hooks.StorageDetaching
+		s.hookInfo.StorageSize = 0
+	}
+	logger.Debugf("queued hook: %v", s.hookInfo)
+	return nil
+}
+
+// ensureContext sets the storage context from the attachment, if it
+// has not already been set.
+func (s *storageHookQueue) ensureContext(attachment params.StorageAttachment) {
 	if s.context == nil {
 		s.context = &contextStorage{
 			tag:      s.storageTag,
@@ -201,19 +238,30 @@ func (s *storageHookQueue) Update(attachment params.StorageAttachment) error {
 			location: attachment.Location,
 		}
 	}
+}
 
+// updateSize queues a storage-resized hook if an attached storage
+// attachment has grown beyond the size the charm was last told about.
+// If no size has been recorded, as for state files written before
+// sizes were, the size observed only establishes the baseline.
+func (s *storageHookQueue) updateSize(size uint64) {
+	if s.size == 0 {
+		s.size = size
+		return
+	}
+	if size <= s.size {
+		return
+	}
 	if s.hookInfo == nil {
 		s.hookInfo = &hook.Info{
+			Kind:      hook.StorageResized,
 			StorageId: s.storageTag.Id(),
 		}
+		logger.Debugf("queued hook: %v", s.hookInfo)
 	}
-	if attachment.Life == params.Alive {
-		s.hookInfo.Kind = hooks.StorageAttached
-	} else {
-		s.hookInfo.Kind = hooks.StorageDetaching
+	if s.hookInfo.Kind == hook.StorageResized {
+		s.hookInfo.StorageSize = size
 	}
-	logger.Debugf("queued hook: %v", s.hookInfo)
-	return nil
 }
 
 // Context returns the ContextStorage for the storage that this hook queue
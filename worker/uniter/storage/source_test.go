@@ -29,10 +29,15 @@ type storageHookQueueSuite struct {
 var _ = gc.Suite(&storageHookQueueSuite{})
 
 func newHookQueue(attached bool) storage.StorageHookQueue {
+	return newHookQueueWithSize(attached, 0)
+}
+
+func newHookQueueWithSize(attached bool, size uint64) storage.StorageHookQueue {
 	return storage.NewStorageHookQueue(
 		names.NewUnitTag("mysql/0"),
 		names.NewStorageTag("data/0"),
 		attached,
+		size,
 	)
 }
 
@@ -113,6 +118,77 @@ func (s *storageHookQueueSuite) TestStorageHookQueueDead(c *gc.C) {
 	c.Assert(q.Empty(), jc.IsTrue)
 }
 
+func updateHookQueueSize(c *gc.C, q storage.StorageHookQueue, size uint64) {
+	err := q.Update(params.StorageAttachment{
+		Life:     params.Alive,
+		Kind:     params.StorageKindBlock,
+		Location: "/dev/sdb",
+		Size:     size,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageHookQueueSuite) TestStorageHookQueueResized(c *gc.C) {
+	q := newHookQueue(initiallyUnattached)
+	updateHookQueueSize(c, q, 1024)
+	c.Assert(q.Next(), gc.Equals, hook.Info{
+		Kind:        hooks.StorageAttached,
+		StorageId:   "data/0",
+		StorageSize: 1024,
+	})
+	q.Pop()
+	updateHookQueueSize(c, q, 1024)
+	c.Assert(q.Empty(), jc.IsTrue)
+
+	// Growth seen before the hook is consumed updates the size the
+	// hook reports.
+	updateHookQueueSize(c, q, 2048)
+	updateHookQueueSize(c, q, 3072)
+	c.Assert(q.Empty(), jc.IsFalse)
+	c.Assert(q.Next(), gc.Equals, hook.Info{
+		Kind:        hook.StorageResized,
+		StorageId:   "data/0",
+		StorageSize: 3072,
+	})
+	q.Pop()
+	updateHookQueueSize(c, q, 3072)
+	c.Assert(q.Empty(), jc.IsTrue)
+
+	// The storage-resized hook does not affect the attached
+	// state, so detaching still queues storage-detaching.
+	updateHookQueue(c, q, params.Dying)
+	c.Assert(q.Next(), gc.Equals, hook.Info{
+		Kind:      hooks.StorageDetaching,
+		StorageId: "data/0",
+	})
+}
+
+func (s *storageHookQueueSuite) TestStorageHookQueueResizedWhileDown(c *gc.C) {
+	// The storage grew while the agent was down, after the charm
+	// was told it was 1024MiB.
+	q := newHookQueueWithSize(initiallyAttached, 1024)
+	updateHookQueueSize(c, q, 2048)
+	c.Assert(q.Next(), gc.Equals, hook.Info{
+		Kind:        hook.StorageResized,
+		StorageId:   "data/0",
+		StorageSize: 2048,
+	})
+}
+
+func (s *storageHookQueueSuite) TestStorageHookQueueNoRecordedSize(c *gc.C) {
+	// State files written before sizes were recorded have no size,
+	// so the first size seen only establishes the baseline.
+	q := newHookQueue(initiallyAttached)
+	updateHookQueueSize(c, q, 1024)
+	c.Assert(q.Empty(), jc.IsTrue)
+	updateHookQueueSize(c, q, 2048)
+	c.Assert(q.Next(), gc.Equals, hook.Info{
+		Kind:        hook.StorageResized,
+		StorageId:   "data/0",
+		StorageSize: 2048,
+	})
+}
+
 func (s *storageHookQueueSuite) TestStorageHookQueueContext(c *gc.C) {
 	q := newHookQueue(initiallyUnattached)
 	_, ok := q.Context()
@@ -153,7 +229,7 @@ func (s *storageHookQueueSuite) TestStorageSourceStop(c *gc.C) {
 	}
 
 	const initiallyUnattached = false
-	source, err := storage.NewStorageSource(st, unitTag, storageTag, initiallyUnattached)
+	source, err := storage.NewStorageSource(st, unitTag, storageTag, initiallyUnattached, 0)
 	c.Assert(err, jc.ErrorIsNil)
 	err = source.Stop()
 	c.Assert(err, jc.ErrorIsNil)
@@ -189,7 +265,7 @@ func (s *storageHookQueueSuite) TestStorageSourceUpdateErrors(c *gc.C) {
 	}
 
 	const initiallyUnattached = false
-	source, err := storage.NewStorageSource(st, unitTag, storageTag, initiallyUnattached)
+	source, err := storage.NewStorageSource(st, unitTag, storageTag, initiallyUnattached, 0)
 	c.Assert(err, jc.ErrorIsNil)
 
 	assertNoSourceChange := func() {
@@ -25,6 +25,11 @@ type state struct {
 	// attached records the uniter's knowledge of the
 	// storage attachment state.
 	attached bool
+
+	// size records the size of the storage, in MiB, that the charm
+	// was last told about by a committed hook. It is zero if no
+	// size has been recorded.
+	size uint64
 }
 
 // ValidateHook returns an error if the supplied hook.Info does not represent
@@ -41,7 +46,7 @@ func (s *state) ValidateHook(hi hook.Info) (err error) {
 		if s.attached {
 			return errors.New("storage already attached")
 		}
-	case hooks.StorageDetaching:
+	case hooks.StorageDetaching, hook.StorageResized:
 		if !s.attached {
 			return errors.New("storage not attached")
 		}
@@ -85,6 +90,7 @@ func readStateFile(dirPath string, tag names.StorageTag) (d *stateFile, err erro
 		return nil, errors.Errorf("invalid storage state file %q: missing 'attached'", d.path)
 	}
 	d.state.attached = *info.Attached
+	d.state.size = info.Size
 	return d, nil
 }
 
@@ -130,12 +136,17 @@ func (d *stateFile) CommitHook(hi hook.Info) (err error) {
 		return d.Remove()
 	}
 	attached := true
-	di := diskInfo{&attached}
+	size := d.state.size
+	if hi.StorageSize != 0 {
+		size = hi.StorageSize
+	}
+	di := diskInfo{Attached: &attached, Size: size}
 	if err := utils.WriteYaml(d.path, &di); err != nil {
 		return err
 	}
 	// If write was successful, update own state.
 	d.state.attached = true
+	d.state.size = size
 	return nil
 }
 
@@ -146,10 +157,12 @@ func (d *stateFile) Remove() error {
 	}
 	// If atomic delete succeeded, update own state.
 	d.state.attached = false
+	d.state.size = 0
 	return nil
 }
 
 // diskInfo defines the storage attachment data serialization.
 type diskInfo struct {
-	Attached *bool `yaml:"attached,omitempty"`
+	Attached *bool  `yaml:"attached,omitempty"`
+	Size     uint64 `yaml:"size,omitempty"`
 }
@@ -169,6 +169,37 @@ func (s *stateSuite) TestCommitHook(c *gc.C) {
 	}
 }
 
+func (s *stateSuite) TestCommitHookRecordsSize(c *gc.C) {
+	dir := c.MkDir()
+	tag := names.NewStorageTag("data/0")
+	state, err := storage.ReadStateFile(dir, tag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = state.CommitHook(hook.Info{
+		Kind:        hooks.StorageAttached,
+		StorageId:   "data/0",
+		StorageSize: 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storage.StateSize(state), gc.Equals, uint64(1024))
+
+	err = state.CommitHook(hook.Info{
+		Kind:        hook.StorageResized,
+		StorageId:   "data/0",
+		StorageSize: 2048,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "data-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "attached: true\nsize: 2048\n")
+
+	// The size survives a restart.
+	state, err = storage.ReadStateFile(dir, tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storage.StateAttached(state), jc.IsTrue)
+	c.Assert(storage.StateSize(state), gc.Equals, uint64(2048))
+}
+
 func (s *stateSuite) TestValidateHook(c *gc.C) {
 	const unattached = false
 	const attached = true
@@ -198,4 +229,6 @@ func (s *stateSuite) TestValidateHook(c *gc.C) {
 	assertValidates(true, hooks.StorageDetaching)
 	assertValidateFails(false, hooks.StorageDetaching, `inappropriate "storage-detaching" hook for storage "data/0": storage not attached`)
 	assertValidateFails(true, hooks.StorageAttached, `inappropriate "storage-attached" hook for storage "data/0": storage already attached`)
+	assertValidates(true, hook.StorageResized)
+	assertValidateFails(false, hook.StorageResized, `inappropriate "storage-resized" hook for storage "data/0": storage not attached`)
 }
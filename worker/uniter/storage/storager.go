@@ -30,7 +30,7 @@ func newStorager(
 	state *stateFile,
 	hooks chan<- hook.Info,
 ) (*storager, error) {
-	source, err := newStorageSource(st, unitTag, storageTag, state.attached)
+	source, err := newStorageSource(st, unitTag, storageTag, state.attached, state.size)
 	if err != nil {
 		return nil, errors.Annotate(err, "creating storage event source")
 	}